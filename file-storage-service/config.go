@@ -22,6 +22,7 @@ type Config struct {
 
 	Idempotency struct {
 		DataExp time.Duration `mapstructure:"data_exp"`
+		LockExp time.Duration `mapstructure:"lock_exp"`
 	} `mapstructure:"idempotency"`
 
 	Upload struct {
@@ -33,6 +34,10 @@ type Config struct {
 		MaxPartSize int64 `mapstructure:"max_part_size"`
 	} `mapstructure:"multipart_upload"`
 
+	RateLimit struct {
+		Upload RateLimitConfig `mapstructure:"upload"`
+	} `mapstructure:"rate_limit"`
+
 	S3 struct {
 		Bucket    string `mapstructure:"bucket"`
 		UrlPrefix string `mapstructure:"url_prefix"`
@@ -62,6 +67,11 @@ type JWTConfig struct {
 	KeyFilePath   string        `mapstructure:"key_file_path"`
 }
 
+type RateLimitConfig struct {
+	Requests int           `mapstructure:"requests"`
+	Window   time.Duration `mapstructure:"window"`
+}
+
 func loadConfig(file string) *Config {
 	viper.AutomaticEnv()
 
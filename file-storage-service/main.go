@@ -20,6 +20,7 @@ import (
 	"github.com/chakchat/chakchat-backend/shared/go/auth"
 	"github.com/chakchat/chakchat-backend/shared/go/idempotency"
 	"github.com/chakchat/chakchat-backend/shared/go/jwt"
+	"github.com/chakchat/chakchat-backend/shared/go/ratelimit"
 	"github.com/gin-gonic/gin"
 	"github.com/redis/go-redis/extra/redisotel/v9"
 	"github.com/redis/go-redis/v9"
@@ -80,6 +81,7 @@ func main() {
 
 	s3Client := connectS3()
 	idempStorage := createIdempStorage(rdb)
+	idempLocker := createIdempLocker(rdb)
 	fileMetaStorage := storage.NewFileMetaStorage(db)
 	uploadMetaStorage := storage.NewUploadMetaStorage(db)
 
@@ -118,11 +120,20 @@ func main() {
 		DefaultHeader: "X-Internal-Token",
 	})
 
+	uploadLimit := ratelimit.New(ratelimit.NewRedisLimiter(rdb), &ratelimit.Config{
+		Name: "upload",
+		Limit: ratelimit.Limit{
+			Requests: conf.RateLimit.Upload.Requests,
+			Window:   conf.RateLimit.Upload.Window,
+		},
+		Key: ratelimit.ByUser(),
+	})
+
 	r.Group("/").
-		Use(idempotency.New(idempStorage)).
 		Use(authMiddleware).
-		POST("/v1.0/upload", handlers.Upload(uploadConfig, uploadService)).
-		POST("/v1.0/upload/multipart/init", handlers.UploadInit(multipartConfig, uploadInitService)).
+		Use(idempotency.New(idempStorage, idempLocker)).
+		POST("/v1.0/upload", uploadLimit, handlers.Upload(uploadConfig, uploadService)).
+		POST("/v1.0/upload/multipart/init", uploadLimit, handlers.UploadInit(multipartConfig, uploadInitService)).
 		POST("/v1.0/upload/multipart/complete", handlers.UploadComplete(uploadCompleteService))
 
 	r.Group("/").
@@ -197,6 +208,12 @@ func createIdempStorage(redisClient *redis.Client) idempotency.IdempotencyStorag
 	})
 }
 
+func createIdempLocker(redisClient *redis.Client) idempotency.Locker {
+	return idempotency.NewLocker(redisClient, &idempotency.LockerConfig{
+		LockExp: conf.Idempotency.LockExp,
+	})
+}
+
 func readKey(path string) []byte {
 	key, err := os.ReadFile(path)
 	if err != nil {
@@ -38,24 +38,97 @@ type Config struct {
 		Lifetime time.Duration `mapstructure:"lifetime"`
 	} `mapstructure:"signup_meta"`
 
+	QRLogin struct {
+		Lifetime time.Duration `mapstructure:"lifetime"`
+	} `mapstructure:"qr_login"`
+
+	PhoneChange struct {
+		Lifetime time.Duration `mapstructure:"lifetime"`
+	} `mapstructure:"phone_change"`
+
+	LoginHistory struct {
+		MaxEvents int           `mapstructure:"max_events"`
+		Lifetime  time.Duration `mapstructure:"lifetime"`
+	} `mapstructure:"login_history"`
+
+	Suspension struct {
+		CacheLifetime time.Duration `mapstructure:"cache_lifetime"`
+	} `mapstructure:"suspension"`
+
+	// WebAuthn relying party. Origins must match the ones clients run on.
+	Passkey struct {
+		RPID                    string        `mapstructure:"rp_id"`
+		RPName                  string        `mapstructure:"rp_name"`
+		Origins                 []string      `mapstructure:"origins"`
+		RequireUserVerification bool          `mapstructure:"require_user_verification"`
+		Timeout                 time.Duration `mapstructure:"timeout"`
+	} `mapstructure:"passkey"`
+
 	Idempotency struct {
 		DataExp time.Duration `mapstructure:"data_exp"`
+		LockExp time.Duration `mapstructure:"lock_exp"`
 	} `mapstructure:"idempotency"`
 
 	PhoneCode struct {
 		SendFrequency time.Duration `mapstructure:"send_frequency"`
 	} `mapstructure:"phone_code"`
 
+	RateLimit struct {
+		SendCodeByPhone RateLimitConfig `mapstructure:"send_code_by_phone"`
+		SendCodeByIP    RateLimitConfig `mapstructure:"send_code_by_ip"`
+		// Cloud password and recovery code attempts per sign in key
+		PasswordAttempts RateLimitConfig `mapstructure:"password_attempts"`
+		// Phone change code attempts per user
+		PhoneChangeAttempts RateLimitConfig `mapstructure:"phone_change_attempts"`
+		// Current cloud password and recovery email code attempts per user
+		CloudPasswordAttempts RateLimitConfig `mapstructure:"cloud_password_attempts"`
+		// QR login tokens created per IP
+		QRTokenByIP RateLimitConfig `mapstructure:"qr_token_by_ip"`
+	} `mapstructure:"rate_limit"`
+
 	Sms struct {
-		Type   string `mapstructure:"type"`
+		// Providers are tried in the given order until one of them sends the code.
+		// Possible values: sms_aero, sms_ru, stub
+		Providers []string `mapstructure:"providers"`
+		// SMS Aero credentials
 		Email  string `mapstructure:"email"`
 		ApiKey string `mapstructure:"api_key"`
 
+		SmsRu struct {
+			ApiId string `mapstructure:"api_id"`
+		} `mapstructure:"sms_ru"`
+
 		Stub struct {
 			Addr string `mapstructure:"addr"`
 		} `mapstructure:"stub"`
 	} `mapstructure:"sms"`
 
+	// Used for cloud password recovery
+	Email struct {
+		// Possible values: smtp, stub
+		Type string `mapstructure:"type"`
+
+		Smtp struct {
+			Addr     string `mapstructure:"addr"`
+			Username string `mapstructure:"username"`
+			Password string `mapstructure:"password"`
+			From     string `mapstructure:"from"`
+		} `mapstructure:"smtp"`
+
+		Stub struct {
+			Addr string `mapstructure:"addr"`
+		} `mapstructure:"stub"`
+	} `mapstructure:"email"`
+
+	// Notifies users about logins, phone changes, etc.
+	LiveConnection struct {
+		Enabled bool     `mapstructure:"enabled"`
+		Brokers []string `mapstructure:"brokers"`
+		Topic   string   `mapstructure:"topic"`
+		// Allows users to request sign in codes on the devices they are signed in on instead of SMS
+		SendCodes bool `mapstructure:"send_codes"`
+	} `mapstructure:"live_connection"`
+
 	Otlp struct {
 		GrpcAddr string `mapstructure:"grpc_addr"`
 	} `mapstructure:"otlp"`
@@ -69,12 +142,20 @@ type JWTConfig struct {
 	KeyFilePath   string        `mapstructure:"key_file_path"`
 }
 
+type RateLimitConfig struct {
+	Requests int           `mapstructure:"requests"`
+	Window   time.Duration `mapstructure:"window"`
+}
+
 func loadConfig(file string) *Config {
 	// viper.SetConfigFile("/app/config.yml")
 
 	viper.AutomaticEnv()
 	viper.BindEnv("sms.email", "SMSAERO_EMAIL")
 	viper.BindEnv("sms.api_key", "SMSAERO_APIKEY")
+	viper.BindEnv("sms.sms_ru.api_id", "SMSRU_API_ID")
+	viper.BindEnv("email.smtp.username", "SMTP_USERNAME")
+	viper.BindEnv("email.smtp.password", "SMTP_PASSWORD")
 
 	viper.SetConfigFile(file)
 	if err := viper.ReadInConfig(); err != nil {
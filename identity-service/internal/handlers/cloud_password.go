@@ -0,0 +1,187 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"net/mail"
+
+	"github.com/chakchat/chakchat-backend/identity-service/internal/restapi"
+	"github.com/chakchat/chakchat-backend/identity-service/internal/services"
+	"github.com/chakchat/chakchat-backend/shared/go/jwt"
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	minPasswordLength = 8
+	maxPasswordLength = 128
+)
+
+type CloudPasswordService interface {
+	SetPassword(ctx context.Context, access jwt.Token, data *services.SetPasswordData) error
+	RemovePassword(ctx context.Context, access jwt.Token, currentPassword string) error
+	VerifyRecoveryEmail(ctx context.Context, access jwt.Token, code string) error
+}
+
+func SetCloudPassword(service CloudPasswordService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		access, ok := extractAccessToken(c)
+		if !ok {
+			return
+		}
+
+		var req setCloudPasswordRequest
+		if err := c.ShouldBindBodyWithJSON(&req); err != nil {
+			restapi.SendUnprocessableJSON(c)
+			return
+		}
+
+		if errors := validateSetCloudPassword(&req); len(errors) != 0 {
+			restapi.SendValidationError(c, errors)
+			return
+		}
+
+		err := service.SetPassword(c.Request.Context(), access, &services.SetPasswordData{
+			CurrentPassword: req.CurrentPassword,
+			NewPassword:     req.NewPassword,
+			RecoveryEmail:   req.RecoveryEmail,
+		})
+		if err != nil {
+			if sendAccessTokenError(c, err) {
+				return
+			}
+			switch err {
+			case services.ErrWrongPassword:
+				c.JSON(http.StatusBadRequest, restapi.ErrorResponse{
+					ErrorType:    restapi.ErrTypeWrongPassword,
+					ErrorMessage: "Wrong current cloud password",
+				})
+			default:
+				c.Error(err)
+				restapi.SendInternalError(c)
+			}
+			return
+		}
+
+		restapi.SendSuccess(c, cloudPasswordResponse{})
+	}
+}
+
+func RemoveCloudPassword(service CloudPasswordService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		access, ok := extractAccessToken(c)
+		if !ok {
+			return
+		}
+
+		var req removeCloudPasswordRequest
+		if err := c.ShouldBindBodyWithJSON(&req); err != nil {
+			restapi.SendUnprocessableJSON(c)
+			return
+		}
+
+		err := service.RemovePassword(c.Request.Context(), access, req.CurrentPassword)
+		if err != nil {
+			if sendAccessTokenError(c, err) {
+				return
+			}
+			switch err {
+			case services.ErrPasswordNotSet:
+				c.JSON(http.StatusBadRequest, restapi.ErrorResponse{
+					ErrorType:    restapi.ErrTypePasswordNotSet,
+					ErrorMessage: "Cloud password is not set",
+				})
+			case services.ErrWrongPassword:
+				c.JSON(http.StatusBadRequest, restapi.ErrorResponse{
+					ErrorType:    restapi.ErrTypeWrongPassword,
+					ErrorMessage: "Wrong current cloud password",
+				})
+			default:
+				c.Error(err)
+				restapi.SendInternalError(c)
+			}
+			return
+		}
+
+		restapi.SendSuccess(c, cloudPasswordResponse{})
+	}
+}
+
+func VerifyRecoveryEmail(service CloudPasswordService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		access, ok := extractAccessToken(c)
+		if !ok {
+			return
+		}
+
+		var req verifyRecoveryEmailRequest
+		if err := c.ShouldBindBodyWithJSON(&req); err != nil {
+			restapi.SendUnprocessableJSON(c)
+			return
+		}
+
+		err := service.VerifyRecoveryEmail(c.Request.Context(), access, req.Code)
+		if err != nil {
+			if sendAccessTokenError(c, err) {
+				return
+			}
+			switch err {
+			case services.ErrPasswordNotSet:
+				c.JSON(http.StatusBadRequest, restapi.ErrorResponse{
+					ErrorType:    restapi.ErrTypePasswordNotSet,
+					ErrorMessage: "Cloud password is not set",
+				})
+			case services.ErrEmailCodeNotRequested:
+				c.JSON(http.StatusBadRequest, restapi.ErrorResponse{
+					ErrorType:    restapi.ErrTypeEmailCodeNotRequested,
+					ErrorMessage: "Recovery email is not set or already verified",
+				})
+			case services.ErrWrongCode:
+				c.JSON(http.StatusBadRequest, restapi.ErrorResponse{
+					ErrorType:    restapi.ErrTypeWrongCode,
+					ErrorMessage: "Wrong email verification code",
+				})
+			default:
+				c.Error(err)
+				restapi.SendInternalError(c)
+			}
+			return
+		}
+
+		restapi.SendSuccess(c, cloudPasswordResponse{})
+	}
+}
+
+type setCloudPasswordRequest struct {
+	CurrentPassword string `json:"current_password"`
+	NewPassword     string `json:"new_password" binding:"required"`
+	RecoveryEmail   string `json:"recovery_email"`
+}
+
+type removeCloudPasswordRequest struct {
+	CurrentPassword string `json:"current_password" binding:"required"`
+}
+
+type verifyRecoveryEmailRequest struct {
+	Code string `json:"code" binding:"required"`
+}
+
+type cloudPasswordResponse struct{}
+
+func validateSetCloudPassword(req *setCloudPasswordRequest) []restapi.ErrorDetail {
+	var errors []restapi.ErrorDetail
+	if len(req.NewPassword) < minPasswordLength || len(req.NewPassword) > maxPasswordLength {
+		errors = append(errors, restapi.ErrorDetail{
+			Field:   "new_password",
+			Message: "it should contain from 8 to 128 characters",
+		})
+	}
+	if req.RecoveryEmail != "" {
+		if _, err := mail.ParseAddress(req.RecoveryEmail); err != nil {
+			errors = append(errors, restapi.ErrorDetail{
+				Field:   "recovery_email",
+				Message: "it should be a valid email address",
+			})
+		}
+	}
+	return errors
+}
@@ -57,6 +57,11 @@ func Identity(service IdentityService) gin.HandlerFunc {
 					ErrorType:    restapi.ErrTypeInvalidTokenType,
 					ErrorMessage: "Invalid token type",
 				})
+			case services.ErrUserSuspended:
+				c.JSON(http.StatusForbidden, restapi.ErrorResponse{
+					ErrorType:    restapi.ErrTypeUserSuspended,
+					ErrorMessage: "User is suspended",
+				})
 			default:
 				c.Error(err)
 				restapi.SendInternalError(c)
@@ -69,6 +74,43 @@ func Identity(service IdentityService) gin.HandlerFunc {
 	}
 }
 
+// Returns false if err is not related to the access token
+func sendAccessTokenError(c *gin.Context, err error) bool {
+	switch err {
+	case services.ErrInvalidJWT:
+		c.JSON(http.StatusUnauthorized, restapi.ErrorResponse{
+			ErrorType:    restapi.ErrTypeInvalidJWT,
+			ErrorMessage: "Invalid Authorization token",
+		})
+	case services.ErrAccessTokenExpired:
+		c.JSON(http.StatusUnauthorized, restapi.ErrorResponse{
+			ErrorType:    restapi.ErrTypeAccessTokenExpired,
+			ErrorMessage: "Access token expired",
+		})
+	case services.ErrInvalidTokenType:
+		c.JSON(http.StatusUnauthorized, restapi.ErrorResponse{
+			ErrorType:    restapi.ErrTypeInvalidTokenType,
+			ErrorMessage: "Invalid token type",
+		})
+	default:
+		return false
+	}
+	return true
+}
+
+// Returns false if the response is already sent
+func extractAccessToken(c *gin.Context) (jwt.Token, bool) {
+	access, ok := extractJWT(c.GetHeader(HeaderAuthorization))
+	if !ok {
+		c.JSON(http.StatusUnauthorized, restapi.ErrorResponse{
+			ErrorType:    restapi.ErrTypeUnautorized,
+			ErrorMessage: "Authorization header must contain access token",
+		})
+		return "", false
+	}
+	return access, true
+}
+
 func extractJWT(authHeader string) (jwt.Token, bool) {
 	found, ok := strings.CutPrefix(authHeader, "Bearer ")
 	if !ok {
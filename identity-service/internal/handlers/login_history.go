@@ -0,0 +1,60 @@
+package handlers
+
+import (
+	"context"
+	"time"
+
+	"github.com/chakchat/chakchat-backend/identity-service/internal/restapi"
+	"github.com/chakchat/chakchat-backend/identity-service/internal/services"
+	"github.com/chakchat/chakchat-backend/shared/go/jwt"
+	"github.com/gin-gonic/gin"
+)
+
+type LoginHistoryService interface {
+	History(ctx context.Context, access jwt.Token) ([]services.LoginEvent, error)
+}
+
+func LoginHistory(service LoginHistoryService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		access, ok := extractAccessToken(c)
+		if !ok {
+			return
+		}
+
+		history, err := service.History(c.Request.Context(), access)
+		if err != nil {
+			if sendAccessTokenError(c, err) {
+				return
+			}
+			c.Error(err)
+			restapi.SendInternalError(c)
+			return
+		}
+
+		events := make([]loginEvent, 0, len(history))
+		for _, event := range history {
+			events = append(events, loginEvent{
+				Type:       event.Type,
+				Time:       event.Time,
+				IP:         event.IP,
+				UserAgent:  event.UserAgent,
+				DeviceType: event.DeviceType,
+			})
+		}
+		restapi.SendSuccess(c, loginHistoryResponse{
+			Events: events,
+		})
+	}
+}
+
+type loginEvent struct {
+	Type       string    `json:"type"`
+	Time       time.Time `json:"time"`
+	IP         string    `json:"ip"`
+	UserAgent  string    `json:"user_agent"`
+	DeviceType string    `json:"device_type"`
+}
+
+type loginHistoryResponse struct {
+	Events []loginEvent `json:"events"`
+}
@@ -0,0 +1,353 @@
+package handlers
+
+import (
+	"context"
+	"encoding/base64"
+	"net/http"
+	"time"
+
+	"github.com/chakchat/chakchat-backend/identity-service/internal/restapi"
+	"github.com/chakchat/chakchat-backend/identity-service/internal/services"
+	"github.com/chakchat/chakchat-backend/shared/go/jwt"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type PasskeyService interface {
+	BeginRegistration(ctx context.Context, access jwt.Token) (*services.PasskeyCreationOptions, error)
+	FinishRegistration(ctx context.Context, access jwt.Token, data *services.PasskeyRegistrationData) (*services.Passkey, error)
+	List(ctx context.Context, access jwt.Token) ([]services.Passkey, error)
+	Remove(ctx context.Context, access jwt.Token, credentialId []byte) error
+	BeginSignIn(ctx context.Context) (*services.PasskeyRequestOptions, error)
+	SignIn(ctx context.Context, data *services.PasskeySignInData, device *services.DeviceInfo,
+		client *services.ClientInfo) (jwt.Pair, error)
+}
+
+func PasskeyBeginRegistration(service PasskeyService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		access, ok := extractAccessToken(c)
+		if !ok {
+			return
+		}
+
+		opts, err := service.BeginRegistration(c.Request.Context(), access)
+		if err != nil {
+			if sendAccessTokenError(c, err) {
+				return
+			}
+			c.Error(err)
+			restapi.SendInternalError(c)
+			return
+		}
+
+		exclude := make([]string, 0, len(opts.ExcludeCredentials))
+		for _, id := range opts.ExcludeCredentials {
+			exclude = append(exclude, base64.RawURLEncoding.EncodeToString(id))
+		}
+		restapi.SendSuccess(c, passkeyCreationOptionsResponse{
+			Challenge:               base64.RawURLEncoding.EncodeToString(opts.Challenge),
+			RPID:                    opts.RPID,
+			RPName:                  opts.RPName,
+			UserHandle:              base64.RawURLEncoding.EncodeToString(opts.UserHandle),
+			UserName:                opts.UserName,
+			DisplayName:             opts.DisplayName,
+			Algs:                    opts.Algs,
+			ExcludeCredentials:      exclude,
+			Timeout:                 opts.Timeout.Milliseconds(),
+			RequireUserVerification: opts.RequireUserVerification,
+		})
+	}
+}
+
+func PasskeyFinishRegistration(service PasskeyService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		access, ok := extractAccessToken(c)
+		if !ok {
+			return
+		}
+
+		var req passkeyFinishRegistrationRequest
+		if err := c.ShouldBindBodyWithJSON(&req); err != nil {
+			restapi.SendUnprocessableJSON(c)
+			return
+		}
+
+		var data services.PasskeyRegistrationData
+		decoded := decodePasskeyFields(c, map[string]passkeyField{
+			"client_data_json":   {req.ClientDataJSON, &data.ClientDataJSON},
+			"attestation_object": {req.AttestationObject, &data.AttestationObject},
+		})
+		if !decoded {
+			return
+		}
+		data.Name = req.Name
+
+		passkey, err := service.FinishRegistration(c.Request.Context(), access, &data)
+		if err != nil {
+			if sendAccessTokenError(c, err) {
+				return
+			}
+			switch err {
+			case services.ErrPasskeyChallengeNotFound:
+				sendPasskeyChallengeNotFound(c)
+			case services.ErrPasskeyVerificationFailed:
+				sendPasskeyVerificationFailed(c)
+			case services.ErrPasskeyAlreadyRegistered:
+				c.JSON(http.StatusConflict, restapi.ErrorResponse{
+					ErrorType:    restapi.ErrTypePasskeyAlreadyRegistered,
+					ErrorMessage: "Passkey is already registered",
+				})
+			default:
+				c.Error(err)
+				restapi.SendInternalError(c)
+			}
+			return
+		}
+
+		restapi.SendSuccess(c, toPasskeyResponse(passkey))
+	}
+}
+
+func PasskeyList(service PasskeyService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		access, ok := extractAccessToken(c)
+		if !ok {
+			return
+		}
+
+		passkeys, err := service.List(c.Request.Context(), access)
+		if err != nil {
+			if sendAccessTokenError(c, err) {
+				return
+			}
+			c.Error(err)
+			restapi.SendInternalError(c)
+			return
+		}
+
+		resp := make([]passkeyResponse, 0, len(passkeys))
+		for i := range passkeys {
+			resp = append(resp, toPasskeyResponse(&passkeys[i]))
+		}
+		restapi.SendSuccess(c, passkeyListResponse{
+			Passkeys: resp,
+		})
+	}
+}
+
+func PasskeyRemove(service PasskeyService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		access, ok := extractAccessToken(c)
+		if !ok {
+			return
+		}
+
+		credentialId, err := base64.RawURLEncoding.DecodeString(c.Param("id"))
+		if err != nil {
+			restapi.SendValidationError(c, []restapi.ErrorDetail{
+				{
+					Field:   "id",
+					Message: "Invalid base64url",
+				},
+			})
+			return
+		}
+
+		err = service.Remove(c.Request.Context(), access, credentialId)
+		if err != nil {
+			if sendAccessTokenError(c, err) {
+				return
+			}
+			switch err {
+			case services.ErrPasskeyNotFound:
+				sendPasskeyNotFound(c)
+			default:
+				c.Error(err)
+				restapi.SendInternalError(c)
+			}
+			return
+		}
+
+		restapi.SendSuccess(c, struct{}{})
+	}
+}
+
+func PasskeyBeginSignIn(service PasskeyService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		opts, err := service.BeginSignIn(c.Request.Context())
+		if err != nil {
+			c.Error(err)
+			restapi.SendInternalError(c)
+			return
+		}
+
+		restapi.SendSuccess(c, passkeyRequestOptionsResponse{
+			SessionId:               opts.SessionId,
+			Challenge:               base64.RawURLEncoding.EncodeToString(opts.Challenge),
+			RPID:                    opts.RPID,
+			Timeout:                 opts.Timeout.Milliseconds(),
+			RequireUserVerification: opts.RequireUserVerification,
+		})
+	}
+}
+
+func PasskeySignIn(service PasskeyService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req passkeySignInRequest
+		if err := c.ShouldBindBodyWithJSON(&req); err != nil {
+			restapi.SendUnprocessableJSON(c)
+			return
+		}
+
+		data := services.PasskeySignInData{
+			SessionId: req.SessionId,
+		}
+		decoded := decodePasskeyFields(c, map[string]passkeyField{
+			"credential_id":      {req.CredentialId, &data.CredentialId},
+			"client_data_json":   {req.ClientDataJSON, &data.ClientDataJSON},
+			"authenticator_data": {req.AuthenticatorData, &data.AuthenticatorData},
+			"signature":          {req.Signature, &data.Signature},
+			"user_handle":        {req.UserHandle, &data.UserHandle},
+		})
+		if !decoded {
+			return
+		}
+
+		tokens, err := service.SignIn(c.Request.Context(), &data, toDeviceInfo(req.Device), toClientInfo(c))
+		if err != nil {
+			switch err {
+			case services.ErrPasskeyChallengeNotFound:
+				sendPasskeyChallengeNotFound(c)
+			case services.ErrPasskeyNotFound:
+				sendPasskeyNotFound(c)
+			case services.ErrPasskeyVerificationFailed:
+				sendPasskeyVerificationFailed(c)
+			case services.ErrUserNotFound:
+				c.JSON(http.StatusNotFound, restapi.ErrorResponse{
+					ErrorType:    restapi.ErrTypeUserNotFound,
+					ErrorMessage: "Such user doesn't exist",
+				})
+			case services.ErrUserSuspended:
+				c.JSON(http.StatusForbidden, restapi.ErrorResponse{
+					ErrorType:    restapi.ErrTypeUserSuspended,
+					ErrorMessage: "User is suspended",
+				})
+			default:
+				c.Error(err)
+				restapi.SendInternalError(c)
+			}
+			return
+		}
+
+		restapi.SendSuccess(c, signInResponse{
+			AccessToken:  string(tokens.Access),
+			RefreshToken: string(tokens.Refresh),
+		})
+	}
+}
+
+type passkeyField struct {
+	value string
+	dest  *[]byte
+}
+
+// decodePasskeyFields decodes base64url fields and sends validation error if any of them is malformed.
+func decodePasskeyFields(c *gin.Context, fields map[string]passkeyField) bool {
+	var errors []restapi.ErrorDetail
+	for name, field := range fields {
+		decoded, err := base64.RawURLEncoding.DecodeString(field.value)
+		if err != nil {
+			errors = append(errors, restapi.ErrorDetail{
+				Field:   name,
+				Message: "Invalid base64url",
+			})
+			continue
+		}
+		*field.dest = decoded
+	}
+
+	if len(errors) != 0 {
+		restapi.SendValidationError(c, errors)
+		return false
+	}
+	return true
+}
+
+func sendPasskeyChallengeNotFound(c *gin.Context) {
+	c.JSON(http.StatusNotFound, restapi.ErrorResponse{
+		ErrorType:    restapi.ErrTypePasskeyChallengeNotFound,
+		ErrorMessage: "Passkey challenge not found or expired",
+	})
+}
+
+func sendPasskeyNotFound(c *gin.Context) {
+	c.JSON(http.StatusNotFound, restapi.ErrorResponse{
+		ErrorType:    restapi.ErrTypePasskeyNotFound,
+		ErrorMessage: "Passkey not found",
+	})
+}
+
+func sendPasskeyVerificationFailed(c *gin.Context) {
+	c.JSON(http.StatusBadRequest, restapi.ErrorResponse{
+		ErrorType:    restapi.ErrTypePasskeyVerificationFailed,
+		ErrorMessage: "Passkey verification failed",
+	})
+}
+
+func toPasskeyResponse(passkey *services.Passkey) passkeyResponse {
+	return passkeyResponse{
+		Id:         base64.RawURLEncoding.EncodeToString(passkey.Credential.ID),
+		Name:       passkey.Name,
+		CreatedAt:  passkey.CreatedAt,
+		LastUsedAt: passkey.LastUsedAt,
+	}
+}
+
+type passkeyCreationOptionsResponse struct {
+	Challenge          string   `json:"challenge"`
+	RPID               string   `json:"rp_id"`
+	RPName             string   `json:"rp_name"`
+	UserHandle         string   `json:"user_handle"`
+	UserName           string   `json:"user_name"`
+	DisplayName        string   `json:"display_name"`
+	Algs               []int    `json:"algs"`
+	ExcludeCredentials []string `json:"exclude_credentials"`
+	// In milliseconds as WebAuthn expects
+	Timeout                 int64 `json:"timeout"`
+	RequireUserVerification bool  `json:"require_user_verification"`
+}
+
+type passkeyFinishRegistrationRequest struct {
+	Name              string `json:"name" binding:"max=64"`
+	ClientDataJSON    string `json:"client_data_json" binding:"required"`
+	AttestationObject string `json:"attestation_object" binding:"required"`
+}
+
+type passkeyResponse struct {
+	Id         string     `json:"id"`
+	Name       string     `json:"name"`
+	CreatedAt  time.Time  `json:"created_at"`
+	LastUsedAt *time.Time `json:"last_used_at,omitempty"`
+}
+
+type passkeyListResponse struct {
+	Passkeys []passkeyResponse `json:"passkeys"`
+}
+
+type passkeyRequestOptionsResponse struct {
+	SessionId               uuid.UUID `json:"session_id"`
+	Challenge               string    `json:"challenge"`
+	RPID                    string    `json:"rp_id"`
+	Timeout                 int64     `json:"timeout"`
+	RequireUserVerification bool      `json:"require_user_verification"`
+}
+
+type passkeySignInRequest struct {
+	SessionId         uuid.UUID   `json:"session_id" binding:"required"`
+	CredentialId      string      `json:"credential_id" binding:"required"`
+	ClientDataJSON    string      `json:"client_data_json" binding:"required"`
+	AuthenticatorData string      `json:"authenticator_data" binding:"required"`
+	Signature         string      `json:"signature" binding:"required"`
+	UserHandle        string      `json:"user_handle" binding:"required"`
+	Device            *DeviceInfo `json:"device"`
+}
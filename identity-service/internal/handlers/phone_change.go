@@ -0,0 +1,132 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/chakchat/chakchat-backend/identity-service/internal/restapi"
+	"github.com/chakchat/chakchat-backend/identity-service/internal/services"
+	"github.com/chakchat/chakchat-backend/shared/go/jwt"
+	"github.com/gin-gonic/gin"
+)
+
+type PhoneChangeService interface {
+	SendCode(ctx context.Context, access jwt.Token, phone string) error
+	Confirm(ctx context.Context, access jwt.Token, code string) error
+}
+
+func PhoneChangeSendCode(service PhoneChangeService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		access, ok := extractAccessToken(c)
+		if !ok {
+			return
+		}
+
+		var req phoneChangeSendCodeRequest
+		if err := c.ShouldBindBodyWithJSON(&req); err != nil {
+			restapi.SendUnprocessableJSON(c)
+			return
+		}
+
+		if errors := validatePhoneChangeSendCode(&req); len(errors) != 0 {
+			restapi.SendValidationError(c, errors)
+			return
+		}
+
+		err := service.SendCode(c.Request.Context(), access, req.Phone)
+		if err != nil {
+			if sendAccessTokenError(c, err) {
+				return
+			}
+			switch err {
+			case services.ErrPhoneTaken:
+				c.JSON(http.StatusBadRequest, restapi.ErrorResponse{
+					ErrorType:    restapi.ErrTypePhoneTaken,
+					ErrorMessage: "Phone is already taken by another user",
+				})
+			case services.ErrSendCodeFreqExceeded:
+				c.JSON(http.StatusBadRequest, restapi.ErrorResponse{
+					ErrorType:    restapi.ErrTypeSendCodeFreqExceeded,
+					ErrorMessage: "Send code operation frequency exceeded",
+				})
+			default:
+				c.Error(err)
+				restapi.SendInternalError(c)
+			}
+			return
+		}
+
+		restapi.SendSuccess(c, phoneChangeResponse{})
+	}
+}
+
+func PhoneChangeConfirm(service PhoneChangeService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		access, ok := extractAccessToken(c)
+		if !ok {
+			return
+		}
+
+		var req phoneChangeConfirmRequest
+		if err := c.ShouldBindBodyWithJSON(&req); err != nil {
+			restapi.SendUnprocessableJSON(c)
+			return
+		}
+
+		err := service.Confirm(c.Request.Context(), access, req.Code)
+		if err != nil {
+			if sendAccessTokenError(c, err) {
+				return
+			}
+			switch err {
+			case services.ErrPhoneChangeNotRequested:
+				c.JSON(http.StatusBadRequest, restapi.ErrorResponse{
+					ErrorType:    restapi.ErrTypePhoneChangeNotRequested,
+					ErrorMessage: "Phone change is not requested or already expired",
+				})
+			case services.ErrWrongCode:
+				c.JSON(http.StatusBadRequest, restapi.ErrorResponse{
+					ErrorType:    restapi.ErrTypeWrongCode,
+					ErrorMessage: "Wrong phone verification code",
+				})
+			case services.ErrPhoneTaken:
+				c.JSON(http.StatusBadRequest, restapi.ErrorResponse{
+					ErrorType:    restapi.ErrTypePhoneTaken,
+					ErrorMessage: "Phone is already taken by another user",
+				})
+			case services.ErrUserNotFound:
+				c.JSON(http.StatusNotFound, restapi.ErrorResponse{
+					ErrorType:    restapi.ErrTypeUserNotFound,
+					ErrorMessage: "User not found",
+				})
+			default:
+				c.Error(err)
+				restapi.SendInternalError(c)
+			}
+			return
+		}
+
+		restapi.SendSuccess(c, phoneChangeResponse{})
+	}
+}
+
+type phoneChangeSendCodeRequest struct {
+	Phone string `json:"phone" binding:"required"`
+}
+
+type phoneChangeConfirmRequest struct {
+	Code string `json:"code" binding:"required"`
+}
+
+type phoneChangeResponse struct{}
+
+func validatePhoneChangeSendCode(req *phoneChangeSendCodeRequest) []restapi.ErrorDetail {
+	var errors []restapi.ErrorDetail
+	if !phoneRegex.MatchString(req.Phone) {
+		errors = append(errors, restapi.ErrorDetail{
+			Field:   "phone",
+			Message: "phone number must match a regex " + phoneRegex.String(),
+		})
+	}
+	return errors
+}
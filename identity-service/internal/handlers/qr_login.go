@@ -0,0 +1,144 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/chakchat/chakchat-backend/identity-service/internal/restapi"
+	"github.com/chakchat/chakchat-backend/identity-service/internal/services"
+	"github.com/chakchat/chakchat-backend/shared/go/jwt"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type QRLoginService interface {
+	CreateToken(ctx context.Context) (*services.QRLoginMeta, error)
+	Confirm(ctx context.Context, access jwt.Token, token uuid.UUID) error
+	SignIn(ctx context.Context, token, pollSecret uuid.UUID,
+		device *services.DeviceInfo, client *services.ClientInfo) (jwt.Pair, error)
+}
+
+func QRLoginCreateToken(service QRLoginService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		meta, err := service.CreateToken(c.Request.Context())
+		if err != nil {
+			c.Error(err)
+			restapi.SendInternalError(c)
+			return
+		}
+
+		restapi.SendSuccess(c, qrLoginCreateTokenResponse{
+			QRToken:    meta.Token,
+			PollSecret: meta.PollSecret,
+			ExpiresAt:  meta.ExpiresAt,
+		})
+	}
+}
+
+func QRLoginConfirm(service QRLoginService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		access, ok := extractAccessToken(c)
+		if !ok {
+			return
+		}
+
+		var req qrLoginTokenRequest
+		if err := c.ShouldBindBodyWithJSON(&req); err != nil {
+			restapi.SendUnprocessableJSON(c)
+			return
+		}
+
+		err := service.Confirm(c.Request.Context(), access, req.QRToken)
+		if err != nil {
+			if sendAccessTokenError(c, err) {
+				return
+			}
+			switch err {
+			case services.ErrQRLoginTokenNotFound:
+				c.JSON(http.StatusNotFound, restapi.ErrorResponse{
+					ErrorType:    restapi.ErrTypeQRLoginTokenNotFound,
+					ErrorMessage: "QR login token not found or expired",
+				})
+			case services.ErrQRLoginAlreadyConfirmed:
+				c.JSON(http.StatusConflict, restapi.ErrorResponse{
+					ErrorType:    restapi.ErrTypeQRLoginAlreadyConfirmed,
+					ErrorMessage: "QR login is already confirmed",
+				})
+			default:
+				c.Error(err)
+				restapi.SendInternalError(c)
+			}
+			return
+		}
+
+		restapi.SendSuccess(c, qrLoginConfirmResponse{})
+	}
+}
+
+func QRLoginSignIn(service QRLoginService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req qrLoginSignInRequest
+		if err := c.ShouldBindBodyWithJSON(&req); err != nil {
+			restapi.SendUnprocessableJSON(c)
+			return
+		}
+
+		var deviceInfo *services.DeviceInfo
+		if req.Device != nil {
+			deviceInfo = &services.DeviceInfo{
+				DeviceToken: req.Device.DeviceToken,
+				Type:        req.Device.Type,
+			}
+		}
+
+		tokens, err := service.SignIn(c.Request.Context(), req.QRToken, req.PollSecret, deviceInfo, toClientInfo(c))
+		if err != nil {
+			switch err {
+			case services.ErrQRLoginTokenNotFound:
+				c.JSON(http.StatusNotFound, restapi.ErrorResponse{
+					ErrorType:    restapi.ErrTypeQRLoginTokenNotFound,
+					ErrorMessage: "QR login token not found or expired",
+				})
+			case services.ErrQRLoginNotConfirmed:
+				c.JSON(http.StatusConflict, restapi.ErrorResponse{
+					ErrorType:    restapi.ErrTypeQRLoginNotConfirmed,
+					ErrorMessage: "QR login is not confirmed yet",
+				})
+			case services.ErrUserSuspended:
+				c.JSON(http.StatusForbidden, restapi.ErrorResponse{
+					ErrorType:    restapi.ErrTypeUserSuspended,
+					ErrorMessage: "User is suspended",
+				})
+			default:
+				c.Error(err)
+				restapi.SendInternalError(c)
+			}
+			return
+		}
+
+		restapi.SendSuccess(c, signInResponse{
+			AccessToken:  string(tokens.Access),
+			RefreshToken: string(tokens.Refresh),
+		})
+	}
+}
+
+type qrLoginCreateTokenResponse struct {
+	QRToken uuid.UUID `json:"qr_token"`
+	// Must not be shown in the QR code
+	PollSecret uuid.UUID `json:"poll_secret"`
+	ExpiresAt  time.Time `json:"expires_at"`
+}
+
+type qrLoginTokenRequest struct {
+	QRToken uuid.UUID `json:"qr_token" binding:"required"`
+}
+
+type qrLoginConfirmResponse struct{}
+
+type qrLoginSignInRequest struct {
+	QRToken    uuid.UUID   `json:"qr_token" binding:"required"`
+	PollSecret uuid.UUID   `json:"poll_secret" binding:"required"`
+	Device     *DeviceInfo `json:"device"`
+}
@@ -12,7 +12,7 @@ import (
 )
 
 type RefreshJWTService interface {
-	Refresh(ctx context.Context, refresh jwt.Token) (jwt.Pair, error)
+	Refresh(ctx context.Context, refresh jwt.Token, client *services.ClientInfo) (jwt.Pair, error)
 }
 
 func RefreshJWT(service RefreshJWTService) gin.HandlerFunc {
@@ -23,7 +23,7 @@ func RefreshJWT(service RefreshJWTService) gin.HandlerFunc {
 			return
 		}
 
-		tokens, err := service.Refresh(c.Request.Context(), jwt.Token(req.RefreshToken))
+		tokens, err := service.Refresh(c.Request.Context(), jwt.Token(req.RefreshToken), toClientInfo(c))
 
 		if err != nil {
 			log.Printf("met error in refresh-jwt: %s", err)
@@ -48,6 +48,11 @@ func RefreshJWT(service RefreshJWTService) gin.HandlerFunc {
 					ErrorType:    restapi.ErrTypeInvalidJWT,
 					ErrorMessage: "Invalid signature of JWT",
 				})
+			case services.ErrUserSuspended:
+				c.JSON(http.StatusForbidden, restapi.ErrorResponse{
+					ErrorType:    restapi.ErrTypeUserSuspended,
+					ErrorMessage: "User is suspended",
+				})
 			default:
 				c.Error(err)
 				restapi.SendInternalError(c)
@@ -12,7 +12,8 @@ import (
 )
 
 type SignInService interface {
-	SignIn(ctx context.Context, signInKey uuid.UUID, code string, device *services.DeviceInfo) (jwt.Pair, error)
+	SignIn(ctx context.Context, signInKey uuid.UUID, code string, device *services.DeviceInfo,
+		client *services.ClientInfo) (jwt.Pair, error)
 }
 
 func SignIn(service SignInService) gin.HandlerFunc {
@@ -36,7 +37,7 @@ func SignIn(service SignInService) gin.HandlerFunc {
 			}
 		}
 
-		tokens, err := service.SignIn(c.Request.Context(), req.SignInKey, req.Code, deviceInfo)
+		tokens, err := service.SignIn(c.Request.Context(), req.SignInKey, req.Code, deviceInfo, toClientInfo(c))
 
 		if err != nil {
 			switch err {
@@ -50,6 +51,16 @@ func SignIn(service SignInService) gin.HandlerFunc {
 					ErrorType:    restapi.ErrTypeWrongCode,
 					ErrorMessage: "Wrong phone verification code",
 				})
+			case services.ErrUserSuspended:
+				c.JSON(http.StatusForbidden, restapi.ErrorResponse{
+					ErrorType:    restapi.ErrTypeUserSuspended,
+					ErrorMessage: "User is suspended",
+				})
+			case services.ErrPasswordRequired:
+				c.JSON(http.StatusForbidden, restapi.ErrorResponse{
+					ErrorType:    restapi.ErrTypePasswordRequired,
+					ErrorMessage: "Cloud password is required. Sign in with the same sign in key and the password",
+				})
 			default:
 				c.Error(err)
 				restapi.SendInternalError(c)
@@ -69,6 +80,23 @@ type DeviceInfo struct {
 	DeviceToken string `json:"device_token"`
 }
 
+func toDeviceInfo(device *DeviceInfo) *services.DeviceInfo {
+	if device == nil {
+		return nil
+	}
+	return &services.DeviceInfo{
+		DeviceToken: device.DeviceToken,
+		Type:        device.Type,
+	}
+}
+
+func toClientInfo(c *gin.Context) *services.ClientInfo {
+	return &services.ClientInfo{
+		IP:        c.ClientIP(),
+		UserAgent: c.Request.UserAgent(),
+	}
+}
+
 type signInRequest struct {
 	SignInKey uuid.UUID   `json:"signin_key" binding:"required"`
 	Code      string      `json:"code" binding:"required"`
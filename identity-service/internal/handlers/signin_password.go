@@ -0,0 +1,163 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/chakchat/chakchat-backend/identity-service/internal/restapi"
+	"github.com/chakchat/chakchat-backend/identity-service/internal/services"
+	"github.com/chakchat/chakchat-backend/shared/go/jwt"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type SignInPasswordService interface {
+	SignInWithPassword(ctx context.Context, signInKey uuid.UUID, password string, device *services.DeviceInfo,
+		client *services.ClientInfo) (jwt.Pair, error)
+	SendRecoveryCode(ctx context.Context, signInKey uuid.UUID) error
+	RecoverPassword(ctx context.Context, signInKey uuid.UUID, code string, device *services.DeviceInfo,
+		client *services.ClientInfo) (jwt.Pair, error)
+}
+
+func SignInPassword(service SignInPasswordService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req signInPasswordRequest
+		if err := c.ShouldBindBodyWithJSON(&req); err != nil {
+			restapi.SendUnprocessableJSON(c)
+			return
+		}
+
+		tokens, err := service.SignInWithPassword(c.Request.Context(), req.SignInKey, req.Password, toDeviceInfo(req.Device), toClientInfo(c))
+		if err != nil {
+			if sendSignInPasswordError(c, err) {
+				return
+			}
+			switch err {
+			case services.ErrWrongPassword:
+				c.JSON(http.StatusBadRequest, restapi.ErrorResponse{
+					ErrorType:    restapi.ErrTypeWrongPassword,
+					ErrorMessage: "Wrong cloud password",
+				})
+			default:
+				c.Error(err)
+				restapi.SendInternalError(c)
+			}
+			return
+		}
+
+		restapi.SendSuccess(c, signInResponse{
+			AccessToken:  string(tokens.Access),
+			RefreshToken: string(tokens.Refresh),
+		})
+	}
+}
+
+func SignInPasswordRecovery(service SignInPasswordService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req signInPasswordRecoveryRequest
+		if err := c.ShouldBindBodyWithJSON(&req); err != nil {
+			restapi.SendUnprocessableJSON(c)
+			return
+		}
+
+		err := service.SendRecoveryCode(c.Request.Context(), req.SignInKey)
+		if err != nil {
+			if sendSignInPasswordError(c, err) {
+				return
+			}
+			switch err {
+			case services.ErrPasswordNotSet:
+				c.JSON(http.StatusBadRequest, restapi.ErrorResponse{
+					ErrorType:    restapi.ErrTypePasswordNotSet,
+					ErrorMessage: "Cloud password is not set",
+				})
+			case services.ErrRecoveryEmailNotSet:
+				c.JSON(http.StatusBadRequest, restapi.ErrorResponse{
+					ErrorType:    restapi.ErrTypeRecoveryEmailNotSet,
+					ErrorMessage: "Verified recovery email is not set",
+				})
+			default:
+				c.Error(err)
+				restapi.SendInternalError(c)
+			}
+			return
+		}
+
+		restapi.SendSuccess(c, signInPasswordRecoveryResponse{})
+	}
+}
+
+func SignInPasswordRecoveryConfirm(service SignInPasswordService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req signInPasswordRecoveryConfirmRequest
+		if err := c.ShouldBindBodyWithJSON(&req); err != nil {
+			restapi.SendUnprocessableJSON(c)
+			return
+		}
+
+		tokens, err := service.RecoverPassword(c.Request.Context(), req.SignInKey, req.Code, toDeviceInfo(req.Device), toClientInfo(c))
+		if err != nil {
+			if sendSignInPasswordError(c, err) {
+				return
+			}
+			switch err {
+			case services.ErrWrongCode:
+				c.JSON(http.StatusBadRequest, restapi.ErrorResponse{
+					ErrorType:    restapi.ErrTypeWrongCode,
+					ErrorMessage: "Wrong recovery code",
+				})
+			default:
+				c.Error(err)
+				restapi.SendInternalError(c)
+			}
+			return
+		}
+
+		restapi.SendSuccess(c, signInResponse{
+			AccessToken:  string(tokens.Access),
+			RefreshToken: string(tokens.Refresh),
+		})
+	}
+}
+
+// Returns false if err is not related to the sign in key or the user
+func sendSignInPasswordError(c *gin.Context, err error) bool {
+	switch err {
+	case services.ErrSignInKeyNotFound:
+		c.JSON(http.StatusNotFound, restapi.ErrorResponse{
+			ErrorType:    restapi.ErrTypeSignInKeyNotFound,
+			ErrorMessage: "Sign in key not found",
+		})
+	case services.ErrPhoneNotVerified:
+		c.JSON(http.StatusBadRequest, restapi.ErrorResponse{
+			ErrorType:    restapi.ErrTypePhoneNotVerified,
+			ErrorMessage: "Phone verification code must be entered first",
+		})
+	case services.ErrUserSuspended:
+		c.JSON(http.StatusForbidden, restapi.ErrorResponse{
+			ErrorType:    restapi.ErrTypeUserSuspended,
+			ErrorMessage: "User is suspended",
+		})
+	default:
+		return false
+	}
+	return true
+}
+
+type signInPasswordRequest struct {
+	SignInKey uuid.UUID   `json:"signin_key" binding:"required"`
+	Password  string      `json:"password" binding:"required"`
+	Device    *DeviceInfo `json:"device"`
+}
+
+type signInPasswordRecoveryRequest struct {
+	SignInKey uuid.UUID `json:"signin_key" binding:"required"`
+}
+
+type signInPasswordRecoveryResponse struct{}
+
+type signInPasswordRecoveryConfirmRequest struct {
+	SignInKey uuid.UUID   `json:"signin_key" binding:"required"`
+	Code      string      `json:"code" binding:"required"`
+	Device    *DeviceInfo `json:"device"`
+}
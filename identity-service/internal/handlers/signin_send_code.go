@@ -15,7 +15,7 @@ import (
 var phoneRegex = regexp.MustCompile(`^[78]9\d{9}$`)
 
 type SignInSendCodeService interface {
-	SendCode(ctx context.Context, phone string) (signInKey uuid.UUID, err error)
+	SendCode(ctx context.Context, phone string, viaDevices bool) (signInKey uuid.UUID, err error)
 }
 
 func SignInSendCode(service SignInSendCodeService) gin.HandlerFunc {
@@ -31,7 +31,7 @@ func SignInSendCode(service SignInSendCodeService) gin.HandlerFunc {
 			return
 		}
 
-		signInKey, err := service.SendCode(c.Request.Context(), req.Phone)
+		signInKey, err := service.SendCode(c.Request.Context(), req.Phone, req.ViaDevices)
 
 		if err != nil {
 			switch err {
@@ -59,7 +59,8 @@ func SignInSendCode(service SignInSendCodeService) gin.HandlerFunc {
 }
 
 type signInSendCodeRequest struct {
-	Phone string `json:"phone" binding:"required"`
+	Phone      string `json:"phone" binding:"required"`
+	ViaDevices bool   `json:"via_devices"`
 }
 
 type signInSendCodeResponse struct {
@@ -11,7 +11,7 @@ import (
 )
 
 type SignOutService interface {
-	SignOut(ctx context.Context, refresh jwt.Token) error
+	SignOut(ctx context.Context, refresh jwt.Token, client *services.ClientInfo) error
 }
 
 func SignOut(service SignOutService) gin.HandlerFunc {
@@ -22,7 +22,7 @@ func SignOut(service SignOutService) gin.HandlerFunc {
 			return
 		}
 
-		err := service.SignOut(c.Request.Context(), jwt.Token(req.RefreshJWT))
+		err := service.SignOut(c.Request.Context(), jwt.Token(req.RefreshJWT), toClientInfo(c))
 
 		// I think that signing out expired token counts as a successful operation
 		if err != nil && err != services.ErrRefreshTokenExpired {
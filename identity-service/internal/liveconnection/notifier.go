@@ -0,0 +1,108 @@
+package liveconnection
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/chakchat/chakchat-backend/identity-service/internal/services"
+	"github.com/google/uuid"
+	"github.com/segmentio/kafka-go"
+)
+
+const (
+	typeUserPhoneChanged = "user_phone_changed"
+	typeNewLogin         = "new_login"
+	typeUserSuspended    = "user_suspended"
+)
+
+// Notifier publishes user related events to the live-connection-service topic.
+type Notifier struct {
+	writer *kafka.Writer
+}
+
+func NewNotifier(writer *kafka.Writer) *Notifier {
+	return &Notifier{
+		writer: writer,
+	}
+}
+
+func (n *Notifier) PhoneChanged(ctx context.Context, userId uuid.UUID, phone string, receivers []uuid.UUID) error {
+	type Data struct {
+		UserId uuid.UUID `json:"user_id"`
+		Phone  string    `json:"phone"`
+	}
+	return n.publish(ctx, receivers, typeUserPhoneChanged, Data{
+		UserId: userId,
+		Phone:  phone,
+	})
+}
+
+// NewLogin alerts the user's devices about a sign in from an unknown device type or IP range.
+// deviceToken is the previous device of the user since the new one is stored for the user by then.
+func (n *Notifier) NewLogin(ctx context.Context, userId uuid.UUID, event *services.LoginEvent,
+	deviceToken string) error {
+	type Data struct {
+		Time        time.Time `json:"time"`
+		IP          string    `json:"ip"`
+		UserAgent   string    `json:"user_agent"`
+		DeviceType  string    `json:"device_type"`
+		DeviceToken string    `json:"device_token,omitempty"`
+	}
+	return n.publish(ctx, []uuid.UUID{userId}, typeNewLogin, Data{
+		Time:        event.Time,
+		IP:          event.IP,
+		UserAgent:   event.UserAgent,
+		DeviceType:  event.DeviceType,
+		DeviceToken: deviceToken,
+	})
+}
+
+// UserSuspended makes live-connection-service close the user's connections.
+func (n *Notifier) UserSuspended(ctx context.Context, userId uuid.UUID, suspension *services.Suspension) error {
+	type Data struct {
+		Reason string     `json:"reason"`
+		Until  *time.Time `json:"until"`
+	}
+	return n.publish(ctx, []uuid.UUID{userId}, typeUserSuspended, Data{
+		Reason: suspension.Reason,
+		Until:  suspension.Until,
+	})
+}
+
+func (n *Notifier) publish(ctx context.Context, receivers []uuid.UUID, typ string, data any) error {
+	type Msg struct {
+		Receivers []uuid.UUID `json:"receivers"`
+		Type      string      `json:"type"`
+		Data      any         `json:"data"`
+	}
+	raw, err := json.Marshal(Msg{
+		Receivers: receivers,
+		Type:      typ,
+		Data:      data,
+	})
+	if err != nil {
+		return fmt.Errorf("marshalling live connection message failed: %s", err)
+	}
+
+	if err := n.writer.WriteMessages(ctx, kafka.Message{Value: raw}); err != nil {
+		return fmt.Errorf("publishing %s to live connection failed: %s", typ, err)
+	}
+	return nil
+}
+
+// NopNotifier is used when live connection is disabled.
+type NopNotifier struct{}
+
+func (NopNotifier) PhoneChanged(context.Context, uuid.UUID, string, []uuid.UUID) error {
+	return nil
+}
+
+func (NopNotifier) NewLogin(context.Context, uuid.UUID, *services.LoginEvent, string) error {
+	return nil
+}
+
+func (NopNotifier) UserSuspended(context.Context, uuid.UUID, *services.Suspension) error {
+	return nil
+}
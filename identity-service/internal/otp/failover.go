@@ -0,0 +1,46 @@
+package otp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+)
+
+type NamedSender struct {
+	Name   string
+	Sender CodeSender
+}
+
+// FailoverSender tries senders in order until one of them succeeds.
+type FailoverSender struct {
+	senders []NamedSender
+}
+
+func NewFailoverSender(senders ...NamedSender) *FailoverSender {
+	return &FailoverSender{
+		senders: senders,
+	}
+}
+
+func (s *FailoverSender) SendCode(ctx context.Context, to *Recipient, message string) error {
+	var errs []error
+	for _, sender := range s.senders {
+		err := sender.Sender.SendCode(ctx, to, message)
+		if err == nil {
+			return nil
+		}
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return ctxErr
+		}
+		if !errors.Is(err, ErrNotDeliverable) {
+			log.Printf("sending code via %s failed, trying next channel: %s", sender.Name, err)
+		}
+		errs = append(errs, fmt.Errorf("%s: %w", sender.Name, err))
+	}
+
+	if len(errs) == 0 {
+		return errors.New("no code senders configured")
+	}
+	return fmt.Errorf("all code senders failed: %w", errors.Join(errs...))
+}
@@ -0,0 +1,53 @@
+package otp
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_FailoverSender(t *testing.T) {
+	to := &Recipient{Phone: "79998887766"}
+
+	t.Run("FirstSucceeds", func(t *testing.T) {
+		first, second := &fakeSender{}, &fakeSender{}
+		sender := NewFailoverSender(NamedSender{"first", first}, NamedSender{"second", second})
+
+		require.NoError(t, sender.SendCode(context.Background(), to, "code"))
+		require.Equal(t, 1, first.calls)
+		require.Equal(t, 0, second.calls)
+	})
+
+	t.Run("FallsBack", func(t *testing.T) {
+		first := &fakeSender{err: errors.New("provider is down")}
+		second := &fakeSender{err: ErrNotDeliverable}
+		third := &fakeSender{}
+		sender := NewFailoverSender(NamedSender{"first", first}, NamedSender{"second", second}, NamedSender{"third", third})
+
+		require.NoError(t, sender.SendCode(context.Background(), to, "code"))
+		require.Equal(t, 1, third.calls)
+	})
+
+	t.Run("AllFail", func(t *testing.T) {
+		sender := NewFailoverSender(
+			NamedSender{"first", &fakeSender{err: errors.New("provider is down")}},
+			NamedSender{"second", &fakeSender{err: ErrNotDeliverable}},
+		)
+
+		err := sender.SendCode(context.Background(), to, "code")
+		require.Error(t, err)
+		require.ErrorIs(t, err, ErrNotDeliverable)
+	})
+}
+
+type fakeSender struct {
+	err   error
+	calls int
+}
+
+func (s *fakeSender) SendCode(_ context.Context, _ *Recipient, _ string) error {
+	s.calls++
+	return s.err
+}
@@ -0,0 +1,69 @@
+package otp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/segmentio/kafka-go"
+)
+
+const liveConnectionTypeLoginCode = "login_code"
+
+type DeviceChecker interface {
+	HasDevice(ctx context.Context, userID uuid.UUID) (bool, error)
+}
+
+// LiveConnectionSender delivers the code to devices the user is already signed in on.
+// The message is published to the live-connection-service topic,
+// so it reaches the device via web socket or push notification if the device is offline.
+type LiveConnectionSender struct {
+	writer  *kafka.Writer
+	devices DeviceChecker
+}
+
+func NewLiveConnectionSender(writer *kafka.Writer, devices DeviceChecker) *LiveConnectionSender {
+	return &LiveConnectionSender{
+		writer:  writer,
+		devices: devices,
+	}
+}
+
+func (s *LiveConnectionSender) SendCode(ctx context.Context, to *Recipient, message string) error {
+	if to.UserId == uuid.Nil || !to.ViaDevices {
+		return ErrNotDeliverable
+	}
+
+	hasDevice, err := s.devices.HasDevice(ctx, to.UserId)
+	if err != nil {
+		return fmt.Errorf("checking user devices failed: %s", err)
+	}
+	if !hasDevice {
+		return ErrNotDeliverable
+	}
+
+	type Data struct {
+		Message string `json:"message"`
+	}
+	type Msg struct {
+		Receivers []uuid.UUID `json:"receivers"`
+		Type      string      `json:"type"`
+		Data      Data        `json:"data"`
+	}
+	raw, err := json.Marshal(Msg{
+		Receivers: []uuid.UUID{to.UserId},
+		Type:      liveConnectionTypeLoginCode,
+		Data: Data{
+			Message: message,
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("marshalling live connection message failed: %s", err)
+	}
+
+	if err := s.writer.WriteMessages(ctx, kafka.Message{Value: raw}); err != nil {
+		return fmt.Errorf("publishing code to live connection failed: %s", err)
+	}
+	return nil
+}
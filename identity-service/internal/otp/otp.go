@@ -0,0 +1,27 @@
+package otp
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+)
+
+// Returned by channels that can't reach the recipient at all (e.g. user has no signed-in devices).
+// It is not a provider failure so it is not worth logging.
+var ErrNotDeliverable = errors.New("code is not deliverable via this channel")
+
+type Recipient struct {
+	Phone string
+	// Set only for email channels
+	Email string
+	// uuid.Nil if the user is not registered yet (sign-up)
+	UserId uuid.UUID
+	// Set if the user asked to get the code on the devices they are signed in on.
+	// Otherwise such channels are skipped, because the devices may be lost.
+	ViaDevices bool
+}
+
+type CodeSender interface {
+	SendCode(ctx context.Context, to *Recipient, message string) error
+}
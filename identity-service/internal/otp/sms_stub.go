@@ -0,0 +1,50 @@
+package otp
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+// SmsServerStubSender sends codes to sms-service-stub.
+// It is used as a test double of a real SMS or email provider.
+type SmsServerStubSender struct {
+	addr string
+}
+
+func NewSmsServerStubSender(addr string) *SmsServerStubSender {
+	return &SmsServerStubSender{
+		addr: addr,
+	}
+}
+
+func (s *SmsServerStubSender) SendCode(ctx context.Context, to *Recipient, message string) error {
+	type Req struct {
+		Phone   string `json:"phone,omitempty"`
+		Email   string `json:"email,omitempty"`
+		Message string `json:"message"`
+	}
+	body, _ := json.Marshal(Req{
+		Phone:   to.Phone,
+		Email:   to.Email,
+		Message: message,
+	})
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.addr, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("creating stub server request failed: %s", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("sending sms to stub server failed: %s", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("sending sms request failed with status code: %s", resp.Status)
+	}
+	return nil
+}
@@ -0,0 +1,33 @@
+package otp
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	smsaero_golang "github.com/smsaero/smsaero_golang/smsaero"
+)
+
+type SmsAeroSender struct {
+	email  string
+	apiKey string
+}
+
+func NewSmsAeroSender(email string, apiKey string) *SmsAeroSender {
+	return &SmsAeroSender{
+		email:  email,
+		apiKey: apiKey,
+	}
+}
+
+func (s *SmsAeroSender) SendCode(ctx context.Context, to *Recipient, message string) error {
+	client := smsaero_golang.NewSmsAeroClient(s.email, s.apiKey, smsaero_golang.WithContext(ctx))
+	phoneInt, err := strconv.Atoi(to.Phone)
+	if err != nil {
+		return fmt.Errorf("error converting phone number to integer: %v", err)
+	}
+	if _, err := client.SendSms(phoneInt, message); err != nil {
+		return fmt.Errorf("smsaero sending sms failed: %s", err)
+	}
+	return nil
+}
@@ -0,0 +1,71 @@
+package otp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+)
+
+const smsRuAddr = "https://sms.ru/sms/send"
+
+// SmsRuSender sends codes via SMS.ru HTTP API.
+type SmsRuSender struct {
+	addr  string
+	apiId string
+}
+
+func NewSmsRuSender(apiId string) *SmsRuSender {
+	return &SmsRuSender{
+		addr:  smsRuAddr,
+		apiId: apiId,
+	}
+}
+
+func (s *SmsRuSender) SendCode(ctx context.Context, to *Recipient, message string) error {
+	query := url.Values{
+		"api_id": {s.apiId},
+		"to":     {to.Phone},
+		"msg":    {message},
+		"json":   {"1"},
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.addr, nil)
+	if err != nil {
+		return fmt.Errorf("creating sms.ru request failed: %s", err)
+	}
+	req.URL.RawQuery = query.Encode()
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("sms.ru sending sms failed: %s", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("sms.ru request failed with status code: %s", resp.Status)
+	}
+
+	type Status struct {
+		Status     string `json:"status"`
+		StatusText string `json:"status_text"`
+	}
+	var body struct {
+		Status
+		Sms map[string]Status `json:"sms"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return fmt.Errorf("decoding sms.ru response failed: %s", err)
+	}
+
+	// The request may succeed while the sms to the particular phone is rejected
+	if body.Status.Status != "OK" {
+		return fmt.Errorf("sms.ru rejected the request: %s", body.StatusText)
+	}
+	for phone, sms := range body.Sms {
+		if sms.Status != "OK" {
+			return fmt.Errorf("sms.ru rejected the sms to %s: %s", phone, sms.StatusText)
+		}
+	}
+	return nil
+}
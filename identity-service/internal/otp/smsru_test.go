@@ -0,0 +1,44 @@
+package otp
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_SmsRuSender(t *testing.T) {
+	to := &Recipient{Phone: "79998887766"}
+
+	newSender := func(t *testing.T, response string) *SmsRuSender {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			query := r.URL.Query()
+			require.Equal(t, "api-id", query.Get("api_id"))
+			require.Equal(t, to.Phone, query.Get("to"))
+			require.Equal(t, "code", query.Get("msg"))
+			w.Write([]byte(response))
+		}))
+		t.Cleanup(server.Close)
+
+		sender := NewSmsRuSender("api-id")
+		sender.addr = server.URL
+		return sender
+	}
+
+	t.Run("Sent", func(t *testing.T) {
+		sender := newSender(t, `{"status":"OK","sms":{"79998887766":{"status":"OK","sms_id":"1"}}}`)
+		require.NoError(t, sender.SendCode(context.Background(), to, "code"))
+	})
+
+	t.Run("RequestRejected", func(t *testing.T) {
+		sender := newSender(t, `{"status":"ERROR","status_text":"Invalid api_id"}`)
+		require.Error(t, sender.SendCode(context.Background(), to, "code"))
+	})
+
+	t.Run("SmsRejected", func(t *testing.T) {
+		sender := newSender(t, `{"status":"OK","sms":{"79998887766":{"status":"ERROR","status_text":"Invalid phone"}}}`)
+		require.Error(t, sender.SendCode(context.Background(), to, "code"))
+	})
+}
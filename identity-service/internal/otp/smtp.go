@@ -0,0 +1,55 @@
+package otp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/smtp"
+	"strings"
+)
+
+type SmtpConfig struct {
+	// host:port
+	Addr     string
+	Username string
+	Password string
+	From     string
+}
+
+// SmtpSender sends codes to Recipient.Email
+type SmtpSender struct {
+	conf *SmtpConfig
+}
+
+func NewSmtpSender(conf *SmtpConfig) *SmtpSender {
+	return &SmtpSender{
+		conf: conf,
+	}
+}
+
+func (s *SmtpSender) SendCode(_ context.Context, to *Recipient, message string) error {
+	if to.Email == "" {
+		return ErrNotDeliverable
+	}
+	if strings.ContainsAny(to.Email, "\r\n") {
+		return errors.New("invalid email address")
+	}
+
+	host, _, err := net.SplitHostPort(s.conf.Addr)
+	if err != nil {
+		return fmt.Errorf("invalid smtp address: %s", err)
+	}
+	auth := smtp.PlainAuth("", s.conf.Username, s.conf.Password, host)
+
+	msg := "From: " + s.conf.From + "\r\n" +
+		"To: " + to.Email + "\r\n" +
+		"Subject: chakchat code\r\n" +
+		"\r\n" +
+		message + "\r\n"
+
+	if err := smtp.SendMail(s.conf.Addr, auth, s.conf.From, []string{to.Email}, []byte(msg)); err != nil {
+		return fmt.Errorf("smtp sending email failed: %s", err)
+	}
+	return nil
+}
@@ -0,0 +1,24 @@
+package password
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_HashVerify(t *testing.T) {
+	hash, err := Hash("correct horse battery staple")
+	require.NoError(t, err)
+	require.Contains(t, hash, "$argon2id$")
+
+	ok, err := Verify("correct horse battery staple", hash)
+	require.NoError(t, err)
+	require.True(t, ok)
+
+	ok, err = Verify("wrong password", hash)
+	require.NoError(t, err)
+	require.False(t, ok)
+
+	_, err = Verify("password", "not a hash")
+	require.ErrorIs(t, err, ErrInvalidHash)
+}
@@ -4,20 +4,24 @@ import (
 	"context"
 	"errors"
 	"log"
+	"time"
 
 	"github.com/chakchat/chakchat-backend/identity-service/internal/proto/identity"
+	"github.com/chakchat/chakchat-backend/identity-service/internal/services"
 	"github.com/chakchat/chakchat-backend/identity-service/internal/storage"
 	"github.com/google/uuid"
 )
 
 type GRPCService struct {
 	deviceStorage *storage.DeviceStorage
+	suspensions   *services.SuspensionService
 	identity.UnimplementedIdentityServiceServer
 }
 
-func NewGRPCServer(deviceStorage *storage.DeviceStorage) *GRPCService {
+func NewGRPCServer(deviceStorage *storage.DeviceStorage, suspensions *services.SuspensionService) *GRPCService {
 	return &GRPCService{
 		deviceStorage: deviceStorage,
+		suspensions:   suspensions,
 	}
 }
 
@@ -50,3 +54,56 @@ func (s *GRPCService) GetDeviceToken(ctx context.Context, req *identity.DeviceTo
 		DeviceToken: token,
 	}, nil
 }
+
+// SuspendUser is the operators' API. The gRPC port is not exposed through nginx.
+func (s *GRPCService) SuspendUser(ctx context.Context, req *identity.SuspendUserRequest) (*identity.SuspendUserResponse, error) {
+	userId, err := uuid.Parse(req.GetUserId().GetValue())
+	if err != nil {
+		log.Printf("Can't parse userId")
+		return &identity.SuspendUserResponse{
+			Status: identity.SuspensionStatus_SUSPENSION_FAILED,
+		}, nil
+	}
+
+	var until *time.Time
+	if req.SuspendedUntil != nil {
+		t := time.Unix(req.GetSuspendedUntil(), 0).UTC()
+		until = &t
+	}
+
+	if err := s.suspensions.Suspend(ctx, userId, req.Reason, until); err != nil {
+		return &identity.SuspendUserResponse{
+			Status: suspensionFailureStatus(err),
+		}, nil
+	}
+	return &identity.SuspendUserResponse{
+		Status: identity.SuspensionStatus_SUSPENSION_UPDATED,
+	}, nil
+}
+
+func (s *GRPCService) UnsuspendUser(ctx context.Context, req *identity.UnsuspendUserRequest) (*identity.UnsuspendUserResponse, error) {
+	userId, err := uuid.Parse(req.GetUserId().GetValue())
+	if err != nil {
+		log.Printf("Can't parse userId")
+		return &identity.UnsuspendUserResponse{
+			Status: identity.SuspensionStatus_SUSPENSION_FAILED,
+		}, nil
+	}
+
+	if err := s.suspensions.Unsuspend(ctx, userId); err != nil {
+		return &identity.UnsuspendUserResponse{
+			Status: suspensionFailureStatus(err),
+		}, nil
+	}
+	return &identity.UnsuspendUserResponse{
+		Status: identity.SuspensionStatus_SUSPENSION_UPDATED,
+	}, nil
+}
+
+func suspensionFailureStatus(err error) identity.SuspensionStatus {
+	if errors.Is(err, services.ErrUserNotFound) {
+		return identity.SuspensionStatus_SUSPENSION_USER_NOT_FOUND
+	}
+	log.Printf("Unknown fail: %s", err)
+	return identity.SuspensionStatus_SUSPENSION_FAILED
+}
@@ -28,6 +28,26 @@ const (
 	ErrTypeSignUpKeyNotFound     = "signup_key_not_found"
 	ErrTypeUsernameAlreadyExists = "username_already_exists"
 	ErrTypePhoneNotVerified      = "phone_not_verified"
+
+	ErrTypeQRLoginTokenNotFound    = "qr_login_token_not_found"
+	ErrTypeQRLoginNotConfirmed     = "qr_login_not_confirmed"
+	ErrTypeQRLoginAlreadyConfirmed = "qr_login_already_confirmed"
+
+	ErrTypePasswordRequired      = "password_required"
+	ErrTypeWrongPassword         = "wrong_password"
+	ErrTypePasswordNotSet        = "password_not_set"
+	ErrTypeRecoveryEmailNotSet   = "recovery_email_not_set"
+	ErrTypeEmailCodeNotRequested = "email_code_not_requested"
+
+	ErrTypePhoneTaken              = "phone_taken"
+	ErrTypePhoneChangeNotRequested = "phone_change_not_requested"
+
+	ErrTypeUserSuspended = "user_suspended"
+
+	ErrTypePasskeyChallengeNotFound  = "passkey_challenge_not_found"
+	ErrTypePasskeyNotFound           = "passkey_not_found"
+	ErrTypePasskeyVerificationFailed = "passkey_verification_failed"
+	ErrTypePasskeyAlreadyRegistered  = "passkey_already_registered"
 )
 
 type ErrorDetail struct {
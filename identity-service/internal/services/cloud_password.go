@@ -0,0 +1,169 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/chakchat/chakchat-backend/identity-service/internal/otp"
+	"github.com/chakchat/chakchat-backend/identity-service/internal/password"
+	"github.com/chakchat/chakchat-backend/shared/go/jwt"
+	"github.com/google/uuid"
+)
+
+var (
+	ErrPasswordRequired      = errors.New("cloud password is required")
+	ErrWrongPassword         = errors.New("wrong cloud password")
+	ErrPasswordNotSet        = errors.New("cloud password is not set")
+	ErrRecoveryEmailNotSet   = errors.New("verified recovery email is not set")
+	ErrEmailCodeNotRequested = errors.New("recovery email verification code is not requested")
+)
+
+// CloudPassword is the second factor of signing in (two-step verification).
+type CloudPassword struct {
+	UserId uuid.UUID
+	// Argon2id hash in PHC string format
+	Hash string
+
+	RecoveryEmail string
+	EmailVerified bool
+	// Code sent to RecoveryEmail until it is verified
+	EmailCode string
+}
+
+type CloudPasswordStorage interface {
+	Find(ctx context.Context, userId uuid.UUID) (*CloudPassword, bool, error)
+	Store(ctx context.Context, pw *CloudPassword) error
+	Remove(ctx context.Context, userId uuid.UUID) error
+}
+
+type SetPasswordData struct {
+	// Required only if the password is already set
+	CurrentPassword string
+	NewPassword     string
+	// Empty keeps the current recovery email
+	RecoveryEmail string
+}
+
+type CloudPasswordService struct {
+	storage    CloudPasswordStorage
+	emails     otp.CodeSender
+	accessConf *jwt.Config
+}
+
+func NewCloudPasswordService(storage CloudPasswordStorage, emails otp.CodeSender, accessConf *jwt.Config) *CloudPasswordService {
+	return &CloudPasswordService{
+		storage:    storage,
+		emails:     emails,
+		accessConf: accessConf,
+	}
+}
+
+// SetPassword sets a new password or changes the existing one.
+func (s *CloudPasswordService) SetPassword(ctx context.Context, access jwt.Token, data *SetPasswordData) error {
+	userId, _, err := parseAccess(s.accessConf, access)
+	if err != nil {
+		return err
+	}
+
+	pw, ok, err := s.storage.Find(ctx, userId)
+	if err != nil {
+		return fmt.Errorf("cloud password finding failed: %s", err)
+	}
+	if ok {
+		if err := checkPassword(pw, data.CurrentPassword); err != nil {
+			return err
+		}
+	} else {
+		pw = &CloudPassword{UserId: userId}
+	}
+
+	if pw.Hash, err = password.Hash(data.NewPassword); err != nil {
+		return fmt.Errorf("password hashing failed: %s", err)
+	}
+
+	if data.RecoveryEmail != "" && data.RecoveryEmail != pw.RecoveryEmail {
+		pw.RecoveryEmail = data.RecoveryEmail
+		pw.EmailVerified = false
+		pw.EmailCode = genCode()
+
+		to := &otp.Recipient{
+			UserId: userId,
+			Email:  pw.RecoveryEmail,
+		}
+		if err := s.emails.SendCode(ctx, to, renderEmailCodeMessage(pw.EmailCode)); err != nil {
+			return fmt.Errorf("send email code error: %s", err)
+		}
+	}
+
+	if err := s.storage.Store(ctx, pw); err != nil {
+		return fmt.Errorf("cloud password storing failed: %s", err)
+	}
+	return nil
+}
+
+func (s *CloudPasswordService) RemovePassword(ctx context.Context, access jwt.Token, currentPassword string) error {
+	userId, _, err := parseAccess(s.accessConf, access)
+	if err != nil {
+		return err
+	}
+
+	pw, ok, err := s.storage.Find(ctx, userId)
+	if err != nil {
+		return fmt.Errorf("cloud password finding failed: %s", err)
+	}
+	if !ok {
+		return ErrPasswordNotSet
+	}
+	if err := checkPassword(pw, currentPassword); err != nil {
+		return err
+	}
+
+	if err := s.storage.Remove(ctx, userId); err != nil {
+		return fmt.Errorf("cloud password removal failed: %s", err)
+	}
+	return nil
+}
+
+func (s *CloudPasswordService) VerifyRecoveryEmail(ctx context.Context, access jwt.Token, code string) error {
+	userId, _, err := parseAccess(s.accessConf, access)
+	if err != nil {
+		return err
+	}
+
+	pw, ok, err := s.storage.Find(ctx, userId)
+	if err != nil {
+		return fmt.Errorf("cloud password finding failed: %s", err)
+	}
+	if !ok {
+		return ErrPasswordNotSet
+	}
+	if pw.EmailCode == "" {
+		return ErrEmailCodeNotRequested
+	}
+	if pw.EmailCode != code {
+		return ErrWrongCode
+	}
+
+	pw.EmailVerified = true
+	pw.EmailCode = ""
+	if err := s.storage.Store(ctx, pw); err != nil {
+		return fmt.Errorf("cloud password storing failed: %s", err)
+	}
+	return nil
+}
+
+func checkPassword(pw *CloudPassword, plain string) error {
+	ok, err := password.Verify(plain, pw.Hash)
+	if err != nil {
+		return fmt.Errorf("password verification failed: %s", err)
+	}
+	if !ok {
+		return ErrWrongPassword
+	}
+	return nil
+}
+
+func renderEmailCodeMessage(code string) string {
+	return "Do not tell this code to anybody. Your chakchat recovery email verification code is " + code
+}
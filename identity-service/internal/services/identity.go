@@ -3,9 +3,11 @@ package services
 import (
 	"context"
 	"errors"
+	"fmt"
 	"log"
 
 	"github.com/chakchat/chakchat-backend/shared/go/jwt"
+	"github.com/google/uuid"
 )
 
 var ErrAccessTokenExpired = errors.New("access token expired")
@@ -13,12 +15,14 @@ var ErrAccessTokenExpired = errors.New("access token expired")
 type IdentityService struct {
 	userConf     *jwt.Config
 	internalConf *jwt.Config
+	suspensions  SuspensionChecker
 }
 
-func NewIdentityService(userConf, internalConf *jwt.Config) *IdentityService {
+func NewIdentityService(userConf, internalConf *jwt.Config, suspensions SuspensionChecker) *IdentityService {
 	return &IdentityService{
 		userConf:     userConf,
 		internalConf: internalConf,
+		suspensions:  suspensions,
 	}
 }
 
@@ -35,6 +39,15 @@ func (i *IdentityService) Idenitfy(ctx context.Context, token jwt.Token) (jwt.In
 		return "", ErrInvalidJWT
 	}
 
+	userId, err := uuid.Parse(fmt.Sprint(claims[jwt.ClaimSub]))
+	if err != nil {
+		return "", ErrInvalidJWT
+	}
+	// Access tokens issued before the suspension are still valid, so it is checked on every request
+	if err := i.suspensions.CheckSuspended(ctx, userId); err != nil {
+		return "", err
+	}
+
 	internalClaims := extractInternal(claims)
 
 	internalToken, err := jwt.Generate(i.internalConf, internalClaims)
@@ -44,6 +57,26 @@ func (i *IdentityService) Idenitfy(ctx context.Context, token jwt.Token) (jwt.In
 	return jwt.InternalToken(internalToken), nil
 }
 
+// parseAccess is used by endpoints authenticated by user access token directly.
+func parseAccess(conf *jwt.Config, access jwt.Token) (uuid.UUID, jwt.Claims, error) {
+	claims, err := jwt.Parse(conf, access)
+	if err != nil {
+		if err == jwt.ErrTokenExpired {
+			return uuid.Nil, nil, ErrAccessTokenExpired
+		}
+		if err == jwt.ErrInvalidTokenType {
+			return uuid.Nil, nil, ErrInvalidTokenType
+		}
+		return uuid.Nil, nil, ErrInvalidJWT
+	}
+
+	userId, err := uuid.Parse(fmt.Sprint(claims[jwt.ClaimSub]))
+	if err != nil {
+		return uuid.Nil, nil, ErrInvalidJWT
+	}
+	return userId, claims, nil
+}
+
 func extractInternal(claims jwt.Claims) jwt.Claims {
 	return jwt.Claims{
 		jwt.ClaimSub:      claims[jwt.ClaimSub],
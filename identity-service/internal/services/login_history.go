@@ -0,0 +1,186 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/netip"
+	"time"
+
+	"github.com/chakchat/chakchat-backend/shared/go/jwt"
+	"github.com/google/uuid"
+)
+
+const (
+	LoginEventSignIn  = "sign_in"
+	LoginEventRefresh = "refresh"
+	LoginEventSignOut = "sign_out"
+)
+
+// ClientInfo describes where the request came from.
+type ClientInfo struct {
+	IP        string
+	UserAgent string
+}
+
+type LoginEvent struct {
+	Type       string
+	Time       time.Time
+	IP         string
+	UserAgent  string
+	DeviceType string
+}
+
+type LoginHistoryStorage interface {
+	Add(ctx context.Context, userId uuid.UUID, event *LoginEvent) error
+	// Returns events from the newest to the oldest.
+	List(ctx context.Context, userId uuid.UUID) ([]LoginEvent, error)
+}
+
+type DeviceFinder interface {
+	Find(ctx context.Context, userId uuid.UUID) (*DeviceInfo, bool, error)
+}
+
+type NewLoginNotifier interface {
+	// deviceToken is the token of the device the user signed in before. It is empty if it is unknown.
+	NewLogin(ctx context.Context, userId uuid.UUID, event *LoginEvent, deviceToken string) error
+}
+
+// LoginRecorder is used by services that issue or revoke tokens.
+type LoginRecorder interface {
+	// device may be nil, then the device type is taken from the stored device.
+	// Sign ins are recorded with recordSignIn.
+	Record(ctx context.Context, userId uuid.UUID, eventType string, device *DeviceInfo, client *ClientInfo) error
+}
+
+// recordSignIn records the sign in and only then stores the new device.
+// The order matters: recording looks up the stored device to put the previous one
+// to the new login alert, and it would find the new device if it were stored first.
+func recordSignIn(ctx context.Context, logins LoginRecorder, devices DeviceStorage, userId uuid.UUID,
+	device *DeviceInfo, client *ClientInfo) error {
+	if err := logins.Record(ctx, userId, LoginEventSignIn, device, client); err != nil {
+		return err
+	}
+
+	if device != nil {
+		if err := devices.Store(ctx, userId, device); err != nil {
+			return fmt.Errorf("failed to store device info: %s", err)
+		}
+	}
+	return nil
+}
+
+type LoginHistoryService struct {
+	storage    LoginHistoryStorage
+	devices    DeviceFinder
+	notifier   NewLoginNotifier
+	accessConf *jwt.Config
+}
+
+func NewLoginHistoryService(storage LoginHistoryStorage, devices DeviceFinder, notifier NewLoginNotifier,
+	accessConf *jwt.Config) *LoginHistoryService {
+	return &LoginHistoryService{
+		storage:    storage,
+		devices:    devices,
+		notifier:   notifier,
+		accessConf: accessConf,
+	}
+}
+
+func (s *LoginHistoryService) Record(ctx context.Context, userId uuid.UUID, eventType string,
+	device *DeviceInfo, client *ClientInfo) error {
+	event := &LoginEvent{
+		Type: eventType,
+		Time: nowUTC(),
+	}
+	if client != nil {
+		event.IP = client.IP
+		event.UserAgent = client.UserAgent
+	}
+
+	var stored *DeviceInfo
+	if device == nil || eventType == LoginEventSignIn {
+		found, ok, err := s.devices.Find(ctx, userId)
+		if err != nil {
+			return fmt.Errorf("device finding failed: %s", err)
+		}
+		if ok {
+			stored = found
+		}
+	}
+	if device != nil {
+		event.DeviceType = device.Type
+	} else if stored != nil {
+		event.DeviceType = stored.Type
+	}
+
+	if eventType == LoginEventSignIn {
+		history, err := s.storage.List(ctx, userId)
+		if err != nil {
+			return fmt.Errorf("login history listing failed: %s", err)
+		}
+		// Nobody to alert on the very first sign in
+		if len(history) != 0 && isNewLoginSource(history, event) {
+			var deviceToken string
+			if stored != nil {
+				deviceToken = stored.DeviceToken
+			}
+			// Sign in shouldn't fail because of the alert
+			if err := s.notifier.NewLogin(ctx, userId, event, deviceToken); err != nil {
+				log.Printf("new login alert publishing failed: %s", err)
+			}
+		}
+	}
+
+	if err := s.storage.Add(ctx, userId, event); err != nil {
+		return fmt.Errorf("login history adding failed: %s", err)
+	}
+	return nil
+}
+
+func (s *LoginHistoryService) History(ctx context.Context, access jwt.Token) ([]LoginEvent, error) {
+	userId, _, err := parseAccess(s.accessConf, access)
+	if err != nil {
+		return nil, err
+	}
+
+	history, err := s.storage.List(ctx, userId)
+	if err != nil {
+		return nil, fmt.Errorf("login history listing failed: %s", err)
+	}
+	return history, nil
+}
+
+// isNewLoginSource reports whether the device type or the IP range of the event is not seen in the history.
+func isNewLoginSource(history []LoginEvent, event *LoginEvent) bool {
+	eventRange, eventRangeOk := ipRange(event.IP)
+	// Unknown IP can't be compared, so only the device type is checked
+	deviceSeen, rangeSeen := false, !eventRangeOk
+	for _, prev := range history {
+		if prev.DeviceType == event.DeviceType {
+			deviceSeen = true
+		}
+		if prevRange, ok := ipRange(prev.IP); ok && eventRangeOk && prevRange == eventRange {
+			rangeSeen = true
+		}
+	}
+	return !deviceSeen || !rangeSeen
+}
+
+// ipRange returns /24 network for IPv4 and /48 for IPv6.
+func ipRange(ip string) (netip.Prefix, bool) {
+	addr, err := netip.ParseAddr(ip)
+	if err != nil {
+		return netip.Prefix{}, false
+	}
+	addr = addr.Unmap()
+	bits := 48
+	if addr.Is4() {
+		bits = 24
+	}
+	prefix, err := addr.Prefix(bits)
+	if err != nil {
+		return netip.Prefix{}, false
+	}
+	return prefix, true
+}
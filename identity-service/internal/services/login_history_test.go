@@ -0,0 +1,138 @@
+package services
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_LoginHistory_NewLoginAlert(t *testing.T) {
+	// Arrange
+	userId := uuid.New()
+	storage := &loginHistoryStorageFake{m: map[uuid.UUID][]LoginEvent{}}
+	notifier := &newLoginNotifierFake{}
+	service := NewLoginHistoryService(storage, deviceFinderFake{}, notifier, testJWTConfig("access"))
+	ios := &DeviceInfo{Type: "ios"}
+	record := func(device *DeviceInfo, ip string) {
+		err := service.Record(context.Background(), userId, LoginEventSignIn, device, &ClientInfo{IP: ip})
+		require.NoError(t, err)
+	}
+
+	// Act & Assert
+	record(ios, "192.168.1.10")
+	assert.Equal(t, 0, notifier.alerts, "first sign in must not be alerted")
+
+	record(ios, "192.168.1.20")
+	assert.Equal(t, 0, notifier.alerts, "the same device type and /24 network are known")
+
+	record(ios, "10.0.0.1")
+	assert.Equal(t, 1, notifier.alerts, "new IP range")
+
+	record(&DeviceInfo{Type: "android"}, "10.0.0.2")
+	assert.Equal(t, 2, notifier.alerts, "new device type")
+
+	err := service.Record(context.Background(), userId, LoginEventRefresh, nil, &ClientInfo{IP: "172.16.0.1"})
+	require.NoError(t, err)
+	assert.Equal(t, 2, notifier.alerts, "refresh is not alerted")
+
+	assert.Len(t, storage.m[userId], 5)
+	assert.Equal(t, LoginEventRefresh, storage.m[userId][0].Type)
+}
+
+func Test_LoginHistory_AlertsPreviousDevice(t *testing.T) {
+	// Arrange
+	userId := uuid.New()
+	storage := &loginHistoryStorageFake{m: map[uuid.UUID][]LoginEvent{
+		userId: {{Type: LoginEventSignIn, IP: "192.168.1.10", DeviceType: "ios"}},
+	}}
+	notifier := &newLoginNotifierFake{}
+	devices := deviceFinderFake{device: &DeviceInfo{Type: "ios", DeviceToken: "old-token"}}
+	service := NewLoginHistoryService(storage, devices, notifier, testJWTConfig("access"))
+
+	// Act
+	err := service.Record(context.Background(), userId, LoginEventSignIn,
+		&DeviceInfo{Type: "android", DeviceToken: "new-token"}, &ClientInfo{IP: "10.0.0.1"})
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, 1, notifier.alerts)
+	assert.Equal(t, "old-token", notifier.deviceToken)
+	assert.Equal(t, "android", storage.m[userId][0].DeviceType)
+}
+
+func Test_RecordSignIn_StoresDeviceAfterRecording(t *testing.T) {
+	// Arrange
+	userId := uuid.New()
+	storage := &loginHistoryStorageFake{m: map[uuid.UUID][]LoginEvent{
+		userId: {{Type: LoginEventSignIn, IP: "192.168.1.10", DeviceType: "ios"}},
+	}}
+	notifier := &newLoginNotifierFake{}
+	devices := &singleDeviceStorageFake{device: &DeviceInfo{Type: "ios", DeviceToken: "old-token"}}
+	logins := NewLoginHistoryService(storage, devices, notifier, testJWTConfig("access"))
+
+	// Act
+	err := recordSignIn(context.Background(), logins, devices, userId,
+		&DeviceInfo{Type: "android", DeviceToken: "new-token"}, &ClientInfo{IP: "10.0.0.1"})
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, "old-token", notifier.deviceToken)
+	assert.Equal(t, "new-token", devices.device.DeviceToken)
+}
+
+type loginHistoryStorageFake struct {
+	m map[uuid.UUID][]LoginEvent
+}
+
+func (s *loginHistoryStorageFake) Add(_ context.Context, userId uuid.UUID, event *LoginEvent) error {
+	s.m[userId] = append([]LoginEvent{*event}, s.m[userId]...)
+	return nil
+}
+
+func (s *loginHistoryStorageFake) List(_ context.Context, userId uuid.UUID) ([]LoginEvent, error) {
+	return s.m[userId], nil
+}
+
+type deviceFinderFake struct {
+	device *DeviceInfo
+}
+
+func (f deviceFinderFake) Find(context.Context, uuid.UUID) (*DeviceInfo, bool, error) {
+	return f.device, f.device != nil, nil
+}
+
+type newLoginNotifierFake struct {
+	alerts      int
+	deviceToken string
+}
+
+func (n *newLoginNotifierFake) NewLogin(_ context.Context, _ uuid.UUID, _ *LoginEvent, deviceToken string) error {
+	n.alerts++
+	n.deviceToken = deviceToken
+	return nil
+}
+
+// singleDeviceStorageFake keeps one device like the real storage does,
+// so the previous device is lost once the new one is stored.
+type singleDeviceStorageFake struct {
+	device *DeviceInfo
+}
+
+func (f *singleDeviceStorageFake) Find(context.Context, uuid.UUID) (*DeviceInfo, bool, error) {
+	return f.device, f.device != nil, nil
+}
+
+func (f *singleDeviceStorageFake) Store(_ context.Context, _ uuid.UUID, device *DeviceInfo) error {
+	f.device = device
+	return nil
+}
+
+func (f *singleDeviceStorageFake) Refresh(context.Context, uuid.UUID) error { return nil }
+
+func (f *singleDeviceStorageFake) Remove(context.Context, uuid.UUID) error {
+	f.device = nil
+	return nil
+}
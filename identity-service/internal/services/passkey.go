@@ -0,0 +1,473 @@
+package services
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/chakchat/chakchat-backend/identity-service/internal/userservice"
+	"github.com/chakchat/chakchat-backend/shared/go/jwt"
+	"github.com/go-webauthn/webauthn/protocol"
+	"github.com/go-webauthn/webauthn/webauthn"
+	"github.com/google/uuid"
+)
+
+var (
+	ErrPasskeyChallengeNotFound  = errors.New("passkey challenge not found")
+	ErrPasskeyNotFound           = errors.New("passkey not found")
+	ErrPasskeyAlreadyRegistered  = errors.New("passkey is already registered")
+	ErrPasskeyVerificationFailed = errors.New("passkey verification failed")
+)
+
+// Passkey is a WebAuthn credential registered by the user.
+type Passkey struct {
+	UserId     uuid.UUID
+	Credential webauthn.Credential
+	// Given by the user to tell passkeys apart
+	Name       string
+	CreatedAt  time.Time
+	LastUsedAt *time.Time
+}
+
+type PasskeyStorage interface {
+	FindByCredential(ctx context.Context, credentialId []byte) (*Passkey, bool, error)
+	List(ctx context.Context, userId uuid.UUID) ([]Passkey, error)
+	Store(ctx context.Context, passkey *Passkey) error
+	Remove(ctx context.Context, userId uuid.UUID, credentialId []byte) error
+}
+
+// PasskeySessionStorage keeps the ceremony session with its challenge until the ceremony is finished.
+// The key is user id for registration and sign in session id for signing in.
+type PasskeySessionStorage interface {
+	Store(ctx context.Context, key uuid.UUID, session *webauthn.SessionData) error
+	// Consume returns the session and removes it in one step,
+	// so the same challenge can't be consumed twice.
+	Consume(ctx context.Context, key uuid.UUID) (*webauthn.SessionData, bool, error)
+}
+
+type PasskeyConfig struct {
+	// Relying party, allowed origins, user verification and timeouts
+	WebAuthn *webauthn.WebAuthn
+}
+
+// PasskeyCreationOptions are passed to navigator.credentials.create().
+type PasskeyCreationOptions struct {
+	Challenge   []byte
+	RPID        string
+	RPName      string
+	UserHandle  []byte
+	UserName    string
+	DisplayName string
+	Algs        []int
+	// Already registered credentials of the user
+	ExcludeCredentials      [][]byte
+	Timeout                 time.Duration
+	RequireUserVerification bool
+}
+
+// PasskeyRequestOptions are passed to navigator.credentials.get().
+// Credentials are discoverable, so the list of allowed credentials is empty.
+type PasskeyRequestOptions struct {
+	SessionId               uuid.UUID
+	Challenge               []byte
+	RPID                    string
+	Timeout                 time.Duration
+	RequireUserVerification bool
+}
+
+type PasskeyRegistrationData struct {
+	Name              string
+	ClientDataJSON    []byte
+	AttestationObject []byte
+}
+
+type PasskeySignInData struct {
+	SessionId         uuid.UUID
+	CredentialId      []byte
+	ClientDataJSON    []byte
+	AuthenticatorData []byte
+	Signature         []byte
+	// The user id the credential was created with
+	UserHandle []byte
+}
+
+type PasskeyService struct {
+	config        *PasskeyConfig
+	storage       PasskeyStorage
+	registrations PasskeySessionStorage
+	signIns       PasskeySessionStorage
+	deviceStorage DeviceStorage
+	logins        LoginRecorder
+	suspensions   SuspensionChecker
+	users         userservice.UserServiceClient
+	accessConf    *jwt.Config
+	refreshConf   *jwt.Config
+}
+
+func NewPasskeyService(config *PasskeyConfig, storage PasskeyStorage, registrations, signIns PasskeySessionStorage,
+	accessConf, refreshConf *jwt.Config, deviceStorage DeviceStorage, logins LoginRecorder,
+	suspensions SuspensionChecker, users userservice.UserServiceClient) *PasskeyService {
+	return &PasskeyService{
+		config:        config,
+		storage:       storage,
+		registrations: registrations,
+		signIns:       signIns,
+		deviceStorage: deviceStorage,
+		logins:        logins,
+		suspensions:   suspensions,
+		users:         users,
+		accessConf:    accessConf,
+		refreshConf:   refreshConf,
+	}
+}
+
+// BeginRegistration is called from a signed in session.
+func (s *PasskeyService) BeginRegistration(ctx context.Context, access jwt.Token) (*PasskeyCreationOptions, error) {
+	userId, claims, err := parseAccess(s.accessConf, access)
+	if err != nil {
+		return nil, err
+	}
+
+	passkeys, err := s.storage.List(ctx, userId)
+	if err != nil {
+		return nil, fmt.Errorf("passkeys listing failed: %s", err)
+	}
+	user := &passkeyUser{
+		id:          userId,
+		name:        fmt.Sprint(claims[jwt.ClaimUsername]),
+		displayName: fmt.Sprint(claims[jwt.ClaimName]),
+		credentials: passkeyCredentials(passkeys),
+	}
+
+	creation, session, err := s.config.WebAuthn.BeginRegistration(user,
+		webauthn.WithExclusions(webauthn.Credentials(user.credentials).CredentialDescriptors()))
+	if err != nil {
+		return nil, fmt.Errorf("passkey registration beginning failed: %s", err)
+	}
+	if err := s.registrations.Store(ctx, userId, session); err != nil {
+		return nil, fmt.Errorf("passkey session storing failed: %s", err)
+	}
+
+	opts := creation.Response
+	algs := make([]int, 0, len(opts.Parameters))
+	for _, param := range opts.Parameters {
+		algs = append(algs, int(param.Algorithm))
+	}
+	exclude := make([][]byte, 0, len(opts.CredentialExcludeList))
+	for _, cred := range opts.CredentialExcludeList {
+		exclude = append(exclude, cred.CredentialID)
+	}
+	return &PasskeyCreationOptions{
+		Challenge:               opts.Challenge,
+		RPID:                    opts.RelyingParty.ID,
+		RPName:                  opts.RelyingParty.Name,
+		UserHandle:              user.WebAuthnID(),
+		UserName:                user.WebAuthnName(),
+		DisplayName:             user.WebAuthnDisplayName(),
+		Algs:                    algs,
+		ExcludeCredentials:      exclude,
+		Timeout:                 time.Duration(opts.Timeout) * time.Millisecond,
+		RequireUserVerification: opts.AuthenticatorSelection.UserVerification == protocol.VerificationRequired,
+	}, nil
+}
+
+func (s *PasskeyService) FinishRegistration(ctx context.Context, access jwt.Token,
+	data *PasskeyRegistrationData) (*Passkey, error) {
+	userId, _, err := parseAccess(s.accessConf, access)
+	if err != nil {
+		return nil, err
+	}
+
+	session, err := s.consumeSession(ctx, s.registrations, userId)
+	if err != nil {
+		return nil, err
+	}
+
+	parsed, err := parseRegistration(data)
+	if err != nil {
+		log.Printf("passkey registration parsing failed: %s", err)
+		return nil, ErrPasskeyVerificationFailed
+	}
+	cred, err := s.config.WebAuthn.CreateCredential(&passkeyUser{id: userId}, *session, parsed)
+	if err != nil {
+		log.Printf("passkey registration verification failed: %s", describeWebAuthnError(err))
+		return nil, ErrPasskeyVerificationFailed
+	}
+
+	_, ok, err := s.storage.FindByCredential(ctx, cred.ID)
+	if err != nil {
+		return nil, fmt.Errorf("passkey finding failed: %s", err)
+	}
+	if ok {
+		return nil, ErrPasskeyAlreadyRegistered
+	}
+
+	passkey := &Passkey{
+		UserId:     userId,
+		Credential: *cred,
+		Name:       data.Name,
+		CreatedAt:  nowUTC(),
+	}
+	if err := s.storage.Store(ctx, passkey); err != nil {
+		return nil, fmt.Errorf("passkey storing failed: %s", err)
+	}
+	return passkey, nil
+}
+
+func (s *PasskeyService) List(ctx context.Context, access jwt.Token) ([]Passkey, error) {
+	userId, _, err := parseAccess(s.accessConf, access)
+	if err != nil {
+		return nil, err
+	}
+
+	passkeys, err := s.storage.List(ctx, userId)
+	if err != nil {
+		return nil, fmt.Errorf("passkeys listing failed: %s", err)
+	}
+	return passkeys, nil
+}
+
+func (s *PasskeyService) Remove(ctx context.Context, access jwt.Token, credentialId []byte) error {
+	userId, _, err := parseAccess(s.accessConf, access)
+	if err != nil {
+		return err
+	}
+
+	passkey, ok, err := s.storage.FindByCredential(ctx, credentialId)
+	if err != nil {
+		return fmt.Errorf("passkey finding failed: %s", err)
+	}
+	// Passkeys of other users are indistinguishable from absent ones
+	if !ok || passkey.UserId != userId {
+		return ErrPasskeyNotFound
+	}
+
+	if err := s.storage.Remove(ctx, userId, credentialId); err != nil {
+		return fmt.Errorf("passkey removal failed: %s", err)
+	}
+	return nil
+}
+
+func (s *PasskeyService) BeginSignIn(ctx context.Context) (*PasskeyRequestOptions, error) {
+	assertion, session, err := s.config.WebAuthn.BeginDiscoverableLogin()
+	if err != nil {
+		return nil, fmt.Errorf("passkey sign in beginning failed: %s", err)
+	}
+
+	sessionId := uuid.New()
+	if err := s.signIns.Store(ctx, sessionId, session); err != nil {
+		return nil, fmt.Errorf("passkey session storing failed: %s", err)
+	}
+
+	opts := assertion.Response
+	return &PasskeyRequestOptions{
+		SessionId:               sessionId,
+		Challenge:               opts.Challenge,
+		RPID:                    opts.RelyingPartyID,
+		Timeout:                 time.Duration(opts.Timeout) * time.Millisecond,
+		RequireUserVerification: opts.UserVerification == protocol.VerificationRequired,
+	}, nil
+}
+
+// SignIn doesn't ask for the cloud password.
+// A passkey with user verification is already the second factor.
+func (s *PasskeyService) SignIn(ctx context.Context, data *PasskeySignInData, device *DeviceInfo,
+	client *ClientInfo) (jwt.Pair, error) {
+	session, err := s.consumeSession(ctx, s.signIns, data.SessionId)
+	if err != nil {
+		return jwt.Pair{}, err
+	}
+
+	parsed, err := parseSignIn(data)
+	if err != nil {
+		log.Printf("passkey assertion parsing failed: %s", err)
+		return jwt.Pair{}, ErrPasskeyVerificationFailed
+	}
+
+	var passkey *Passkey
+	var findErr error
+	findUser := func(rawID, userHandle []byte) (webauthn.User, error) {
+		found, ok, err := s.storage.FindByCredential(ctx, rawID)
+		if err != nil {
+			findErr = fmt.Errorf("passkey finding failed: %s", err)
+			return nil, findErr
+		}
+		if !ok {
+			findErr = ErrPasskeyNotFound
+			return nil, findErr
+		}
+		passkey = found
+		// The user handle is compared with the user id by the library
+		return &passkeyUser{
+			id:          found.UserId,
+			credentials: []webauthn.Credential{found.Credential},
+		}, nil
+	}
+	cred, err := s.config.WebAuthn.ValidateDiscoverableLogin(findUser, *session, parsed)
+	if err != nil {
+		if findErr != nil {
+			return jwt.Pair{}, findErr
+		}
+		log.Printf("passkey assertion verification failed: %s", describeWebAuthnError(err))
+		return jwt.Pair{}, ErrPasskeyVerificationFailed
+	}
+	if cred.Authenticator.CloneWarning {
+		log.Printf("passkey signature counter went backwards, the authenticator may be cloned")
+		return jwt.Pair{}, ErrPasskeyVerificationFailed
+	}
+
+	if err := s.suspensions.CheckSuspended(ctx, passkey.UserId); err != nil {
+		return jwt.Pair{}, err
+	}
+
+	// The name and the username may have changed since the passkey was registered
+	user, err := s.fetchUser(ctx, passkey.UserId)
+	if err != nil {
+		return jwt.Pair{}, err
+	}
+
+	now := nowUTC()
+	passkey.Credential = *cred
+	passkey.LastUsedAt = &now
+	if err := s.storage.Store(ctx, passkey); err != nil {
+		return jwt.Pair{}, fmt.Errorf("passkey storing failed: %s", err)
+	}
+
+	claims := jwt.Claims{
+		jwt.ClaimSub:      passkey.UserId,
+		jwt.ClaimName:     user.GetName(),
+		jwt.ClaimUsername: user.GetUserName(),
+	}
+	pair, err := generatePair(s.accessConf, s.refreshConf, claims)
+	if err != nil {
+		return jwt.Pair{}, err
+	}
+
+	if err := recordSignIn(ctx, s.logins, s.deviceStorage, passkey.UserId, device, client); err != nil {
+		return jwt.Pair{}, err
+	}
+
+	return pair, nil
+}
+
+// consumeSession removes the session so that its challenge can't be replayed even if verification fails.
+func (s *PasskeyService) consumeSession(ctx context.Context, sessions PasskeySessionStorage,
+	key uuid.UUID) (*webauthn.SessionData, error) {
+	session, ok, err := sessions.Consume(ctx, key)
+	if err != nil {
+		return nil, fmt.Errorf("passkey session consuming failed: %s", err)
+	}
+	if !ok {
+		return nil, ErrPasskeyChallengeNotFound
+	}
+	return session, nil
+}
+
+func (s *PasskeyService) fetchUser(ctx context.Context, userId uuid.UUID) (*userservice.UserResponse, error) {
+	user, err := s.users.GetUser(ctx, &userservice.UserRequest{
+		UserId: &userservice.UUID{Value: userId.String()},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("user gRPC call error: %s", err)
+	}
+	if user.Status != userservice.UserResponseStatus_SUCCESS {
+		if user.Status == userservice.UserResponseStatus_FAILED {
+			return nil, errors.New("unknown gRPC GetUser() error")
+		}
+		if user.Status == userservice.UserResponseStatus_NOT_FOUND {
+			return nil, ErrUserNotFound
+		}
+		return nil, fmt.Errorf("unexpected user service status: %v", user.Status)
+	}
+	return user, nil
+}
+
+// passkeyUser is the user as WebAuthn sees it. The user handle is the user id.
+type passkeyUser struct {
+	id          uuid.UUID
+	name        string
+	displayName string
+	credentials []webauthn.Credential
+}
+
+func (u *passkeyUser) WebAuthnID() []byte {
+	return u.id[:]
+}
+
+func (u *passkeyUser) WebAuthnName() string {
+	return u.name
+}
+
+func (u *passkeyUser) WebAuthnDisplayName() string {
+	return u.displayName
+}
+
+func (u *passkeyUser) WebAuthnCredentials() []webauthn.Credential {
+	return u.credentials
+}
+
+func passkeyCredentials(passkeys []Passkey) []webauthn.Credential {
+	creds := make([]webauthn.Credential, 0, len(passkeys))
+	for _, passkey := range passkeys {
+		creds = append(creds, passkey.Credential)
+	}
+	return creds
+}
+
+// parseRegistration parses the response of navigator.credentials.create().
+// The credential id isn't sent separately, so it is taken from the attestation object.
+func parseRegistration(data *PasskeyRegistrationData) (*protocol.ParsedCredentialCreationData, error) {
+	resp := protocol.AuthenticatorAttestationResponse{
+		AuthenticatorResponse: protocol.AuthenticatorResponse{
+			ClientDataJSON: data.ClientDataJSON,
+		},
+		AttestationObject: data.AttestationObject,
+	}
+	attestation, err := resp.Parse()
+	if err != nil {
+		return nil, err
+	}
+
+	credentialId := attestation.AttestationObject.AuthData.AttData.CredentialID
+	return protocol.CredentialCreationResponse{
+		PublicKeyCredential: publicKeyCredential(credentialId),
+		AttestationResponse: resp,
+	}.Parse()
+}
+
+// parseSignIn parses the response of navigator.credentials.get().
+func parseSignIn(data *PasskeySignInData) (*protocol.ParsedCredentialAssertionData, error) {
+	return protocol.CredentialAssertionResponse{
+		PublicKeyCredential: publicKeyCredential(data.CredentialId),
+		AssertionResponse: protocol.AuthenticatorAssertionResponse{
+			AuthenticatorResponse: protocol.AuthenticatorResponse{
+				ClientDataJSON: data.ClientDataJSON,
+			},
+			AuthenticatorData: data.AuthenticatorData,
+			Signature:         data.Signature,
+			UserHandle:        data.UserHandle,
+		},
+	}.Parse()
+}
+
+func publicKeyCredential(id []byte) protocol.PublicKeyCredential {
+	return protocol.PublicKeyCredential{
+		Credential: protocol.Credential{
+			ID:   base64.RawURLEncoding.EncodeToString(id),
+			Type: string(protocol.PublicKeyCredentialType),
+		},
+		RawID: id,
+	}
+}
+
+// describeWebAuthnError adds the details that protocol errors keep out of Error().
+func describeWebAuthnError(err error) string {
+	var protoErr *protocol.Error
+	if errors.As(err, &protoErr) {
+		return fmt.Sprintf("%s: %s %s", protoErr.Type, protoErr.Details, protoErr.DevInfo)
+	}
+	return err.Error()
+}
@@ -0,0 +1,354 @@
+package services
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"testing"
+
+	"github.com/chakchat/chakchat-backend/identity-service/internal/userservice"
+	"github.com/chakchat/chakchat-backend/shared/go/jwt"
+	"github.com/fxamacker/cbor/v2"
+	"github.com/go-webauthn/webauthn/protocol"
+	"github.com/go-webauthn/webauthn/webauthn"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testOrigin = "https://chakchat.example.com"
+
+// The user is renamed after the passkey registration
+var (
+	testPasskeyUserName = "New Name"
+	testPasskeyUsername = "new_username"
+)
+
+func Test_PasskeyRegisterAndSignIn(t *testing.T) {
+	// Arrange
+	accessConf := testJWTConfig("access")
+	userId := uuid.New()
+	access, err := jwt.Generate(accessConf, jwt.Claims{
+		jwt.ClaimSub:      userId,
+		jwt.ClaimName:     "Name",
+		jwt.ClaimUsername: "username",
+	})
+	require.NoError(t, err)
+
+	service, _ := newTestPasskeyService(accessConf, suspensionCheckerFake{})
+	authenticator := newSoftwareAuthenticator(t)
+
+	// Act
+	creation, err := service.BeginRegistration(context.Background(), access)
+	require.NoError(t, err)
+	passkey, err := service.FinishRegistration(context.Background(), access,
+		authenticator.create(t, creation, "Laptop"))
+	require.NoError(t, err)
+
+	request, err := service.BeginSignIn(context.Background())
+	require.NoError(t, err)
+	pair, err := service.SignIn(context.Background(),
+		authenticator.get(t, request.RPID, request.SessionId, request.Challenge), nil, nil)
+	require.NoError(t, err)
+
+	// Assert
+	assert.Equal(t, authenticator.credentialId, passkey.Credential.ID)
+	claims, err := jwt.Parse(accessConf, pair.Access)
+	require.NoError(t, err)
+	assert.Equal(t, userId.String(), claims[jwt.ClaimSub])
+	assert.Equal(t, testPasskeyUserName, claims[jwt.ClaimName])
+	assert.Equal(t, testPasskeyUsername, claims[jwt.ClaimUsername])
+
+	passkeys, err := service.List(context.Background(), access)
+	require.NoError(t, err)
+	require.Len(t, passkeys, 1)
+	assert.Equal(t, "Laptop", passkeys[0].Name)
+	assert.Equal(t, authenticator.signCount, passkeys[0].Credential.Authenticator.SignCount)
+	assert.NotNil(t, passkeys[0].LastUsedAt)
+}
+
+func Test_PasskeySignInFails(t *testing.T) {
+	accessConf := testJWTConfig("access")
+	userId := uuid.New()
+	access, err := jwt.Generate(accessConf, jwt.Claims{
+		jwt.ClaimSub: userId,
+	})
+	require.NoError(t, err)
+
+	register := func(t *testing.T, suspensions SuspensionChecker) (*PasskeyService, *softwareAuthenticator) {
+		service, _ := newTestPasskeyService(accessConf, suspensions)
+		authenticator := newSoftwareAuthenticator(t)
+		creation, err := service.BeginRegistration(context.Background(), access)
+		require.NoError(t, err)
+		_, err = service.FinishRegistration(context.Background(), access,
+			authenticator.create(t, creation, ""))
+		require.NoError(t, err)
+		return service, authenticator
+	}
+
+	t.Run("WrongChallenge", func(t *testing.T) {
+		service, authenticator := register(t, suspensionCheckerFake{})
+		request, err := service.BeginSignIn(context.Background())
+		require.NoError(t, err)
+
+		wrong, err := protocol.CreateChallenge()
+		require.NoError(t, err)
+		_, err = service.SignIn(context.Background(),
+			authenticator.get(t, request.RPID, request.SessionId, wrong), nil, nil)
+
+		assert.Equal(t, ErrPasskeyVerificationFailed, err)
+	})
+
+	t.Run("BadSignature", func(t *testing.T) {
+		service, authenticator := register(t, suspensionCheckerFake{})
+		request, err := service.BeginSignIn(context.Background())
+		require.NoError(t, err)
+
+		data := authenticator.get(t, request.RPID, request.SessionId, request.Challenge)
+		data.Signature[len(data.Signature)-1] ^= 0xff
+		_, err = service.SignIn(context.Background(), data, nil, nil)
+
+		assert.Equal(t, ErrPasskeyVerificationFailed, err)
+	})
+
+	t.Run("ChallengeReplay", func(t *testing.T) {
+		service, authenticator := register(t, suspensionCheckerFake{})
+		request, err := service.BeginSignIn(context.Background())
+		require.NoError(t, err)
+
+		data := authenticator.get(t, request.RPID, request.SessionId, request.Challenge)
+		_, err = service.SignIn(context.Background(), data, nil, nil)
+		require.NoError(t, err)
+		_, err = service.SignIn(context.Background(), data, nil, nil)
+
+		assert.Equal(t, ErrPasskeyChallengeNotFound, err)
+	})
+
+	t.Run("UnknownCredential", func(t *testing.T) {
+		service, _ := register(t, suspensionCheckerFake{})
+		request, err := service.BeginSignIn(context.Background())
+		require.NoError(t, err)
+
+		other := newSoftwareAuthenticator(t)
+		other.userHandle = userId[:]
+		_, err = service.SignIn(context.Background(),
+			other.get(t, request.RPID, request.SessionId, request.Challenge), nil, nil)
+
+		assert.Equal(t, ErrPasskeyNotFound, err)
+	})
+
+	t.Run("ClonedAuthenticator", func(t *testing.T) {
+		service, authenticator := register(t, suspensionCheckerFake{})
+		request, err := service.BeginSignIn(context.Background())
+		require.NoError(t, err)
+		_, err = service.SignIn(context.Background(),
+			authenticator.get(t, request.RPID, request.SessionId, request.Challenge), nil, nil)
+		require.NoError(t, err)
+
+		// The clone has the same key but doesn't know about the previous signature
+		authenticator.signCount--
+		request, err = service.BeginSignIn(context.Background())
+		require.NoError(t, err)
+		_, err = service.SignIn(context.Background(),
+			authenticator.get(t, request.RPID, request.SessionId, request.Challenge), nil, nil)
+
+		assert.Equal(t, ErrPasskeyVerificationFailed, err)
+	})
+
+	t.Run("SuspendedUser", func(t *testing.T) {
+		service, authenticator := register(t, suspensionCheckerFake{suspended: map[uuid.UUID]bool{userId: true}})
+		request, err := service.BeginSignIn(context.Background())
+		require.NoError(t, err)
+
+		_, err = service.SignIn(context.Background(),
+			authenticator.get(t, request.RPID, request.SessionId, request.Challenge), nil, nil)
+
+		assert.Equal(t, ErrUserSuspended, err)
+	})
+}
+
+func Test_PasskeyRemove(t *testing.T) {
+	// Arrange
+	accessConf := testJWTConfig("access")
+	access, err := jwt.Generate(accessConf, jwt.Claims{jwt.ClaimSub: uuid.New()})
+	require.NoError(t, err)
+	otherAccess, err := jwt.Generate(accessConf, jwt.Claims{jwt.ClaimSub: uuid.New()})
+	require.NoError(t, err)
+
+	service, passkeys := newTestPasskeyService(accessConf, suspensionCheckerFake{})
+	authenticator := newSoftwareAuthenticator(t)
+	creation, err := service.BeginRegistration(context.Background(), access)
+	require.NoError(t, err)
+	_, err = service.FinishRegistration(context.Background(), access,
+		authenticator.create(t, creation, ""))
+	require.NoError(t, err)
+
+	// Act & Assert
+	assert.Equal(t, ErrPasskeyNotFound, service.Remove(context.Background(), otherAccess, authenticator.credentialId),
+		"passkeys of other users mustn't be removed")
+	require.NoError(t, service.Remove(context.Background(), access, authenticator.credentialId))
+	assert.Empty(t, passkeys.m)
+}
+
+func newTestPasskeyService(accessConf *jwt.Config, suspensions SuspensionChecker) (*PasskeyService, *passkeyStorageFake) {
+	wa, err := webauthn.New(&webauthn.Config{
+		RPID:          "chakchat.example.com",
+		RPDisplayName: "ChakChat",
+		RPOrigins:     []string{testOrigin},
+		AuthenticatorSelection: protocol.AuthenticatorSelection{
+			ResidentKey:      protocol.ResidentKeyRequirementRequired,
+			UserVerification: protocol.VerificationRequired,
+		},
+	})
+	if err != nil {
+		panic(err)
+	}
+	passkeys := &passkeyStorageFake{m: map[string]Passkey{}}
+	service := NewPasskeyService(&PasskeyConfig{WebAuthn: wa}, passkeys,
+		&passkeySessionStorageFake{m: map[uuid.UUID]webauthn.SessionData{}},
+		&passkeySessionStorageFake{m: map[uuid.UUID]webauthn.SessionData{}},
+		accessConf, testJWTConfig("refresh"), deviceStorageFake{}, loginRecorderFake{}, suspensions,
+		userServiceMock{resp: &userservice.UserResponse{
+			Status:   userservice.UserResponseStatus_SUCCESS,
+			Name:     &testPasskeyUserName,
+			UserName: &testPasskeyUsername,
+		}})
+	return service, passkeys
+}
+
+// softwareAuthenticator is a platform authenticator with an ES256 key that always verifies the user.
+type softwareAuthenticator struct {
+	key          *ecdsa.PrivateKey
+	credentialId []byte
+	signCount    uint32
+	// Saved on registration and returned on every sign in as discoverable credentials do
+	userHandle []byte
+}
+
+func newSoftwareAuthenticator(t *testing.T) *softwareAuthenticator {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	credentialId := make([]byte, 16)
+	_, err = rand.Read(credentialId)
+	require.NoError(t, err)
+	return &softwareAuthenticator{
+		key:          key,
+		credentialId: credentialId,
+	}
+}
+
+func (a *softwareAuthenticator) create(t *testing.T, opts *PasskeyCreationOptions, name string) *PasskeyRegistrationData {
+	a.userHandle = opts.UserHandle
+	coseKeyRaw, err := cbor.Marshal(map[int]any{
+		1:  2,  // kty: EC2
+		3:  -7, // alg: ES256
+		-1: 1,  // crv: P-256
+		-2: a.key.X.FillBytes(make([]byte, 32)),
+		-3: a.key.Y.FillBytes(make([]byte, 32)),
+	})
+	require.NoError(t, err)
+
+	authData := a.authData(opts.RPID, 0x01|0x04|0x40)
+	authData = append(authData, make([]byte, 16)...) // aaguid
+	authData = binary.BigEndian.AppendUint16(authData, uint16(len(a.credentialId)))
+	authData = append(authData, a.credentialId...)
+	authData = append(authData, coseKeyRaw...)
+
+	attestation, err := cbor.Marshal(map[string]any{
+		"fmt":      "none",
+		"attStmt":  map[string]any{},
+		"authData": authData,
+	})
+	require.NoError(t, err)
+
+	return &PasskeyRegistrationData{
+		Name:              name,
+		ClientDataJSON:    clientDataJSON(t, "webauthn.create", opts.Challenge),
+		AttestationObject: attestation,
+	}
+}
+
+func (a *softwareAuthenticator) get(t *testing.T, rpId string, sessionId uuid.UUID, challenge []byte) *PasskeySignInData {
+	a.signCount++
+	authData := a.authData(rpId, 0x01|0x04)
+	clientData := clientDataJSON(t, "webauthn.get", challenge)
+
+	clientDataHash := sha256.Sum256(clientData)
+	signed := sha256.Sum256(append(authData, clientDataHash[:]...))
+	signature, err := ecdsa.SignASN1(rand.Reader, a.key, signed[:])
+	require.NoError(t, err)
+
+	return &PasskeySignInData{
+		SessionId:         sessionId,
+		CredentialId:      a.credentialId,
+		ClientDataJSON:    clientData,
+		AuthenticatorData: authData,
+		Signature:         signature,
+		UserHandle:        a.userHandle,
+	}
+}
+
+func (a *softwareAuthenticator) authData(rpId string, flags byte) []byte {
+	rpIdHash := sha256.Sum256([]byte(rpId))
+	data := append(rpIdHash[:], flags)
+	return binary.BigEndian.AppendUint32(data, a.signCount)
+}
+
+func clientDataJSON(t *testing.T, typ string, challenge []byte) []byte {
+	data, err := json.Marshal(map[string]string{
+		"type":      typ,
+		"challenge": base64.RawURLEncoding.EncodeToString(challenge),
+		"origin":    testOrigin,
+	})
+	require.NoError(t, err)
+	return data
+}
+
+type passkeyStorageFake struct {
+	m map[string]Passkey
+}
+
+func (s *passkeyStorageFake) FindByCredential(_ context.Context, credentialId []byte) (*Passkey, bool, error) {
+	passkey, ok := s.m[string(credentialId)]
+	return &passkey, ok, nil
+}
+
+func (s *passkeyStorageFake) List(_ context.Context, userId uuid.UUID) ([]Passkey, error) {
+	var passkeys []Passkey
+	for _, passkey := range s.m {
+		if passkey.UserId == userId {
+			passkeys = append(passkeys, passkey)
+		}
+	}
+	return passkeys, nil
+}
+
+func (s *passkeyStorageFake) Store(_ context.Context, passkey *Passkey) error {
+	s.m[string(passkey.Credential.ID)] = *passkey
+	return nil
+}
+
+func (s *passkeyStorageFake) Remove(_ context.Context, _ uuid.UUID, credentialId []byte) error {
+	delete(s.m, string(credentialId))
+	return nil
+}
+
+type passkeySessionStorageFake struct {
+	m map[uuid.UUID]webauthn.SessionData
+}
+
+func (s *passkeySessionStorageFake) Store(_ context.Context, key uuid.UUID, session *webauthn.SessionData) error {
+	s.m[key] = *session
+	return nil
+}
+
+func (s *passkeySessionStorageFake) Consume(_ context.Context, key uuid.UUID) (*webauthn.SessionData, bool, error) {
+	session, ok := s.m[key]
+	delete(s.m, key)
+	return &session, ok, nil
+}
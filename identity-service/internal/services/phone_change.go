@@ -0,0 +1,187 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/chakchat/chakchat-backend/identity-service/internal/otp"
+	"github.com/chakchat/chakchat-backend/identity-service/internal/userservice"
+	"github.com/chakchat/chakchat-backend/shared/go/jwt"
+	"github.com/google/uuid"
+)
+
+var (
+	ErrPhoneTaken              = errors.New("phone is already taken")
+	ErrPhoneChangeNotRequested = errors.New("phone change is not requested")
+)
+
+// PhoneChangeMeta is kept per user until the new phone is confirmed.
+type PhoneChangeMeta struct {
+	UserId      uuid.UUID
+	LastRequest time.Time
+	// New phone the code is sent to
+	Phone string
+	Code  string
+}
+
+type PhoneChangeMetaStorage interface {
+	Find(ctx context.Context, userId uuid.UUID) (*PhoneChangeMeta, bool, error)
+	Store(ctx context.Context, meta *PhoneChangeMeta) error
+	Remove(ctx context.Context, userId uuid.UUID) error
+}
+
+type PhoneChangeNotifier interface {
+	PhoneChanged(ctx context.Context, userId uuid.UUID, phone string, receivers []uuid.UUID) error
+}
+
+type PhoneChangeService struct {
+	config *CodeConfig
+
+	codes      otp.CodeSender
+	storage    PhoneChangeMetaStorage
+	users      userservice.UserServiceClient
+	notifier   PhoneChangeNotifier
+	accessConf *jwt.Config
+}
+
+func NewPhoneChangeService(config *CodeConfig, codes otp.CodeSender, storage PhoneChangeMetaStorage,
+	users userservice.UserServiceClient, notifier PhoneChangeNotifier, accessConf *jwt.Config) *PhoneChangeService {
+	return &PhoneChangeService{
+		config:     config,
+		codes:      codes,
+		storage:    storage,
+		users:      users,
+		notifier:   notifier,
+		accessConf: accessConf,
+	}
+}
+
+// SendCode sends a verification code to the new phone of the current user.
+func (s *PhoneChangeService) SendCode(ctx context.Context, access jwt.Token, phone string) error {
+	userId, _, err := parseAccess(s.accessConf, access)
+	if err != nil {
+		return err
+	}
+
+	prevMeta, ok, err := s.storage.Find(ctx, userId)
+	if err != nil {
+		return fmt.Errorf("finding PhoneChangeMeta error: %s", err)
+	}
+	if ok && prevMeta.LastRequest.Add(s.config.SendFrequency).Compare(nowUTC()) > 0 {
+		return ErrSendCodeFreqExceeded
+	}
+
+	if err := s.validatePhoneFree(ctx, phone); err != nil {
+		return err
+	}
+
+	meta := &PhoneChangeMeta{
+		UserId:      userId,
+		LastRequest: nowUTC(),
+		Phone:       phone,
+		Code:        genCode(),
+	}
+
+	// UserId is not set intentionally.
+	// The code must reach the new phone, not the devices the user is signed in on.
+	to := &otp.Recipient{
+		Phone: phone,
+	}
+	if err := s.codes.SendCode(ctx, to, renderPhoneChangeMessage(meta.Code)); err != nil {
+		return fmt.Errorf("send code error: %s", err)
+	}
+
+	if err := s.storage.Store(ctx, meta); err != nil {
+		return fmt.Errorf("storage error: %s", err)
+	}
+	return nil
+}
+
+// Confirm changes the phone of the current user if the code is correct
+// and notifies users who can see the phone.
+func (s *PhoneChangeService) Confirm(ctx context.Context, access jwt.Token, code string) error {
+	userId, _, err := parseAccess(s.accessConf, access)
+	if err != nil {
+		return err
+	}
+
+	meta, ok, err := s.storage.Find(ctx, userId)
+	if err != nil {
+		return fmt.Errorf("finding PhoneChangeMeta error: %s", err)
+	}
+	if !ok {
+		return ErrPhoneChangeNotRequested
+	}
+	if meta.Code != code {
+		return ErrWrongCode
+	}
+
+	resp, err := s.users.UpdatePhone(ctx, &userservice.UpdatePhoneRequest{
+		UserId:      &userservice.UUID{Value: userId.String()},
+		PhoneNumber: meta.Phone,
+	})
+	if err != nil {
+		return fmt.Errorf("user gRPC call error: %s", err)
+	}
+
+	switch resp.Status {
+	case userservice.UpdatePhoneStatus_UPDATED:
+	case userservice.UpdatePhoneStatus_PHONE_TAKEN:
+		return ErrPhoneTaken
+	case userservice.UpdatePhoneStatus_USER_NOT_FOUND:
+		return ErrUserNotFound
+	case userservice.UpdatePhoneStatus_PHONE_VALIDATION_FAILED:
+		return errors.New("user service phone validation failed")
+	case userservice.UpdatePhoneStatus_UPDATE_FAILED:
+		return errors.New("unknown gRPC UpdatePhone() error")
+	default:
+		return fmt.Errorf("unexpected user service status: %v", resp.Status)
+	}
+
+	if err := s.storage.Remove(ctx, userId); err != nil {
+		return fmt.Errorf("removing PhoneChangeMeta failed: %s", err)
+	}
+
+	// The user's other devices should get the new phone as well
+	receivers := make([]uuid.UUID, 0, len(resp.NotifyUserIds)+1)
+	receivers = append(receivers, userId)
+	for _, id := range resp.NotifyUserIds {
+		receiver, err := uuid.Parse(id.Value)
+		if err != nil {
+			return fmt.Errorf("parsing notified user id failed: %s", err)
+		}
+		receivers = append(receivers, receiver)
+	}
+
+	// The phone is already changed, so notification failure shouldn't fail the request
+	if err := s.notifier.PhoneChanged(ctx, userId, meta.Phone, receivers); err != nil {
+		log.Printf("notifying about phone change failed: %s", err)
+	}
+	return nil
+}
+
+func (s *PhoneChangeService) validatePhoneFree(ctx context.Context, phone string) error {
+	user, err := s.users.GetUser(ctx, &userservice.UserRequest{
+		PhoneNumber: phone,
+	})
+	if err != nil {
+		return fmt.Errorf("user gRPC call error: %s", err)
+	}
+
+	switch user.Status {
+	case userservice.UserResponseStatus_NOT_FOUND:
+		return nil
+	case userservice.UserResponseStatus_SUCCESS:
+		return ErrPhoneTaken
+	case userservice.UserResponseStatus_FAILED:
+		return errors.New("unknown gRPC GetUser() error")
+	}
+	return fmt.Errorf("unexpected user service status: %v", user.Status)
+}
+
+func renderPhoneChangeMessage(code string) string {
+	return "Do not tell this code to anybody. Your code for changing chakchat phone number is " + code
+}
@@ -0,0 +1,109 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/chakchat/chakchat-backend/identity-service/internal/userservice"
+	"github.com/chakchat/chakchat-backend/shared/go/jwt"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_PhoneChange(t *testing.T) {
+	// Arrange
+	accessConf := testJWTConfig("access")
+	userId := uuid.New()
+	contactId := uuid.New()
+	access, err := jwt.Generate(accessConf, jwt.Claims{
+		jwt.ClaimSub:      userId,
+		jwt.ClaimName:     "Name",
+		jwt.ClaimUsername: "username",
+	})
+	require.NoError(t, err)
+
+	users := &userServiceMock{
+		resp: &userservice.UserResponse{
+			Status: userservice.UserResponseStatus_NOT_FOUND,
+		},
+		updateResp: &userservice.UpdatePhoneResponse{
+			Status:        userservice.UpdatePhoneStatus_UPDATED,
+			NotifyUserIds: []*userservice.UUID{{Value: contactId.String()}},
+		},
+	}
+	storage := &phoneChangeStorageFake{m: map[uuid.UUID]PhoneChangeMeta{}}
+	notifier := &phoneChangeNotifierFake{}
+	service := NewPhoneChangeService(&CodeConfig{SendFrequency: time.Minute}, smsStub{}, storage,
+		users, notifier, accessConf)
+
+	// Act & Assert
+	assert.Equal(t, ErrPhoneChangeNotRequested, service.Confirm(context.Background(), access, "123456"))
+
+	require.NoError(t, service.SendCode(context.Background(), access, "79998887766"))
+	assert.Equal(t, ErrSendCodeFreqExceeded, service.SendCode(context.Background(), access, "79998887766"))
+
+	code := storage.m[userId].Code
+	assert.Equal(t, ErrWrongCode, service.Confirm(context.Background(), access, "wrong"))
+
+	require.NoError(t, service.Confirm(context.Background(), access, code))
+	assert.Equal(t, "79998887766", notifier.phone)
+	assert.ElementsMatch(t, []uuid.UUID{userId, contactId}, notifier.receivers)
+
+	_, ok := storage.m[userId]
+	assert.False(t, ok, "phone change meta must be removed")
+}
+
+func Test_PhoneChange_PhoneTaken(t *testing.T) {
+	// Arrange
+	accessConf := testJWTConfig("access")
+	access, err := jwt.Generate(accessConf, jwt.Claims{
+		jwt.ClaimSub: uuid.New(),
+	})
+	require.NoError(t, err)
+
+	users := &userServiceMock{
+		resp: &userservice.UserResponse{
+			Status: userservice.UserResponseStatus_SUCCESS,
+		},
+	}
+	service := NewPhoneChangeService(&CodeConfig{SendFrequency: time.Minute}, smsStub{},
+		&phoneChangeStorageFake{m: map[uuid.UUID]PhoneChangeMeta{}}, users, &phoneChangeNotifierFake{}, accessConf)
+
+	// Act
+	err = service.SendCode(context.Background(), access, "79998887766")
+
+	// Assert
+	assert.Equal(t, ErrPhoneTaken, err)
+}
+
+type phoneChangeStorageFake struct {
+	m map[uuid.UUID]PhoneChangeMeta
+}
+
+func (s *phoneChangeStorageFake) Find(_ context.Context, userId uuid.UUID) (*PhoneChangeMeta, bool, error) {
+	meta, ok := s.m[userId]
+	return &meta, ok, nil
+}
+
+func (s *phoneChangeStorageFake) Store(_ context.Context, meta *PhoneChangeMeta) error {
+	s.m[meta.UserId] = *meta
+	return nil
+}
+
+func (s *phoneChangeStorageFake) Remove(_ context.Context, userId uuid.UUID) error {
+	delete(s.m, userId)
+	return nil
+}
+
+type phoneChangeNotifierFake struct {
+	phone     string
+	receivers []uuid.UUID
+}
+
+func (n *phoneChangeNotifierFake) PhoneChanged(_ context.Context, _ uuid.UUID, phone string, receivers []uuid.UUID) error {
+	n.phone = phone
+	n.receivers = receivers
+	return nil
+}
@@ -0,0 +1,160 @@
+package services
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/chakchat/chakchat-backend/shared/go/jwt"
+	"github.com/google/uuid"
+)
+
+var (
+	ErrQRLoginTokenNotFound    = errors.New("qr login token not found")
+	ErrQRLoginNotConfirmed     = errors.New("qr login is not confirmed yet")
+	ErrQRLoginAlreadyConfirmed = errors.New("qr login is already confirmed")
+)
+
+// QRLoginMeta is created by a new device (e.g. desktop) and its token is shown as a QR code.
+// Already signed in device scans it and confirms the login on behalf of its user.
+// The token is public, so only the new device knows PollSecret needed to sign in.
+type QRLoginMeta struct {
+	Token      uuid.UUID
+	PollSecret uuid.UUID
+	ExpiresAt  time.Time
+	Confirmed  bool
+
+	UserId   uuid.UUID
+	Name     string
+	Username string
+}
+
+type QRLoginStorage interface {
+	Find(ctx context.Context, token uuid.UUID) (*QRLoginMeta, bool, error)
+	// Stored meta must expire at ExpiresAt
+	Store(ctx context.Context, meta *QRLoginMeta) error
+	// Confirm atomically sets the confirming user of the meta if it is not confirmed yet.
+	// It returns false if there is no such meta or it is already confirmed, so only one caller confirms it.
+	Confirm(ctx context.Context, meta *QRLoginMeta) (bool, error)
+	// Consume atomically removes the meta if it is confirmed and has such poll secret.
+	// It returns false if there is no such meta, so only one caller gets it.
+	Consume(ctx context.Context, token, pollSecret uuid.UUID) (*QRLoginMeta, bool, error)
+}
+
+type QRLoginConfig struct {
+	Lifetime time.Duration
+}
+
+type QRLoginService struct {
+	config        *QRLoginConfig
+	storage       QRLoginStorage
+	deviceStorage DeviceStorage
+	logins        LoginRecorder
+	suspensions   SuspensionChecker
+	accessConf    *jwt.Config
+	refreshConf   *jwt.Config
+}
+
+func NewQRLoginService(config *QRLoginConfig, storage QRLoginStorage, accessConf, refreshConf *jwt.Config,
+	deviceStorage DeviceStorage, logins LoginRecorder, suspensions SuspensionChecker) *QRLoginService {
+	return &QRLoginService{
+		config:        config,
+		storage:       storage,
+		deviceStorage: deviceStorage,
+		logins:        logins,
+		suspensions:   suspensions,
+		accessConf:    accessConf,
+		refreshConf:   refreshConf,
+	}
+}
+
+func (s *QRLoginService) CreateToken(ctx context.Context) (*QRLoginMeta, error) {
+	meta := &QRLoginMeta{
+		Token:      uuid.New(),
+		PollSecret: uuid.New(),
+		ExpiresAt:  nowUTC().Add(s.config.Lifetime),
+	}
+	if err := s.storage.Store(ctx, meta); err != nil {
+		return nil, fmt.Errorf("qr login meta storing failed: %s", err)
+	}
+	return meta, nil
+}
+
+func (s *QRLoginService) Confirm(ctx context.Context, access jwt.Token, token uuid.UUID) error {
+	userId, claims, err := parseAccess(s.accessConf, access)
+	if err != nil {
+		return err
+	}
+
+	meta, ok, err := s.storage.Find(ctx, token)
+	if err != nil {
+		return fmt.Errorf("qr login meta finding failed: %s", err)
+	}
+	if !ok {
+		return ErrQRLoginTokenNotFound
+	}
+	if meta.Confirmed {
+		return ErrQRLoginAlreadyConfirmed
+	}
+
+	meta.UserId = userId
+	meta.Name, _ = claims[jwt.ClaimName].(string)
+	meta.Username, _ = claims[jwt.ClaimUsername].(string)
+
+	ok, err = s.storage.Confirm(ctx, meta)
+	if err != nil {
+		return fmt.Errorf("qr login meta confirming failed: %s", err)
+	}
+	if !ok {
+		// Another device has confirmed the token in the meantime
+		return ErrQRLoginAlreadyConfirmed
+	}
+	return nil
+}
+
+// SignIn is polled by the new device until the login is confirmed.
+// Token can be exchanged only once and only with the poll secret returned on its creation.
+func (s *QRLoginService) SignIn(ctx context.Context, token, pollSecret uuid.UUID,
+	device *DeviceInfo, client *ClientInfo) (jwt.Pair, error) {
+	meta, ok, err := s.storage.Find(ctx, token)
+	if err != nil {
+		return jwt.Pair{}, fmt.Errorf("qr login meta finding failed: %s", err)
+	}
+	// Wrong secret is reported as not found not to reveal the token exists
+	if !ok || subtle.ConstantTimeCompare(meta.PollSecret[:], pollSecret[:]) != 1 {
+		return jwt.Pair{}, ErrQRLoginTokenNotFound
+	}
+	if !meta.Confirmed {
+		return jwt.Pair{}, ErrQRLoginNotConfirmed
+	}
+	if err := s.suspensions.CheckSuspended(ctx, meta.UserId); err != nil {
+		return jwt.Pair{}, err
+	}
+
+	meta, ok, err = s.storage.Consume(ctx, token, pollSecret)
+	if err != nil {
+		return jwt.Pair{}, fmt.Errorf("qr login meta consuming failed: %s", err)
+	}
+	if !ok {
+		// Another request has already exchanged the token
+		return jwt.Pair{}, ErrQRLoginTokenNotFound
+	}
+
+	claims := jwt.Claims{
+		jwt.ClaimSub:      meta.UserId,
+		jwt.ClaimName:     meta.Name,
+		jwt.ClaimUsername: meta.Username,
+	}
+	pair, err := generatePair(s.accessConf, s.refreshConf, claims)
+	if err != nil {
+		return jwt.Pair{}, err
+	}
+
+	if err := recordSignIn(ctx, s.logins, s.deviceStorage, meta.UserId, device, client); err != nil {
+		return jwt.Pair{}, err
+	}
+
+	return pair, nil
+}
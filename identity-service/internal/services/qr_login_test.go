@@ -0,0 +1,161 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/chakchat/chakchat-backend/shared/go/jwt"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_QRLogin(t *testing.T) {
+	// Arrange
+	accessConf := testJWTConfig("access")
+	refreshConf := testJWTConfig("refresh")
+	service := NewQRLoginService(&QRLoginConfig{Lifetime: time.Minute}, &qrLoginStorageFake{},
+		accessConf, refreshConf, &deviceStorageFake{}, loginRecorderFake{}, suspensionCheckerFake{})
+
+	userId := uuid.New()
+	access, err := jwt.Generate(accessConf, jwt.Claims{
+		jwt.ClaimSub:      userId,
+		jwt.ClaimName:     "Name",
+		jwt.ClaimUsername: "username",
+	})
+	require.NoError(t, err)
+
+	meta, err := service.CreateToken(context.Background())
+	require.NoError(t, err)
+
+	// Act & Assert
+	_, err = service.SignIn(context.Background(), meta.Token, meta.PollSecret, nil, nil)
+	assert.Equal(t, ErrQRLoginNotConfirmed, err)
+
+	require.NoError(t, service.Confirm(context.Background(), access, meta.Token))
+	assert.Equal(t, ErrQRLoginAlreadyConfirmed, service.Confirm(context.Background(), access, meta.Token))
+
+	// The one who scanned the QR code doesn't know the poll secret
+	_, err = service.SignIn(context.Background(), meta.Token, uuid.New(), nil, nil)
+	assert.Equal(t, ErrQRLoginTokenNotFound, err)
+
+	pair, err := service.SignIn(context.Background(), meta.Token, meta.PollSecret, nil, nil)
+	require.NoError(t, err)
+	claims, err := jwt.Parse(accessConf, pair.Access)
+	require.NoError(t, err)
+	assert.Equal(t, userId.String(), claims[jwt.ClaimSub])
+
+	// Token is exchanged only once
+	_, err = service.SignIn(context.Background(), meta.Token, meta.PollSecret, nil, nil)
+	assert.Equal(t, ErrQRLoginTokenNotFound, err)
+}
+
+func Test_QRLogin_ConfirmedOnlyOnce(t *testing.T) {
+	// Arrange
+	accessConf := testJWTConfig("access")
+	storage := &staleQRLoginStorage{}
+	service := NewQRLoginService(&QRLoginConfig{Lifetime: time.Minute}, storage,
+		accessConf, testJWTConfig("refresh"), &deviceStorageFake{}, loginRecorderFake{}, suspensionCheckerFake{})
+
+	first, second := uuid.New(), uuid.New()
+	firstAccess, err := jwt.Generate(accessConf, jwt.Claims{jwt.ClaimSub: first})
+	require.NoError(t, err)
+	secondAccess, err := jwt.Generate(accessConf, jwt.Claims{jwt.ClaimSub: second})
+	require.NoError(t, err)
+
+	meta, err := service.CreateToken(context.Background())
+	require.NoError(t, err)
+
+	// Act
+	require.NoError(t, service.Confirm(context.Background(), firstAccess, meta.Token))
+	err = service.Confirm(context.Background(), secondAccess, meta.Token)
+
+	// Assert
+	assert.Equal(t, ErrQRLoginAlreadyConfirmed, err)
+	assert.Equal(t, first, storage.m[meta.Token].UserId)
+}
+
+// staleQRLoginStorage finds the meta as it was before confirmation like concurrent confirmations do
+type staleQRLoginStorage struct {
+	qrLoginStorageFake
+}
+
+func (s *staleQRLoginStorage) Find(ctx context.Context, token uuid.UUID) (*QRLoginMeta, bool, error) {
+	meta, ok, err := s.qrLoginStorageFake.Find(ctx, token)
+	meta.Confirmed = false
+	return meta, ok, err
+}
+
+func testJWTConfig(typ string) *jwt.Config {
+	return &jwt.Config{
+		SigningMethod: "HS512",
+		Lifetime:      time.Hour,
+		Issuer:        "identity_service",
+		Audience:      []string{"client"},
+		Type:          typ,
+		SymmetricKey:  []byte("I_DONT_WANNA_GEN_A_KEY_SO_I_PASTE_UUID_5ad9b4a7c1e84f0f9b4a8e0e2f6d0c11"),
+	}
+}
+
+type qrLoginStorageFake struct {
+	m map[uuid.UUID]QRLoginMeta
+}
+
+func (s *qrLoginStorageFake) Find(_ context.Context, token uuid.UUID) (*QRLoginMeta, bool, error) {
+	meta, ok := s.m[token]
+	return &meta, ok, nil
+}
+
+func (s *qrLoginStorageFake) Store(_ context.Context, meta *QRLoginMeta) error {
+	if s.m == nil {
+		s.m = map[uuid.UUID]QRLoginMeta{}
+	}
+	s.m[meta.Token] = *meta
+	return nil
+}
+
+func (s *qrLoginStorageFake) Confirm(_ context.Context, meta *QRLoginMeta) (bool, error) {
+	stored, ok := s.m[meta.Token]
+	if !ok || stored.Confirmed {
+		return false, nil
+	}
+	stored.Confirmed = true
+	stored.UserId = meta.UserId
+	stored.Name = meta.Name
+	stored.Username = meta.Username
+	s.m[meta.Token] = stored
+	return true, nil
+}
+
+func (s *qrLoginStorageFake) Consume(_ context.Context, token, pollSecret uuid.UUID) (*QRLoginMeta, bool, error) {
+	meta, ok := s.m[token]
+	if !ok || !meta.Confirmed || meta.PollSecret != pollSecret {
+		return nil, false, nil
+	}
+	delete(s.m, token)
+	return &meta, true, nil
+}
+
+type deviceStorageFake struct{}
+
+func (deviceStorageFake) Store(context.Context, uuid.UUID, *DeviceInfo) error { return nil }
+func (deviceStorageFake) Refresh(context.Context, uuid.UUID) error            { return nil }
+func (deviceStorageFake) Remove(context.Context, uuid.UUID) error             { return nil }
+
+type loginRecorderFake struct{}
+
+func (loginRecorderFake) Record(context.Context, uuid.UUID, string, *DeviceInfo, *ClientInfo) error {
+	return nil
+}
+
+type suspensionCheckerFake struct {
+	suspended map[uuid.UUID]bool
+}
+
+func (f suspensionCheckerFake) CheckSuspended(_ context.Context, userId uuid.UUID) error {
+	if f.suspended[userId] {
+		return ErrUserSuspended
+	}
+	return nil
+}
@@ -26,18 +26,23 @@ type RefreshService struct {
 	refreshConf   *jwt.Config
 	deviceStorage DeviceStorage
 	checker       RefreshTokenCheckInvalidator
+	logins        LoginRecorder
+	suspensions   SuspensionChecker
 }
 
-func NewRefreshService(checker RefreshTokenCheckInvalidator, accessConf, refreshConf *jwt.Config, deviceStorage DeviceStorage) *RefreshService {
+func NewRefreshService(checker RefreshTokenCheckInvalidator, accessConf, refreshConf *jwt.Config, deviceStorage DeviceStorage,
+	logins LoginRecorder, suspensions SuspensionChecker) *RefreshService {
 	return &RefreshService{
 		accessConf:    accessConf,
 		deviceStorage: deviceStorage,
 		refreshConf:   refreshConf,
 		checker:       checker,
+		logins:        logins,
+		suspensions:   suspensions,
 	}
 }
 
-func (s *RefreshService) Refresh(ctx context.Context, refresh jwt.Token) (jwt.Pair, error) {
+func (s *RefreshService) Refresh(ctx context.Context, refresh jwt.Token, client *ClientInfo) (jwt.Pair, error) {
 	if err := s.validate(ctx, refresh); err != nil {
 		return jwt.Pair{}, err
 	}
@@ -69,6 +74,10 @@ func (s *RefreshService) Refresh(ctx context.Context, refresh jwt.Token) (jwt.Pa
 	if err != nil {
 		return jwt.Pair{}, fmt.Errorf("failed to parse sub claim: %w", err)
 	}
+	// The refresh token is already invalidated, so the suspended user has to sign in again after unsuspension
+	if err := s.suspensions.CheckSuspended(ctx, userId); err != nil {
+		return jwt.Pair{}, err
+	}
 	err = s.deviceStorage.Refresh(ctx, userId)
 	if err != nil {
 		return jwt.Pair{}, fmt.Errorf("failed to store device token: %w", err)
@@ -82,6 +91,10 @@ func (s *RefreshService) Refresh(ctx context.Context, refresh jwt.Token) (jwt.Pa
 		return jwt.Pair{}, fmt.Errorf("refresh token generation failed: %s", err)
 	}
 
+	if err := s.logins.Record(ctx, userId, LoginEventRefresh, nil, client); err != nil {
+		return jwt.Pair{}, err
+	}
+
 	return pair, nil
 }
 
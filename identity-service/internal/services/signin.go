@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 
+	"github.com/chakchat/chakchat-backend/identity-service/internal/otp"
 	"github.com/chakchat/chakchat-backend/shared/go/jwt"
 	"github.com/google/uuid"
 )
@@ -19,8 +20,9 @@ type DeviceInfo struct {
 	DeviceToken string
 }
 
-type SignInMetaFindRemover interface {
+type SignInMetaStorage interface {
 	FindMeta(ctx context.Context, signInKey uuid.UUID) (*SignInMeta, bool, error)
+	Store(context.Context, *SignInMeta) error
 	Remove(ctx context.Context, signInKey uuid.UUID) error
 }
 
@@ -30,22 +32,34 @@ type DeviceStorage interface {
 	Remove(ctx context.Context, userID uuid.UUID) error
 }
 type SignInService struct {
-	storage       SignInMetaFindRemover
+	storage       SignInMetaStorage
 	deviceStorage DeviceStorage
+	passwords     CloudPasswordStorage
+	emails        otp.CodeSender
+	logins        LoginRecorder
+	suspensions   SuspensionChecker
 	accessConf    *jwt.Config
 	refreshConf   *jwt.Config
 }
 
-func NewSignInService(storage SignInMetaFindRemover, accessConf, refreshConf *jwt.Config, deviceStorage DeviceStorage) *SignInService {
+func NewSignInService(storage SignInMetaStorage, accessConf, refreshConf *jwt.Config, deviceStorage DeviceStorage,
+	passwords CloudPasswordStorage, emails otp.CodeSender, logins LoginRecorder, suspensions SuspensionChecker) *SignInService {
 	return &SignInService{
 		storage:       storage,
 		deviceStorage: deviceStorage,
+		passwords:     passwords,
+		emails:        emails,
+		logins:        logins,
+		suspensions:   suspensions,
 		accessConf:    accessConf,
 		refreshConf:   refreshConf,
 	}
 }
 
-func (s *SignInService) SignIn(ctx context.Context, signInKey uuid.UUID, code string, device *DeviceInfo) (jwt.Pair, error) {
+// SignIn returns ErrPasswordRequired if the user enabled two-step verification.
+// In this case the sign in key stays valid for SignInWithPassword.
+func (s *SignInService) SignIn(ctx context.Context, signInKey uuid.UUID, code string, device *DeviceInfo,
+	client *ClientInfo) (jwt.Pair, error) {
 	meta, ok, err := s.storage.FindMeta(ctx, signInKey)
 	if err != nil {
 		return jwt.Pair{}, fmt.Errorf("sign in metadata finding failed: %s", err)
@@ -57,28 +71,146 @@ func (s *SignInService) SignIn(ctx context.Context, signInKey uuid.UUID, code st
 		return jwt.Pair{}, ErrWrongCode
 	}
 
+	_, passwordSet, err := s.passwords.Find(ctx, meta.UserId)
+	if err != nil {
+		return jwt.Pair{}, fmt.Errorf("cloud password finding failed: %s", err)
+	}
+	if passwordSet {
+		meta.CodeVerified = true
+		if err := s.storage.Store(ctx, meta); err != nil {
+			return jwt.Pair{}, fmt.Errorf("sign in metadata storing failed: %s", err)
+		}
+		return jwt.Pair{}, ErrPasswordRequired
+	}
+
+	return s.complete(ctx, meta, device, client)
+}
+
+func (s *SignInService) SignInWithPassword(ctx context.Context, signInKey uuid.UUID, plainPassword string,
+	device *DeviceInfo, client *ClientInfo) (jwt.Pair, error) {
+	meta, err := s.findVerifiedMeta(ctx, signInKey)
+	if err != nil {
+		return jwt.Pair{}, err
+	}
+
+	pw, ok, err := s.passwords.Find(ctx, meta.UserId)
+	if err != nil {
+		return jwt.Pair{}, fmt.Errorf("cloud password finding failed: %s", err)
+	}
+	// If password was removed in the meantime the phone code is enough
+	if ok {
+		if err := checkPassword(pw, plainPassword); err != nil {
+			return jwt.Pair{}, err
+		}
+	}
+
+	return s.complete(ctx, meta, device, client)
+}
+
+// SendRecoveryCode sends a code to the verified recovery email.
+// The code allows to sign in without password. The password is removed then.
+func (s *SignInService) SendRecoveryCode(ctx context.Context, signInKey uuid.UUID) error {
+	meta, err := s.findVerifiedMeta(ctx, signInKey)
+	if err != nil {
+		return err
+	}
+
+	pw, ok, err := s.passwords.Find(ctx, meta.UserId)
+	if err != nil {
+		return fmt.Errorf("cloud password finding failed: %s", err)
+	}
+	if !ok {
+		return ErrPasswordNotSet
+	}
+	if pw.RecoveryEmail == "" || !pw.EmailVerified {
+		return ErrRecoveryEmailNotSet
+	}
+
+	meta.RecoveryCode = genCode()
+	to := &otp.Recipient{
+		UserId: meta.UserId,
+		Email:  pw.RecoveryEmail,
+	}
+	if err := s.emails.SendCode(ctx, to, renderRecoveryCodeMessage(meta.RecoveryCode)); err != nil {
+		return fmt.Errorf("send email code error: %s", err)
+	}
+
+	if err := s.storage.Store(ctx, meta); err != nil {
+		return fmt.Errorf("sign in metadata storing failed: %s", err)
+	}
+	return nil
+}
+
+func (s *SignInService) RecoverPassword(ctx context.Context, signInKey uuid.UUID, code string, device *DeviceInfo,
+	client *ClientInfo) (jwt.Pair, error) {
+	meta, err := s.findVerifiedMeta(ctx, signInKey)
+	if err != nil {
+		return jwt.Pair{}, err
+	}
+	if meta.RecoveryCode == "" || meta.RecoveryCode != code {
+		return jwt.Pair{}, ErrWrongCode
+	}
+
+	if err := s.passwords.Remove(ctx, meta.UserId); err != nil {
+		return jwt.Pair{}, fmt.Errorf("cloud password removal failed: %s", err)
+	}
+
+	return s.complete(ctx, meta, device, client)
+}
+
+func (s *SignInService) findVerifiedMeta(ctx context.Context, signInKey uuid.UUID) (*SignInMeta, error) {
+	meta, ok, err := s.storage.FindMeta(ctx, signInKey)
+	if err != nil {
+		return nil, fmt.Errorf("sign in metadata finding failed: %s", err)
+	}
+	if !ok {
+		return nil, ErrSignInKeyNotFound
+	}
+	if !meta.CodeVerified {
+		return nil, ErrPhoneNotVerified
+	}
+	return meta, nil
+}
+
+func (s *SignInService) complete(ctx context.Context, meta *SignInMeta, device *DeviceInfo, client *ClientInfo) (jwt.Pair, error) {
+	if err := s.suspensions.CheckSuspended(ctx, meta.UserId); err != nil {
+		return jwt.Pair{}, err
+	}
+
 	claims := jwt.Claims{
 		jwt.ClaimSub:      meta.UserId,
 		jwt.ClaimName:     meta.Name,
 		jwt.ClaimUsername: meta.Username,
 	}
-	var pair jwt.Pair
-	if pair.Access, err = jwt.Generate(s.accessConf, claims); err != nil {
-		return jwt.Pair{}, fmt.Errorf("access token generation failed: %s", err)
+	pair, err := generatePair(s.accessConf, s.refreshConf, claims)
+	if err != nil {
+		return jwt.Pair{}, err
 	}
-	if pair.Refresh, err = jwt.Generate(s.refreshConf, claims); err != nil {
-		return jwt.Pair{}, fmt.Errorf("refresh token generation failed: %s", err)
+
+	if err := recordSignIn(ctx, s.logins, s.deviceStorage, meta.UserId, device, client); err != nil {
+		return jwt.Pair{}, err
 	}
 
-	if err := s.storage.Remove(ctx, signInKey); err != nil {
+	// Removed last so that the user can retry with the same key if anything above fails
+	if err := s.storage.Remove(ctx, meta.SignInKey); err != nil {
 		return jwt.Pair{}, fmt.Errorf("sign in key removal failed: %s", err)
 	}
 
-	if device != nil {
-		if err := s.deviceStorage.Store(ctx, meta.UserId, device); err != nil {
-			return jwt.Pair{}, fmt.Errorf("failed to store device info: %s", err)
-		}
-	}
+	return pair, nil
+}
 
+func generatePair(accessConf, refreshConf *jwt.Config, claims jwt.Claims) (jwt.Pair, error) {
+	var pair jwt.Pair
+	var err error
+	if pair.Access, err = jwt.Generate(accessConf, claims); err != nil {
+		return jwt.Pair{}, fmt.Errorf("access token generation failed: %s", err)
+	}
+	if pair.Refresh, err = jwt.Generate(refreshConf, claims); err != nil {
+		return jwt.Pair{}, fmt.Errorf("refresh token generation failed: %s", err)
+	}
 	return pair, nil
 }
+
+func renderRecoveryCodeMessage(code string) string {
+	return "Do not tell this code to anybody. Your code for chakchat password recovery is " + code
+}
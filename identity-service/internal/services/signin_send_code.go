@@ -8,7 +8,7 @@ import (
 	"fmt"
 	"time"
 
-	"github.com/chakchat/chakchat-backend/identity-service/internal/sms"
+	"github.com/chakchat/chakchat-backend/identity-service/internal/otp"
 	"github.com/chakchat/chakchat-backend/identity-service/internal/userservice"
 	"github.com/google/uuid"
 )
@@ -31,6 +31,13 @@ type SignInMeta struct {
 	UserId   uuid.UUID
 	Name     string
 	Username string
+
+	// Set when the code is correct but the cloud password is required
+	CodeVerified bool
+	// Code sent to the recovery email if the user forgot the cloud password
+	RecoveryCode string
+	// Set if the code was requested on the signed in devices instead of SMS
+	ViaDevices bool
 }
 
 type SignInMetaFindStorer interface {
@@ -45,22 +52,24 @@ type CodeConfig struct {
 type SignInSendCodeService struct {
 	config *CodeConfig
 
-	sms     sms.SmsSender
+	codes   otp.CodeSender
 	storage SignInMetaFindStorer
 	users   userservice.UserServiceClient
 }
 
-func NewSignInSendCodeService(config *CodeConfig, sms sms.SmsSender, storage SignInMetaFindStorer, users userservice.UserServiceClient) *SignInSendCodeService {
+func NewSignInSendCodeService(config *CodeConfig, codes otp.CodeSender, storage SignInMetaFindStorer, users userservice.UserServiceClient) *SignInSendCodeService {
 	return &SignInSendCodeService{
 		config:  config,
-		sms:     sms,
+		codes:   codes,
 		storage: storage,
 		users:   users,
 	}
 }
 
-func (s *SignInSendCodeService) SendCode(ctx context.Context, phone string) (signInKey uuid.UUID, err error) {
-	if err := s.validateSendFreq(ctx, phone); err != nil {
+// SendCode sends the code via SMS.
+// If viaDevices is set the code is sent to the devices the user is signed in on if there are any.
+func (s *SignInSendCodeService) SendCode(ctx context.Context, phone string, viaDevices bool) (signInKey uuid.UUID, err error) {
+	if err := s.validateSendFreq(ctx, phone, viaDevices); err != nil {
 		return uuid.Nil, err
 	}
 
@@ -77,11 +86,16 @@ func (s *SignInSendCodeService) SendCode(ctx context.Context, phone string) (sig
 		Username:    *user.UserName,
 		Name:        *user.Name,
 		Code:        genCode(),
+		ViaDevices:  viaDevices,
 	}
 
-	sms := renderCodeSMS(meta.Code)
-	if _, err := s.sms.SendSms(ctx, phone, sms); err != nil {
-		return uuid.Nil, fmt.Errorf("send SMS error: %s", err)
+	to := &otp.Recipient{
+		Phone:      phone,
+		UserId:     meta.UserId,
+		ViaDevices: viaDevices,
+	}
+	if err := s.codes.SendCode(ctx, to, renderCodeMessage(meta.Code)); err != nil {
+		return uuid.Nil, fmt.Errorf("send code error: %s", err)
 	}
 
 	if err := s.storage.Store(ctx, &meta); err != nil {
@@ -91,12 +105,16 @@ func (s *SignInSendCodeService) SendCode(ctx context.Context, phone string) (sig
 	return meta.SignInKey, err
 }
 
-func (s *SignInSendCodeService) validateSendFreq(ctx context.Context, phone string) error {
+func (s *SignInSendCodeService) validateSendFreq(ctx context.Context, phone string, viaDevices bool) error {
 	prevMeta, ok, err := s.storage.FindMetaByPhone(ctx, phone)
 
 	if err != nil {
 		return fmt.Errorf("finding SignInMeta error: %s", err)
 	}
+	// The user may have lost the signed in devices, so the code can be resent via SMS right away.
+	if ok && prevMeta.ViaDevices && !viaDevices {
+		return nil
+	}
 	if ok && prevMeta.LastRequest.Add(s.config.SendFrequency).Compare(nowUTC()) > 0 {
 		return ErrSendCodeFreqExceeded
 	}
@@ -125,7 +143,7 @@ func (s *SignInSendCodeService) fetchUser(ctx context.Context, phone string) (*u
 	return user, nil
 }
 
-func renderCodeSMS(code string) string {
+func renderCodeMessage(code string) string {
 	return "Do not tell this code to anybody. Your code for chakchat signing in is " + code
 }
 
@@ -5,9 +5,9 @@ import (
 	"testing"
 	"time"
 
+	"github.com/chakchat/chakchat-backend/identity-service/internal/otp"
 	"github.com/chakchat/chakchat-backend/identity-service/internal/userservice"
 	"github.com/google/uuid"
-	smsaero_golang "github.com/smsaero/smsaero_golang/smsaero"
 	"github.com/stretchr/testify/assert"
 	"google.golang.org/grpc"
 )
@@ -33,7 +33,7 @@ func Test_Success(t *testing.T) {
 	sender := NewSignInSendCodeService(&config, smsSender, &metaStorage, userService)
 
 	// Act
-	signInKey, err := sender.SendCode(context.Background(), "+79998887766")
+	signInKey, err := sender.SendCode(context.Background(), "+79998887766", false)
 
 	// Assert
 	assert.NoError(t, err)
@@ -74,7 +74,7 @@ func Test_Error(t *testing.T) {
 
 	t.Run("FrequencyExceeded", func(t *testing.T) {
 		// Act
-		_, err := sender.SendCode(context.Background(), "+7999888776")
+		_, err := sender.SendCode(context.Background(), "+7999888776", false)
 
 		// Assert
 		if assert.Error(t, err) {
@@ -92,7 +92,7 @@ func Test_Error(t *testing.T) {
 		}
 
 		// Act
-		_, err := sender.SendCode(context.Background(), "+79888888888")
+		_, err := sender.SendCode(context.Background(), "+79888888888", false)
 
 		// Assert
 		if assert.Error(t, err) {
@@ -101,8 +101,38 @@ func Test_Error(t *testing.T) {
 	})
 }
 
+func Test_ResendViaSMS(t *testing.T) {
+	userService := userServiceMock{
+		resp: &userservice.UserResponse{
+			Status:   userservice.UserResponseStatus_SUCCESS,
+			Name:     new(string),
+			UserName: new(string),
+			UserId: &userservice.UUID{
+				Value: "6c056fb3-7efc-483a-9506-4336456ac79f",
+			},
+		},
+	}
+	config := &CodeConfig{
+		SendFrequency: 1 * time.Minute,
+	}
+	sender := NewSignInSendCodeService(config, smsStub{}, &metaStorageFake{}, userService)
+
+	_, err := sender.SendCode(context.Background(), "+79998887766", true)
+	assert.NoError(t, err)
+
+	// The devices may be lost, so the user doesn't have to wait
+	_, err = sender.SendCode(context.Background(), "+79998887766", false)
+	assert.NoError(t, err)
+
+	_, err = sender.SendCode(context.Background(), "+79998887766", false)
+	assert.Equal(t, ErrSendCodeFreqExceeded, err)
+}
+
 type userServiceMock struct {
-	resp *userservice.UserResponse
+	resp           *userservice.UserResponse
+	updateResp     *userservice.UpdatePhoneResponse
+	suspensionResp *userservice.GetSuspensionResponse
+	suspendStatus  userservice.UserResponseStatus
 }
 
 func (s userServiceMock) GetUser(ctx context.Context, in *userservice.UserRequest,
@@ -115,6 +145,36 @@ func (s userServiceMock) CreateUser(ctx context.Context, in *userservice.CreateU
 	panic("why do you use it here?")
 }
 
+func (s userServiceMock) GetName(ctx context.Context, in *userservice.GetNameRequest,
+	opts ...grpc.CallOption) (*userservice.GetNameResponse, error) {
+	panic("why do you use it here?")
+}
+
+func (s userServiceMock) UpdatePhone(ctx context.Context, in *userservice.UpdatePhoneRequest,
+	opts ...grpc.CallOption) (*userservice.UpdatePhoneResponse, error) {
+	return s.updateResp, nil
+}
+
+func (s userServiceMock) GetSuspension(ctx context.Context, in *userservice.GetSuspensionRequest,
+	opts ...grpc.CallOption) (*userservice.GetSuspensionResponse, error) {
+	return s.suspensionResp, nil
+}
+
+func (s userServiceMock) SuspendUser(ctx context.Context, in *userservice.SuspendUserRequest,
+	opts ...grpc.CallOption) (*userservice.SuspendUserResponse, error) {
+	return &userservice.SuspendUserResponse{Status: s.suspendStatus}, nil
+}
+
+func (s userServiceMock) UnsuspendUser(ctx context.Context, in *userservice.UnsuspendUserRequest,
+	opts ...grpc.CallOption) (*userservice.UnsuspendUserResponse, error) {
+	return &userservice.UnsuspendUserResponse{Status: s.suspendStatus}, nil
+}
+
+func (s userServiceMock) GetUserIDsByUsernames(ctx context.Context, in *userservice.GetUserIDsByUsernamesRequest,
+	opts ...grpc.CallOption) (*userservice.GetUserIDsByUsernamesResponse, error) {
+	panic("why do you use it here?")
+}
+
 type metaStorageFake struct {
 	s []*SignInMeta
 }
@@ -129,12 +189,19 @@ func (s *metaStorageFake) FindMetaByPhone(_ context.Context, phone string) (*Sig
 }
 
 func (s *metaStorageFake) Store(_ context.Context, meta *SignInMeta) error {
+	// Like the real storage, the phone points to the latest meta
+	for i := range s.s {
+		if s.s[i].Phone == meta.Phone {
+			s.s[i] = meta
+			return nil
+		}
+	}
 	s.s = append(s.s, meta)
 	return nil
 }
 
 type smsStub struct{}
 
-func (s smsStub) SendSms(_ context.Context, _ string, _ string) (*smsaero_golang.SendSms, error) {
-	return nil, nil
+func (s smsStub) SendCode(_ context.Context, _ *otp.Recipient, _ string) error {
+	return nil
 }
@@ -0,0 +1,110 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/chakchat/chakchat-backend/identity-service/internal/password"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_SignInWithCloudPassword(t *testing.T) {
+	// Arrange
+	userId := uuid.New()
+	signInKey := uuid.New()
+	metaStorage := &signInMetaStorageFake{m: map[uuid.UUID]SignInMeta{
+		signInKey: {SignInKey: signInKey, Code: "123456", UserId: userId},
+	}}
+
+	hash, err := password.Hash("cloud password")
+	require.NoError(t, err)
+	passwords := &cloudPasswordStorageFake{m: map[uuid.UUID]CloudPassword{
+		userId: {UserId: userId, Hash: hash},
+	}}
+
+	service := NewSignInService(metaStorage, testJWTConfig("access"), testJWTConfig("refresh"),
+		&deviceStorageFake{}, passwords, smsStub{}, loginRecorderFake{}, suspensionCheckerFake{})
+
+	// Act & Assert
+	_, err = service.SignInWithPassword(context.Background(), signInKey, "cloud password", nil, nil)
+	assert.Equal(t, ErrPhoneNotVerified, err)
+
+	_, err = service.SignIn(context.Background(), signInKey, "123456", nil, nil)
+	assert.Equal(t, ErrPasswordRequired, err)
+
+	_, err = service.SignInWithPassword(context.Background(), signInKey, "wrong password", nil, nil)
+	assert.Equal(t, ErrWrongPassword, err)
+
+	_, err = service.SignInWithPassword(context.Background(), signInKey, "cloud password", nil, nil)
+	assert.NoError(t, err)
+
+	_, ok := metaStorage.m[signInKey]
+	assert.False(t, ok, "sign in key must be removed")
+}
+
+func Test_SignIn_KeepsKeyIfRecordingFails(t *testing.T) {
+	// Arrange
+	signInKey := uuid.New()
+	metaStorage := &signInMetaStorageFake{m: map[uuid.UUID]SignInMeta{
+		signInKey: {SignInKey: signInKey, Code: "123456", UserId: uuid.New()},
+	}}
+	passwords := &cloudPasswordStorageFake{m: map[uuid.UUID]CloudPassword{}}
+
+	service := NewSignInService(metaStorage, testJWTConfig("access"), testJWTConfig("refresh"),
+		&deviceStorageFake{}, passwords, smsStub{}, failingLoginRecorder{}, suspensionCheckerFake{})
+
+	// Act
+	_, err := service.SignIn(context.Background(), signInKey, "123456", nil, nil)
+
+	// Assert
+	assert.Error(t, err)
+	_, ok := metaStorage.m[signInKey]
+	assert.True(t, ok, "sign in key must stay for a retry")
+}
+
+type failingLoginRecorder struct{}
+
+func (failingLoginRecorder) Record(context.Context, uuid.UUID, string, *DeviceInfo, *ClientInfo) error {
+	return errors.New("recording failed")
+}
+
+type signInMetaStorageFake struct {
+	m map[uuid.UUID]SignInMeta
+}
+
+func (s *signInMetaStorageFake) FindMeta(_ context.Context, signInKey uuid.UUID) (*SignInMeta, bool, error) {
+	meta, ok := s.m[signInKey]
+	return &meta, ok, nil
+}
+
+func (s *signInMetaStorageFake) Store(_ context.Context, meta *SignInMeta) error {
+	s.m[meta.SignInKey] = *meta
+	return nil
+}
+
+func (s *signInMetaStorageFake) Remove(_ context.Context, signInKey uuid.UUID) error {
+	delete(s.m, signInKey)
+	return nil
+}
+
+type cloudPasswordStorageFake struct {
+	m map[uuid.UUID]CloudPassword
+}
+
+func (s *cloudPasswordStorageFake) Find(_ context.Context, userId uuid.UUID) (*CloudPassword, bool, error) {
+	pw, ok := s.m[userId]
+	return &pw, ok, nil
+}
+
+func (s *cloudPasswordStorageFake) Store(_ context.Context, pw *CloudPassword) error {
+	s.m[pw.UserId] = *pw
+	return nil
+}
+
+func (s *cloudPasswordStorageFake) Remove(_ context.Context, userId uuid.UUID) error {
+	delete(s.m, userId)
+	return nil
+}
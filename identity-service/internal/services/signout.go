@@ -16,17 +16,20 @@ type SignOutService struct {
 	invalidator   RefreshTokenInvalidator
 	refreshConfig *jwt.Config
 	deviceStorage DeviceStorage
+	logins        LoginRecorder
 }
 
-func NewSignOutService(invalidator RefreshTokenInvalidator, refreshConf *jwt.Config, deviceStorage DeviceStorage) *SignOutService {
+func NewSignOutService(invalidator RefreshTokenInvalidator, refreshConf *jwt.Config, deviceStorage DeviceStorage,
+	logins LoginRecorder) *SignOutService {
 	return &SignOutService{
 		invalidator:   invalidator,
 		refreshConfig: refreshConf,
 		deviceStorage: deviceStorage,
+		logins:        logins,
 	}
 }
 
-func (s *SignOutService) SignOut(ctx context.Context, refresh jwt.Token) error {
+func (s *SignOutService) SignOut(ctx context.Context, refresh jwt.Token, client *ClientInfo) error {
 	// idk should I check smth?
 	if err := s.invalidator.Invalidate(ctx, refresh); err != nil {
 		return fmt.Errorf("token invalidation failed: %s", err)
@@ -42,6 +45,11 @@ func (s *SignOutService) SignOut(ctx context.Context, refresh jwt.Token) error {
 		return fmt.Errorf("failed to parse sub claim")
 	}
 
+	// Recorded before the device removal so the device type is still known
+	if err := s.logins.Record(ctx, userID, LoginEventSignOut, nil, client); err != nil {
+		return err
+	}
+
 	if err := s.deviceStorage.Remove(ctx, userID); err != nil {
 		return fmt.Errorf("failed to delete device token: %s", err)
 	}
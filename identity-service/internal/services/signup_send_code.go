@@ -6,7 +6,7 @@ import (
 	"fmt"
 	"time"
 
-	"github.com/chakchat/chakchat-backend/identity-service/internal/sms"
+	"github.com/chakchat/chakchat-backend/identity-service/internal/otp"
 	"github.com/chakchat/chakchat-backend/identity-service/internal/userservice"
 	"github.com/google/uuid"
 )
@@ -31,16 +31,16 @@ type SignUpMetaFindStorer interface {
 type SignUpSendCodeService struct {
 	config *CodeConfig
 
-	sms     sms.SmsSender
+	codes   otp.CodeSender
 	storage SignUpMetaFindStorer
 	users   userservice.UserServiceClient
 }
 
-func NewSignUpSendCodeService(config *CodeConfig, sms sms.SmsSender,
+func NewSignUpSendCodeService(config *CodeConfig, codes otp.CodeSender,
 	storage SignUpMetaFindStorer, users userservice.UserServiceClient) *SignUpSendCodeService {
 	return &SignUpSendCodeService{
 		config:  config,
-		sms:     sms,
+		codes:   codes,
 		storage: storage,
 		users:   users,
 	}
@@ -63,10 +63,11 @@ func (s *SignUpSendCodeService) SendCode(ctx context.Context, phone string) (sig
 		Verified:    false,
 	}
 
-	sms := renderCodeSMS(meta.Code)
-
-	if _, err := s.sms.SendSms(ctx, phone, sms); err != nil {
-		return uuid.Nil, fmt.Errorf("send SMS error: %s", err)
+	to := &otp.Recipient{
+		Phone: phone,
+	}
+	if err := s.codes.SendCode(ctx, to, renderCodeMessage(meta.Code)); err != nil {
+		return uuid.Nil, fmt.Errorf("send code error: %s", err)
 	}
 
 	if err := s.storage.Store(ctx, &meta); err != nil {
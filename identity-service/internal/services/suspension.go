@@ -0,0 +1,175 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/chakchat/chakchat-backend/identity-service/internal/userservice"
+	"github.com/google/uuid"
+)
+
+var ErrUserSuspended = errors.New("user is suspended")
+
+type Suspension struct {
+	Suspended bool
+	Reason    string
+	// Nil if the suspension doesn't expire
+	Until *time.Time
+}
+
+// Active reports whether the suspension is still in effect.
+func (s *Suspension) Active() bool {
+	if !s.Suspended {
+		return false
+	}
+	return s.Until == nil || s.Until.After(nowUTC())
+}
+
+// SuspensionCache keeps user-service answers so that every request doesn't cost a gRPC call.
+type SuspensionCache interface {
+	Find(ctx context.Context, userId uuid.UUID) (*Suspension, bool, error)
+	Store(ctx context.Context, userId uuid.UUID, suspension *Suspension) error
+}
+
+type SuspensionNotifier interface {
+	UserSuspended(ctx context.Context, userId uuid.UUID, suspension *Suspension) error
+}
+
+// SuspensionChecker is used by services that issue tokens.
+type SuspensionChecker interface {
+	// Returns ErrUserSuspended if the user is suspended.
+	CheckSuspended(ctx context.Context, userId uuid.UUID) error
+}
+
+type SuspensionService struct {
+	cache    SuspensionCache
+	users    userservice.UserServiceClient
+	notifier SuspensionNotifier
+}
+
+func NewSuspensionService(cache SuspensionCache, users userservice.UserServiceClient,
+	notifier SuspensionNotifier) *SuspensionService {
+	return &SuspensionService{
+		cache:    cache,
+		users:    users,
+		notifier: notifier,
+	}
+}
+
+func (s *SuspensionService) CheckSuspended(ctx context.Context, userId uuid.UUID) error {
+	suspension, ok, err := s.cache.Find(ctx, userId)
+	if err != nil {
+		return fmt.Errorf("finding cached suspension failed: %s", err)
+	}
+	if !ok {
+		suspension, err = s.fetch(ctx, userId)
+		if err != nil {
+			return err
+		}
+		if err := s.cache.Store(ctx, userId, suspension); err != nil {
+			return fmt.Errorf("caching suspension failed: %s", err)
+		}
+	}
+
+	if suspension.Active() {
+		return ErrUserSuspended
+	}
+	return nil
+}
+
+// Suspend is called by operators. until is nil for the suspension that doesn't expire.
+func (s *SuspensionService) Suspend(ctx context.Context, userId uuid.UUID, reason string, until *time.Time) error {
+	req := &userservice.SuspendUserRequest{
+		UserId: &userservice.UUID{Value: userId.String()},
+		Reason: reason,
+	}
+	if until != nil {
+		unix := until.Unix()
+		req.SuspendedUntil = &unix
+	}
+	resp, err := s.users.SuspendUser(ctx, req)
+	if err != nil {
+		return fmt.Errorf("user gRPC call error: %s", err)
+	}
+	if err := checkSuspensionStatus(resp.Status, "SuspendUser"); err != nil {
+		return err
+	}
+
+	suspension := &Suspension{
+		Suspended: true,
+		Reason:    reason,
+		Until:     until,
+	}
+	// The cache is overwritten so that the suspension takes effect immediately
+	if err := s.cache.Store(ctx, userId, suspension); err != nil {
+		return fmt.Errorf("caching suspension failed: %s", err)
+	}
+
+	// The user is already suspended, live connections are closed on the best effort basis
+	if err := s.notifier.UserSuspended(ctx, userId, suspension); err != nil {
+		log.Printf("notifying about user suspension failed: %s", err)
+	}
+	return nil
+}
+
+func (s *SuspensionService) Unsuspend(ctx context.Context, userId uuid.UUID) error {
+	resp, err := s.users.UnsuspendUser(ctx, &userservice.UnsuspendUserRequest{
+		UserId: &userservice.UUID{Value: userId.String()},
+	})
+	if err != nil {
+		return fmt.Errorf("user gRPC call error: %s", err)
+	}
+	if err := checkSuspensionStatus(resp.Status, "UnsuspendUser"); err != nil {
+		return err
+	}
+
+	if err := s.cache.Store(ctx, userId, &Suspension{}); err != nil {
+		return fmt.Errorf("caching suspension failed: %s", err)
+	}
+	return nil
+}
+
+func (s *SuspensionService) fetch(ctx context.Context, userId uuid.UUID) (*Suspension, error) {
+	resp, err := s.users.GetSuspension(ctx, &userservice.GetSuspensionRequest{
+		UserId: &userservice.UUID{Value: userId.String()},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("user gRPC call error: %s", err)
+	}
+
+	switch resp.Status {
+	case userservice.UserResponseStatus_SUCCESS:
+	case userservice.UserResponseStatus_NOT_FOUND:
+		// Nothing to suspend. Tokens of deleted users are not this service's concern.
+		return &Suspension{}, nil
+	case userservice.UserResponseStatus_FAILED:
+		return nil, errors.New("unknown gRPC GetSuspension() error")
+	default:
+		return nil, fmt.Errorf("unexpected user service status: %v", resp.Status)
+	}
+
+	suspension := &Suspension{
+		Suspended: resp.Suspended,
+		Reason:    resp.GetReason(),
+	}
+	if resp.SuspendedUntil != nil {
+		until := time.Unix(resp.GetSuspendedUntil(), 0).UTC()
+		suspension.Until = &until
+	}
+	return suspension, nil
+}
+
+func checkSuspensionStatus(status userservice.UserResponseStatus, method string) error {
+	switch status {
+	case userservice.UserResponseStatus_SUCCESS:
+		return nil
+	case userservice.UserResponseStatus_NOT_FOUND:
+		return ErrUserNotFound
+	case userservice.UserResponseStatus_FAILED:
+		return fmt.Errorf("unknown gRPC %s() error", method)
+	}
+	return fmt.Errorf("unexpected user service status: %v", status)
+}
@@ -0,0 +1,135 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/chakchat/chakchat-backend/identity-service/internal/userservice"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_SuspensionCheck(t *testing.T) {
+	t.Run("FetchedAndCached", func(t *testing.T) {
+		// Arrange
+		userId := uuid.New()
+		until := nowUTC().Add(time.Hour).Unix()
+		reason := "spam"
+		users := &userServiceMock{
+			suspensionResp: &userservice.GetSuspensionResponse{
+				Status:         userservice.UserResponseStatus_SUCCESS,
+				Suspended:      true,
+				Reason:         &reason,
+				SuspendedUntil: &until,
+			},
+		}
+		cache := &suspensionCacheFake{m: map[uuid.UUID]Suspension{}}
+		service := NewSuspensionService(cache, users, &suspensionNotifierFake{})
+
+		// Act
+		err := service.CheckSuspended(context.Background(), userId)
+
+		// Assert
+		assert.Equal(t, ErrUserSuspended, err)
+		cached, ok := cache.m[userId]
+		require.True(t, ok, "suspension must be cached")
+		assert.Equal(t, reason, cached.Reason)
+	})
+
+	t.Run("Expired", func(t *testing.T) {
+		// Arrange
+		userId := uuid.New()
+		until := nowUTC().Add(-time.Minute)
+		cache := &suspensionCacheFake{m: map[uuid.UUID]Suspension{
+			userId: {Suspended: true, Reason: "spam", Until: &until},
+		}}
+		service := NewSuspensionService(cache, &userServiceMock{}, &suspensionNotifierFake{})
+
+		// Act & Assert
+		assert.NoError(t, service.CheckSuspended(context.Background(), userId))
+	})
+
+	t.Run("DeletedUser", func(t *testing.T) {
+		// Arrange
+		users := &userServiceMock{
+			suspensionResp: &userservice.GetSuspensionResponse{
+				Status: userservice.UserResponseStatus_NOT_FOUND,
+			},
+		}
+		cache := &suspensionCacheFake{m: map[uuid.UUID]Suspension{}}
+		service := NewSuspensionService(cache, users, &suspensionNotifierFake{})
+
+		// Act & Assert
+		assert.NoError(t, service.CheckSuspended(context.Background(), uuid.New()))
+	})
+}
+
+func Test_SuspendUser(t *testing.T) {
+	// Arrange
+	userId := uuid.New()
+	users := &userServiceMock{
+		suspendStatus: userservice.UserResponseStatus_SUCCESS,
+	}
+	cache := &suspensionCacheFake{m: map[uuid.UUID]Suspension{
+		userId: {},
+	}}
+	notifier := &suspensionNotifierFake{}
+	service := NewSuspensionService(cache, users, notifier)
+
+	// Act & Assert
+	require.NoError(t, service.Suspend(context.Background(), userId, "spam", nil))
+	assert.Equal(t, ErrUserSuspended, service.CheckSuspended(context.Background(), userId),
+		"suspension must take effect before the cache expires")
+	assert.Equal(t, []uuid.UUID{userId}, notifier.suspended)
+
+	require.NoError(t, service.Unsuspend(context.Background(), userId))
+	assert.NoError(t, service.CheckSuspended(context.Background(), userId))
+
+	users.suspendStatus = userservice.UserResponseStatus_NOT_FOUND
+	assert.Equal(t, ErrUserNotFound, service.Suspend(context.Background(), uuid.New(), "spam", nil))
+}
+
+func Test_SignInSuspendedUser(t *testing.T) {
+	// Arrange
+	userId := uuid.New()
+	signInKey := uuid.New()
+	metaStorage := &signInMetaStorageFake{m: map[uuid.UUID]SignInMeta{
+		signInKey: {SignInKey: signInKey, Code: "123456", UserId: userId},
+	}}
+	passwords := &cloudPasswordStorageFake{m: map[uuid.UUID]CloudPassword{}}
+	suspensions := suspensionCheckerFake{suspended: map[uuid.UUID]bool{userId: true}}
+
+	service := NewSignInService(metaStorage, testJWTConfig("access"), testJWTConfig("refresh"),
+		&deviceStorageFake{}, passwords, smsStub{}, loginRecorderFake{}, suspensions)
+
+	// Act
+	_, err := service.SignIn(context.Background(), signInKey, "123456", nil, nil)
+
+	// Assert
+	assert.Equal(t, ErrUserSuspended, err)
+}
+
+type suspensionCacheFake struct {
+	m map[uuid.UUID]Suspension
+}
+
+func (s *suspensionCacheFake) Find(_ context.Context, userId uuid.UUID) (*Suspension, bool, error) {
+	suspension, ok := s.m[userId]
+	return &suspension, ok, nil
+}
+
+func (s *suspensionCacheFake) Store(_ context.Context, userId uuid.UUID, suspension *Suspension) error {
+	s.m[userId] = *suspension
+	return nil
+}
+
+type suspensionNotifierFake struct {
+	suspended []uuid.UUID
+}
+
+func (n *suspensionNotifierFake) UserSuspended(_ context.Context, userId uuid.UUID, _ *Suspension) error {
+	n.suspended = append(n.suspended, userId)
+	return nil
+}
@@ -0,0 +1,60 @@
+package storage
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/chakchat/chakchat-backend/identity-service/internal/services"
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+)
+
+const prefixCloudPassword = "CloudPassword:User:"
+
+// CloudPasswordStorage keeps passwords without expiration.
+// Redis must be configured with persistence.
+type CloudPasswordStorage struct {
+	client *redis.Client
+}
+
+func NewCloudPasswordStorage(client *redis.Client) *CloudPasswordStorage {
+	return &CloudPasswordStorage{
+		client: client,
+	}
+}
+
+func (s *CloudPasswordStorage) Find(ctx context.Context, userId uuid.UUID) (*services.CloudPassword, bool, error) {
+	res := s.client.Get(ctx, prefixCloudPassword+userId.String())
+	if err := res.Err(); err != nil {
+		if err == redis.Nil {
+			return nil, false, nil
+		}
+		return nil, false, fmt.Errorf("redis get cloud password failed: %s", err)
+	}
+
+	pw := new(services.CloudPassword)
+	if err := json.Unmarshal([]byte(res.Val()), pw); err != nil {
+		return nil, false, fmt.Errorf("unmarshalling cloud password failed: %s", err)
+	}
+	return pw, true, nil
+}
+
+func (s *CloudPasswordStorage) Store(ctx context.Context, pw *services.CloudPassword) error {
+	pwJson, err := json.Marshal(pw)
+	if err != nil {
+		return fmt.Errorf("cloud password json marshalling failed: %s", err)
+	}
+
+	if err := s.client.Set(ctx, prefixCloudPassword+pw.UserId.String(), pwJson, 0).Err(); err != nil {
+		return fmt.Errorf("redis set cloud password failed: %s", err)
+	}
+	return nil
+}
+
+func (s *CloudPasswordStorage) Remove(ctx context.Context, userId uuid.UUID) error {
+	if err := s.client.Del(ctx, prefixCloudPassword+userId.String()).Err(); err != nil {
+		return fmt.Errorf("redis delete cloud password failed: %s", err)
+	}
+	return nil
+}
@@ -70,6 +70,32 @@ func (s *DeviceStorage) Remove(ctx context.Context, userID uuid.UUID) error {
 	return nil
 }
 
+func (s *DeviceStorage) HasDevice(ctx context.Context, userID uuid.UUID) (bool, error) {
+	key := DeviceKeyPrefix + userID.String()
+	exists, err := s.client.Exists(ctx, key).Result()
+	if err != nil {
+		return false, fmt.Errorf("redis checking device existence failed: %s", err)
+	}
+	return exists == 1, nil
+}
+
+func (s *DeviceStorage) Find(ctx context.Context, userID uuid.UUID) (*services.DeviceInfo, bool, error) {
+	key := DeviceKeyPrefix + userID.String()
+	res := s.client.Get(ctx, key)
+	if err := res.Err(); err != nil {
+		if err == redis.Nil {
+			return nil, false, nil
+		}
+		return nil, false, fmt.Errorf("redis get device failed: %s", err)
+	}
+
+	info := new(services.DeviceInfo)
+	if err := json.Unmarshal([]byte(res.Val()), info); err != nil {
+		return nil, false, fmt.Errorf("unmarshalling device info failed: %s", err)
+	}
+	return info, true, nil
+}
+
 func (s *DeviceStorage) GetDeviceTokenByID(ctx context.Context, userID uuid.UUID) (*string, error) {
 	key := DeviceKeyPrefix + userID.String()
 
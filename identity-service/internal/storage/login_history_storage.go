@@ -0,0 +1,67 @@
+package storage
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/chakchat/chakchat-backend/identity-service/internal/services"
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+)
+
+const prefixLoginHistory = "LoginHistory:User:"
+
+type LoginHistoryConfig struct {
+	// Older events are dropped
+	MaxEvents int
+	// History expires if the user does nothing for this time
+	Lifetime time.Duration
+}
+
+type LoginHistoryStorage struct {
+	client *redis.Client
+	conf   *LoginHistoryConfig
+}
+
+func NewLoginHistoryStorage(conf *LoginHistoryConfig, client *redis.Client) *LoginHistoryStorage {
+	return &LoginHistoryStorage{
+		client: client,
+		conf:   conf,
+	}
+}
+
+func (s *LoginHistoryStorage) Add(ctx context.Context, userId uuid.UUID, event *services.LoginEvent) error {
+	eventJson, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("login event json marshalling failed: %s", err)
+	}
+
+	key := prefixLoginHistory + userId.String()
+	_, err = s.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.LPush(ctx, key, eventJson)
+		pipe.LTrim(ctx, key, 0, int64(s.conf.MaxEvents)-1)
+		pipe.Expire(ctx, key, s.conf.Lifetime)
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("redis adding login event failed: %s", err)
+	}
+	return nil
+}
+
+func (s *LoginHistoryStorage) List(ctx context.Context, userId uuid.UUID) ([]services.LoginEvent, error) {
+	res, err := s.client.LRange(ctx, prefixLoginHistory+userId.String(), 0, -1).Result()
+	if err != nil {
+		return nil, fmt.Errorf("redis login history range failed: %s", err)
+	}
+
+	events := make([]services.LoginEvent, len(res))
+	for i, raw := range res {
+		if err := json.Unmarshal([]byte(raw), &events[i]); err != nil {
+			return nil, fmt.Errorf("unmarshalling login event failed: %s", err)
+		}
+	}
+	return events, nil
+}
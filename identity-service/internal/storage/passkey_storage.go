@@ -0,0 +1,170 @@
+package storage
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/chakchat/chakchat-backend/identity-service/internal/services"
+	"github.com/go-webauthn/webauthn/webauthn"
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	// Hash of credential id to passkey
+	prefixPasskeyUser = "Passkey:User:"
+	// Credential id to user id index used on sign in
+	prefixPasskeyCredential = "Passkey:Credential:"
+
+	prefixPasskeyRegistration = "PasskeySession:Registration:"
+	prefixPasskeySignIn       = "PasskeySession:SignIn:"
+)
+
+// PasskeyStorage keeps passkeys without expiration.
+// Redis must be configured with persistence.
+type PasskeyStorage struct {
+	client *redis.Client
+}
+
+func NewPasskeyStorage(client *redis.Client) *PasskeyStorage {
+	return &PasskeyStorage{
+		client: client,
+	}
+}
+
+func (s *PasskeyStorage) FindByCredential(ctx context.Context, credentialId []byte) (*services.Passkey, bool, error) {
+	res := s.client.Get(ctx, prefixPasskeyCredential+encodeCredentialId(credentialId))
+	if err := res.Err(); err != nil {
+		if err == redis.Nil {
+			return nil, false, nil
+		}
+		return nil, false, fmt.Errorf("redis get passkey credential failed: %s", err)
+	}
+
+	hashRes := s.client.HGet(ctx, prefixPasskeyUser+res.Val(), encodeCredentialId(credentialId))
+	if err := hashRes.Err(); err != nil {
+		if err == redis.Nil {
+			return nil, false, nil
+		}
+		return nil, false, fmt.Errorf("redis hget passkey failed: %s", err)
+	}
+
+	passkey := new(services.Passkey)
+	if err := json.Unmarshal([]byte(hashRes.Val()), passkey); err != nil {
+		return nil, false, fmt.Errorf("unmarshalling passkey failed: %s", err)
+	}
+	return passkey, true, nil
+}
+
+func (s *PasskeyStorage) List(ctx context.Context, userId uuid.UUID) ([]services.Passkey, error) {
+	res := s.client.HVals(ctx, prefixPasskeyUser+userId.String())
+	if err := res.Err(); err != nil {
+		return nil, fmt.Errorf("redis hvals passkeys failed: %s", err)
+	}
+
+	passkeys := make([]services.Passkey, 0, len(res.Val()))
+	for _, val := range res.Val() {
+		var passkey services.Passkey
+		if err := json.Unmarshal([]byte(val), &passkey); err != nil {
+			return nil, fmt.Errorf("unmarshalling passkey failed: %s", err)
+		}
+		passkeys = append(passkeys, passkey)
+	}
+	return passkeys, nil
+}
+
+func (s *PasskeyStorage) Store(ctx context.Context, passkey *services.Passkey) error {
+	passkeyJson, err := json.Marshal(passkey)
+	if err != nil {
+		return fmt.Errorf("passkey json marshalling failed: %s", err)
+	}
+
+	credId := encodeCredentialId(passkey.Credential.ID)
+	_, err = s.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.HSet(ctx, prefixPasskeyUser+passkey.UserId.String(), credId, passkeyJson)
+		pipe.Set(ctx, prefixPasskeyCredential+credId, passkey.UserId.String(), 0)
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("redis set passkey failed: %s", err)
+	}
+	return nil
+}
+
+func (s *PasskeyStorage) Remove(ctx context.Context, userId uuid.UUID, credentialId []byte) error {
+	credId := encodeCredentialId(credentialId)
+	_, err := s.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.HDel(ctx, prefixPasskeyUser+userId.String(), credId)
+		pipe.Del(ctx, prefixPasskeyCredential+credId)
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("redis delete passkey failed: %s", err)
+	}
+	return nil
+}
+
+func encodeCredentialId(id []byte) string {
+	return base64.RawURLEncoding.EncodeToString(id)
+}
+
+type PasskeySessionConfig struct {
+	Lifetime time.Duration
+}
+
+// PasskeySessionStorage keeps WebAuthn ceremony sessions as JSON.
+type PasskeySessionStorage struct {
+	client *redis.Client
+	conf   *PasskeySessionConfig
+	prefix string
+}
+
+// NewPasskeyRegistrationStorage returns the storage of registration sessions keyed by user id.
+func NewPasskeyRegistrationStorage(conf *PasskeySessionConfig, client *redis.Client) *PasskeySessionStorage {
+	return &PasskeySessionStorage{
+		client: client,
+		conf:   conf,
+		prefix: prefixPasskeyRegistration,
+	}
+}
+
+// NewPasskeySignInStorage returns the storage of sign in sessions keyed by session id.
+func NewPasskeySignInStorage(conf *PasskeySessionConfig, client *redis.Client) *PasskeySessionStorage {
+	return &PasskeySessionStorage{
+		client: client,
+		conf:   conf,
+		prefix: prefixPasskeySignIn,
+	}
+}
+
+func (s *PasskeySessionStorage) Store(ctx context.Context, key uuid.UUID, session *webauthn.SessionData) error {
+	sessionJson, err := json.Marshal(session)
+	if err != nil {
+		return fmt.Errorf("passkey session json marshalling failed: %s", err)
+	}
+
+	if err := s.client.Set(ctx, s.prefix+key.String(), sessionJson, s.conf.Lifetime).Err(); err != nil {
+		return fmt.Errorf("redis set passkey session failed: %s", err)
+	}
+	return nil
+}
+
+// Consume uses GETDEL so that concurrent requests can't get the same challenge.
+func (s *PasskeySessionStorage) Consume(ctx context.Context, key uuid.UUID) (*webauthn.SessionData, bool, error) {
+	res := s.client.GetDel(ctx, s.prefix+key.String())
+	if err := res.Err(); err != nil {
+		if err == redis.Nil {
+			return nil, false, nil
+		}
+		return nil, false, fmt.Errorf("redis getdel passkey session failed: %s", err)
+	}
+
+	session := new(webauthn.SessionData)
+	if err := json.Unmarshal([]byte(res.Val()), session); err != nil {
+		return nil, false, fmt.Errorf("unmarshalling passkey session failed: %s", err)
+	}
+	return session, true, nil
+}
@@ -0,0 +1,66 @@
+package storage
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/chakchat/chakchat-backend/identity-service/internal/services"
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+)
+
+const prefixPhoneChange = "PhoneChange:User:"
+
+type PhoneChangeMetaConfig struct {
+	MetaLifetime time.Duration
+}
+
+type PhoneChangeMetaStorage struct {
+	client *redis.Client
+	conf   *PhoneChangeMetaConfig
+}
+
+func NewPhoneChangeMetaStorage(conf *PhoneChangeMetaConfig, client *redis.Client) *PhoneChangeMetaStorage {
+	return &PhoneChangeMetaStorage{
+		client: client,
+		conf:   conf,
+	}
+}
+
+func (s *PhoneChangeMetaStorage) Find(ctx context.Context, userId uuid.UUID) (*services.PhoneChangeMeta, bool, error) {
+	res := s.client.Get(ctx, prefixPhoneChange+userId.String())
+	if err := res.Err(); err != nil {
+		if err == redis.Nil {
+			return nil, false, nil
+		}
+		return nil, false, fmt.Errorf("redis get phone change meta failed: %s", err)
+	}
+
+	meta := new(services.PhoneChangeMeta)
+	if err := json.Unmarshal([]byte(res.Val()), meta); err != nil {
+		return nil, false, fmt.Errorf("unmarshalling phone change meta failed: %s", err)
+	}
+	return meta, true, nil
+}
+
+func (s *PhoneChangeMetaStorage) Store(ctx context.Context, meta *services.PhoneChangeMeta) error {
+	metaJson, err := json.Marshal(meta)
+	if err != nil {
+		return fmt.Errorf("phone change meta json marshalling failed: %s", err)
+	}
+
+	key := prefixPhoneChange + meta.UserId.String()
+	if err := s.client.Set(ctx, key, metaJson, s.conf.MetaLifetime).Err(); err != nil {
+		return fmt.Errorf("redis set phone change meta failed: %s", err)
+	}
+	return nil
+}
+
+func (s *PhoneChangeMetaStorage) Remove(ctx context.Context, userId uuid.UUID) error {
+	if err := s.client.Del(ctx, prefixPhoneChange+userId.String()).Err(); err != nil {
+		return fmt.Errorf("redis delete phone change meta failed: %s", err)
+	}
+	return nil
+}
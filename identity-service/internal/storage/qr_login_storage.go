@@ -0,0 +1,126 @@
+package storage
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/chakchat/chakchat-backend/identity-service/internal/services"
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+)
+
+const prefixQRLogin = "QRLogin:"
+
+// consumeQRLoginScript deletes the meta only if it is confirmed and the poll secret matches.
+// It returns the deleted meta or nil.
+var consumeQRLoginScript = redis.NewScript(`
+local val = redis.call('GET', KEYS[1])
+if not val then
+	return false
+end
+local meta = cjson.decode(val)
+if meta.Confirmed ~= true or meta.PollSecret ~= ARGV[1] then
+	return false
+end
+redis.call('DEL', KEYS[1])
+return val
+`)
+
+// confirmQRLoginScript sets the confirming user only if the meta is not confirmed yet.
+// The TTL is kept. It returns 1 if the meta is confirmed by this call and 0 otherwise.
+var confirmQRLoginScript = redis.NewScript(`
+local val = redis.call('GET', KEYS[1])
+if not val then
+	return 0
+end
+local meta = cjson.decode(val)
+if meta.Confirmed == true then
+	return 0
+end
+meta.Confirmed = true
+meta.UserId = ARGV[1]
+meta.Name = ARGV[2]
+meta.Username = ARGV[3]
+redis.call('SET', KEYS[1], cjson.encode(meta), 'KEEPTTL')
+return 1
+`)
+
+type QRLoginStorage struct {
+	client *redis.Client
+}
+
+func NewQRLoginStorage(client *redis.Client) *QRLoginStorage {
+	return &QRLoginStorage{
+		client: client,
+	}
+}
+
+func (s *QRLoginStorage) Find(ctx context.Context, token uuid.UUID) (*services.QRLoginMeta, bool, error) {
+	res := s.client.Get(ctx, prefixQRLogin+token.String())
+	if err := res.Err(); err != nil {
+		if err == redis.Nil {
+			return nil, false, nil
+		}
+		return nil, false, fmt.Errorf("redis get qr login meta failed: %s", err)
+	}
+
+	meta := new(services.QRLoginMeta)
+	if err := json.Unmarshal([]byte(res.Val()), meta); err != nil {
+		return nil, false, fmt.Errorf("unmarshalling qr login meta failed: %s", err)
+	}
+	return meta, true, nil
+}
+
+func (s *QRLoginStorage) Store(ctx context.Context, meta *services.QRLoginMeta) error {
+	ttl := time.Until(meta.ExpiresAt)
+	if ttl <= 0 {
+		// Already expired so it just shouldn't be found anymore
+		return s.Remove(ctx, meta.Token)
+	}
+
+	metaJson, err := json.Marshal(meta)
+	if err != nil {
+		return fmt.Errorf("qr login meta json marshalling failed: %s", err)
+	}
+
+	if err := s.client.Set(ctx, prefixQRLogin+meta.Token.String(), metaJson, ttl).Err(); err != nil {
+		return fmt.Errorf("redis set qr login meta failed: %s", err)
+	}
+	return nil
+}
+
+func (s *QRLoginStorage) Remove(ctx context.Context, token uuid.UUID) error {
+	if err := s.client.Del(ctx, prefixQRLogin+token.String()).Err(); err != nil {
+		return fmt.Errorf("redis delete qr login meta failed: %s", err)
+	}
+	return nil
+}
+
+func (s *QRLoginStorage) Confirm(ctx context.Context, meta *services.QRLoginMeta) (bool, error) {
+	res, err := confirmQRLoginScript.Run(ctx, s.client, []string{prefixQRLogin + meta.Token.String()},
+		meta.UserId.String(), meta.Name, meta.Username).Int()
+	if err != nil {
+		return false, fmt.Errorf("redis confirm qr login meta failed: %s", err)
+	}
+	return res == 1, nil
+}
+
+func (s *QRLoginStorage) Consume(
+	ctx context.Context, token, pollSecret uuid.UUID,
+) (*services.QRLoginMeta, bool, error) {
+	res, err := consumeQRLoginScript.Run(ctx, s.client, []string{prefixQRLogin + token.String()}, pollSecret.String()).Text()
+	if err != nil {
+		if err == redis.Nil {
+			return nil, false, nil
+		}
+		return nil, false, fmt.Errorf("redis consume qr login meta failed: %s", err)
+	}
+
+	meta := new(services.QRLoginMeta)
+	if err := json.Unmarshal([]byte(res), meta); err != nil {
+		return nil, false, fmt.Errorf("unmarshalling qr login meta failed: %s", err)
+	}
+	return meta, true, nil
+}
@@ -0,0 +1,67 @@
+package storage
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/chakchat/chakchat-backend/identity-service/internal/services"
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+)
+
+const prefixSuspension = "Suspension:User:"
+
+type SuspensionCacheConfig struct {
+	Lifetime time.Duration
+}
+
+type SuspensionCache struct {
+	client *redis.Client
+	conf   *SuspensionCacheConfig
+}
+
+func NewSuspensionCache(conf *SuspensionCacheConfig, client *redis.Client) *SuspensionCache {
+	return &SuspensionCache{
+		client: client,
+		conf:   conf,
+	}
+}
+
+func (s *SuspensionCache) Find(ctx context.Context, userId uuid.UUID) (*services.Suspension, bool, error) {
+	res := s.client.Get(ctx, prefixSuspension+userId.String())
+	if err := res.Err(); err != nil {
+		if err == redis.Nil {
+			return nil, false, nil
+		}
+		return nil, false, fmt.Errorf("redis get suspension failed: %s", err)
+	}
+
+	suspension := new(services.Suspension)
+	if err := json.Unmarshal([]byte(res.Val()), suspension); err != nil {
+		return nil, false, fmt.Errorf("unmarshalling suspension failed: %s", err)
+	}
+	return suspension, true, nil
+}
+
+func (s *SuspensionCache) Store(ctx context.Context, userId uuid.UUID, suspension *services.Suspension) error {
+	suspensionJson, err := json.Marshal(suspension)
+	if err != nil {
+		return fmt.Errorf("suspension json marshalling failed: %s", err)
+	}
+
+	lifetime := s.conf.Lifetime
+	// Expired suspension shouldn't stay in the cache
+	if suspension.Until != nil {
+		if left := time.Until(*suspension.Until); left > 0 && left < lifetime {
+			lifetime = left
+		}
+	}
+
+	key := prefixSuspension + userId.String()
+	if err := s.client.Set(ctx, key, suspensionJson, lifetime).Err(); err != nil {
+		return fmt.Errorf("redis set suspension failed: %s", err)
+	}
+	return nil
+}
@@ -22,6 +22,12 @@ const _ = grpc.SupportPackageIsVersion7
 type UserServiceClient interface {
 	GetUser(ctx context.Context, in *UserRequest, opts ...grpc.CallOption) (*UserResponse, error)
 	CreateUser(ctx context.Context, in *CreateUserRequest, opts ...grpc.CallOption) (*CreateUserResponse, error)
+	GetName(ctx context.Context, in *GetNameRequest, opts ...grpc.CallOption) (*GetNameResponse, error)
+	UpdatePhone(ctx context.Context, in *UpdatePhoneRequest, opts ...grpc.CallOption) (*UpdatePhoneResponse, error)
+	GetSuspension(ctx context.Context, in *GetSuspensionRequest, opts ...grpc.CallOption) (*GetSuspensionResponse, error)
+	SuspendUser(ctx context.Context, in *SuspendUserRequest, opts ...grpc.CallOption) (*SuspendUserResponse, error)
+	UnsuspendUser(ctx context.Context, in *UnsuspendUserRequest, opts ...grpc.CallOption) (*UnsuspendUserResponse, error)
+	GetUserIDsByUsernames(ctx context.Context, in *GetUserIDsByUsernamesRequest, opts ...grpc.CallOption) (*GetUserIDsByUsernamesResponse, error)
 }
 
 type userServiceClient struct {
@@ -49,3 +55,57 @@ func (c *userServiceClient) CreateUser(ctx context.Context, in *CreateUserReques
 	}
 	return out, nil
 }
+
+func (c *userServiceClient) GetName(ctx context.Context, in *GetNameRequest, opts ...grpc.CallOption) (*GetNameResponse, error) {
+	out := new(GetNameResponse)
+	err := c.cc.Invoke(ctx, "/user.UserService/GetName", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *userServiceClient) UpdatePhone(ctx context.Context, in *UpdatePhoneRequest, opts ...grpc.CallOption) (*UpdatePhoneResponse, error) {
+	out := new(UpdatePhoneResponse)
+	err := c.cc.Invoke(ctx, "/user.UserService/UpdatePhone", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *userServiceClient) GetSuspension(ctx context.Context, in *GetSuspensionRequest, opts ...grpc.CallOption) (*GetSuspensionResponse, error) {
+	out := new(GetSuspensionResponse)
+	err := c.cc.Invoke(ctx, "/user.UserService/GetSuspension", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *userServiceClient) SuspendUser(ctx context.Context, in *SuspendUserRequest, opts ...grpc.CallOption) (*SuspendUserResponse, error) {
+	out := new(SuspendUserResponse)
+	err := c.cc.Invoke(ctx, "/user.UserService/SuspendUser", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *userServiceClient) UnsuspendUser(ctx context.Context, in *UnsuspendUserRequest, opts ...grpc.CallOption) (*UnsuspendUserResponse, error) {
+	out := new(UnsuspendUserResponse)
+	err := c.cc.Invoke(ctx, "/user.UserService/UnsuspendUser", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *userServiceClient) GetUserIDsByUsernames(ctx context.Context, in *GetUserIDsByUsernamesRequest, opts ...grpc.CallOption) (*GetUserIDsByUsernamesResponse, error) {
+	out := new(GetUserIDsByUsernamesResponse)
+	err := c.cc.Invoke(ctx, "/user.UserService/GetUserIDsByUsernames", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
@@ -7,20 +7,26 @@ import (
 	"net/http"
 	"os"
 	"strconv"
+	"strings"
 
 	"github.com/chakchat/chakchat-backend/identity-service/internal/handlers"
+	"github.com/chakchat/chakchat-backend/identity-service/internal/liveconnection"
+	"github.com/chakchat/chakchat-backend/identity-service/internal/otp"
 	"github.com/chakchat/chakchat-backend/identity-service/internal/proto"
 	"github.com/chakchat/chakchat-backend/identity-service/internal/proto/identity"
 	"github.com/chakchat/chakchat-backend/identity-service/internal/restapi"
 	"github.com/chakchat/chakchat-backend/identity-service/internal/services"
-	"github.com/chakchat/chakchat-backend/identity-service/internal/sms"
 	"github.com/chakchat/chakchat-backend/identity-service/internal/storage"
 	"github.com/chakchat/chakchat-backend/identity-service/internal/userservice"
 	"github.com/chakchat/chakchat-backend/shared/go/idempotency"
 	"github.com/chakchat/chakchat-backend/shared/go/jwt"
+	"github.com/chakchat/chakchat-backend/shared/go/ratelimit"
 	"github.com/gin-gonic/gin"
+	"github.com/go-webauthn/webauthn/protocol"
+	"github.com/go-webauthn/webauthn/webauthn"
 	"github.com/redis/go-redis/extra/redisotel/v9"
 	"github.com/redis/go-redis/v9"
+	"github.com/segmentio/kafka-go"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials/insecure"
 
@@ -58,7 +64,6 @@ func main() {
 		log.Fatalf("Add instrument tracing to redis failed: %s", err)
 	}
 
-	sms := createSmsSender()
 	usersClient, closeGrpc := createUsersClient()
 	defer closeGrpc()
 
@@ -67,26 +72,50 @@ func main() {
 	internalTokenConfig := loadInternalTokenConfig()
 
 	idempotencyStorage := createIdempotencyStorage(rdb)
+	idempotencyLocker := createIdempotencyLocker(rdb)
 	signInMetaStorage := createSignInMetaStorage(rdb)
 	invalidatedTokenStorage := createInvalidatedTokenStorage(rdb)
 	signUpMetaStorage := createSignUpMetaStorage(rdb)
 	deviceStorage := createDeviceStorage(rdb)
-
-	sendCodeService := createSignInSendCodeService(sms, signInMetaStorage, usersClient)
-	signInService := services.NewSignInService(signInMetaStorage, accessTokenConfig, refreshTokenConfig, deviceStorage)
-	refreshService := services.NewRefreshService(invalidatedTokenStorage, accessTokenConfig, refreshTokenConfig, deviceStorage)
-	signOutService := services.NewSignOutService(invalidatedTokenStorage, refreshTokenConfig, deviceStorage)
-	identityService := services.NewIdentityService(accessTokenConfig, internalTokenConfig)
-	signUpSendCodeService := createSignUpSendCodeService(sms, signUpMetaStorage, usersClient)
+	qrLoginStorage := storage.NewQRLoginStorage(rdb)
+	cloudPasswordStorage := storage.NewCloudPasswordStorage(rdb)
+	phoneChangeStorage := createPhoneChangeMetaStorage(rdb)
+	loginHistoryStorage := createLoginHistoryStorage(rdb)
+	suspensionCache := createSuspensionCache(rdb)
+	passkeyStorage := storage.NewPasskeyStorage(rdb)
+
+	liveConnWriter, closeLiveConnWriter := createLiveConnectionWriter()
+	defer closeLiveConnWriter()
+	codeSender := createCodeSender(liveConnWriter, deviceStorage)
+	liveConnNotifier := createLiveConnectionNotifier(liveConnWriter)
+	emailSender := createEmailSender()
+
+	suspensionService := services.NewSuspensionService(suspensionCache, usersClient, liveConnNotifier)
+	loginHistoryService := services.NewLoginHistoryService(loginHistoryStorage, deviceStorage, liveConnNotifier, accessTokenConfig)
+	sendCodeService := createSignInSendCodeService(codeSender, signInMetaStorage, usersClient)
+	signInService := services.NewSignInService(signInMetaStorage, accessTokenConfig, refreshTokenConfig, deviceStorage,
+		cloudPasswordStorage, emailSender, loginHistoryService, suspensionService)
+	cloudPasswordService := services.NewCloudPasswordService(cloudPasswordStorage, emailSender, accessTokenConfig)
+	refreshService := services.NewRefreshService(invalidatedTokenStorage, accessTokenConfig, refreshTokenConfig, deviceStorage,
+		loginHistoryService, suspensionService)
+	signOutService := services.NewSignOutService(invalidatedTokenStorage, refreshTokenConfig, deviceStorage, loginHistoryService)
+	identityService := services.NewIdentityService(accessTokenConfig, internalTokenConfig, suspensionService)
+	signUpSendCodeService := createSignUpSendCodeService(codeSender, signUpMetaStorage, usersClient)
 	signUpVerifyService := services.NewSignUpVerifyCodeService(signUpMetaStorage)
+	qrLoginService := createQRLoginService(qrLoginStorage, accessTokenConfig, refreshTokenConfig, deviceStorage,
+		loginHistoryService, suspensionService)
 	signUpService := services.NewSignUpService(accessTokenConfig, refreshTokenConfig, usersClient, signUpMetaStorage, deviceStorage)
+	phoneChangeService := createPhoneChangeService(codeSender, phoneChangeStorage, usersClient,
+		liveConnNotifier, accessTokenConfig)
+	passkeyService := createPasskeyService(rdb, passkeyStorage, accessTokenConfig, refreshTokenConfig, deviceStorage,
+		loginHistoryService, suspensionService, usersClient)
 
 	grpcListener, err := net.Listen("tcp", ":"+strconv.Itoa(conf.GRPCService.Port))
 	if err != nil {
 		log.Fatalf("Listening TCP failed: %s", err)
 	}
 
-	grpcService := proto.NewGRPCServer(deviceStorage)
+	grpcService := proto.NewGRPCServer(deviceStorage, suspensionService)
 
 	grpcServer := grpc.NewServer()
 	identity.RegisterIdentityServiceServer(grpcServer, grpcService)
@@ -110,27 +139,179 @@ func main() {
 
 	r.Use(gin.Logger())
 
+	limiter := ratelimit.NewRedisLimiter(rdb)
+	sendCodeByPhone := ratelimit.New(limiter, &ratelimit.Config{
+		Name:  "send_code_phone",
+		Limit: rateLimit(conf.RateLimit.SendCodeByPhone),
+		Key:   ratelimit.ByJSONField("phone"),
+	})
+	sendCodeByIP := ratelimit.New(limiter, &ratelimit.Config{
+		Name:  "send_code_ip",
+		Limit: rateLimit(conf.RateLimit.SendCodeByIP),
+		Key:   ratelimit.ByIP(),
+	})
+	passwordAttempts := ratelimit.New(limiter, &ratelimit.Config{
+		Name:  "password_attempts",
+		Limit: rateLimit(conf.RateLimit.PasswordAttempts),
+		Key:   ratelimit.ByJSONField("signin_key"),
+	})
+	phoneChangeAttempts := ratelimit.New(limiter, &ratelimit.Config{
+		Name:  "phone_change_attempts",
+		Limit: rateLimit(conf.RateLimit.PhoneChangeAttempts),
+		Key:   byAccessTokenUser(accessTokenConfig),
+	})
+	cloudPasswordAttempts := ratelimit.New(limiter, &ratelimit.Config{
+		Name:  "cloud_password_attempts",
+		Limit: rateLimit(conf.RateLimit.CloudPasswordAttempts),
+		Key:   byAccessTokenUser(accessTokenConfig),
+	})
+	qrTokenByIP := ratelimit.New(limiter, &ratelimit.Config{
+		Name:  "qr_token_ip",
+		Limit: rateLimit(conf.RateLimit.QRTokenByIP),
+		Key:   ratelimit.ByIP(),
+	})
+
 	r.Group("/v1.0").
-		Use(idempotency.New(idempotencyStorage)).
-		POST("/signin/send-phone-code", handlers.SignInSendCode(sendCodeService)).
+		Use(idempotency.New(idempotencyStorage, idempotencyLocker)).
+		POST("/signin/send-phone-code", sendCodeByIP, sendCodeByPhone, handlers.SignInSendCode(sendCodeService)).
 		POST("/signin", handlers.SignIn(signInService)).
+		POST("/signin/password", passwordAttempts, handlers.SignInPassword(signInService)).
+		POST("/signin/password/recovery", handlers.SignInPasswordRecovery(signInService)).
+		POST("/signin/password/recovery/confirm", passwordAttempts, handlers.SignInPasswordRecoveryConfirm(signInService)).
 		POST("/refresh-token", handlers.RefreshJWT(refreshService)).
-		POST("/signup/send-phone-code", handlers.SignUpSendCode(signUpSendCodeService)).
+		POST("/signup/send-phone-code", sendCodeByIP, sendCodeByPhone, handlers.SignUpSendCode(signUpSendCodeService)).
 		POST("/signup/verify-code", handlers.SignUpVerifyCode(signUpVerifyService)).
-		POST("/signup", handlers.SignUp(signUpService))
+		POST("/signup", handlers.SignUp(signUpService)).
+		POST("/signin/qr/token", qrTokenByIP, handlers.QRLoginCreateToken(qrLoginService)).
+		POST("/signin/qr/confirm", handlers.QRLoginConfirm(qrLoginService)).
+		POST("/signin/passkey", handlers.PasskeySignIn(passkeyService))
+
+	// It is polled by the new device until the login is confirmed.
+	// So it is not idempotent because "not confirmed" response mustn't be cached.
+	r.POST("/v1.0/signin/qr", handlers.QRLoginSignIn(qrLoginService))
 
 	r.PUT("/v1.0/sign-out", handlers.SignOut(signOutService))
+	r.PUT("/v1.0/password", cloudPasswordAttempts, handlers.SetCloudPassword(cloudPasswordService))
+	r.PUT("/v1.0/password/remove", cloudPasswordAttempts, handlers.RemoveCloudPassword(cloudPasswordService))
+	r.PUT("/v1.0/password/email/verify", cloudPasswordAttempts, handlers.VerifyRecoveryEmail(cloudPasswordService))
+	r.PUT("/v1.0/phone/send-code", sendCodeByIP, sendCodeByPhone, handlers.PhoneChangeSendCode(phoneChangeService))
+	r.PUT("/v1.0/phone/confirm", phoneChangeAttempts, handlers.PhoneChangeConfirm(phoneChangeService))
 	r.GET("/v1.0/identity", handlers.Identity(identityService))
+	r.GET("/v1.0/login-history", handlers.LoginHistory(loginHistoryService))
+
+	// Every call issues a new challenge, so the responses mustn't be cached.
+	r.POST("/v1.0/signin/passkey/begin", handlers.PasskeyBeginSignIn(passkeyService))
+	r.POST("/v1.0/passkey/register/begin", handlers.PasskeyBeginRegistration(passkeyService))
+	r.POST("/v1.0/passkey/register/finish", handlers.PasskeyFinishRegistration(passkeyService))
+	r.GET("/v1.0/passkeys", handlers.PasskeyList(passkeyService))
+	r.DELETE("/v1.0/passkey/:id", handlers.PasskeyRemove(passkeyService))
 
 	r.Run(":5000")
 }
 
-func createSignUpSendCodeService(sms sms.SmsSender, storage *storage.SignUpMetaStorage,
+func createSignUpSendCodeService(codes otp.CodeSender, storage *storage.SignUpMetaStorage,
 	users userservice.UserServiceClient) *services.SignUpSendCodeService {
 	config := &services.CodeConfig{
 		SendFrequency: conf.PhoneCode.SendFrequency,
 	}
-	return services.NewSignUpSendCodeService(config, sms, storage, users)
+	return services.NewSignUpSendCodeService(config, codes, storage, users)
+}
+
+func createPhoneChangeService(codes otp.CodeSender, storage services.PhoneChangeMetaStorage,
+	users userservice.UserServiceClient, notifier services.PhoneChangeNotifier,
+	accessConf *jwt.Config) *services.PhoneChangeService {
+	config := &services.CodeConfig{
+		SendFrequency: conf.PhoneCode.SendFrequency,
+	}
+	return services.NewPhoneChangeService(config, codes, storage, users, notifier, accessConf)
+}
+
+func createLoginHistoryStorage(redisClient *redis.Client) *storage.LoginHistoryStorage {
+	stConf := &storage.LoginHistoryConfig{
+		MaxEvents: conf.LoginHistory.MaxEvents,
+		Lifetime:  conf.LoginHistory.Lifetime,
+	}
+	return storage.NewLoginHistoryStorage(stConf, redisClient)
+}
+
+func createSuspensionCache(redisClient *redis.Client) *storage.SuspensionCache {
+	stConf := &storage.SuspensionCacheConfig{
+		Lifetime: conf.Suspension.CacheLifetime,
+	}
+	return storage.NewSuspensionCache(stConf, redisClient)
+}
+
+func createPhoneChangeMetaStorage(redisClient *redis.Client) *storage.PhoneChangeMetaStorage {
+	stConf := &storage.PhoneChangeMetaConfig{
+		MetaLifetime: conf.PhoneChange.Lifetime,
+	}
+	return storage.NewPhoneChangeMetaStorage(stConf, redisClient)
+}
+
+func createEmailSender() otp.CodeSender {
+	if conf.Email.Type == "stub" {
+		return otp.NewSmsServerStubSender(conf.Email.Stub.Addr)
+	}
+	return otp.NewSmtpSender(&otp.SmtpConfig{
+		Addr:     conf.Email.Smtp.Addr,
+		Username: conf.Email.Smtp.Username,
+		Password: conf.Email.Smtp.Password,
+		From:     conf.Email.Smtp.From,
+	})
+}
+
+func createQRLoginService(storage services.QRLoginStorage, accessConf, refreshConf *jwt.Config,
+	deviceStorage services.DeviceStorage, logins services.LoginRecorder,
+	suspensions services.SuspensionChecker) *services.QRLoginService {
+	config := &services.QRLoginConfig{
+		Lifetime: conf.QRLogin.Lifetime,
+	}
+	return services.NewQRLoginService(config, storage, accessConf, refreshConf, deviceStorage, logins, suspensions)
+}
+
+func createPasskeyService(redisClient *redis.Client, passkeys services.PasskeyStorage,
+	accessConf, refreshConf *jwt.Config, deviceStorage services.DeviceStorage, logins services.LoginRecorder,
+	suspensions services.SuspensionChecker, users userservice.UserServiceClient) *services.PasskeyService {
+	userVerification := protocol.VerificationPreferred
+	if conf.Passkey.RequireUserVerification {
+		userVerification = protocol.VerificationRequired
+	}
+	timeout := webauthn.TimeoutConfig{
+		Enforce:    true,
+		Timeout:    conf.Passkey.Timeout,
+		TimeoutUVD: conf.Passkey.Timeout,
+	}
+	wa, err := webauthn.New(&webauthn.Config{
+		RPID:          conf.Passkey.RPID,
+		RPDisplayName: conf.Passkey.RPName,
+		RPOrigins:     conf.Passkey.Origins,
+		// Attestation statements aren't verified, passkeys of any authenticator are accepted
+		AttestationPreference: protocol.PreferNoAttestation,
+		// Credentials are discoverable so that the user signs in without entering the phone
+		AuthenticatorSelection: protocol.AuthenticatorSelection{
+			ResidentKey:        protocol.ResidentKeyRequirementRequired,
+			RequireResidentKey: protocol.ResidentKeyRequired(),
+			UserVerification:   userVerification,
+		},
+		Timeouts: webauthn.TimeoutsConfig{
+			Login:        timeout,
+			Registration: timeout,
+		},
+	})
+	if err != nil {
+		log.Fatalf("WebAuthn config is invalid: %s", err)
+	}
+
+	config := &services.PasskeyConfig{
+		WebAuthn: wa,
+	}
+	sessionConf := &storage.PasskeySessionConfig{
+		Lifetime: conf.Passkey.Timeout,
+	}
+	return services.NewPasskeyService(config, passkeys,
+		storage.NewPasskeyRegistrationStorage(sessionConf, redisClient),
+		storage.NewPasskeySignInStorage(sessionConf, redisClient),
+		accessConf, refreshConf, deviceStorage, logins, suspensions, users)
 }
 
 func createSignUpMetaStorage(redisClient *redis.Client) *storage.SignUpMetaStorage {
@@ -147,11 +328,70 @@ func createDeviceStorage(redisClient *redis.Client) *storage.DeviceStorage {
 	return storage.NewDeviceStorage(redisClient, conf)
 }
 
-func createSmsSender() sms.SmsSender {
-	if conf.Sms.Type == "stub" {
-		return sms.NewSmsServerStubSender(conf.Sms.Stub.Addr)
+// Returns nil writer if live connection is disabled.
+func createLiveConnectionWriter() (*kafka.Writer, func()) {
+	if !conf.LiveConnection.Enabled {
+		return nil, func() {}
+	}
+	writer := &kafka.Writer{
+		Addr:                   kafka.TCP(conf.LiveConnection.Brokers...),
+		Topic:                  conf.LiveConnection.Topic,
+		Balancer:               &kafka.Hash{},
+		AllowAutoTopicCreation: true,
+	}
+	return writer, func() {
+		if err := writer.Close(); err != nil {
+			log.Printf("closing live connection kafka writer failed: %s", err)
+		}
+	}
+}
+
+type liveConnectionNotifier interface {
+	services.PhoneChangeNotifier
+	services.NewLoginNotifier
+	services.SuspensionNotifier
+}
+
+func createLiveConnectionNotifier(writer *kafka.Writer) liveConnectionNotifier {
+	if writer == nil {
+		return liveconnection.NopNotifier{}
 	}
-	return sms.NewSmsSender(conf.Sms.Email, conf.Sms.ApiKey)
+	return liveconnection.NewNotifier(writer)
+}
+
+func createCodeSender(writer *kafka.Writer, devices otp.DeviceChecker) otp.CodeSender {
+	var senders []otp.NamedSender
+
+	if writer != nil && conf.LiveConnection.SendCodes {
+		senders = append(senders, otp.NamedSender{
+			Name:   "live_connection",
+			Sender: otp.NewLiveConnectionSender(writer, devices),
+		})
+	}
+
+	for _, provider := range conf.Sms.Providers {
+		switch provider {
+		case "sms_aero":
+			senders = append(senders, otp.NamedSender{
+				Name:   provider,
+				Sender: otp.NewSmsAeroSender(conf.Sms.Email, conf.Sms.ApiKey),
+			})
+		case "sms_ru":
+			senders = append(senders, otp.NamedSender{
+				Name:   provider,
+				Sender: otp.NewSmsRuSender(conf.Sms.SmsRu.ApiId),
+			})
+		case "stub":
+			senders = append(senders, otp.NamedSender{
+				Name:   provider,
+				Sender: otp.NewSmsServerStubSender(conf.Sms.Stub.Addr),
+			})
+		default:
+			log.Fatalf("unknown sms provider: %s", provider)
+		}
+	}
+
+	return otp.NewFailoverSender(senders...)
 }
 
 func createInvalidatedTokenStorage(redisClient *redis.Client) *storage.InvalidatedTokenStorage {
@@ -225,6 +465,34 @@ func createSignInMetaStorage(redisClient *redis.Client) *storage.SignInMetaStora
 	return storage.NewSignInMetaStorage(config, redisClient)
 }
 
+func rateLimit(conf RateLimitConfig) ratelimit.Limit {
+	return ratelimit.Limit{
+		Requests: conf.Requests,
+		Window:   conf.Window,
+	}
+}
+
+// byAccessTokenUser is like ratelimit.ByUser() but for the endpoints
+// that parse the access token in the handler instead of the auth middleware.
+// Requests with invalid tokens are not counted, the handler rejects them anyway.
+func byAccessTokenUser(accessConf *jwt.Config) ratelimit.KeyFunc {
+	return func(c *gin.Context) (string, bool) {
+		token, ok := strings.CutPrefix(c.GetHeader("Authorization"), "Bearer ")
+		if !ok {
+			return "", false
+		}
+		claims, err := jwt.Parse(accessConf, jwt.Token(token))
+		if err != nil {
+			return "", false
+		}
+		sub, ok := claims[jwt.ClaimSub].(string)
+		if !ok || sub == "" {
+			return "", false
+		}
+		return "user:" + sub, true
+	}
+}
+
 func createIdempotencyStorage(redisClient *redis.Client) idempotency.IdempotencyStorage {
 	idempotencyConf := &idempotency.IdempotencyConfig{
 		DataExp: conf.Idempotency.DataExp,
@@ -232,12 +500,19 @@ func createIdempotencyStorage(redisClient *redis.Client) idempotency.Idempotency
 	return idempotency.NewStorage(redisClient, idempotencyConf)
 }
 
-func createSignInSendCodeService(sms sms.SmsSender, storage services.SignInMetaFindStorer,
+func createIdempotencyLocker(redisClient *redis.Client) idempotency.Locker {
+	lockerConf := &idempotency.LockerConfig{
+		LockExp: conf.Idempotency.LockExp,
+	}
+	return idempotency.NewLocker(redisClient, lockerConf)
+}
+
+func createSignInSendCodeService(codes otp.CodeSender, storage services.SignInMetaFindStorer,
 	users userservice.UserServiceClient) *services.SignInSendCodeService {
 	config := &services.CodeConfig{
 		SendFrequency: conf.PhoneCode.SendFrequency,
 	}
-	return services.NewSignInSendCodeService(config, sms, storage, users)
+	return services.NewSignInSendCodeService(config, codes, storage, users)
 }
 
 func readKey(path string) []byte {
@@ -12,6 +12,9 @@ import (
 	"github.com/chakchat/chakchat-backend/live-connection-service/internal/ws"
 )
 
+// Receivers of this message must be disconnected after it is delivered.
+const typeUserSuspended = "user_suspended"
+
 type KafkaProcessor struct {
 	hub    *ws.Hub
 	notifq *mq.Producer //queue to send message in notification service
@@ -41,6 +44,9 @@ func (p *KafkaProcessor) MessageHandler(ctx context.Context, msg kafka.Message)
 		if !p.hub.Send(userId, response) {
 			notificReceivers = append(notificReceivers, userId)
 		}
+		if message.Type == typeUserSuspended {
+			p.hub.Disconnect(userId, "user suspended")
+		}
 	}
 	if len(notificReceivers) != 0 {
 		notificMessage := models.KafkaMessage{
@@ -105,6 +105,19 @@ func (h *Hub) Send(userId uuid.UUID, message any) bool {
 	return false
 }
 
+// Disconnect closes the user's connection if there is one.
+// The client is removed by the handler when reading fails.
+func (h *Hub) Disconnect(userId uuid.UUID, reason string) {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	if client, ok := h.clients[userId]; ok {
+		closeMsg := websocket.FormatCloseMessage(websocket.ClosePolicyViolation, reason)
+		client.conn.WriteControl(websocket.CloseMessage, closeMsg, time.Now().Add(time.Second))
+		client.conn.Close()
+	}
+}
+
 func (h *Hub) GetOnlineStatus(userIds []uuid.UUID) map[uuid.UUID]bool {
 	h.mu.RLock()
 	defer h.mu.RUnlock()
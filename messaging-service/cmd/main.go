@@ -99,11 +99,19 @@ func main() {
 	}
 	defer fileStConn.Close()
 
+	userConn, err := grpc.NewClient(config.UserService.GrpcAddr,
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		log.Fatalf("Cannot connect to user service gRPC: %s", err)
+	}
+	defer userConn.Close()
+
 	confDB := configuration.NewDB(db, rdb)
 
-	confExternal := configuration.NewExternal(fileStConn, kafkaMq)
+	confExternal := configuration.NewExternal(fileStConn, userConn, kafkaMq)
 
-	srv := configuration.NewServices(confDB, confExternal)
+	srv := configuration.NewServices(confDB, confExternal, config)
 
 	rest := configuration.NewHandlers(srv)
 
@@ -112,6 +120,9 @@ func main() {
 		log.Fatal(err)
 	}
 
+	go srv.ScheduledDispatcher.Run(ctx, config.ScheduledMessages.DispatchInterval)
+	go srv.FanoutDispatcher.Run(ctx, config.Channels.FanoutInterval)
+
 	if err := ginEngine.Run(":5000"); err != nil {
 		log.Fatalf("Gin engine running failed: %s", err)
 	}
@@ -0,0 +1,34 @@
+package dto
+
+import (
+	"github.com/chakchat/chakchat-backend/messaging-service/internal/domain/channel"
+	"github.com/google/uuid"
+)
+
+type ChannelDTO struct {
+	ID     uuid.UUID
+	Owner  uuid.UUID
+	Admins []GroupAdminDTO
+
+	Name             string
+	Description      string
+	SubscribersCount int64
+	CreatedAt        int64
+}
+
+func NewChannelDTO(c *channel.Channel) ChannelDTO {
+	return ChannelDTO{
+		ID:               uuid.UUID(c.ID),
+		Owner:            uuid.UUID(c.Owner),
+		Admins:           NewGroupAdminDTOs(c.Admins),
+		Name:             c.Name,
+		Description:      c.Description,
+		SubscribersCount: c.SubscribersCount,
+		CreatedAt:        int64(c.CreatedAt),
+	}
+}
+
+type PostViewsDTO struct {
+	PostID int64
+	Views  int64
+}
@@ -13,6 +13,22 @@ func UserIDs(users []uuid.UUID) []domain.UserID {
 	return res
 }
 
+func updateIDPtr(id *domain.UpdateID) *int64 {
+	if id == nil {
+		return nil
+	}
+	cp := int64(*id)
+	return &cp
+}
+
+func timestampPtr(ts *domain.Timestamp) *int64 {
+	if ts == nil {
+		return nil
+	}
+	cp := int64(*ts)
+	return &cp
+}
+
 func UUIDs(users []domain.UserID) []uuid.UUID {
 	res := make([]uuid.UUID, len(users))
 	for i, u := range users {
@@ -26,9 +26,10 @@ func NewFileMetaDTO(f *domain.FileMeta) FileMetaDTO {
 }
 
 type FileMessageDTO struct {
-	ChatID   uuid.UUID
-	UpdateID int64
-	SenderID uuid.UUID
+	ChatID       uuid.UUID
+	UpdateID     int64
+	SenderID     uuid.UUID
+	ThreadRootID *int64
 
 	File    FileMetaDTO
 	ReplyTo *int64
@@ -44,11 +45,12 @@ func NewFileMessageDTO(m *domain.FileMessage) FileMessageDTO {
 	}
 
 	return FileMessageDTO{
-		ChatID:    uuid.UUID(m.ChatID),
-		UpdateID:  int64(m.UpdateID),
-		SenderID:  uuid.UUID(m.SenderID),
-		File:      NewFileMetaDTO(&m.File),
-		ReplyTo:   replyTo,
-		CreatedAt: int64(m.CreatedAt),
+		ChatID:       uuid.UUID(m.ChatID),
+		UpdateID:     int64(m.UpdateID),
+		SenderID:     uuid.UUID(m.SenderID),
+		ThreadRootID: updateIDPtr(m.ThreadRootID),
+		File:         NewFileMetaDTO(&m.File),
+		ReplyTo:      replyTo,
+		CreatedAt:    int64(m.CreatedAt),
 	}
 }
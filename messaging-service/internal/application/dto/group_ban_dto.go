@@ -0,0 +1,40 @@
+package dto
+
+import (
+	"github.com/chakchat/chakchat-backend/messaging-service/internal/domain/group"
+	"github.com/google/uuid"
+)
+
+type GroupBanDTO struct {
+	ChatID    uuid.UUID
+	UserID    uuid.UUID
+	BannedBy  uuid.UUID
+	Reason    string
+	ExpiresAt *int64
+	CreatedAt int64
+}
+
+func NewGroupBanDTO(b *group.Ban) GroupBanDTO {
+	return GroupBanDTO{
+		ChatID:    uuid.UUID(b.ChatID),
+		UserID:    uuid.UUID(b.UserID),
+		BannedBy:  uuid.UUID(b.BannedBy),
+		Reason:    b.Reason,
+		ExpiresAt: timestampPtr(b.ExpiresAt),
+		CreatedAt: int64(b.CreatedAt),
+	}
+}
+
+func NewGroupBanDTOs(bans []*group.Ban) []GroupBanDTO {
+	res := make([]GroupBanDTO, len(bans))
+	for i, b := range bans {
+		res[i] = NewGroupBanDTO(b)
+	}
+	return res
+}
+
+type GroupBanPageDTO struct {
+	Bans []GroupBanDTO
+	// nil if there are no more bans
+	NextOffset *int
+}
@@ -1,29 +1,42 @@
 package dto
 
 import (
+	"time"
+
 	"github.com/chakchat/chakchat-backend/messaging-service/internal/domain/group"
 	"github.com/google/uuid"
 )
 
 type GroupChatDTO struct {
-	ID      uuid.UUID
-	Admin   uuid.UUID
-	Members []uuid.UUID
+	ID                uuid.UUID
+	Owner             uuid.UUID
+	Admins            []GroupAdminDTO
+	MemberPermissions []string
+	Members           []uuid.UUID
 
 	Name        string
 	Description string
 	GroupPhoto  string
 	CreatedAt   int64
+
+	SlowModeSeconds int
+	AdminsOnly      bool
+	HistoryHidden   bool
 }
 
 func NewGroupChatDTO(g *group.GroupChat) GroupChatDTO {
 	return GroupChatDTO{
-		ID:          uuid.UUID(g.ID),
-		Admin:       uuid.UUID(g.Admin),
-		Members:     UUIDs(g.Members),
-		Name:        g.Name,
-		Description: g.Description,
-		GroupPhoto:  string(g.GroupPhoto),
-		CreatedAt:   int64(g.CreatedAt),
+		ID:                uuid.UUID(g.ID),
+		Owner:             uuid.UUID(g.Owner),
+		Admins:            NewGroupAdminDTOs(g.Admins),
+		MemberPermissions: g.MemberPermissions.Names(),
+		Members:           UUIDs(g.Members),
+		Name:              g.Name,
+		Description:       g.Description,
+		GroupPhoto:        string(g.GroupPhoto),
+		CreatedAt:         int64(g.CreatedAt),
+		SlowModeSeconds:   int(g.SlowMode / time.Second),
+		AdminsOnly:        g.AdminsOnly,
+		HistoryHidden:     g.HistoryHidden,
 	}
 }
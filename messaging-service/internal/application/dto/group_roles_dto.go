@@ -0,0 +1,29 @@
+package dto
+
+import (
+	"bytes"
+	"slices"
+
+	"github.com/chakchat/chakchat-backend/messaging-service/internal/domain"
+	"github.com/google/uuid"
+)
+
+type GroupAdminDTO struct {
+	UserID      uuid.UUID
+	Permissions []string
+}
+
+// NewGroupAdminDTOs returns admins ordered by user id
+func NewGroupAdminDTOs(admins map[domain.UserID]domain.AdminPermissions) []GroupAdminDTO {
+	res := make([]GroupAdminDTO, 0, len(admins))
+	for userID, perms := range admins {
+		res = append(res, GroupAdminDTO{
+			UserID:      uuid.UUID(userID),
+			Permissions: perms.Names(),
+		})
+	}
+	slices.SortFunc(res, func(a, b GroupAdminDTO) int {
+		return bytes.Compare(a.UserID[:], b.UserID[:])
+	})
+	return res
+}
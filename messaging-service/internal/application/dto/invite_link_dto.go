@@ -0,0 +1,66 @@
+package dto
+
+import (
+	"github.com/chakchat/chakchat-backend/messaging-service/internal/domain/group"
+	"github.com/google/uuid"
+)
+
+type InviteLinkDTO struct {
+	Token     string
+	ChatID    uuid.UUID
+	CreatorID uuid.UUID
+
+	ExpiresAt        *int64
+	UsageLimit       int
+	UsageCount       int
+	RequiresApproval bool
+	Revoked          bool
+
+	CreatedAt int64
+}
+
+func NewInviteLinkDTO(l *group.InviteLink) InviteLinkDTO {
+	return InviteLinkDTO{
+		Token:            l.Token,
+		ChatID:           uuid.UUID(l.ChatID),
+		CreatorID:        uuid.UUID(l.CreatorID),
+		ExpiresAt:        timestampPtr(l.ExpiresAt),
+		UsageLimit:       l.UsageLimit,
+		UsageCount:       l.UsageCount,
+		RequiresApproval: l.RequiresApproval,
+		Revoked:          l.Revoked,
+		CreatedAt:        int64(l.CreatedAt),
+	}
+}
+
+func NewInviteLinkDTOs(links []*group.InviteLink) []InviteLinkDTO {
+	res := make([]InviteLinkDTO, len(links))
+	for i, l := range links {
+		res[i] = NewInviteLinkDTO(l)
+	}
+	return res
+}
+
+type JoinRequestDTO struct {
+	ChatID    uuid.UUID
+	UserID    uuid.UUID
+	Token     string
+	CreatedAt int64
+}
+
+func NewJoinRequestDTO(r *group.JoinRequest) JoinRequestDTO {
+	return JoinRequestDTO{
+		ChatID:    uuid.UUID(r.ChatID),
+		UserID:    uuid.UUID(r.UserID),
+		Token:     r.Token,
+		CreatedAt: int64(r.CreatedAt),
+	}
+}
+
+func NewJoinRequestDTOs(reqs []*group.JoinRequest) []JoinRequestDTO {
+	res := make([]JoinRequestDTO, len(reqs))
+	for i, r := range reqs {
+		res[i] = NewJoinRequestDTO(r)
+	}
+	return res
+}
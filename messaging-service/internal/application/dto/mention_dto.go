@@ -0,0 +1,32 @@
+package dto
+
+import (
+	"github.com/chakchat/chakchat-backend/messaging-service/internal/domain"
+	"github.com/google/uuid"
+)
+
+type MentionDTO struct {
+	Offset int
+	Length int
+	// Nil if everyone is mentioned
+	UserID *uuid.UUID
+}
+
+func NewMentionDTOs(mentions []domain.Mention) []MentionDTO {
+	if len(mentions) == 0 {
+		return nil
+	}
+
+	res := make([]MentionDTO, len(mentions))
+	for i, m := range mentions {
+		res[i] = MentionDTO{
+			Offset: m.Offset,
+			Length: m.Length,
+		}
+		if !m.IsEveryone() {
+			id := uuid.UUID(*m.UserID)
+			res[i].UserID = &id
+		}
+	}
+	return res
+}
@@ -0,0 +1,27 @@
+package dto
+
+import (
+	"github.com/chakchat/chakchat-backend/messaging-service/internal/domain"
+	"github.com/google/uuid"
+)
+
+type PinDTO struct {
+	UpdateID int64
+	ChatID   uuid.UUID
+	SenderID uuid.UUID
+
+	CreatedAt int64
+	MessageID int64
+	Pinned    bool
+}
+
+func NewPinDTO(p *domain.Pin) PinDTO {
+	return PinDTO{
+		UpdateID:  int64(p.UpdateID),
+		ChatID:    uuid.UUID(p.ChatID),
+		SenderID:  uuid.UUID(p.SenderID),
+		CreatedAt: int64(p.CreatedAt),
+		MessageID: int64(p.MessageID),
+		Pinned:    p.Pinned,
+	}
+}
@@ -0,0 +1,66 @@
+package dto
+
+import (
+	"github.com/chakchat/chakchat-backend/messaging-service/internal/domain"
+	"github.com/google/uuid"
+)
+
+type PollDTO struct {
+	ChatID       uuid.UUID
+	UpdateID     int64
+	SenderID     uuid.UUID
+	ThreadRootID *int64
+
+	Question       string
+	Options        []PollOptionDTO
+	MultipleChoice bool
+	Anonymous      bool
+	ClosesAt       *int64
+	Closed         bool
+	TotalVoters    int
+	ReplyTo        *int64
+
+	CreatedAt int64
+}
+
+type PollOptionDTO struct {
+	Text  string
+	Votes int
+	// Always nil for anonymous polls
+	Voters []uuid.UUID
+}
+
+func NewPollDTO(p *domain.Poll) PollDTO {
+	options := make([]PollOptionDTO, len(p.Options))
+	for i, option := range p.Options {
+		options[i] = PollOptionDTO{
+			Text:  option.Text,
+			Votes: len(option.Voters),
+		}
+		if !p.Anonymous {
+			options[i].Voters = UUIDs(option.Voters)
+		}
+	}
+
+	var closesAt *int64
+	if p.ClosesAt != nil {
+		cp := int64(*p.ClosesAt)
+		closesAt = &cp
+	}
+
+	return PollDTO{
+		ChatID:         uuid.UUID(p.ChatID),
+		UpdateID:       int64(p.UpdateID),
+		SenderID:       uuid.UUID(p.SenderID),
+		ThreadRootID:   updateIDPtr(p.ThreadRootID),
+		Question:       p.Question,
+		Options:        options,
+		MultipleChoice: p.MultipleChoice,
+		Anonymous:      p.Anonymous,
+		ClosesAt:       closesAt,
+		Closed:         p.IsClosed(),
+		TotalVoters:    p.VotersCount(),
+		ReplyTo:        updateIDPtr(p.ReplyTo),
+		CreatedAt:      int64(p.CreatedAt),
+	}
+}
@@ -6,9 +6,10 @@ import (
 )
 
 type ReactionDTO struct {
-	UpdateID int64
-	ChatID   uuid.UUID
-	SenderID uuid.UUID
+	UpdateID     int64
+	ChatID       uuid.UUID
+	SenderID     uuid.UUID
+	ThreadRootID *int64
 
 	CreatedAt    int64
 	MessageID    int64
@@ -20,6 +21,7 @@ func NewReactionDTO(r *domain.Reaction) ReactionDTO {
 		UpdateID:     int64(r.UpdateID),
 		ChatID:       uuid.UUID(r.ChatID),
 		SenderID:     uuid.UUID(r.SenderID),
+		ThreadRootID: updateIDPtr(r.ThreadRootID),
 		CreatedAt:    int64(r.CreatedAt),
 		MessageID:    int64(r.MessageID),
 		ReactionType: string(r.Type),
@@ -0,0 +1,42 @@
+package dto
+
+import (
+	"github.com/chakchat/chakchat-backend/messaging-service/internal/domain"
+	"github.com/google/uuid"
+)
+
+type ScheduledMessageDTO struct {
+	ID       int64
+	ChatID   uuid.UUID
+	SenderID uuid.UUID
+
+	Text     string
+	Entities []TextEntityDTO
+	FileID   *uuid.UUID
+	ReplyTo  *int64
+
+	SendAt    int64
+	CreatedAt int64
+}
+
+func NewScheduledMessageDTO(m *domain.ScheduledMessage) ScheduledMessageDTO {
+	return ScheduledMessageDTO{
+		ID:        int64(m.ID),
+		ChatID:    uuid.UUID(m.ChatID),
+		SenderID:  uuid.UUID(m.SenderID),
+		Text:      m.Text,
+		Entities:  NewTextEntityDTOs(m.Entities),
+		FileID:    m.FileID,
+		ReplyTo:   updateIDPtr(m.ReplyTo),
+		SendAt:    int64(m.SendAt),
+		CreatedAt: int64(m.CreatedAt),
+	}
+}
+
+func NewScheduledMessageDTOs(msgs []*domain.ScheduledMessage) []ScheduledMessageDTO {
+	res := make([]ScheduledMessageDTO, len(msgs))
+	for i, m := range msgs {
+		res[i] = NewScheduledMessageDTO(m)
+	}
+	return res
+}
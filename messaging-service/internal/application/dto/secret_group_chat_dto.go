@@ -11,8 +11,10 @@ type SecretGroupChatDTO struct {
 	ID        uuid.UUID
 	CreatedAt int64
 
-	Admin   uuid.UUID
-	Members []uuid.UUID
+	Owner             uuid.UUID
+	Admins            []GroupAdminDTO
+	MemberPermissions []string
+	Members           []uuid.UUID
 
 	Name          string
 	Description   string
@@ -23,13 +25,15 @@ type SecretGroupChatDTO struct {
 
 func NewSecretGroupChatDTO(g *secgroup.SecretGroupChat) SecretGroupChatDTO {
 	return SecretGroupChatDTO{
-		ID:            uuid.UUID(g.ID),
-		CreatedAt:     int64(g.CreatedAt),
-		Admin:         uuid.UUID(g.Admin),
-		Members:       UUIDs(g.Members),
-		Name:          g.Name,
-		Description:   g.Description,
-		GroupPhotoURL: string(g.GroupPhoto),
-		Expiration:    g.Exp,
+		ID:                uuid.UUID(g.ID),
+		CreatedAt:         int64(g.CreatedAt),
+		Owner:             uuid.UUID(g.Owner),
+		Admins:            NewGroupAdminDTOs(g.Admins),
+		MemberPermissions: g.MemberPermissions.Names(),
+		Members:           UUIDs(g.Members),
+		Name:              g.Name,
+		Description:       g.Description,
+		GroupPhotoURL:     string(g.GroupPhoto),
+		Expiration:        g.Exp,
 	}
 }
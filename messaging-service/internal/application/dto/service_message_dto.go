@@ -0,0 +1,42 @@
+package dto
+
+import (
+	"github.com/chakchat/chakchat-backend/messaging-service/internal/domain"
+	"github.com/google/uuid"
+)
+
+type ServiceMessageDTO struct {
+	UpdateID   int64
+	ChatID     uuid.UUID
+	SenderID   uuid.UUID
+	UpdateType string
+	CreatedAt  int64
+
+	Members     []uuid.UUID
+	Name        string
+	Description string
+	Photo       string
+	// In seconds
+	Expiration *int64
+}
+
+func NewServiceMessageDTO(m *domain.ServiceMessage) ServiceMessageDTO {
+	var exp *int64
+	if m.Expiration != nil {
+		cp := int64(m.Expiration.Seconds())
+		exp = &cp
+	}
+
+	return ServiceMessageDTO{
+		UpdateID:    int64(m.UpdateID),
+		ChatID:      uuid.UUID(m.ChatID),
+		SenderID:    uuid.UUID(m.SenderID),
+		UpdateType:  m.Type,
+		CreatedAt:   int64(m.CreatedAt),
+		Members:     UUIDs(m.Members),
+		Name:        m.Name,
+		Description: m.Description,
+		Photo:       string(m.Photo),
+		Expiration:  exp,
+	}
+}
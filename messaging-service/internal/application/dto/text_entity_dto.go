@@ -0,0 +1,27 @@
+package dto
+
+import "github.com/chakchat/chakchat-backend/messaging-service/internal/domain"
+
+type TextEntityDTO struct {
+	Type   string
+	Offset int
+	Length int
+	URL    string
+}
+
+func NewTextEntityDTOs(entities []domain.TextEntity) []TextEntityDTO {
+	if len(entities) == 0 {
+		return nil
+	}
+
+	res := make([]TextEntityDTO, len(entities))
+	for i, e := range entities {
+		res[i] = TextEntityDTO{
+			Type:   string(e.Type),
+			Offset: e.Offset,
+			Length: e.Length,
+			URL:    e.URL,
+		}
+	}
+	return res
+}
@@ -6,13 +6,16 @@ import (
 )
 
 type TextMessageDTO struct {
-	ChatID   uuid.UUID
-	UpdateID int64
-	SenderID uuid.UUID
+	ChatID       uuid.UUID
+	UpdateID     int64
+	SenderID     uuid.UUID
+	ThreadRootID *int64
 
-	Text    string
-	Edited  *TextMessageEditedDTO
-	ReplyTo *int64
+	Text     string
+	Entities []TextEntityDTO
+	Mentions []MentionDTO
+	Edited   *TextMessageEditedDTO
+	ReplyTo  *int64
 
 	CreatedAt int64
 }
@@ -31,12 +34,15 @@ func NewTextMessageDTO(m *domain.TextMessage) TextMessageDTO {
 	}
 
 	return TextMessageDTO{
-		ChatID:    uuid.UUID(m.ChatID),
-		UpdateID:  int64(m.UpdateID),
-		SenderID:  uuid.UUID(m.SenderID),
-		Text:      m.Text,
-		Edited:    edited,
-		ReplyTo:   replyTo,
-		CreatedAt: int64(m.CreatedAt),
+		ChatID:       uuid.UUID(m.ChatID),
+		UpdateID:     int64(m.UpdateID),
+		SenderID:     uuid.UUID(m.SenderID),
+		ThreadRootID: updateIDPtr(m.ThreadRootID),
+		Text:         m.Text,
+		Entities:     NewTextEntityDTOs(m.Entities),
+		Mentions:     NewMentionDTOs(m.Mentions),
+		Edited:       edited,
+		ReplyTo:      replyTo,
+		CreatedAt:    int64(m.CreatedAt),
 	}
 }
@@ -6,23 +6,27 @@ import (
 )
 
 type TextMessageEditedDTO struct {
-	ChatID   uuid.UUID
-	UpdateID int64
-	SenderID uuid.UUID
+	ChatID       uuid.UUID
+	UpdateID     int64
+	SenderID     uuid.UUID
+	ThreadRootID *int64
 
-	MessageID int64
-	NewText   string
+	MessageID   int64
+	NewText     string
+	NewEntities []TextEntityDTO
 
 	CreatedAt int64
 }
 
 func NewTextMessageEditedDTO(dom *domain.TextMessageEdited) TextMessageEditedDTO {
 	return TextMessageEditedDTO{
-		ChatID:    uuid.UUID(dom.ChatID),
-		UpdateID:  int64(dom.UpdateID),
-		SenderID:  uuid.UUID(dom.SenderID),
-		MessageID: int64(dom.MessageID),
-		NewText:   dom.NewText,
-		CreatedAt: int64(dom.CreatedAt),
+		ChatID:       uuid.UUID(dom.ChatID),
+		UpdateID:     int64(dom.UpdateID),
+		SenderID:     uuid.UUID(dom.SenderID),
+		ThreadRootID: updateIDPtr(dom.ThreadRootID),
+		MessageID:    int64(dom.MessageID),
+		NewText:      dom.NewText,
+		NewEntities:  NewTextEntityDTOs(dom.NewEntities),
+		CreatedAt:    int64(dom.CreatedAt),
 	}
 }
@@ -0,0 +1,32 @@
+package dto
+
+import (
+	"github.com/chakchat/chakchat-backend/messaging-service/internal/domain/group"
+	"github.com/google/uuid"
+)
+
+type TopicDTO struct {
+	TopicID   uuid.UUID
+	ChatID    uuid.UUID
+	Title     string
+	Icon      string
+	CreatorID uuid.UUID
+	Closed    bool
+	CreatedAt int64
+
+	// Set only when topics are listed for a member
+	LastUpdateID *int64
+	UnreadCount  *int
+}
+
+func NewTopicDTO(t *group.Topic) TopicDTO {
+	return TopicDTO{
+		TopicID:   uuid.UUID(t.ID),
+		ChatID:    uuid.UUID(t.Group.ID),
+		Title:     t.Title,
+		Icon:      t.Icon,
+		CreatorID: uuid.UUID(t.CreatorID),
+		Closed:    t.Closed,
+		CreatedAt: int64(t.CreatedAt),
+	}
+}
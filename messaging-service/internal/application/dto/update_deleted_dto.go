@@ -6,9 +6,10 @@ import (
 )
 
 type UpdateDeletedDTO struct {
-	ChatID   uuid.UUID
-	UpdateID int64
-	SenderID uuid.UUID
+	ChatID       uuid.UUID
+	UpdateID     int64
+	SenderID     uuid.UUID
+	ThreadRootID *int64
 
 	DeletedID  int64
 	DeleteMode string
@@ -18,11 +19,12 @@ type UpdateDeletedDTO struct {
 
 func NewUpdateDeletedDTO(dom *domain.UpdateDeleted) UpdateDeletedDTO {
 	return UpdateDeletedDTO{
-		ChatID:     uuid.UUID(dom.ChatID),
-		UpdateID:   int64(dom.UpdateID),
-		SenderID:   uuid.UUID(dom.SenderID),
-		DeletedID:  int64(dom.DeletedID),
-		DeleteMode: string(dom.Mode),
-		CreatedAt:  int64(dom.CreatedAt),
+		ChatID:       uuid.UUID(dom.ChatID),
+		UpdateID:     int64(dom.UpdateID),
+		SenderID:     uuid.UUID(dom.SenderID),
+		ThreadRootID: updateIDPtr(dom.ThreadRootID),
+		DeletedID:    int64(dom.DeletedID),
+		DeleteMode:   string(dom.Mode),
+		CreatedAt:    int64(dom.CreatedAt),
 	}
 }
@@ -0,0 +1,12 @@
+package external
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+type UserService interface {
+	// Returns ids of existing users. Unknown usernames are omitted.
+	GetUserIDsByUsernames(ctx context.Context, usernames []string) (map[string]uuid.UUID, error)
+}
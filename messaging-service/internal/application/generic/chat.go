@@ -19,6 +19,12 @@ type Chat struct {
 	// Last update ID in the chat.
 	// Be careful, it may hold even ID of update not visible for user (e.g. deleted)
 	LastUpdateID *int64 `json:"last_update_id,omitempty"`
+	// IDs of pinned messages in order of pinning.
+	// Secret chats have no pinned messages.
+	PinnedUpdateIDs []int64 `json:"pinned_update_ids,omitempty"`
+	// Number of unread messages mentioning the requesting member.
+	// Only group chats have mentions.
+	UnreadMentions int `json:"unread_mentions,omitempty"`
 	// Holds last updates to show chat preview in the client.
 	// Not fetched by default.
 	UpdatePreview []Update `json:"update_preview,omitempty"`
@@ -29,6 +35,7 @@ type ChatInfo struct {
 	Group          *GroupInfo
 	SecretPersonal *SecretPersonalInfo
 	SecretGroup    *SecretGroupInfo
+	Channel        *ChannelInfo
 }
 
 func (c ChatInfo) MarshalJSON() ([]byte, error) {
@@ -41,6 +48,8 @@ func (c ChatInfo) MarshalJSON() ([]byte, error) {
 		return json.Marshal(c.SecretPersonal)
 	case c.SecretGroup != nil:
 		return json.Marshal(c.SecretGroup)
+	case c.Channel != nil:
+		return json.Marshal(c.Channel)
 	default:
 		return nil, nil
 	}
@@ -51,10 +60,34 @@ type PersonalInfo struct {
 }
 
 type GroupInfo struct {
-	AdminID     uuid.UUID `json:"admin_id"`
-	Name        string    `json:"name"`
-	Description string    `json:"description"`
-	GroupPhoto  string    `json:"group_photo"`
+	// Group owner. It is named admin_id for backward compatibility
+	AdminID           uuid.UUID    `json:"admin_id"`
+	Admins            []GroupAdmin `json:"admins"`
+	MemberPermissions []string     `json:"member_permissions"`
+	Name              string       `json:"name"`
+	Description       string       `json:"description"`
+	GroupPhoto        string       `json:"group_photo"`
+	// Zero means slow mode is off
+	SlowModeSeconds int  `json:"slow_mode_seconds"`
+	AdminsOnly      bool `json:"admins_only"`
+	// New members see only updates since they joined
+	HistoryHidden bool `json:"history_hidden"`
+}
+
+type GroupAdmin struct {
+	UserID      uuid.UUID `json:"user_id"`
+	Permissions []string  `json:"permissions"`
+}
+
+func FromGroupAdminDTOs(admins []dto.GroupAdminDTO) []GroupAdmin {
+	res := make([]GroupAdmin, len(admins))
+	for i, a := range admins {
+		res[i] = GroupAdmin{
+			UserID:      a.UserID,
+			Permissions: a.Permissions,
+		}
+	}
+	return res
 }
 
 type SecretPersonalInfo struct {
@@ -62,11 +95,24 @@ type SecretPersonalInfo struct {
 }
 
 type SecretGroupInfo struct {
-	AdminID     uuid.UUID      `json:"admin_id"`
-	Name        string         `json:"name"`
-	Description string         `json:"description"`
-	GroupPhoto  string         `json:"group_photo"`
-	Expiration  *time.Duration `json:"expiration"`
+	// Group owner. It is named admin_id for backward compatibility
+	AdminID           uuid.UUID      `json:"admin_id"`
+	Admins            []GroupAdmin   `json:"admins"`
+	MemberPermissions []string       `json:"member_permissions"`
+	Name              string         `json:"name"`
+	Description       string         `json:"description"`
+	GroupPhoto        string         `json:"group_photo"`
+	Expiration        *time.Duration `json:"expiration"`
+}
+
+// ChannelInfo is the info of a channel.
+// Members of a channel are not listed because there may be millions of subscribers.
+type ChannelInfo struct {
+	OwnerID          uuid.UUID    `json:"owner_id"`
+	Admins           []GroupAdmin `json:"admins"`
+	Name             string       `json:"name"`
+	Description      string       `json:"description"`
+	SubscribersCount int64        `json:"subscribers_count"`
 }
 
 func FromPersonalChatDTO(chatDTO *dto.PersonalChatDTO) Chat {
@@ -93,10 +139,15 @@ func FromGroupChatDTO(chatDTO *dto.GroupChatDTO) Chat {
 		Members:   chatDTO.Members,
 		Info: ChatInfo{
 			Group: &GroupInfo{
-				AdminID:     chatDTO.Admin,
-				Name:        chatDTO.Name,
-				Description: chatDTO.Description,
-				GroupPhoto:  chatDTO.GroupPhoto,
+				AdminID:           chatDTO.Owner,
+				Admins:            FromGroupAdminDTOs(chatDTO.Admins),
+				MemberPermissions: chatDTO.MemberPermissions,
+				Name:              chatDTO.Name,
+				Description:       chatDTO.Description,
+				GroupPhoto:        chatDTO.GroupPhoto,
+				SlowModeSeconds:   chatDTO.SlowModeSeconds,
+				AdminsOnly:        chatDTO.AdminsOnly,
+				HistoryHidden:     chatDTO.HistoryHidden,
 			},
 		},
 		LastUpdateID:  nil,
@@ -128,14 +179,52 @@ func FromSecretGroupChatDTO(chatDTO *dto.SecretGroupChatDTO) Chat {
 		Members:   chatDTO.Members,
 		Info: ChatInfo{
 			SecretGroup: &SecretGroupInfo{
-				AdminID:     chatDTO.Admin,
-				Name:        chatDTO.Name,
-				Description: chatDTO.Description,
-				GroupPhoto:  chatDTO.GroupPhotoURL,
-				Expiration:  chatDTO.Expiration,
+				AdminID:           chatDTO.Owner,
+				Admins:            FromGroupAdminDTOs(chatDTO.Admins),
+				MemberPermissions: chatDTO.MemberPermissions,
+				Name:              chatDTO.Name,
+				Description:       chatDTO.Description,
+				GroupPhoto:        chatDTO.GroupPhotoURL,
+				Expiration:        chatDTO.Expiration,
 			},
 		},
 		LastUpdateID:  nil,
 		UpdatePreview: nil,
 	}
 }
+
+func FromChannelDTO(chatDTO *dto.ChannelDTO) Chat {
+	return Chat{
+		ChatID:    chatDTO.ID,
+		CreatedAt: chatDTO.CreatedAt,
+		Type:      domain.ChatTypeChannel,
+		Members:   nil,
+		Info: ChatInfo{
+			Channel: &ChannelInfo{
+				OwnerID:          chatDTO.Owner,
+				Admins:           FromGroupAdminDTOs(chatDTO.Admins),
+				Name:             chatDTO.Name,
+				Description:      chatDTO.Description,
+				SubscribersCount: chatDTO.SubscribersCount,
+			},
+		},
+		LastUpdateID:  nil,
+		UpdatePreview: nil,
+	}
+}
+
+type PostViews struct {
+	PostID int64 `json:"post_id"`
+	Views  int64 `json:"views"`
+}
+
+func FromPostViewsDTOs(views []dto.PostViewsDTO) []PostViews {
+	res := make([]PostViews, len(views))
+	for i, v := range views {
+		res[i] = PostViews{
+			PostID: v.PostID,
+			Views:  v.Views,
+		}
+	}
+	return res
+}
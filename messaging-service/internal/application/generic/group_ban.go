@@ -0,0 +1,42 @@
+package generic
+
+import (
+	"github.com/chakchat/chakchat-backend/messaging-service/internal/application/dto"
+	"github.com/google/uuid"
+)
+
+type GroupBan struct {
+	ChatID    uuid.UUID `json:"chat_id"`
+	UserID    uuid.UUID `json:"user_id"`
+	BannedBy  uuid.UUID `json:"banned_by"`
+	Reason    string    `json:"reason,omitempty"`
+	ExpiresAt *int64    `json:"expires_at,omitempty"`
+	CreatedAt int64     `json:"created_at"`
+}
+
+type GroupBanPage struct {
+	Bans       []GroupBan `json:"bans"`
+	NextOffset *int       `json:"next_offset,omitempty"`
+}
+
+func FromGroupBanDTO(b *dto.GroupBanDTO) GroupBan {
+	return GroupBan{
+		ChatID:    b.ChatID,
+		UserID:    b.UserID,
+		BannedBy:  b.BannedBy,
+		Reason:    b.Reason,
+		ExpiresAt: b.ExpiresAt,
+		CreatedAt: b.CreatedAt,
+	}
+}
+
+func FromGroupBanPageDTO(p *dto.GroupBanPageDTO) GroupBanPage {
+	bans := make([]GroupBan, len(p.Bans))
+	for i := range p.Bans {
+		bans[i] = FromGroupBanDTO(&p.Bans[i])
+	}
+	return GroupBanPage{
+		Bans:       bans,
+		NextOffset: p.NextOffset,
+	}
+}
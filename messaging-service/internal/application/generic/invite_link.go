@@ -0,0 +1,67 @@
+package generic
+
+import (
+	"github.com/chakchat/chakchat-backend/messaging-service/internal/application/dto"
+	"github.com/google/uuid"
+)
+
+type InviteLink struct {
+	Token     string    `json:"token"`
+	ChatID    uuid.UUID `json:"chat_id"`
+	CreatorID uuid.UUID `json:"creator_id"`
+
+	ExpiresAt *int64 `json:"expires_at,omitempty"`
+	// 0 means unlimited
+	UsageLimit       int  `json:"usage_limit"`
+	UsageCount       int  `json:"usage_count"`
+	RequiresApproval bool `json:"requires_approval"`
+	Revoked          bool `json:"revoked"`
+
+	CreatedAt int64 `json:"created_at"`
+}
+
+func FromInviteLinkDTO(l *dto.InviteLinkDTO) InviteLink {
+	return InviteLink{
+		Token:            l.Token,
+		ChatID:           l.ChatID,
+		CreatorID:        l.CreatorID,
+		ExpiresAt:        l.ExpiresAt,
+		UsageLimit:       l.UsageLimit,
+		UsageCount:       l.UsageCount,
+		RequiresApproval: l.RequiresApproval,
+		Revoked:          l.Revoked,
+		CreatedAt:        l.CreatedAt,
+	}
+}
+
+func FromInviteLinkDTOs(links []dto.InviteLinkDTO) []InviteLink {
+	res := make([]InviteLink, len(links))
+	for i := range links {
+		res[i] = FromInviteLinkDTO(&links[i])
+	}
+	return res
+}
+
+type JoinRequest struct {
+	ChatID    uuid.UUID `json:"chat_id"`
+	UserID    uuid.UUID `json:"user_id"`
+	Token     string    `json:"token"`
+	CreatedAt int64     `json:"created_at"`
+}
+
+func FromJoinRequestDTO(r *dto.JoinRequestDTO) JoinRequest {
+	return JoinRequest{
+		ChatID:    r.ChatID,
+		UserID:    r.UserID,
+		Token:     r.Token,
+		CreatedAt: r.CreatedAt,
+	}
+}
+
+func FromJoinRequestDTOs(reqs []dto.JoinRequestDTO) []JoinRequest {
+	res := make([]JoinRequest, len(reqs))
+	for i := range reqs {
+		res[i] = FromJoinRequestDTO(&reqs[i])
+	}
+	return res
+}
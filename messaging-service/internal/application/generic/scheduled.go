@@ -0,0 +1,51 @@
+package generic
+
+import (
+	"github.com/chakchat/chakchat-backend/messaging-service/internal/application/dto"
+	"github.com/chakchat/chakchat-backend/messaging-service/internal/domain"
+	"github.com/google/uuid"
+)
+
+// ScheduledMessage is visible only to its sender until it is sent
+type ScheduledMessage struct {
+	ScheduledID int64     `json:"scheduled_id"`
+	ChatID      uuid.UUID `json:"chat_id"`
+	SenderID    uuid.UUID `json:"sender_id"`
+	// "text_message" or "file_message"
+	Type     string              `json:"type"`
+	Text     string              `json:"text,omitempty"`
+	Entities []TextEntityContent `json:"entities,omitempty"`
+	FileID   *uuid.UUID          `json:"file_id,omitempty"`
+	ReplyTo  *int64              `json:"reply_to,omitempty"`
+
+	SendAt    int64 `json:"send_at"`
+	CreatedAt int64 `json:"created_at"`
+}
+
+func FromScheduledMessageDTO(m *dto.ScheduledMessageDTO) ScheduledMessage {
+	msgType := domain.UpdateTypeTextMessage
+	if m.FileID != nil {
+		msgType = domain.UpdateTypeFileMessage
+	}
+
+	return ScheduledMessage{
+		ScheduledID: m.ID,
+		ChatID:      m.ChatID,
+		SenderID:    m.SenderID,
+		Type:        msgType,
+		Text:        m.Text,
+		Entities:    FromTextEntityDTOs(m.Entities),
+		FileID:      m.FileID,
+		ReplyTo:     m.ReplyTo,
+		SendAt:      m.SendAt,
+		CreatedAt:   m.CreatedAt,
+	}
+}
+
+func FromScheduledMessageDTOs(msgs []dto.ScheduledMessageDTO) []ScheduledMessage {
+	res := make([]ScheduledMessage, len(msgs))
+	for i := range msgs {
+		res[i] = FromScheduledMessageDTO(&msgs[i])
+	}
+	return res
+}
@@ -0,0 +1,92 @@
+package generic
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+type SearchResult struct {
+	Messages []FoundMessage `json:"messages"`
+	// Nil if there are no more messages
+	NextOffset *int `json:"next_offset,omitempty"`
+}
+
+// FoundMessage is a message matched by search.
+type FoundMessage struct {
+	Update
+	// Set if the message is posted in a topic of the group chat
+	TopicID *uuid.UUID `json:"topic_id,omitempty"`
+	// Matched fragments of the message text sorted by offset
+	Highlights []Highlight `json:"highlights"`
+}
+
+// Highlight is counted in runes of the message text.
+type Highlight struct {
+	Offset int `json:"offset"`
+	Length int `json:"length"`
+}
+
+// ParseHighlights removes selection marks from the text and returns the marked fragments.
+func ParseHighlights(marked string, startSel, stopSel rune) (string, []Highlight) {
+	var (
+		text       strings.Builder
+		highlights []Highlight
+		offset     int
+		start      = -1
+	)
+	for _, r := range marked {
+		switch {
+		case r == startSel:
+			start = offset
+		case r == stopSel && start >= 0:
+			if offset > start {
+				highlights = append(highlights, Highlight{Offset: start, Length: offset - start})
+			}
+			start = -1
+		default:
+			text.WriteRune(r)
+			offset++
+		}
+	}
+	return text.String(), highlights
+}
+
+// SubstringHighlights returns case-insensitive occurrences of the query in the text.
+func SubstringHighlights(text, query string) []Highlight {
+	// Runes are lowered one by one so that offsets stay the same
+	textRunes := []rune(strings.ToLower(text))
+	queryRunes := []rune(strings.ToLower(query))
+	if len(queryRunes) == 0 {
+		return nil
+	}
+
+	var highlights []Highlight
+	for i := 0; i+len(queryRunes) <= len(textRunes); i++ {
+		if slices.Equal(textRunes[i:i+len(queryRunes)], queryRunes) {
+			highlights = append(highlights, Highlight{Offset: i, Length: len(queryRunes)})
+			i += len(queryRunes) - 1
+		}
+	}
+	return highlights
+}
+
+// MergeHighlights joins overlapping and adjacent highlights.
+func MergeHighlights(highlights ...[]Highlight) []Highlight {
+	all := slices.Concat(highlights...)
+	slices.SortFunc(all, func(a, b Highlight) int {
+		return a.Offset - b.Offset
+	})
+
+	merged := make([]Highlight, 0, len(all))
+	for _, h := range all {
+		if n := len(merged); n > 0 && merged[n-1].Offset+merged[n-1].Length >= h.Offset {
+			last := &merged[n-1]
+			last.Length = max(last.Length, h.Offset+h.Length-last.Offset)
+			continue
+		}
+		merged = append(merged, h)
+	}
+	return merged
+}
@@ -0,0 +1,42 @@
+package generic
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseHighlights(t *testing.T) {
+	text, highlights := ParseHighlights("Привет, [мир]! Hello [world]", '[', ']')
+
+	require.Equal(t, "Привет, мир! Hello world", text)
+	require.Equal(t, []Highlight{
+		{Offset: 8, Length: 3},
+		{Offset: 19, Length: 5},
+	}, highlights)
+}
+
+func TestSubstringHighlights(t *testing.T) {
+	highlights := SubstringHighlights("Кот и КОТЁНОК, котокот", "кот")
+
+	require.Equal(t, []Highlight{
+		{Offset: 0, Length: 3},
+		{Offset: 6, Length: 3},
+		{Offset: 15, Length: 3},
+		{Offset: 19, Length: 3},
+	}, highlights)
+	require.Nil(t, SubstringHighlights("text", ""))
+}
+
+func TestMergeHighlights(t *testing.T) {
+	merged := MergeHighlights(
+		[]Highlight{{Offset: 10, Length: 5}, {Offset: 0, Length: 3}},
+		[]Highlight{{Offset: 12, Length: 6}, {Offset: 3, Length: 2}, {Offset: 30, Length: 1}},
+	)
+
+	require.Equal(t, []Highlight{
+		{Offset: 0, Length: 5},
+		{Offset: 10, Length: 8},
+		{Offset: 30, Length: 1},
+	}, merged)
+}
@@ -0,0 +1,40 @@
+package generic
+
+import (
+	"github.com/chakchat/chakchat-backend/messaging-service/internal/application/dto"
+	"github.com/google/uuid"
+)
+
+type Topic struct {
+	TopicID      uuid.UUID `json:"topic_id"`
+	ChatID       uuid.UUID `json:"chat_id"`
+	Title        string    `json:"title"`
+	Icon         string    `json:"icon,omitempty"`
+	CreatorID    uuid.UUID `json:"creator_id"`
+	Closed       bool      `json:"closed"`
+	CreatedAt    int64     `json:"created_at"`
+	LastUpdateID *int64    `json:"last_update_id,omitempty"`
+	UnreadCount  *int      `json:"unread_count,omitempty"`
+}
+
+func FromTopicDTO(t *dto.TopicDTO) Topic {
+	return Topic{
+		TopicID:      t.TopicID,
+		ChatID:       t.ChatID,
+		Title:        t.Title,
+		Icon:         t.Icon,
+		CreatorID:    t.CreatorID,
+		Closed:       t.Closed,
+		CreatedAt:    t.CreatedAt,
+		LastUpdateID: t.LastUpdateID,
+		UnreadCount:  t.UnreadCount,
+	}
+}
+
+func FromTopicDTOs(topics []dto.TopicDTO) []Topic {
+	res := make([]Topic, len(topics))
+	for i := range topics {
+		res[i] = FromTopicDTO(&topics[i])
+	}
+	return res
+}
@@ -14,6 +14,8 @@ type Update struct {
 	ChatID     uuid.UUID `json:"chat_id"`
 	SenderID   uuid.UUID `json:"sender_id"`
 	UpdateType string    `json:"type"`
+	// Is set if the update belongs to a thread
+	ThreadRootID *int64 `json:"thread_root_id,omitempty"`
 
 	CreatedAt int64         `json:"created_at"`
 	Content   UpdateContent `json:"content"`
@@ -25,8 +27,11 @@ type UpdateContent struct {
 	TextMessageEdited *TextMessageEditedContent
 	FileMessage       *FileMessageContent
 	Deleted           *DeletedContent
-	Reaction          *ReactionContent 
+	Reaction          *ReactionContent
+	Pin               *PinContent
+	Poll              *PollContent
 	Secret            *SecretUpdateContent
+	Service           *ServiceMessageContent
 }
 
 func (c UpdateContent) MarshalJSON() ([]byte, error) {
@@ -41,29 +46,112 @@ func (c UpdateContent) MarshalJSON() ([]byte, error) {
 		return json.Marshal(c.Deleted)
 	case c.Reaction != nil:
 		return json.Marshal(c.Reaction)
+	case c.Pin != nil:
+		return json.Marshal(c.Pin)
+	case c.Poll != nil:
+		return json.Marshal(c.Poll)
 	case c.Secret != nil:
 		return json.Marshal(c.Secret)
+	case c.Service != nil:
+		return json.Marshal(c.Service)
 	default:
 		return nil, nil
 	}
 }
 
 type TextMessageContent struct {
-	Text      string   `json:"text"`
-	Edited    *Update  `json:"edited,omitempty"`
-	ReplyTo   *int64   `json:"reply_to,omitempty"`
-	Reactions []Update `json:"reactions,omitempty"`
+	Text      string              `json:"text"`
+	Entities  []TextEntityContent `json:"entities,omitempty"`
+	Mentions  []MentionContent    `json:"mentions,omitempty"`
+	Edited    *Update             `json:"edited,omitempty"`
+	ReplyTo   *int64              `json:"reply_to,omitempty"`
+	Reactions []Update            `json:"reactions,omitempty"`
+	Thread    *ThreadInfo         `json:"thread,omitempty"`
+}
+
+// TextEntityContent formats a part of the text. Offset and length are measured in runes.
+type TextEntityContent struct {
+	Type   string `json:"type"`
+	Offset int    `json:"offset"`
+	Length int    `json:"length"`
+	URL    string `json:"url,omitempty"`
+}
+
+func FromTextEntityDTOs(entities []dto.TextEntityDTO) []TextEntityContent {
+	if len(entities) == 0 {
+		return nil
+	}
+
+	res := make([]TextEntityContent, len(entities))
+	for i, e := range entities {
+		res[i] = TextEntityContent{
+			Type:   e.Type,
+			Offset: e.Offset,
+			Length: e.Length,
+			URL:    e.URL,
+		}
+	}
+	return res
+}
+
+const (
+	MentionTypeUser     = "user"
+	MentionTypeEveryone = "everyone"
+)
+
+// MentionContent refers to a part of the text. Offset and length are measured in runes.
+type MentionContent struct {
+	Offset int    `json:"offset"`
+	Length int    `json:"length"`
+	Type   string `json:"type"`
+	// Not set if everyone is mentioned
+	UserID *uuid.UUID `json:"user_id,omitempty"`
+}
+
+func NewMentionContent(offset, length int, userID *uuid.UUID) MentionContent {
+	mentionType := MentionTypeUser
+	if userID == nil {
+		mentionType = MentionTypeEveryone
+	}
+	return MentionContent{
+		Offset: offset,
+		Length: length,
+		Type:   mentionType,
+		UserID: userID,
+	}
+}
+
+func FromMentionDTOs(mentions []dto.MentionDTO) []MentionContent {
+	if len(mentions) == 0 {
+		return nil
+	}
+
+	res := make([]MentionContent, len(mentions))
+	for i, m := range mentions {
+		res[i] = NewMentionContent(m.Offset, m.Length, m.UserID)
+	}
+	return res
 }
 
 type TextMessageEditedContent struct {
-	MessageID int64  `json:"message_id"`
-	NewText   string `json:"new_text"`
+	MessageID   int64               `json:"message_id"`
+	NewText     string              `json:"new_text"`
+	NewEntities []TextEntityContent `json:"new_entities,omitempty"`
 }
 
 type FileMessageContent struct {
-	File      FileMeta `json:"file"`
-	ReplyTo   *int64   `json:"reply_to,omitempty"`
-	Reactions []Update `json:"reactions,omitempty"`
+	File      FileMeta    `json:"file"`
+	ReplyTo   *int64      `json:"reply_to,omitempty"`
+	Reactions []Update    `json:"reactions,omitempty"`
+	Thread    *ThreadInfo `json:"thread,omitempty"`
+}
+
+// ThreadInfo is set on a thread root message if the thread has replies.
+// Replies deleted for all are not counted.
+type ThreadInfo struct {
+	ReplyCount    int       `json:"reply_count"`
+	LastReplyID   int64     `json:"last_reply_id"`
+	LastReplierID uuid.UUID `json:"last_replier_id"`
 }
 
 type FileMeta struct {
@@ -85,6 +173,60 @@ type ReactionContent struct {
 	MessageID int64  `json:"message_id"`
 }
 
+type PollContent struct {
+	Question       string              `json:"question"`
+	Options        []PollOptionContent `json:"options"`
+	MultipleChoice bool                `json:"multiple_choice"`
+	Anonymous      bool                `json:"anonymous"`
+	ClosesAt       *int64              `json:"closes_at,omitempty"`
+	Closed         bool                `json:"closed"`
+	TotalVoters    int                 `json:"total_voters"`
+	ReplyTo        *int64              `json:"reply_to,omitempty"`
+	Reactions      []Update            `json:"reactions,omitempty"`
+}
+
+type PollOptionContent struct {
+	Text  string `json:"text"`
+	Votes int    `json:"votes"`
+	// Voters of anonymous polls are never revealed
+	Voters []uuid.UUID `json:"voters,omitempty"`
+}
+
+// Content of both pinned and unpinned updates
+type PinContent struct {
+	MessageID int64 `json:"message_id"`
+}
+
+// Content of service messages. Only the fields of the update type are set:
+// members on member_added and member_removed, name and description on info_changed,
+// photo on photo_changed and expiration on expiration_changed.
+type ServiceMessageContent struct {
+	Members     []uuid.UUID `json:"members,omitempty"`
+	Name        *string     `json:"name,omitempty"`
+	Description *string     `json:"description,omitempty"`
+	// Empty if the photo is deleted
+	Photo *string `json:"photo,omitempty"`
+	// In seconds. Not set on expiration_changed if expiration is turned off
+	Expiration *int64 `json:"expiration,omitempty"`
+}
+
+func NewServiceMessageContent(
+	updateType string, members []uuid.UUID, name, description, photo string, expiration *int64,
+) ServiceMessageContent {
+	var c ServiceMessageContent
+	switch updateType {
+	case domain.UpdateTypeMemberAdded, domain.UpdateTypeMemberRemoved:
+		c.Members = members
+	case domain.UpdateTypeInfoChanged:
+		c.Name, c.Description = &name, &description
+	case domain.UpdateTypePhotoChanged:
+		c.Photo = &photo
+	case domain.UpdateTypeExpirationChanged:
+		c.Expiration = expiration
+	}
+	return c
+}
+
 type SecretUpdateContent struct {
 	PayloadBase64              string `json:"payload"`
 	InitializationVectorBase64 string `json:"initialization_vector"`
@@ -99,11 +241,12 @@ func FromFileMessageDTO(msg *dto.FileMessageDTO) Update {
 	}
 
 	return Update{
-		UpdateID:   msg.UpdateID,
-		ChatID:     msg.ChatID,
-		SenderID:   msg.SenderID,
-		UpdateType: domain.UpdateTypeFileMessage,
-		CreatedAt:  msg.CreatedAt,
+		UpdateID:     msg.UpdateID,
+		ChatID:       msg.ChatID,
+		SenderID:     msg.SenderID,
+		ThreadRootID: msg.ThreadRootID,
+		UpdateType:   domain.UpdateTypeFileMessage,
+		CreatedAt:    msg.CreatedAt,
 		Content: UpdateContent{
 			FileMessage: &FileMessageContent{
 				File: FileMeta{
@@ -135,14 +278,17 @@ func FromTextMessageDTO(msg *dto.TextMessageDTO) Update {
 	}
 
 	return Update{
-		UpdateID:   msg.UpdateID,
-		ChatID:     msg.ChatID,
-		SenderID:   msg.SenderID,
-		UpdateType: domain.UpdateTypeTextMessage,
-		CreatedAt:  msg.CreatedAt,
+		UpdateID:     msg.UpdateID,
+		ChatID:       msg.ChatID,
+		SenderID:     msg.SenderID,
+		ThreadRootID: msg.ThreadRootID,
+		UpdateType:   domain.UpdateTypeTextMessage,
+		CreatedAt:    msg.CreatedAt,
 		Content: UpdateContent{
 			TextMessage: &TextMessageContent{
 				Text:      msg.Text,
+				Entities:  FromTextEntityDTOs(msg.Entities),
+				Mentions:  FromMentionDTOs(msg.Mentions),
 				Edited:    edited,
 				ReplyTo:   replyTo,
 				Reactions: nil,
@@ -153,15 +299,17 @@ func FromTextMessageDTO(msg *dto.TextMessageDTO) Update {
 
 func FromTextMessageEditedDTO(msg *dto.TextMessageEditedDTO) Update {
 	return Update{
-		UpdateID:   msg.UpdateID,
-		ChatID:     msg.ChatID,
-		SenderID:   msg.SenderID,
-		UpdateType: domain.UpdateTypeTextMessageEdited,
-		CreatedAt:  msg.CreatedAt,
+		UpdateID:     msg.UpdateID,
+		ChatID:       msg.ChatID,
+		SenderID:     msg.SenderID,
+		ThreadRootID: msg.ThreadRootID,
+		UpdateType:   domain.UpdateTypeTextMessageEdited,
+		CreatedAt:    msg.CreatedAt,
 		Content: UpdateContent{
 			TextMessageEdited: &TextMessageEditedContent{
-				MessageID: msg.MessageID,
-				NewText:   msg.NewText,
+				MessageID:   msg.MessageID,
+				NewText:     msg.NewText,
+				NewEntities: FromTextEntityDTOs(msg.NewEntities),
 			},
 		},
 	}
@@ -169,11 +317,12 @@ func FromTextMessageEditedDTO(msg *dto.TextMessageEditedDTO) Update {
 
 func FromUpdateDeletedDTO(msg *dto.UpdateDeletedDTO) Update {
 	return Update{
-		UpdateID:   msg.UpdateID,
-		ChatID:     msg.ChatID,
-		SenderID:   msg.SenderID,
-		UpdateType: domain.UpdateTypeDeleted,
-		CreatedAt:  msg.CreatedAt,
+		UpdateID:     msg.UpdateID,
+		ChatID:       msg.ChatID,
+		SenderID:     msg.SenderID,
+		ThreadRootID: msg.ThreadRootID,
+		UpdateType:   domain.UpdateTypeDeleted,
+		CreatedAt:    msg.CreatedAt,
 		Content: UpdateContent{
 			Deleted: &DeletedContent{
 				DeletedID:   msg.DeletedID,
@@ -202,11 +351,12 @@ func FromSecretUpdateDTO(msg *dto.SecretUpdateDTO) Update {
 
 func FromReactionDTO(r *dto.ReactionDTO) Update {
 	return Update{
-		UpdateID:   r.UpdateID,
-		ChatID:     r.ChatID,
-		SenderID:   r.SenderID,
-		UpdateType: domain.UpdateTypeReaction,
-		CreatedAt:  r.CreatedAt,
+		UpdateID:     r.UpdateID,
+		ChatID:       r.ChatID,
+		SenderID:     r.SenderID,
+		ThreadRootID: r.ThreadRootID,
+		UpdateType:   domain.UpdateTypeReaction,
+		CreatedAt:    r.CreatedAt,
 		Content: UpdateContent{
 			Reaction: &ReactionContent{
 				Reaction:  r.ReactionType,
@@ -215,3 +365,70 @@ func FromReactionDTO(r *dto.ReactionDTO) Update {
 		},
 	}
 }
+
+func FromPinDTO(p *dto.PinDTO) Update {
+	updateType := domain.UpdateTypeMessageUnpinned
+	if p.Pinned {
+		updateType = domain.UpdateTypeMessagePinned
+	}
+
+	return Update{
+		UpdateID:   p.UpdateID,
+		ChatID:     p.ChatID,
+		SenderID:   p.SenderID,
+		UpdateType: updateType,
+		CreatedAt:  p.CreatedAt,
+		Content: UpdateContent{
+			Pin: &PinContent{
+				MessageID: p.MessageID,
+			},
+		},
+	}
+}
+
+func FromPollDTO(p *dto.PollDTO) Update {
+	options := make([]PollOptionContent, len(p.Options))
+	for i, option := range p.Options {
+		options[i] = PollOptionContent{
+			Text:   option.Text,
+			Votes:  option.Votes,
+			Voters: option.Voters,
+		}
+	}
+
+	return Update{
+		UpdateID:     p.UpdateID,
+		ChatID:       p.ChatID,
+		SenderID:     p.SenderID,
+		ThreadRootID: p.ThreadRootID,
+		UpdateType:   domain.UpdateTypePoll,
+		CreatedAt:    p.CreatedAt,
+		Content: UpdateContent{
+			Poll: &PollContent{
+				Question:       p.Question,
+				Options:        options,
+				MultipleChoice: p.MultipleChoice,
+				Anonymous:      p.Anonymous,
+				ClosesAt:       p.ClosesAt,
+				Closed:         p.Closed,
+				TotalVoters:    p.TotalVoters,
+				ReplyTo:        p.ReplyTo,
+			},
+		},
+	}
+}
+
+func FromServiceMessageDTO(m *dto.ServiceMessageDTO) Update {
+	content := NewServiceMessageContent(m.UpdateType, m.Members, m.Name, m.Description, m.Photo, m.Expiration)
+
+	return Update{
+		UpdateID:   m.UpdateID,
+		ChatID:     m.ChatID,
+		SenderID:   m.SenderID,
+		UpdateType: m.UpdateType,
+		CreatedAt:  m.CreatedAt,
+		Content: UpdateContent{
+			Service: &content,
+		},
+	}
+}
@@ -2,7 +2,11 @@ package generic
 
 import (
 	"encoding/json"
+	"strings"
 	"testing"
+
+	"github.com/chakchat/chakchat-backend/messaging-service/internal/application/dto"
+	"github.com/chakchat/chakchat-backend/messaging-service/internal/domain"
 )
 
 func TestUpdateContentMarshalJSON(t *testing.T) {
@@ -20,4 +24,70 @@ func TestUpdateContentMarshalJSON(t *testing.T) {
 	if string(enc) != `{"text":"test"}` {
 		t.Fatalf("got: %s", enc)
 	}
-}
\ No newline at end of file
+}
+
+func TestAnonymousPollMarshalJSON(t *testing.T) {
+	voter, _ := domain.NewUserID("3d7ca3ef-3b0d-4113-91c9-20b7bf874324")
+	poll := &domain.Poll{
+		PollSettings: domain.PollSettings{
+			Anonymous: true,
+		},
+		Question: "question?",
+		Options: []domain.PollOption{
+			{Text: "yes", Voters: []domain.UserID{voter}},
+			{Text: "no"},
+		},
+	}
+	pollDto := dto.NewPollDTO(poll)
+
+	enc, err := json.Marshal(FromPollDTO(&pollDto).Content)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if strings.Contains(string(enc), "3d7ca3ef-3b0d-4113-91c9-20b7bf874324") {
+		t.Fatalf("voter is revealed: %s", enc)
+	}
+	if !strings.Contains(string(enc), `{"text":"yes","votes":1}`) {
+		t.Fatalf("got: %s", enc)
+	}
+}
+
+func TestServiceMessageMarshalJSON(t *testing.T) {
+	tests := []struct {
+		name string
+		msg  *domain.ServiceMessage
+		want string
+	}{
+		{
+			name: "info_changed",
+			msg:  &domain.ServiceMessage{Type: domain.UpdateTypeInfoChanged, Name: "group", Photo: "ignored"},
+			want: `{"name":"group","description":""}`,
+		},
+		{
+			name: "photo_deleted",
+			msg:  &domain.ServiceMessage{Type: domain.UpdateTypePhotoChanged, Name: "ignored"},
+			want: `{"photo":""}`,
+		},
+		{
+			name: "expiration_turned_off",
+			msg:  &domain.ServiceMessage{Type: domain.UpdateTypeExpirationChanged},
+			want: `{}`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			msgDto := dto.NewServiceMessageDTO(test.msg)
+
+			enc, err := json.Marshal(FromServiceMessageDTO(&msgDto).Content)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if string(enc) != test.want {
+				t.Fatalf("got: %s", enc)
+			}
+		})
+	}
+}
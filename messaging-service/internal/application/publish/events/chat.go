@@ -52,3 +52,75 @@ type GroupMembersRemoved struct {
 	ChatID   uuid.UUID   `json:"chat_id"`
 	Members  []uuid.UUID `json:"members"`
 }
+
+const (
+	RoleAdmin  = "admin"
+	RoleMember = "member"
+)
+
+type GroupMemberRoleChanged struct {
+	SenderID uuid.UUID `json:"sender_id"`
+	ChatID   uuid.UUID `json:"chat_id"`
+	MemberID uuid.UUID `json:"member_id"`
+	// RoleAdmin or RoleMember
+	Role string `json:"role"`
+	// Admin permissions. Empty if the member is not admin anymore
+	Permissions []string `json:"permissions"`
+}
+
+type GroupMemberPermissionsChanged struct {
+	SenderID    uuid.UUID `json:"sender_id"`
+	ChatID      uuid.UUID `json:"chat_id"`
+	Permissions []string  `json:"permissions"`
+}
+
+type GroupPostingModeChanged struct {
+	SenderID        uuid.UUID `json:"sender_id"`
+	ChatID          uuid.UUID `json:"chat_id"`
+	SlowModeSeconds int       `json:"slow_mode_seconds"`
+	AdminsOnly      bool      `json:"admins_only"`
+}
+
+type GroupHistoryVisibilityChanged struct {
+	SenderID      uuid.UUID `json:"sender_id"`
+	ChatID        uuid.UUID `json:"chat_id"`
+	HistoryHidden bool      `json:"history_hidden"`
+}
+
+// GroupOwnerChanged is sent when the owner transfers ownership or leaves the group.
+// The previous owner stays an admin with all permissions only after a transfer.
+type GroupOwnerChanged struct {
+	SenderID        uuid.UUID `json:"sender_id"`
+	ChatID          uuid.UUID `json:"chat_id"`
+	PreviousOwnerID uuid.UUID `json:"previous_owner_id"`
+	OwnerID         uuid.UUID `json:"owner_id"`
+}
+
+// GroupJoinRequested is sent to admins who can approve the request
+type GroupJoinRequested struct {
+	ChatID uuid.UUID `json:"chat_id"`
+	UserID uuid.UUID `json:"user_id"`
+}
+
+type ChannelInfoUpdated struct {
+	SenderID    uuid.UUID `json:"sender_id"`
+	ChatID      uuid.UUID `json:"chat_id"`
+	Name        string    `json:"name"`
+	Description string    `json:"description"`
+}
+
+// TopicInfo is sent when a topic is created or updated
+type TopicInfo struct {
+	SenderID uuid.UUID `json:"sender_id"`
+	ChatID   uuid.UUID `json:"chat_id"`
+	TopicID  uuid.UUID `json:"topic_id"`
+	Title    string    `json:"title"`
+	Icon     string    `json:"icon"`
+	Closed   bool      `json:"closed"`
+}
+
+type TopicDeleted struct {
+	SenderID uuid.UUID `json:"sender_id"`
+	ChatID   uuid.UUID `json:"chat_id"`
+	TopicID  uuid.UUID `json:"topic_id"`
+}
@@ -10,4 +10,27 @@ const (
 	TypeGroupInfoUpdated    = "group_info_updated"
 	TypeGroupMembersAdded   = "group_members_added"
 	TypeGroupMembersRemoved = "group_members_removed"
+
+	TypeGroupMemberRoleChanged        = "group_member_role_changed"
+	TypeGroupMemberPermissionsChanged = "group_member_permissions_changed"
+	TypeGroupOwnerChanged             = "group_owner_changed"
+	TypeGroupJoinRequested            = "group_join_requested"
+	TypeGroupPostingModeChanged       = "group_posting_mode_changed"
+	TypeGroupHistoryVisibilityChanged = "group_history_visibility_changed"
+
+	TypeChannelInfoUpdated = "channel_info_updated"
+
+	TypeTopicCreated = "topic_created"
+	TypeTopicUpdated = "topic_updated"
+	TypeTopicDeleted = "topic_deleted"
+	// Update of a topic stream. Its chat_id is the topic ID.
+	TypeTopicUpdate = "topic_update"
+
+	// New thread message sent to thread participants.
+	// Unlike TypeUpdate it should be delivered even if the main chat is muted.
+	TypeThreadUpdate = "thread_update"
+
+	// New message sent to the members it mentions.
+	// It should be notified with high priority even if the chat is muted.
+	TypeMention = "mention"
 )
@@ -0,0 +1,42 @@
+package publish
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Fanout is an event for all subscribers of a channel.
+// A channel may have millions of subscribers, so they can't be put into a single UserEvent.
+// Instead the event is stored and published for batches of subscribers by a background dispatcher.
+type Fanout struct {
+	ID       int64
+	ChatID   uuid.UUID
+	SenderID uuid.UUID
+	Type     string
+	Data     json.RawMessage
+	// The last subscriber the event is published for. Subscribers are published for in ID order.
+	// nil means publication hasn't started yet.
+	LastUserID *uuid.UUID
+
+	// Failed dispatch attempts. The fanout isn't dispatched again until RetryAt.
+	Attempts int
+	RetryAt  *time.Time
+	// Parked fanouts failed too many times and aren't dispatched anymore
+	ParkedAt *time.Time
+}
+
+func NewFanout(chatID, senderID uuid.UUID, typ string, data any) (*Fanout, error) {
+	binData, err := json.Marshal(data)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Fanout{
+		ChatID:   chatID,
+		SenderID: senderID,
+		Type:     typ,
+		Data:     binData,
+	}, nil
+}
@@ -115,3 +115,155 @@ func WithChatLastUpdateID() GetChatOption {
 		opts.LoadLastUpdateID = true
 	}
 }
+
+type SetGroupAdmin struct {
+	ChatID      uuid.UUID
+	SenderID    uuid.UUID
+	MemberID    uuid.UUID
+	Permissions []string
+}
+
+type RemoveGroupAdmin struct {
+	ChatID   uuid.UUID
+	SenderID uuid.UUID
+	MemberID uuid.UUID
+}
+
+type TransferGroupOwnership struct {
+	ChatID   uuid.UUID
+	SenderID uuid.UUID
+	MemberID uuid.UUID
+}
+
+type LeaveGroup struct {
+	ChatID   uuid.UUID
+	SenderID uuid.UUID
+}
+
+type SetMemberPermissions struct {
+	ChatID      uuid.UUID
+	SenderID    uuid.UUID
+	Permissions []string
+}
+
+type SetPostingMode struct {
+	ChatID          uuid.UUID
+	SenderID        uuid.UUID
+	SlowModeSeconds int
+	AdminsOnly      bool
+}
+
+type SetHistoryVisibility struct {
+	ChatID        uuid.UUID
+	SenderID      uuid.UUID
+	HistoryHidden bool
+}
+
+type CreateInviteLink struct {
+	ChatID           uuid.UUID
+	SenderID         uuid.UUID
+	ExpiresAt        *int64
+	UsageLimit       int
+	RequiresApproval bool
+}
+
+type GetInviteLinks struct {
+	ChatID   uuid.UUID
+	SenderID uuid.UUID
+}
+
+type RevokeInviteLink struct {
+	ChatID   uuid.UUID
+	SenderID uuid.UUID
+	Token    string
+}
+
+type JoinByInviteLink struct {
+	SenderID uuid.UUID
+	Token    string
+}
+
+type GetJoinRequests struct {
+	ChatID   uuid.UUID
+	SenderID uuid.UUID
+}
+
+type ResolveJoinRequest struct {
+	ChatID   uuid.UUID
+	SenderID uuid.UUID
+	UserID   uuid.UUID
+}
+
+type BanMember struct {
+	ChatID    uuid.UUID
+	SenderID  uuid.UUID
+	MemberID  uuid.UUID
+	Reason    string
+	ExpiresAt *int64
+}
+
+type UnbanMember struct {
+	ChatID   uuid.UUID
+	SenderID uuid.UUID
+	MemberID uuid.UUID
+}
+
+type GetGroupBans struct {
+	ChatID   uuid.UUID
+	SenderID uuid.UUID
+	Offset   int
+	Limit    int
+}
+
+type CreateChannel struct {
+	SenderID    uuid.UUID
+	Name        string
+	Description string
+}
+
+type UpdateChannelInfo struct {
+	ChatID      uuid.UUID
+	SenderID    uuid.UUID
+	Name        string
+	Description string
+}
+
+type SubscribeChannel struct {
+	ChatID   uuid.UUID
+	SenderID uuid.UUID
+}
+
+type CreateTopic struct {
+	ChatID   uuid.UUID
+	SenderID uuid.UUID
+	Title    string
+	Icon     string
+}
+
+type UpdateTopic struct {
+	ChatID   uuid.UUID
+	TopicID  uuid.UUID
+	SenderID uuid.UUID
+	Title    string
+	Icon     string
+}
+
+// TopicAction is a request to close, reopen or delete a topic
+type TopicAction struct {
+	ChatID   uuid.UUID
+	TopicID  uuid.UUID
+	SenderID uuid.UUID
+}
+
+type GetTopics struct {
+	ChatID   uuid.UUID
+	SenderID uuid.UUID
+}
+
+type ReadTopic struct {
+	ChatID   uuid.UUID
+	TopicID  uuid.UUID
+	SenderID uuid.UUID
+	// Messages up to this update are marked as read
+	UpdateID int64
+}
@@ -6,15 +6,45 @@ type SendTextMessage struct {
 	ChatID         uuid.UUID
 	SenderID       uuid.UUID
 	Text           string
+	Entities       []TextEntity
 	ReplyToMessage *int64
+	// Only group chats have threads
+	ThreadRootID *int64
+	// Only group chats have mentions
+	Mentions []Mention
+}
+
+// Mention refers to a part of the text. Offset and length are measured in runes.
+// The part is "@username" unless everyone is mentioned.
+type Mention struct {
+	Offset   int
+	Length   int
+	Everyone bool
+}
+
+// TextEntity formats a part of the text. Offset and length are measured in runes.
+type TextEntity struct {
+	Type   string
+	Offset int
+	Length int
+	// Set only for links
+	URL string
+}
+
+type ReadMentions struct {
+	ChatID   uuid.UUID
+	SenderID uuid.UUID
+	// Mentions up to this update are marked as read
+	UpdateID int64
 }
 
 type EditTextMessage struct {
 	ChatID   uuid.UUID
 	SenderID uuid.UUID
 
-	MessageID int64
-	NewText   string
+	MessageID   int64
+	NewText     string
+	NewEntities []TextEntity
 }
 
 type DeleteMessage struct {
@@ -40,6 +70,90 @@ type DeleteReaction struct {
 	ReactionID int64
 }
 
+// Used both for pinning and unpinning
+type PinMessage struct {
+	ChatID   uuid.UUID
+	SenderID uuid.UUID
+
+	MessageID int64
+}
+
+type SendPoll struct {
+	ChatID         uuid.UUID
+	SenderID       uuid.UUID
+	Question       string
+	Options        []string
+	MultipleChoice bool
+	Anonymous      bool
+	// Unix time
+	ClosesAt       *int64
+	ReplyToMessage *int64
+}
+
+type VotePoll struct {
+	ChatID   uuid.UUID
+	SenderID uuid.UUID
+
+	PollID int64
+	// Indexes of chosen options
+	Options []int
+}
+
+type RetractPollVote struct {
+	ChatID   uuid.UUID
+	SenderID uuid.UUID
+
+	PollID int64
+}
+
+type ClosePoll struct {
+	ChatID   uuid.UUID
+	SenderID uuid.UUID
+
+	PollID int64
+}
+
+type ScheduleTextMessage struct {
+	ChatID         uuid.UUID
+	SenderID       uuid.UUID
+	Text           string
+	Entities       []TextEntity
+	ReplyToMessage *int64
+	// Unix time
+	SendAt int64
+}
+
+type ScheduleFileMessage struct {
+	ChatID         uuid.UUID
+	SenderID       uuid.UUID
+	FileID         uuid.UUID
+	ReplyToMessage *int64
+	// Unix time
+	SendAt int64
+}
+
+type GetScheduledMessages struct {
+	ChatID   uuid.UUID
+	SenderID uuid.UUID
+}
+
+type EditScheduledMessage struct {
+	ChatID      uuid.UUID
+	SenderID    uuid.UUID
+	ScheduledID int64
+	// Must be empty for file messages
+	NewText     string
+	NewEntities []TextEntity
+	// Unix time
+	SendAt int64
+}
+
+type CancelScheduledMessage struct {
+	ChatID      uuid.UUID
+	SenderID    uuid.UUID
+	ScheduledID int64
+}
+
 type ForwardMessage struct {
 	ToChatID uuid.UUID
 	SenderID uuid.UUID
@@ -53,6 +167,8 @@ type SendFileMessage struct {
 	SenderID       uuid.UUID
 	FileID         uuid.UUID
 	ReplyToMessage *int64
+	// Only group chats have threads
+	ThreadRootID *int64
 }
 
 type SendSecretUpdate struct {
@@ -74,6 +190,16 @@ type DeleteSecretUpdate struct {
 type GetUpdatesRange struct {
 	ChatID   uuid.UUID
 	SenderID uuid.UUID
+	// Updates of the topic of the group are fetched if it is set
+	TopicID *uuid.UUID
+
+	From, To int64
+}
+
+type GetThreadUpdatesRange struct {
+	ChatID       uuid.UUID
+	SenderID     uuid.UUID
+	ThreadRootID int64
 
 	From, To int64
 }
@@ -84,3 +210,56 @@ type GetUpdate struct {
 
 	UpdateID int64
 }
+
+type SearchMessages struct {
+	SenderID uuid.UUID
+	Query    string
+
+	// Optional filters.
+	// ChatType is required if ChatID is set.
+	ChatID       *uuid.UUID
+	ChatType     string
+	FromSenderID *uuid.UUID
+	// Unix seconds, inclusive
+	Since *int64
+	// Unix seconds, exclusive
+	Until *int64
+
+	Offset int
+	Limit  int
+}
+
+type ViewPosts struct {
+	ChatID   uuid.UUID
+	SenderID uuid.UUID
+	PostIDs  []int64
+}
+
+// Topic messages are addressed by the group chat and the topic
+type SendTopicTextMessage struct {
+	ChatID         uuid.UUID
+	TopicID        uuid.UUID
+	SenderID       uuid.UUID
+	Text           string
+	Entities       []TextEntity
+	ReplyToMessage *int64
+}
+
+type EditTopicTextMessage struct {
+	ChatID   uuid.UUID
+	TopicID  uuid.UUID
+	SenderID uuid.UUID
+
+	MessageID   int64
+	NewText     string
+	NewEntities []TextEntity
+}
+
+type DeleteTopicMessage struct {
+	ChatID   uuid.UUID
+	TopicID  uuid.UUID
+	SenderID uuid.UUID
+
+	MessageID  int64
+	DeleteMode string
+}
@@ -0,0 +1,321 @@
+package chat
+
+import (
+	"context"
+	"errors"
+
+	"github.com/chakchat/chakchat-backend/messaging-service/internal/application/dto"
+	"github.com/chakchat/chakchat-backend/messaging-service/internal/application/publish"
+	"github.com/chakchat/chakchat-backend/messaging-service/internal/application/publish/events"
+	"github.com/chakchat/chakchat-backend/messaging-service/internal/application/request"
+	"github.com/chakchat/chakchat-backend/messaging-service/internal/application/services"
+	"github.com/chakchat/chakchat-backend/messaging-service/internal/application/storage"
+	"github.com/chakchat/chakchat-backend/messaging-service/internal/application/storage/repository"
+	"github.com/chakchat/chakchat-backend/messaging-service/internal/domain"
+	"github.com/chakchat/chakchat-backend/messaging-service/internal/domain/channel"
+	"github.com/google/uuid"
+)
+
+// ChannelService manages channels and subscriptions.
+// Events for subscribers are published through the channel fanout.
+type ChannelService struct {
+	txProvider storage.TxProvider
+	repo       repository.ChannelRepository
+	fanoutRepo repository.ChannelFanoutRepository
+	pub        publish.Publisher
+}
+
+func NewChannelService(
+	txProvider storage.TxProvider,
+	repo repository.ChannelRepository,
+	fanoutRepo repository.ChannelFanoutRepository,
+	pub publish.Publisher,
+) *ChannelService {
+	return &ChannelService{
+		txProvider: txProvider,
+		repo:       repo,
+		fanoutRepo: fanoutRepo,
+		pub:        pub,
+	}
+}
+
+func (s *ChannelService) CreateChannel(
+	ctx context.Context, req request.CreateChannel,
+) (_ *dto.ChannelDTO, err error) {
+	tx, err := s.txProvider.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer storage.FinishTx(ctx, tx, &err)
+
+	c, err := channel.NewChannel(domain.UserID(req.SenderID), req.Name, req.Description)
+	if err != nil {
+		return nil, err
+	}
+
+	c, err = s.repo.Create(ctx, tx, c)
+	if err != nil {
+		return nil, err
+	}
+
+	cDto := dto.NewChannelDTO(c)
+	return &cDto, nil
+}
+
+func (s *ChannelService) UpdateChannelInfo(
+	ctx context.Context, req request.UpdateChannelInfo,
+) (_ *dto.ChannelDTO, err error) {
+	tx, err := s.txProvider.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer storage.FinishTx(ctx, tx, &err)
+
+	c, err := s.findChannel(ctx, tx, req.ChatID)
+	if err != nil {
+		return nil, err
+	}
+
+	err = c.UpdateInfo(domain.UserID(req.SenderID), req.Name, req.Description)
+	if err != nil {
+		return nil, err
+	}
+
+	c, err = s.repo.Update(ctx, tx, c)
+	if err != nil {
+		return nil, err
+	}
+
+	cDto := dto.NewChannelDTO(c)
+
+	err = services.PublishForSubscribers(
+		ctx, tx, s.fanoutRepo, c.ID, domain.UserID(req.SenderID),
+		events.TypeChannelInfoUpdated,
+		events.ChannelInfoUpdated{
+			SenderID:    req.SenderID,
+			ChatID:      cDto.ID,
+			Name:        cDto.Name,
+			Description: cDto.Description,
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &cDto, nil
+}
+
+// DeleteChannel deletes the channel with all its subscriptions.
+// Subscribers are deleted with the channel, so only admins are notified.
+// Others find out the channel is deleted when they fetch their chats.
+func (s *ChannelService) DeleteChannel(ctx context.Context, req request.DeleteChat) (err error) {
+	tx, err := s.txProvider.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer storage.FinishTx(ctx, tx, &err)
+
+	c, err := s.findChannel(ctx, tx, req.ChatID)
+	if err != nil {
+		return err
+	}
+
+	err = c.Delete(domain.UserID(req.SenderID))
+	if err != nil {
+		return err
+	}
+
+	err = s.repo.Delete(ctx, tx, c.ID)
+	if err != nil {
+		return err
+	}
+
+	return s.pub.PublishForReceivers(
+		ctx,
+		services.GetReceivingMembers(channelAdmins(c), domain.UserID(req.SenderID)),
+		events.TypeChatDeleted,
+		events.ChatDeleted{
+			SenderID: req.SenderID,
+			ChatID:   req.ChatID,
+		},
+	)
+}
+
+func (s *ChannelService) Subscribe(
+	ctx context.Context, req request.SubscribeChannel,
+) (_ *dto.ChannelDTO, err error) {
+	tx, err := s.txProvider.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer storage.FinishTx(ctx, tx, &err)
+
+	c, err := s.findChannel(ctx, tx, req.ChatID, req.SenderID)
+	if err != nil {
+		return nil, err
+	}
+
+	err = c.Subscribe(domain.UserID(req.SenderID))
+	if err != nil {
+		return nil, err
+	}
+
+	c, err = s.repo.Update(ctx, tx, c)
+	if err != nil {
+		return nil, err
+	}
+
+	cDto := dto.NewChannelDTO(c)
+	return &cDto, nil
+}
+
+func (s *ChannelService) Unsubscribe(
+	ctx context.Context, req request.SubscribeChannel,
+) (err error) {
+	tx, err := s.txProvider.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer storage.FinishTx(ctx, tx, &err)
+
+	c, err := s.findChannel(ctx, tx, req.ChatID, req.SenderID)
+	if err != nil {
+		return err
+	}
+
+	wasAdmin := c.IsAdmin(domain.UserID(req.SenderID))
+
+	err = c.Unsubscribe(domain.UserID(req.SenderID))
+	if err != nil {
+		return err
+	}
+
+	c, err = s.repo.Update(ctx, tx, c)
+	if err != nil {
+		return err
+	}
+
+	if wasAdmin {
+		return s.publishRoleChanged(ctx, tx, c, req.SenderID, req.SenderID, events.RoleMember, []string{})
+	}
+	return nil
+}
+
+func (s *ChannelService) SetAdmin(
+	ctx context.Context, req request.SetGroupAdmin,
+) (_ *dto.ChannelDTO, err error) {
+	perms, err := domain.NewAdminPermissions(req.Permissions)
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := s.txProvider.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer storage.FinishTx(ctx, tx, &err)
+
+	c, err := s.findChannel(ctx, tx, req.ChatID, req.MemberID)
+	if err != nil {
+		return nil, err
+	}
+
+	err = c.SetAdmin(domain.UserID(req.SenderID), domain.UserID(req.MemberID), perms)
+	if err != nil {
+		return nil, err
+	}
+
+	c, err = s.repo.Update(ctx, tx, c)
+	if err != nil {
+		return nil, err
+	}
+
+	cDto := dto.NewChannelDTO(c)
+
+	err = s.publishRoleChanged(ctx, tx, c, req.SenderID, req.MemberID, events.RoleAdmin, perms.Names())
+	if err != nil {
+		return nil, err
+	}
+
+	return &cDto, nil
+}
+
+func (s *ChannelService) RemoveAdmin(
+	ctx context.Context, req request.RemoveGroupAdmin,
+) (_ *dto.ChannelDTO, err error) {
+	tx, err := s.txProvider.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer storage.FinishTx(ctx, tx, &err)
+
+	c, err := s.findChannel(ctx, tx, req.ChatID)
+	if err != nil {
+		return nil, err
+	}
+
+	err = c.RemoveAdmin(domain.UserID(req.SenderID), domain.UserID(req.MemberID))
+	if err != nil {
+		return nil, err
+	}
+
+	c, err = s.repo.Update(ctx, tx, c)
+	if err != nil {
+		return nil, err
+	}
+
+	cDto := dto.NewChannelDTO(c)
+
+	err = s.publishRoleChanged(ctx, tx, c, req.SenderID, req.MemberID, events.RoleMember, []string{})
+	if err != nil {
+		return nil, err
+	}
+
+	return &cDto, nil
+}
+
+func (s *ChannelService) publishRoleChanged(
+	ctx context.Context, db storage.ExecQuerier, c *channel.Channel,
+	sender, member uuid.UUID, role string, perms []string,
+) error {
+	return services.PublishForSubscribers(
+		ctx, db, s.fanoutRepo, c.ID, domain.UserID(sender),
+		events.TypeGroupMemberRoleChanged,
+		events.GroupMemberRoleChanged{
+			SenderID:    sender,
+			ChatID:      uuid.UUID(c.ID),
+			MemberID:    member,
+			Role:        role,
+			Permissions: perms,
+		},
+	)
+}
+
+// findChannel finds the channel with subscriptions of the users
+func (s *ChannelService) findChannel(
+	ctx context.Context, db storage.ExecQuerier, chatID uuid.UUID, users ...uuid.UUID,
+) (*channel.Channel, error) {
+	userIDs := make([]domain.UserID, len(users))
+	for i, user := range users {
+		userIDs[i] = domain.UserID(user)
+	}
+
+	c, err := s.repo.FindById(ctx, db, domain.ChatID(chatID), userIDs...)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, services.ErrChatNotFound
+		}
+		return nil, err
+	}
+	return c, nil
+}
+
+// channelAdmins returns the owner and admins of the channel
+func channelAdmins(c *channel.Channel) []domain.UserID {
+	res := make([]domain.UserID, 0, len(c.Admins)+1)
+	res = append(res, c.Owner)
+	for admin := range c.Admins {
+		res = append(res, admin)
+	}
+	return res
+}
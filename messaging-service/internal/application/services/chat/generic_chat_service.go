@@ -17,20 +17,23 @@ import (
 )
 
 type GenericChatService struct {
-	txProvider storage.TxProvider
-	chatRepo   repository.GenericChatRepository
-	updaterepo repository.GenericUpdateRepository
+	txProvider  storage.TxProvider
+	chatRepo    repository.GenericChatRepository
+	updaterepo  repository.GenericUpdateRepository
+	mentionRepo repository.MentionRepository
 }
 
 func NewGenericChatService(
 	txProvider storage.TxProvider,
 	chatRepo repository.GenericChatRepository,
 	updateRepo repository.GenericUpdateRepository,
+	mentionRepo repository.MentionRepository,
 ) *GenericChatService {
 	return &GenericChatService{
-		txProvider: txProvider,
-		chatRepo:   chatRepo,
-		updaterepo: updateRepo,
+		txProvider:  txProvider,
+		chatRepo:    chatRepo,
+		updaterepo:  updateRepo,
+		mentionRepo: mentionRepo,
 	}
 }
 
@@ -48,6 +51,12 @@ func (s *GenericChatService) GetByMemberID(ctx context.Context, memberID uuid.UU
 		return nil, err
 	}
 
+	for i := range chats {
+		if err = s.fillUnreadMentions(ctx, tx, &chats[i], memberID); err != nil {
+			return nil, err
+		}
+	}
+
 	if opt.LoadLastUpdateID {
 		for _, chat := range chats {
 			if err = s.fillLastUpdateID(ctx, tx, &chat); err != nil {
@@ -84,10 +93,22 @@ func (s *GenericChatService) GetByChatID(ctx context.Context, senderID, chatID u
 		return nil, fmt.Errorf("getting generic chat failed: %s", err)
 	}
 
-	if !slices.Contains(chat.Members, senderID) {
+	isMember := slices.Contains(chat.Members, senderID)
+	if chat.Type == domain.ChatTypeChannel {
+		// Channel subscribers are not loaded with the chat
+		isMember, err = s.chatRepo.IsMember(ctx, tx, domain.ChatID(chatID), domain.UserID(senderID))
+		if err != nil {
+			return nil, err
+		}
+	}
+	if !isMember {
 		return nil, domain.ErrUserNotMember
 	}
 
+	if err = s.fillUnreadMentions(ctx, tx, chat, senderID); err != nil {
+		return nil, err
+	}
+
 	if opt.LoadLastUpdateID {
 		if err = s.fillLastUpdateID(ctx, tx, chat); err != nil {
 			return nil, err
@@ -114,6 +135,21 @@ func (s *GenericChatService) fillLastUpdateID(ctx context.Context, tx pgx.Tx, ch
 	return nil
 }
 
+func (s *GenericChatService) fillUnreadMentions(ctx context.Context, tx pgx.Tx, chat *generic.Chat, memberID uuid.UUID) error {
+	// Only group chats have mentions
+	if chat.Type != domain.ChatTypeGroup {
+		return nil
+	}
+
+	count, err := s.mentionRepo.CountUnread(ctx, tx, domain.ChatID(chat.ChatID), domain.UserID(memberID))
+	if err != nil {
+		return fmt.Errorf("fill unread mentions: %w", err)
+	}
+
+	chat.UnreadMentions = count
+	return nil
+}
+
 func (s *GenericChatService) fillPreview(
 	ctx context.Context, tx pgx.Tx, chat *generic.Chat, senderID uuid.UUID, previewCount int,
 ) error {
@@ -3,6 +3,7 @@ package chat
 import (
 	"context"
 	"errors"
+	"time"
 
 	"github.com/chakchat/chakchat-backend/messaging-service/internal/application/dto"
 	"github.com/chakchat/chakchat-backend/messaging-service/internal/application/generic"
@@ -20,14 +21,22 @@ import (
 type GroupChatService struct {
 	txProvider storage.TxProvider
 	repo       repository.GroupChatRepository
+	banRepo    repository.GroupBanRepository
+	updateRepo repository.UpdateRepository
 	pub        publish.Publisher
 }
 
 func NewGroupChatService(
-	txProvider storage.TxProvider, repo repository.GroupChatRepository, pub publish.Publisher,
+	txProvider storage.TxProvider,
+	repo repository.GroupChatRepository,
+	banRepo repository.GroupBanRepository,
+	updateRepo repository.UpdateRepository,
+	pub publish.Publisher,
 ) *GroupChatService {
 	return &GroupChatService{
 		repo:       repo,
+		banRepo:    banRepo,
+		updateRepo: updateRepo,
 		pub:        pub,
 		txProvider: txProvider,
 	}
@@ -101,9 +110,16 @@ func (s *GroupChatService) UpdateGroupInfo(ctx context.Context, req request.Upda
 
 	gDto := dto.NewGroupChatDTO(g)
 
+	err = recordServiceMessage(ctx, tx, s.updateRepo, s.pub, g.Members,
+		domain.NewInfoChangedMessage(g, domain.UserID(req.SenderID), g.Name, g.Description),
+	)
+	if err != nil {
+		return nil, err
+	}
+
 	err = s.pub.PublishForReceivers(
 		ctx,
-		services.GetReceivingMembers(g.Members, g.Admin),
+		services.GetReceivingMembers(g.Members, domain.UserID(req.SenderID)),
 		events.TypeGroupInfoUpdated,
 		events.GroupInfoUpdated{
 			SenderID:    req.SenderID,
@@ -172,6 +188,12 @@ func (s *GroupChatService) AddMember(ctx context.Context, req request.AddMember)
 		if errors.Is(err, repository.ErrNotFound) {
 			return nil, services.ErrChatNotFound
 		}
+		return nil, err
+	}
+
+	err = validateNotBanned(ctx, tx, s.banRepo, g, req.MemberID)
+	if err != nil {
+		return nil, err
 	}
 
 	err = g.AddMember(domain.UserID(req.SenderID), domain.UserID(req.MemberID))
@@ -187,23 +209,38 @@ func (s *GroupChatService) AddMember(ctx context.Context, req request.AddMember)
 
 	gDto := dto.NewGroupChatDTO(g)
 
-	err = s.pub.PublishForReceivers(
-		ctx,
-		services.GetReceivingMembers(g.Members, domain.UserID(req.SenderID)),
-		events.TypeGroupMembersAdded,
-		events.GroupMemberAdded{
-			SenderID: req.SenderID,
-			ChatID:   req.ChatID,
-			Members:  []uuid.UUID{req.MemberID},
-		},
+	err = recordServiceMessage(ctx, tx, s.updateRepo, s.pub, g.Members,
+		domain.NewMembersAddedMessage(g, domain.UserID(req.SenderID), []domain.UserID{domain.UserID(req.MemberID)}),
 	)
 	if err != nil {
 		return nil, err
 	}
 
+	err = publishMembersAdded(ctx, s.pub, g, req.SenderID, req.MemberID)
+	if err != nil {
+		return nil, err
+	}
+
 	return &gDto, nil
 }
 
+// publishMembersAdded notifies group members except the sender about new members.
+// The sender is the new member itself if it joined by an invite link.
+func publishMembersAdded(
+	ctx context.Context, pub publish.Publisher, g *group.GroupChat, sender uuid.UUID, members ...uuid.UUID,
+) error {
+	return pub.PublishForReceivers(
+		ctx,
+		services.GetReceivingMembers(g.Members, domain.UserID(sender)),
+		events.TypeGroupMembersAdded,
+		events.GroupMemberAdded{
+			SenderID: sender,
+			ChatID:   uuid.UUID(g.ID),
+			Members:  members,
+		},
+	)
+}
+
 func (s *GroupChatService) DeleteMember(ctx context.Context, req request.DeleteMember) (_ *dto.GroupChatDTO, err error) {
 	tx, err := s.txProvider.Begin(ctx)
 	if err != nil {
@@ -216,8 +253,10 @@ func (s *GroupChatService) DeleteMember(ctx context.Context, req request.DeleteM
 		if errors.Is(err, repository.ErrNotFound) {
 			return nil, services.ErrChatNotFound
 		}
+		return nil, err
 	}
 
+	prevOwner := g.Owner
 	err = g.DeleteMember(domain.UserID(req.SenderID), domain.UserID(req.MemberID))
 
 	if err != nil {
@@ -231,6 +270,13 @@ func (s *GroupChatService) DeleteMember(ctx context.Context, req request.DeleteM
 
 	gDto := dto.NewGroupChatDTO(g)
 
+	err = recordServiceMessage(ctx, tx, s.updateRepo, s.pub, g.Members,
+		domain.NewMembersRemovedMessage(g, domain.UserID(req.SenderID), []domain.UserID{domain.UserID(req.MemberID)}),
+	)
+	if err != nil {
+		return nil, err
+	}
+
 	err = s.pub.PublishForReceivers(
 		ctx,
 		services.GetReceivingMembers(g.Members, domain.UserID(req.SenderID)),
@@ -245,5 +291,465 @@ func (s *GroupChatService) DeleteMember(ctx context.Context, req request.DeleteM
 		return nil, err
 	}
 
+	if g.Owner != prevOwner {
+		err = s.pub.PublishForReceivers(
+			ctx,
+			services.GetReceivingMembers(g.Members, domain.UserID(req.SenderID)),
+			events.TypeGroupOwnerChanged,
+			events.GroupOwnerChanged{
+				SenderID:        req.SenderID,
+				ChatID:          req.ChatID,
+				PreviousOwnerID: uuid.UUID(prevOwner),
+				OwnerID:         uuid.UUID(g.Owner),
+			},
+		)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return &gDto, nil
+}
+
+// LeaveGroup removes the sender from the group.
+// If the sender is the owner, ownership passes to the oldest remaining admin or member.
+func (s *GroupChatService) LeaveGroup(ctx context.Context, req request.LeaveGroup) (*dto.GroupChatDTO, error) {
+	return s.DeleteMember(ctx, request.DeleteMember{
+		ChatID:   req.ChatID,
+		SenderID: req.SenderID,
+		MemberID: req.SenderID,
+	})
+}
+
+func (s *GroupChatService) SetAdmin(ctx context.Context, req request.SetGroupAdmin) (_ *dto.GroupChatDTO, err error) {
+	perms, err := domain.NewAdminPermissions(req.Permissions)
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := s.txProvider.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer storage.FinishTx(ctx, tx, &err)
+
+	g, err := s.repo.FindById(ctx, tx, domain.ChatID(req.ChatID))
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, services.ErrChatNotFound
+		}
+		return nil, err
+	}
+
+	err = g.SetAdmin(domain.UserID(req.SenderID), domain.UserID(req.MemberID), perms)
+	if err != nil {
+		return nil, err
+	}
+
+	g, err = s.repo.Update(ctx, tx, g)
+	if err != nil {
+		return nil, err
+	}
+
+	gDto := dto.NewGroupChatDTO(g)
+
+	err = s.pub.PublishForReceivers(
+		ctx,
+		services.GetReceivingMembers(g.Members, domain.UserID(req.SenderID)),
+		events.TypeGroupMemberRoleChanged,
+		events.GroupMemberRoleChanged{
+			SenderID:    req.SenderID,
+			ChatID:      req.ChatID,
+			MemberID:    req.MemberID,
+			Role:        events.RoleAdmin,
+			Permissions: perms.Names(),
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &gDto, nil
+}
+
+func (s *GroupChatService) RemoveAdmin(ctx context.Context, req request.RemoveGroupAdmin) (_ *dto.GroupChatDTO, err error) {
+	tx, err := s.txProvider.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer storage.FinishTx(ctx, tx, &err)
+
+	g, err := s.repo.FindById(ctx, tx, domain.ChatID(req.ChatID))
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, services.ErrChatNotFound
+		}
+		return nil, err
+	}
+
+	err = g.RemoveAdmin(domain.UserID(req.SenderID), domain.UserID(req.MemberID))
+	if err != nil {
+		return nil, err
+	}
+
+	g, err = s.repo.Update(ctx, tx, g)
+	if err != nil {
+		return nil, err
+	}
+
+	gDto := dto.NewGroupChatDTO(g)
+
+	err = s.pub.PublishForReceivers(
+		ctx,
+		services.GetReceivingMembers(g.Members, domain.UserID(req.SenderID)),
+		events.TypeGroupMemberRoleChanged,
+		events.GroupMemberRoleChanged{
+			SenderID:    req.SenderID,
+			ChatID:      req.ChatID,
+			MemberID:    req.MemberID,
+			Role:        events.RoleMember,
+			Permissions: []string{},
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &gDto, nil
+}
+
+func (s *GroupChatService) TransferOwnership(
+	ctx context.Context, req request.TransferGroupOwnership,
+) (_ *dto.GroupChatDTO, err error) {
+	tx, err := s.txProvider.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer storage.FinishTx(ctx, tx, &err)
+
+	g, err := s.repo.FindById(ctx, tx, domain.ChatID(req.ChatID))
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, services.ErrChatNotFound
+		}
+		return nil, err
+	}
+
+	err = g.TransferOwnership(domain.UserID(req.SenderID), domain.UserID(req.MemberID))
+	if err != nil {
+		return nil, err
+	}
+
+	g, err = s.repo.Update(ctx, tx, g)
+	if err != nil {
+		return nil, err
+	}
+
+	gDto := dto.NewGroupChatDTO(g)
+
+	err = s.pub.PublishForReceivers(
+		ctx,
+		services.GetReceivingMembers(g.Members, domain.UserID(req.SenderID)),
+		events.TypeGroupOwnerChanged,
+		events.GroupOwnerChanged{
+			SenderID:        req.SenderID,
+			ChatID:          req.ChatID,
+			PreviousOwnerID: req.SenderID,
+			OwnerID:         req.MemberID,
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+
 	return &gDto, nil
 }
+
+func (s *GroupChatService) SetMemberPermissions(
+	ctx context.Context, req request.SetMemberPermissions,
+) (_ *dto.GroupChatDTO, err error) {
+	perms, err := domain.NewMemberPermissions(req.Permissions)
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := s.txProvider.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer storage.FinishTx(ctx, tx, &err)
+
+	g, err := s.repo.FindById(ctx, tx, domain.ChatID(req.ChatID))
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, services.ErrChatNotFound
+		}
+		return nil, err
+	}
+
+	err = g.SetMemberPermissions(domain.UserID(req.SenderID), perms)
+	if err != nil {
+		return nil, err
+	}
+
+	g, err = s.repo.Update(ctx, tx, g)
+	if err != nil {
+		return nil, err
+	}
+
+	gDto := dto.NewGroupChatDTO(g)
+
+	err = s.pub.PublishForReceivers(
+		ctx,
+		services.GetReceivingMembers(g.Members, domain.UserID(req.SenderID)),
+		events.TypeGroupMemberPermissionsChanged,
+		events.GroupMemberPermissionsChanged{
+			SenderID:    req.SenderID,
+			ChatID:      req.ChatID,
+			Permissions: perms.Names(),
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &gDto, nil
+}
+
+func (s *GroupChatService) SetPostingMode(
+	ctx context.Context, req request.SetPostingMode,
+) (_ *dto.GroupChatDTO, err error) {
+	tx, err := s.txProvider.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer storage.FinishTx(ctx, tx, &err)
+
+	g, err := s.repo.FindById(ctx, tx, domain.ChatID(req.ChatID))
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, services.ErrChatNotFound
+		}
+		return nil, err
+	}
+
+	slowMode := time.Duration(req.SlowModeSeconds) * time.Second
+	err = g.SetPostingMode(domain.UserID(req.SenderID), slowMode, req.AdminsOnly)
+	if err != nil {
+		return nil, err
+	}
+
+	g, err = s.repo.Update(ctx, tx, g)
+	if err != nil {
+		return nil, err
+	}
+
+	gDto := dto.NewGroupChatDTO(g)
+
+	err = s.pub.PublishForReceivers(
+		ctx,
+		services.GetReceivingMembers(g.Members, domain.UserID(req.SenderID)),
+		events.TypeGroupPostingModeChanged,
+		events.GroupPostingModeChanged{
+			SenderID:        req.SenderID,
+			ChatID:          req.ChatID,
+			SlowModeSeconds: gDto.SlowModeSeconds,
+			AdminsOnly:      gDto.AdminsOnly,
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &gDto, nil
+}
+
+func (s *GroupChatService) SetHistoryVisibility(
+	ctx context.Context, req request.SetHistoryVisibility,
+) (_ *dto.GroupChatDTO, err error) {
+	tx, err := s.txProvider.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer storage.FinishTx(ctx, tx, &err)
+
+	g, err := s.repo.FindById(ctx, tx, domain.ChatID(req.ChatID))
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, services.ErrChatNotFound
+		}
+		return nil, err
+	}
+
+	err = g.SetHistoryVisibility(domain.UserID(req.SenderID), req.HistoryHidden)
+	if err != nil {
+		return nil, err
+	}
+
+	g, err = s.repo.Update(ctx, tx, g)
+	if err != nil {
+		return nil, err
+	}
+
+	gDto := dto.NewGroupChatDTO(g)
+
+	err = s.pub.PublishForReceivers(
+		ctx,
+		services.GetReceivingMembers(g.Members, domain.UserID(req.SenderID)),
+		events.TypeGroupHistoryVisibilityChanged,
+		events.GroupHistoryVisibilityChanged{
+			SenderID:      req.SenderID,
+			ChatID:        req.ChatID,
+			HistoryHidden: gDto.HistoryHidden,
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &gDto, nil
+}
+
+func (s *GroupChatService) BanMember(ctx context.Context, req request.BanMember) (_ *dto.GroupBanDTO, err error) {
+	tx, err := s.txProvider.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer storage.FinishTx(ctx, tx, &err)
+
+	g, err := s.repo.FindById(ctx, tx, domain.ChatID(req.ChatID))
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, services.ErrChatNotFound
+		}
+		return nil, err
+	}
+
+	wasMember := g.IsMember(domain.UserID(req.MemberID))
+	ban, err := g.Ban(domain.UserID(req.SenderID), domain.UserID(req.MemberID), req.Reason, timestampPtr(req.ExpiresAt))
+	if err != nil {
+		return nil, err
+	}
+
+	g, err = s.repo.Update(ctx, tx, g)
+	if err != nil {
+		return nil, err
+	}
+
+	ban, err = s.banRepo.Store(ctx, tx, ban)
+	if err != nil {
+		return nil, err
+	}
+
+	banDto := dto.NewGroupBanDTO(ban)
+
+	if wasMember {
+		err = recordServiceMessage(ctx, tx, s.updateRepo, s.pub, g.Members,
+			domain.NewMembersRemovedMessage(g, domain.UserID(req.SenderID), []domain.UserID{domain.UserID(req.MemberID)}),
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		err = s.pub.PublishForReceivers(
+			ctx,
+			services.GetReceivingMembers(g.Members, domain.UserID(req.SenderID)),
+			events.TypeGroupMembersRemoved,
+			events.GroupMembersRemoved{
+				SenderID: req.SenderID,
+				ChatID:   req.ChatID,
+				Members:  []uuid.UUID{req.MemberID},
+			},
+		)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return &banDto, nil
+}
+
+func (s *GroupChatService) UnbanMember(ctx context.Context, req request.UnbanMember) (err error) {
+	tx, err := s.txProvider.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer storage.FinishTx(ctx, tx, &err)
+
+	g, err := s.repo.FindById(ctx, tx, domain.ChatID(req.ChatID))
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return services.ErrChatNotFound
+		}
+		return err
+	}
+
+	ban, err := s.banRepo.Find(ctx, tx, g.ID, domain.UserID(req.MemberID))
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return services.ErrBanNotFound
+		}
+		return err
+	}
+
+	err = g.Unban(domain.UserID(req.SenderID), ban)
+	if err != nil {
+		return err
+	}
+
+	return s.banRepo.Delete(ctx, tx, g.ID, ban.UserID)
+}
+
+func (s *GroupChatService) GetBans(ctx context.Context, req request.GetGroupBans) (_ *dto.GroupBanPageDTO, err error) {
+	tx, err := s.txProvider.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer storage.FinishTx(ctx, tx, &err)
+
+	g, err := s.repo.FindById(ctx, tx, domain.ChatID(req.ChatID))
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, services.ErrChatNotFound
+		}
+		return nil, err
+	}
+
+	if err := g.ValidateCanSeeBans(domain.UserID(req.SenderID)); err != nil {
+		return nil, err
+	}
+
+	// One more ban is fetched to know if there is a next page
+	bans, err := s.banRepo.GetActive(ctx, tx, g.ID, req.Offset, req.Limit+1)
+	if err != nil {
+		return nil, err
+	}
+
+	page := &dto.GroupBanPageDTO{}
+	if len(bans) > req.Limit {
+		bans = bans[:req.Limit]
+		nextOffset := req.Offset + req.Limit
+		page.NextOffset = &nextOffset
+	}
+	page.Bans = dto.NewGroupBanDTOs(bans)
+	return page, nil
+}
+
+// validateNotBanned rejects users with an active ban in the group
+func validateNotBanned(
+	ctx context.Context, db storage.ExecQuerier, banRepo repository.GroupBanRepository, g *group.GroupChat, user uuid.UUID,
+) error {
+	ban, err := banRepo.Find(ctx, db, g.ID, domain.UserID(user))
+	if err != nil && !errors.Is(err, repository.ErrNotFound) {
+		return err
+	}
+	return g.ValidateNotBanned(ban)
+}
+
+func timestampPtr(ts *int64) *domain.Timestamp {
+	if ts == nil {
+		return nil
+	}
+	cp := domain.Timestamp(*ts)
+	return &cp
+}
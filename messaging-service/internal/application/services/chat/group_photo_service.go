@@ -32,6 +32,7 @@ type GroupPhotoService struct {
 	txProvider storage.TxProvider
 	repo       repository.GroupChatRepository
 	files      external.FileStorage
+	updateRepo repository.UpdateRepository
 	pub        publish.Publisher
 }
 
@@ -39,11 +40,13 @@ func NewGroupPhotoService(
 	txProvider storage.TxProvider,
 	repo repository.GroupChatRepository,
 	files external.FileStorage,
+	updateRepo repository.UpdateRepository,
 	pub publish.Publisher,
 ) *GroupPhotoService {
 	return &GroupPhotoService{
 		repo:       repo,
 		files:      files,
+		updateRepo: updateRepo,
 		pub:        pub,
 		txProvider: txProvider,
 	}
@@ -89,6 +92,13 @@ func (s *GroupPhotoService) UpdatePhoto(ctx context.Context, req request.UpdateG
 
 	gDto := dto.NewGroupChatDTO(g)
 
+	err = recordServiceMessage(ctx, tx, s.updateRepo, s.pub, g.Members,
+		domain.NewPhotoChangedMessage(g, domain.UserID(req.SenderID), g.GroupPhoto),
+	)
+	if err != nil {
+		return nil, err
+	}
+
 	err = s.pub.PublishForReceivers(
 		ctx,
 		services.GetReceivingMembers(g.Members, domain.UserID(req.SenderID)),
@@ -148,6 +158,13 @@ func (s *GroupPhotoService) DeletePhoto(ctx context.Context, req request.DeleteG
 
 	gDto := dto.NewGroupChatDTO(g)
 
+	err = recordServiceMessage(ctx, tx, s.updateRepo, s.pub, g.Members,
+		domain.NewPhotoChangedMessage(g, domain.UserID(req.SenderID), g.GroupPhoto),
+	)
+	if err != nil {
+		return nil, err
+	}
+
 	err = s.pub.PublishForReceivers(
 		ctx,
 		services.GetReceivingMembers(g.Members, domain.UserID(req.SenderID)),
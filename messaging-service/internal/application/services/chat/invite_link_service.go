@@ -0,0 +1,407 @@
+package chat
+
+import (
+	"context"
+	"errors"
+
+	"github.com/chakchat/chakchat-backend/messaging-service/internal/application/dto"
+	"github.com/chakchat/chakchat-backend/messaging-service/internal/application/publish"
+	"github.com/chakchat/chakchat-backend/messaging-service/internal/application/publish/events"
+	"github.com/chakchat/chakchat-backend/messaging-service/internal/application/request"
+	"github.com/chakchat/chakchat-backend/messaging-service/internal/application/services"
+	"github.com/chakchat/chakchat-backend/messaging-service/internal/application/storage"
+	"github.com/chakchat/chakchat-backend/messaging-service/internal/application/storage/repository"
+	"github.com/chakchat/chakchat-backend/messaging-service/internal/domain"
+	"github.com/chakchat/chakchat-backend/messaging-service/internal/domain/group"
+	"github.com/google/uuid"
+)
+
+// InviteLinkService manages invite links and join requests of group chats
+type InviteLinkService struct {
+	txProvider storage.TxProvider
+	groupRepo  repository.GroupChatRepository
+	linkRepo   repository.InviteLinkRepository
+	banRepo    repository.GroupBanRepository
+	updateRepo repository.UpdateRepository
+	pub        publish.Publisher
+}
+
+func NewInviteLinkService(
+	txProvider storage.TxProvider,
+	groupRepo repository.GroupChatRepository,
+	linkRepo repository.InviteLinkRepository,
+	banRepo repository.GroupBanRepository,
+	updateRepo repository.UpdateRepository,
+	pub publish.Publisher,
+) *InviteLinkService {
+	return &InviteLinkService{
+		txProvider: txProvider,
+		groupRepo:  groupRepo,
+		linkRepo:   linkRepo,
+		banRepo:    banRepo,
+		updateRepo: updateRepo,
+		pub:        pub,
+	}
+}
+
+func (s *InviteLinkService) CreateInviteLink(
+	ctx context.Context, req request.CreateInviteLink,
+) (_ *dto.InviteLinkDTO, err error) {
+	tx, err := s.txProvider.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer storage.FinishTx(ctx, tx, &err)
+
+	g, err := s.findGroup(ctx, tx, req.ChatID)
+	if err != nil {
+		return nil, err
+	}
+
+	link, err := g.NewInviteLink(
+		domain.UserID(req.SenderID), timestampPtr(req.ExpiresAt), req.UsageLimit, req.RequiresApproval,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	link, err = s.linkRepo.Create(ctx, tx, link)
+	if err != nil {
+		return nil, err
+	}
+
+	linkDto := dto.NewInviteLinkDTO(link)
+	return &linkDto, nil
+}
+
+func (s *InviteLinkService) GetInviteLinks(
+	ctx context.Context, req request.GetInviteLinks,
+) (_ []dto.InviteLinkDTO, err error) {
+	tx, err := s.txProvider.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer storage.FinishTx(ctx, tx, &err)
+
+	g, err := s.findGroup(ctx, tx, req.ChatID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := g.ValidateCanManageInvites(domain.UserID(req.SenderID)); err != nil {
+		return nil, err
+	}
+
+	links, err := s.linkRepo.GetByChat(ctx, tx, g.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	return dto.NewInviteLinkDTOs(links), nil
+}
+
+func (s *InviteLinkService) RevokeInviteLink(
+	ctx context.Context, req request.RevokeInviteLink,
+) (_ *dto.InviteLinkDTO, err error) {
+	tx, err := s.txProvider.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer storage.FinishTx(ctx, tx, &err)
+
+	g, err := s.findGroup(ctx, tx, req.ChatID)
+	if err != nil {
+		return nil, err
+	}
+
+	link, err := s.findLink(ctx, tx, req.Token)
+	if err != nil {
+		return nil, err
+	}
+
+	err = g.RevokeInviteLink(domain.UserID(req.SenderID), link)
+	if err != nil {
+		return nil, err
+	}
+
+	link, err = s.linkRepo.Update(ctx, tx, link)
+	if err != nil {
+		return nil, err
+	}
+
+	linkDto := dto.NewInviteLinkDTO(link)
+	return &linkDto, nil
+}
+
+func (s *InviteLinkService) JoinByInviteLink(
+	ctx context.Context, req request.JoinByInviteLink,
+) (_ *dto.GroupChatDTO, err error) {
+	tx, err := s.txProvider.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer storage.FinishTx(ctx, tx, &err)
+
+	link, err := s.findLink(ctx, tx, req.Token)
+	if err != nil {
+		return nil, err
+	}
+
+	g, err := s.findGroup(ctx, tx, uuid.UUID(link.ChatID))
+	if err != nil {
+		return nil, err
+	}
+
+	err = validateNotBanned(ctx, tx, s.banRepo, g, req.SenderID)
+	if err != nil {
+		return nil, err
+	}
+
+	err = g.JoinByLink(domain.UserID(req.SenderID), link)
+	if err != nil {
+		return nil, err
+	}
+
+	g, err = s.groupRepo.Update(ctx, tx, g)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := s.linkRepo.Update(ctx, tx, link); err != nil {
+		return nil, err
+	}
+
+	gDto := dto.NewGroupChatDTO(g)
+
+	err = recordServiceMessage(ctx, tx, s.updateRepo, s.pub, g.Members,
+		domain.NewMembersAddedMessage(g, domain.UserID(req.SenderID), []domain.UserID{domain.UserID(req.SenderID)}),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	err = publishMembersAdded(ctx, s.pub, g, req.SenderID, req.SenderID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &gDto, nil
+}
+
+func (s *InviteLinkService) RequestToJoin(
+	ctx context.Context, req request.JoinByInviteLink,
+) (_ *dto.JoinRequestDTO, err error) {
+	tx, err := s.txProvider.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer storage.FinishTx(ctx, tx, &err)
+
+	link, err := s.findLink(ctx, tx, req.Token)
+	if err != nil {
+		return nil, err
+	}
+
+	g, err := s.findGroup(ctx, tx, uuid.UUID(link.ChatID))
+	if err != nil {
+		return nil, err
+	}
+
+	err = validateNotBanned(ctx, tx, s.banRepo, g, req.SenderID)
+	if err != nil {
+		return nil, err
+	}
+
+	joinReq, err := g.RequestToJoin(domain.UserID(req.SenderID), link)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = s.linkRepo.FindJoinRequest(ctx, tx, g.ID, joinReq.UserID)
+	if err == nil {
+		return nil, services.ErrJoinRequestExists
+	}
+	if !errors.Is(err, repository.ErrNotFound) {
+		return nil, err
+	}
+
+	joinReq, err = s.linkRepo.CreateJoinRequest(ctx, tx, joinReq)
+	if err != nil {
+		return nil, err
+	}
+
+	reqDto := dto.NewJoinRequestDTO(joinReq)
+
+	err = s.pub.PublishForReceivers(
+		ctx,
+		inviteManagers(g),
+		events.TypeGroupJoinRequested,
+		events.GroupJoinRequested{
+			ChatID: uuid.UUID(g.ID),
+			UserID: req.SenderID,
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &reqDto, nil
+}
+
+func (s *InviteLinkService) GetJoinRequests(
+	ctx context.Context, req request.GetJoinRequests,
+) (_ []dto.JoinRequestDTO, err error) {
+	tx, err := s.txProvider.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer storage.FinishTx(ctx, tx, &err)
+
+	g, err := s.findGroup(ctx, tx, req.ChatID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := g.ValidateCanManageInvites(domain.UserID(req.SenderID)); err != nil {
+		return nil, err
+	}
+
+	reqs, err := s.linkRepo.GetJoinRequests(ctx, tx, g.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	return dto.NewJoinRequestDTOs(reqs), nil
+}
+
+func (s *InviteLinkService) ApproveJoinRequest(
+	ctx context.Context, req request.ResolveJoinRequest,
+) (_ *dto.GroupChatDTO, err error) {
+	tx, err := s.txProvider.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer storage.FinishTx(ctx, tx, &err)
+
+	g, err := s.findGroup(ctx, tx, req.ChatID)
+	if err != nil {
+		return nil, err
+	}
+
+	joinReq, err := s.findJoinRequest(ctx, tx, g.ID, req.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	err = validateNotBanned(ctx, tx, s.banRepo, g, req.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	err = g.ApproveJoinRequest(domain.UserID(req.SenderID), joinReq)
+	if err != nil {
+		return nil, err
+	}
+
+	g, err = s.groupRepo.Update(ctx, tx, g)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.linkRepo.DeleteJoinRequest(ctx, tx, g.ID, joinReq.UserID); err != nil {
+		return nil, err
+	}
+
+	gDto := dto.NewGroupChatDTO(g)
+
+	err = recordServiceMessage(ctx, tx, s.updateRepo, s.pub, g.Members,
+		domain.NewMembersAddedMessage(g, domain.UserID(req.SenderID), []domain.UserID{domain.UserID(req.UserID)}),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	err = publishMembersAdded(ctx, s.pub, g, req.SenderID, req.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &gDto, nil
+}
+
+func (s *InviteLinkService) DeclineJoinRequest(
+	ctx context.Context, req request.ResolveJoinRequest,
+) (err error) {
+	tx, err := s.txProvider.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer storage.FinishTx(ctx, tx, &err)
+
+	g, err := s.findGroup(ctx, tx, req.ChatID)
+	if err != nil {
+		return err
+	}
+
+	joinReq, err := s.findJoinRequest(ctx, tx, g.ID, req.UserID)
+	if err != nil {
+		return err
+	}
+
+	err = g.DeclineJoinRequest(domain.UserID(req.SenderID), joinReq)
+	if err != nil {
+		return err
+	}
+
+	return s.linkRepo.DeleteJoinRequest(ctx, tx, g.ID, joinReq.UserID)
+}
+
+func (s *InviteLinkService) findGroup(
+	ctx context.Context, db storage.ExecQuerier, chatID uuid.UUID,
+) (*group.GroupChat, error) {
+	g, err := s.groupRepo.FindById(ctx, db, domain.ChatID(chatID))
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, services.ErrChatNotFound
+		}
+		return nil, err
+	}
+	return g, nil
+}
+
+func (s *InviteLinkService) findLink(
+	ctx context.Context, db storage.ExecQuerier, token string,
+) (*group.InviteLink, error) {
+	link, err := s.linkRepo.FindByToken(ctx, db, token)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, services.ErrInviteLinkNotFound
+		}
+		return nil, err
+	}
+	return link, nil
+}
+
+func (s *InviteLinkService) findJoinRequest(
+	ctx context.Context, db storage.ExecQuerier, chatID domain.ChatID, userID uuid.UUID,
+) (*group.JoinRequest, error) {
+	joinReq, err := s.linkRepo.FindJoinRequest(ctx, db, chatID, domain.UserID(userID))
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, services.ErrJoinRequestNotFound
+		}
+		return nil, err
+	}
+	return joinReq, nil
+}
+
+// inviteManagers returns members who can approve join requests
+func inviteManagers(g *group.GroupChat) []uuid.UUID {
+	res := make([]uuid.UUID, 0, len(g.Admins)+1)
+	for _, member := range g.Members {
+		if g.ValidateCanManageInvites(member) == nil {
+			res = append(res, uuid.UUID(member))
+		}
+	}
+	return res
+}
@@ -20,15 +20,20 @@ import (
 type SecretGroupChatService struct {
 	txProvider storage.TxProvider
 	repo       repository.SecretGroupChatRepository
+	updateRepo repository.UpdateRepository
 	pub        publish.Publisher
 }
 
 func NewSecretGroupChatService(
-	txProvider storage.TxProvider, repo repository.SecretGroupChatRepository, pub publish.Publisher,
+	txProvider storage.TxProvider,
+	repo repository.SecretGroupChatRepository,
+	updateRepo repository.UpdateRepository,
+	pub publish.Publisher,
 ) *SecretGroupChatService {
 	return &SecretGroupChatService{
 		txProvider: txProvider,
 		repo:       repo,
+		updateRepo: updateRepo,
 		pub:        pub,
 	}
 }
@@ -106,6 +111,13 @@ func (s *SecretGroupChatService) UpdateGroupInfo(
 
 	gDto := dto.NewSecretGroupChatDTO(g)
 
+	err = recordServiceMessage(ctx, tx, s.updateRepo, s.pub, g.Members,
+		domain.NewInfoChangedMessage(g, domain.UserID(req.SenderID), g.Name, g.Description),
+	)
+	if err != nil {
+		return nil, err
+	}
+
 	err = s.pub.PublishForReceivers(
 		ctx,
 		services.GetReceivingMembers(g.Members, domain.UserID(req.SenderID)),
@@ -191,6 +203,13 @@ func (s *SecretGroupChatService) AddMember(
 		return nil, err
 	}
 
+	err = recordServiceMessage(ctx, tx, s.updateRepo, s.pub, g.Members,
+		domain.NewMembersAddedMessage(g, domain.UserID(req.SenderID), []domain.UserID{domain.UserID(req.MemberID)}),
+	)
+	if err != nil {
+		return nil, err
+	}
+
 	err = s.pub.PublishForReceivers(
 		ctx,
 		services.GetReceivingMembers(g.Members, domain.UserID(req.SenderID)),
@@ -223,8 +242,10 @@ func (s *SecretGroupChatService) DeleteMember(
 		if errors.Is(err, repository.ErrNotFound) {
 			return nil, services.ErrChatNotFound
 		}
+		return nil, err
 	}
 
+	prevOwner := g.Owner
 	err = g.DeleteMember(domain.UserID(req.SenderID), domain.UserID(req.MemberID))
 
 	if err != nil {
@@ -238,6 +259,13 @@ func (s *SecretGroupChatService) DeleteMember(
 
 	gDto := dto.NewSecretGroupChatDTO(g)
 
+	err = recordServiceMessage(ctx, tx, s.updateRepo, s.pub, g.Members,
+		domain.NewMembersRemovedMessage(g, domain.UserID(req.SenderID), []domain.UserID{domain.UserID(req.MemberID)}),
+	)
+	if err != nil {
+		return nil, err
+	}
+
 	err = s.pub.PublishForReceivers(
 		ctx,
 		services.GetReceivingMembers(g.Members, domain.UserID(req.SenderID)),
@@ -252,9 +280,36 @@ func (s *SecretGroupChatService) DeleteMember(
 		return nil, err
 	}
 
+	if g.Owner != prevOwner {
+		err = s.pub.PublishForReceivers(
+			ctx,
+			services.GetReceivingMembers(g.Members, domain.UserID(req.SenderID)),
+			events.TypeGroupOwnerChanged,
+			events.GroupOwnerChanged{
+				SenderID:        req.SenderID,
+				ChatID:          req.ChatID,
+				PreviousOwnerID: uuid.UUID(prevOwner),
+				OwnerID:         uuid.UUID(g.Owner),
+			},
+		)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	return &gDto, nil
 }
 
+// LeaveGroup removes the sender from the group.
+// If the sender is the owner, ownership passes to the oldest remaining admin or member.
+func (s *SecretGroupChatService) LeaveGroup(ctx context.Context, req request.LeaveGroup) (*dto.SecretGroupChatDTO, error) {
+	return s.DeleteMember(ctx, request.DeleteMember{
+		ChatID:   req.ChatID,
+		SenderID: req.SenderID,
+		MemberID: req.SenderID,
+	})
+}
+
 func (s *SecretGroupChatService) SetExpiration(
 	ctx context.Context, req request.SetExpiration,
 ) (_ *dto.SecretGroupChatDTO, err error) {
@@ -282,6 +337,13 @@ func (s *SecretGroupChatService) SetExpiration(
 		return nil, err
 	}
 
+	err = recordServiceMessage(ctx, tx, s.updateRepo, s.pub, g.Members,
+		domain.NewExpirationChangedMessage(g, domain.UserID(req.SenderID), g.Exp),
+	)
+	if err != nil {
+		return nil, err
+	}
+
 	err = s.pub.PublishForReceivers(
 		ctx,
 		services.GetReceivingMembers(g.Members[:], domain.UserID(req.SenderID)),
@@ -299,3 +361,198 @@ func (s *SecretGroupChatService) SetExpiration(
 	gDto := dto.NewSecretGroupChatDTO(g)
 	return &gDto, nil
 }
+
+func (s *SecretGroupChatService) SetAdmin(ctx context.Context, req request.SetGroupAdmin) (_ *dto.SecretGroupChatDTO, err error) {
+	perms, err := domain.NewAdminPermissions(req.Permissions)
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := s.txProvider.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer storage.FinishTx(ctx, tx, &err)
+
+	g, err := s.repo.FindById(ctx, tx, domain.ChatID(req.ChatID))
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, services.ErrChatNotFound
+		}
+		return nil, err
+	}
+
+	err = g.SetAdmin(domain.UserID(req.SenderID), domain.UserID(req.MemberID), perms)
+	if err != nil {
+		return nil, err
+	}
+
+	g, err = s.repo.Update(ctx, tx, g)
+	if err != nil {
+		return nil, err
+	}
+
+	gDto := dto.NewSecretGroupChatDTO(g)
+
+	err = s.pub.PublishForReceivers(
+		ctx,
+		services.GetReceivingMembers(g.Members, domain.UserID(req.SenderID)),
+		events.TypeGroupMemberRoleChanged,
+		events.GroupMemberRoleChanged{
+			SenderID:    req.SenderID,
+			ChatID:      req.ChatID,
+			MemberID:    req.MemberID,
+			Role:        events.RoleAdmin,
+			Permissions: perms.Names(),
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &gDto, nil
+}
+
+func (s *SecretGroupChatService) RemoveAdmin(ctx context.Context, req request.RemoveGroupAdmin) (_ *dto.SecretGroupChatDTO, err error) {
+	tx, err := s.txProvider.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer storage.FinishTx(ctx, tx, &err)
+
+	g, err := s.repo.FindById(ctx, tx, domain.ChatID(req.ChatID))
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, services.ErrChatNotFound
+		}
+		return nil, err
+	}
+
+	err = g.RemoveAdmin(domain.UserID(req.SenderID), domain.UserID(req.MemberID))
+	if err != nil {
+		return nil, err
+	}
+
+	g, err = s.repo.Update(ctx, tx, g)
+	if err != nil {
+		return nil, err
+	}
+
+	gDto := dto.NewSecretGroupChatDTO(g)
+
+	err = s.pub.PublishForReceivers(
+		ctx,
+		services.GetReceivingMembers(g.Members, domain.UserID(req.SenderID)),
+		events.TypeGroupMemberRoleChanged,
+		events.GroupMemberRoleChanged{
+			SenderID:    req.SenderID,
+			ChatID:      req.ChatID,
+			MemberID:    req.MemberID,
+			Role:        events.RoleMember,
+			Permissions: []string{},
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &gDto, nil
+}
+
+func (s *SecretGroupChatService) TransferOwnership(
+	ctx context.Context, req request.TransferGroupOwnership,
+) (_ *dto.SecretGroupChatDTO, err error) {
+	tx, err := s.txProvider.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer storage.FinishTx(ctx, tx, &err)
+
+	g, err := s.repo.FindById(ctx, tx, domain.ChatID(req.ChatID))
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, services.ErrChatNotFound
+		}
+		return nil, err
+	}
+
+	err = g.TransferOwnership(domain.UserID(req.SenderID), domain.UserID(req.MemberID))
+	if err != nil {
+		return nil, err
+	}
+
+	g, err = s.repo.Update(ctx, tx, g)
+	if err != nil {
+		return nil, err
+	}
+
+	gDto := dto.NewSecretGroupChatDTO(g)
+
+	err = s.pub.PublishForReceivers(
+		ctx,
+		services.GetReceivingMembers(g.Members, domain.UserID(req.SenderID)),
+		events.TypeGroupOwnerChanged,
+		events.GroupOwnerChanged{
+			SenderID:        req.SenderID,
+			ChatID:          req.ChatID,
+			PreviousOwnerID: req.SenderID,
+			OwnerID:         req.MemberID,
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &gDto, nil
+}
+
+func (s *SecretGroupChatService) SetMemberPermissions(
+	ctx context.Context, req request.SetMemberPermissions,
+) (_ *dto.SecretGroupChatDTO, err error) {
+	perms, err := domain.NewMemberPermissions(req.Permissions)
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := s.txProvider.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer storage.FinishTx(ctx, tx, &err)
+
+	g, err := s.repo.FindById(ctx, tx, domain.ChatID(req.ChatID))
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, services.ErrChatNotFound
+		}
+		return nil, err
+	}
+
+	err = g.SetMemberPermissions(domain.UserID(req.SenderID), perms)
+	if err != nil {
+		return nil, err
+	}
+
+	g, err = s.repo.Update(ctx, tx, g)
+	if err != nil {
+		return nil, err
+	}
+
+	gDto := dto.NewSecretGroupChatDTO(g)
+
+	err = s.pub.PublishForReceivers(
+		ctx,
+		services.GetReceivingMembers(g.Members, domain.UserID(req.SenderID)),
+		events.TypeGroupMemberPermissionsChanged,
+		events.GroupMemberPermissionsChanged{
+			SenderID:    req.SenderID,
+			ChatID:      req.ChatID,
+			Permissions: perms.Names(),
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &gDto, nil
+}
@@ -19,6 +19,7 @@ type SecretGroupPhotoService struct {
 	txProvider storage.TxProvider
 	repo       repository.SecretGroupChatRepository
 	files      external.FileStorage
+	updateRepo repository.UpdateRepository
 	pub        publish.Publisher
 }
 
@@ -26,11 +27,13 @@ func NewSecretGroupPhotoService(
 	txProvider storage.TxProvider,
 	repo repository.SecretGroupChatRepository,
 	files external.FileStorage,
+	updateRepo repository.UpdateRepository,
 	pub publish.Publisher,
 ) *SecretGroupPhotoService {
 	return &SecretGroupPhotoService{
 		repo:       repo,
 		files:      files,
+		updateRepo: updateRepo,
 		pub:        pub,
 		txProvider: txProvider,
 	}
@@ -78,6 +81,13 @@ func (s *SecretGroupPhotoService) UpdatePhoto(
 
 	gDto := dto.NewSecretGroupChatDTO(g)
 
+	err = recordServiceMessage(ctx, tx, s.updateRepo, s.pub, g.Members,
+		domain.NewPhotoChangedMessage(g, domain.UserID(req.SenderID), g.GroupPhoto),
+	)
+	if err != nil {
+		return nil, err
+	}
+
 	err = s.pub.PublishForReceivers(
 		ctx,
 		services.GetReceivingMembers(g.Members, domain.UserID(req.SenderID)),
@@ -127,6 +137,13 @@ func (s *SecretGroupPhotoService) DeletePhoto(
 
 	gDto := dto.NewSecretGroupChatDTO(g)
 
+	err = recordServiceMessage(ctx, tx, s.updateRepo, s.pub, g.Members,
+		domain.NewPhotoChangedMessage(g, domain.UserID(req.SenderID), g.GroupPhoto),
+	)
+	if err != nil {
+		return nil, err
+	}
+
 	err = s.pub.PublishForReceivers(
 		ctx,
 		services.GetReceivingMembers(g.Members, domain.UserID(req.SenderID)),
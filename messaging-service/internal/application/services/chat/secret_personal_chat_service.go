@@ -20,16 +20,19 @@ import (
 type SecretPersonalChatService struct {
 	txProvider storage.TxProvider
 	repo       repository.SecretPersonalChatRepository
+	updateRepo repository.UpdateRepository
 	pub        publish.Publisher
 }
 
 func NewSecretPersonalChatService(
 	txProvider storage.TxProvider,
 	repo repository.SecretPersonalChatRepository,
+	updateRepo repository.UpdateRepository,
 	pub publish.Publisher,
 ) *SecretPersonalChatService {
 	return &SecretPersonalChatService{
 		repo:       repo,
+		updateRepo: updateRepo,
 		txProvider: txProvider,
 		pub:        pub,
 	}
@@ -127,6 +130,13 @@ func (s *SecretPersonalChatService) SetExpiration(
 		return nil, err
 	}
 
+	err = recordServiceMessage(ctx, tx, s.updateRepo, s.pub, chat.Members[:],
+		domain.NewExpirationChangedMessage(chat, domain.UserID(req.SenderID), chat.Exp),
+	)
+	if err != nil {
+		return nil, err
+	}
+
 	err = s.pub.PublishForReceivers(
 		ctx,
 		services.GetReceivingMembers(chat.Members[:], domain.UserID(req.SenderID)),
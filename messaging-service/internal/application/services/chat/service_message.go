@@ -0,0 +1,40 @@
+package chat
+
+import (
+	"context"
+
+	"github.com/chakchat/chakchat-backend/messaging-service/internal/application/dto"
+	"github.com/chakchat/chakchat-backend/messaging-service/internal/application/generic"
+	"github.com/chakchat/chakchat-backend/messaging-service/internal/application/publish"
+	"github.com/chakchat/chakchat-backend/messaging-service/internal/application/publish/events"
+	"github.com/chakchat/chakchat-backend/messaging-service/internal/application/services"
+	"github.com/chakchat/chakchat-backend/messaging-service/internal/application/storage"
+	"github.com/chakchat/chakchat-backend/messaging-service/internal/application/storage/repository"
+	"github.com/chakchat/chakchat-backend/messaging-service/internal/domain"
+)
+
+// recordServiceMessage stores the service message in the chat update history
+// and sends it as an update to the members except the sender.
+// It is sent in addition to the chat event, so online clients may rely on any of them.
+func recordServiceMessage(
+	ctx context.Context,
+	db storage.ExecQuerier,
+	updateRepo repository.UpdateRepository,
+	pub publish.Publisher,
+	members []domain.UserID,
+	msg *domain.ServiceMessage,
+) error {
+	msg, err := updateRepo.CreateServiceMessage(ctx, db, msg)
+	if err != nil {
+		return err
+	}
+
+	msgDto := dto.NewServiceMessageDTO(msg)
+
+	return pub.PublishForReceivers(
+		ctx,
+		services.GetReceivingUpdateMembers(members, msg.SenderID, &msg.Update),
+		events.TypeUpdate,
+		generic.FromServiceMessageDTO(&msgDto),
+	)
+}
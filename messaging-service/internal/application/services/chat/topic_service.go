@@ -0,0 +1,305 @@
+package chat
+
+import (
+	"context"
+	"errors"
+
+	"github.com/chakchat/chakchat-backend/messaging-service/internal/application/dto"
+	"github.com/chakchat/chakchat-backend/messaging-service/internal/application/publish"
+	"github.com/chakchat/chakchat-backend/messaging-service/internal/application/publish/events"
+	"github.com/chakchat/chakchat-backend/messaging-service/internal/application/request"
+	"github.com/chakchat/chakchat-backend/messaging-service/internal/application/services"
+	"github.com/chakchat/chakchat-backend/messaging-service/internal/application/storage"
+	"github.com/chakchat/chakchat-backend/messaging-service/internal/application/storage/repository"
+	"github.com/chakchat/chakchat-backend/messaging-service/internal/domain"
+	"github.com/chakchat/chakchat-backend/messaging-service/internal/domain/group"
+	"github.com/google/uuid"
+)
+
+// TopicService manages forum topics of groups
+type TopicService struct {
+	txProvider storage.TxProvider
+	groupRepo  repository.GroupChatRepository
+	topicRepo  repository.TopicRepository
+	pub        publish.Publisher
+}
+
+func NewTopicService(
+	txProvider storage.TxProvider,
+	groupRepo repository.GroupChatRepository,
+	topicRepo repository.TopicRepository,
+	pub publish.Publisher,
+) *TopicService {
+	return &TopicService{
+		txProvider: txProvider,
+		groupRepo:  groupRepo,
+		topicRepo:  topicRepo,
+		pub:        pub,
+	}
+}
+
+func (s *TopicService) CreateTopic(
+	ctx context.Context, req request.CreateTopic,
+) (_ *dto.TopicDTO, err error) {
+	tx, err := s.txProvider.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer storage.FinishTx(ctx, tx, &err)
+
+	g, err := s.groupRepo.FindById(ctx, tx, domain.ChatID(req.ChatID))
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, services.ErrChatNotFound
+		}
+		return nil, err
+	}
+
+	topic, err := g.NewTopic(domain.UserID(req.SenderID), req.Title, req.Icon)
+	if err != nil {
+		return nil, err
+	}
+
+	topic, err = s.topicRepo.Create(ctx, tx, topic)
+	if err != nil {
+		return nil, err
+	}
+
+	topicDto := dto.NewTopicDTO(topic)
+
+	err = s.publishTopicInfo(ctx, topic, req.SenderID, events.TypeTopicCreated)
+	if err != nil {
+		return nil, err
+	}
+
+	return &topicDto, nil
+}
+
+func (s *TopicService) UpdateTopic(
+	ctx context.Context, req request.UpdateTopic,
+) (_ *dto.TopicDTO, err error) {
+	tx, err := s.txProvider.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer storage.FinishTx(ctx, tx, &err)
+
+	topic, err := s.findTopic(ctx, tx, req.ChatID, req.TopicID)
+	if err != nil {
+		return nil, err
+	}
+
+	err = topic.UpdateInfo(domain.UserID(req.SenderID), req.Title, req.Icon)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.updateTopic(ctx, tx, topic, req.SenderID)
+}
+
+func (s *TopicService) CloseTopic(
+	ctx context.Context, req request.TopicAction,
+) (_ *dto.TopicDTO, err error) {
+	tx, err := s.txProvider.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer storage.FinishTx(ctx, tx, &err)
+
+	topic, err := s.findTopic(ctx, tx, req.ChatID, req.TopicID)
+	if err != nil {
+		return nil, err
+	}
+
+	err = topic.Close(domain.UserID(req.SenderID))
+	if err != nil {
+		return nil, err
+	}
+
+	return s.updateTopic(ctx, tx, topic, req.SenderID)
+}
+
+func (s *TopicService) ReopenTopic(
+	ctx context.Context, req request.TopicAction,
+) (_ *dto.TopicDTO, err error) {
+	tx, err := s.txProvider.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer storage.FinishTx(ctx, tx, &err)
+
+	topic, err := s.findTopic(ctx, tx, req.ChatID, req.TopicID)
+	if err != nil {
+		return nil, err
+	}
+
+	err = topic.Reopen(domain.UserID(req.SenderID))
+	if err != nil {
+		return nil, err
+	}
+
+	return s.updateTopic(ctx, tx, topic, req.SenderID)
+}
+
+// DeleteTopic deletes the topic with all its updates
+func (s *TopicService) DeleteTopic(ctx context.Context, req request.TopicAction) (err error) {
+	tx, err := s.txProvider.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer storage.FinishTx(ctx, tx, &err)
+
+	topic, err := s.findTopic(ctx, tx, req.ChatID, req.TopicID)
+	if err != nil {
+		return err
+	}
+
+	err = topic.Delete(domain.UserID(req.SenderID))
+	if err != nil {
+		return err
+	}
+
+	err = s.topicRepo.Delete(ctx, tx, topic.ID)
+	if err != nil {
+		return err
+	}
+
+	return s.pub.PublishForReceivers(
+		ctx,
+		services.GetReceivingMembers(topic.Group.Members, domain.UserID(req.SenderID)),
+		events.TypeTopicDeleted,
+		events.TopicDeleted{
+			SenderID: req.SenderID,
+			ChatID:   req.ChatID,
+			TopicID:  req.TopicID,
+		},
+	)
+}
+
+// GetTopics returns topics of the group with the last update ID and unread messages count of the sender
+func (s *TopicService) GetTopics(
+	ctx context.Context, req request.GetTopics,
+) (_ []dto.TopicDTO, err error) {
+	tx, err := s.txProvider.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer storage.FinishTx(ctx, tx, &err)
+
+	g, err := s.groupRepo.FindById(ctx, tx, domain.ChatID(req.ChatID))
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, services.ErrChatNotFound
+		}
+		return nil, err
+	}
+	if !g.IsMember(domain.UserID(req.SenderID)) {
+		return nil, domain.ErrUserNotMember
+	}
+
+	topics, err := s.topicRepo.GetByChatID(ctx, tx, g.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	topicIDs := make([]domain.ChatID, len(topics))
+	for i, topic := range topics {
+		topicIDs[i] = topic.ID
+	}
+
+	stats, err := s.topicRepo.GetStats(ctx, tx, domain.UserID(req.SenderID), topicIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]dto.TopicDTO, len(topics))
+	for i, topic := range topics {
+		topic.Group = g
+		res[i] = dto.NewTopicDTO(topic)
+
+		lastUpdateID := int64(stats[topic.ID].LastUpdateID)
+		unreadCount := stats[topic.ID].UnreadCount
+		res[i].LastUpdateID = &lastUpdateID
+		res[i].UnreadCount = &unreadCount
+	}
+
+	return res, nil
+}
+
+// ReadTopic returns the number of messages of the topic that are still unread
+func (s *TopicService) ReadTopic(ctx context.Context, req request.ReadTopic) (_ int, err error) {
+	tx, err := s.txProvider.Begin(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer storage.FinishTx(ctx, tx, &err)
+
+	topic, err := s.findTopic(ctx, tx, req.ChatID, req.TopicID)
+	if err != nil {
+		return 0, err
+	}
+	if !topic.IsMember(domain.UserID(req.SenderID)) {
+		return 0, domain.ErrUserNotMember
+	}
+
+	err = s.topicRepo.MarkRead(ctx, tx, topic.ID, domain.UserID(req.SenderID), domain.UpdateID(req.UpdateID))
+	if err != nil {
+		return 0, err
+	}
+
+	stats, err := s.topicRepo.GetStats(ctx, tx, domain.UserID(req.SenderID), []domain.ChatID{topic.ID})
+	if err != nil {
+		return 0, err
+	}
+
+	return stats[topic.ID].UnreadCount, nil
+}
+
+func (s *TopicService) updateTopic(
+	ctx context.Context, db storage.ExecQuerier, topic *group.Topic, sender uuid.UUID,
+) (*dto.TopicDTO, error) {
+	topic, err := s.topicRepo.Update(ctx, db, topic)
+	if err != nil {
+		return nil, err
+	}
+
+	topicDto := dto.NewTopicDTO(topic)
+
+	err = s.publishTopicInfo(ctx, topic, sender, events.TypeTopicUpdated)
+	if err != nil {
+		return nil, err
+	}
+
+	return &topicDto, nil
+}
+
+func (s *TopicService) publishTopicInfo(
+	ctx context.Context, topic *group.Topic, sender uuid.UUID, typ string,
+) error {
+	return s.pub.PublishForReceivers(
+		ctx,
+		services.GetReceivingMembers(topic.Group.Members, domain.UserID(sender)),
+		typ,
+		events.TopicInfo{
+			SenderID: sender,
+			ChatID:   uuid.UUID(topic.Group.ID),
+			TopicID:  uuid.UUID(topic.ID),
+			Title:    topic.Title,
+			Icon:     topic.Icon,
+			Closed:   topic.Closed,
+		},
+	)
+}
+
+func (s *TopicService) findTopic(
+	ctx context.Context, db storage.ExecQuerier, chatID, topicID uuid.UUID,
+) (*group.Topic, error) {
+	topic, err := s.topicRepo.FindById(ctx, db, domain.ChatID(chatID), domain.ChatID(topicID))
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, services.ErrTopicNotFound
+		}
+		return nil, err
+	}
+	return topic, nil
+}
@@ -1,7 +1,12 @@
 package services
 
 import (
+	"context"
+
 	"github.com/chakchat/chakchat-backend/messaging-service/internal/application/external"
+	"github.com/chakchat/chakchat-backend/messaging-service/internal/application/publish"
+	"github.com/chakchat/chakchat-backend/messaging-service/internal/application/storage"
+	"github.com/chakchat/chakchat-backend/messaging-service/internal/application/storage/repository"
 	"github.com/chakchat/chakchat-backend/messaging-service/internal/domain"
 	"github.com/google/uuid"
 )
@@ -26,6 +31,46 @@ func GetReceivingUpdateMembers(members []domain.UserID, sender domain.UserID, up
 	return res
 }
 
+// GetThreadReceivers splits receivers of a new thread message into thread participants and other members.
+// Participants who are no longer members don't receive it.
+func GetThreadReceivers(
+	members []domain.UserID, participants []domain.UserID, sender domain.UserID, update *domain.Update,
+) (participating, others []uuid.UUID) {
+	isParticipant := make(map[domain.UserID]bool, len(participants))
+	for _, user := range participants {
+		isParticipant[user] = true
+	}
+
+	for _, user := range GetReceivingUpdateMembers(members, sender, update) {
+		if isParticipant[domain.UserID(user)] {
+			participating = append(participating, user)
+		} else {
+			others = append(others, user)
+		}
+	}
+	return participating, others
+}
+
+// PublishForSubscribers stores the event for all channel subscribers except the sender.
+// It is stored within the transaction and published later in batches of subscribers.
+func PublishForSubscribers(
+	ctx context.Context,
+	db storage.ExecQuerier,
+	fanoutRepo repository.ChannelFanoutRepository,
+	chatID domain.ChatID,
+	sender domain.UserID,
+	typ string,
+	data any,
+) error {
+	f, err := publish.NewFanout(uuid.UUID(chatID), uuid.UUID(sender), typ, data)
+	if err != nil {
+		return err
+	}
+
+	_, err = fanoutRepo.Create(ctx, db, f)
+	return err
+}
+
 func NewDomainFileMeta(f *external.FileMeta) domain.FileMeta {
 	return domain.FileMeta{
 		FileId:    f.FileId,
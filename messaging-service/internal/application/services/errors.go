@@ -12,12 +12,20 @@ func (e Error) Error() string {
 // If you add an error here
 // You alse should add it to `errmap` package
 var (
-	ErrFileNotFound         = Error{"service: file not found"}
-	ErrChatNotFound         = Error{"service: chat not found"}
-	ErrInvalidChatType      = Error{"service: invalid chat type"}
-	ErrInvalidPhoto         = Error{"service: invalid photo"}
-	ErrChatAlreadyExists    = Error{"service: chat already exists"}
-	ErrMessageNotFound      = Error{"service: message not found"}
-	ErrReactionNotFound     = Error{"service: reaction not found"}
-	ErrSecretUpdateNotFound = Error{"service: secret update is not found"}
+	ErrFileNotFound          = Error{"service: file not found"}
+	ErrChatNotFound          = Error{"service: chat not found"}
+	ErrInvalidChatType       = Error{"service: invalid chat type"}
+	ErrInvalidPhoto          = Error{"service: invalid photo"}
+	ErrChatAlreadyExists     = Error{"service: chat already exists"}
+	ErrMessageNotFound       = Error{"service: message not found"}
+	ErrReactionNotFound      = Error{"service: reaction not found"}
+	ErrSecretUpdateNotFound  = Error{"service: secret update is not found"}
+	ErrPollNotFound          = Error{"service: poll not found"}
+	ErrScheduledNotFound     = Error{"service: scheduled message not found"}
+	ErrMentionedUserNotFound = Error{"service: mentioned user not found"}
+	ErrInviteLinkNotFound    = Error{"service: invite link not found"}
+	ErrJoinRequestNotFound   = Error{"service: join request not found"}
+	ErrJoinRequestExists     = Error{"service: join request already exists"}
+	ErrBanNotFound           = Error{"service: ban not found"}
+	ErrTopicNotFound         = Error{"service: topic not found"}
 )
@@ -0,0 +1,255 @@
+package update
+
+import (
+	"context"
+	"errors"
+
+	"github.com/chakchat/chakchat-backend/messaging-service/internal/application/dto"
+	"github.com/chakchat/chakchat-backend/messaging-service/internal/application/generic"
+	"github.com/chakchat/chakchat-backend/messaging-service/internal/application/publish/events"
+	"github.com/chakchat/chakchat-backend/messaging-service/internal/application/request"
+	"github.com/chakchat/chakchat-backend/messaging-service/internal/application/services"
+	"github.com/chakchat/chakchat-backend/messaging-service/internal/application/storage"
+	"github.com/chakchat/chakchat-backend/messaging-service/internal/application/storage/repository"
+	"github.com/chakchat/chakchat-backend/messaging-service/internal/domain"
+	"github.com/chakchat/chakchat-backend/messaging-service/internal/domain/channel"
+	"github.com/google/uuid"
+)
+
+// ChannelUpdateService manages channel posts.
+// Posts are published for subscribers through the channel fanout.
+type ChannelUpdateService struct {
+	txProvider  storage.TxProvider
+	channelRepo repository.ChannelRepository
+	updateRepo  repository.UpdateRepository
+	fanoutRepo  repository.ChannelFanoutRepository
+}
+
+func NewChannelUpdateService(
+	txProvider storage.TxProvider,
+	channelRepo repository.ChannelRepository,
+	updateRepo repository.UpdateRepository,
+	fanoutRepo repository.ChannelFanoutRepository,
+) *ChannelUpdateService {
+	return &ChannelUpdateService{
+		txProvider:  txProvider,
+		channelRepo: channelRepo,
+		updateRepo:  updateRepo,
+		fanoutRepo:  fanoutRepo,
+	}
+}
+
+func (s *ChannelUpdateService) SendPost(
+	ctx context.Context, req request.SendTextMessage,
+) (_ *dto.TextMessageDTO, err error) {
+	tx, err := s.txProvider.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer storage.FinishTx(ctx, tx, &err)
+
+	c, err := s.findChannel(ctx, tx, req.ChatID, req.SenderID)
+	if err != nil {
+		return nil, err
+	}
+
+	var replyToMessage *domain.Message
+	if req.ReplyToMessage != nil {
+		replyToMessage, err = s.updateRepo.FindGenericMessage(ctx, tx, c.ID, domain.UpdateID(*req.ReplyToMessage), domain.UserID(req.SenderID))
+		if err != nil {
+			if errors.Is(err, repository.ErrNotFound) {
+				return nil, services.ErrMessageNotFound
+			}
+			return nil, err
+		}
+	}
+
+	msg, err := domain.NewTextMessage(c, domain.UserID(req.SenderID), req.Text, toDomainEntities(req.Entities), replyToMessage)
+	if err != nil {
+		return nil, err
+	}
+
+	msg, err = s.updateRepo.CreateTextMessage(ctx, tx, msg)
+	if err != nil {
+		return nil, err
+	}
+
+	msgDto := dto.NewTextMessageDTO(msg)
+
+	err = services.PublishForSubscribers(
+		ctx, tx, s.fanoutRepo, c.ID, msg.SenderID,
+		events.TypeUpdate,
+		generic.FromTextMessageDTO(&msgDto),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &msgDto, nil
+}
+
+func (s *ChannelUpdateService) EditPost(
+	ctx context.Context, req request.EditTextMessage,
+) (_ *dto.TextMessageDTO, err error) {
+	tx, err := s.txProvider.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer storage.FinishTx(ctx, tx, &err)
+
+	c, err := s.findChannel(ctx, tx, req.ChatID, req.SenderID)
+	if err != nil {
+		return nil, err
+	}
+
+	msg, err := s.updateRepo.FindTextMessage(ctx, tx, c.ID, domain.UpdateID(req.MessageID), domain.UserID(req.SenderID))
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, services.ErrMessageNotFound
+		}
+		return nil, err
+	}
+
+	err = msg.Edit(c, domain.UserID(req.SenderID), req.NewText, toDomainEntities(req.NewEntities))
+	if err != nil {
+		return nil, err
+	}
+
+	msg.Edited, err = s.updateRepo.CreateTextMessageEdited(ctx, tx, msg.Edited)
+	if err != nil {
+		return nil, err
+	}
+	msg, err = s.updateRepo.UpdateTextMessage(ctx, tx, msg)
+	if err != nil {
+		return nil, err
+	}
+
+	msgDto := dto.NewTextMessageDTO(msg)
+
+	err = services.PublishForSubscribers(
+		ctx, tx, s.fanoutRepo, c.ID, msg.Edited.SenderID,
+		events.TypeUpdate,
+		generic.FromTextMessageEditedDTO(msgDto.Edited),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &msgDto, nil
+}
+
+func (s *ChannelUpdateService) DeletePost(
+	ctx context.Context, req request.DeleteMessage,
+) (_ *dto.UpdateDeletedDTO, err error) {
+	tx, err := s.txProvider.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer storage.FinishTx(ctx, tx, &err)
+
+	c, err := s.findChannel(ctx, tx, req.ChatID, req.SenderID)
+	if err != nil {
+		return nil, err
+	}
+
+	msg, err := s.updateRepo.FindGenericMessage(ctx, tx, c.ID, domain.UpdateID(req.MessageID), domain.UserID(req.SenderID))
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, services.ErrMessageNotFound
+		}
+		return nil, err
+	}
+
+	deleteMode, err := domain.NewDeleteMode(req.DeleteMode)
+	if err != nil {
+		return nil, err
+	}
+
+	err = msg.Delete(c, domain.UserID(req.SenderID), deleteMode)
+	if err != nil {
+		return nil, err
+	}
+
+	deleted, err := s.updateRepo.CreateUpdateDeleted(ctx, tx, msg.Deleted[len(msg.Deleted)-1])
+	if err != nil {
+		return nil, err
+	}
+
+	deletedDto := dto.NewUpdateDeletedDTO(deleted)
+
+	if msg.DeletedForAll() {
+		err = services.PublishForSubscribers(
+			ctx, tx, s.fanoutRepo, c.ID, domain.UserID(req.SenderID),
+			events.TypeUpdate,
+			generic.FromUpdateDeletedDTO(&deletedDto),
+		)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return &deletedDto, nil
+}
+
+// ViewPosts counts views of the posts by the sender and returns views of the posts.
+// Each subscriber's view is counted only once.
+func (s *ChannelUpdateService) ViewPosts(
+	ctx context.Context, req request.ViewPosts,
+) (_ []dto.PostViewsDTO, err error) {
+	tx, err := s.txProvider.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer storage.FinishTx(ctx, tx, &err)
+
+	c, err := s.findChannel(ctx, tx, req.ChatID, req.SenderID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := c.ValidateCanView(domain.UserID(req.SenderID), len(req.PostIDs)); err != nil {
+		return nil, err
+	}
+
+	posts := make([]domain.UpdateID, len(req.PostIDs))
+	for i, id := range req.PostIDs {
+		posts[i] = domain.UpdateID(id)
+	}
+
+	err = s.channelRepo.AddViews(ctx, tx, c.ID, domain.UserID(req.SenderID), posts)
+	if err != nil {
+		return nil, err
+	}
+
+	views, err := s.channelRepo.GetViews(ctx, tx, c.ID, posts)
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]dto.PostViewsDTO, 0, len(views))
+	for _, id := range req.PostIDs {
+		if count, ok := views[domain.UpdateID(id)]; ok {
+			res = append(res, dto.PostViewsDTO{
+				PostID: id,
+				Views:  count,
+			})
+			// Duplicate IDs are returned once
+			delete(views, domain.UpdateID(id))
+		}
+	}
+
+	return res, nil
+}
+
+func (s *ChannelUpdateService) findChannel(
+	ctx context.Context, db storage.ExecQuerier, chatID, sender uuid.UUID,
+) (*channel.Channel, error) {
+	c, err := s.channelRepo.FindById(ctx, db, domain.ChatID(chatID), domain.UserID(sender))
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, services.ErrChatNotFound
+		}
+		return nil, err
+	}
+	return c, nil
+}
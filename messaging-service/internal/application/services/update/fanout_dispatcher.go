@@ -0,0 +1,163 @@
+package update
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/chakchat/chakchat-backend/messaging-service/internal/application/publish"
+	"github.com/chakchat/chakchat-backend/messaging-service/internal/application/storage"
+	"github.com/chakchat/chakchat-backend/messaging-service/internal/application/storage/repository"
+	"github.com/chakchat/chakchat-backend/messaging-service/internal/domain"
+	"github.com/google/uuid"
+)
+
+const (
+	fanoutsPerDispatch = 10
+	// Limits the time a huge channel holds the dispatcher, so other channels are not starved
+	batchesPerFanout = 10
+	// A failed fanout is retried after attempts * fanoutRetryDelay
+	fanoutRetryDelay  = time.Minute
+	maxFanoutAttempts = 10
+)
+
+// FanoutDispatcher publishes stored channel events for subscribers in batches,
+// so no single UserEvent holds all subscribers of a channel.
+//
+// A batch may be published again if the transaction fails after publishing,
+// so receivers should be ready to get the same event twice.
+type FanoutDispatcher struct {
+	txProvider storage.TxProvider
+	fanoutRepo repository.ChannelFanoutRepository
+	pub        publish.Publisher
+	batchSize  int
+}
+
+func NewFanoutDispatcher(
+	txProvider storage.TxProvider,
+	fanoutRepo repository.ChannelFanoutRepository,
+	pub publish.Publisher,
+	batchSize int,
+) *FanoutDispatcher {
+	return &FanoutDispatcher{
+		txProvider: txProvider,
+		fanoutRepo: fanoutRepo,
+		pub:        pub,
+		batchSize:  batchSize,
+	}
+}
+
+// Run dispatches pending fanouts every interval until ctx is done
+func (d *FanoutDispatcher) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := d.DispatchPending(ctx); err != nil {
+				log.Printf("dispatching channel fanouts failed: %s", err)
+			}
+		}
+	}
+}
+
+// DispatchPending publishes pending fanouts for the next batches of subscribers.
+// Concurrent calls (e.g. from other service instances) process different fanouts.
+//
+// Every fanout is dispatched in its own transaction, so a failing one doesn't roll back the others.
+// It is retried later and parked after maxFanoutAttempts.
+func (d *FanoutDispatcher) DispatchPending(ctx context.Context) error {
+	for range fanoutsPerDispatch {
+		f, err := d.dispatchNext(ctx)
+		if f == nil {
+			// Nothing is pending or it can't be fetched
+			return err
+		}
+		if err != nil {
+			log.Printf("dispatching channel fanout %d failed: %s", f.ID, err)
+			if err := d.fail(ctx, f); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// dispatchNext dispatches the oldest pending fanout. It returns nil if there is none.
+func (d *FanoutDispatcher) dispatchNext(ctx context.Context) (_ *publish.Fanout, err error) {
+	tx, err := d.txProvider.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer storage.FinishTx(ctx, tx, &err)
+
+	pending, err := d.fanoutRepo.FetchPending(ctx, tx, 1)
+	if err != nil || len(pending) == 0 {
+		return nil, err
+	}
+
+	f := pending[0]
+	return f, d.dispatch(ctx, tx, f)
+}
+
+// fail records the failed attempt in a new transaction because the one of the dispatch is rolled back.
+// The progress is kept, so the batches published before the failure are not published again.
+func (d *FanoutDispatcher) fail(ctx context.Context, f *publish.Fanout) (err error) {
+	tx, err := d.txProvider.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer storage.FinishTx(ctx, tx, &err)
+
+	now := time.Now()
+	f.Attempts++
+	retryAt := now.Add(time.Duration(f.Attempts) * fanoutRetryDelay)
+	f.RetryAt = &retryAt
+	if f.Attempts >= maxFanoutAttempts {
+		log.Printf("channel fanout %d is parked after %d failed attempts", f.ID, f.Attempts)
+		f.ParkedAt = &now
+	}
+
+	_, err = d.fanoutRepo.Update(ctx, tx, f)
+	return err
+}
+
+func (d *FanoutDispatcher) dispatch(ctx context.Context, db storage.ExecQuerier, f *publish.Fanout) error {
+	for range batchesPerFanout {
+		var after *domain.UserID
+		if f.LastUserID != nil {
+			cp := domain.UserID(*f.LastUserID)
+			after = &cp
+		}
+
+		subscribers, err := d.fanoutRepo.GetSubscribers(ctx, db, domain.ChatID(f.ChatID), after, d.batchSize)
+		if err != nil {
+			return err
+		}
+
+		receivers := make([]uuid.UUID, 0, len(subscribers))
+		for _, s := range subscribers {
+			if uuid.UUID(s) != f.SenderID {
+				receivers = append(receivers, uuid.UUID(s))
+			}
+		}
+
+		if err := d.pub.PublishForReceivers(ctx, receivers, f.Type, f.Data); err != nil {
+			return err
+		}
+
+		if len(subscribers) < d.batchSize {
+			return d.fanoutRepo.Delete(ctx, db, f.ID)
+		}
+
+		last := uuid.UUID(subscribers[len(subscribers)-1])
+		f.LastUserID = &last
+	}
+
+	_, err := d.fanoutRepo.Update(ctx, db, f)
+	return err
+}
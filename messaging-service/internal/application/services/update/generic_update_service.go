@@ -2,29 +2,36 @@ package update
 
 import (
 	"context"
+	"errors"
 
 	"github.com/chakchat/chakchat-backend/messaging-service/internal/application/generic"
 	"github.com/chakchat/chakchat-backend/messaging-service/internal/application/request"
+	"github.com/chakchat/chakchat-backend/messaging-service/internal/application/services"
 	"github.com/chakchat/chakchat-backend/messaging-service/internal/application/storage"
 	"github.com/chakchat/chakchat-backend/messaging-service/internal/application/storage/repository"
 	"github.com/chakchat/chakchat-backend/messaging-service/internal/domain"
+	"github.com/chakchat/chakchat-backend/messaging-service/internal/domain/group"
+	"github.com/google/uuid"
 )
 
 type GenericUpdateService struct {
-	txProvider storage.TxProvider
-	chatRepo   repository.ChatterRepository
-	updateRepo repository.GenericUpdateRepository
+	txProvider  storage.TxProvider
+	chatRepo    repository.ChatterRepository
+	updateRepo  repository.GenericUpdateRepository
+	messageRepo repository.UpdateRepository
 }
 
 func NewGenericUpdateService(
 	txProvider storage.TxProvider,
 	chatRepo repository.ChatterRepository,
 	updateRepo repository.GenericUpdateRepository,
+	messageRepo repository.UpdateRepository,
 ) *GenericUpdateService {
 	return &GenericUpdateService{
-		txProvider: txProvider,
-		chatRepo:   chatRepo,
-		updateRepo: updateRepo,
+		txProvider:  txProvider,
+		chatRepo:    chatRepo,
+		updateRepo:  updateRepo,
+		messageRepo: messageRepo,
 	}
 }
 
@@ -37,19 +44,66 @@ func (s *GenericUpdateService) GetUpdatesRange(
 	}
 	defer storage.FinishTx(ctx, tx, &err)
 
+	// Topic updates form a separate stream stored under the topic ID
+	streamID := req.ChatID
+	if req.TopicID != nil {
+		streamID = *req.TopicID
+	}
+
 	// It is such cringe, it should be refactored
-	chat, err := s.chatRepo.FindChatter(ctx, tx, domain.ChatID(req.ChatID))
+	chat, err := s.chatRepo.FindChatter(ctx, tx, domain.ChatID(streamID), domain.UserID(req.SenderID))
 	if err != nil {
+		if req.TopicID != nil && errors.Is(err, repository.ErrNotFound) {
+			return nil, services.ErrTopicNotFound
+		}
 		return nil, err
 	}
+	if req.TopicID != nil {
+		if topic, ok := chat.(*group.Topic); !ok || uuid.UUID(topic.Group.ID) != req.ChatID {
+			return nil, services.ErrTopicNotFound
+		}
+	}
 	if !chat.IsMember(domain.UserID(req.SenderID)) {
 		return nil, domain.ErrUserNotMember
 	}
 
 	updates, err := s.updateRepo.GetRange(
+		ctx, tx,
+		domain.UserID(req.SenderID),
+		domain.ChatID(streamID),
+		domain.UpdateID(req.From),
+		domain.UpdateID(req.To),
+	)
+	return updates, err
+}
+
+func (s *GenericUpdateService) GetThreadUpdatesRange(
+	ctx context.Context, req request.GetThreadUpdatesRange,
+) ([]generic.Update, error) {
+	tx, err := s.txProvider.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer storage.FinishTx(ctx, tx, &err)
+
+	chat, err := s.chatRepo.FindChatter(ctx, tx, domain.ChatID(req.ChatID), domain.UserID(req.SenderID))
+	if err != nil {
+		return nil, err
+	}
+	if !chat.IsMember(domain.UserID(req.SenderID)) {
+		return nil, domain.ErrUserNotMember
+	}
+
+	_, err = findThreadRoot(ctx, tx, s.messageRepo, domain.ChatID(req.ChatID), &req.ThreadRootID, domain.UserID(req.SenderID))
+	if err != nil {
+		return nil, err
+	}
+
+	updates, err := s.updateRepo.GetThreadRange(
 		ctx, tx,
 		domain.UserID(req.SenderID),
 		domain.ChatID(req.ChatID),
+		domain.UpdateID(req.ThreadRootID),
 		domain.UpdateID(req.From),
 		domain.UpdateID(req.To),
 	)
@@ -66,7 +120,7 @@ func (s *GenericUpdateService) GetUpdate(
 	defer storage.FinishTx(ctx, tx, &err)
 
 	// It is such cringe, it should be refactored
-	chat, err := s.chatRepo.FindChatter(ctx, tx, domain.ChatID(req.ChatID))
+	chat, err := s.chatRepo.FindChatter(ctx, tx, domain.ChatID(req.ChatID), domain.UserID(req.SenderID))
 	if err != nil {
 		return nil, err
 	}
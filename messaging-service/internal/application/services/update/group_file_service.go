@@ -8,7 +8,6 @@ import (
 	"github.com/chakchat/chakchat-backend/messaging-service/internal/application/external"
 	"github.com/chakchat/chakchat-backend/messaging-service/internal/application/generic"
 	"github.com/chakchat/chakchat-backend/messaging-service/internal/application/publish"
-	"github.com/chakchat/chakchat-backend/messaging-service/internal/application/publish/events"
 	"github.com/chakchat/chakchat-backend/messaging-service/internal/application/request"
 	"github.com/chakchat/chakchat-backend/messaging-service/internal/application/services"
 	"github.com/chakchat/chakchat-backend/messaging-service/internal/application/storage"
@@ -63,6 +62,7 @@ func (s *GroupFileService) SendFileMessage(
 			ctx, tx,
 			domain.ChatID(req.ChatID),
 			domain.UpdateID(*req.ReplyToMessage),
+			domain.UserID(req.SenderID),
 		)
 		if err != nil {
 			if errors.Is(err, repository.ErrNotFound) {
@@ -83,7 +83,21 @@ func (s *GroupFileService) SendFileMessage(
 
 	domFile := services.NewDomainFileMeta(file)
 
-	msg, err := domain.NewFileMessage(chat, domain.UserID(req.SenderID), &domFile, replyToMessage)
+	threadRoot, err := findThreadRoot(ctx, tx, s.updateRepo, domain.ChatID(req.ChatID), req.ThreadRootID, domain.UserID(req.SenderID))
+	if err != nil {
+		return nil, err
+	}
+
+	var msg *domain.FileMessage
+	if threadRoot == nil {
+		msg, err = domain.NewFileMessage(chat, domain.UserID(req.SenderID), &domFile, replyToMessage)
+	} else {
+		// The reply is validated against the thread
+		msg, err = domain.NewFileMessage(chat, domain.UserID(req.SenderID), &domFile, nil)
+		if err == nil {
+			err = msg.ReplyInThread(threadRoot, replyToMessage)
+		}
+	}
 	if err != nil {
 		return nil, err
 	}
@@ -95,10 +109,9 @@ func (s *GroupFileService) SendFileMessage(
 
 	msgDto := dto.NewFileMessageDTO(msg)
 
-	err = s.pub.PublishForReceivers(
-		ctx,
-		services.GetReceivingUpdateMembers(chat.Members[:], msg.SenderID, &msg.Update),
-		events.TypeUpdate,
+	err = publishGroupMessage(
+		ctx, tx, s.updateRepo, s.pub,
+		chat.Members, nil, &msg.Update,
 		generic.FromFileMessageDTO(&msgDto),
 	)
 	if err != nil {
@@ -5,6 +5,7 @@ import (
 	"errors"
 
 	"github.com/chakchat/chakchat-backend/messaging-service/internal/application/dto"
+	"github.com/chakchat/chakchat-backend/messaging-service/internal/application/external"
 	"github.com/chakchat/chakchat-backend/messaging-service/internal/application/generic"
 	"github.com/chakchat/chakchat-backend/messaging-service/internal/application/publish"
 	"github.com/chakchat/chakchat-backend/messaging-service/internal/application/publish/events"
@@ -20,6 +21,7 @@ type GroupUpdateService struct {
 	groupRepo   repository.GroupChatRepository
 	updateRepo  repository.UpdateRepository
 	chatterRepo repository.ChatterRepository
+	users       external.UserService
 	pub         publish.Publisher
 }
 
@@ -28,6 +30,7 @@ func NewGroupUpdateService(
 	groupRepo repository.GroupChatRepository,
 	updateRepo repository.UpdateRepository,
 	chatterRepo repository.ChatterRepository,
+	users external.UserService,
 	pub publish.Publisher,
 ) *GroupUpdateService {
 	return &GroupUpdateService{
@@ -35,6 +38,7 @@ func NewGroupUpdateService(
 		updateRepo:  updateRepo,
 		chatterRepo: chatterRepo,
 		txProvider:  txProvider,
+		users:       users,
 		pub:         pub,
 	}
 }
@@ -42,6 +46,12 @@ func NewGroupUpdateService(
 func (s *GroupUpdateService) SendTextMessage(
 	ctx context.Context, req request.SendTextMessage,
 ) (_ *dto.TextMessageDTO, err error) {
+	// Resolved before the transaction is started to not hold it during the call
+	mentions, err := resolveMentions(ctx, s.users, req.Text, req.Mentions)
+	if err != nil {
+		return nil, err
+	}
+
 	tx, err := s.txProvider.Begin(ctx)
 	if err != nil {
 		return nil, err
@@ -58,7 +68,7 @@ func (s *GroupUpdateService) SendTextMessage(
 
 	var replyToMessage *domain.Message
 	if req.ReplyToMessage != nil {
-		replyToMessage, err = s.updateRepo.FindGenericMessage(ctx, tx, domain.ChatID(req.ChatID), domain.UpdateID(*req.ReplyToMessage))
+		replyToMessage, err = s.updateRepo.FindGenericMessage(ctx, tx, domain.ChatID(req.ChatID), domain.UpdateID(*req.ReplyToMessage), domain.UserID(req.SenderID))
 		if err != nil {
 			if errors.Is(err, repository.ErrNotFound) {
 				return nil, services.ErrMessageNotFound
@@ -67,16 +77,30 @@ func (s *GroupUpdateService) SendTextMessage(
 		}
 	}
 
-	msg, err := domain.NewTextMessage(
-		chat,
-		domain.UserID(req.SenderID),
-		req.Text,
-		replyToMessage,
-	)
+	threadRoot, err := findThreadRoot(ctx, tx, s.updateRepo, domain.ChatID(req.ChatID), req.ThreadRootID, domain.UserID(req.SenderID))
 	if err != nil {
 		return nil, err
 	}
 
+	entities := toDomainEntities(req.Entities)
+	var msg *domain.TextMessage
+	if threadRoot == nil {
+		msg, err = domain.NewTextMessage(chat, domain.UserID(req.SenderID), req.Text, entities, replyToMessage)
+	} else {
+		// The reply is validated against the thread
+		msg, err = domain.NewTextMessage(chat, domain.UserID(req.SenderID), req.Text, entities, nil)
+		if err == nil {
+			err = msg.ReplyInThread(threadRoot, replyToMessage)
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := msg.SetMentions(chat, mentions); err != nil {
+		return nil, err
+	}
+
 	msg, err = s.updateRepo.CreateTextMessage(ctx, tx, msg)
 	if err != nil {
 		return nil, err
@@ -84,10 +108,9 @@ func (s *GroupUpdateService) SendTextMessage(
 
 	msgDto := dto.NewTextMessageDTO(msg)
 
-	err = s.pub.PublishForReceivers(
-		ctx,
-		services.GetReceivingUpdateMembers(chat.Members[:], domain.UserID(req.SenderID), &msg.Update),
-		events.TypeUpdate,
+	err = publishGroupMessage(
+		ctx, tx, s.updateRepo, s.pub,
+		chat.Members, msg.MentionedMembers(chat.Members), &msg.Update,
 		generic.FromTextMessageDTO(&msgDto),
 	)
 	if err != nil {
@@ -114,7 +137,7 @@ func (s *GroupUpdateService) EditTextMessage(
 		return nil, err
 	}
 
-	msg, err := s.updateRepo.FindTextMessage(ctx, tx, domain.ChatID(req.ChatID), domain.UpdateID(req.MessageID))
+	msg, err := s.updateRepo.FindTextMessage(ctx, tx, domain.ChatID(req.ChatID), domain.UpdateID(req.MessageID), domain.UserID(req.SenderID))
 	if err != nil {
 		if errors.Is(err, repository.ErrNotFound) {
 			return nil, services.ErrMessageNotFound
@@ -122,7 +145,7 @@ func (s *GroupUpdateService) EditTextMessage(
 		return nil, err
 	}
 
-	err = msg.Edit(chat, domain.UserID(req.SenderID), req.NewText)
+	err = msg.Edit(chat, domain.UserID(req.SenderID), req.NewText, toDomainEntities(req.NewEntities))
 
 	if err != nil {
 		return nil, err
@@ -173,7 +196,7 @@ func (s *GroupUpdateService) DeleteMessage(
 		return nil, err
 	}
 
-	msg, err := s.updateRepo.FindTextMessage(ctx, tx, domain.ChatID(req.ChatID), domain.UpdateID(req.MessageID))
+	msg, err := s.updateRepo.FindTextMessage(ctx, tx, domain.ChatID(req.ChatID), domain.UpdateID(req.MessageID), domain.UserID(req.SenderID))
 	if err != nil {
 		if errors.Is(err, repository.ErrNotFound) {
 			return nil, services.ErrMessageNotFound
@@ -236,7 +259,7 @@ func (s *GroupUpdateService) SendReaction(
 		return nil, err
 	}
 
-	msg, err := s.updateRepo.FindGenericMessage(ctx, tx, domain.ChatID(req.ChatID), domain.UpdateID(req.MessageID))
+	msg, err := s.updateRepo.FindGenericMessage(ctx, tx, domain.ChatID(req.ChatID), domain.UpdateID(req.MessageID), domain.UserID(req.SenderID))
 	if err != nil {
 		if errors.Is(err, repository.ErrNotFound) {
 			return nil, services.ErrMessageNotFound
@@ -337,7 +360,7 @@ func (s *GroupUpdateService) ForwardTextMessage(
 	}
 	defer storage.FinishTx(ctx, tx, &err)
 
-	fromChat, err := s.chatterRepo.FindChatter(ctx, tx, domain.ChatID(req.FromChatID))
+	fromChat, err := s.chatterRepo.FindChatter(ctx, tx, domain.ChatID(req.FromChatID), domain.UserID(req.SenderID))
 	if err != nil {
 		if errors.Is(err, repository.ErrNotFound) {
 			return nil, services.ErrChatNotFound
@@ -357,6 +380,7 @@ func (s *GroupUpdateService) ForwardTextMessage(
 		ctx, tx,
 		domain.ChatID(req.FromChatID),
 		domain.UpdateID(req.MessageID),
+		domain.UserID(req.SenderID),
 	)
 	if err != nil {
 		if errors.Is(err, repository.ErrNotFound) {
@@ -399,7 +423,7 @@ func (s *GroupUpdateService) ForwardFileMessage(
 	}
 	defer storage.FinishTx(ctx, tx, &err)
 
-	fromChat, err := s.chatterRepo.FindChatter(ctx, tx, domain.ChatID(req.FromChatID))
+	fromChat, err := s.chatterRepo.FindChatter(ctx, tx, domain.ChatID(req.FromChatID), domain.UserID(req.SenderID))
 	if err != nil {
 		if errors.Is(err, repository.ErrNotFound) {
 			return nil, services.ErrChatNotFound
@@ -415,7 +439,7 @@ func (s *GroupUpdateService) ForwardFileMessage(
 		return nil, err
 	}
 
-	msg, err := s.updateRepo.FindFileMessage(ctx, tx, domain.ChatID(req.FromChatID), domain.UpdateID(req.MessageID))
+	msg, err := s.updateRepo.FindFileMessage(ctx, tx, domain.ChatID(req.FromChatID), domain.UpdateID(req.MessageID), domain.UserID(req.SenderID))
 	if err != nil {
 		if errors.Is(err, repository.ErrNotFound) {
 			return nil, services.ErrMessageNotFound
@@ -447,3 +471,75 @@ func (s *GroupUpdateService) ForwardFileMessage(
 
 	return &forwardedDto, nil
 }
+
+func (s *GroupUpdateService) PinMessage(
+	ctx context.Context, req request.PinMessage,
+) (*dto.PinDTO, error) {
+	return s.setPinned(ctx, req, true)
+}
+
+func (s *GroupUpdateService) UnpinMessage(
+	ctx context.Context, req request.PinMessage,
+) (*dto.PinDTO, error) {
+	return s.setPinned(ctx, req, false)
+}
+
+func (s *GroupUpdateService) setPinned(
+	ctx context.Context, req request.PinMessage, pin bool,
+) (_ *dto.PinDTO, err error) {
+	tx, err := s.txProvider.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer storage.FinishTx(ctx, tx, &err)
+
+	chat, err := s.groupRepo.FindById(ctx, tx, domain.ChatID(req.ChatID))
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, services.ErrChatNotFound
+		}
+		return nil, err
+	}
+
+	msg, err := s.updateRepo.FindGenericMessage(ctx, tx, domain.ChatID(req.ChatID), domain.UpdateID(req.MessageID), domain.UserID(req.SenderID))
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, services.ErrMessageNotFound
+		}
+		return nil, err
+	}
+
+	pinned, err := s.updateRepo.IsPinned(ctx, tx, domain.ChatID(req.ChatID), msg.UpdateID)
+	if err != nil {
+		return nil, err
+	}
+
+	var pinUpdate *domain.Pin
+	if pin {
+		pinUpdate, err = domain.NewPin(chat, domain.UserID(req.SenderID), msg, pinned)
+	} else {
+		pinUpdate, err = domain.NewUnpin(chat, domain.UserID(req.SenderID), msg, pinned)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	pinUpdate, err = s.updateRepo.CreatePin(ctx, tx, pinUpdate)
+	if err != nil {
+		return nil, err
+	}
+
+	pinDto := dto.NewPinDTO(pinUpdate)
+
+	err = s.pub.PublishForReceivers(
+		ctx,
+		services.GetReceivingMembers(chat.Members, pinUpdate.SenderID),
+		events.TypeUpdate,
+		generic.FromPinDTO(&pinDto),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &pinDto, nil
+}
@@ -0,0 +1,66 @@
+package update
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/chakchat/chakchat-backend/messaging-service/internal/application/external"
+	"github.com/chakchat/chakchat-backend/messaging-service/internal/application/request"
+	"github.com/chakchat/chakchat-backend/messaging-service/internal/application/services"
+	"github.com/chakchat/chakchat-backend/messaging-service/internal/domain"
+)
+
+// resolveMentions finds mentioned users by usernames written in the text.
+// Membership of mentioned users is validated by the domain.
+func resolveMentions(
+	ctx context.Context, users external.UserService, text string, mentions []request.Mention,
+) ([]domain.Mention, error) {
+	if len(mentions) == 0 {
+		return nil, nil
+	}
+	if len(mentions) > domain.MaxMentionsCount {
+		return nil, domain.ErrTooManyMentions
+	}
+
+	res := make([]domain.Mention, len(mentions))
+	usernames := make([]string, len(mentions))
+	var toResolve []string
+	for i, m := range mentions {
+		res[i] = domain.Mention{
+			Offset: m.Offset,
+			Length: m.Length,
+		}
+		if m.Everyone {
+			continue
+		}
+
+		username, err := domain.MentionedUsername(text, m.Offset, m.Length)
+		if err != nil {
+			return nil, err
+		}
+		usernames[i] = username
+		toResolve = append(toResolve, username)
+	}
+
+	if len(toResolve) == 0 {
+		return res, nil
+	}
+
+	ids, err := users.GetUserIDsByUsernames(ctx, toResolve)
+	if err != nil {
+		return nil, fmt.Errorf("resolving mentioned usernames failed: %w", err)
+	}
+
+	for i, m := range mentions {
+		if m.Everyone {
+			continue
+		}
+		id, ok := ids[usernames[i]]
+		if !ok {
+			return nil, services.ErrMentionedUserNotFound
+		}
+		userID := domain.UserID(id)
+		res[i].UserID = &userID
+	}
+	return res, nil
+}
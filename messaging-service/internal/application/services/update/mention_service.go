@@ -0,0 +1,72 @@
+package update
+
+import (
+	"context"
+	"errors"
+
+	"github.com/chakchat/chakchat-backend/messaging-service/internal/application/request"
+	"github.com/chakchat/chakchat-backend/messaging-service/internal/application/services"
+	"github.com/chakchat/chakchat-backend/messaging-service/internal/application/storage"
+	"github.com/chakchat/chakchat-backend/messaging-service/internal/application/storage/repository"
+	"github.com/chakchat/chakchat-backend/messaging-service/internal/domain"
+)
+
+type MentionService struct {
+	txProvider  storage.TxProvider
+	chatRepo    repository.GenericChatRepository
+	chatterRepo repository.ChatterRepository
+	mentionRepo repository.MentionRepository
+}
+
+func NewMentionService(
+	txProvider storage.TxProvider,
+	chatRepo repository.GenericChatRepository,
+	chatterRepo repository.ChatterRepository,
+	mentionRepo repository.MentionRepository,
+) *MentionService {
+	return &MentionService{
+		txProvider:  txProvider,
+		chatRepo:    chatRepo,
+		chatterRepo: chatterRepo,
+		mentionRepo: mentionRepo,
+	}
+}
+
+// ReadMentions returns the number of mentions that are still unread
+func (s *MentionService) ReadMentions(ctx context.Context, req request.ReadMentions) (_ int, err error) {
+	tx, err := s.txProvider.Begin(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer storage.FinishTx(ctx, tx, &err)
+
+	chatType, err := s.chatRepo.GetChatType(ctx, tx, domain.ChatID(req.ChatID))
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return 0, services.ErrChatNotFound
+		}
+		return 0, err
+	}
+	// Only group chats have mentions
+	if chatType != domain.ChatTypeGroup {
+		return 0, services.ErrInvalidChatType
+	}
+
+	chat, err := s.chatterRepo.FindChatter(ctx, tx, domain.ChatID(req.ChatID), domain.UserID(req.SenderID))
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return 0, services.ErrChatNotFound
+		}
+		return 0, err
+	}
+	if !chat.IsMember(domain.UserID(req.SenderID)) {
+		return 0, domain.ErrUserNotMember
+	}
+
+	err = s.mentionRepo.MarkRead(ctx, tx, chat.ChatID(), domain.UserID(req.SenderID), domain.UpdateID(req.UpdateID))
+	if err != nil {
+		return 0, err
+	}
+
+	return s.mentionRepo.CountUnread(ctx, tx, chat.ChatID(), domain.UserID(req.SenderID))
+}
@@ -63,6 +63,7 @@ func (s *PersonalFileService) SendFileMessage(
 			ctx, tx,
 			domain.ChatID(req.ChatID),
 			domain.UpdateID(*req.ReplyToMessage),
+			domain.UserID(req.SenderID),
 		)
 		if err != nil {
 			if errors.Is(err, repository.ErrNotFound) {
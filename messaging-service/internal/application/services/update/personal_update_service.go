@@ -62,6 +62,7 @@ func (s *PersonalUpdateService) SendTextMessage(
 			ctx, tx,
 			domain.ChatID(req.ChatID),
 			domain.UpdateID(*req.ReplyToMessage),
+			domain.UserID(req.SenderID),
 		)
 		if err != nil {
 			if errors.Is(err, repository.ErrNotFound) {
@@ -75,6 +76,7 @@ func (s *PersonalUpdateService) SendTextMessage(
 		chat,
 		domain.UserID(req.SenderID),
 		req.Text,
+		toDomainEntities(req.Entities),
 		replyToMessage,
 	)
 
@@ -123,6 +125,7 @@ func (s *PersonalUpdateService) EditTextMessage(
 		ctx, tx,
 		domain.ChatID(req.ChatID),
 		domain.UpdateID(req.MessageID),
+		domain.UserID(req.SenderID),
 	)
 	if err != nil {
 		if errors.Is(err, repository.ErrNotFound) {
@@ -131,7 +134,7 @@ func (s *PersonalUpdateService) EditTextMessage(
 		return nil, err
 	}
 
-	err = msg.Edit(chat, domain.UserID(req.SenderID), req.NewText)
+	err = msg.Edit(chat, domain.UserID(req.SenderID), req.NewText, toDomainEntities(req.NewEntities))
 
 	if err != nil {
 		return nil, err
@@ -186,6 +189,7 @@ func (s *PersonalUpdateService) DeleteMessage(
 		ctx, tx,
 		domain.ChatID(req.ChatID),
 		domain.UpdateID(req.MessageID),
+		domain.UserID(req.SenderID),
 	)
 	if err != nil {
 		if errors.Is(err, repository.ErrNotFound) {
@@ -253,6 +257,7 @@ func (s *PersonalUpdateService) SendReaction(
 		ctx, tx,
 		domain.ChatID(req.ChatID),
 		domain.UpdateID(req.MessageID),
+		domain.UserID(req.SenderID),
 	)
 	if err != nil {
 		if errors.Is(err, repository.ErrNotFound) {
@@ -355,7 +360,7 @@ func (s *PersonalUpdateService) ForwardTextMessage(
 	}
 	defer storage.FinishTx(ctx, tx, &err)
 
-	fromChat, err := s.chatterRepo.FindChatter(ctx, tx, domain.ChatID(req.FromChatID))
+	fromChat, err := s.chatterRepo.FindChatter(ctx, tx, domain.ChatID(req.FromChatID), domain.UserID(req.SenderID))
 	if err != nil {
 		if errors.Is(err, repository.ErrNotFound) {
 			return nil, services.ErrChatNotFound
@@ -375,6 +380,7 @@ func (s *PersonalUpdateService) ForwardTextMessage(
 		ctx, tx,
 		domain.ChatID(req.FromChatID),
 		domain.UpdateID(req.MessageID),
+		domain.UserID(req.SenderID),
 	)
 	if err != nil {
 		if errors.Is(err, repository.ErrNotFound) {
@@ -417,7 +423,7 @@ func (s *PersonalUpdateService) ForwardFileMessage(
 	}
 	defer storage.FinishTx(ctx, tx, &err)
 
-	fromChat, err := s.chatterRepo.FindChatter(ctx, tx, domain.ChatID(req.FromChatID))
+	fromChat, err := s.chatterRepo.FindChatter(ctx, tx, domain.ChatID(req.FromChatID), domain.UserID(req.SenderID))
 	if err != nil {
 		if errors.Is(err, repository.ErrNotFound) {
 			return nil, services.ErrChatNotFound
@@ -435,6 +441,7 @@ func (s *PersonalUpdateService) ForwardFileMessage(
 
 	msg, err := s.updateRepo.FindFileMessage(
 		ctx, tx, domain.ChatID(req.FromChatID), domain.UpdateID(req.MessageID),
+		domain.UserID(req.SenderID),
 	)
 	if err != nil {
 		if errors.Is(err, repository.ErrNotFound) {
@@ -467,3 +474,75 @@ func (s *PersonalUpdateService) ForwardFileMessage(
 
 	return &forwardedDto, nil
 }
+
+func (s *PersonalUpdateService) PinMessage(
+	ctx context.Context, req request.PinMessage,
+) (*dto.PinDTO, error) {
+	return s.setPinned(ctx, req, true)
+}
+
+func (s *PersonalUpdateService) UnpinMessage(
+	ctx context.Context, req request.PinMessage,
+) (*dto.PinDTO, error) {
+	return s.setPinned(ctx, req, false)
+}
+
+func (s *PersonalUpdateService) setPinned(
+	ctx context.Context, req request.PinMessage, pin bool,
+) (_ *dto.PinDTO, err error) {
+	tx, err := s.txProvider.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer storage.FinishTx(ctx, tx, &err)
+
+	chat, err := s.pchatRepo.FindById(ctx, tx, domain.ChatID(req.ChatID))
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, services.ErrChatNotFound
+		}
+		return nil, err
+	}
+
+	msg, err := s.updateRepo.FindGenericMessage(ctx, tx, domain.ChatID(req.ChatID), domain.UpdateID(req.MessageID), domain.UserID(req.SenderID))
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, services.ErrMessageNotFound
+		}
+		return nil, err
+	}
+
+	pinned, err := s.updateRepo.IsPinned(ctx, tx, domain.ChatID(req.ChatID), msg.UpdateID)
+	if err != nil {
+		return nil, err
+	}
+
+	var pinUpdate *domain.Pin
+	if pin {
+		pinUpdate, err = domain.NewPin(chat, domain.UserID(req.SenderID), msg, pinned)
+	} else {
+		pinUpdate, err = domain.NewUnpin(chat, domain.UserID(req.SenderID), msg, pinned)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	pinUpdate, err = s.updateRepo.CreatePin(ctx, tx, pinUpdate)
+	if err != nil {
+		return nil, err
+	}
+
+	pinDto := dto.NewPinDTO(pinUpdate)
+
+	err = s.pub.PublishForReceivers(
+		ctx,
+		services.GetReceivingMembers(chat.Members[:], pinUpdate.SenderID),
+		events.TypeUpdate,
+		generic.FromPinDTO(&pinDto),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &pinDto, nil
+}
@@ -0,0 +1,205 @@
+package update
+
+import (
+	"context"
+	"errors"
+
+	"github.com/chakchat/chakchat-backend/messaging-service/internal/application/dto"
+	"github.com/chakchat/chakchat-backend/messaging-service/internal/application/generic"
+	"github.com/chakchat/chakchat-backend/messaging-service/internal/application/publish"
+	"github.com/chakchat/chakchat-backend/messaging-service/internal/application/publish/events"
+	"github.com/chakchat/chakchat-backend/messaging-service/internal/application/request"
+	"github.com/chakchat/chakchat-backend/messaging-service/internal/application/services"
+	"github.com/chakchat/chakchat-backend/messaging-service/internal/application/storage"
+	"github.com/chakchat/chakchat-backend/messaging-service/internal/application/storage/repository"
+	"github.com/chakchat/chakchat-backend/messaging-service/internal/domain"
+	"github.com/chakchat/chakchat-backend/messaging-service/internal/domain/group"
+	"github.com/google/uuid"
+)
+
+// PollService manages polls of group chats.
+// Changes of poll results are published as the poll update itself,
+// so voters of anonymous polls are never revealed to other members.
+type PollService struct {
+	txProvider storage.TxProvider
+	groupRepo  repository.GroupChatRepository
+	updateRepo repository.UpdateRepository
+	pollRepo   repository.PollRepository
+	pub        publish.Publisher
+}
+
+func NewPollService(
+	txProvider storage.TxProvider,
+	groupRepo repository.GroupChatRepository,
+	updateRepo repository.UpdateRepository,
+	pollRepo repository.PollRepository,
+	pub publish.Publisher,
+) *PollService {
+	return &PollService{
+		txProvider: txProvider,
+		groupRepo:  groupRepo,
+		updateRepo: updateRepo,
+		pollRepo:   pollRepo,
+		pub:        pub,
+	}
+}
+
+func (s *PollService) SendPoll(
+	ctx context.Context, req request.SendPoll,
+) (_ *dto.PollDTO, err error) {
+	tx, err := s.txProvider.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer storage.FinishTx(ctx, tx, &err)
+
+	chat, err := s.findChat(ctx, tx, req.ChatID)
+	if err != nil {
+		return nil, err
+	}
+
+	var replyToMessage *domain.Message
+	if req.ReplyToMessage != nil {
+		replyToMessage, err = s.updateRepo.FindGenericMessage(ctx, tx, chat.ID, domain.UpdateID(*req.ReplyToMessage), domain.UserID(req.SenderID))
+		if err != nil {
+			if errors.Is(err, repository.ErrNotFound) {
+				return nil, services.ErrMessageNotFound
+			}
+			return nil, err
+		}
+	}
+
+	var closesAt *domain.Timestamp
+	if req.ClosesAt != nil {
+		ts := domain.Timestamp(*req.ClosesAt)
+		closesAt = &ts
+	}
+
+	poll, err := domain.NewPoll(
+		chat,
+		domain.UserID(req.SenderID),
+		req.Question,
+		req.Options,
+		domain.PollSettings{
+			MultipleChoice: req.MultipleChoice,
+			Anonymous:      req.Anonymous,
+			ClosesAt:       closesAt,
+		},
+		replyToMessage,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	poll, err = s.pollRepo.CreatePoll(ctx, tx, poll)
+	if err != nil {
+		return nil, err
+	}
+
+	pollDto := dto.NewPollDTO(poll)
+
+	err = s.pub.PublishForReceivers(
+		ctx,
+		services.GetReceivingUpdateMembers(chat.Members, poll.SenderID, &poll.Update),
+		events.TypeUpdate,
+		generic.FromPollDTO(&pollDto),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &pollDto, nil
+}
+
+func (s *PollService) Vote(
+	ctx context.Context, req request.VotePoll,
+) (*dto.PollDTO, error) {
+	return s.changePoll(ctx, req.ChatID, req.SenderID, req.PollID,
+		func(chat *group.GroupChat, poll *domain.Poll) error {
+			return poll.Vote(chat, domain.UserID(req.SenderID), req.Options)
+		},
+	)
+}
+
+func (s *PollService) RetractVote(
+	ctx context.Context, req request.RetractPollVote,
+) (*dto.PollDTO, error) {
+	return s.changePoll(ctx, req.ChatID, req.SenderID, req.PollID,
+		func(chat *group.GroupChat, poll *domain.Poll) error {
+			return poll.RetractVote(chat, domain.UserID(req.SenderID))
+		},
+	)
+}
+
+func (s *PollService) ClosePoll(
+	ctx context.Context, req request.ClosePoll,
+) (*dto.PollDTO, error) {
+	return s.changePoll(ctx, req.ChatID, req.SenderID, req.PollID,
+		func(chat *group.GroupChat, poll *domain.Poll) error {
+			return poll.Close(chat, domain.UserID(req.SenderID))
+		},
+	)
+}
+
+// changePoll applies the change to the poll, stores it and publishes new poll results
+func (s *PollService) changePoll(
+	ctx context.Context,
+	chatID, senderID uuid.UUID,
+	pollID int64,
+	change func(*group.GroupChat, *domain.Poll) error,
+) (_ *dto.PollDTO, err error) {
+	tx, err := s.txProvider.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer storage.FinishTx(ctx, tx, &err)
+
+	chat, err := s.findChat(ctx, tx, chatID)
+	if err != nil {
+		return nil, err
+	}
+
+	poll, err := s.pollRepo.FindPoll(ctx, tx, chat.ID, domain.UpdateID(pollID))
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, services.ErrPollNotFound
+		}
+		return nil, err
+	}
+
+	if err := change(chat, poll); err != nil {
+		return nil, err
+	}
+
+	poll, err = s.pollRepo.UpdatePoll(ctx, tx, poll)
+	if err != nil {
+		return nil, err
+	}
+
+	pollDto := dto.NewPollDTO(poll)
+
+	err = s.pub.PublishForReceivers(
+		ctx,
+		services.GetReceivingUpdateMembers(chat.Members, domain.UserID(senderID), &poll.Update),
+		events.TypeUpdate,
+		generic.FromPollDTO(&pollDto),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &pollDto, nil
+}
+
+func (s *PollService) findChat(
+	ctx context.Context, db storage.ExecQuerier, chatID uuid.UUID,
+) (*group.GroupChat, error) {
+	chat, err := s.groupRepo.FindById(ctx, db, domain.ChatID(chatID))
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, services.ErrChatNotFound
+		}
+		return nil, err
+	}
+	return chat, nil
+}
@@ -0,0 +1,205 @@
+package update
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/chakchat/chakchat-backend/messaging-service/internal/application/dto"
+	"github.com/chakchat/chakchat-backend/messaging-service/internal/application/request"
+	"github.com/chakchat/chakchat-backend/messaging-service/internal/application/services"
+	"github.com/chakchat/chakchat-backend/messaging-service/internal/application/storage"
+	"github.com/chakchat/chakchat-backend/messaging-service/internal/application/storage/repository"
+	"github.com/chakchat/chakchat-backend/messaging-service/internal/domain"
+	"github.com/google/uuid"
+)
+
+const (
+	dispatchBatchSize = 100
+	// A message isn't sent again by other dispatchers until its claim expires
+	dispatchClaimTimeout = time.Minute
+)
+
+type textMessageSender interface {
+	SendTextMessage(context.Context, request.SendTextMessage) (*dto.TextMessageDTO, error)
+}
+
+type fileMessageSender interface {
+	SendFileMessage(context.Context, request.SendFileMessage) (*dto.FileMessageDTO, error)
+}
+
+// ScheduledDispatcher sends due scheduled messages through the same services as regular messages,
+// so all send rules and publication are applied at send time.
+//
+// Messages rejected by these rules are dropped.
+// Messages failed for other reasons (e.g. file storage is unavailable) are retried when their claim expires.
+type ScheduledDispatcher struct {
+	txProvider    storage.TxProvider
+	chatRepo      repository.GenericChatRepository
+	scheduledRepo repository.ScheduledMessageRepository
+
+	personalText textMessageSender
+	personalFile fileMessageSender
+	groupText    textMessageSender
+	groupFile    fileMessageSender
+}
+
+func NewScheduledDispatcher(
+	txProvider storage.TxProvider,
+	chatRepo repository.GenericChatRepository,
+	scheduledRepo repository.ScheduledMessageRepository,
+	personalUpdate *PersonalUpdateService,
+	personalFile *PersonalFileService,
+	groupUpdate *GroupUpdateService,
+	groupFile *GroupFileService,
+) *ScheduledDispatcher {
+	return &ScheduledDispatcher{
+		txProvider:    txProvider,
+		chatRepo:      chatRepo,
+		scheduledRepo: scheduledRepo,
+		personalText:  personalUpdate,
+		personalFile:  personalFile,
+		groupText:     groupUpdate,
+		groupFile:     groupFile,
+	}
+}
+
+// Run dispatches due messages every interval until ctx is done
+func (d *ScheduledDispatcher) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := d.DispatchDue(ctx); err != nil {
+				log.Printf("dispatching scheduled messages failed: %s", err)
+			}
+		}
+	}
+}
+
+// DispatchDue sends messages that are due now.
+// Concurrent calls (e.g. from other service instances) don't send the same message twice.
+//
+// Messages are claimed in a separate transaction because the senders commit their own ones.
+// Otherwise a message would stay in the table after it is sent if the dispatcher fails before deleting it.
+func (d *ScheduledDispatcher) DispatchDue(ctx context.Context) error {
+	due, err := d.claimDue(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, msg := range due {
+		if err := d.send(ctx, msg); err != nil {
+			if !isRejected(err) {
+				log.Printf("sending scheduled message %d failed, it will be retried: %s", msg.ID, err)
+				continue
+			}
+			log.Printf("scheduled message %d is rejected: %s", msg.ID, err)
+		}
+
+		if err := d.delete(ctx, msg.ID); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (d *ScheduledDispatcher) claimDue(ctx context.Context) (_ []*domain.ScheduledMessage, err error) {
+	tx, err := d.txProvider.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer storage.FinishTx(ctx, tx, &err)
+
+	return d.scheduledRepo.ClaimDue(ctx, tx, time.Now(), dispatchClaimTimeout, dispatchBatchSize)
+}
+
+func (d *ScheduledDispatcher) delete(ctx context.Context, id domain.ScheduledMessageID) (err error) {
+	tx, err := d.txProvider.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer storage.FinishTx(ctx, tx, &err)
+
+	return d.scheduledRepo.DeleteSent(ctx, tx, id)
+}
+
+func (d *ScheduledDispatcher) getChatType(ctx context.Context, id domain.ChatID) (_ string, err error) {
+	tx, err := d.txProvider.Begin(ctx)
+	if err != nil {
+		return "", err
+	}
+	defer storage.FinishTx(ctx, tx, &err)
+
+	return d.chatRepo.GetChatType(ctx, tx, id)
+}
+
+func (d *ScheduledDispatcher) send(ctx context.Context, msg *domain.ScheduledMessage) error {
+	chatType, err := d.getChatType(ctx, msg.ChatID)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return services.ErrChatNotFound
+		}
+		return err
+	}
+
+	var (
+		textSender textMessageSender
+		fileSender fileMessageSender
+	)
+	switch chatType {
+	case domain.ChatTypePersonal:
+		textSender, fileSender = d.personalText, d.personalFile
+	case domain.ChatTypeGroup:
+		textSender, fileSender = d.groupText, d.groupFile
+	default:
+		return services.ErrInvalidChatType
+	}
+
+	replyTo := replyToPtr(msg.ReplyTo)
+
+	if msg.IsFile() {
+		_, err = fileSender.SendFileMessage(ctx, request.SendFileMessage{
+			ChatID:         uuid.UUID(msg.ChatID),
+			SenderID:       uuid.UUID(msg.SenderID),
+			FileID:         *msg.FileID,
+			ReplyToMessage: replyTo,
+		})
+	} else {
+		_, err = textSender.SendTextMessage(ctx, request.SendTextMessage{
+			ChatID:         uuid.UUID(msg.ChatID),
+			SenderID:       uuid.UUID(msg.SenderID),
+			Text:           msg.Text,
+			Entities:       toRequestEntities(msg.Entities),
+			ReplyToMessage: replyTo,
+		})
+	}
+	if err != nil {
+		return fmt.Errorf("sending %s chat message failed: %w", chatType, err)
+	}
+	return nil
+}
+
+// isRejected tells if the error is caused by send rules, so retrying makes no sense
+func isRejected(err error) bool {
+	var (
+		domainErr  domain.Error
+		serviceErr services.Error
+	)
+	return errors.As(err, &domainErr) || errors.As(err, &serviceErr)
+}
+
+func replyToPtr(id *domain.UpdateID) *int64 {
+	if id == nil {
+		return nil
+	}
+	cp := int64(*id)
+	return &cp
+}
@@ -0,0 +1,250 @@
+package update
+
+import (
+	"context"
+	"errors"
+
+	"github.com/chakchat/chakchat-backend/messaging-service/internal/application/dto"
+	"github.com/chakchat/chakchat-backend/messaging-service/internal/application/request"
+	"github.com/chakchat/chakchat-backend/messaging-service/internal/application/services"
+	"github.com/chakchat/chakchat-backend/messaging-service/internal/application/storage"
+	"github.com/chakchat/chakchat-backend/messaging-service/internal/application/storage/repository"
+	"github.com/chakchat/chakchat-backend/messaging-service/internal/domain"
+	"github.com/google/uuid"
+)
+
+// ScheduledMessageService manages scheduled messages of personal and group chats.
+// They are sent by ScheduledDispatcher.
+type ScheduledMessageService struct {
+	txProvider    storage.TxProvider
+	chatterRepo   repository.ChatterRepository
+	chatRepo      repository.GenericChatRepository
+	scheduledRepo repository.ScheduledMessageRepository
+}
+
+func NewScheduledMessageService(
+	txProvider storage.TxProvider,
+	chatterRepo repository.ChatterRepository,
+	chatRepo repository.GenericChatRepository,
+	scheduledRepo repository.ScheduledMessageRepository,
+) *ScheduledMessageService {
+	return &ScheduledMessageService{
+		txProvider:    txProvider,
+		chatterRepo:   chatterRepo,
+		chatRepo:      chatRepo,
+		scheduledRepo: scheduledRepo,
+	}
+}
+
+func (s *ScheduledMessageService) ScheduleTextMessage(
+	ctx context.Context, req request.ScheduleTextMessage,
+) (_ *dto.ScheduledMessageDTO, err error) {
+	tx, err := s.txProvider.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer storage.FinishTx(ctx, tx, &err)
+
+	chat, err := s.findChat(ctx, tx, req.ChatID)
+	if err != nil {
+		return nil, err
+	}
+
+	msg, err := domain.NewScheduledTextMessage(
+		chat,
+		domain.UserID(req.SenderID),
+		req.Text,
+		toDomainEntities(req.Entities),
+		updateIDPtr(req.ReplyToMessage),
+		domain.Timestamp(req.SendAt),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	msg, err = s.scheduledRepo.Create(ctx, tx, msg)
+	if err != nil {
+		return nil, err
+	}
+
+	msgDto := dto.NewScheduledMessageDTO(msg)
+	return &msgDto, nil
+}
+
+func (s *ScheduledMessageService) ScheduleFileMessage(
+	ctx context.Context, req request.ScheduleFileMessage,
+) (_ *dto.ScheduledMessageDTO, err error) {
+	tx, err := s.txProvider.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer storage.FinishTx(ctx, tx, &err)
+
+	chat, err := s.findChat(ctx, tx, req.ChatID)
+	if err != nil {
+		return nil, err
+	}
+
+	// The file is fetched from the file storage at send time
+	msg, err := domain.NewScheduledFileMessage(
+		chat,
+		domain.UserID(req.SenderID),
+		req.FileID,
+		updateIDPtr(req.ReplyToMessage),
+		domain.Timestamp(req.SendAt),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	msg, err = s.scheduledRepo.Create(ctx, tx, msg)
+	if err != nil {
+		return nil, err
+	}
+
+	msgDto := dto.NewScheduledMessageDTO(msg)
+	return &msgDto, nil
+}
+
+func (s *ScheduledMessageService) GetScheduledMessages(
+	ctx context.Context, req request.GetScheduledMessages,
+) (_ []dto.ScheduledMessageDTO, err error) {
+	tx, err := s.txProvider.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer storage.FinishTx(ctx, tx, &err)
+
+	chat, err := s.findChat(ctx, tx, req.ChatID)
+	if err != nil {
+		return nil, err
+	}
+	if !chat.IsMember(domain.UserID(req.SenderID)) {
+		return nil, domain.ErrUserNotMember
+	}
+
+	msgs, err := s.scheduledRepo.GetByChatAndSender(ctx, tx, chat.ChatID(), domain.UserID(req.SenderID))
+	if err != nil {
+		return nil, err
+	}
+
+	return dto.NewScheduledMessageDTOs(msgs), nil
+}
+
+func (s *ScheduledMessageService) EditScheduledMessage(
+	ctx context.Context, req request.EditScheduledMessage,
+) (_ *dto.ScheduledMessageDTO, err error) {
+	tx, err := s.txProvider.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer storage.FinishTx(ctx, tx, &err)
+
+	chat, err := s.findChat(ctx, tx, req.ChatID)
+	if err != nil {
+		return nil, err
+	}
+
+	msg, err := s.findScheduled(ctx, tx, chat, req.ScheduledID)
+	if err != nil {
+		return nil, err
+	}
+
+	err = msg.Edit(chat, domain.UserID(req.SenderID), req.NewText, toDomainEntities(req.NewEntities),
+		domain.Timestamp(req.SendAt))
+	if err != nil {
+		return nil, err
+	}
+
+	msg, err = s.scheduledRepo.Update(ctx, tx, msg)
+	if err != nil {
+		if errors.Is(err, repository.ErrScheduledClaimed) {
+			return nil, domain.ErrScheduledSending
+		}
+		return nil, err
+	}
+
+	msgDto := dto.NewScheduledMessageDTO(msg)
+	return &msgDto, nil
+}
+
+func (s *ScheduledMessageService) CancelScheduledMessage(
+	ctx context.Context, req request.CancelScheduledMessage,
+) (err error) {
+	tx, err := s.txProvider.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer storage.FinishTx(ctx, tx, &err)
+
+	chat, err := s.findChat(ctx, tx, req.ChatID)
+	if err != nil {
+		return err
+	}
+
+	msg, err := s.findScheduled(ctx, tx, chat, req.ScheduledID)
+	if err != nil {
+		return err
+	}
+
+	if err := msg.Cancel(chat, domain.UserID(req.SenderID)); err != nil {
+		return err
+	}
+
+	if err := s.scheduledRepo.Delete(ctx, tx, msg.ID); err != nil {
+		if errors.Is(err, repository.ErrScheduledClaimed) {
+			return domain.ErrScheduledSending
+		}
+		return err
+	}
+	return nil
+}
+
+// findChat finds a chat messages can be scheduled in. Secret chats are not allowed.
+func (s *ScheduledMessageService) findChat(
+	ctx context.Context, db storage.ExecQuerier, chatID uuid.UUID,
+) (domain.Chatter, error) {
+	chatType, err := s.chatRepo.GetChatType(ctx, db, domain.ChatID(chatID))
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, services.ErrChatNotFound
+		}
+		return nil, err
+	}
+	if chatType != domain.ChatTypePersonal && chatType != domain.ChatTypeGroup {
+		return nil, services.ErrInvalidChatType
+	}
+
+	chat, err := s.chatterRepo.FindChatter(ctx, db, domain.ChatID(chatID))
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, services.ErrChatNotFound
+		}
+		return nil, err
+	}
+	return chat, nil
+}
+
+func (s *ScheduledMessageService) findScheduled(
+	ctx context.Context, db storage.ExecQuerier, chat domain.Chatter, id int64,
+) (*domain.ScheduledMessage, error) {
+	msg, err := s.scheduledRepo.FindByID(ctx, db, domain.ScheduledMessageID(id))
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, services.ErrScheduledNotFound
+		}
+		return nil, err
+	}
+	if msg.ChatID != chat.ChatID() {
+		return nil, services.ErrScheduledNotFound
+	}
+	return msg, nil
+}
+
+func updateIDPtr(id *int64) *domain.UpdateID {
+	if id == nil {
+		return nil
+	}
+	cp := domain.UpdateID(*id)
+	return &cp
+}
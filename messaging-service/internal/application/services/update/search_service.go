@@ -0,0 +1,103 @@
+package update
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"time"
+
+	"github.com/chakchat/chakchat-backend/messaging-service/internal/application/generic"
+	"github.com/chakchat/chakchat-backend/messaging-service/internal/application/request"
+	"github.com/chakchat/chakchat-backend/messaging-service/internal/application/services"
+	"github.com/chakchat/chakchat-backend/messaging-service/internal/application/storage"
+	"github.com/chakchat/chakchat-backend/messaging-service/internal/application/storage/repository"
+	"github.com/chakchat/chakchat-backend/messaging-service/internal/domain"
+)
+
+type SearchService struct {
+	txProvider storage.TxProvider
+	chatRepo   repository.GenericChatRepository
+	searchRepo repository.SearchRepository
+}
+
+func NewSearchService(
+	txProvider storage.TxProvider,
+	chatRepo repository.GenericChatRepository,
+	searchRepo repository.SearchRepository,
+) *SearchService {
+	return &SearchService{
+		txProvider: txProvider,
+		chatRepo:   chatRepo,
+		searchRepo: searchRepo,
+	}
+}
+
+// SearchMessages searches text messages across all chats of the user or inside one chat.
+// Secret chats are never searched because the server can't read them.
+func (s *SearchService) SearchMessages(
+	ctx context.Context, req request.SearchMessages,
+) (_ *generic.SearchResult, err error) {
+	query, err := domain.NewSearchQuery(req.Query)
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := s.txProvider.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer storage.FinishTx(ctx, tx, &err)
+
+	filter := repository.SearchFilter{
+		Offset: req.Offset,
+		// One more is fetched to know if there is the next page
+		Limit: req.Limit + 1,
+	}
+
+	if req.ChatID != nil {
+		chat, err := s.chatRepo.GetByChatID(ctx, tx, domain.ChatID(*req.ChatID))
+		if err != nil {
+			if errors.Is(err, repository.ErrNotFound) {
+				return nil, services.ErrChatNotFound
+			}
+			return nil, err
+		}
+		if !slices.Contains(chat.Members, req.SenderID) {
+			return nil, domain.ErrUserNotMember
+		}
+		if chat.Type != req.ChatType || chat.Type == domain.ChatTypeSecretPersonal ||
+			chat.Type == domain.ChatTypeSecretGroup {
+			return nil, services.ErrInvalidChatType
+		}
+
+		chatID := domain.ChatID(*req.ChatID)
+		filter.ChatID = &chatID
+	}
+	if req.FromSenderID != nil {
+		senderID := domain.UserID(*req.FromSenderID)
+		filter.SenderID = &senderID
+	}
+	if req.Since != nil {
+		since := time.Unix(*req.Since, 0)
+		filter.Since = &since
+	}
+	if req.Until != nil {
+		until := time.Unix(*req.Until, 0)
+		filter.Until = &until
+	}
+
+	messages, err := s.searchRepo.SearchMessages(ctx, tx, domain.UserID(req.SenderID), query, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	res := &generic.SearchResult{
+		Messages: messages,
+	}
+	if len(messages) > req.Limit {
+		res.Messages = messages[:req.Limit]
+		nextOffset := req.Offset + req.Limit
+		res.NextOffset = &nextOffset
+	}
+	return res, nil
+}
@@ -0,0 +1,40 @@
+package update
+
+import (
+	"github.com/chakchat/chakchat-backend/messaging-service/internal/application/request"
+	"github.com/chakchat/chakchat-backend/messaging-service/internal/domain"
+)
+
+func toDomainEntities(entities []request.TextEntity) []domain.TextEntity {
+	if len(entities) == 0 {
+		return nil
+	}
+
+	res := make([]domain.TextEntity, len(entities))
+	for i, e := range entities {
+		res[i] = domain.TextEntity{
+			Type:   domain.TextEntityType(e.Type),
+			Offset: e.Offset,
+			Length: e.Length,
+			URL:    e.URL,
+		}
+	}
+	return res
+}
+
+func toRequestEntities(entities []domain.TextEntity) []request.TextEntity {
+	if len(entities) == 0 {
+		return nil
+	}
+
+	res := make([]request.TextEntity, len(entities))
+	for i, e := range entities {
+		res[i] = request.TextEntity{
+			Type:   string(e.Type),
+			Offset: e.Offset,
+			Length: e.Length,
+			URL:    e.URL,
+		}
+	}
+	return res
+}
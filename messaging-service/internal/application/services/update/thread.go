@@ -0,0 +1,83 @@
+package update
+
+import (
+	"context"
+	"errors"
+	"slices"
+
+	"github.com/chakchat/chakchat-backend/messaging-service/internal/application/generic"
+	"github.com/chakchat/chakchat-backend/messaging-service/internal/application/publish"
+	"github.com/chakchat/chakchat-backend/messaging-service/internal/application/publish/events"
+	"github.com/chakchat/chakchat-backend/messaging-service/internal/application/services"
+	"github.com/chakchat/chakchat-backend/messaging-service/internal/application/storage"
+	"github.com/chakchat/chakchat-backend/messaging-service/internal/application/storage/repository"
+	"github.com/chakchat/chakchat-backend/messaging-service/internal/domain"
+)
+
+// findThreadRoot returns nil message if rootID is nil
+func findThreadRoot(
+	ctx context.Context,
+	db storage.ExecQuerier,
+	updateRepo repository.UpdateRepository,
+	chatID domain.ChatID,
+	rootID *int64,
+	visibleTo domain.UserID,
+) (*domain.Message, error) {
+	if rootID == nil {
+		return nil, nil
+	}
+
+	root, err := updateRepo.FindGenericMessage(ctx, db, chatID, domain.UpdateID(*rootID), visibleTo)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, services.ErrMessageNotFound
+		}
+		return nil, err
+	}
+	return root, nil
+}
+
+// publishGroupMessage publishes a new message of a group chat.
+// Mentioned members get it as a mention.
+// Thread participants get thread messages as thread updates, other members get them as regular updates.
+func publishGroupMessage(
+	ctx context.Context,
+	db storage.ExecQuerier,
+	updateRepo repository.UpdateRepository,
+	pub publish.Publisher,
+	members []domain.UserID,
+	mentioned []domain.UserID,
+	msg *domain.Update,
+	data generic.Update,
+) error {
+	if len(mentioned) > 0 {
+		receivers := services.GetReceivingUpdateMembers(mentioned, msg.SenderID, msg)
+		if err := pub.PublishForReceivers(ctx, receivers, events.TypeMention, data); err != nil {
+			return err
+		}
+
+		members = slices.DeleteFunc(slices.Clone(members), func(member domain.UserID) bool {
+			return slices.Contains(mentioned, member)
+		})
+	}
+
+	if msg.ThreadRootID == nil {
+		return pub.PublishForReceivers(
+			ctx,
+			services.GetReceivingUpdateMembers(members, msg.SenderID, msg),
+			events.TypeUpdate,
+			data,
+		)
+	}
+
+	participants, err := updateRepo.GetThreadParticipants(ctx, db, msg.ChatID, *msg.ThreadRootID)
+	if err != nil {
+		return err
+	}
+
+	participating, others := services.GetThreadReceivers(members, participants, msg.SenderID, msg)
+	if err := pub.PublishForReceivers(ctx, participating, events.TypeThreadUpdate, data); err != nil {
+		return err
+	}
+	return pub.PublishForReceivers(ctx, others, events.TypeUpdate, data)
+}
@@ -0,0 +1,205 @@
+package update
+
+import (
+	"context"
+	"errors"
+
+	"github.com/chakchat/chakchat-backend/messaging-service/internal/application/dto"
+	"github.com/chakchat/chakchat-backend/messaging-service/internal/application/generic"
+	"github.com/chakchat/chakchat-backend/messaging-service/internal/application/publish"
+	"github.com/chakchat/chakchat-backend/messaging-service/internal/application/publish/events"
+	"github.com/chakchat/chakchat-backend/messaging-service/internal/application/request"
+	"github.com/chakchat/chakchat-backend/messaging-service/internal/application/services"
+	"github.com/chakchat/chakchat-backend/messaging-service/internal/application/storage"
+	"github.com/chakchat/chakchat-backend/messaging-service/internal/application/storage/repository"
+	"github.com/chakchat/chakchat-backend/messaging-service/internal/domain"
+	"github.com/chakchat/chakchat-backend/messaging-service/internal/domain/group"
+	"github.com/google/uuid"
+)
+
+// TopicUpdateService manages messages of group topics.
+// Updates of a topic are stored with the topic ID as their chat ID.
+type TopicUpdateService struct {
+	txProvider storage.TxProvider
+	topicRepo  repository.TopicRepository
+	updateRepo repository.UpdateRepository
+	pub        publish.Publisher
+}
+
+func NewTopicUpdateService(
+	txProvider storage.TxProvider,
+	topicRepo repository.TopicRepository,
+	updateRepo repository.UpdateRepository,
+	pub publish.Publisher,
+) *TopicUpdateService {
+	return &TopicUpdateService{
+		txProvider: txProvider,
+		topicRepo:  topicRepo,
+		updateRepo: updateRepo,
+		pub:        pub,
+	}
+}
+
+func (s *TopicUpdateService) SendTextMessage(
+	ctx context.Context, req request.SendTopicTextMessage,
+) (_ *dto.TextMessageDTO, err error) {
+	tx, err := s.txProvider.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer storage.FinishTx(ctx, tx, &err)
+
+	topic, err := s.findTopic(ctx, tx, req.ChatID, req.TopicID)
+	if err != nil {
+		return nil, err
+	}
+
+	var replyToMessage *domain.Message
+	if req.ReplyToMessage != nil {
+		replyToMessage, err = s.updateRepo.FindGenericMessage(ctx, tx, topic.ID, domain.UpdateID(*req.ReplyToMessage), domain.UserID(req.SenderID))
+		if err != nil {
+			if errors.Is(err, repository.ErrNotFound) {
+				return nil, services.ErrMessageNotFound
+			}
+			return nil, err
+		}
+	}
+
+	msg, err := domain.NewTextMessage(topic, domain.UserID(req.SenderID), req.Text, toDomainEntities(req.Entities), replyToMessage)
+	if err != nil {
+		return nil, err
+	}
+
+	msg, err = s.updateRepo.CreateTextMessage(ctx, tx, msg)
+	if err != nil {
+		return nil, err
+	}
+
+	msgDto := dto.NewTextMessageDTO(msg)
+
+	err = s.publishTopicUpdate(ctx, topic, msg.SenderID, &msg.Update, generic.FromTextMessageDTO(&msgDto))
+	if err != nil {
+		return nil, err
+	}
+
+	return &msgDto, nil
+}
+
+func (s *TopicUpdateService) EditTextMessage(
+	ctx context.Context, req request.EditTopicTextMessage,
+) (_ *dto.TextMessageDTO, err error) {
+	tx, err := s.txProvider.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer storage.FinishTx(ctx, tx, &err)
+
+	topic, err := s.findTopic(ctx, tx, req.ChatID, req.TopicID)
+	if err != nil {
+		return nil, err
+	}
+
+	msg, err := s.updateRepo.FindTextMessage(ctx, tx, topic.ID, domain.UpdateID(req.MessageID), domain.UserID(req.SenderID))
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, services.ErrMessageNotFound
+		}
+		return nil, err
+	}
+
+	err = msg.Edit(topic, domain.UserID(req.SenderID), req.NewText, toDomainEntities(req.NewEntities))
+	if err != nil {
+		return nil, err
+	}
+
+	msg.Edited, err = s.updateRepo.CreateTextMessageEdited(ctx, tx, msg.Edited)
+	if err != nil {
+		return nil, err
+	}
+	msg, err = s.updateRepo.UpdateTextMessage(ctx, tx, msg)
+	if err != nil {
+		return nil, err
+	}
+
+	msgDto := dto.NewTextMessageDTO(msg)
+
+	err = s.publishTopicUpdate(ctx, topic, msg.Edited.SenderID, &msg.Update, generic.FromTextMessageEditedDTO(msgDto.Edited))
+	if err != nil {
+		return nil, err
+	}
+
+	return &msgDto, nil
+}
+
+func (s *TopicUpdateService) DeleteMessage(
+	ctx context.Context, req request.DeleteTopicMessage,
+) (_ *dto.UpdateDeletedDTO, err error) {
+	tx, err := s.txProvider.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer storage.FinishTx(ctx, tx, &err)
+
+	topic, err := s.findTopic(ctx, tx, req.ChatID, req.TopicID)
+	if err != nil {
+		return nil, err
+	}
+
+	msg, err := s.updateRepo.FindGenericMessage(ctx, tx, topic.ID, domain.UpdateID(req.MessageID), domain.UserID(req.SenderID))
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, services.ErrMessageNotFound
+		}
+		return nil, err
+	}
+
+	deleteMode, err := domain.NewDeleteMode(req.DeleteMode)
+	if err != nil {
+		return nil, err
+	}
+
+	err = msg.Delete(topic, domain.UserID(req.SenderID), deleteMode)
+	if err != nil {
+		return nil, err
+	}
+
+	deleted, err := s.updateRepo.CreateUpdateDeleted(ctx, tx, msg.Deleted[len(msg.Deleted)-1])
+	if err != nil {
+		return nil, err
+	}
+
+	deletedDto := dto.NewUpdateDeletedDTO(deleted)
+
+	if msg.DeletedForAll() {
+		err = s.publishTopicUpdate(ctx, topic, domain.UserID(req.SenderID), &msg.Update, generic.FromUpdateDeletedDTO(&deletedDto))
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return &deletedDto, nil
+}
+
+func (s *TopicUpdateService) publishTopicUpdate(
+	ctx context.Context, topic *group.Topic, sender domain.UserID, update *domain.Update, data generic.Update,
+) error {
+	return s.pub.PublishForReceivers(
+		ctx,
+		services.GetReceivingUpdateMembers(topic.Group.Members, sender, update),
+		events.TypeTopicUpdate,
+		data,
+	)
+}
+
+func (s *TopicUpdateService) findTopic(
+	ctx context.Context, db storage.ExecQuerier, chatID, topicID uuid.UUID,
+) (*group.Topic, error) {
+	topic, err := s.topicRepo.FindById(ctx, db, domain.ChatID(chatID), domain.ChatID(topicID))
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, services.ErrTopicNotFound
+		}
+		return nil, err
+	}
+	return topic, nil
+}
@@ -0,0 +1,36 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/chakchat/chakchat-backend/messaging-service/internal/application/publish"
+	"github.com/chakchat/chakchat-backend/messaging-service/internal/application/storage"
+	"github.com/chakchat/chakchat-backend/messaging-service/internal/domain"
+	"github.com/chakchat/chakchat-backend/messaging-service/internal/domain/channel"
+)
+
+type ChannelRepository interface {
+	// Loads the channel with subscriptions of the users.
+	// Should return ErrNotFound if entity is not found
+	FindById(ctx context.Context, db storage.ExecQuerier, id domain.ChatID, users ...domain.UserID) (*channel.Channel, error)
+	// Stores loaded subscriptions and updates the subscribers count
+	Update(context.Context, storage.ExecQuerier, *channel.Channel) (*channel.Channel, error)
+	Create(context.Context, storage.ExecQuerier, *channel.Channel) (*channel.Channel, error)
+	Delete(context.Context, storage.ExecQuerier, domain.ChatID) error
+
+	// Counts views of posts the user hasn't viewed yet. Unknown posts are ignored.
+	AddViews(ctx context.Context, db storage.ExecQuerier, chatID domain.ChatID, user domain.UserID, posts []domain.UpdateID) error
+	// Returns views count of the posts. Posts without views are omitted.
+	GetViews(ctx context.Context, db storage.ExecQuerier, chatID domain.ChatID, posts []domain.UpdateID) (map[domain.UpdateID]int64, error)
+}
+
+type ChannelFanoutRepository interface {
+	Create(context.Context, storage.ExecQuerier, *publish.Fanout) (*publish.Fanout, error)
+	// Returns the oldest fanouts locking them for other transactions.
+	// Fanouts locked by other transactions, parked ones and the ones waiting for retry are skipped.
+	FetchPending(ctx context.Context, db storage.ExecQuerier, limit int) ([]*publish.Fanout, error)
+	// Returns channel subscribers ordered by ID starting after the user. after may be nil.
+	GetSubscribers(ctx context.Context, db storage.ExecQuerier, chatID domain.ChatID, after *domain.UserID, limit int) ([]domain.UserID, error)
+	Update(context.Context, storage.ExecQuerier, *publish.Fanout) (*publish.Fanout, error)
+	Delete(ctx context.Context, db storage.ExecQuerier, id int64) error
+}
@@ -9,5 +9,7 @@ import (
 
 // TODO: move it to GenericChatRepository
 type ChatterRepository interface {
-	FindChatter(context.Context, storage.ExecQuerier, domain.ChatID) (domain.Chatter, error)
+	// Channels are loaded with subscriptions of the users only,
+	// so membership of other users is unknown.
+	FindChatter(ctx context.Context, db storage.ExecQuerier, id domain.ChatID, users ...domain.UserID) (domain.Chatter, error)
 }
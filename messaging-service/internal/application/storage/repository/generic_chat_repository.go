@@ -15,4 +15,6 @@ type GenericChatRepository interface {
 	GetByChatID(context.Context, storage.ExecQuerier, domain.ChatID) (*generic.Chat, error)
 	// Should return ErrNotFound if not found
 	GetChatType(context.Context, storage.ExecQuerier, domain.ChatID) (string, error)
+	// Works for chats of all types, including channels which members are not loaded with the chat
+	IsMember(context.Context, storage.ExecQuerier, domain.ChatID, domain.UserID) (bool, error)
 }
@@ -8,6 +8,9 @@ import (
 	"github.com/chakchat/chakchat-backend/messaging-service/internal/domain"
 )
 
+// Updates are returned only if they are visible to visibleTo.
+// Updates deleted for the user are not visible,
+// and neither are updates sent before the user joined a group with hidden history.
 type GenericUpdateRepository interface {
 	// Should return ErrNotFound if not found
 	GetLastUpdateID(
@@ -15,6 +18,7 @@ type GenericUpdateRepository interface {
 		storage.ExecQuerier,
 		domain.ChatID,
 	) (domain.UpdateID, error)
+	// Thread updates are not included
 	GetRange(
 		ctx context.Context,
 		db storage.ExecQuerier,
@@ -22,6 +26,15 @@ type GenericUpdateRepository interface {
 		chatID domain.ChatID,
 		from, to domain.UpdateID,
 	) ([]generic.Update, error)
+	// Returns only updates of the thread
+	GetThreadRange(
+		ctx context.Context,
+		db storage.ExecQuerier,
+		visibleTo domain.UserID,
+		chatID domain.ChatID,
+		rootID domain.UpdateID,
+		from, to domain.UpdateID,
+	) ([]generic.Update, error)
 	Get(
 		ctx context.Context,
 		db storage.ExecQuerier,
@@ -0,0 +1,19 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/chakchat/chakchat-backend/messaging-service/internal/application/storage"
+	"github.com/chakchat/chakchat-backend/messaging-service/internal/domain"
+	"github.com/chakchat/chakchat-backend/messaging-service/internal/domain/group"
+)
+
+type GroupBanRepository interface {
+	// Replaces the previous ban of the user if there is one
+	Store(context.Context, storage.ExecQuerier, *group.Ban) (*group.Ban, error)
+	// Should return ErrNotFound if not found. Expired bans are returned too.
+	Find(context.Context, storage.ExecQuerier, domain.ChatID, domain.UserID) (*group.Ban, error)
+	Delete(context.Context, storage.ExecQuerier, domain.ChatID, domain.UserID) error
+	// Returns active bans, the newest first
+	GetActive(ctx context.Context, db storage.ExecQuerier, chatID domain.ChatID, offset, limit int) ([]*group.Ban, error)
+}
@@ -0,0 +1,27 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/chakchat/chakchat-backend/messaging-service/internal/application/storage"
+	"github.com/chakchat/chakchat-backend/messaging-service/internal/domain"
+	"github.com/chakchat/chakchat-backend/messaging-service/internal/domain/group"
+)
+
+type InviteLinkRepository interface {
+	Create(context.Context, storage.ExecQuerier, *group.InviteLink) (*group.InviteLink, error)
+	// Should return ErrNotFound if not found.
+	// The link is locked until the end of transaction, so its usage is counted correctly.
+	FindByToken(ctx context.Context, db storage.ExecQuerier, token string) (*group.InviteLink, error)
+	// Stores usage count and revocation
+	Update(context.Context, storage.ExecQuerier, *group.InviteLink) (*group.InviteLink, error)
+	// Returns links ordered by creation time
+	GetByChat(context.Context, storage.ExecQuerier, domain.ChatID) ([]*group.InviteLink, error)
+
+	CreateJoinRequest(context.Context, storage.ExecQuerier, *group.JoinRequest) (*group.JoinRequest, error)
+	// Should return ErrNotFound if not found
+	FindJoinRequest(context.Context, storage.ExecQuerier, domain.ChatID, domain.UserID) (*group.JoinRequest, error)
+	DeleteJoinRequest(context.Context, storage.ExecQuerier, domain.ChatID, domain.UserID) error
+	// Returns requests ordered by creation time
+	GetJoinRequests(context.Context, storage.ExecQuerier, domain.ChatID) ([]*group.JoinRequest, error)
+}
@@ -0,0 +1,16 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/chakchat/chakchat-backend/messaging-service/internal/application/storage"
+	"github.com/chakchat/chakchat-backend/messaging-service/internal/domain"
+)
+
+type MentionRepository interface {
+	// Counts messages mentioning the user after the last read mention.
+	// The user's own messages and messages deleted for the user are not counted.
+	CountUnread(ctx context.Context, db storage.ExecQuerier, chatID domain.ChatID, userID domain.UserID) (int, error)
+	// Marks mentions up to the update as read. The read boundary never moves back.
+	MarkRead(ctx context.Context, db storage.ExecQuerier, chatID domain.ChatID, userID domain.UserID, until domain.UpdateID) error
+}
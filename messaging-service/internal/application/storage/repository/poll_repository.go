@@ -0,0 +1,17 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/chakchat/chakchat-backend/messaging-service/internal/application/storage"
+	"github.com/chakchat/chakchat-backend/messaging-service/internal/domain"
+)
+
+type PollRepository interface {
+	CreatePoll(context.Context, storage.ExecQuerier, *domain.Poll) (*domain.Poll, error)
+	// The poll is locked until the end of transaction,
+	// so concurrent votes don't overwrite each other when UpdatePoll stores all the votes.
+	FindPoll(context.Context, storage.ExecQuerier, domain.ChatID, domain.UpdateID) (*domain.Poll, error)
+	// Stores votes and closing of the poll
+	UpdatePoll(context.Context, storage.ExecQuerier, *domain.Poll) (*domain.Poll, error)
+}
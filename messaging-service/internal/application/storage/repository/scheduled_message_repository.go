@@ -0,0 +1,30 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/chakchat/chakchat-backend/messaging-service/internal/application/storage"
+	"github.com/chakchat/chakchat-backend/messaging-service/internal/domain"
+)
+
+var (
+	ErrScheduledClaimed = errors.New("scheduled message is claimed")
+)
+
+type ScheduledMessageRepository interface {
+	Create(context.Context, storage.ExecQuerier, *domain.ScheduledMessage) (*domain.ScheduledMessage, error)
+	FindByID(context.Context, storage.ExecQuerier, domain.ScheduledMessageID) (*domain.ScheduledMessage, error)
+	// Should return ErrScheduledClaimed if the message is claimed or already deleted by the dispatcher
+	Update(context.Context, storage.ExecQuerier, *domain.ScheduledMessage) (*domain.ScheduledMessage, error)
+	// Should return ErrScheduledClaimed if the message is claimed or already deleted by the dispatcher
+	Delete(context.Context, storage.ExecQuerier, domain.ScheduledMessageID) error
+	// Deletes the message whether it is claimed or not. It is used by the dispatcher after sending.
+	DeleteSent(context.Context, storage.ExecQuerier, domain.ScheduledMessageID) error
+	// Returns messages ordered by send time
+	GetByChatAndSender(ctx context.Context, db storage.ExecQuerier, chatID domain.ChatID, senderID domain.UserID) ([]*domain.ScheduledMessage, error)
+	// Claims and returns messages that should be sent before now.
+	// They are skipped by concurrent calls until the claim expires after claimFor.
+	ClaimDue(ctx context.Context, db storage.ExecQuerier, now time.Time, claimFor time.Duration, limit int) ([]*domain.ScheduledMessage, error)
+}
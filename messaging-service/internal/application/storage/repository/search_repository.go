@@ -0,0 +1,37 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/chakchat/chakchat-backend/messaging-service/internal/application/generic"
+	"github.com/chakchat/chakchat-backend/messaging-service/internal/application/storage"
+	"github.com/chakchat/chakchat-backend/messaging-service/internal/domain"
+)
+
+type SearchRepository interface {
+	// Searches text messages in non-secret chats the user is a member of.
+	// Messages deleted for the user are skipped,
+	// and so are messages sent before the user joined a group with hidden history.
+	// Results are ordered from the newest to the oldest.
+	SearchMessages(
+		ctx context.Context,
+		db storage.ExecQuerier,
+		visibleTo domain.UserID,
+		query domain.SearchQuery,
+		filter SearchFilter,
+	) ([]generic.FoundMessage, error)
+}
+
+// Nil fields are not applied
+type SearchFilter struct {
+	ChatID   *domain.ChatID
+	SenderID *domain.UserID
+	// Inclusive
+	Since *time.Time
+	// Exclusive
+	Until *time.Time
+
+	Offset int
+	Limit  int
+}
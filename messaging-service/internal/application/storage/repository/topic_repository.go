@@ -0,0 +1,30 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/chakchat/chakchat-backend/messaging-service/internal/application/storage"
+	"github.com/chakchat/chakchat-backend/messaging-service/internal/domain"
+	"github.com/chakchat/chakchat-backend/messaging-service/internal/domain/group"
+)
+
+type TopicStats struct {
+	LastUpdateID domain.UpdateID
+	// Messages of other members after the last read one
+	UnreadCount int
+}
+
+type TopicRepository interface {
+	// Loads the topic with its group.
+	// Should return ErrNotFound if the topic is not found in the group
+	FindById(ctx context.Context, db storage.ExecQuerier, chatID, topicID domain.ChatID) (*group.Topic, error)
+	// Returns topics of the group, the oldest first. Their group is not loaded.
+	GetByChatID(ctx context.Context, db storage.ExecQuerier, chatID domain.ChatID) ([]*group.Topic, error)
+	Create(context.Context, storage.ExecQuerier, *group.Topic) (*group.Topic, error)
+	Update(context.Context, storage.ExecQuerier, *group.Topic) (*group.Topic, error)
+	// Deletes the topic with all its updates
+	Delete(ctx context.Context, db storage.ExecQuerier, topicID domain.ChatID) error
+
+	GetStats(ctx context.Context, db storage.ExecQuerier, user domain.UserID, topics []domain.ChatID) (map[domain.ChatID]TopicStats, error)
+	MarkRead(ctx context.Context, db storage.ExecQuerier, topicID domain.ChatID, user domain.UserID, until domain.UpdateID) error
+}
@@ -7,21 +7,32 @@ import (
 	"github.com/chakchat/chakchat-backend/messaging-service/internal/domain"
 )
 
+// Find methods return ErrNotFound for messages hidden from visibleTo by the history visibility of the group.
+// So messages a new member can't read can't be replied to, forwarded, pinned or reacted to either.
 type UpdateRepository interface {
-	FindGenericMessage(context.Context, storage.ExecQuerier, domain.ChatID, domain.UpdateID) (*domain.Message, error)
+	FindGenericMessage(ctx context.Context, db storage.ExecQuerier, chatID domain.ChatID, updateID domain.UpdateID, visibleTo domain.UserID) (*domain.Message, error)
 	DeleteUpdate(context.Context, storage.ExecQuerier, domain.ChatID, domain.UpdateID) error
 	CreateUpdateDeleted(context.Context, storage.ExecQuerier, *domain.UpdateDeleted) (*domain.UpdateDeleted, error)
 
 	CreateTextMessage(context.Context, storage.ExecQuerier, *domain.TextMessage) (*domain.TextMessage, error)
 	CreateTextMessageEdited(context.Context, storage.ExecQuerier, *domain.TextMessageEdited) (*domain.TextMessageEdited, error)
-	FindTextMessage(context.Context, storage.ExecQuerier, domain.ChatID, domain.UpdateID) (*domain.TextMessage, error)
+	FindTextMessage(ctx context.Context, db storage.ExecQuerier, chatID domain.ChatID, updateID domain.UpdateID, visibleTo domain.UserID) (*domain.TextMessage, error)
 	UpdateTextMessage(context.Context, storage.ExecQuerier, *domain.TextMessage) (*domain.TextMessage, error)
 
 	CreateReaction(context.Context, storage.ExecQuerier, *domain.Reaction) (*domain.Reaction, error)
 	FindReaction(context.Context, storage.ExecQuerier, domain.ChatID, domain.UpdateID) (*domain.Reaction, error)
 
-	FindFileMessage(context.Context, storage.ExecQuerier, domain.ChatID, domain.UpdateID) (*domain.FileMessage, error)
+	CreatePin(context.Context, storage.ExecQuerier, *domain.Pin) (*domain.Pin, error)
+	// Returns true if the latest pin update of the message pins it
+	IsPinned(ctx context.Context, db storage.ExecQuerier, chatID domain.ChatID, messageID domain.UpdateID) (bool, error)
+
+	CreateServiceMessage(context.Context, storage.ExecQuerier, *domain.ServiceMessage) (*domain.ServiceMessage, error)
+
+	FindFileMessage(ctx context.Context, db storage.ExecQuerier, chatID domain.ChatID, updateID domain.UpdateID, visibleTo domain.UserID) (*domain.FileMessage, error)
 	CreateFileMessage(context.Context, storage.ExecQuerier, *domain.FileMessage) (*domain.FileMessage, error)
+
+	// Returns the root message sender and senders of thread messages
+	GetThreadParticipants(ctx context.Context, db storage.ExecQuerier, chatID domain.ChatID, rootID domain.UpdateID) ([]domain.UserID, error)
 }
 
 type SecretUpdateRepository interface {
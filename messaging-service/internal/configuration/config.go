@@ -2,6 +2,7 @@ package configuration
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/spf13/viper"
 )
@@ -32,10 +33,32 @@ type Config struct {
 		GrpcAddr string `mapstructure:"grpc_addr"`
 	} `mapstructure:"file_storage"`
 
+	UserService struct {
+		GrpcAddr string `mapstructure:"grpc_addr"`
+	} `mapstructure:"user_service"`
+
+	RateLimit struct {
+		SendMessage RateLimitConfig `mapstructure:"send_message"`
+	} `mapstructure:"rate_limit"`
+
 	Kafka struct {
 		Brokers []string `mapstructure:"brokers"`
 		Topic string `mapstructure:"topic"`
 	} `mapstructure:"kafka"`
+
+	ScheduledMessages struct {
+		DispatchInterval time.Duration `mapstructure:"dispatch_interval"`
+	} `mapstructure:"scheduled_messages"`
+
+	Channels struct {
+		FanoutInterval  time.Duration `mapstructure:"fanout_interval"`
+		FanoutBatchSize int           `mapstructure:"fanout_batch_size"`
+	} `mapstructure:"channels"`
+}
+
+type RateLimitConfig struct {
+	Requests int           `mapstructure:"requests"`
+	Window   time.Duration `mapstructure:"window"`
 }
 
 func LoadConfig(file string) (*Config, error) {
@@ -15,10 +15,19 @@ type DB struct {
 	SecretGroupChat    repository.SecretGroupChatRepository
 	Chatter            repository.ChatterRepository
 	GenericChat        repository.GenericChatRepository
+	InviteLink         repository.InviteLinkRepository
+	GroupBan           repository.GroupBanRepository
+	Channel            repository.ChannelRepository
+	ChannelFanout      repository.ChannelFanoutRepository
+	Topic              repository.TopicRepository
 
 	Update        repository.UpdateRepository
 	SecretUpdate  repository.SecretUpdateRepository
 	GenericUpdate repository.GenericUpdateRepository
+	Search        repository.SearchRepository
+	Poll          repository.PollRepository
+	Scheduled     repository.ScheduledMessageRepository
+	Mention       repository.MentionRepository
 
 	SQLer storage.SQLer
 
@@ -33,9 +42,18 @@ func NewDB(db storage.SQLer, redis *redis.Client) *DB {
 		SecretGroupChat:    chat.NewSecretGroupChatRepository(),
 		Chatter:            chat.NewChatterRepository(),
 		GenericChat:        chat.NewGenericChatRepository(),
+		InviteLink:         chat.NewInviteLinkRepository(),
+		GroupBan:           chat.NewGroupBanRepository(),
+		Channel:            chat.NewChannelRepository(),
+		ChannelFanout:      chat.NewChannelFanoutRepository(),
+		Topic:              chat.NewTopicRepository(),
 		Update:             update.NewUpdateRepository(),
 		SecretUpdate:       update.NewSecretUpdateRepository(),
 		GenericUpdate:      update.NewGenericUpdateRepository(),
+		Search:             update.NewSearchRepository(),
+		Poll:               update.NewPollRepository(),
+		Scheduled:          update.NewScheduledMessageRepository(),
+		Mention:            update.NewMentionRepository(),
 		SQLer:              db,
 		Redis:              redis,
 	}
@@ -10,11 +10,13 @@ import (
 type External struct {
 	Publisher   publish.Publisher
 	FileStorage external.FileStorage
+	UserService external.UserService
 }
 
-func NewExternal(fileStConn *grpc.ClientConn, mq external.MqPublisher) *External {
+func NewExternal(fileStConn, userConn *grpc.ClientConn, mq external.MqPublisher) *External {
 	return &External{
 		Publisher:   publish.NewUserEventPublisher(mq),
 		FileStorage: proto.NewFileStorage(fileStConn),
+		UserService: proto.NewUserService(userConn),
 	}
 }
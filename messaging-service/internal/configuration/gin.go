@@ -9,6 +9,7 @@ import (
 	"github.com/chakchat/chakchat-backend/shared/go/auth"
 	"github.com/chakchat/chakchat-backend/shared/go/idempotency"
 	"github.com/chakchat/chakchat-backend/shared/go/jwt"
+	"github.com/chakchat/chakchat-backend/shared/go/ratelimit"
 	"github.com/gin-gonic/gin"
 	"go.opentelemetry.io/contrib/instrumentation/github.com/gin-gonic/gin/otelgin"
 )
@@ -50,8 +51,21 @@ func GinEngine(handlers *Handlers, db *DB, conf *Config) (*gin.Engine, error) {
 			idempotency.NewStorage(db.Redis, &idempotency.IdempotencyConfig{
 				DataExp: 1 * time.Hour,
 			}),
+			idempotency.NewLocker(db.Redis, &idempotency.LockerConfig{
+				LockExp: 1 * time.Minute,
+			}),
 		))
 
+	// All message sending endpoints share the same per-user counter
+	sendLimit := ratelimit.New(ratelimit.NewRedisLimiter(db.Redis), &ratelimit.Config{
+		Name: "send_message",
+		Limit: ratelimit.Limit{
+			Requests: conf.RateLimit.SendMessage.Requests,
+			Window:   conf.RateLimit.SendMessage.Window,
+		},
+		Key: ratelimit.ByUser(),
+	})
+
 	r.GET("/v1.0/chat/all", handlers.GenericChat.GetAllChats)
 	r.GET("/v1.0/chat/:chatId", handlers.GenericChat.GetChat)
 
@@ -69,42 +83,108 @@ func GinEngine(handlers *Handlers, db *DB, conf *Config) (*gin.Engine, error) {
 	r.DELETE("/v1.0/chat/group/:chatId", handlers.GroupChat.DeleteGroup)
 	r.PUT("/v1.0/chat/group/:chatId/member/:memberId", handlers.GroupChat.AddMember)
 	r.DELETE("/v1.0/chat/group/:chatId/member/:memberId", handlers.GroupChat.DeleteMember)
+	r.PUT("/v1.0/chat/group/:chatId/admin/:memberId", handlers.GroupChat.SetAdmin)
+	r.DELETE("/v1.0/chat/group/:chatId/admin/:memberId", handlers.GroupChat.RemoveAdmin)
+	r.PUT("/v1.0/chat/group/:chatId/owner/:memberId", handlers.GroupChat.TransferOwnership)
+	r.POST("/v1.0/chat/group/:chatId/leave", handlers.GroupChat.LeaveGroup)
+	r.PUT("/v1.0/chat/group/:chatId/member-permissions", handlers.GroupChat.SetMemberPermissions)
+	r.PUT("/v1.0/chat/group/:chatId/posting-mode", handlers.GroupChat.SetPostingMode)
+	r.PUT("/v1.0/chat/group/:chatId/history-visibility", handlers.GroupChat.SetHistoryVisibility)
+	r.GET("/v1.0/chat/group/:chatId/ban", handlers.GroupChat.GetBans)
+	r.PUT("/v1.0/chat/group/:chatId/ban/:memberId", handlers.GroupChat.BanMember)
+	r.DELETE("/v1.0/chat/group/:chatId/ban/:memberId", handlers.GroupChat.UnbanMember)
+	idemp.POST("/v1.0/chat/group/:chatId/invite-link", handlers.InviteLink.CreateInviteLink)
+	r.GET("/v1.0/chat/group/:chatId/invite-link", handlers.InviteLink.GetInviteLinks)
+	r.DELETE("/v1.0/chat/group/:chatId/invite-link/:token", handlers.InviteLink.RevokeInviteLink)
+	r.POST("/v1.0/chat/group/join/:token", handlers.InviteLink.JoinByInviteLink)
+	r.POST("/v1.0/chat/group/join/:token/request", handlers.InviteLink.RequestToJoin)
+	r.GET("/v1.0/chat/group/:chatId/join-request", handlers.InviteLink.GetJoinRequests)
+	r.PUT("/v1.0/chat/group/:chatId/join-request/:memberId", handlers.InviteLink.ApproveJoinRequest)
+	r.DELETE("/v1.0/chat/group/:chatId/join-request/:memberId", handlers.InviteLink.DeclineJoinRequest)
 	r.PUT("/v1.0/chat/group/:chatId/photo", handlers.GroupPhoto.UpdatePhoto)
 	r.DELETE("/v1.0/chat/group/:chatId/photo", handlers.GroupPhoto.DeletePhoto)
+	idemp.POST("/v1.0/chat/group/:chatId/topic", handlers.Topic.CreateTopic)
+	r.GET("/v1.0/chat/group/:chatId/topic", handlers.Topic.GetTopics)
+	r.PUT("/v1.0/chat/group/:chatId/topic/:topicId", handlers.Topic.UpdateTopic)
+	r.DELETE("/v1.0/chat/group/:chatId/topic/:topicId", handlers.Topic.DeleteTopic)
+	r.PUT("/v1.0/chat/group/:chatId/topic/:topicId/close", handlers.Topic.CloseTopic)
+	r.PUT("/v1.0/chat/group/:chatId/topic/:topicId/reopen", handlers.Topic.ReopenTopic)
+	r.PUT("/v1.0/chat/group/:chatId/topic/:topicId/read", handlers.Topic.ReadTopic)
 
 	idemp.POST("/v1.0/chat/group/secret", handlers.SecretGroup.Create)
 	r.PUT("/v1.0/chat/group/secret/:chatId", handlers.SecretGroup.Update)
 	r.DELETE("/v1.0/chat/group/secret/:chatId", handlers.SecretGroup.Delete)
 	r.PUT("/v1.0/chat/group/secret/:chatId/member/:memberId", handlers.SecretGroup.AddMember)
 	r.DELETE("/v1.0/chat/group/secret/:chatId/member/:memberId", handlers.SecretGroup.DeleteMember)
+	r.PUT("/v1.0/chat/group/secret/:chatId/admin/:memberId", handlers.SecretGroup.SetAdmin)
+	r.DELETE("/v1.0/chat/group/secret/:chatId/admin/:memberId", handlers.SecretGroup.RemoveAdmin)
+	r.PUT("/v1.0/chat/group/secret/:chatId/owner/:memberId", handlers.SecretGroup.TransferOwnership)
+	r.POST("/v1.0/chat/group/secret/:chatId/leave", handlers.SecretGroup.LeaveGroup)
+	r.PUT("/v1.0/chat/group/secret/:chatId/member-permissions", handlers.SecretGroup.SetMemberPermissions)
 	r.PUT("/v1.0/chat/group/secret/:chatId/photo", handlers.SecretGroupPhoto.UpdatePhoto)
 	r.DELETE("/v1.0/chat/group/secret/:chatId/photo", handlers.SecretGroupPhoto.DeletePhoto)
 
-	r.GET("/v1.0/chat/:chatId/update", handlers.GenericUpdate.GetUpdatesRange)
+	idemp.POST("/v1.0/chat/channel", handlers.Channel.CreateChannel)
+	r.PUT("/v1.0/chat/channel/:chatId", handlers.Channel.UpdateChannel)
+	r.DELETE("/v1.0/chat/channel/:chatId", handlers.Channel.DeleteChannel)
+	r.PUT("/v1.0/chat/channel/:chatId/subscription", handlers.Channel.Subscribe)
+	r.DELETE("/v1.0/chat/channel/:chatId/subscription", handlers.Channel.Unsubscribe)
+	r.PUT("/v1.0/chat/channel/:chatId/admin/:memberId", handlers.Channel.SetAdmin)
+	r.DELETE("/v1.0/chat/channel/:chatId/admin/:memberId", handlers.Channel.RemoveAdmin)
 
-	idemp.POST("/v1.0/chat/personal/:chatId/update/message/text", handlers.PersonalUpdate.SendTextMessage)
+	r.GET("/v1.0/chat/:chatId/update", handlers.GenericUpdate.GetUpdatesRange)
+	r.GET("/v1.0/chat/:chatId/update/thread/:rootId", handlers.GenericUpdate.GetThreadUpdatesRange)
+	r.GET("/v1.0/chat/:chatId/topic/:topicId/update", handlers.GenericUpdate.GetUpdatesRange)
+	r.GET("/v1.0/update/message/search", handlers.Search.SearchMessages)
+	r.GET("/v1.0/chat/:chatId/update/scheduled", handlers.ScheduledMessage.GetScheduledMessages)
+	idemp.POST("/v1.0/chat/:chatId/update/scheduled/text", handlers.ScheduledMessage.ScheduleTextMessage)
+	idemp.POST("/v1.0/chat/:chatId/update/scheduled/file", handlers.ScheduledMessage.ScheduleFileMessage)
+	r.PUT("/v1.0/chat/:chatId/update/scheduled/:scheduledId", handlers.ScheduledMessage.EditScheduledMessage)
+	r.DELETE("/v1.0/chat/:chatId/update/scheduled/:scheduledId", handlers.ScheduledMessage.CancelScheduledMessage)
+
+	r.GET("/v1.0/chat/personal/:chatId/update/message/search", handlers.Search.SearchPersonalMessages)
+	idemp.POST("/v1.0/chat/personal/:chatId/update/message/text", sendLimit, handlers.PersonalUpdate.SendTextMessage)
 	r.DELETE("/v1.0/chat/personal/:chatId/update/message/:updateId/:deleteMode", handlers.PersonalUpdate.DeleteMessage)
 	r.PUT("/v1.0/chat/personal/:chatId/update/message/text/:updateId", handlers.PersonalUpdate.EditTextMessage)
-	idemp.POST("/v1.0/chat/personal/:chatId/update/message/file", handlers.PersonalFile.SendFileMessage)
+	idemp.POST("/v1.0/chat/personal/:chatId/update/message/file", sendLimit, handlers.PersonalFile.SendFileMessage)
 	idemp.POST("/v1.0/chat/personal/:chatId/update/reaction", handlers.PersonalUpdate.SendReaction)
 	r.DELETE("/v1.0/chat/personal/:chatId/update/reaction/:updateId", handlers.PersonalUpdate.DeleteReaction)
-	idemp.POST("/v1.0/chat/personal/:chatId/update/text-message/forward", handlers.PersonalUpdate.ForwardTextMessage)
-	idemp.POST("/v1.0/chat/personal/:chatId/update/file-message/forward", handlers.PersonalUpdate.ForwardFileMessage)
+	idemp.POST("/v1.0/chat/personal/:chatId/update/pin", handlers.PersonalUpdate.PinMessage)
+	idemp.POST("/v1.0/chat/personal/:chatId/update/unpin", handlers.PersonalUpdate.UnpinMessage)
+	idemp.POST("/v1.0/chat/personal/:chatId/update/text-message/forward", sendLimit, handlers.PersonalUpdate.ForwardTextMessage)
+	idemp.POST("/v1.0/chat/personal/:chatId/update/file-message/forward", sendLimit, handlers.PersonalUpdate.ForwardFileMessage)
 
-	idemp.POST("/v1.0/chat/group/:chatId/update/message/text", handlers.GroupUpdate.SendTextMessage)
+	r.GET("/v1.0/chat/group/:chatId/update/message/search", handlers.Search.SearchGroupMessages)
+	idemp.POST("/v1.0/chat/group/:chatId/update/message/text", sendLimit, handlers.GroupUpdate.SendTextMessage)
 	r.DELETE("/v1.0/chat/group/:chatId/update/message/:updateId/:deleteMode", handlers.GroupUpdate.DeleteMessage)
 	r.PUT("/v1.0/chat/group/:chatId/update/message/text/:updateId", handlers.GroupUpdate.EditTextMessage)
-	idemp.POST("/v1.0/chat/group/:chatId/update/message/file", handlers.GroupFile.SendFileMessage)
+	idemp.POST("/v1.0/chat/group/:chatId/update/message/file", sendLimit, handlers.GroupFile.SendFileMessage)
 	idemp.POST("/v1.0/chat/group/:chatId/update/reaction", handlers.GroupUpdate.SendReaction)
 	r.DELETE("/v1.0/chat/group/:chatId/update/reaction/:updateId", handlers.GroupUpdate.DeleteReaction)
-	idemp.POST("/v1.0/chat/group/:chatId/update/text-message/forward", handlers.GroupUpdate.ForwardTextMessage)
-	idemp.POST("/v1.0/chat/group/:chatId/update/file-message/forward", handlers.GroupUpdate.ForwardFileMessage)
-
-	idemp.POST("/v1.0/chat/personal/secret/:chatId/update/secret", handlers.SecretPersonalUpdate.SendSecretUpdate)
+	idemp.POST("/v1.0/chat/group/:chatId/update/pin", handlers.GroupUpdate.PinMessage)
+	idemp.POST("/v1.0/chat/group/:chatId/update/unpin", handlers.GroupUpdate.UnpinMessage)
+	idemp.POST("/v1.0/chat/group/:chatId/update/message/poll", sendLimit, handlers.Poll.SendPoll)
+	r.PUT("/v1.0/chat/group/:chatId/update/poll/:updateId/vote", handlers.Poll.Vote)
+	r.DELETE("/v1.0/chat/group/:chatId/update/poll/:updateId/vote", handlers.Poll.RetractVote)
+	r.PUT("/v1.0/chat/group/:chatId/update/poll/:updateId/close", handlers.Poll.ClosePoll)
+	r.PUT("/v1.0/chat/group/:chatId/update/mention/read", handlers.Mention.ReadMentions)
+	idemp.POST("/v1.0/chat/group/:chatId/update/text-message/forward", sendLimit, handlers.GroupUpdate.ForwardTextMessage)
+	idemp.POST("/v1.0/chat/group/:chatId/update/file-message/forward", sendLimit, handlers.GroupUpdate.ForwardFileMessage)
+
+	idemp.POST("/v1.0/chat/personal/secret/:chatId/update/secret", sendLimit, handlers.SecretPersonalUpdate.SendSecretUpdate)
 	r.DELETE("/v1.0/chat/personal/secret/:chatId/update/secret/:updateId", handlers.SecretPersonalUpdate.DeleteSecretUpdate)
 
-	idemp.POST("/v1.0/chat/group/secret/:chatId/update/secret", handlers.SecretGroupUpdate.SendSecretUpdate)
+	idemp.POST("/v1.0/chat/group/secret/:chatId/update/secret", sendLimit, handlers.SecretGroupUpdate.SendSecretUpdate)
 	r.DELETE("/v1.0/chat/group/secret/:chatId/update/secret/:updateId", handlers.SecretGroupUpdate.DeleteSecretUpdate)
 
+	idemp.POST("/v1.0/chat/channel/:chatId/update/message/text", sendLimit, handlers.ChannelUpdate.SendPost)
+	r.DELETE("/v1.0/chat/channel/:chatId/update/message/:updateId/:deleteMode", handlers.ChannelUpdate.DeletePost)
+	r.PUT("/v1.0/chat/channel/:chatId/update/message/text/:updateId", handlers.ChannelUpdate.EditPost)
+	r.POST("/v1.0/chat/channel/:chatId/update/view", handlers.ChannelUpdate.ViewPosts)
+
+	idemp.POST("/v1.0/chat/group/:chatId/topic/:topicId/update/message/text", sendLimit, handlers.TopicUpdate.SendTextMessage)
+	r.DELETE("/v1.0/chat/group/:chatId/topic/:topicId/update/message/:updateId/:deleteMode", handlers.TopicUpdate.DeleteMessage)
+	r.PUT("/v1.0/chat/group/:chatId/topic/:topicId/update/message/text/:updateId", handlers.TopicUpdate.EditTextMessage)
+
 	return r, nil
 }
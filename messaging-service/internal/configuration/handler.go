@@ -13,6 +13,9 @@ type Handlers struct {
 	SecretGroup        *chat.SecretGroupHandler
 	SecretGroupPhoto   *chat.SecretGroupPhotoHandler
 	GenericChat        *chat.GenericChatHandler
+	InviteLink         *chat.InviteLinkHandler
+	Channel            *chat.ChannelHandler
+	Topic              *chat.TopicHandler
 
 	PersonalUpdate       *update.PersonalUpdateHandler
 	PersonalFile         *update.PersonalFileHandler
@@ -21,6 +24,12 @@ type Handlers struct {
 	SecretPersonalUpdate *update.SecretPersonalUpdateHandler
 	SecretGroupUpdate    *update.SecretGroupUpdateHandler
 	GenericUpdate        *update.GenericUpdateHandler
+	Search               *update.SearchHandler
+	Poll                 *update.PollHandler
+	ScheduledMessage     *update.ScheduledMessageHandler
+	Mention              *update.MentionHandler
+	ChannelUpdate        *update.ChannelUpdateHandler
+	TopicUpdate          *update.TopicUpdateHandler
 }
 
 func NewHandlers(services *Services) *Handlers {
@@ -32,6 +41,7 @@ func NewHandlers(services *Services) *Handlers {
 		SecretGroup:          chat.NewSecretGroupHandler(services.SecretGroup),
 		SecretGroupPhoto:     chat.NewSecretGroupPhotoHandler(services.SecretGroupPhoto),
 		GenericChat:          chat.NewGenericChatHandler(services.GenericChat),
+		InviteLink:           chat.NewInviteLinkHandler(services.InviteLink),
 		PersonalUpdate:       update.NewPersonalUpdateHandler(services.PersonalUpdate),
 		PersonalFile:         update.NewFileHandler(services.PersonalFile),
 		GroupUpdate:          update.NewGroupUpdateHandler(services.GroupUpdate),
@@ -39,5 +49,13 @@ func NewHandlers(services *Services) *Handlers {
 		SecretPersonalUpdate: update.NewSecretPersonalUpdateHandler(services.SecretPersonalUpdate),
 		SecretGroupUpdate:    update.NewSecretGroupUpdateHandler(services.SecretGroupUpdate),
 		GenericUpdate:        update.NewGenericUpdateHandler(services.GenericUpdate),
+		Search:               update.NewSearchHandler(services.Search),
+		Poll:                 update.NewPollHandler(services.Poll),
+		ScheduledMessage:     update.NewScheduledMessageHandler(services.ScheduledMessage),
+		Mention:              update.NewMentionHandler(services.Mention),
+		Channel:              chat.NewChannelHandler(services.Channel),
+		ChannelUpdate:        update.NewChannelUpdateHandler(services.ChannelUpdate),
+		Topic:                chat.NewTopicHandler(services.Topic),
+		TopicUpdate:          update.NewTopicUpdateHandler(services.TopicUpdate),
 	}
 }
@@ -13,6 +13,9 @@ type Services struct {
 	SecretGroup        *chat.SecretGroupChatService
 	SecretGroupPhoto   *chat.SecretGroupPhotoService
 	GenericChat        *chat.GenericChatService
+	InviteLink         *chat.InviteLinkService
+	Channel            *chat.ChannelService
+	Topic              *chat.TopicService
 
 	PersonalUpdate       *update.PersonalUpdateService
 	PersonalFile         *update.PersonalFileService
@@ -21,30 +24,47 @@ type Services struct {
 	SecretPersonalUpdate *update.SecretPersonalUpdateService
 	SecretGroupUpdate    *update.SecretGroupUpdateService
 	GenericUpdate        *update.GenericUpdateService
+	Search               *update.SearchService
+	Poll                 *update.PollService
+	ScheduledMessage     *update.ScheduledMessageService
+	Mention              *update.MentionService
+	ScheduledDispatcher  *update.ScheduledDispatcher
+	ChannelUpdate        *update.ChannelUpdateService
+	FanoutDispatcher     *update.FanoutDispatcher
+	TopicUpdate          *update.TopicUpdateService
 }
 
-func NewServices(db *DB, external *External) *Services {
-	return &Services{
+func NewServices(db *DB, external *External, conf *Config) *Services {
+	srv := &Services{
 		PersonalChat: chat.NewPersonalChatService(
 			db.SQLer, db.PersonalChat, external.Publisher,
 		),
 		GroupChat: chat.NewGroupChatService(
-			db.SQLer, db.GroupChat, external.Publisher,
+			db.SQLer, db.GroupChat, db.GroupBan, db.Update, external.Publisher,
 		),
 		GroupPhoto: chat.NewGroupPhotoService(
-			db.SQLer, db.GroupChat, external.FileStorage, external.Publisher,
+			db.SQLer, db.GroupChat, external.FileStorage, db.Update, external.Publisher,
 		),
 		SecretPersonalChat: chat.NewSecretPersonalChatService(
-			db.SQLer, db.SecretPersonalChat, external.Publisher,
+			db.SQLer, db.SecretPersonalChat, db.Update, external.Publisher,
 		),
 		SecretGroup: chat.NewSecretGroupChatService(
-			db.SQLer, db.SecretGroupChat, external.Publisher,
+			db.SQLer, db.SecretGroupChat, db.Update, external.Publisher,
 		),
 		SecretGroupPhoto: chat.NewSecretGroupPhotoService(
-			db.SQLer, db.SecretGroupChat, external.FileStorage, external.Publisher,
+			db.SQLer, db.SecretGroupChat, external.FileStorage, db.Update, external.Publisher,
 		),
 		GenericChat: chat.NewGenericChatService(
-			db.SQLer, db.GenericChat, db.GenericUpdate,
+			db.SQLer, db.GenericChat, db.GenericUpdate, db.Mention,
+		),
+		InviteLink: chat.NewInviteLinkService(
+			db.SQLer, db.GroupChat, db.InviteLink, db.GroupBan, db.Update, external.Publisher,
+		),
+		Channel: chat.NewChannelService(
+			db.SQLer, db.Channel, db.ChannelFanout, external.Publisher,
+		),
+		Topic: chat.NewTopicService(
+			db.SQLer, db.GroupChat, db.Topic, external.Publisher,
 		),
 		PersonalUpdate: update.NewPersonalUpdateService(
 			db.SQLer, db.PersonalChat, db.Update, db.Chatter, external.Publisher,
@@ -53,7 +73,7 @@ func NewServices(db *DB, external *External) *Services {
 			db.SQLer, db.PersonalChat, db.Update, external.FileStorage, external.Publisher,
 		),
 		GroupUpdate: update.NewGroupUpdateService(
-			db.SQLer, db.GroupChat, db.Update, db.Chatter, external.Publisher,
+			db.SQLer, db.GroupChat, db.Update, db.Chatter, external.UserService, external.Publisher,
 		),
 		GroupFile: update.NewGroupFileService(
 			db.SQLer, db.GroupChat, db.Update, external.FileStorage, external.Publisher,
@@ -65,7 +85,33 @@ func NewServices(db *DB, external *External) *Services {
 			db.SQLer, db.SecretGroupChat, db.SecretUpdate, external.Publisher,
 		),
 		GenericUpdate: update.NewGenericUpdateService(
-			db.SQLer, db.Chatter, db.GenericUpdate,
+			db.SQLer, db.Chatter, db.GenericUpdate, db.Update,
+		),
+		Search: update.NewSearchService(
+			db.SQLer, db.GenericChat, db.Search,
+		),
+		Poll: update.NewPollService(
+			db.SQLer, db.GroupChat, db.Update, db.Poll, external.Publisher,
+		),
+		ScheduledMessage: update.NewScheduledMessageService(
+			db.SQLer, db.Chatter, db.GenericChat, db.Scheduled,
+		),
+		Mention: update.NewMentionService(
+			db.SQLer, db.GenericChat, db.Chatter, db.Mention,
+		),
+		ChannelUpdate: update.NewChannelUpdateService(
+			db.SQLer, db.Channel, db.Update, db.ChannelFanout,
+		),
+		FanoutDispatcher: update.NewFanoutDispatcher(
+			db.SQLer, db.ChannelFanout, external.Publisher, conf.Channels.FanoutBatchSize,
+		),
+		TopicUpdate: update.NewTopicUpdateService(
+			db.SQLer, db.Topic, db.Update, external.Publisher,
 		),
 	}
+	srv.ScheduledDispatcher = update.NewScheduledDispatcher(
+		db.SQLer, db.GenericChat, db.Scheduled,
+		srv.PersonalUpdate, srv.PersonalFile, srv.GroupUpdate, srv.GroupFile,
+	)
+	return srv
 }
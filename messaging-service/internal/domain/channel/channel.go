@@ -0,0 +1,132 @@
+package channel
+
+import (
+	"github.com/chakchat/chakchat-backend/messaging-service/internal/domain"
+)
+
+// MaxViewedPosts limits the number of posts viewed at once
+const MaxViewedPosts = 100
+
+// Channel is a broadcast chat. Only admins post, subscribers have read-only access.
+//
+// A channel may have millions of subscribers, so they are never loaded all together.
+// Only subscriptions of users the channel is loaded for are known.
+type Channel struct {
+	domain.Chat
+	domain.GroupRoles
+
+	Name             string
+	Description      string
+	SubscribersCount int64
+
+	// Subscription status of users the channel is loaded for
+	Subscribed map[domain.UserID]bool
+}
+
+// NewChannel creates a channel. The owner is its first subscriber.
+func NewChannel(owner domain.UserID, name, description string) (*Channel, error) {
+	if err := domain.ValidateGroupInfo(name, description); err != nil {
+		return nil, err
+	}
+
+	return &Channel{
+		Chat: domain.Chat{
+			ID: domain.NewChatID(),
+		},
+		GroupRoles:       domain.NewGroupRoles(owner),
+		Name:             name,
+		Description:      description,
+		SubscribersCount: 1,
+		Subscribed: map[domain.UserID]bool{
+			owner: true,
+		},
+	}, nil
+}
+
+// IsMember reports whether the user is subscribed to the channel.
+// The user's subscription must be loaded.
+func (c *Channel) IsMember(user domain.UserID) bool {
+	return c.IsAdmin(user) || c.Subscribed[user]
+}
+
+func (c *Channel) ValidateCanSend(user domain.UserID) error {
+	if !c.IsMember(user) {
+		return domain.ErrUserNotMember
+	}
+	if !c.IsAdmin(user) {
+		return domain.ErrChannelReadOnly
+	}
+	return nil
+}
+
+func (c *Channel) Delete(sender domain.UserID) error {
+	if !c.IsOwner(sender) {
+		return domain.ErrSenderNotOwner
+	}
+	return nil
+}
+
+func (c *Channel) UpdateInfo(sender domain.UserID, name, description string) error {
+	if err := c.ValidateAdminPermission(sender, domain.PermissionEditInfo); err != nil {
+		return err
+	}
+
+	if err := domain.ValidateGroupInfo(name, description); err != nil {
+		return err
+	}
+
+	c.Name = name
+	c.Description = description
+	return nil
+}
+
+func (c *Channel) Subscribe(user domain.UserID) error {
+	if c.IsMember(user) {
+		return domain.ErrAlreadySubscribed
+	}
+
+	c.setSubscribed(user, true)
+	c.SubscribersCount++
+	return nil
+}
+
+// Unsubscribe removes the subscription and admin rights of the user.
+// The owner can't unsubscribe, it should transfer ownership or delete the channel.
+func (c *Channel) Unsubscribe(user domain.UserID) error {
+	if !c.IsMember(user) {
+		return domain.ErrNotSubscribed
+	}
+	if c.IsOwner(user) {
+		return domain.ErrMemberIsOwner
+	}
+
+	delete(c.Admins, user)
+	c.setSubscribed(user, false)
+	c.SubscribersCount--
+	return nil
+}
+
+func (c *Channel) SetAdmin(sender, user domain.UserID, perms domain.AdminPermissions) error {
+	if !c.IsMember(user) {
+		return domain.ErrNotSubscribed
+	}
+	return c.GroupRoles.SetAdmin(sender, user, perms)
+}
+
+// ValidateCanView checks that the user can view postsCount posts at once
+func (c *Channel) ValidateCanView(user domain.UserID, postsCount int) error {
+	if !c.IsMember(user) {
+		return domain.ErrUserNotMember
+	}
+	if postsCount > MaxViewedPosts {
+		return domain.ErrTooManyViewedPosts
+	}
+	return nil
+}
+
+func (c *Channel) setSubscribed(user domain.UserID, subscribed bool) {
+	if c.Subscribed == nil {
+		c.Subscribed = make(map[domain.UserID]bool)
+	}
+	c.Subscribed[user] = subscribed
+}
@@ -0,0 +1,60 @@
+package channel
+
+import (
+	"testing"
+
+	"github.com/chakchat/chakchat-backend/messaging-service/internal/domain"
+	"github.com/stretchr/testify/require"
+)
+
+func TestChannel(t *testing.T) {
+	owner, _ := domain.NewUserID("3d7ca3ef-3b0d-4113-91c9-20b7bf874324")
+	admin, _ := domain.NewUserID("ce30ebc7-4058-4351-9a8f-66c71f987fdf")
+	subscriber, _ := domain.NewUserID("fb048277-ad4f-4730-88eb-5e453c9ca5ce")
+
+	t.Run("Subscribe", func(t *testing.T) {
+		c, err := NewChannel(owner, "channel", "")
+		require.NoError(t, err)
+		require.Equal(t, int64(1), c.SubscribersCount)
+
+		require.ErrorIs(t, c.Unsubscribe(subscriber), domain.ErrNotSubscribed)
+		require.NoError(t, c.Subscribe(subscriber))
+		require.ErrorIs(t, c.Subscribe(subscriber), domain.ErrAlreadySubscribed)
+		require.True(t, c.IsMember(subscriber))
+		require.Equal(t, int64(2), c.SubscribersCount)
+
+		require.ErrorIs(t, c.Unsubscribe(owner), domain.ErrMemberIsOwner)
+		require.NoError(t, c.Unsubscribe(subscriber))
+		require.False(t, c.IsMember(subscriber))
+		require.Equal(t, int64(1), c.SubscribersCount)
+	})
+
+	t.Run("OnlyAdminsPost", func(t *testing.T) {
+		c, err := NewChannel(owner, "channel", "")
+		require.NoError(t, err)
+		require.NoError(t, c.Subscribe(subscriber))
+
+		require.ErrorIs(t, c.SetAdmin(owner, admin, domain.PermissionEditInfo), domain.ErrNotSubscribed)
+		require.NoError(t, c.Subscribe(admin))
+		require.NoError(t, c.SetAdmin(owner, admin, domain.PermissionEditInfo))
+
+		require.NoError(t, c.ValidateCanSend(owner))
+		require.NoError(t, c.ValidateCanSend(admin))
+		require.ErrorIs(t, c.ValidateCanSend(subscriber), domain.ErrChannelReadOnly)
+
+		// Unsubscribed admins lose their rights
+		require.NoError(t, c.Unsubscribe(admin))
+		require.False(t, c.IsAdmin(admin))
+		require.ErrorIs(t, c.ValidateCanSend(admin), domain.ErrUserNotMember)
+	})
+
+	t.Run("ViewPosts", func(t *testing.T) {
+		c, err := NewChannel(owner, "channel", "")
+		require.NoError(t, err)
+
+		require.ErrorIs(t, c.ValidateCanView(subscriber, 1), domain.ErrUserNotMember)
+		require.NoError(t, c.Subscribe(subscriber))
+		require.NoError(t, c.ValidateCanView(subscriber, MaxViewedPosts))
+		require.ErrorIs(t, c.ValidateCanView(subscriber, MaxViewedPosts+1), domain.ErrTooManyViewedPosts)
+	})
+}
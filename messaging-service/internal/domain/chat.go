@@ -11,6 +11,8 @@ const (
 	ChatTypeGroup          = "group"
 	ChatTypeSecretPersonal = "secret_personal"
 	ChatTypeSecretGroup    = "secret_group"
+	ChatTypeChannel        = "channel"
+	ChatTypeGroupTopic     = "group_topic"
 )
 
 const (
@@ -48,6 +50,20 @@ type Chatter interface {
 	ValidateCanSend(UserID) error
 }
 
+// PostChatter is a chat with extra restrictions on creating new content, like slow mode.
+// Editing, deleting and voting are validated by ValidateCanSend only.
+type PostChatter interface {
+	ValidateCanPost(UserID) error
+}
+
+// validateCanPost checks that the sender can create new content in the chat
+func validateCanPost(chat Chatter, sender UserID) error {
+	if pc, ok := chat.(PostChatter); ok {
+		return pc.ValidateCanPost(sender)
+	}
+	return chat.ValidateCanSend(sender)
+}
+
 func NormilizeMembers(members []UserID) []UserID {
 	met := make(map[UserID]struct{}, len(members))
 	normMembers := make([]UserID, 0, len(members))
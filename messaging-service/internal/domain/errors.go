@@ -8,30 +8,110 @@ func (e Error) Error() string {
 	return e.text
 }
 
+// RetryError tells that the action will be allowed at RetryAt
+type RetryError struct {
+	Err     Error
+	RetryAt Timestamp
+}
+
+func (e RetryError) Error() string {
+	return e.Err.Error()
+}
+
+func (e RetryError) Unwrap() error {
+	return e.Err
+}
+
 // NOTE:
 // If you add an error here
 // You alse should add it to `errmap` package
 var (
-	ErrAdminNotMember      = Error{"group members doesn't include admin"}
-	ErrGroupNameEmpty      = Error{"group name is empty"}
-	ErrGroupNameTooLong    = Error{"group name is too long"}
-	ErrGroupDescTooLong    = Error{"group description is too long"}
-	ErrUserAlreadyMember   = Error{"user is already a member of a chat"}
-	ErrMemberIsAdmin       = Error{"group member is admin"}
-	ErrGroupPhotoEmpty     = Error{"group photo is empty"}
-	ErrChatWithMyself      = Error{"chat with myself"}
-	ErrChatBlocked         = Error{"chat is blocked"}
-	ErrFileTooBig          = Error{"file is too big"}
-	ErrReactionNotFromUser = Error{"the reaction is not from this user"}
-	ErrTooManyTextRunes    = Error{"too many runes in text"}
-	ErrTextEmpty           = Error{"the text is empty"}
-	ErrUserNotSender       = Error{"user is not update's sender"}
-	ErrUpdateNotFromChat   = Error{"update is not from this chat"}
-	ErrUpdateDeleted       = Error{"update is deleted"}
-	ErrAlreadyBlocked      = Error{"chat is already blocked"}
-	ErrAlreadyUnblocked    = Error{"chat is already unblocked"}
-	ErrSenderNotAdmin      = Error{"sender is not admin"}
-	ErrUserNotMember       = Error{"user is not member of a chat"}
-	ErrInvalidDeleteMode   = Error{"invalid delete mode"}
-	ErrInvalidReactionType = Error{"invalid reaction type"}
+	ErrAdminNotMember       = Error{"group members doesn't include admin"}
+	ErrGroupNameEmpty       = Error{"group name is empty"}
+	ErrGroupNameTooLong     = Error{"group name is too long"}
+	ErrGroupDescTooLong     = Error{"group description is too long"}
+	ErrUserAlreadyMember    = Error{"user is already a member of a chat"}
+	ErrMemberIsAdmin        = Error{"group member is admin"}
+	ErrGroupPhotoEmpty      = Error{"group photo is empty"}
+	ErrChatWithMyself       = Error{"chat with myself"}
+	ErrChatBlocked          = Error{"chat is blocked"}
+	ErrFileTooBig           = Error{"file is too big"}
+	ErrReactionNotFromUser  = Error{"the reaction is not from this user"}
+	ErrTooManyTextRunes     = Error{"too many runes in text"}
+	ErrTextEmpty            = Error{"the text is empty"}
+	ErrUserNotSender        = Error{"user is not update's sender"}
+	ErrUpdateNotFromChat    = Error{"update is not from this chat"}
+	ErrUpdateDeleted        = Error{"update is deleted"}
+	ErrAlreadyBlocked       = Error{"chat is already blocked"}
+	ErrAlreadyUnblocked     = Error{"chat is already unblocked"}
+	ErrSenderNotAdmin       = Error{"sender is not admin"}
+	ErrUserNotMember        = Error{"user is not member of a chat"}
+	ErrInvalidDeleteMode    = Error{"invalid delete mode"}
+	ErrInvalidReactionType  = Error{"invalid reaction type"}
+	ErrSearchQueryEmpty     = Error{"search query is empty"}
+	ErrSearchQueryTooLong   = Error{"search query is too long"}
+	ErrNestedThread         = Error{"thread message can't be a thread root"}
+	ErrUpdateNotFromThread  = Error{"update is not from this thread"}
+	ErrReplyOutsideThread   = Error{"thread message can be replied only in its thread"}
+	ErrMessageAlreadyPinned = Error{"message is already pinned"}
+	ErrMessageNotPinned     = Error{"message is not pinned"}
+	ErrPollQuestionEmpty    = Error{"poll question is empty"}
+	ErrPollQuestionTooLong  = Error{"poll question is too long"}
+	ErrPollOptionsCount     = Error{"invalid number of poll options"}
+	ErrPollInvalidOption    = Error{"invalid poll option"}
+	ErrPollCloseTimePassed  = Error{"poll close time has already passed"}
+	ErrPollClosed           = Error{"poll is closed"}
+	ErrPollNoOptionChosen   = Error{"no poll option is chosen"}
+	ErrPollSingleChoice     = Error{"poll allows only one option"}
+	ErrPollNotVoted         = Error{"user has not voted in the poll"}
+	ErrPollCloseForbidden   = Error{"only poll author or admin can close the poll"}
+	ErrScheduleTimePassed   = Error{"scheduled send time has already passed"}
+	ErrScheduleTooFar       = Error{"scheduled send time is too far"}
+	ErrScheduledFileText    = Error{"text of scheduled file message can't be set"}
+	ErrScheduledSending     = Error{"scheduled message is already being sent"}
+	ErrMentionInvalid       = Error{"mention doesn't match the text"}
+	ErrMentionNotMember     = Error{"mentioned user is not member of a chat"}
+	ErrTooManyMentions      = Error{"too many mentions"}
+	ErrTextEntityInvalid    = Error{"text entity is invalid"}
+	ErrTextEntityOutOfRange = Error{"text entity is out of text range"}
+	ErrTextEntitiesOverlap  = Error{"text entities overlap"}
+	ErrTextEntityURLInvalid = Error{"text entity url is invalid"}
+	ErrTooManyTextEntities  = Error{"too many text entities"}
+	ErrInvalidPermissions   = Error{"invalid permissions"}
+	ErrPermissionDenied     = Error{"sender doesn't have such permission"}
+	ErrMemberRestricted     = Error{"group members are restricted from sending it"}
+	ErrMemberIsOwner        = Error{"group member is owner"}
+	ErrMemberNotAdmin       = Error{"group member is not admin"}
+	ErrSenderNotOwner       = Error{"sender is not group owner"}
+	ErrOwnerLastMember      = Error{"group owner is the last member"}
+
+	ErrInviteLinkInvalid           = Error{"invite link is invalid"}
+	ErrInviteLinkNotFromChat       = Error{"invite link is not from this chat"}
+	ErrInviteLinkRevoked           = Error{"invite link is revoked"}
+	ErrInviteLinkExpired           = Error{"invite link is expired"}
+	ErrInviteLinkUsageLimitReached = Error{"invite link usage limit is reached"}
+	ErrJoinRequiresApproval        = Error{"joining requires admin approval"}
+	ErrJoinApprovalNotRequired     = Error{"joining doesn't require approval"}
+	ErrJoinRequestNotFromChat      = Error{"join request is not from this chat"}
+
+	ErrBanSelf              = Error{"sender can't ban itself"}
+	ErrBanReasonTooLong     = Error{"ban reason is too long"}
+	ErrBanExpirationInvalid = Error{"ban expiration is invalid"}
+	ErrBanNotFromChat       = Error{"ban is not from this chat"}
+	ErrUserBanned           = Error{"user is banned in the group"}
+
+	ErrChannelReadOnly    = Error{"only channel admins can post"}
+	ErrAlreadySubscribed  = Error{"user is already subscribed to the channel"}
+	ErrNotSubscribed      = Error{"user is not subscribed to the channel"}
+	ErrTooManyViewedPosts = Error{"too many viewed posts"}
+
+	ErrTopicTitleEmpty   = Error{"topic title is empty"}
+	ErrTopicTitleTooLong = Error{"topic title is too long"}
+	ErrTopicIconTooLong  = Error{"topic icon is too long"}
+	ErrTopicClosed       = Error{"topic is closed"}
+	ErrTopicNotClosed    = Error{"topic is not closed"}
+
+	ErrAdminsOnly      = Error{"only group admins can post"}
+	ErrSlowMode        = Error{"slow mode delay has not passed yet"}
+	ErrSlowModeInvalid = Error{"slow mode delay is invalid"}
 )
@@ -23,7 +23,7 @@ type FileMessage struct {
 }
 
 func NewFileMessage(chat Chatter, sender UserID, file *FileMeta, replyTo *Message) (*FileMessage, error) {
-	if err := chat.ValidateCanSend(sender); err != nil {
+	if err := validateContentPermission(chat, sender, MemberCanSendMedia); err != nil {
 		return nil, err
 	}
 
@@ -58,7 +58,7 @@ func (m *FileMessage) Forward(chat Chatter, sender UserID, destChat Chatter) (*F
 	if !chat.IsMember(sender) {
 		return nil, ErrUserNotMember
 	}
-	if err := destChat.ValidateCanSend(sender); err != nil {
+	if err := validateContentPermission(destChat, sender, MemberCanSendMedia); err != nil {
 		return nil, err
 	}
 
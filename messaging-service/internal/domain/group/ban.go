@@ -0,0 +1,89 @@
+package group
+
+import (
+	"slices"
+
+	"github.com/chakchat/chakchat-backend/messaging-service/internal/domain"
+)
+
+const MaxBanReasonLength = 500
+
+// Ban forbids the user to be added to the group or to join it by an invite link
+type Ban struct {
+	ChatID   domain.ChatID
+	UserID   domain.UserID
+	BannedBy domain.UserID
+	Reason   string
+	// nil means the ban never expires
+	ExpiresAt *domain.Timestamp
+
+	// It will be assigned automatically when it is stored in DB
+	CreatedAt domain.Timestamp
+}
+
+// Ban removes the user from the group if it is a member and forbids it to join again.
+// The user doesn't have to be a member, so it can be banned in advance.
+func (g *GroupChat) Ban(
+	sender domain.UserID, user domain.UserID, reason string, expiresAt *domain.Timestamp,
+) (*Ban, error) {
+	if sender == user {
+		return nil, domain.ErrBanSelf
+	}
+	if err := g.ValidateAdminPermission(sender, domain.PermissionRemoveMembers); err != nil {
+		return nil, err
+	}
+	if g.IsOwner(user) {
+		return nil, domain.ErrMemberIsOwner
+	}
+	if g.IsAdmin(user) && !g.IsOwner(sender) {
+		return nil, domain.ErrMemberIsAdmin
+	}
+
+	if len([]rune(reason)) > MaxBanReasonLength {
+		return nil, domain.ErrBanReasonTooLong
+	}
+	if expiresAt != nil && int64(*expiresAt) <= domain.TimeFunc().Unix() {
+		return nil, domain.ErrBanExpirationInvalid
+	}
+
+	if i := slices.Index(g.Members, user); i != -1 {
+		g.Members = slices.Delete(g.Members, i, i+1)
+	}
+	delete(g.Admins, user)
+
+	return &Ban{
+		ChatID:    g.ID,
+		UserID:    user,
+		BannedBy:  sender,
+		Reason:    reason,
+		ExpiresAt: expiresAt,
+	}, nil
+}
+
+func (g *GroupChat) Unban(sender domain.UserID, ban *Ban) error {
+	if err := g.ValidateAdminPermission(sender, domain.PermissionRemoveMembers); err != nil {
+		return err
+	}
+	if ban.ChatID != g.ID {
+		return domain.ErrBanNotFromChat
+	}
+	return nil
+}
+
+// ValidateCanSeeBans checks that the sender can see the ban list
+func (g *GroupChat) ValidateCanSeeBans(sender domain.UserID) error {
+	return g.ValidateAdminPermission(sender, domain.PermissionRemoveMembers)
+}
+
+// ValidateNotBanned checks that the user may become a member.
+// ban is the user's ban in this group or nil if there is none.
+func (g *GroupChat) ValidateNotBanned(ban *Ban) error {
+	if ban != nil && ban.ChatID == g.ID && ban.IsActive() {
+		return domain.ErrUserBanned
+	}
+	return nil
+}
+
+func (b *Ban) IsActive() bool {
+	return b.ExpiresAt == nil || domain.TimeFunc().Unix() < int64(*b.ExpiresAt)
+}
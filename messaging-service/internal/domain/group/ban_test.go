@@ -0,0 +1,82 @@
+package group
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/chakchat/chakchat-backend/messaging-service/internal/domain"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBans(t *testing.T) {
+	owner, _ := domain.NewUserID("3d7ca3ef-3b0d-4113-91c9-20b7bf874324")
+	admin, _ := domain.NewUserID("ce30ebc7-4058-4351-9a8f-66c71f987fdf")
+	member, _ := domain.NewUserID("fb048277-ad4f-4730-88eb-5e453c9ca5ce")
+	stranger, _ := domain.NewUserID("0b1a2e4e-52a9-4a8b-9c41-4b6d2b0d9f57")
+
+	newGroup := func(t *testing.T) *GroupChat {
+		g, err := NewGroupChat(owner, []domain.UserID{owner, admin, member}, "group")
+		require.NoError(t, err)
+		require.NoError(t, g.SetAdmin(owner, admin, domain.PermissionRemoveMembers))
+		return g
+	}
+
+	t.Run("Ban", func(t *testing.T) {
+		g := newGroup(t)
+
+		_, err := g.Ban(member, stranger, "", nil)
+		require.ErrorIs(t, err, domain.ErrSenderNotAdmin)
+		_, err = g.Ban(admin, admin, "", nil)
+		require.ErrorIs(t, err, domain.ErrBanSelf)
+		_, err = g.Ban(admin, owner, "", nil)
+		require.ErrorIs(t, err, domain.ErrMemberIsOwner)
+		_, err = g.Ban(owner, member, strings.Repeat("a", MaxBanReasonLength+1), nil)
+		require.ErrorIs(t, err, domain.ErrBanReasonTooLong)
+		passed := domain.Timestamp(time.Now().Add(-time.Minute).Unix())
+		_, err = g.Ban(owner, member, "", &passed)
+		require.ErrorIs(t, err, domain.ErrBanExpirationInvalid)
+
+		ban, err := g.Ban(admin, member, "spam", nil)
+		require.NoError(t, err)
+		require.False(t, g.IsMember(member))
+		require.Equal(t, admin, ban.BannedBy)
+		require.ErrorIs(t, g.ValidateNotBanned(ban), domain.ErrUserBanned)
+
+		// Users can be banned in advance
+		_, err = g.Ban(admin, stranger, "", nil)
+		require.NoError(t, err)
+
+		// Only the owner can ban admins
+		_, err = g.Ban(owner, admin, "", nil)
+		require.NoError(t, err)
+		require.False(t, g.IsAdmin(admin))
+	})
+
+	t.Run("Expired", func(t *testing.T) {
+		g := newGroup(t)
+		expiresAt := domain.Timestamp(time.Now().Add(time.Hour).Unix())
+		ban, err := g.Ban(owner, member, "", &expiresAt)
+		require.NoError(t, err)
+		require.ErrorIs(t, g.ValidateNotBanned(ban), domain.ErrUserBanned)
+
+		defer func(f func() time.Time) { domain.TimeFunc = f }(domain.TimeFunc)
+		domain.TimeFunc = func() time.Time {
+			return time.Now().Add(2 * time.Hour)
+		}
+
+		require.False(t, ban.IsActive())
+		require.NoError(t, g.ValidateNotBanned(ban))
+	})
+
+	t.Run("Unban", func(t *testing.T) {
+		g := newGroup(t)
+		ban, err := g.Ban(owner, stranger, "", nil)
+		require.NoError(t, err)
+
+		require.ErrorIs(t, g.Unban(member, ban), domain.ErrSenderNotAdmin)
+		require.ErrorIs(t, newGroup(t).Unban(owner, ban), domain.ErrBanNotFromChat)
+		require.NoError(t, g.Unban(admin, ban))
+		require.NoError(t, g.ValidateNotBanned(nil))
+	})
+}
@@ -2,26 +2,37 @@ package group
 
 import (
 	"slices"
+	"time"
 
 	"github.com/chakchat/chakchat-backend/messaging-service/internal/domain"
 )
 
 type GroupChat struct {
 	domain.Chat
-	Admin   domain.UserID
+	domain.GroupRoles
 	Members []domain.UserID
 
 	Name        string
 	Description string
 	GroupPhoto  domain.URL
+
+	// SlowMode is the minimal delay between messages of a member. Zero means slow mode is off
+	SlowMode time.Duration
+	// AdminsOnly means only admins can post
+	AdminsOnly bool
+	// LastSentAt is the time of the last message of each member.
+	// It is needed for slow mode only, so it may be empty when slow mode is off.
+	LastSentAt map[domain.UserID]domain.Timestamp
+	// HistoryHidden means members see only updates since they joined
+	HistoryHidden bool
 }
 
-func NewGroupChat(admin domain.UserID, members []domain.UserID, name string) (*GroupChat, error) {
+func NewGroupChat(owner domain.UserID, members []domain.UserID, name string) (*GroupChat, error) {
 	if err := domain.ValidateGroupInfo(name, ""); err != nil {
 		return nil, err
 	}
 
-	if !slices.Contains(members, admin) {
+	if !slices.Contains(members, owner) {
 		return nil, domain.ErrAdminNotMember
 	}
 
@@ -31,7 +42,7 @@ func NewGroupChat(admin domain.UserID, members []domain.UserID, name string) (*G
 		Chat: domain.Chat{
 			ID: domain.NewChatID(),
 		},
-		Admin:       admin,
+		GroupRoles:  domain.NewGroupRoles(owner),
 		Members:     normMembers,
 		Name:        name,
 		Description: "",
@@ -40,15 +51,15 @@ func NewGroupChat(admin domain.UserID, members []domain.UserID, name string) (*G
 }
 
 func (g *GroupChat) Delete(sender domain.UserID) error {
-	if sender != g.Admin {
-		return domain.ErrSenderNotAdmin
+	if !g.IsOwner(sender) {
+		return domain.ErrSenderNotOwner
 	}
 	return nil
 }
 
 func (g *GroupChat) UpdateInfo(sender domain.UserID, name, description string) error {
-	if sender != g.Admin {
-		return domain.ErrSenderNotAdmin
+	if err := g.ValidateAdminPermission(sender, domain.PermissionEditInfo); err != nil {
+		return err
 	}
 
 	if err := domain.ValidateGroupInfo(name, description); err != nil {
@@ -61,17 +72,28 @@ func (g *GroupChat) UpdateInfo(sender domain.UserID, name, description string) e
 }
 
 func (g *GroupChat) UpdatePhoto(sender domain.UserID, photo domain.URL) error {
-	if sender != g.Admin {
-		return domain.ErrSenderNotAdmin
+	if err := g.ValidateAdminPermission(sender, domain.PermissionEditInfo); err != nil {
+		return err
 	}
 
 	g.GroupPhoto = photo
 	return nil
 }
 
+// SetHistoryVisibility decides whether new members can see updates sent before they joined.
+// It applies to members who joined before it was set too.
+func (g *GroupChat) SetHistoryVisibility(sender domain.UserID, hidden bool) error {
+	if err := g.ValidateAdminPermission(sender, domain.PermissionEditInfo); err != nil {
+		return err
+	}
+
+	g.HistoryHidden = hidden
+	return nil
+}
+
 func (g *GroupChat) DeletePhoto(sender domain.UserID) error {
-	if sender != g.Admin {
-		return domain.ErrSenderNotAdmin
+	if err := g.ValidateAdminPermission(sender, domain.PermissionEditInfo); err != nil {
+		return err
 	}
 
 	if g.GroupPhoto == "" {
@@ -83,8 +105,8 @@ func (g *GroupChat) DeletePhoto(sender domain.UserID) error {
 }
 
 func (g *GroupChat) AddMember(sender domain.UserID, newMember domain.UserID) error {
-	if sender != g.Admin {
-		return domain.ErrSenderNotAdmin
+	if err := g.ValidateAdminPermission(sender, domain.PermissionAddMembers); err != nil {
+		return err
 	}
 
 	if g.IsMember(newMember) {
@@ -96,23 +118,56 @@ func (g *GroupChat) AddMember(sender domain.UserID, newMember domain.UserID) err
 }
 
 func (g *GroupChat) DeleteMember(sender domain.UserID, member domain.UserID) error {
-	if sender != g.Admin && sender != member {
-		return domain.ErrSenderNotAdmin
+	if sender == member {
+		return g.Leave(member)
+	}
+	if err := g.ValidateCanRemoveMember(sender, member); err != nil {
+		return err
 	}
 
-	if g.Admin == member {
-		return domain.ErrMemberIsAdmin
+	i := slices.Index(g.Members, member)
+	if i == -1 {
+		return domain.ErrUserNotMember
 	}
 
+	g.Members = slices.Delete(g.Members, i, i+1)
+	delete(g.Admins, member)
+	return nil
+}
+
+// Leave removes the member from the group.
+// If the owner leaves, ownership passes to the oldest remaining admin or member.
+func (g *GroupChat) Leave(member domain.UserID) error {
 	i := slices.Index(g.Members, member)
 	if i == -1 {
 		return domain.ErrUserNotMember
 	}
 
+	if g.IsOwner(member) {
+		if err := g.PassOwnership(g.Members); err != nil {
+			return err
+		}
+	}
+
 	g.Members = slices.Delete(g.Members, i, i+1)
+	delete(g.Admins, member)
 	return nil
 }
 
+func (g *GroupChat) TransferOwnership(sender domain.UserID, newOwner domain.UserID) error {
+	if !g.IsMember(newOwner) {
+		return domain.ErrUserNotMember
+	}
+	return g.GroupRoles.TransferOwnership(sender, newOwner)
+}
+
+func (g *GroupChat) SetAdmin(sender domain.UserID, member domain.UserID, perms domain.AdminPermissions) error {
+	if !g.IsMember(member) {
+		return domain.ErrUserNotMember
+	}
+	return g.GroupRoles.SetAdmin(sender, member, perms)
+}
+
 func (g *GroupChat) IsMember(user domain.UserID) bool {
 	return slices.Contains(g.Members, user)
 }
@@ -121,5 +176,30 @@ func (g *GroupChat) ValidateCanSend(sender domain.UserID) error {
 	if !g.IsMember(sender) {
 		return domain.ErrUserNotMember
 	}
-	return nil
+	return g.ValidateMemberPermission(sender, domain.MemberCanSendMessages)
+}
+
+// ValidateCanPost checks that the sender can create new content in the group now.
+// Admins-only posting and slow mode don't affect admins.
+func (g *GroupChat) ValidateCanPost(sender domain.UserID) error {
+	if !g.IsMember(sender) {
+		return domain.ErrUserNotMember
+	}
+	if g.IsAdmin(sender) {
+		return nil
+	}
+	if g.AdminsOnly {
+		return domain.ErrAdminsOnly
+	}
+	if err := g.ValidateCanSend(sender); err != nil {
+		return err
+	}
+	return g.validateSlowMode(sender)
+}
+
+func (g *GroupChat) ValidateCanPin(sender domain.UserID) error {
+	if err := g.ValidateCanSend(sender); err != nil {
+		return err
+	}
+	return g.ValidateAdminPermission(sender, domain.PermissionPinMessages)
 }
@@ -0,0 +1,151 @@
+package group
+
+import (
+	"testing"
+
+	"github.com/chakchat/chakchat-backend/messaging-service/internal/domain"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGroupRoles(t *testing.T) {
+	owner, _ := domain.NewUserID("3d7ca3ef-3b0d-4113-91c9-20b7bf874324")
+	admin, _ := domain.NewUserID("ce30ebc7-4058-4351-9a8f-66c71f987fdf")
+	member, _ := domain.NewUserID("fb048277-ad4f-4730-88eb-5e453c9ca5ce")
+	stranger, _ := domain.NewUserID("0b1a2e4e-52a9-4a8b-9c41-4b6d2b0d9f57")
+
+	newGroup := func(t *testing.T) *GroupChat {
+		g, err := NewGroupChat(owner, []domain.UserID{owner, admin, member}, "group")
+		require.NoError(t, err)
+		require.NoError(t, g.SetAdmin(owner, admin, domain.PermissionEditInfo|domain.PermissionManageAdmins))
+		return g
+	}
+
+	t.Run("SetAdmin", func(t *testing.T) {
+		g := newGroup(t)
+
+		require.ErrorIs(t, g.SetAdmin(owner, stranger, domain.PermissionEditInfo), domain.ErrUserNotMember)
+		require.ErrorIs(t, g.SetAdmin(member, admin, domain.PermissionEditInfo), domain.ErrSenderNotAdmin)
+		require.ErrorIs(t, g.SetAdmin(admin, owner, domain.PermissionEditInfo), domain.ErrMemberIsOwner)
+		require.ErrorIs(t, g.SetAdmin(owner, member, 0), domain.ErrInvalidPermissions)
+
+		// Admin can't grant permissions it doesn't have
+		err := g.SetAdmin(admin, member, domain.PermissionEditInfo|domain.PermissionPinMessages)
+		require.ErrorIs(t, err, domain.ErrPermissionDenied)
+
+		require.NoError(t, g.SetAdmin(admin, member, domain.PermissionEditInfo))
+		require.True(t, g.IsAdmin(member))
+		require.NoError(t, g.UpdateInfo(member, "new name", ""))
+		require.ErrorIs(t, g.AddMember(member, stranger), domain.ErrPermissionDenied)
+	})
+
+	t.Run("RemoveAdmin", func(t *testing.T) {
+		g := newGroup(t)
+
+		require.ErrorIs(t, g.RemoveAdmin(admin, owner), domain.ErrMemberIsOwner)
+		require.ErrorIs(t, g.RemoveAdmin(owner, member), domain.ErrMemberNotAdmin)
+
+		// Admin resigns
+		require.NoError(t, g.RemoveAdmin(admin, admin))
+		require.False(t, g.IsAdmin(admin))
+		require.ErrorIs(t, g.UpdateInfo(admin, "new name", ""), domain.ErrSenderNotAdmin)
+	})
+
+	t.Run("DeleteMember", func(t *testing.T) {
+		g := newGroup(t)
+
+		require.ErrorIs(t, g.DeleteMember(admin, owner), domain.ErrMemberIsOwner)
+		require.ErrorIs(t, g.DeleteMember(admin, member), domain.ErrPermissionDenied)
+		require.ErrorIs(t, g.DeleteMember(member, admin), domain.ErrSenderNotAdmin)
+
+		require.NoError(t, g.DeleteMember(owner, admin))
+		require.False(t, g.IsAdmin(admin))
+		require.NoError(t, g.DeleteMember(member, member))
+	})
+
+	t.Run("TransferOwnership", func(t *testing.T) {
+		g := newGroup(t)
+
+		require.ErrorIs(t, g.TransferOwnership(admin, member), domain.ErrSenderNotOwner)
+		require.ErrorIs(t, g.TransferOwnership(owner, stranger), domain.ErrUserNotMember)
+		require.ErrorIs(t, g.TransferOwnership(owner, owner), domain.ErrMemberIsOwner)
+
+		require.NoError(t, g.TransferOwnership(owner, member))
+		require.True(t, g.IsOwner(member))
+		require.Equal(t, domain.AllAdminPermissions, g.AdminPermissions(owner))
+		require.NotContains(t, g.Admins, member)
+		require.ErrorIs(t, g.Delete(owner), domain.ErrSenderNotOwner)
+	})
+
+	t.Run("OwnerLeaves", func(t *testing.T) {
+		g := newGroup(t)
+
+		// Admins are preferred over members
+		require.NoError(t, g.Leave(owner))
+		require.True(t, g.IsOwner(admin))
+		require.NotContains(t, g.Admins, admin)
+		require.False(t, g.IsMember(owner))
+
+		// Without admins the oldest member becomes the owner
+		require.NoError(t, g.AddMember(admin, stranger))
+		require.NoError(t, g.DeleteMember(admin, admin))
+		require.True(t, g.IsOwner(member))
+		require.Equal(t, []domain.UserID{member, stranger}, g.Members)
+
+		require.NoError(t, g.Leave(stranger))
+		require.ErrorIs(t, g.Leave(stranger), domain.ErrUserNotMember)
+		require.ErrorIs(t, g.Leave(member), domain.ErrOwnerLastMember)
+	})
+
+	t.Run("MemberPermissions", func(t *testing.T) {
+		g := newGroup(t)
+
+		require.ErrorIs(t, g.SetMemberPermissions(member, 0), domain.ErrSenderNotAdmin)
+		require.NoError(t, g.SetMemberPermissions(admin, domain.MemberCanSendMessages))
+
+		require.NoError(t, g.ValidateCanSend(member))
+		_, err := domain.NewFileMessage(g, member, &domain.FileMeta{}, nil)
+		require.ErrorIs(t, err, domain.ErrMemberRestricted)
+		_, err = domain.NewFileMessage(g, admin, &domain.FileMeta{}, nil)
+		require.NoError(t, err)
+
+		require.NoError(t, g.SetMemberPermissions(owner, 0))
+		require.ErrorIs(t, g.ValidateCanSend(member), domain.ErrMemberRestricted)
+		require.NoError(t, g.ValidateCanSend(admin))
+	})
+
+	t.Run("DeleteOthersMessages", func(t *testing.T) {
+		g := newGroup(t)
+
+		msg, err := domain.NewTextMessage(g, member, "hello", nil, nil)
+		require.NoError(t, err)
+		msg.UpdateID = 1
+
+		require.ErrorIs(t, msg.Delete(g, admin, domain.DeleteModeForAll), domain.ErrPermissionDenied)
+		require.NoError(t, msg.Delete(g, admin, domain.DeleteModeForSender))
+		require.NoError(t, msg.Delete(g, owner, domain.DeleteModeForAll))
+	})
+
+	t.Run("HistoryVisibility", func(t *testing.T) {
+		g := newGroup(t)
+
+		require.ErrorIs(t, g.SetHistoryVisibility(member, true), domain.ErrSenderNotAdmin)
+		require.NoError(t, g.SetHistoryVisibility(owner, true))
+		require.True(t, g.HistoryHidden)
+	})
+
+	t.Run("Permissions", func(t *testing.T) {
+		perms, err := domain.NewAdminPermissions([]string{"pin_messages", "edit_info"})
+		require.NoError(t, err)
+		require.Equal(t, domain.PermissionPinMessages|domain.PermissionEditInfo, perms)
+		require.Equal(t, []string{"edit_info", "pin_messages"}, perms.Names())
+
+		_, err = domain.NewAdminPermissions([]string{"fly"})
+		require.ErrorIs(t, err, domain.ErrInvalidPermissions)
+		_, err = domain.NewAdminPermissions(nil)
+		require.ErrorIs(t, err, domain.ErrInvalidPermissions)
+
+		memberPerms, err := domain.NewMemberPermissions(nil)
+		require.NoError(t, err)
+		require.Equal(t, domain.MemberPermissions(0), memberPerms)
+	})
+}
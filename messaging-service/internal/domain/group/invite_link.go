@@ -0,0 +1,193 @@
+package group
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+
+	"github.com/chakchat/chakchat-backend/messaging-service/internal/domain"
+)
+
+const (
+	inviteTokenBytes         = 16
+	MaxInviteLinkUsageLimit  = 100000
+	MaxInviteLinkExpireAhead = 365 * 24 * 60 * 60
+)
+
+// InviteLink lets users join the group without being added by an admin.
+// Revoked links are kept, so admins can still see and approve their join requests.
+type InviteLink struct {
+	Token     string
+	ChatID    domain.ChatID
+	CreatorID domain.UserID
+
+	ExpiresAt *domain.Timestamp
+	// 0 means unlimited
+	UsageLimit int
+	UsageCount int
+	// Users can only submit a join request if it is set
+	RequiresApproval bool
+	Revoked          bool
+
+	// It will be assigned automatically when it is stored in DB
+	CreatedAt domain.Timestamp
+}
+
+// JoinRequest is submitted via an invite link that requires approval
+type JoinRequest struct {
+	ChatID domain.ChatID
+	UserID domain.UserID
+	Token  string
+
+	// It will be assigned automatically when it is stored in DB
+	CreatedAt domain.Timestamp
+}
+
+func (g *GroupChat) NewInviteLink(
+	sender domain.UserID, expiresAt *domain.Timestamp, usageLimit int, requiresApproval bool,
+) (*InviteLink, error) {
+	if err := g.ValidateAdminPermission(sender, domain.PermissionAddMembers); err != nil {
+		return nil, err
+	}
+
+	if usageLimit < 0 || usageLimit > MaxInviteLinkUsageLimit {
+		return nil, domain.ErrInviteLinkInvalid
+	}
+	// Join requests don't use the link until they are approved, so a limit is meaningless
+	if requiresApproval && usageLimit != 0 {
+		return nil, domain.ErrInviteLinkInvalid
+	}
+	if expiresAt != nil {
+		now := domain.TimeFunc().Unix()
+		if int64(*expiresAt) <= now || int64(*expiresAt) > now+MaxInviteLinkExpireAhead {
+			return nil, domain.ErrInviteLinkInvalid
+		}
+	}
+
+	token, err := newInviteToken()
+	if err != nil {
+		return nil, err
+	}
+
+	return &InviteLink{
+		Token:            token,
+		ChatID:           g.ID,
+		CreatorID:        sender,
+		ExpiresAt:        expiresAt,
+		UsageLimit:       usageLimit,
+		RequiresApproval: requiresApproval,
+	}, nil
+}
+
+func (g *GroupChat) RevokeInviteLink(sender domain.UserID, link *InviteLink) error {
+	if err := g.ValidateAdminPermission(sender, domain.PermissionAddMembers); err != nil {
+		return err
+	}
+	if link.ChatID != g.ID {
+		return domain.ErrInviteLinkNotFromChat
+	}
+	if link.Revoked {
+		return domain.ErrInviteLinkRevoked
+	}
+
+	link.Revoked = true
+	return nil
+}
+
+// JoinByLink adds the user to the group and counts the link usage
+func (g *GroupChat) JoinByLink(user domain.UserID, link *InviteLink) error {
+	if err := g.validateInviteLink(user, link); err != nil {
+		return err
+	}
+	if link.RequiresApproval {
+		return domain.ErrJoinRequiresApproval
+	}
+
+	g.Members = append(g.Members, user)
+	link.UsageCount++
+	return nil
+}
+
+func (g *GroupChat) RequestToJoin(user domain.UserID, link *InviteLink) (*JoinRequest, error) {
+	if err := g.validateInviteLink(user, link); err != nil {
+		return nil, err
+	}
+	if !link.RequiresApproval {
+		return nil, domain.ErrJoinApprovalNotRequired
+	}
+
+	return &JoinRequest{
+		ChatID: g.ID,
+		UserID: user,
+		Token:  link.Token,
+	}, nil
+}
+
+// ApproveJoinRequest adds the user to the group.
+// The request should be deleted after it, even if the link is revoked or expired by then.
+func (g *GroupChat) ApproveJoinRequest(sender domain.UserID, req *JoinRequest) error {
+	if err := g.ValidateAdminPermission(sender, domain.PermissionAddMembers); err != nil {
+		return err
+	}
+	if req.ChatID != g.ID {
+		return domain.ErrJoinRequestNotFromChat
+	}
+	if g.IsMember(req.UserID) {
+		return domain.ErrUserAlreadyMember
+	}
+
+	g.Members = append(g.Members, req.UserID)
+	return nil
+}
+
+func (g *GroupChat) DeclineJoinRequest(sender domain.UserID, req *JoinRequest) error {
+	if err := g.ValidateAdminPermission(sender, domain.PermissionAddMembers); err != nil {
+		return err
+	}
+	if req.ChatID != g.ID {
+		return domain.ErrJoinRequestNotFromChat
+	}
+	return nil
+}
+
+// ValidateCanManageInvites checks that the sender can see invite links and join requests
+func (g *GroupChat) ValidateCanManageInvites(sender domain.UserID) error {
+	return g.ValidateAdminPermission(sender, domain.PermissionAddMembers)
+}
+
+func (l *InviteLink) IsExpired() bool {
+	return l.ExpiresAt != nil && domain.TimeFunc().Unix() >= int64(*l.ExpiresAt)
+}
+
+func (l *InviteLink) Validate() error {
+	if l.Revoked {
+		return domain.ErrInviteLinkRevoked
+	}
+	if l.IsExpired() {
+		return domain.ErrInviteLinkExpired
+	}
+	if l.UsageLimit != 0 && l.UsageCount >= l.UsageLimit {
+		return domain.ErrInviteLinkUsageLimitReached
+	}
+	return nil
+}
+
+func (g *GroupChat) validateInviteLink(user domain.UserID, link *InviteLink) error {
+	if link.ChatID != g.ID {
+		return domain.ErrInviteLinkNotFromChat
+	}
+	if err := link.Validate(); err != nil {
+		return err
+	}
+	if g.IsMember(user) {
+		return domain.ErrUserAlreadyMember
+	}
+	return nil
+}
+
+func newInviteToken() (string, error) {
+	b := make([]byte, inviteTokenBytes)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
@@ -0,0 +1,108 @@
+package group
+
+import (
+	"testing"
+	"time"
+
+	"github.com/chakchat/chakchat-backend/messaging-service/internal/domain"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInviteLinks(t *testing.T) {
+	owner, _ := domain.NewUserID("3d7ca3ef-3b0d-4113-91c9-20b7bf874324")
+	member, _ := domain.NewUserID("fb048277-ad4f-4730-88eb-5e453c9ca5ce")
+	user1, _ := domain.NewUserID("0b1a2e4e-52a9-4a8b-9c41-4b6d2b0d9f57")
+	user2, _ := domain.NewUserID("7e0cb0a4-5a8e-4d2a-a3b3-1f6a0d2b9c11")
+
+	newGroup := func(t *testing.T) *GroupChat {
+		g, err := NewGroupChat(owner, []domain.UserID{owner, member}, "group")
+		require.NoError(t, err)
+		return g
+	}
+
+	t.Run("Create", func(t *testing.T) {
+		g := newGroup(t)
+
+		_, err := g.NewInviteLink(member, nil, 0, false)
+		require.ErrorIs(t, err, domain.ErrSenderNotAdmin)
+		_, err = g.NewInviteLink(owner, nil, -1, false)
+		require.ErrorIs(t, err, domain.ErrInviteLinkInvalid)
+		_, err = g.NewInviteLink(owner, nil, 10, true)
+		require.ErrorIs(t, err, domain.ErrInviteLinkInvalid)
+		passed := domain.Timestamp(time.Now().Add(-time.Minute).Unix())
+		_, err = g.NewInviteLink(owner, &passed, 0, false)
+		require.ErrorIs(t, err, domain.ErrInviteLinkInvalid)
+
+		link1, err := g.NewInviteLink(owner, nil, 0, false)
+		require.NoError(t, err)
+		link2, err := g.NewInviteLink(owner, nil, 0, false)
+		require.NoError(t, err)
+		require.NotEmpty(t, link1.Token)
+		require.NotEqual(t, link1.Token, link2.Token)
+	})
+
+	t.Run("Join", func(t *testing.T) {
+		g := newGroup(t)
+		link, err := g.NewInviteLink(owner, nil, 1, false)
+		require.NoError(t, err)
+
+		require.ErrorIs(t, g.JoinByLink(member, link), domain.ErrUserAlreadyMember)
+		require.NoError(t, g.JoinByLink(user1, link))
+		require.True(t, g.IsMember(user1))
+		require.Equal(t, 1, link.UsageCount)
+
+		require.ErrorIs(t, g.JoinByLink(user2, link), domain.ErrInviteLinkUsageLimitReached)
+
+		other := newGroup(t)
+		require.ErrorIs(t, other.JoinByLink(user2, link), domain.ErrInviteLinkNotFromChat)
+	})
+
+	t.Run("Expired", func(t *testing.T) {
+		g := newGroup(t)
+		expiresAt := domain.Timestamp(time.Now().Add(time.Hour).Unix())
+		link, err := g.NewInviteLink(owner, &expiresAt, 0, false)
+		require.NoError(t, err)
+
+		defer func(f func() time.Time) { domain.TimeFunc = f }(domain.TimeFunc)
+		domain.TimeFunc = func() time.Time {
+			return time.Now().Add(2 * time.Hour)
+		}
+
+		require.ErrorIs(t, g.JoinByLink(user1, link), domain.ErrInviteLinkExpired)
+	})
+
+	t.Run("Revoke", func(t *testing.T) {
+		g := newGroup(t)
+		link, err := g.NewInviteLink(owner, nil, 0, false)
+		require.NoError(t, err)
+
+		require.ErrorIs(t, g.RevokeInviteLink(member, link), domain.ErrSenderNotAdmin)
+		require.NoError(t, g.RevokeInviteLink(owner, link))
+		require.ErrorIs(t, g.RevokeInviteLink(owner, link), domain.ErrInviteLinkRevoked)
+		require.ErrorIs(t, g.JoinByLink(user1, link), domain.ErrInviteLinkRevoked)
+	})
+
+	t.Run("JoinRequest", func(t *testing.T) {
+		g := newGroup(t)
+		link, err := g.NewInviteLink(owner, nil, 0, true)
+		require.NoError(t, err)
+
+		require.ErrorIs(t, g.JoinByLink(user1, link), domain.ErrJoinRequiresApproval)
+
+		req, err := g.RequestToJoin(user1, link)
+		require.NoError(t, err)
+		require.False(t, g.IsMember(user1))
+
+		require.ErrorIs(t, g.ApproveJoinRequest(member, req), domain.ErrSenderNotAdmin)
+		require.NoError(t, g.RevokeInviteLink(owner, link))
+		// Revoking the link doesn't cancel pending requests
+		require.NoError(t, g.ApproveJoinRequest(owner, req))
+		require.True(t, g.IsMember(user1))
+		require.Zero(t, link.UsageCount)
+
+		plain, err := g.NewInviteLink(owner, nil, 0, false)
+		require.NoError(t, err)
+		_, err = g.RequestToJoin(user2, plain)
+		require.ErrorIs(t, err, domain.ErrJoinApprovalNotRequired)
+	})
+}
@@ -0,0 +1,44 @@
+package group
+
+import (
+	"time"
+
+	"github.com/chakchat/chakchat-backend/messaging-service/internal/domain"
+)
+
+const MaxSlowMode = time.Hour
+
+// SetPostingMode sets slow mode and admins-only posting of the group.
+// Zero slowMode turns slow mode off.
+func (g *GroupChat) SetPostingMode(sender domain.UserID, slowMode time.Duration, adminsOnly bool) error {
+	if err := g.ValidateAdminPermission(sender, domain.PermissionEditInfo); err != nil {
+		return err
+	}
+	if slowMode < 0 || slowMode > MaxSlowMode || slowMode%time.Second != 0 {
+		return domain.ErrSlowModeInvalid
+	}
+
+	g.SlowMode = slowMode
+	g.AdminsOnly = adminsOnly
+	return nil
+}
+
+// validateSlowMode checks that the delay since the last message of the sender has passed
+func (g *GroupChat) validateSlowMode(sender domain.UserID) error {
+	if g.SlowMode == 0 {
+		return nil
+	}
+	last, ok := g.LastSentAt[sender]
+	if !ok {
+		return nil
+	}
+
+	retryAt := last.Time().Add(g.SlowMode)
+	if domain.TimeFunc().Before(retryAt) {
+		return domain.RetryError{
+			Err:     domain.ErrSlowMode,
+			RetryAt: domain.Timestamp(retryAt.Unix()),
+		}
+	}
+	return nil
+}
@@ -0,0 +1,121 @@
+package group
+
+import (
+	"testing"
+	"time"
+
+	"github.com/chakchat/chakchat-backend/messaging-service/internal/domain"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPostingMode(t *testing.T) {
+	owner, _ := domain.NewUserID("3d7ca3ef-3b0d-4113-91c9-20b7bf874324")
+	admin, _ := domain.NewUserID("ce30ebc7-4058-4351-9a8f-66c71f987fdf")
+	member, _ := domain.NewUserID("fb048277-ad4f-4730-88eb-5e453c9ca5ce")
+
+	newGroup := func(t *testing.T) *GroupChat {
+		g, err := NewGroupChat(owner, []domain.UserID{owner, admin, member}, "group")
+		require.NoError(t, err)
+		require.NoError(t, g.SetAdmin(owner, admin, domain.PermissionEditInfo))
+		return g
+	}
+
+	t.Run("SetPostingMode", func(t *testing.T) {
+		g := newGroup(t)
+
+		require.ErrorIs(t, g.SetPostingMode(member, time.Minute, false), domain.ErrSenderNotAdmin)
+		require.ErrorIs(t, g.SetPostingMode(admin, -time.Second, false), domain.ErrSlowModeInvalid)
+		require.ErrorIs(t, g.SetPostingMode(admin, MaxSlowMode+time.Second, false), domain.ErrSlowModeInvalid)
+		require.ErrorIs(t, g.SetPostingMode(admin, 1500*time.Millisecond, false), domain.ErrSlowModeInvalid)
+
+		require.NoError(t, g.SetPostingMode(admin, time.Minute, true))
+		require.Equal(t, time.Minute, g.SlowMode)
+		require.True(t, g.AdminsOnly)
+	})
+
+	t.Run("AdminsOnly", func(t *testing.T) {
+		g := newGroup(t)
+		require.NoError(t, g.SetPostingMode(owner, 0, true))
+
+		require.ErrorIs(t, g.ValidateCanPost(member), domain.ErrAdminsOnly)
+		_, err := domain.NewTextMessage(g, member, "hello", nil, nil)
+		require.ErrorIs(t, err, domain.ErrAdminsOnly)
+		require.NoError(t, g.ValidateCanPost(admin))
+
+		require.NoError(t, g.SetPostingMode(owner, 0, false))
+		require.NoError(t, g.ValidateCanPost(member))
+	})
+
+	t.Run("SlowMode", func(t *testing.T) {
+		g := newGroup(t)
+		require.NoError(t, g.SetPostingMode(owner, time.Minute, false))
+
+		// The member hasn't sent anything yet
+		require.NoError(t, g.ValidateCanPost(member))
+
+		sentAt := time.Now().Add(-10 * time.Second)
+		g.LastSentAt = map[domain.UserID]domain.Timestamp{
+			member: domain.Timestamp(sentAt.Unix()),
+			admin:  domain.Timestamp(sentAt.Unix()),
+		}
+
+		err := g.ValidateCanPost(member)
+		require.ErrorIs(t, err, domain.ErrSlowMode)
+		var retryErr domain.RetryError
+		require.ErrorAs(t, err, &retryErr)
+		require.Equal(t, domain.Timestamp(sentAt.Add(time.Minute).Unix()), retryErr.RetryAt)
+
+		// Admins are not affected
+		require.NoError(t, g.ValidateCanPost(admin))
+
+		defer func(f func() time.Time) { domain.TimeFunc = f }(domain.TimeFunc)
+		domain.TimeFunc = func() time.Time {
+			return time.Now().Add(time.Minute)
+		}
+		require.NoError(t, g.ValidateCanPost(member))
+	})
+
+	t.Run("EditAndDeleteInSlowMode", func(t *testing.T) {
+		g := newGroup(t)
+
+		msg, err := domain.NewTextMessage(g, member, "hello", nil, nil)
+		require.NoError(t, err)
+		msg.UpdateID = 1
+
+		require.NoError(t, g.SetPostingMode(owner, time.Minute, false))
+		g.LastSentAt = map[domain.UserID]domain.Timestamp{
+			member: domain.Timestamp(time.Now().Unix()),
+		}
+
+		_, err = domain.NewTextMessage(g, member, "again", nil, nil)
+		require.ErrorIs(t, err, domain.ErrSlowMode)
+
+		require.NoError(t, msg.Edit(g, member, "hello!", nil))
+		_, err = domain.NewScheduledTextMessage(g, member, "later", nil, nil, domain.Timestamp(time.Now().Add(time.Hour).Unix()))
+		require.NoError(t, err)
+		require.NoError(t, msg.Delete(g, member, domain.DeleteModeForAll))
+	})
+
+	t.Run("OwnContentInAdminsOnly", func(t *testing.T) {
+		g := newGroup(t)
+
+		msg, err := domain.NewTextMessage(g, member, "hello", nil, nil)
+		require.NoError(t, err)
+		msg.UpdateID = 1
+		reaction, err := domain.NewReaction(g, member, &msg.Message, "like")
+		require.NoError(t, err)
+		reaction.UpdateID = 2
+		poll, err := domain.NewPoll(g, admin, "question?", []string{"yes", "no"}, domain.PollSettings{}, nil)
+		require.NoError(t, err)
+		poll.UpdateID = 3
+
+		require.NoError(t, g.SetPostingMode(owner, 0, true))
+
+		_, err = domain.NewReaction(g, member, &msg.Message, "heart")
+		require.ErrorIs(t, err, domain.ErrAdminsOnly)
+
+		require.NoError(t, poll.Vote(g, member, []int{0}))
+		require.NoError(t, reaction.Delete(g, member))
+		require.NoError(t, msg.Delete(g, member, domain.DeleteModeForAll))
+	})
+}
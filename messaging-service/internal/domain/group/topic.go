@@ -0,0 +1,158 @@
+package group
+
+import (
+	"github.com/chakchat/chakchat-backend/messaging-service/internal/domain"
+)
+
+const (
+	MaxTopicTitleLength = 128
+	// Icon is an emoji, some of them take many bytes
+	MaxTopicIconLength = 32
+)
+
+// Topic is a named sub-conversation of a group.
+//
+// Each topic has its own update stream with an independent update_id sequence,
+// so updates of the topic are stored with the topic ID as their chat ID.
+// Members, roles and restrictions are the ones of the group.
+type Topic struct {
+	domain.Chat
+	Group *GroupChat
+
+	Title     string
+	Icon      string
+	CreatorID domain.UserID
+	Closed    bool
+}
+
+// NewTopic creates a topic. Any member who can post can create it.
+func (g *GroupChat) NewTopic(sender domain.UserID, title, icon string) (*Topic, error) {
+	if err := g.ValidateCanPost(sender); err != nil {
+		return nil, err
+	}
+
+	if err := validateTopicInfo(title, icon); err != nil {
+		return nil, err
+	}
+
+	return &Topic{
+		Chat: domain.Chat{
+			ID: domain.NewChatID(),
+		},
+		Group:     g,
+		Title:     title,
+		Icon:      icon,
+		CreatorID: sender,
+	}, nil
+}
+
+func (t *Topic) IsMember(user domain.UserID) bool {
+	return t.Group.IsMember(user)
+}
+
+// ValidateCanSend checks that the user can send to the topic.
+// Only the ones who can manage the topic send to a closed topic.
+func (t *Topic) ValidateCanSend(user domain.UserID) error {
+	if err := t.Group.ValidateCanSend(user); err != nil {
+		return err
+	}
+	return t.validateNotClosed(user)
+}
+
+// ValidateCanPost checks that the user can create new content in the topic now
+func (t *Topic) ValidateCanPost(user domain.UserID) error {
+	if err := t.Group.ValidateCanPost(user); err != nil {
+		return err
+	}
+	return t.validateNotClosed(user)
+}
+
+func (t *Topic) validateNotClosed(user domain.UserID) error {
+	if t.Closed && t.validateCanManage(user) != nil {
+		return domain.ErrTopicClosed
+	}
+	return nil
+}
+
+func (t *Topic) ValidateAdminPermission(user domain.UserID, perm domain.AdminPermissions) error {
+	return t.Group.ValidateAdminPermission(user, perm)
+}
+
+func (t *Topic) ValidateMemberPermission(user domain.UserID, perm domain.MemberPermissions) error {
+	return t.Group.ValidateMemberPermission(user, perm)
+}
+
+func (t *Topic) IsAdmin(user domain.UserID) bool {
+	return t.Group.IsAdmin(user)
+}
+
+func (t *Topic) UpdateInfo(sender domain.UserID, title, icon string) error {
+	if err := t.validateCanManage(sender); err != nil {
+		return err
+	}
+
+	if err := validateTopicInfo(title, icon); err != nil {
+		return err
+	}
+
+	t.Title = title
+	t.Icon = icon
+	return nil
+}
+
+func (t *Topic) Close(sender domain.UserID) error {
+	if err := t.validateCanManage(sender); err != nil {
+		return err
+	}
+	if t.Closed {
+		return domain.ErrTopicClosed
+	}
+
+	t.Closed = true
+	return nil
+}
+
+func (t *Topic) Reopen(sender domain.UserID) error {
+	if err := t.validateCanManage(sender); err != nil {
+		return err
+	}
+	if !t.Closed {
+		return domain.ErrTopicNotClosed
+	}
+
+	t.Closed = false
+	return nil
+}
+
+// Delete validates that the sender can delete the topic with all its updates.
+// Unlike other actions it is not allowed to the creator.
+func (t *Topic) Delete(sender domain.UserID) error {
+	if !t.IsMember(sender) {
+		return domain.ErrUserNotMember
+	}
+	return t.ValidateAdminPermission(sender, domain.PermissionManageTopics)
+}
+
+// validateCanManage checks that the user is the topic creator or an admin who manages topics
+func (t *Topic) validateCanManage(user domain.UserID) error {
+	if !t.IsMember(user) {
+		return domain.ErrUserNotMember
+	}
+	if user == t.CreatorID {
+		return nil
+	}
+	return t.ValidateAdminPermission(user, domain.PermissionManageTopics)
+}
+
+func validateTopicInfo(title, icon string) error {
+	if title == "" {
+		return domain.ErrTopicTitleEmpty
+	}
+	if len(title) > MaxTopicTitleLength {
+		return domain.ErrTopicTitleTooLong
+	}
+	if len(icon) > MaxTopicIconLength {
+		return domain.ErrTopicIconTooLong
+	}
+	return nil
+}
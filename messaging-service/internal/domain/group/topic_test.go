@@ -0,0 +1,90 @@
+package group
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/chakchat/chakchat-backend/messaging-service/internal/domain"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTopics(t *testing.T) {
+	owner, _ := domain.NewUserID("3d7ca3ef-3b0d-4113-91c9-20b7bf874324")
+	admin, _ := domain.NewUserID("ce30ebc7-4058-4351-9a8f-66c71f987fdf")
+	member, _ := domain.NewUserID("fb048277-ad4f-4730-88eb-5e453c9ca5ce")
+	stranger, _ := domain.NewUserID("0b1a2e4e-52a9-4a8b-9c41-4b6d2b0d9f57")
+
+	newGroup := func(t *testing.T) *GroupChat {
+		g, err := NewGroupChat(owner, []domain.UserID{owner, admin, member}, "group")
+		require.NoError(t, err)
+		require.NoError(t, g.SetAdmin(owner, admin, domain.PermissionManageTopics))
+		return g
+	}
+
+	t.Run("Create", func(t *testing.T) {
+		g := newGroup(t)
+
+		_, err := g.NewTopic(stranger, "topic", "")
+		require.ErrorIs(t, err, domain.ErrUserNotMember)
+		_, err = g.NewTopic(member, "", "")
+		require.ErrorIs(t, err, domain.ErrTopicTitleEmpty)
+		_, err = g.NewTopic(member, strings.Repeat("a", MaxTopicTitleLength+1), "")
+		require.ErrorIs(t, err, domain.ErrTopicTitleTooLong)
+		_, err = g.NewTopic(member, "topic", strings.Repeat("a", MaxTopicIconLength+1))
+		require.ErrorIs(t, err, domain.ErrTopicIconTooLong)
+
+		g.MemberPermissions = 0
+		_, err = g.NewTopic(member, "topic", "")
+		require.ErrorIs(t, err, domain.ErrMemberRestricted)
+		g.MemberPermissions = domain.DefaultMemberPermissions
+
+		topic, err := g.NewTopic(member, "topic", "🔥")
+		require.NoError(t, err)
+		require.NotEqual(t, g.ID, topic.ID)
+		require.Equal(t, member, topic.CreatorID)
+		require.False(t, topic.Closed)
+	})
+
+	t.Run("Close", func(t *testing.T) {
+		g := newGroup(t)
+		topic, err := g.NewTopic(member, "topic", "")
+		require.NoError(t, err)
+
+		require.ErrorIs(t, topic.Reopen(member), domain.ErrTopicNotClosed)
+		require.NoError(t, topic.Close(member))
+		require.ErrorIs(t, topic.Close(admin), domain.ErrTopicClosed)
+
+		// Only the ones who can manage the topic send to a closed topic
+		require.NoError(t, topic.ValidateCanSend(member))
+		require.NoError(t, topic.ValidateCanSend(admin))
+		require.NoError(t, topic.ValidateCanSend(owner))
+
+		other, err := g.NewTopic(owner, "other", "")
+		require.NoError(t, err)
+		require.ErrorIs(t, other.Close(member), domain.ErrSenderNotAdmin)
+		require.NoError(t, other.Close(admin))
+		require.ErrorIs(t, other.ValidateCanSend(member), domain.ErrTopicClosed)
+
+		require.NoError(t, other.Reopen(owner))
+		require.NoError(t, other.ValidateCanSend(member))
+	})
+
+	t.Run("Manage", func(t *testing.T) {
+		g := newGroup(t)
+		topic, err := g.NewTopic(owner, "topic", "")
+		require.NoError(t, err)
+
+		require.ErrorIs(t, topic.UpdateInfo(member, "new", ""), domain.ErrSenderNotAdmin)
+		require.ErrorIs(t, topic.UpdateInfo(stranger, "new", ""), domain.ErrUserNotMember)
+		require.ErrorIs(t, topic.UpdateInfo(admin, "", ""), domain.ErrTopicTitleEmpty)
+		require.NoError(t, topic.UpdateInfo(admin, "new", "🔥"))
+		require.Equal(t, "new", topic.Title)
+		require.Equal(t, "🔥", topic.Icon)
+
+		// The creator can't delete the topic without the permission
+		own, err := g.NewTopic(member, "own", "")
+		require.NoError(t, err)
+		require.ErrorIs(t, own.Delete(member), domain.ErrSenderNotAdmin)
+		require.NoError(t, own.Delete(admin))
+	})
+}
@@ -0,0 +1,299 @@
+package domain
+
+import "slices"
+
+// AdminPermissions is a set of group management rights.
+// The owner has all of them.
+type AdminPermissions uint16
+
+const (
+	PermissionEditInfo AdminPermissions = 1 << iota
+	PermissionAddMembers
+	PermissionRemoveMembers
+	PermissionPinMessages
+	PermissionDeleteMessages
+	PermissionManageAdmins
+	PermissionManageTopics
+
+	AllAdminPermissions = PermissionEditInfo | PermissionAddMembers | PermissionRemoveMembers |
+		PermissionPinMessages | PermissionDeleteMessages | PermissionManageAdmins | PermissionManageTopics
+)
+
+var adminPermissionNames = map[AdminPermissions]string{
+	PermissionEditInfo:       "edit_info",
+	PermissionAddMembers:     "add_members",
+	PermissionRemoveMembers:  "remove_members",
+	PermissionPinMessages:    "pin_messages",
+	PermissionDeleteMessages: "delete_messages",
+	PermissionManageAdmins:   "manage_admins",
+	PermissionManageTopics:   "manage_topics",
+}
+
+// MemberPermissions restricts what ordinary members can send.
+// Admins are never restricted.
+type MemberPermissions uint16
+
+const (
+	MemberCanSendMessages MemberPermissions = 1 << iota
+	MemberCanSendMedia
+	MemberCanSendPolls
+
+	AllMemberPermissions     = MemberCanSendMessages | MemberCanSendMedia | MemberCanSendPolls
+	DefaultMemberPermissions = AllMemberPermissions
+)
+
+var memberPermissionNames = map[MemberPermissions]string{
+	MemberCanSendMessages: "send_messages",
+	MemberCanSendMedia:    "send_media",
+	MemberCanSendPolls:    "send_polls",
+}
+
+func NewAdminPermissions(names []string) (AdminPermissions, error) {
+	perms, err := parsePermissions(adminPermissionNames, names)
+	if err != nil {
+		return 0, err
+	}
+	if perms == 0 {
+		return 0, ErrInvalidPermissions
+	}
+	return perms, nil
+}
+
+func (p AdminPermissions) Has(perm AdminPermissions) bool {
+	return p&perm == perm
+}
+
+func (p AdminPermissions) Names() []string {
+	return permissionNames(adminPermissionNames, p)
+}
+
+func NewMemberPermissions(names []string) (MemberPermissions, error) {
+	return parsePermissions(memberPermissionNames, names)
+}
+
+func (p MemberPermissions) Has(perm MemberPermissions) bool {
+	return p&perm == perm
+}
+
+func (p MemberPermissions) Names() []string {
+	return permissionNames(memberPermissionNames, p)
+}
+
+func parsePermissions[P ~uint16](known map[P]string, names []string) (P, error) {
+	var perms P
+	for _, name := range names {
+		found := false
+		for perm, permName := range known {
+			if permName == name {
+				perms |= perm
+				found = true
+				break
+			}
+		}
+		if !found {
+			return 0, ErrInvalidPermissions
+		}
+	}
+	return perms, nil
+}
+
+func permissionNames[P ~uint16](known map[P]string, perms P) []string {
+	names := make([]string, 0, len(known))
+	for perm, name := range known {
+		if perms&perm != 0 {
+			names = append(names, name)
+		}
+	}
+	slices.Sort(names)
+	return names
+}
+
+// RoleChatter is a chat where members have roles and restrictions
+type RoleChatter interface {
+	ValidateAdminPermission(UserID, AdminPermissions) error
+	ValidateMemberPermission(UserID, MemberPermissions) error
+}
+
+// GroupRoles holds the owner, admins and default member restrictions of a group.
+// It doesn't know group members, so membership is validated by the group itself.
+type GroupRoles struct {
+	Owner             UserID
+	Admins            map[UserID]AdminPermissions
+	MemberPermissions MemberPermissions
+}
+
+func NewGroupRoles(owner UserID) GroupRoles {
+	return GroupRoles{
+		Owner:             owner,
+		Admins:            make(map[UserID]AdminPermissions),
+		MemberPermissions: DefaultMemberPermissions,
+	}
+}
+
+func (r *GroupRoles) IsOwner(user UserID) bool {
+	return user == r.Owner
+}
+
+// IsAdmin reports whether the user is the owner or an admin
+func (r *GroupRoles) IsAdmin(user UserID) bool {
+	_, ok := r.Admins[user]
+	return ok || r.IsOwner(user)
+}
+
+func (r *GroupRoles) AdminPermissions(user UserID) AdminPermissions {
+	if r.IsOwner(user) {
+		return AllAdminPermissions
+	}
+	return r.Admins[user]
+}
+
+func (r *GroupRoles) ValidateAdminPermission(user UserID, perm AdminPermissions) error {
+	if !r.IsAdmin(user) {
+		return ErrSenderNotAdmin
+	}
+	if !r.AdminPermissions(user).Has(perm) {
+		return ErrPermissionDenied
+	}
+	return nil
+}
+
+func (r *GroupRoles) ValidateMemberPermission(user UserID, perm MemberPermissions) error {
+	if r.IsAdmin(user) || r.MemberPermissions.Has(perm) {
+		return nil
+	}
+	return ErrMemberRestricted
+}
+
+// SetAdmin makes the user an admin or changes their permissions.
+// The user must be a group member, it is validated by the group.
+// Admins can't grant permissions they don't have and can't change admins with more permissions.
+func (r *GroupRoles) SetAdmin(sender, user UserID, perms AdminPermissions) error {
+	if err := r.ValidateAdminPermission(sender, PermissionManageAdmins); err != nil {
+		return err
+	}
+	if r.IsOwner(user) {
+		return ErrMemberIsOwner
+	}
+	if perms == 0 || perms&^AllAdminPermissions != 0 {
+		return ErrInvalidPermissions
+	}
+
+	senderPerms := r.AdminPermissions(sender)
+	if perms&^senderPerms != 0 || r.Admins[user]&^senderPerms != 0 {
+		return ErrPermissionDenied
+	}
+
+	if r.Admins == nil {
+		r.Admins = make(map[UserID]AdminPermissions)
+	}
+	r.Admins[user] = perms
+	return nil
+}
+
+// RemoveAdmin makes the admin an ordinary member. Admins can resign by themselves.
+func (r *GroupRoles) RemoveAdmin(sender, user UserID) error {
+	if r.IsOwner(user) {
+		return ErrMemberIsOwner
+	}
+	if _, ok := r.Admins[user]; !ok {
+		return ErrMemberNotAdmin
+	}
+
+	if sender != user {
+		if err := r.ValidateAdminPermission(sender, PermissionManageAdmins); err != nil {
+			return err
+		}
+		if r.Admins[user]&^r.AdminPermissions(sender) != 0 {
+			return ErrPermissionDenied
+		}
+	}
+
+	delete(r.Admins, user)
+	return nil
+}
+
+func (r *GroupRoles) SetMemberPermissions(sender UserID, perms MemberPermissions) error {
+	if err := r.ValidateAdminPermission(sender, PermissionEditInfo); err != nil {
+		return err
+	}
+	if perms&^AllMemberPermissions != 0 {
+		return ErrInvalidPermissions
+	}
+
+	r.MemberPermissions = perms
+	return nil
+}
+
+// ValidateCanRemoveMember checks that the sender can remove the member from the group.
+// Any member except the owner can leave the group.
+func (r *GroupRoles) ValidateCanRemoveMember(sender, member UserID) error {
+	if r.IsOwner(member) {
+		return ErrMemberIsOwner
+	}
+	if sender == member {
+		return nil
+	}
+	if err := r.ValidateAdminPermission(sender, PermissionRemoveMembers); err != nil {
+		return err
+	}
+	if r.IsAdmin(member) && !r.IsOwner(sender) {
+		return ErrMemberIsAdmin
+	}
+	return nil
+}
+
+// TransferOwnership makes the user the owner. The previous owner stays an admin with all permissions.
+// The user must be a group member, it is validated by the group.
+func (r *GroupRoles) TransferOwnership(sender, newOwner UserID) error {
+	if !r.IsOwner(sender) {
+		return ErrSenderNotOwner
+	}
+	if r.IsOwner(newOwner) {
+		return ErrMemberIsOwner
+	}
+
+	if r.Admins == nil {
+		r.Admins = make(map[UserID]AdminPermissions)
+	}
+	delete(r.Admins, newOwner)
+	r.Admins[sender] = AllAdminPermissions
+	r.Owner = newOwner
+	return nil
+}
+
+// PassOwnership passes ownership of the leaving owner to the oldest admin
+// or, if there are no admins, to the oldest member.
+// Members must be ordered by join time.
+func (r *GroupRoles) PassOwnership(members []UserID) error {
+	successor, found := UserID{}, false
+	for _, member := range members {
+		if member == r.Owner {
+			continue
+		}
+		if r.IsAdmin(member) {
+			successor, found = member, true
+			break
+		}
+		if !found {
+			successor, found = member, true
+		}
+	}
+	if !found {
+		return ErrOwnerLastMember
+	}
+
+	delete(r.Admins, successor)
+	r.Owner = successor
+	return nil
+}
+
+// validateContentPermission checks that the sender may send such content to the chat
+func validateContentPermission(chat Chatter, sender UserID, perm MemberPermissions) error {
+	if err := validateCanPost(chat, sender); err != nil {
+		return err
+	}
+	if rc, ok := chat.(RoleChatter); ok {
+		return rc.ValidateMemberPermission(sender, perm)
+	}
+	return nil
+}
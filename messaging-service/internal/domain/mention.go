@@ -0,0 +1,100 @@
+package domain
+
+import (
+	"slices"
+	"unicode/utf8"
+)
+
+const (
+	MaxMentionsCount = 50
+)
+
+// Mention refers to a part of message text.
+// Offset and length are measured in runes.
+type Mention struct {
+	Offset int
+	Length int
+	// Nil if everyone is mentioned
+	UserID *UserID
+}
+
+func (m Mention) IsEveryone() bool {
+	return m.UserID == nil
+}
+
+// MentionedUsername returns the username written in the text part, e.g. "john" for "@john"
+func MentionedUsername(text string, offset, length int) (string, error) {
+	runes := []rune(text)
+	if offset < 0 || length <= 1 || offset+length > len(runes) {
+		return "", ErrMentionInvalid
+	}
+	if runes[offset] != '@' {
+		return "", ErrMentionInvalid
+	}
+	return string(runes[offset+1 : offset+length]), nil
+}
+
+// SetMentions validates mentions against the text and chat members.
+// Mention entities of the text are derived from them, so they must not overlap other entities.
+func (m *TextMessage) SetMentions(chat Chatter, mentions []Mention) error {
+	if len(mentions) > MaxMentionsCount {
+		return ErrTooManyMentions
+	}
+
+	sorted := slices.Clone(mentions)
+	slices.SortFunc(sorted, func(a, b Mention) int {
+		return a.Offset - b.Offset
+	})
+
+	runesCount := utf8.RuneCountInString(m.Text)
+	end := 0
+	for _, mention := range sorted {
+		if mention.Offset < end || mention.Length <= 0 || mention.Offset+mention.Length > runesCount {
+			return ErrMentionInvalid
+		}
+		end = mention.Offset + mention.Length
+
+		if !mention.IsEveryone() && !chat.IsMember(*mention.UserID) {
+			return ErrMentionNotMember
+		}
+	}
+
+	entities := withoutMentionEntities(m.Entities)
+	for _, mention := range sorted {
+		entities = append(entities, TextEntity{
+			Type:   TextEntityMention,
+			Offset: mention.Offset,
+			Length: mention.Length,
+		})
+	}
+	entities = sortEntities(entities)
+	for i := 1; i < len(entities); i++ {
+		if entities[i].Offset < entities[i-1].Offset+entities[i-1].Length {
+			return ErrTextEntitiesOverlap
+		}
+	}
+
+	m.Mentions = sorted
+	m.Entities = entities
+	return nil
+}
+
+// MentionedMembers returns members who are mentioned in the message except the sender
+func (m *TextMessage) MentionedMembers(members []UserID) []UserID {
+	var mentioned []UserID
+	for _, member := range members {
+		if member != m.SenderID && m.IsMentioned(member) {
+			mentioned = append(mentioned, member)
+		}
+	}
+	return mentioned
+}
+
+func (m *TextMessage) IsMentioned(user UserID) bool {
+	for _, mention := range m.Mentions {
+		if mention.IsEveryone() || *mention.UserID == user {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,121 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMentionedUsername(t *testing.T) {
+	username, err := MentionedUsername("привет @john!", 7, 5)
+	require.NoError(t, err)
+	require.Equal(t, "john", username)
+
+	_, err = MentionedUsername("hi john", 3, 4)
+	require.ErrorIs(t, err, ErrMentionInvalid)
+
+	_, err = MentionedUsername("hi @john", 3, 10)
+	require.ErrorIs(t, err, ErrMentionInvalid)
+
+	_, err = MentionedUsername("hi @", 3, 1)
+	require.ErrorIs(t, err, ErrMentionInvalid)
+}
+
+func TestSetMentions(t *testing.T) {
+	user1, _ := NewUserID("3d7ca3ef-3b0d-4113-91c9-20b7bf874324")
+	user2, _ := NewUserID("ce30ebc7-4058-4351-9a8f-66c71f987fdf")
+	user3, _ := NewUserID("fb048277-ad4f-4730-88eb-5e453c9ca5ce")
+	chat := &FakeChat{
+		Chat: Chat{
+			ID: NewChatID(),
+		},
+		Members: [2]UserID{user1, user2},
+	}
+
+	t.Run("Success", func(t *testing.T) {
+		msg, err := NewTextMessage(chat, user1, "@user2 and @all look", nil, nil)
+		require.NoError(t, err)
+
+		err = msg.SetMentions(chat, []Mention{
+			{Offset: 11, Length: 4},
+			{Offset: 0, Length: 6, UserID: &user2},
+		})
+		require.NoError(t, err)
+		require.Len(t, msg.Mentions, 2)
+		require.Equal(t, 0, msg.Mentions[0].Offset)
+		require.True(t, msg.Mentions[1].IsEveryone())
+
+		require.True(t, msg.IsMentioned(user2))
+		require.Equal(t, []UserID{user2}, msg.MentionedMembers(chat.Members[:]))
+
+		require.Equal(t, []TextEntity{
+			{Type: TextEntityMention, Offset: 0, Length: 6},
+			{Type: TextEntityMention, Offset: 11, Length: 4},
+		}, msg.Entities)
+	})
+
+	t.Run("Entities", func(t *testing.T) {
+		msg, err := NewTextMessage(chat, user1, "@user2 look", []TextEntity{
+			{Type: TextEntityBold, Offset: 7, Length: 4},
+		}, nil)
+		require.NoError(t, err)
+
+		require.NoError(t, msg.SetMentions(chat, []Mention{{Offset: 0, Length: 6, UserID: &user2}}))
+		require.Equal(t, []TextEntity{
+			{Type: TextEntityMention, Offset: 0, Length: 6},
+			{Type: TextEntityBold, Offset: 7, Length: 4},
+		}, msg.Entities)
+
+		err = msg.SetMentions(chat, []Mention{{Offset: 5, Length: 6, UserID: &user2}})
+		require.ErrorIs(t, err, ErrTextEntitiesOverlap)
+
+		forwarded, err := msg.Forward(chat, user2, chat)
+		require.NoError(t, err)
+		require.Empty(t, forwarded.Mentions)
+		require.Equal(t, []TextEntity{{Type: TextEntityBold, Offset: 7, Length: 4}}, forwarded.Entities)
+	})
+
+	t.Run("OnlyUser", func(t *testing.T) {
+		msg, err := NewTextMessage(chat, user2, "@user1 look", nil, nil)
+		require.NoError(t, err)
+
+		err = msg.SetMentions(chat, []Mention{{Offset: 0, Length: 6, UserID: &user1}})
+		require.NoError(t, err)
+		require.True(t, msg.IsMentioned(user1))
+		require.False(t, msg.IsMentioned(user2))
+	})
+
+	t.Run("Invalid", func(t *testing.T) {
+		msg, err := NewTextMessage(chat, user1, "@user2 look", nil, nil)
+		require.NoError(t, err)
+
+		err = msg.SetMentions(chat, []Mention{{Offset: 5, Length: 10, UserID: &user2}})
+		require.ErrorIs(t, err, ErrMentionInvalid)
+
+		err = msg.SetMentions(chat, []Mention{{Offset: 0, Length: 0, UserID: &user2}})
+		require.ErrorIs(t, err, ErrMentionInvalid)
+
+		err = msg.SetMentions(chat, []Mention{
+			{Offset: 0, Length: 6, UserID: &user2},
+			{Offset: 3, Length: 4},
+		})
+		require.ErrorIs(t, err, ErrMentionInvalid)
+	})
+
+	t.Run("NotMember", func(t *testing.T) {
+		msg, err := NewTextMessage(chat, user1, "@user3 look", nil, nil)
+		require.NoError(t, err)
+
+		err = msg.SetMentions(chat, []Mention{{Offset: 0, Length: 6, UserID: &user3}})
+		require.ErrorIs(t, err, ErrMentionNotMember)
+	})
+
+	t.Run("ClearedOnEdit", func(t *testing.T) {
+		msg, err := NewTextMessage(chat, user1, "@user2 look", nil, nil)
+		require.NoError(t, err)
+		require.NoError(t, msg.SetMentions(chat, []Mention{{Offset: 0, Length: 6, UserID: &user2}}))
+
+		require.NoError(t, msg.Edit(chat, user1, "never mind", nil))
+		require.Empty(t, msg.Mentions)
+	})
+}
@@ -20,6 +20,14 @@ func (m *Message) Delete(chat Chatter, sender UserID, mode DeleteMode) error {
 		return ErrUpdateDeleted
 	}
 
+	if mode == DeleteModeForAll && m.SenderID != sender {
+		if rc, ok := chat.(RoleChatter); ok {
+			if err := rc.ValidateAdminPermission(sender, PermissionDeleteMessages); err != nil {
+				return err
+			}
+		}
+	}
+
 	m.AddDeletion(sender, mode)
 	return nil
 }
@@ -31,5 +39,8 @@ func validateCanReply(chat Chatter, sender UserID, replyTo *Message) error {
 	if chat.ChatID() != replyTo.ChatID {
 		return ErrUpdateNotFromChat
 	}
+	if replyTo.ThreadRootID != nil {
+		return ErrReplyOutsideThread
+	}
 	return nil
 }
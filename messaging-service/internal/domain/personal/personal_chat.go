@@ -81,3 +81,8 @@ func (c *PersonalChat) ValidateCanSend(sender domain.UserID) error {
 	}
 	return nil
 }
+
+// Both members can pin messages in a personal chat
+func (c *PersonalChat) ValidateCanPin(sender domain.UserID) error {
+	return c.ValidateCanSend(sender)
+}
@@ -0,0 +1,80 @@
+package domain
+
+// Pinner is a chat where messages can be pinned
+type Pinner interface {
+	Chatter
+	ValidateCanPin(UserID) error
+}
+
+// Pin is an update that pins or unpins a message.
+// The latest pin update of the message tells if it is pinned now.
+type Pin struct {
+	Update
+
+	MessageID UpdateID
+	// False if the message is unpinned by this update
+	Pinned bool
+}
+
+// NewPin pins the message. pinned tells if the message is already pinned.
+func NewPin(chat Pinner, sender UserID, m *Message, pinned bool) (*Pin, error) {
+	if err := validatePin(chat, sender, m); err != nil {
+		return nil, err
+	}
+
+	if m.DeletedFor(sender) {
+		return nil, ErrUpdateDeleted
+	}
+
+	if pinned {
+		return nil, ErrMessageAlreadyPinned
+	}
+
+	return &Pin{
+		Update: Update{
+			ChatID:   chat.ChatID(),
+			SenderID: sender,
+		},
+		MessageID: m.UpdateID,
+		Pinned:    true,
+	}, nil
+}
+
+// NewUnpin unpins the message. pinned tells if the message is pinned now.
+func NewUnpin(chat Pinner, sender UserID, m *Message, pinned bool) (*Pin, error) {
+	if err := validatePin(chat, sender, m); err != nil {
+		return nil, err
+	}
+
+	if !pinned {
+		return nil, ErrMessageNotPinned
+	}
+
+	return &Pin{
+		Update: Update{
+			ChatID:   chat.ChatID(),
+			SenderID: sender,
+		},
+		MessageID: m.UpdateID,
+		Pinned:    false,
+	}, nil
+}
+
+func (p *Pin) UpdateType() string {
+	if p.Pinned {
+		return UpdateTypeMessagePinned
+	}
+	return UpdateTypeMessageUnpinned
+}
+
+func validatePin(chat Pinner, sender UserID, m *Message) error {
+	if err := chat.ValidateCanPin(sender); err != nil {
+		return err
+	}
+
+	if chat.ChatID() != m.ChatID {
+		return ErrUpdateNotFromChat
+	}
+
+	return nil
+}
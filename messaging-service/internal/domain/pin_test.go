@@ -0,0 +1,101 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type FakeGroupChat struct {
+	FakeChat
+	Admin UserID
+}
+
+func (c *FakeGroupChat) ValidateCanPin(user UserID) error {
+	if err := c.ValidateCanSend(user); err != nil {
+		return err
+	}
+	if user != c.Admin {
+		return ErrSenderNotAdmin
+	}
+	return nil
+}
+
+func (c *FakeGroupChat) IsAdmin(user UserID) bool {
+	return user == c.Admin
+}
+
+func TestPin(t *testing.T) {
+	user1, _ := NewUserID("3d7ca3ef-3b0d-4113-91c9-20b7bf874324")
+	user2, _ := NewUserID("ce30ebc7-4058-4351-9a8f-66c71f987fdf")
+	user3, _ := NewUserID("fb048277-ad4f-4730-88eb-5e453c9ca5ce")
+	chat := &FakeGroupChat{
+		FakeChat: FakeChat{
+			Chat: Chat{
+				ID: NewChatID(),
+			},
+			Members: [2]UserID{user1, user2},
+		},
+		Admin: user1,
+	}
+
+	msg := Message{
+		Update: Update{
+			UpdateID: 12,
+			ChatID:   chat.ID,
+			SenderID: user2,
+		},
+	}
+
+	t.Run("Pin", func(t *testing.T) {
+		pin, err := NewPin(chat, user1, &msg, false)
+		require.NoError(t, err)
+		require.Equal(t, chat.ID, pin.ChatID)
+		require.Equal(t, user1, pin.SenderID)
+		require.Equal(t, msg.UpdateID, pin.MessageID)
+		require.Equal(t, UpdateTypeMessagePinned, pin.UpdateType())
+	})
+
+	t.Run("Unpin", func(t *testing.T) {
+		unpin, err := NewUnpin(chat, user1, &msg, true)
+		require.NoError(t, err)
+		require.Equal(t, msg.UpdateID, unpin.MessageID)
+		require.Equal(t, UpdateTypeMessageUnpinned, unpin.UpdateType())
+	})
+
+	t.Run("NotAllowed", func(t *testing.T) {
+		_, err := NewPin(chat, user2, &msg, false)
+		require.ErrorIs(t, err, ErrSenderNotAdmin)
+
+		_, err = NewPin(chat, user3, &msg, false)
+		require.ErrorIs(t, err, ErrUserNotMember)
+
+		_, err = NewUnpin(chat, user2, &msg, true)
+		require.ErrorIs(t, err, ErrSenderNotAdmin)
+	})
+
+	t.Run("AlreadyPinned", func(t *testing.T) {
+		_, err := NewPin(chat, user1, &msg, true)
+		require.ErrorIs(t, err, ErrMessageAlreadyPinned)
+
+		_, err = NewUnpin(chat, user1, &msg, false)
+		require.ErrorIs(t, err, ErrMessageNotPinned)
+	})
+
+	t.Run("OtherChat", func(t *testing.T) {
+		other := msg
+		other.ChatID = NewChatID()
+		_, err := NewPin(chat, user1, &other, false)
+		require.ErrorIs(t, err, ErrUpdateNotFromChat)
+	})
+
+	t.Run("Deleted", func(t *testing.T) {
+		deleted := msg
+		deleted.AddDeletion(user2, DeleteModeForAll)
+		_, err := NewPin(chat, user1, &deleted, false)
+		require.ErrorIs(t, err, ErrUpdateDeleted)
+
+		_, err = NewUnpin(chat, user1, &deleted, true)
+		require.NoError(t, err)
+	})
+}
@@ -0,0 +1,236 @@
+package domain
+
+import (
+	"slices"
+	"unicode/utf8"
+)
+
+const (
+	MinPollOptions            = 2
+	MaxPollOptions            = 10
+	MaxPollQuestionRunesCount = 300
+	MaxPollOptionRunesCount   = 100
+)
+
+// AdminChatter is a chat that has admins
+type AdminChatter interface {
+	Chatter
+	IsAdmin(UserID) bool
+}
+
+type PollOption struct {
+	Text   string
+	Voters []UserID
+}
+
+type PollSettings struct {
+	MultipleChoice bool
+	// Voters of anonymous polls are never revealed
+	Anonymous bool
+	// Optional time when the poll is closed automatically
+	ClosesAt *Timestamp
+}
+
+type Poll struct {
+	Message
+	PollSettings
+
+	Question string
+	Options  []PollOption
+	// True if the poll is closed manually
+	Closed bool
+}
+
+func NewPoll(
+	chat Chatter,
+	sender UserID,
+	question string,
+	options []string,
+	settings PollSettings,
+	replyTo *Message,
+) (*Poll, error) {
+	if err := validateContentPermission(chat, sender, MemberCanSendPolls); err != nil {
+		return nil, err
+	}
+
+	if replyTo != nil {
+		if err := validateCanReply(chat, sender, replyTo); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := validatePoll(question, options, settings); err != nil {
+		return nil, err
+	}
+
+	var replyToID *UpdateID
+	if replyTo != nil {
+		replyToID = &replyTo.UpdateID
+	}
+
+	pollOptions := make([]PollOption, len(options))
+	for i, text := range options {
+		pollOptions[i] = PollOption{Text: text}
+	}
+
+	return &Poll{
+		Message: Message{
+			Update: Update{
+				ChatID:   chat.ChatID(),
+				SenderID: sender,
+			},
+			ReplyTo: replyToID,
+		},
+		PollSettings: settings,
+		Question:     question,
+		Options:      pollOptions,
+	}, nil
+}
+
+// IsClosed tells if the poll is closed manually or its close time has come
+func (p *Poll) IsClosed() bool {
+	if p.Closed {
+		return true
+	}
+	return p.ClosesAt != nil && TimeFunc().Unix() >= int64(*p.ClosesAt)
+}
+
+// Vote replaces previous votes of the voter.
+// options are indexes of chosen poll options.
+func (p *Poll) Vote(chat Chatter, voter UserID, options []int) error {
+	if err := p.validateCanVote(chat, voter); err != nil {
+		return err
+	}
+
+	if len(options) == 0 {
+		return ErrPollNoOptionChosen
+	}
+
+	if !p.MultipleChoice && len(options) > 1 {
+		return ErrPollSingleChoice
+	}
+
+	for i, option := range options {
+		if option < 0 || option >= len(p.Options) || slices.Contains(options[:i], option) {
+			return ErrPollInvalidOption
+		}
+	}
+
+	p.removeVotes(voter)
+	for _, option := range options {
+		p.Options[option].Voters = append(p.Options[option].Voters, voter)
+	}
+
+	return nil
+}
+
+func (p *Poll) RetractVote(chat Chatter, voter UserID) error {
+	if err := p.validateCanVote(chat, voter); err != nil {
+		return err
+	}
+
+	if !p.HasVoted(voter) {
+		return ErrPollNotVoted
+	}
+
+	p.removeVotes(voter)
+	return nil
+}
+
+// Close closes the poll. Only the poll author or an admin can close it.
+func (p *Poll) Close(chat AdminChatter, sender UserID) error {
+	if err := chat.ValidateCanSend(sender); err != nil {
+		return err
+	}
+
+	if chat.ChatID() != p.ChatID {
+		return ErrUpdateNotFromChat
+	}
+
+	if p.DeletedFor(sender) {
+		return ErrUpdateDeleted
+	}
+
+	if p.SenderID != sender && !chat.IsAdmin(sender) {
+		return ErrPollCloseForbidden
+	}
+
+	if p.IsClosed() {
+		return ErrPollClosed
+	}
+
+	p.Closed = true
+	return nil
+}
+
+func (p *Poll) HasVoted(user UserID) bool {
+	for _, option := range p.Options {
+		if slices.Contains(option.Voters, user) {
+			return true
+		}
+	}
+	return false
+}
+
+// VotersCount returns the number of users who voted for at least one option
+func (p *Poll) VotersCount() int {
+	voters := make(map[UserID]struct{})
+	for _, option := range p.Options {
+		for _, voter := range option.Voters {
+			voters[voter] = struct{}{}
+		}
+	}
+	return len(voters)
+}
+
+func (p *Poll) validateCanVote(chat Chatter, voter UserID) error {
+	if err := chat.ValidateCanSend(voter); err != nil {
+		return err
+	}
+
+	if chat.ChatID() != p.ChatID {
+		return ErrUpdateNotFromChat
+	}
+
+	if p.DeletedFor(voter) {
+		return ErrUpdateDeleted
+	}
+
+	if p.IsClosed() {
+		return ErrPollClosed
+	}
+
+	return nil
+}
+
+func (p *Poll) removeVotes(voter UserID) {
+	for i := range p.Options {
+		p.Options[i].Voters = slices.DeleteFunc(p.Options[i].Voters, func(user UserID) bool {
+			return user == voter
+		})
+	}
+}
+
+func validatePoll(question string, options []string, settings PollSettings) error {
+	if question == "" {
+		return ErrPollQuestionEmpty
+	}
+	if utf8.RuneCountInString(question) > MaxPollQuestionRunesCount {
+		return ErrPollQuestionTooLong
+	}
+
+	if len(options) < MinPollOptions || len(options) > MaxPollOptions {
+		return ErrPollOptionsCount
+	}
+	for _, option := range options {
+		if option == "" || utf8.RuneCountInString(option) > MaxPollOptionRunesCount {
+			return ErrPollInvalidOption
+		}
+	}
+
+	if settings.ClosesAt != nil && int64(*settings.ClosesAt) <= TimeFunc().Unix() {
+		return ErrPollCloseTimePassed
+	}
+
+	return nil
+}
@@ -0,0 +1,139 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPoll(t *testing.T) {
+	user1, _ := NewUserID("3d7ca3ef-3b0d-4113-91c9-20b7bf874324")
+	user2, _ := NewUserID("ce30ebc7-4058-4351-9a8f-66c71f987fdf")
+	user3, _ := NewUserID("fb048277-ad4f-4730-88eb-5e453c9ca5ce")
+	chat := &FakeGroupChat{
+		FakeChat: FakeChat{
+			Chat: Chat{
+				ID: NewChatID(),
+			},
+			Members: [2]UserID{user1, user2},
+		},
+		Admin: user1,
+	}
+	options := []string{"yes", "no", "maybe"}
+
+	t.Run("New", func(t *testing.T) {
+		poll, err := NewPoll(chat, user2, "question?", options, PollSettings{}, nil)
+		require.NoError(t, err)
+		require.Equal(t, chat.ID, poll.ChatID)
+		require.Equal(t, user2, poll.SenderID)
+		require.Len(t, poll.Options, len(options))
+		require.False(t, poll.IsClosed())
+
+		_, err = NewPoll(chat, user3, "question?", options, PollSettings{}, nil)
+		require.ErrorIs(t, err, ErrUserNotMember)
+	})
+
+	t.Run("Invalid", func(t *testing.T) {
+		_, err := NewPoll(chat, user1, "", options, PollSettings{}, nil)
+		require.ErrorIs(t, err, ErrPollQuestionEmpty)
+
+		_, err = NewPoll(chat, user1, "question?", []string{"yes"}, PollSettings{}, nil)
+		require.ErrorIs(t, err, ErrPollOptionsCount)
+
+		_, err = NewPoll(chat, user1, "question?", make([]string, MaxPollOptions+1), PollSettings{}, nil)
+		require.ErrorIs(t, err, ErrPollOptionsCount)
+
+		_, err = NewPoll(chat, user1, "question?", []string{"yes", ""}, PollSettings{}, nil)
+		require.ErrorIs(t, err, ErrPollInvalidOption)
+
+		past := Timestamp(time.Now().Add(-time.Minute).Unix())
+		_, err = NewPoll(chat, user1, "question?", options, PollSettings{ClosesAt: &past}, nil)
+		require.ErrorIs(t, err, ErrPollCloseTimePassed)
+	})
+
+	t.Run("SingleChoice", func(t *testing.T) {
+		poll, err := NewPoll(chat, user1, "question?", options, PollSettings{}, nil)
+		require.NoError(t, err)
+
+		err = poll.Vote(chat, user2, []int{0, 1})
+		require.ErrorIs(t, err, ErrPollSingleChoice)
+
+		err = poll.Vote(chat, user2, []int{3})
+		require.ErrorIs(t, err, ErrPollInvalidOption)
+
+		err = poll.Vote(chat, user2, nil)
+		require.ErrorIs(t, err, ErrPollNoOptionChosen)
+
+		err = poll.Vote(chat, user3, []int{0})
+		require.ErrorIs(t, err, ErrUserNotMember)
+
+		require.NoError(t, poll.Vote(chat, user2, []int{0}))
+		require.NoError(t, poll.Vote(chat, user2, []int{1}))
+		require.Empty(t, poll.Options[0].Voters)
+		require.Equal(t, []UserID{user2}, poll.Options[1].Voters)
+		require.Equal(t, 1, poll.VotersCount())
+	})
+
+	t.Run("MultipleChoice", func(t *testing.T) {
+		poll, err := NewPoll(chat, user1, "question?", options, PollSettings{MultipleChoice: true}, nil)
+		require.NoError(t, err)
+
+		err = poll.Vote(chat, user2, []int{0, 0})
+		require.ErrorIs(t, err, ErrPollInvalidOption)
+
+		require.NoError(t, poll.Vote(chat, user2, []int{0, 2}))
+		require.NoError(t, poll.Vote(chat, user1, []int{2}))
+		require.Equal(t, []UserID{user2, user1}, poll.Options[2].Voters)
+		require.Equal(t, 2, poll.VotersCount())
+	})
+
+	t.Run("RetractVote", func(t *testing.T) {
+		poll, err := NewPoll(chat, user1, "question?", options, PollSettings{}, nil)
+		require.NoError(t, err)
+
+		err = poll.RetractVote(chat, user2)
+		require.ErrorIs(t, err, ErrPollNotVoted)
+
+		require.NoError(t, poll.Vote(chat, user2, []int{0}))
+		require.NoError(t, poll.RetractVote(chat, user2))
+		require.False(t, poll.HasVoted(user2))
+	})
+
+	t.Run("Close", func(t *testing.T) {
+		poll, err := NewPoll(chat, user2, "question?", options, PollSettings{}, nil)
+		require.NoError(t, err)
+		require.NoError(t, poll.Close(chat, user2))
+		require.True(t, poll.IsClosed())
+
+		err = poll.Vote(chat, user1, []int{0})
+		require.ErrorIs(t, err, ErrPollClosed)
+
+		err = poll.Close(chat, user1)
+		require.ErrorIs(t, err, ErrPollClosed)
+
+		poll, err = NewPoll(chat, user1, "question?", options, PollSettings{}, nil)
+		require.NoError(t, err)
+		err = poll.Close(chat, user2)
+		require.ErrorIs(t, err, ErrPollCloseForbidden)
+
+		poll, err = NewPoll(chat, user2, "question?", options, PollSettings{}, nil)
+		require.NoError(t, err)
+		require.NoError(t, poll.Close(chat, user1))
+	})
+
+	t.Run("CloseTime", func(t *testing.T) {
+		closesAt := Timestamp(time.Now().Add(time.Hour).Unix())
+		poll, err := NewPoll(chat, user1, "question?", options, PollSettings{ClosesAt: &closesAt}, nil)
+		require.NoError(t, err)
+
+		defer func(f func() time.Time) { TimeFunc = f }(TimeFunc)
+		TimeFunc = func() time.Time {
+			return time.Now().Add(2 * time.Hour)
+		}
+
+		require.True(t, poll.IsClosed())
+		err = poll.Vote(chat, user2, []int{0})
+		require.ErrorIs(t, err, ErrPollClosed)
+	})
+}
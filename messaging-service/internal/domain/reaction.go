@@ -25,7 +25,7 @@ func NewReaction(
 	m *Message,
 	reaction ReactionType,
 ) (*Reaction, error) {
-	if err := chat.ValidateCanSend(sender); err != nil {
+	if err := validateCanPost(chat, sender); err != nil {
 		return nil, err
 	}
 
@@ -43,8 +43,9 @@ func NewReaction(
 
 	return &Reaction{
 		Update: Update{
-			ChatID:   chat.ChatID(),
-			SenderID: sender,
+			ChatID:       chat.ChatID(),
+			SenderID:     sender,
+			ThreadRootID: m.ThreadRootID,
 		},
 		Type:      reaction,
 		MessageID: m.UpdateID,
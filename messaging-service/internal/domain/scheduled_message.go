@@ -0,0 +1,143 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	MaxScheduleAhead = 365 * 24 * time.Hour
+)
+
+type ScheduledMessageID int64
+
+// ScheduledMessage is a message that is sent on behalf of the sender when SendAt comes.
+// It isn't an update, so it is visible only to its sender until it is sent.
+// All send rules (membership, blocking, reply validation) are checked again at send time.
+type ScheduledMessage struct {
+	// It will be assigned automatically when it is stored in DB
+	ID       ScheduledMessageID
+	ChatID   ChatID
+	SenderID UserID
+
+	// Only one of Text and FileID is set
+	Text     string
+	Entities []TextEntity
+	FileID   *uuid.UUID
+	ReplyTo  *UpdateID
+
+	SendAt    Timestamp
+	CreatedAt Timestamp
+}
+
+func NewScheduledTextMessage(
+	chat Chatter, sender UserID, text string, entities []TextEntity, replyTo *UpdateID, sendAt Timestamp,
+) (*ScheduledMessage, error) {
+	if err := validateText(text, entities); err != nil {
+		return nil, err
+	}
+
+	msg, err := newScheduledMessage(chat, sender, text, nil, replyTo, sendAt)
+	if err != nil {
+		return nil, err
+	}
+	msg.Entities = sortEntities(entities)
+	return msg, nil
+}
+
+func NewScheduledFileMessage(
+	chat Chatter, sender UserID, fileID uuid.UUID, replyTo *UpdateID, sendAt Timestamp,
+) (*ScheduledMessage, error) {
+	return newScheduledMessage(chat, sender, "", &fileID, replyTo, sendAt)
+}
+
+func (m *ScheduledMessage) IsFile() bool {
+	return m.FileID != nil
+}
+
+// Edit changes text, its entities and send time of the message.
+// Text of file messages can't be changed, so it and the entities should be empty for them.
+func (m *ScheduledMessage) Edit(
+	chat Chatter, sender UserID, text string, entities []TextEntity, sendAt Timestamp,
+) error {
+	if err := m.validateChangeable(chat, sender); err != nil {
+		return err
+	}
+
+	if m.IsFile() {
+		if text != "" || len(entities) != 0 {
+			return ErrScheduledFileText
+		}
+	} else if err := validateText(text, entities); err != nil {
+		return err
+	}
+
+	if err := validateSendAt(sendAt); err != nil {
+		return err
+	}
+
+	m.Text = text
+	m.Entities = sortEntities(entities)
+	m.SendAt = sendAt
+	return nil
+}
+
+func (m *ScheduledMessage) Cancel(chat Chatter, sender UserID) error {
+	return m.validateChangeable(chat, sender)
+}
+
+// IsDue tells if the message should be sent now
+func (m *ScheduledMessage) IsDue() bool {
+	return TimeFunc().Unix() >= int64(m.SendAt)
+}
+
+// validateChangeable checks that the sender can edit or cancel the message.
+// A due message may be already taken by the dispatcher, so it can't be changed anymore.
+func (m *ScheduledMessage) validateChangeable(chat Chatter, sender UserID) error {
+	if chat.ChatID() != m.ChatID {
+		return ErrUpdateNotFromChat
+	}
+
+	if m.SenderID != sender {
+		return ErrUserNotSender
+	}
+
+	if m.IsDue() {
+		return ErrScheduledSending
+	}
+
+	return nil
+}
+
+func newScheduledMessage(
+	chat Chatter, sender UserID, text string, fileID *uuid.UUID, replyTo *UpdateID, sendAt Timestamp,
+) (*ScheduledMessage, error) {
+	if err := chat.ValidateCanSend(sender); err != nil {
+		return nil, err
+	}
+
+	if err := validateSendAt(sendAt); err != nil {
+		return nil, err
+	}
+
+	return &ScheduledMessage{
+		ChatID:   chat.ChatID(),
+		SenderID: sender,
+		Text:     text,
+		FileID:   fileID,
+		ReplyTo:  replyTo,
+		SendAt:   sendAt,
+	}, nil
+}
+
+func validateSendAt(sendAt Timestamp) error {
+	now := TimeFunc()
+	if int64(sendAt) <= now.Unix() {
+		return ErrScheduleTimePassed
+	}
+	if sendAt.Time().After(now.Add(MaxScheduleAhead)) {
+		return ErrScheduleTooFar
+	}
+	return nil
+}
@@ -0,0 +1,114 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+func TestScheduledMessage(t *testing.T) {
+	user1, _ := NewUserID("3d7ca3ef-3b0d-4113-91c9-20b7bf874324")
+	user2, _ := NewUserID("ce30ebc7-4058-4351-9a8f-66c71f987fdf")
+	user3, _ := NewUserID("fb048277-ad4f-4730-88eb-5e453c9ca5ce")
+	chat := &FakeChat{
+		Chat: Chat{
+			ID: NewChatID(),
+		},
+		Members: [2]UserID{user1, user2},
+	}
+	inHour := Timestamp(time.Now().Add(time.Hour).Unix())
+
+	t.Run("New", func(t *testing.T) {
+		msg, err := NewScheduledTextMessage(chat, user1, "text", nil, nil, inHour)
+		require.NoError(t, err)
+		require.Equal(t, chat.ID, msg.ChatID)
+		require.Equal(t, user1, msg.SenderID)
+		require.False(t, msg.IsFile())
+		require.False(t, msg.IsDue())
+
+		file, err := NewScheduledFileMessage(chat, user1, uuid.New(), nil, inHour)
+		require.NoError(t, err)
+		require.True(t, file.IsFile())
+
+		_, err = NewScheduledTextMessage(chat, user3, "text", nil, nil, inHour)
+		require.ErrorIs(t, err, ErrUserNotMember)
+
+		_, err = NewScheduledTextMessage(chat, user1, "", nil, nil, inHour)
+		require.ErrorIs(t, err, ErrTextEmpty)
+	})
+
+	t.Run("SendAt", func(t *testing.T) {
+		past := Timestamp(time.Now().Add(-time.Minute).Unix())
+		_, err := NewScheduledTextMessage(chat, user1, "text", nil, nil, past)
+		require.ErrorIs(t, err, ErrScheduleTimePassed)
+
+		tooFar := Timestamp(time.Now().Add(MaxScheduleAhead + time.Hour).Unix())
+		_, err = NewScheduledTextMessage(chat, user1, "text", nil, nil, tooFar)
+		require.ErrorIs(t, err, ErrScheduleTooFar)
+	})
+
+	t.Run("Edit", func(t *testing.T) {
+		msg, err := NewScheduledTextMessage(chat, user1, "text", nil, nil, inHour)
+		require.NoError(t, err)
+
+		err = msg.Edit(chat, user2, "new text", nil, inHour)
+		require.ErrorIs(t, err, ErrUserNotSender)
+
+		inTwoHours := Timestamp(time.Now().Add(2 * time.Hour).Unix())
+		require.NoError(t, msg.Edit(chat, user1, "new text", nil, inTwoHours))
+		require.Equal(t, "new text", msg.Text)
+		require.Equal(t, inTwoHours, msg.SendAt)
+
+		file, err := NewScheduledFileMessage(chat, user1, uuid.New(), nil, inHour)
+		require.NoError(t, err)
+		err = file.Edit(chat, user1, "text", nil, inHour)
+		require.ErrorIs(t, err, ErrScheduledFileText)
+		require.NoError(t, file.Edit(chat, user1, "", nil, inTwoHours))
+	})
+
+	t.Run("Entities", func(t *testing.T) {
+		msg, err := NewScheduledTextMessage(chat, user1, "hello world", []TextEntity{
+			{Type: TextEntityItalic, Offset: 6, Length: 5},
+			{Type: TextEntityBold, Offset: 0, Length: 5},
+		}, nil, inHour)
+		require.NoError(t, err)
+		require.Len(t, msg.Entities, 2)
+		require.Equal(t, TextEntityBold, msg.Entities[0].Type)
+
+		_, err = NewScheduledTextMessage(chat, user1, "hello", []TextEntity{
+			{Type: "underline", Offset: 0, Length: 5},
+		}, nil, inHour)
+		require.ErrorIs(t, err, ErrTextEntityInvalid)
+
+		require.NoError(t, msg.Edit(chat, user1, "bye", nil, inHour))
+		require.Nil(t, msg.Entities)
+
+		file, err := NewScheduledFileMessage(chat, user1, uuid.New(), nil, inHour)
+		require.NoError(t, err)
+		err = file.Edit(chat, user1, "", []TextEntity{{Type: TextEntityBold, Offset: 0, Length: 1}}, inHour)
+		require.ErrorIs(t, err, ErrScheduledFileText)
+	})
+
+	t.Run("Cancel", func(t *testing.T) {
+		msg, err := NewScheduledTextMessage(chat, user1, "text", nil, nil, inHour)
+		require.NoError(t, err)
+
+		require.ErrorIs(t, msg.Cancel(chat, user2), ErrUserNotSender)
+		require.NoError(t, msg.Cancel(chat, user1))
+	})
+
+	t.Run("Due", func(t *testing.T) {
+		msg, err := NewScheduledTextMessage(chat, user1, "text", nil, nil, inHour)
+		require.NoError(t, err)
+
+		defer func(f func() time.Time) { TimeFunc = f }(TimeFunc)
+		TimeFunc = func() time.Time {
+			return time.Now().Add(2 * time.Hour)
+		}
+		require.True(t, msg.IsDue())
+		require.ErrorIs(t, msg.Edit(chat, user1, "new text", nil, inHour), ErrScheduledSending)
+		require.ErrorIs(t, msg.Cancel(chat, user1), ErrScheduledSending)
+	})
+}
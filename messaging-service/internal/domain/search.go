@@ -0,0 +1,24 @@
+package domain
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+const (
+	MaxSearchQueryRunes = 256
+)
+
+// SearchQuery is a user's input for full-text message search.
+type SearchQuery string
+
+func NewSearchQuery(query string) (SearchQuery, error) {
+	query = strings.TrimSpace(query)
+	if query == "" {
+		return "", ErrSearchQueryEmpty
+	}
+	if utf8.RuneCountInString(query) > MaxSearchQueryRunes {
+		return "", ErrSearchQueryTooLong
+	}
+	return SearchQuery(query), nil
+}
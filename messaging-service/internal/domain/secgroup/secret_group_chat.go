@@ -9,7 +9,7 @@ import (
 
 type SecretGroupChat struct {
 	domain.SecretChat
-	Admin   domain.UserID
+	domain.GroupRoles
 	Members []domain.UserID
 
 	Name        string
@@ -17,12 +17,12 @@ type SecretGroupChat struct {
 	GroupPhoto  domain.URL
 }
 
-func NewSecretGroupChat(admin domain.UserID, members []domain.UserID, name string) (*SecretGroupChat, error) {
+func NewSecretGroupChat(owner domain.UserID, members []domain.UserID, name string) (*SecretGroupChat, error) {
 	if err := domain.ValidateGroupInfo(name, ""); err != nil {
 		return nil, err
 	}
 
-	if !slices.Contains(members, admin) {
+	if !slices.Contains(members, owner) {
 		return nil, domain.ErrAdminNotMember
 	}
 
@@ -34,7 +34,7 @@ func NewSecretGroupChat(admin domain.UserID, members []domain.UserID, name strin
 				ID: domain.NewChatID(),
 			},
 		},
-		Admin:       admin,
+		GroupRoles:  domain.NewGroupRoles(owner),
 		Members:     normMembers,
 		Name:        name,
 		Description: "",
@@ -43,15 +43,15 @@ func NewSecretGroupChat(admin domain.UserID, members []domain.UserID, name strin
 }
 
 func (g *SecretGroupChat) Delete(sender domain.UserID) error {
-	if sender != g.Admin {
-		return domain.ErrSenderNotAdmin
+	if !g.IsOwner(sender) {
+		return domain.ErrSenderNotOwner
 	}
 	return nil
 }
 
 func (g *SecretGroupChat) UpdateInfo(sender domain.UserID, name, description string) error {
-	if sender != g.Admin {
-		return domain.ErrSenderNotAdmin
+	if err := g.ValidateAdminPermission(sender, domain.PermissionEditInfo); err != nil {
+		return err
 	}
 
 	if err := domain.ValidateGroupInfo(name, description); err != nil {
@@ -64,8 +64,8 @@ func (g *SecretGroupChat) UpdateInfo(sender domain.UserID, name, description str
 }
 
 func (g *SecretGroupChat) UpdatePhoto(sender domain.UserID, photo domain.URL) error {
-	if sender != g.Admin {
-		return domain.ErrSenderNotAdmin
+	if err := g.ValidateAdminPermission(sender, domain.PermissionEditInfo); err != nil {
+		return err
 	}
 
 	g.GroupPhoto = photo
@@ -73,8 +73,8 @@ func (g *SecretGroupChat) UpdatePhoto(sender domain.UserID, photo domain.URL) er
 }
 
 func (g *SecretGroupChat) DeletePhoto(sender domain.UserID) error {
-	if sender != g.Admin {
-		return domain.ErrSenderNotAdmin
+	if err := g.ValidateAdminPermission(sender, domain.PermissionEditInfo); err != nil {
+		return err
 	}
 
 	if g.GroupPhoto == "" {
@@ -86,8 +86,8 @@ func (g *SecretGroupChat) DeletePhoto(sender domain.UserID) error {
 }
 
 func (g *SecretGroupChat) AddMember(sender domain.UserID, newMember domain.UserID) error {
-	if sender != g.Admin {
-		return domain.ErrSenderNotAdmin
+	if err := g.ValidateAdminPermission(sender, domain.PermissionAddMembers); err != nil {
+		return err
 	}
 
 	if g.IsMember(newMember) {
@@ -99,26 +99,59 @@ func (g *SecretGroupChat) AddMember(sender domain.UserID, newMember domain.UserI
 }
 
 func (g *SecretGroupChat) DeleteMember(sender domain.UserID, member domain.UserID) error {
-	if sender != g.Admin && sender != member {
-		return domain.ErrSenderNotAdmin
+	if sender == member {
+		return g.Leave(member)
+	}
+	if err := g.ValidateCanRemoveMember(sender, member); err != nil {
+		return err
 	}
 
-	if g.Admin == member {
-		return domain.ErrMemberIsAdmin
+	i := slices.Index(g.Members, member)
+	if i == -1 {
+		return domain.ErrUserNotMember
 	}
 
+	g.Members = slices.Delete(g.Members, i, i+1)
+	delete(g.Admins, member)
+	return nil
+}
+
+// Leave removes the member from the group.
+// If the owner leaves, ownership passes to the oldest remaining admin or member.
+func (g *SecretGroupChat) Leave(member domain.UserID) error {
 	i := slices.Index(g.Members, member)
 	if i == -1 {
 		return domain.ErrUserNotMember
 	}
 
+	if g.IsOwner(member) {
+		if err := g.PassOwnership(g.Members); err != nil {
+			return err
+		}
+	}
+
 	g.Members = slices.Delete(g.Members, i, i+1)
+	delete(g.Admins, member)
 	return nil
 }
 
+func (g *SecretGroupChat) TransferOwnership(sender domain.UserID, newOwner domain.UserID) error {
+	if !g.IsMember(newOwner) {
+		return domain.ErrUserNotMember
+	}
+	return g.GroupRoles.TransferOwnership(sender, newOwner)
+}
+
+func (g *SecretGroupChat) SetAdmin(sender domain.UserID, member domain.UserID, perms domain.AdminPermissions) error {
+	if !g.IsMember(member) {
+		return domain.ErrUserNotMember
+	}
+	return g.GroupRoles.SetAdmin(sender, member, perms)
+}
+
 func (c *SecretGroupChat) SetExpiration(sender domain.UserID, exp *time.Duration) error {
-	if c.Admin != sender {
-		return domain.ErrSenderNotAdmin
+	if err := c.ValidateAdminPermission(sender, domain.PermissionEditInfo); err != nil {
+		return err
 	}
 	c.Exp = exp
 	return nil
@@ -132,5 +165,5 @@ func (g *SecretGroupChat) ValidateCanSend(sender domain.UserID) error {
 	if !g.IsMember(sender) {
 		return domain.ErrUserNotMember
 	}
-	return nil
+	return g.ValidateMemberPermission(sender, domain.MemberCanSendMessages)
 }
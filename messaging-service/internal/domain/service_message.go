@@ -0,0 +1,77 @@
+package domain
+
+import "time"
+
+// ServiceMessage records a change of the chat itself in its update history,
+// so clients that were offline can show it.
+// The change must be already validated by the chat.
+type ServiceMessage struct {
+	Update
+
+	// One of member_added, member_removed, info_changed, photo_changed and expiration_changed
+	Type string
+
+	// Added or removed members
+	Members []UserID
+	// New name and description on info_changed
+	Name        string
+	Description string
+	// New photo on photo_changed. Empty if the photo is deleted
+	Photo URL
+	// New expiration on expiration_changed. Nil if expiration is turned off
+	Expiration *time.Duration
+}
+
+// NewMembersAddedMessage records new members. The sender is the new member itself if it joined by an invite link.
+func NewMembersAddedMessage(chat Chatter, sender UserID, members []UserID) *ServiceMessage {
+	return &ServiceMessage{
+		Update:  newServiceUpdate(chat, sender),
+		Type:    UpdateTypeMemberAdded,
+		Members: members,
+	}
+}
+
+// NewMembersRemovedMessage records removed members. The sender is the member itself if it left.
+func NewMembersRemovedMessage(chat Chatter, sender UserID, members []UserID) *ServiceMessage {
+	return &ServiceMessage{
+		Update:  newServiceUpdate(chat, sender),
+		Type:    UpdateTypeMemberRemoved,
+		Members: members,
+	}
+}
+
+func NewInfoChangedMessage(chat Chatter, sender UserID, name, description string) *ServiceMessage {
+	return &ServiceMessage{
+		Update:      newServiceUpdate(chat, sender),
+		Type:        UpdateTypeInfoChanged,
+		Name:        name,
+		Description: description,
+	}
+}
+
+func NewPhotoChangedMessage(chat Chatter, sender UserID, photo URL) *ServiceMessage {
+	return &ServiceMessage{
+		Update: newServiceUpdate(chat, sender),
+		Type:   UpdateTypePhotoChanged,
+		Photo:  photo,
+	}
+}
+
+func NewExpirationChangedMessage(chat Chatter, sender UserID, exp *time.Duration) *ServiceMessage {
+	return &ServiceMessage{
+		Update:     newServiceUpdate(chat, sender),
+		Type:       UpdateTypeExpirationChanged,
+		Expiration: exp,
+	}
+}
+
+func (m *ServiceMessage) UpdateType() string {
+	return m.Type
+}
+
+func newServiceUpdate(chat Chatter, sender UserID) Update {
+	return Update{
+		ChatID:   chat.ChatID(),
+		SenderID: sender,
+	}
+}
@@ -0,0 +1,120 @@
+package domain
+
+import (
+	"net/url"
+	"slices"
+)
+
+const (
+	MaxTextEntitiesCount = 100
+	MaxEntityURLLength   = 2048
+)
+
+type TextEntityType string
+
+const (
+	TextEntityBold    TextEntityType = "bold"
+	TextEntityItalic  TextEntityType = "italic"
+	TextEntityCode    TextEntityType = "code"
+	TextEntityPre     TextEntityType = "pre"
+	TextEntitySpoiler TextEntityType = "spoiler"
+	TextEntityLink    TextEntityType = "link"
+	TextEntityMention TextEntityType = "mention"
+)
+
+var allowedURLSchemes = []string{"http", "https", "mailto"}
+
+// TextEntity formats a part of message text.
+// Offset and length are measured in runes.
+type TextEntity struct {
+	Type   TextEntityType
+	Offset int
+	Length int
+	// Set only for links
+	URL string
+}
+
+func (t TextEntityType) Valid() bool {
+	switch t {
+	case TextEntityBold, TextEntityItalic, TextEntityCode, TextEntityPre,
+		TextEntitySpoiler, TextEntityLink, TextEntityMention:
+		return true
+	}
+	return false
+}
+
+func validateEntities(text string, entities []TextEntity) error {
+	if len(entities) > MaxTextEntitiesCount {
+		return ErrTooManyTextEntities
+	}
+
+	runes := []rune(text)
+	end := 0
+	for _, entity := range sortEntities(entities) {
+		if !entity.Type.Valid() {
+			return ErrTextEntityInvalid
+		}
+		if entity.Offset < 0 || entity.Length <= 0 || entity.Offset+entity.Length > len(runes) {
+			return ErrTextEntityOutOfRange
+		}
+		if entity.Offset < end {
+			return ErrTextEntitiesOverlap
+		}
+		end = entity.Offset + entity.Length
+
+		switch entity.Type {
+		case TextEntityLink:
+			if err := validateEntityURL(entity.URL); err != nil {
+				return err
+			}
+		case TextEntityMention:
+			// Mention entities are derived from the resolved mentions by SetMentions
+			return ErrTextEntityInvalid
+		default:
+			if entity.URL != "" {
+				return ErrTextEntityInvalid
+			}
+		}
+	}
+
+	return nil
+}
+
+func sortEntities(entities []TextEntity) []TextEntity {
+	if len(entities) == 0 {
+		return nil
+	}
+	sorted := slices.Clone(entities)
+	slices.SortFunc(sorted, func(a, b TextEntity) int {
+		return a.Offset - b.Offset
+	})
+	return sorted
+}
+
+// withoutMentionEntities is used when the mentions of a message are dropped
+func withoutMentionEntities(entities []TextEntity) []TextEntity {
+	res := slices.DeleteFunc(slices.Clone(entities), func(e TextEntity) bool {
+		return e.Type == TextEntityMention
+	})
+	if len(res) == 0 {
+		return nil
+	}
+	return res
+}
+
+func validateEntityURL(rawURL string) error {
+	if rawURL == "" || len(rawURL) > MaxEntityURLLength {
+		return ErrTextEntityURLInvalid
+	}
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return ErrTextEntityURLInvalid
+	}
+	if !slices.Contains(allowedURLSchemes, u.Scheme) {
+		return ErrTextEntityURLInvalid
+	}
+	if u.Scheme != "mailto" && u.Host == "" {
+		return ErrTextEntityURLInvalid
+	}
+	return nil
+}
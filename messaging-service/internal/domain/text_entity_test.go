@@ -0,0 +1,97 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestTextEntities(t *testing.T) {
+	user1, _ := NewUserID("3d7ca3ef-3b0d-4113-91c9-20b7bf874324")
+	user2, _ := NewUserID("ce30ebc7-4058-4351-9a8f-66c71f987fdf")
+	chat := &FakeChat{
+		Chat: Chat{
+			ID: NewChatID(),
+		},
+		Members: [2]UserID{user1, user2},
+	}
+
+	t.Run("Success", func(t *testing.T) {
+		msg, err := NewTextMessage(chat, user1, "привет @user2, see site", []TextEntity{
+			{Type: TextEntityLink, Offset: 19, Length: 4, URL: "https://example.com/page"},
+			{Type: TextEntityBold, Offset: 0, Length: 6},
+			{Type: TextEntityItalic, Offset: 7, Length: 6},
+		}, nil)
+		require.NoError(t, err)
+		require.Len(t, msg.Entities, 3)
+		require.Equal(t, TextEntityBold, msg.Entities[0].Type)
+		require.Equal(t, TextEntityLink, msg.Entities[2].Type)
+	})
+
+	t.Run("Invalid", func(t *testing.T) {
+		_, err := NewTextMessage(chat, user1, "hello", []TextEntity{
+			{Type: "underline", Offset: 0, Length: 5},
+		}, nil)
+		require.ErrorIs(t, err, ErrTextEntityInvalid)
+
+		_, err = NewTextMessage(chat, user1, "hello", []TextEntity{
+			{Type: TextEntityBold, Offset: 0, Length: 5, URL: "https://example.com"},
+		}, nil)
+		require.ErrorIs(t, err, ErrTextEntityInvalid)
+
+		// Mention entities are derived from the mentions only
+		_, err = NewTextMessage(chat, user1, "@user2", []TextEntity{
+			{Type: TextEntityMention, Offset: 0, Length: 6},
+		}, nil)
+		require.ErrorIs(t, err, ErrTextEntityInvalid)
+
+		_, err = NewTextMessage(chat, user1, "привет", []TextEntity{
+			{Type: TextEntityBold, Offset: 2, Length: 5},
+		}, nil)
+		require.ErrorIs(t, err, ErrTextEntityOutOfRange)
+
+		_, err = NewTextMessage(chat, user1, "hello", []TextEntity{
+			{Type: TextEntityBold, Offset: -1, Length: 2},
+		}, nil)
+		require.ErrorIs(t, err, ErrTextEntityOutOfRange)
+
+		_, err = NewTextMessage(chat, user1, "hello world", []TextEntity{
+			{Type: TextEntityItalic, Offset: 4, Length: 3},
+			{Type: TextEntityBold, Offset: 0, Length: 5},
+		}, nil)
+		require.ErrorIs(t, err, ErrTextEntitiesOverlap)
+	})
+
+	t.Run("URL", func(t *testing.T) {
+		for _, url := range []string{"", "javascript:alert(1)", "ftp://example.com", "https://", "//example.com"} {
+			_, err := NewTextMessage(chat, user1, "link", []TextEntity{
+				{Type: TextEntityLink, Offset: 0, Length: 4, URL: url},
+			}, nil)
+			require.ErrorIs(t, err, ErrTextEntityURLInvalid, url)
+		}
+
+		_, err := NewTextMessage(chat, user1, "mail", []TextEntity{
+			{Type: TextEntityLink, Offset: 0, Length: 4, URL: "mailto:john@example.com"},
+		}, nil)
+		require.NoError(t, err)
+	})
+
+	t.Run("Edit", func(t *testing.T) {
+		msg, err := NewTextMessage(chat, user1, "hello", []TextEntity{
+			{Type: TextEntityBold, Offset: 0, Length: 5},
+		}, nil)
+		require.NoError(t, err)
+
+		err = msg.Edit(chat, user1, "hi", []TextEntity{
+			{Type: TextEntityBold, Offset: 0, Length: 5},
+		})
+		require.ErrorIs(t, err, ErrTextEntityOutOfRange)
+
+		err = msg.Edit(chat, user1, "hi there", []TextEntity{
+			{Type: TextEntityCode, Offset: 3, Length: 5},
+		})
+		require.NoError(t, err)
+		require.Equal(t, []TextEntity{{Type: TextEntityCode, Offset: 3, Length: 5}}, msg.Entities)
+		require.Equal(t, msg.Entities, msg.Edited.NewEntities)
+	})
+}
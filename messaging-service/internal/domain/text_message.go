@@ -11,19 +11,24 @@ const (
 type TextMessage struct {
 	Message
 
-	Text   string
-	Edited *TextMessageEdited
+	Text     string
+	Entities []TextEntity
+	Mentions []Mention
+	Edited   *TextMessageEdited
 }
 
 type TextMessageEdited struct {
 	Update
 
-	MessageID UpdateID
-	NewText   string
+	MessageID   UpdateID
+	NewText     string
+	NewEntities []TextEntity
 }
 
-func NewTextMessage(chat Chatter, sender UserID, text string, replyTo *Message) (*TextMessage, error) {
-	if err := chat.ValidateCanSend(sender); err != nil {
+func NewTextMessage(
+	chat Chatter, sender UserID, text string, entities []TextEntity, replyTo *Message,
+) (*TextMessage, error) {
+	if err := validateCanPost(chat, sender); err != nil {
 		return nil, err
 	}
 
@@ -33,7 +38,7 @@ func NewTextMessage(chat Chatter, sender UserID, text string, replyTo *Message)
 		}
 	}
 
-	if err := validateText(text); err != nil {
+	if err := validateText(text, entities); err != nil {
 		return nil, err
 	}
 
@@ -50,11 +55,12 @@ func NewTextMessage(chat Chatter, sender UserID, text string, replyTo *Message)
 			},
 			ReplyTo: replyToID,
 		},
-		Text: text,
+		Text:     text,
+		Entities: sortEntities(entities),
 	}, nil
 }
 
-func (m *TextMessage) Edit(chat Chatter, sender UserID, newText string) error {
+func (m *TextMessage) Edit(chat Chatter, sender UserID, newText string, newEntities []TextEntity) error {
 	if err := chat.ValidateCanSend(sender); err != nil {
 		return err
 	}
@@ -71,15 +77,19 @@ func (m *TextMessage) Edit(chat Chatter, sender UserID, newText string) error {
 		return ErrUpdateDeleted
 	}
 
-	if err := validateText(newText); err != nil {
+	if err := validateText(newText, newEntities); err != nil {
 		return err
 	}
 
 	m.Text = newText
+	m.Entities = sortEntities(newEntities)
+	// Mentions point to the parts of the old text
+	m.Mentions = nil
 	m.Edited = &TextMessageEdited{
-		Update:    Update{ChatID: chat.ChatID(), SenderID: sender},
-		MessageID: m.UpdateID,
-		NewText:   newText,
+		Update:      Update{ChatID: chat.ChatID(), SenderID: sender, ThreadRootID: m.ThreadRootID},
+		MessageID:   m.UpdateID,
+		NewText:     newText,
+		NewEntities: m.Entities,
 	}
 	return nil
 }
@@ -88,7 +98,7 @@ func (m *TextMessage) Forward(fromChat Chatter, sender UserID, toChat Chatter) (
 	if !fromChat.IsMember(sender) {
 		return nil, ErrUserNotMember
 	}
-	if err := toChat.ValidateCanSend(sender); err != nil {
+	if err := validateCanPost(toChat, sender); err != nil {
 		return nil, err
 	}
 
@@ -101,15 +111,17 @@ func (m *TextMessage) Forward(fromChat Chatter, sender UserID, toChat Chatter) (
 			Forwarded: true,
 		},
 		Text: m.Text,
+		// Mentions are not forwarded
+		Entities: withoutMentionEntities(m.Entities),
 	}, nil
 }
 
-func validateText(text string) error {
+func validateText(text string, entities []TextEntity) error {
 	if text == "" {
 		return ErrTextEmpty
 	}
 	if utf8.RuneCountInString(text) > MaxTextRunesCount {
 		return ErrTooManyTextRunes
 	}
-	return nil
+	return validateEntities(text, entities)
 }
@@ -18,16 +18,16 @@ func TestTextMessage(t *testing.T) {
 	}
 
 	t.Run("New", func(t *testing.T) {
-		_, err := NewTextMessage(chat, user1, "", nil)
+		_, err := NewTextMessage(chat, user1, "", nil, nil)
 		require.ErrorIs(t, err, ErrTextEmpty)
 
-		_, err = NewTextMessage(chat, user3, "valid but not a member", nil)
+		_, err = NewTextMessage(chat, user3, "valid but not a member", nil, nil)
 		require.ErrorIs(t, err, ErrUserNotMember)
 
-		_, err = NewTextMessage(chat, user1, string(make([]byte, 3000)), nil)
+		_, err = NewTextMessage(chat, user1, string(make([]byte, 3000)), nil, nil)
 		require.ErrorIs(t, err, ErrTooManyTextRunes)
 
-		msg1, err := NewTextMessage(chat, user1, "valid text message", nil)
+		msg1, err := NewTextMessage(chat, user1, "valid text message", nil, nil)
 		require.NoError(t, err)
 		require.Equal(t, chat.ChatID(), msg1.ChatID)
 		require.Equal(t, user1, msg1.SenderID)
@@ -47,10 +47,10 @@ func TestTextMessage(t *testing.T) {
 	t.Run("Edit", func(t *testing.T) {
 		msg := msgBase
 
-		err := msg.Edit(chat, user2, "valid but user is not a sender")
+		err := msg.Edit(chat, user2, "valid but user is not a sender", nil)
 		require.ErrorIs(t, err, ErrUserNotSender)
 
-		err = msg.Edit(chat, user3, "valid but not a member")
+		err = msg.Edit(chat, user3, "valid but not a member", nil)
 		require.ErrorIs(t, err, ErrUserNotMember)
 	})
 
@@ -66,7 +66,7 @@ func TestTextMessage(t *testing.T) {
 			Mode:      DeleteModeForSender,
 		}}
 
-		err := msg.Edit(chat, user1, "new valid text")
+		err := msg.Edit(chat, user1, "new valid text", nil)
 		require.ErrorIs(t, err, ErrUpdateDeleted)
 	})
 
@@ -0,0 +1,36 @@
+package domain
+
+// ReplyInThread moves a new message to the thread of the root message.
+// Any message can become a thread root except thread messages themselves.
+//
+// The message should be created without a reply because replyTo is validated here.
+// replyTo is optional and must be either the root or a message of the same thread.
+func (m *Message) ReplyInThread(root *Message, replyTo *Message) error {
+	if m.ChatID != root.ChatID {
+		return ErrUpdateNotFromChat
+	}
+
+	if root.ThreadRootID != nil {
+		return ErrNestedThread
+	}
+
+	if root.DeletedFor(m.SenderID) {
+		return ErrUpdateDeleted
+	}
+
+	if replyTo != nil {
+		if m.ChatID != replyTo.ChatID {
+			return ErrUpdateNotFromChat
+		}
+		if replyTo.UpdateID != root.UpdateID && !replyTo.InThread(root.UpdateID) {
+			return ErrUpdateNotFromThread
+		}
+		if replyTo.DeletedFor(m.SenderID) {
+			return ErrUpdateDeleted
+		}
+		m.ReplyTo = &replyTo.UpdateID
+	}
+
+	m.ThreadRootID = &root.UpdateID
+	return nil
+}
@@ -0,0 +1,110 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestThread(t *testing.T) {
+	user1, _ := NewUserID("3d7ca3ef-3b0d-4113-91c9-20b7bf874324")
+	user2, _ := NewUserID("ce30ebc7-4058-4351-9a8f-66c71f987fdf")
+	chat := &FakeChat{
+		Chat: Chat{
+			ID: NewChatID(),
+		},
+		Members: [2]UserID{user1, user2},
+	}
+
+	root := Message{
+		Update: Update{
+			UpdateID: 12,
+			ChatID:   chat.ID,
+			SenderID: user1,
+		},
+	}
+	rootID := root.UpdateID
+	threadMsg := Message{
+		Update: Update{
+			UpdateID:     13,
+			ChatID:       chat.ID,
+			SenderID:     user1,
+			ThreadRootID: &rootID,
+		},
+	}
+	otherThreadRootID := UpdateID(10)
+	otherThreadMsg := Message{
+		Update: Update{
+			UpdateID:     11,
+			ChatID:       chat.ID,
+			SenderID:     user1,
+			ThreadRootID: &otherThreadRootID,
+		},
+	}
+
+	t.Run("Reply", func(t *testing.T) {
+		msg, err := NewTextMessage(chat, user2, "reply", nil, nil)
+		require.NoError(t, err)
+
+		err = msg.ReplyInThread(&root, &threadMsg)
+		require.NoError(t, err)
+		require.Equal(t, &rootID, msg.ThreadRootID)
+		require.Equal(t, threadMsg.UpdateID, *msg.ReplyTo)
+	})
+
+	t.Run("ReplyToRoot", func(t *testing.T) {
+		msg, err := NewTextMessage(chat, user2, "reply", nil, nil)
+		require.NoError(t, err)
+
+		err = msg.ReplyInThread(&root, &root)
+		require.NoError(t, err)
+		require.Equal(t, root.UpdateID, *msg.ReplyTo)
+	})
+
+	t.Run("NestedThread", func(t *testing.T) {
+		msg, err := NewTextMessage(chat, user2, "reply", nil, nil)
+		require.NoError(t, err)
+
+		err = msg.ReplyInThread(&threadMsg, nil)
+		require.ErrorIs(t, err, ErrNestedThread)
+	})
+
+	t.Run("ReplyToOtherThread", func(t *testing.T) {
+		msg, err := NewTextMessage(chat, user2, "reply", nil, nil)
+		require.NoError(t, err)
+
+		err = msg.ReplyInThread(&root, &otherThreadMsg)
+		require.ErrorIs(t, err, ErrUpdateNotFromThread)
+	})
+
+	t.Run("ReplyOutsideThread", func(t *testing.T) {
+		_, err := NewTextMessage(chat, user2, "reply", nil, &threadMsg)
+		require.ErrorIs(t, err, ErrReplyOutsideThread)
+	})
+
+	t.Run("RootDeleted", func(t *testing.T) {
+		root := root
+		root.AddDeletion(user1, DeleteModeForAll)
+		msg, err := NewTextMessage(chat, user2, "reply", nil, nil)
+		require.NoError(t, err)
+
+		err = msg.ReplyInThread(&root, nil)
+		require.ErrorIs(t, err, ErrUpdateDeleted)
+	})
+
+	t.Run("RelatedUpdatesInThread", func(t *testing.T) {
+		msg := TextMessage{Message: threadMsg, Text: "text"}
+
+		err := msg.Edit(chat, user1, "new text", nil)
+		require.NoError(t, err)
+		require.True(t, msg.Edited.InThread(rootID))
+
+		reaction, err := NewReaction(chat, user2, &msg.Message, "heart")
+		require.NoError(t, err)
+		require.True(t, reaction.InThread(rootID))
+
+		err = msg.Delete(chat, user1, DeleteModeForAll)
+		require.NoError(t, err)
+		require.True(t, msg.Deleted[0].InThread(rootID))
+	})
+}
@@ -11,6 +11,15 @@ const (
 	UpdateTypeReaction          = "reaction"
 	UpdateTypeDeleted           = "update_deleted"
 	UpdateTypeSecret            = "secret_update"
+	UpdateTypeMessagePinned     = "message_pinned"
+	UpdateTypeMessageUnpinned   = "message_unpinned"
+	UpdateTypePoll              = "poll"
+
+	UpdateTypeMemberAdded       = "member_added"
+	UpdateTypeMemberRemoved     = "member_removed"
+	UpdateTypeInfoChanged       = "info_changed"
+	UpdateTypePhotoChanged      = "photo_changed"
+	UpdateTypeExpirationChanged = "expiration_changed"
 )
 
 type (
@@ -28,6 +37,9 @@ type Update struct {
 	UpdateID UpdateID
 	ChatID   ChatID
 	SenderID UserID
+	// Is set if the update belongs to a message thread.
+	// Edits, reactions and deletions of thread messages belong to the thread as well.
+	ThreadRootID *UpdateID
 
 	CreatedAt Timestamp
 	Deleted   []*UpdateDeleted
@@ -81,11 +93,16 @@ func (u *Update) DeletedFor(user UserID) bool {
 	return false
 }
 
+func (u *Update) InThread(rootID UpdateID) bool {
+	return u.ThreadRootID != nil && *u.ThreadRootID == rootID
+}
+
 func (u *Update) AddDeletion(sender UserID, mode DeleteMode) {
 	d := &UpdateDeleted{
 		Update: Update{
-			ChatID:   u.ChatID,
-			SenderID: sender,
+			ChatID:       u.ChatID,
+			SenderID:     sender,
+			ThreadRootID: u.ThreadRootID,
 		},
 		DeletedID: u.UpdateID,
 		Mode:      mode,
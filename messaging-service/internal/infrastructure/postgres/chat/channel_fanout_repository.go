@@ -0,0 +1,128 @@
+package chat
+
+import (
+	"context"
+
+	"github.com/chakchat/chakchat-backend/messaging-service/internal/application/publish"
+	"github.com/chakchat/chakchat-backend/messaging-service/internal/application/storage"
+	"github.com/chakchat/chakchat-backend/messaging-service/internal/domain"
+	"github.com/google/uuid"
+)
+
+type ChannelFanoutRepository struct{}
+
+func NewChannelFanoutRepository() *ChannelFanoutRepository {
+	return &ChannelFanoutRepository{}
+}
+
+func (r *ChannelFanoutRepository) Create(
+	ctx context.Context, db storage.ExecQuerier, f *publish.Fanout,
+) (*publish.Fanout, error) {
+	q := `
+	INSERT INTO messaging.channel_fanout (chat_id, sender_id, event_type, event_data, last_user_id)
+	VALUES ($1, $2, $3, $4, $5)
+	RETURNING fanout_id`
+
+	err := db.QueryRow(ctx, q, f.ChatID, f.SenderID, f.Type, f.Data, f.LastUserID).Scan(&f.ID)
+	if err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+func (r *ChannelFanoutRepository) FetchPending(
+	ctx context.Context, db storage.ExecQuerier, limit int,
+) ([]*publish.Fanout, error) {
+	q := `
+	SELECT fanout_id, chat_id, sender_id, event_type, event_data, last_user_id, attempts, retry_at, parked_at
+	FROM messaging.channel_fanout
+	WHERE parked_at IS NULL
+		AND (retry_at IS NULL OR retry_at <= now())
+	ORDER BY fanout_id
+	LIMIT $1
+	FOR UPDATE SKIP LOCKED`
+
+	rows, err := db.Query(ctx, q, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	res := make([]*publish.Fanout, 0)
+	for rows.Next() {
+		var f publish.Fanout
+		err := rows.Scan(&f.ID, &f.ChatID, &f.SenderID, &f.Type, &f.Data, &f.LastUserID,
+			&f.Attempts, &f.RetryAt, &f.ParkedAt)
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, &f)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+func (r *ChannelFanoutRepository) GetSubscribers(
+	ctx context.Context, db storage.ExecQuerier, chatID domain.ChatID, after *domain.UserID, limit int,
+) ([]domain.UserID, error) {
+	var afterID *uuid.UUID
+	if after != nil {
+		cp := uuid.UUID(*after)
+		afterID = &cp
+	}
+
+	q := `
+	SELECT user_id
+	FROM messaging.membership
+	WHERE chat_id = $1 AND ($2::UUID IS NULL OR user_id > $2)
+	ORDER BY user_id
+	LIMIT $3`
+
+	rows, err := db.Query(ctx, q, chatID, afterID, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	res := make([]domain.UserID, 0, limit)
+	for rows.Next() {
+		var userID uuid.UUID
+		if err := rows.Scan(&userID); err != nil {
+			return nil, err
+		}
+		res = append(res, domain.UserID(userID))
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+func (r *ChannelFanoutRepository) Update(
+	ctx context.Context, db storage.ExecQuerier, f *publish.Fanout,
+) (*publish.Fanout, error) {
+	q := `
+	UPDATE messaging.channel_fanout
+	SET last_user_id = $2, attempts = $3, retry_at = $4, parked_at = $5
+	WHERE fanout_id = $1`
+
+	_, err := db.Exec(ctx, q, f.ID, f.LastUserID, f.Attempts, f.RetryAt, f.ParkedAt)
+	if err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+func (r *ChannelFanoutRepository) Delete(
+	ctx context.Context, db storage.ExecQuerier, id int64,
+) error {
+	q := `DELETE FROM messaging.channel_fanout WHERE fanout_id = $1`
+	_, err := db.Exec(ctx, q, id)
+	return err
+}
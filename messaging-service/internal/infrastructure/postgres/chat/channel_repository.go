@@ -0,0 +1,270 @@
+package chat
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/chakchat/chakchat-backend/messaging-service/internal/application/storage"
+	"github.com/chakchat/chakchat-backend/messaging-service/internal/application/storage/repository"
+	"github.com/chakchat/chakchat-backend/messaging-service/internal/domain"
+	"github.com/chakchat/chakchat-backend/messaging-service/internal/domain/channel"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+type ChannelRepository struct{}
+
+func NewChannelRepository() *ChannelRepository {
+	return &ChannelRepository{}
+}
+
+func (r *ChannelRepository) FindById(
+	ctx context.Context, db storage.ExecQuerier, id domain.ChatID, users ...domain.UserID,
+) (*channel.Channel, error) {
+	q := `
+	SELECT c.chat_id, c.created_at, ch.admin_id, ch.channel_name, ch.channel_description, ch.subscribers_count
+	FROM messaging.chat c
+		JOIN messaging.channel ch ON ch.chat_id = c.chat_id
+	WHERE c.chat_id = $1`
+
+	row := db.QueryRow(ctx, q, id)
+
+	var (
+		chatID           uuid.UUID
+		createdAt        time.Time
+		adminID          uuid.UUID
+		name             string
+		description      string
+		subscribersCount int64
+	)
+	err := row.Scan(&chatID, &createdAt, &adminID, &name, &description, &subscribersCount)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, repository.ErrNotFound
+		}
+		return nil, fmt.Errorf("getting channel failed: %s", err)
+	}
+
+	admins, err := getAdmins(ctx, db, id)
+	if err != nil {
+		return nil, err
+	}
+
+	subscribed, err := r.getSubscribed(ctx, db, id, users)
+	if err != nil {
+		return nil, err
+	}
+
+	return &channel.Channel{
+		Chat: domain.Chat{
+			ID:        domain.ChatID(chatID),
+			CreatedAt: domain.Timestamp(createdAt.Unix()),
+		},
+		GroupRoles: domain.GroupRoles{
+			Owner:             domain.UserID(adminID),
+			Admins:            admins,
+			MemberPermissions: 0,
+		},
+		Name:             name,
+		Description:      description,
+		SubscribersCount: subscribersCount,
+		Subscribed:       subscribed,
+	}, nil
+}
+
+func (r *ChannelRepository) Update(
+	ctx context.Context, db storage.ExecQuerier, c *channel.Channel,
+) (*channel.Channel, error) {
+	var toAdd, toDelete []uuid.UUID
+	for user, subscribed := range c.Subscribed {
+		if subscribed {
+			toAdd = append(toAdd, uuid.UUID(user))
+		} else {
+			toDelete = append(toDelete, uuid.UUID(user))
+		}
+	}
+
+	// The count is changed by really changed subscriptions only
+	// because concurrent transactions may change it too.
+	var delta int64
+	if len(toAdd) != 0 {
+		q := `
+		INSERT INTO messaging.membership (chat_id, user_id)
+		SELECT $1, UNNEST($2::UUID[])
+		ON CONFLICT DO NOTHING`
+
+		tag, err := db.Exec(ctx, q, c.ID, toAdd)
+		if err != nil {
+			return nil, err
+		}
+		delta += tag.RowsAffected()
+	}
+	if len(toDelete) != 0 {
+		q := `DELETE FROM messaging.membership WHERE chat_id = $1 AND user_id = ANY($2)`
+
+		tag, err := db.Exec(ctx, q, c.ID, toDelete)
+		if err != nil {
+			return nil, err
+		}
+		delta -= tag.RowsAffected()
+	}
+
+	q := `
+	UPDATE messaging.channel
+	SET admin_id = $2,
+		channel_name = $3,
+		channel_description = $4,
+		subscribers_count = subscribers_count + $5
+	WHERE chat_id = $1
+	RETURNING subscribers_count`
+
+	err := db.QueryRow(ctx, q, c.ID, c.Owner, c.Name, c.Description, delta).Scan(&c.SubscribersCount)
+	if err != nil {
+		return nil, fmt.Errorf("updating channel failed: %s", err)
+	}
+
+	if err := storeAdmins(ctx, db, c.ID, c.Admins); err != nil {
+		return nil, err
+	}
+
+	return c, nil
+}
+
+func (r *ChannelRepository) Create(
+	ctx context.Context, db storage.ExecQuerier, c *channel.Channel,
+) (*channel.Channel, error) {
+	{
+		q := `
+		INSERT INTO messaging.chat
+		(chat_id, chat_type, created_at)
+		VALUES ($1, 'channel', $2)`
+
+		now := time.Now()
+		_, err := db.Exec(ctx, q, c.ID, now)
+		if err != nil {
+			return nil, err
+		}
+		c.CreatedAt = domain.Timestamp(now.Unix())
+	}
+	{
+		q := `
+		INSERT INTO messaging.channel
+		(chat_id, admin_id, channel_name, channel_description, subscribers_count)
+		VALUES ($1, $2, $3, $4, 1)`
+		_, err := db.Exec(ctx, q, c.ID, c.Owner, c.Name, c.Description)
+		if err != nil {
+			return nil, err
+		}
+	}
+	{
+		q := `INSERT INTO messaging.membership (chat_id, user_id) VALUES ($1, $2)`
+		_, err := db.Exec(ctx, q, c.ID, c.Owner)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	c.SubscribersCount = 1
+	return c, nil
+}
+
+func (r *ChannelRepository) Delete(
+	ctx context.Context, db storage.ExecQuerier, id domain.ChatID,
+) error {
+	q := `DELETE FROM messaging.chat WHERE chat_id = $1`
+	_, err := db.Exec(ctx, q, id)
+	return err
+}
+
+func (r *ChannelRepository) AddViews(
+	ctx context.Context, db storage.ExecQuerier, chatID domain.ChatID, user domain.UserID, posts []domain.UpdateID,
+) error {
+	if len(posts) == 0 {
+		return nil
+	}
+
+	q := `
+	WITH viewed AS (
+		INSERT INTO messaging.channel_post_view (chat_id, update_id, user_id)
+		SELECT u.chat_id, u.update_id, $2
+		FROM messaging.update u
+		WHERE u.chat_id = $1
+			AND u.update_id = ANY($3)
+			AND u.update_type IN ('text_message', 'file_message')
+		ON CONFLICT DO NOTHING
+		RETURNING update_id
+	)
+	INSERT INTO messaging.channel_post_stats (chat_id, update_id, views_count)
+	SELECT $1, update_id, 1 FROM viewed
+	ON CONFLICT (chat_id, update_id) DO UPDATE
+	SET views_count = messaging.channel_post_stats.views_count + 1`
+
+	_, err := db.Exec(ctx, q, chatID, uuid.UUID(user), updateIDs(posts))
+	return err
+}
+
+func (r *ChannelRepository) GetViews(
+	ctx context.Context, db storage.ExecQuerier, chatID domain.ChatID, posts []domain.UpdateID,
+) (map[domain.UpdateID]int64, error) {
+	q := `
+	SELECT update_id, views_count
+	FROM messaging.channel_post_stats
+	WHERE chat_id = $1 AND update_id = ANY($2)`
+
+	rows, err := db.Query(ctx, q, chatID, updateIDs(posts))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	res := make(map[domain.UpdateID]int64, len(posts))
+	for rows.Next() {
+		var (
+			updateID int64
+			views    int64
+		)
+		if err := rows.Scan(&updateID, &views); err != nil {
+			return nil, err
+		}
+		res[domain.UpdateID(updateID)] = views
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+func (r *ChannelRepository) getSubscribed(
+	ctx context.Context, db storage.ExecQuerier, id domain.ChatID, users []domain.UserID,
+) (map[domain.UserID]bool, error) {
+	res := make(map[domain.UserID]bool, len(users))
+	if len(users) == 0 {
+		return res, nil
+	}
+
+	for _, user := range users {
+		res[user] = false
+	}
+
+	q := `SELECT user_id FROM messaging.membership WHERE chat_id = $1 AND user_id = ANY($2)`
+
+	rows, err := db.Query(ctx, q, id, uuids(users))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var userID uuid.UUID
+		if err := rows.Scan(&userID); err != nil {
+			return nil, err
+		}
+		res[domain.UserID(userID)] = true
+	}
+
+	return res, rows.Err()
+}
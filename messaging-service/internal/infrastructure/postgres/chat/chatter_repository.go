@@ -17,6 +17,8 @@ type ChatterRepository struct {
 	groupRepo    *GroupChatRepository
 	secpPersonalRepo *SecretPersonalChatRepository
 	secGroupRepo *SecretGroupChatRepository
+	channelRepo  *ChannelRepository
+	topicRepo    *TopicRepository
 }
 
 func NewChatterRepository() *ChatterRepository {
@@ -25,11 +27,13 @@ func NewChatterRepository() *ChatterRepository {
 		groupRepo:        NewGroupChatRepository(),
 		secpPersonalRepo: NewSecretPersonalChatRepository(),
 		secGroupRepo:     NewSecretGroupChatRepository(),
+		channelRepo:      NewChannelRepository(),
+		topicRepo:        NewTopicRepository(),
 	}
 }
 
 func (r *ChatterRepository) FindChatter(
-	ctx context.Context, db storage.ExecQuerier, id domain.ChatID,
+	ctx context.Context, db storage.ExecQuerier, id domain.ChatID, users ...domain.UserID,
 ) (domain.Chatter, error) {
 	q := `SELECT chat_type FROM messaging.chat WHERE chat_id = $1`
 
@@ -62,5 +66,13 @@ func (r *ChatterRepository) FindChatter(
 		return r.secGroupRepo.FindById(ctx, db, id)
 	}
 
+	if chatType == domain.ChatTypeChannel {
+		return r.channelRepo.FindById(ctx, db, id, users...)
+	}
+
+	if chatType == domain.ChatTypeGroupTopic {
+		return r.topicRepo.findByTopicID(ctx, db, id)
+	}
+
 	return nil, errors.Join(repository.ErrNotFound, fmt.Errorf("unknown Chatter type: %s", chatType))
 }
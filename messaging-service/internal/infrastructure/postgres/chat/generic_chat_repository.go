@@ -14,6 +14,63 @@ import (
 	"github.com/jackc/pgx/v5"
 )
 
+// Selects IDs of messages pinned in the chat c.
+// Messages deleted for all are not considered as pinned.
+const pinnedSubquery = `
+		SELECT ARRAY_AGG(p.message_id ORDER BY p.update_id)
+		FROM (
+			SELECT DISTINCT ON (pu.message_id) pu.message_id, pu.update_id, u.update_type
+			FROM messaging.pin_update pu
+				JOIN messaging.update u ON u.chat_id = pu.chat_id AND u.update_id = pu.update_id
+			WHERE pu.chat_id = c.chat_id
+			ORDER BY pu.message_id, pu.update_id DESC
+		) p
+		WHERE p.update_type = 'message_pinned'
+			AND NOT EXISTS (
+				SELECT 1 FROM messaging.update_deleted_update ud
+				WHERE ud.chat_id = c.chat_id 
+					AND ud.deleted_update_id = p.message_id
+					AND ud.mode = 'for_all'
+			)`
+
+// Selects admins of the group chat c ordered by user id.
+// Admins' ids and permissions are selected as two arrays of the same order.
+const (
+	adminIDsSubquery = `
+		SELECT ARRAY_AGG(ga.user_id ORDER BY ga.user_id)
+		FROM messaging.group_admin ga
+		WHERE ga.chat_id = c.chat_id`
+	adminPermsSubquery = `
+		SELECT ARRAY_AGG(ga.permissions ORDER BY ga.user_id)
+		FROM messaging.group_admin ga
+		WHERE ga.chat_id = c.chat_id`
+)
+
+// groupRolesRow holds group roles selected from the database. It is empty for non-group chats
+type groupRolesRow struct {
+	memberPerms *int32
+	adminIDs    []uuid.UUID
+	adminPerms  []int32
+	slowMode    *int32
+	adminsOnly  *bool
+	hidden      *bool
+}
+
+func (r groupRolesRow) admins() []generic.GroupAdmin {
+	admins := make([]generic.GroupAdmin, len(r.adminIDs))
+	for i, id := range r.adminIDs {
+		admins[i] = generic.GroupAdmin{
+			UserID:      id,
+			Permissions: domain.AdminPermissions(r.adminPerms[i]).Names(),
+		}
+	}
+	return admins
+}
+
+func (r groupRolesRow) memberPermissions() []string {
+	return domain.MemberPermissions(deref(r.memberPerms, 0)).Names()
+}
+
 type GenericChatRepository struct{}
 
 func NewGenericChatRepository() *GenericChatRepository {
@@ -34,24 +91,33 @@ func (r *GenericChatRepository) GetByMemberID(
 		c.created_at,
 		(SELECT ARRAY_AGG(me.user_id) 
 		 FROM messaging.membership me
-		 WHERE me.chat_id = c.chat_id),
+		 WHERE me.chat_id = c.chat_id AND c.chat_type != 'channel'),
 		CASE WHEN c.chat_type = 'personal' 
 				THEN (SELECT ARRAY_AGG(b.user_id) 
 						  FROM messaging.blocking b 
 						  WHERE b.chat_id = c.chat_id)
 				ELSE NULL
 		END,
-		COALESCE(group_chat.admin_id, secret_group_chat.admin_id),
-		COALESCE(group_chat.group_name, secret_group_chat.group_name),
+		COALESCE(group_chat.admin_id, secret_group_chat.admin_id, channel.admin_id),
+		COALESCE(group_chat.group_name, secret_group_chat.group_name, channel.channel_name),
 		COALESCE(group_chat.group_photo, secret_group_chat.group_photo),
-		COALESCE(group_chat.group_description, secret_group_chat.group_description),
-		COALESCE(secret_personal_chat.expiration_seconds, secret_group_chat.expiration_seconds)
+		COALESCE(group_chat.group_description, secret_group_chat.group_description, channel.channel_description),
+		COALESCE(secret_personal_chat.expiration_seconds, secret_group_chat.expiration_seconds),
+		COALESCE(group_chat.member_permissions, secret_group_chat.member_permissions),
+		group_chat.slow_mode_seconds,
+		group_chat.admins_only,
+		group_chat.history_hidden,
+		(` + adminIDsSubquery + `),
+		(` + adminPermsSubquery + `),
+		(` + pinnedSubquery + `),
+		channel.subscribers_count
 	FROM messaging.membership m
 		JOIN messaging.chat c ON c.chat_id = m.chat_id
 		LEFT JOIN messaging.personal_chat ON personal_chat.chat_id = c.chat_id
 		LEFT JOIN messaging.group_chat ON group_chat.chat_id = c.chat_id
 		LEFT JOIN messaging.secret_personal_chat ON secret_personal_chat.chat_id = c.chat_id
 		LEFT JOIN messaging.secret_group_chat ON secret_group_chat.chat_id = c.chat_id
+		LEFT JOIN messaging.channel ON channel.chat_id = c.chat_id
 	WHERE m.user_id = $1`
 
 	rows, err := db.Query(ctx, q, memberID)
@@ -73,15 +139,20 @@ func (r *GenericChatRepository) GetByMemberID(
 			groupPhoto        *string
 			groupDescription  *string
 			expirationSeconds *int
+			roles             groupRolesRow
+			pinned            []int64
+			subscribersCount  *int64
 		)
 		err := rows.Scan(&chatID, &chatType, &createdAt, &members, &blockedBy,
-			&adminID, &groupName, &groupPhoto, &groupDescription, &expirationSeconds)
+			&adminID, &groupName, &groupPhoto, &groupDescription, &expirationSeconds,
+			&roles.memberPerms, &roles.slowMode, &roles.adminsOnly, &roles.hidden, &roles.adminIDs, &roles.adminPerms,
+			&pinned, &subscribersCount)
 		if err != nil {
 			return nil, err
 		}
 
 		res = append(res, r.buildGenericChat(chatID, chatType, createdAt, members, blockedBy,
-			adminID, groupName, groupPhoto, groupDescription, expirationSeconds))
+			adminID, groupName, groupPhoto, groupDescription, expirationSeconds, roles, pinned, subscribersCount))
 	}
 
 	if err := rows.Err(); err != nil {
@@ -105,24 +176,34 @@ func (r *GenericChatRepository) GetByChatID(
 		c.created_at,
 		(SELECT ARRAY_AGG(me.user_id) 
 		FROM messaging.membership me
-		WHERE me.chat_id = c.chat_id),
+		WHERE me.chat_id = c.chat_id AND c.chat_type != 'channel'),
 		CASE WHEN c.chat_type = 'personal' 
 				THEN (SELECT ARRAY_AGG(b.user_id) 
 						FROM messaging.blocking b 
 						WHERE b.chat_id = c.chat_id)
 				ELSE NULL
 		END,
-		COALESCE(group_chat.admin_id, secret_group_chat.admin_id),
-		COALESCE(group_chat.group_name, secret_group_chat.group_name),
+		COALESCE(group_chat.admin_id, secret_group_chat.admin_id, channel.admin_id),
+		COALESCE(group_chat.group_name, secret_group_chat.group_name, channel.channel_name),
 		COALESCE(group_chat.group_photo, secret_group_chat.group_photo),
-		COALESCE(group_chat.group_description, secret_group_chat.group_description),
-		COALESCE(secret_personal_chat.expiration_seconds, secret_group_chat.expiration_seconds)
+		COALESCE(group_chat.group_description, secret_group_chat.group_description, channel.channel_description),
+		COALESCE(secret_personal_chat.expiration_seconds, secret_group_chat.expiration_seconds),
+		COALESCE(group_chat.member_permissions, secret_group_chat.member_permissions),
+		group_chat.slow_mode_seconds,
+		group_chat.admins_only,
+		group_chat.history_hidden,
+		(` + adminIDsSubquery + `),
+		(` + adminPermsSubquery + `),
+		(` + pinnedSubquery + `),
+		channel.subscribers_count
 	FROM messaging.chat c
 		LEFT JOIN messaging.personal_chat ON personal_chat.chat_id = c.chat_id
 		LEFT JOIN messaging.group_chat ON group_chat.chat_id = c.chat_id
 		LEFT JOIN messaging.secret_personal_chat ON secret_personal_chat.chat_id = c.chat_id
 		LEFT JOIN messaging.secret_group_chat ON secret_group_chat.chat_id = c.chat_id
-	WHERE c.chat_id = $1`
+		LEFT JOIN messaging.channel ON channel.chat_id = c.chat_id
+	-- Topics are parts of their group, not chats of their own
+	WHERE c.chat_id = $1 AND c.chat_type != 'group_topic'`
 
 	row := db.QueryRow(ctx, q, id)
 
@@ -137,9 +218,14 @@ func (r *GenericChatRepository) GetByChatID(
 		groupPhoto        *string
 		groupDescription  *string
 		expirationSeconds *int
+		roles             groupRolesRow
+		pinned            []int64
+		subscribersCount  *int64
 	)
 	err := row.Scan(&chatID, &chatType, &createdAt, &members, &blockedBy,
-		&adminID, &groupName, &groupPhoto, &groupDescription, &expirationSeconds)
+		&adminID, &groupName, &groupPhoto, &groupDescription, &expirationSeconds,
+		&roles.memberPerms, &roles.slowMode, &roles.adminsOnly, &roles.hidden, &roles.adminIDs, &roles.adminPerms,
+		&pinned, &subscribersCount)
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
 			return nil, repository.ErrNotFound
@@ -148,7 +234,7 @@ func (r *GenericChatRepository) GetByChatID(
 	}
 
 	chat := r.buildGenericChat(chatID, chatType, createdAt, members, blockedBy,
-		adminID, groupName, groupPhoto, groupDescription, expirationSeconds)
+		adminID, groupName, groupPhoto, groupDescription, expirationSeconds, roles, pinned, subscribersCount)
 
 	return &chat, nil
 }
@@ -171,12 +257,16 @@ func (r *GenericChatRepository) buildGenericChat(
 	groupPhoto *string,
 	groupDescription *string,
 	expirationSeconds *int,
+	roles groupRolesRow,
+	pinned []int64,
+	subscribersCount *int64,
 ) generic.Chat {
 	result := generic.Chat{
-		ChatID:    chatID,
-		CreatedAt: createdAt.Unix(),
-		Type:      chatType,
-		Members:   members,
+		ChatID:          chatID,
+		CreatedAt:       createdAt.Unix(),
+		Type:            chatType,
+		Members:         members,
+		PinnedUpdateIDs: pinned,
 	}
 
 	switch chatType {
@@ -186,10 +276,15 @@ func (r *GenericChatRepository) buildGenericChat(
 		}
 	case domain.ChatTypeGroup:
 		result.Info.Group = &generic.GroupInfo{
-			AdminID:     *adminID,
-			Name:        *groupName,
-			Description: deref(groupDescription, ""),
-			GroupPhoto:  deref(groupPhoto, ""),
+			AdminID:           *adminID,
+			Admins:            roles.admins(),
+			MemberPermissions: roles.memberPermissions(),
+			Name:              *groupName,
+			Description:       deref(groupDescription, ""),
+			GroupPhoto:        deref(groupPhoto, ""),
+			SlowModeSeconds:   int(deref(roles.slowMode, 0)),
+			AdminsOnly:        deref(roles.adminsOnly, false),
+			HistoryHidden:     deref(roles.hidden, false),
 		}
 	case domain.ChatTypeSecretPersonal:
 		var exp *time.Duration
@@ -207,11 +302,21 @@ func (r *GenericChatRepository) buildGenericChat(
 			exp = &cp
 		}
 		result.Info.SecretGroup = &generic.SecretGroupInfo{
-			AdminID:     *adminID,
-			Name:        *groupName,
-			Description: deref(groupDescription, ""),
-			GroupPhoto:  deref(groupPhoto, ""),
-			Expiration:  exp,
+			AdminID:           *adminID,
+			Admins:            roles.admins(),
+			MemberPermissions: roles.memberPermissions(),
+			Name:              *groupName,
+			Description:       deref(groupDescription, ""),
+			GroupPhoto:        deref(groupPhoto, ""),
+			Expiration:        exp,
+		}
+	case domain.ChatTypeChannel:
+		result.Info.Channel = &generic.ChannelInfo{
+			OwnerID:          *adminID,
+			Admins:           roles.admins(),
+			Name:             *groupName,
+			Description:      deref(groupDescription, ""),
+			SubscribersCount: deref(subscribersCount, 0),
 		}
 	default:
 		panic(fmt.Errorf("unknown chat type is gotten from db: %s", chatType))
@@ -236,3 +341,16 @@ func (r *GenericChatRepository) GetChatType(
 
 	return chatType, nil
 }
+
+func (r *GenericChatRepository) IsMember(
+	ctx context.Context, db storage.ExecQuerier, chatID domain.ChatID, userID domain.UserID,
+) (bool, error) {
+	q := `SELECT EXISTS (SELECT 1 FROM messaging.membership WHERE chat_id = $1 AND user_id = $2)`
+
+	var isMember bool
+	if err := db.QueryRow(ctx, q, chatID, uuid.UUID(userID)).Scan(&isMember); err != nil {
+		return false, fmt.Errorf("checking membership failed: %s", err)
+	}
+
+	return isMember, nil
+}
@@ -0,0 +1,141 @@
+package chat
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/chakchat/chakchat-backend/messaging-service/internal/application/storage"
+	"github.com/chakchat/chakchat-backend/messaging-service/internal/application/storage/repository"
+	"github.com/chakchat/chakchat-backend/messaging-service/internal/domain"
+	"github.com/chakchat/chakchat-backend/messaging-service/internal/domain/group"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+const groupBanColumns = `
+		chat_id,
+		user_id,
+		banned_by,
+		reason,
+		expires_at,
+		created_at`
+
+type GroupBanRepository struct{}
+
+func NewGroupBanRepository() *GroupBanRepository {
+	return &GroupBanRepository{}
+}
+
+func (r *GroupBanRepository) Store(
+	ctx context.Context, db storage.ExecQuerier, ban *group.Ban,
+) (*group.Ban, error) {
+	q := `
+	INSERT INTO messaging.group_ban (chat_id, user_id, banned_by, reason, expires_at, created_at)
+	VALUES ($1, $2, $3, $4, $5, $6)
+	ON CONFLICT (chat_id, user_id) DO UPDATE
+	SET banned_by = EXCLUDED.banned_by,
+		reason = EXCLUDED.reason,
+		expires_at = EXCLUDED.expires_at,
+		created_at = EXCLUDED.created_at`
+
+	now := time.Now()
+	_, err := db.Exec(ctx, q,
+		ban.ChatID,
+		uuid.UUID(ban.UserID),
+		uuid.UUID(ban.BannedBy),
+		ban.Reason,
+		timestampOrNil(ban.ExpiresAt),
+		now,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	ban.CreatedAt = domain.Timestamp(now.Unix())
+	return ban, nil
+}
+
+func (r *GroupBanRepository) Find(
+	ctx context.Context, db storage.ExecQuerier, chatID domain.ChatID, userID domain.UserID,
+) (*group.Ban, error) {
+	q := `SELECT ` + groupBanColumns + `
+	FROM messaging.group_ban
+	WHERE chat_id = $1 AND user_id = $2`
+
+	ban, err := scanGroupBan(db.QueryRow(ctx, q, chatID, uuid.UUID(userID)))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, repository.ErrNotFound
+		}
+		return nil, err
+	}
+	return ban, nil
+}
+
+func (r *GroupBanRepository) Delete(
+	ctx context.Context, db storage.ExecQuerier, chatID domain.ChatID, userID domain.UserID,
+) error {
+	q := `DELETE FROM messaging.group_ban WHERE chat_id = $1 AND user_id = $2`
+	_, err := db.Exec(ctx, q, chatID, uuid.UUID(userID))
+	return err
+}
+
+func (r *GroupBanRepository) GetActive(
+	ctx context.Context, db storage.ExecQuerier, chatID domain.ChatID, offset, limit int,
+) ([]*group.Ban, error) {
+	q := `SELECT ` + groupBanColumns + `
+	FROM messaging.group_ban
+	WHERE chat_id = $1 AND (expires_at IS NULL OR expires_at > NOW())
+	ORDER BY created_at DESC, user_id
+	OFFSET $2
+	LIMIT $3`
+
+	rows, err := db.Query(ctx, q, chatID, offset, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	res := make([]*group.Ban, 0)
+	for rows.Next() {
+		ban, err := scanGroupBan(rows)
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, ban)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+func scanGroupBan(row pgx.Row) (*group.Ban, error) {
+	var (
+		ban       group.Ban
+		chatID    uuid.UUID
+		userID    uuid.UUID
+		bannedBy  uuid.UUID
+		expiresAt *time.Time
+		createdAt time.Time
+	)
+
+	err := row.Scan(&chatID, &userID, &bannedBy, &ban.Reason, &expiresAt, &createdAt)
+	if err != nil {
+		return nil, err
+	}
+
+	ban.ChatID = domain.ChatID(chatID)
+	ban.UserID = domain.UserID(userID)
+	ban.BannedBy = domain.UserID(bannedBy)
+	if expiresAt != nil {
+		ts := domain.Timestamp(expiresAt.Unix())
+		ban.ExpiresAt = &ts
+	}
+	ban.CreatedAt = domain.Timestamp(createdAt.Unix())
+
+	return &ban, nil
+}
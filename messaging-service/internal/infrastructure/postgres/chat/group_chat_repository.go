@@ -25,8 +25,9 @@ func (r *GroupChatRepository) FindById(
 	ctx context.Context, db storage.ExecQuerier, id domain.ChatID,
 ) (*group.GroupChat, error) {
 	q := `
-	SELECT c.chat_id, c.created_at, g.admin_id, g.group_name, g.group_photo, g.group_description,
-		(SELECT ARRAY_AGG(m.user_id) FROM messaging.membership m WHERE m.chat_id = c.chat_id)
+	SELECT c.chat_id, c.created_at, g.admin_id, g.group_name, g.group_photo, g.group_description, g.member_permissions,
+		g.slow_mode_seconds, g.admins_only, g.history_hidden,
+		(SELECT ARRAY_AGG(m.user_id ORDER BY m.joined_at, m.user_id) FROM messaging.membership m WHERE m.chat_id = c.chat_id)
 	FROM messaging.chat c
 		JOIN messaging.group_chat g ON g.chat_id = c.chat_id
 	WHERE c.chat_id = $1`
@@ -40,9 +41,14 @@ func (r *GroupChatRepository) FindById(
 		name        string
 		photo       string
 		description string
+		memberPerms int32
+		slowMode    int32
+		adminsOnly  bool
+		hidden      bool
 		members     []uuid.UUID
 	)
-	err := row.Scan(&chatID, &createdAt, &adminID, &name, &photo, &description, &members)
+	err := row.Scan(&chatID, &createdAt, &adminID, &name, &photo, &description, &memberPerms,
+		&slowMode, &adminsOnly, &hidden, &members)
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
 			return nil, repository.ErrNotFound
@@ -50,17 +56,38 @@ func (r *GroupChatRepository) FindById(
 		return nil, fmt.Errorf("getting group chat failed: %s", err)
 	}
 
-	return &group.GroupChat{
+	admins, err := getAdmins(ctx, db, id)
+	if err != nil {
+		return nil, err
+	}
+
+	g := &group.GroupChat{
 		Chat: domain.Chat{
 			ID:        domain.ChatID(chatID),
 			CreatedAt: domain.Timestamp(createdAt.Unix()),
 		},
-		Admin:       domain.UserID(adminID),
-		Members:     userIDs(members),
-		Name:        name,
-		Description: description,
-		GroupPhoto:  domain.URL(photo),
-	}, nil
+		GroupRoles: domain.GroupRoles{
+			Owner:             domain.UserID(adminID),
+			Admins:            admins,
+			MemberPermissions: domain.MemberPermissions(memberPerms),
+		},
+		Members:       userIDs(members),
+		Name:          name,
+		Description:   description,
+		GroupPhoto:    domain.URL(photo),
+		SlowMode:      time.Duration(slowMode) * time.Second,
+		AdminsOnly:    adminsOnly,
+		HistoryHidden: hidden,
+	}
+
+	if g.SlowMode != 0 {
+		g.LastSentAt, err = r.getLastSentAt(ctx, db, g.ID, g.SlowMode)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return g, nil
 }
 
 func (r *GroupChatRepository) Update(
@@ -90,14 +117,23 @@ func (r *GroupChatRepository) Update(
 	SET admin_id = $2, 
 		group_name = $3, 
 		group_photo = $4, 
-		group_description = $5
+		group_description = $5,
+		member_permissions = $6,
+		slow_mode_seconds = $7,
+		admins_only = $8,
+		history_hidden = $9
 	WHERE chat_id = $1`
 
-	_, err = db.Exec(ctx, q, g.ID, g.Admin, g.Name, g.GroupPhoto, g.Description)
+	_, err = db.Exec(ctx, q, g.ID, g.Owner, g.Name, g.GroupPhoto, g.Description, int32(g.MemberPermissions),
+		int32(g.SlowMode/time.Second), g.AdminsOnly, g.HistoryHidden)
 	if err != nil {
 		return nil, fmt.Errorf("updating group chat failed: %s", err)
 	}
 
+	if err := storeAdmins(ctx, db, g.ID, g.Admins); err != nil {
+		return nil, err
+	}
+
 	return g, err
 }
 
@@ -120,9 +156,11 @@ func (r *GroupChatRepository) Create(
 	{
 		q := `
 		INSERT INTO messaging.group_chat
-		(chat_id, admin_id, group_name, group_photo, group_description)
-		VALUES ($1, $2, $3, $4, $5)`
-		_, err := db.Exec(ctx, q, g.ID, g.Admin, g.Name, g.GroupPhoto, g.Description)
+		(chat_id, admin_id, group_name, group_photo, group_description, member_permissions,
+			slow_mode_seconds, admins_only, history_hidden)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)`
+		_, err := db.Exec(ctx, q, g.ID, g.Owner, g.Name, g.GroupPhoto, g.Description, int32(g.MemberPermissions),
+			int32(g.SlowMode/time.Second), g.AdminsOnly, g.HistoryHidden)
 		if err != nil {
 			return nil, err
 		}
@@ -157,23 +195,42 @@ func (r *GroupChatRepository) Delete(
 	return err
 }
 
+// addMembers adds members who can see the group history from the next update,
+// which is the one recording that they joined.
+// The same is done for each topic of the group since topics have their own update sequences.
+// Whether the earlier history is hidden is decided by the group.
 func (r *GroupChatRepository) addMembers(
 	ctx context.Context, db storage.ExecQuerier, id domain.ChatID, toAdd []domain.UserID,
 ) error {
-	q := `INSERT INTO messaging.membership (chat_id, user_id) VALUES `
-
-	valExprs := make([]string, 0, len(toAdd))
-	args := make([]any, 0, 2*len(toAdd))
-	for _, userId := range toAdd {
-		argI := len(args) + 1
-		valExprs = append(valExprs, fmt.Sprintf("($%d, $%d)", argI, argI+1))
-		args = append(args, id, userId)
+	{
+		q := `
+		INSERT INTO messaging.membership (chat_id, user_id, visible_from_update_id)
+		SELECT $1, new_member.user_id,
+			COALESCE((SELECT s.last_update_id FROM messaging.chat_sequence s WHERE s.chat_id = $1), 0) + 1
+		FROM UNNEST($2::UUID[]) WITH ORDINALITY AS new_member (user_id, n)
+		ORDER BY new_member.n`
+
+		if _, err := db.Exec(ctx, q, id, uuids(toAdd)); err != nil {
+			return err
+		}
 	}
-
-	q += strings.Join(valExprs, ", ")
-
-	_, err := db.Exec(ctx, q, args...)
-	return err
+	{
+		// Boundaries of a former member are overwritten when they join again
+		q := `
+		INSERT INTO messaging.topic_history_boundary (topic_id, user_id, visible_from_update_id)
+		SELECT t.topic_id, new_member.user_id, COALESCE(s.last_update_id, 0) + 1
+		FROM messaging.group_topic t
+			CROSS JOIN UNNEST($2::UUID[]) AS new_member (user_id)
+			LEFT JOIN messaging.chat_sequence s ON s.chat_id = t.topic_id
+		WHERE t.chat_id = $1
+		ON CONFLICT (topic_id, user_id) DO UPDATE
+		SET visible_from_update_id = EXCLUDED.visible_from_update_id`
+
+		if _, err := db.Exec(ctx, q, id, uuids(toAdd)); err != nil {
+			return err
+		}
+	}
+	return nil
 }
 
 func (r *GroupChatRepository) deleteMembers(
@@ -188,7 +245,7 @@ func (r *GroupChatRepository) deleteMembers(
 func (r *GroupChatRepository) getMembers(
 	ctx context.Context, db storage.ExecQuerier, id domain.ChatID,
 ) ([]domain.UserID, error) {
-	q := `SELECT user_id FROM messaging.membership WHERE chat_id = $1`
+	q := `SELECT user_id FROM messaging.membership WHERE chat_id = $1 ORDER BY joined_at, user_id`
 
 	rows, err := db.Query(ctx, q, id)
 	if err != nil {
@@ -211,3 +268,41 @@ func (r *GroupChatRepository) getMembers(
 
 	return res, nil
 }
+
+// getLastSentAt returns the time of the last message of each member sent during the slow mode delay.
+// Messages of the group topics are counted too.
+func (r *GroupChatRepository) getLastSentAt(
+	ctx context.Context, db storage.ExecQuerier, id domain.ChatID, slowMode time.Duration,
+) (map[domain.UserID]domain.Timestamp, error) {
+	q := `
+	SELECT u.sender_id, MAX(u.created_at)
+	FROM messaging.update u
+	WHERE (u.chat_id = $1 OR u.chat_id IN (SELECT t.topic_id FROM messaging.group_topic t WHERE t.chat_id = $1))
+		AND u.update_type IN ('text_message', 'file_message', 'poll')
+		AND u.created_at > $2
+	GROUP BY u.sender_id`
+
+	rows, err := db.Query(ctx, q, id, time.Now().Add(-slowMode))
+	if err != nil {
+		return nil, fmt.Errorf("getting last sent messages failed: %s", err)
+	}
+	defer rows.Close()
+
+	res := make(map[domain.UserID]domain.Timestamp)
+	for rows.Next() {
+		var (
+			senderID uuid.UUID
+			sentAt   time.Time
+		)
+		if err := rows.Scan(&senderID, &sentAt); err != nil {
+			return nil, err
+		}
+		res[domain.UserID(senderID)] = domain.Timestamp(sentAt.Unix())
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
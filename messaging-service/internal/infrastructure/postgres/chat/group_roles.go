@@ -0,0 +1,66 @@
+package chat
+
+import (
+	"context"
+
+	"github.com/chakchat/chakchat-backend/messaging-service/internal/application/storage"
+	"github.com/chakchat/chakchat-backend/messaging-service/internal/domain"
+	"github.com/google/uuid"
+)
+
+// getAdmins returns admins of group or secret group. The owner is not included.
+func getAdmins(
+	ctx context.Context, db storage.ExecQuerier, id domain.ChatID,
+) (map[domain.UserID]domain.AdminPermissions, error) {
+	q := `SELECT user_id, permissions FROM messaging.group_admin WHERE chat_id = $1`
+
+	rows, err := db.Query(ctx, q, id)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	admins := make(map[domain.UserID]domain.AdminPermissions)
+	for rows.Next() {
+		var (
+			userID uuid.UUID
+			perms  int32
+		)
+		if err := rows.Scan(&userID, &perms); err != nil {
+			return nil, err
+		}
+		admins[domain.UserID(userID)] = domain.AdminPermissions(perms)
+	}
+
+	return admins, rows.Err()
+}
+
+// storeAdmins replaces stored admins of group or secret group
+func storeAdmins(
+	ctx context.Context, db storage.ExecQuerier, id domain.ChatID, admins map[domain.UserID]domain.AdminPermissions,
+) error {
+	q1 := `DELETE FROM messaging.group_admin WHERE chat_id = $1`
+	if _, err := db.Exec(ctx, q1, id); err != nil {
+		return err
+	}
+	if len(admins) == 0 {
+		return nil
+	}
+
+	var (
+		userIDs = make([]uuid.UUID, 0, len(admins))
+		perms   = make([]int32, 0, len(admins))
+	)
+	for userID, p := range admins {
+		userIDs = append(userIDs, uuid.UUID(userID))
+		perms = append(perms, int32(p))
+	}
+
+	q2 := `
+	INSERT INTO messaging.group_admin (chat_id, user_id, permissions)
+	SELECT $1, a.user_id, a.permissions
+	FROM UNNEST($2::UUID[], $3::INT[]) AS a(user_id, permissions)`
+
+	_, err := db.Exec(ctx, q2, id, userIDs, perms)
+	return err
+}
@@ -0,0 +1,258 @@
+package chat
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/chakchat/chakchat-backend/messaging-service/internal/application/storage"
+	"github.com/chakchat/chakchat-backend/messaging-service/internal/application/storage/repository"
+	"github.com/chakchat/chakchat-backend/messaging-service/internal/domain"
+	"github.com/chakchat/chakchat-backend/messaging-service/internal/domain/group"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+const inviteLinkColumns = `
+		token,
+		chat_id,
+		creator_id,
+		expires_at,
+		usage_limit,
+		usage_count,
+		requires_approval,
+		revoked,
+		created_at`
+
+type InviteLinkRepository struct{}
+
+func NewInviteLinkRepository() *InviteLinkRepository {
+	return &InviteLinkRepository{}
+}
+
+func (r *InviteLinkRepository) Create(
+	ctx context.Context, db storage.ExecQuerier, link *group.InviteLink,
+) (*group.InviteLink, error) {
+	q := `
+	INSERT INTO messaging.invite_link
+	(token, chat_id, creator_id, expires_at, usage_limit, usage_count, requires_approval, revoked, created_at)
+	VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)`
+
+	now := time.Now()
+	_, err := db.Exec(ctx, q,
+		link.Token,
+		link.ChatID,
+		uuid.UUID(link.CreatorID),
+		timestampOrNil(link.ExpiresAt),
+		link.UsageLimit,
+		link.UsageCount,
+		link.RequiresApproval,
+		link.Revoked,
+		now,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	link.CreatedAt = domain.Timestamp(now.Unix())
+	return link, nil
+}
+
+func (r *InviteLinkRepository) FindByToken(
+	ctx context.Context, db storage.ExecQuerier, token string,
+) (*group.InviteLink, error) {
+	q := `SELECT ` + inviteLinkColumns + `
+	FROM messaging.invite_link
+	WHERE token = $1
+	FOR UPDATE`
+
+	link, err := scanInviteLink(db.QueryRow(ctx, q, token))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, repository.ErrNotFound
+		}
+		return nil, err
+	}
+	return link, nil
+}
+
+func (r *InviteLinkRepository) Update(
+	ctx context.Context, db storage.ExecQuerier, link *group.InviteLink,
+) (*group.InviteLink, error) {
+	q := `
+	UPDATE messaging.invite_link
+	SET usage_count = $2, revoked = $3
+	WHERE token = $1`
+
+	_, err := db.Exec(ctx, q, link.Token, link.UsageCount, link.Revoked)
+	if err != nil {
+		return nil, err
+	}
+	return link, nil
+}
+
+func (r *InviteLinkRepository) GetByChat(
+	ctx context.Context, db storage.ExecQuerier, chatID domain.ChatID,
+) ([]*group.InviteLink, error) {
+	q := `SELECT ` + inviteLinkColumns + `
+	FROM messaging.invite_link
+	WHERE chat_id = $1
+	ORDER BY created_at, token`
+
+	rows, err := db.Query(ctx, q, chatID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	res := make([]*group.InviteLink, 0)
+	for rows.Next() {
+		link, err := scanInviteLink(rows)
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, link)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+func (r *InviteLinkRepository) CreateJoinRequest(
+	ctx context.Context, db storage.ExecQuerier, req *group.JoinRequest,
+) (*group.JoinRequest, error) {
+	q := `
+	INSERT INTO messaging.join_request (chat_id, user_id, token, created_at)
+	VALUES ($1, $2, $3, $4)`
+
+	now := time.Now()
+	_, err := db.Exec(ctx, q, req.ChatID, uuid.UUID(req.UserID), req.Token, now)
+	if err != nil {
+		return nil, err
+	}
+
+	req.CreatedAt = domain.Timestamp(now.Unix())
+	return req, nil
+}
+
+func (r *InviteLinkRepository) FindJoinRequest(
+	ctx context.Context, db storage.ExecQuerier, chatID domain.ChatID, userID domain.UserID,
+) (*group.JoinRequest, error) {
+	q := `
+	SELECT chat_id, user_id, token, created_at
+	FROM messaging.join_request
+	WHERE chat_id = $1 AND user_id = $2`
+
+	req, err := scanJoinRequest(db.QueryRow(ctx, q, chatID, uuid.UUID(userID)))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, repository.ErrNotFound
+		}
+		return nil, err
+	}
+	return req, nil
+}
+
+func (r *InviteLinkRepository) DeleteJoinRequest(
+	ctx context.Context, db storage.ExecQuerier, chatID domain.ChatID, userID domain.UserID,
+) error {
+	q := `DELETE FROM messaging.join_request WHERE chat_id = $1 AND user_id = $2`
+	_, err := db.Exec(ctx, q, chatID, uuid.UUID(userID))
+	return err
+}
+
+func (r *InviteLinkRepository) GetJoinRequests(
+	ctx context.Context, db storage.ExecQuerier, chatID domain.ChatID,
+) ([]*group.JoinRequest, error) {
+	q := `
+	SELECT chat_id, user_id, token, created_at
+	FROM messaging.join_request
+	WHERE chat_id = $1
+	ORDER BY created_at, user_id`
+
+	rows, err := db.Query(ctx, q, chatID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	res := make([]*group.JoinRequest, 0)
+	for rows.Next() {
+		req, err := scanJoinRequest(rows)
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, req)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+func scanInviteLink(row pgx.Row) (*group.InviteLink, error) {
+	var (
+		link      group.InviteLink
+		chatID    uuid.UUID
+		creatorID uuid.UUID
+		expiresAt *time.Time
+		createdAt time.Time
+	)
+
+	err := row.Scan(
+		&link.Token,
+		&chatID,
+		&creatorID,
+		&expiresAt,
+		&link.UsageLimit,
+		&link.UsageCount,
+		&link.RequiresApproval,
+		&link.Revoked,
+		&createdAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	link.ChatID = domain.ChatID(chatID)
+	link.CreatorID = domain.UserID(creatorID)
+	if expiresAt != nil {
+		ts := domain.Timestamp(expiresAt.Unix())
+		link.ExpiresAt = &ts
+	}
+	link.CreatedAt = domain.Timestamp(createdAt.Unix())
+
+	return &link, nil
+}
+
+func scanJoinRequest(row pgx.Row) (*group.JoinRequest, error) {
+	var (
+		req       group.JoinRequest
+		chatID    uuid.UUID
+		userID    uuid.UUID
+		createdAt time.Time
+	)
+
+	if err := row.Scan(&chatID, &userID, &req.Token, &createdAt); err != nil {
+		return nil, err
+	}
+
+	req.ChatID = domain.ChatID(chatID)
+	req.UserID = domain.UserID(userID)
+	req.CreatedAt = domain.Timestamp(createdAt.Unix())
+
+	return &req, nil
+}
+
+func timestampOrNil(ts *domain.Timestamp) *time.Time {
+	if ts == nil {
+		return nil
+	}
+	t := ts.Time()
+	return &t
+}
@@ -26,7 +26,8 @@ func (r *SecretGroupChatRepository) FindById(
 ) (*secgroup.SecretGroupChat, error) {
 	q := `
 	SELECT c.chat_id, c.created_at, g.admin_id, g.group_name, g.group_photo, g.group_description, g.expiration_seconds,
-		(SELECT ARRAY_AGG(m.user_id) FROM messaging.membership m WHERE m.chat_id = c.chat_id)
+		g.member_permissions,
+		(SELECT ARRAY_AGG(m.user_id ORDER BY m.joined_at, m.user_id) FROM messaging.membership m WHERE m.chat_id = c.chat_id)
 	FROM messaging.chat c
 		JOIN messaging.secret_group_chat g ON g.chat_id = c.chat_id
 	WHERE c.chat_id = $1`
@@ -41,9 +42,11 @@ func (r *SecretGroupChatRepository) FindById(
 		photo             string
 		description       string
 		expirationSeconds *int
+		memberPerms       int32
 		members           []uuid.UUID
 	)
-	err := row.Scan(&chatID, &createdAt, &adminID, &name, &photo, &description, &expirationSeconds, &members)
+	err := row.Scan(&chatID, &createdAt, &adminID, &name, &photo, &description, &expirationSeconds,
+		&memberPerms, &members)
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
 			return nil, repository.ErrNotFound
@@ -51,6 +54,11 @@ func (r *SecretGroupChatRepository) FindById(
 		return nil, fmt.Errorf("getting group chat failed: %s", err)
 	}
 
+	admins, err := getAdmins(ctx, db, id)
+	if err != nil {
+		return nil, err
+	}
+
 	var exp *time.Duration
 	if expirationSeconds != nil {
 		cp := time.Duration(*expirationSeconds) * time.Second
@@ -64,7 +72,11 @@ func (r *SecretGroupChatRepository) FindById(
 			},
 			Exp: exp,
 		},
-		Admin:       domain.UserID(adminID),
+		GroupRoles: domain.GroupRoles{
+			Owner:             domain.UserID(adminID),
+			Admins:            admins,
+			MemberPermissions: domain.MemberPermissions(memberPerms),
+		},
 		Members:     userIDs(members),
 		Name:        name,
 		Description: description,
@@ -100,7 +112,8 @@ func (r *SecretGroupChatRepository) Update(
 		group_name = $3, 
 		group_photo = $4, 
 		group_description = $5,
-		expiration_seconds = $6
+		expiration_seconds = $6,
+		member_permissions = $7
 	WHERE chat_id = $1`
 
 	var exp *int
@@ -109,11 +122,15 @@ func (r *SecretGroupChatRepository) Update(
 		exp = &cp
 	}
 
-	_, err = db.Exec(ctx, q, g.ID, g.Admin, g.Name, g.GroupPhoto, g.Description, exp)
+	_, err = db.Exec(ctx, q, g.ID, g.Owner, g.Name, g.GroupPhoto, g.Description, exp, int32(g.MemberPermissions))
 	if err != nil {
 		return nil, fmt.Errorf("updating group chat failed: %s", err)
 	}
 
+	if err := storeAdmins(ctx, db, g.ID, g.Admins); err != nil {
+		return nil, err
+	}
+
 	return g, err
 }
 
@@ -141,9 +158,9 @@ func (r *SecretGroupChatRepository) Create(
 		}
 		q := `
 		INSERT INTO messaging.secret_group_chat
-		(chat_id, admin_id, group_name, group_photo, group_description, expiration_seconds)
-		VALUES ($1, $2, $3, $4, $5, $6)`
-		_, err := db.Exec(ctx, q, g.ID, g.Admin, g.Name, g.GroupPhoto, g.Description, exp)
+		(chat_id, admin_id, group_name, group_photo, group_description, expiration_seconds, member_permissions)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)`
+		_, err := db.Exec(ctx, q, g.ID, g.Owner, g.Name, g.GroupPhoto, g.Description, exp, int32(g.MemberPermissions))
 		if err != nil {
 			return nil, err
 		}
@@ -209,7 +226,7 @@ func (r *SecretGroupChatRepository) deleteMembers(
 func (r *SecretGroupChatRepository) getMembers(
 	ctx context.Context, db storage.ExecQuerier, id domain.ChatID,
 ) ([]domain.UserID, error) {
-	q := `SELECT user_id FROM messaging.membership WHERE chat_id = $1`
+	q := `SELECT user_id FROM messaging.membership WHERE chat_id = $1 ORDER BY joined_at, user_id`
 
 	rows, err := db.Query(ctx, q, id)
 	if err != nil {
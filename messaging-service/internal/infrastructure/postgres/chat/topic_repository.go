@@ -0,0 +1,254 @@
+package chat
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/chakchat/chakchat-backend/messaging-service/internal/application/storage"
+	"github.com/chakchat/chakchat-backend/messaging-service/internal/application/storage/repository"
+	"github.com/chakchat/chakchat-backend/messaging-service/internal/domain"
+	"github.com/chakchat/chakchat-backend/messaging-service/internal/domain/group"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+const topicColumns = `
+		t.topic_id,
+		t.chat_id,
+		c.created_at,
+		t.title,
+		t.icon,
+		t.creator_id,
+		t.closed`
+
+type TopicRepository struct {
+	groupRepo *GroupChatRepository
+}
+
+func NewTopicRepository() *TopicRepository {
+	return &TopicRepository{
+		groupRepo: NewGroupChatRepository(),
+	}
+}
+
+func (r *TopicRepository) FindById(
+	ctx context.Context, db storage.ExecQuerier, chatID, topicID domain.ChatID,
+) (*group.Topic, error) {
+	t, err := r.findByTopicID(ctx, db, topicID)
+	if err != nil {
+		return nil, err
+	}
+	if t.Group.ID != chatID {
+		return nil, repository.ErrNotFound
+	}
+	return t, nil
+}
+
+// findByTopicID loads the topic with its group without knowing the group
+func (r *TopicRepository) findByTopicID(
+	ctx context.Context, db storage.ExecQuerier, topicID domain.ChatID,
+) (*group.Topic, error) {
+	q := `SELECT ` + topicColumns + `
+	FROM messaging.group_topic t
+		JOIN messaging.chat c ON c.chat_id = t.topic_id
+	WHERE t.topic_id = $1`
+
+	t, chatID, err := scanTopic(db.QueryRow(ctx, q, topicID))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, repository.ErrNotFound
+		}
+		return nil, fmt.Errorf("getting topic failed: %s", err)
+	}
+
+	t.Group, err = r.groupRepo.FindById(ctx, db, chatID)
+	if err != nil {
+		return nil, err
+	}
+
+	return t, nil
+}
+
+func (r *TopicRepository) GetByChatID(
+	ctx context.Context, db storage.ExecQuerier, chatID domain.ChatID,
+) ([]*group.Topic, error) {
+	q := `SELECT ` + topicColumns + `
+	FROM messaging.group_topic t
+		JOIN messaging.chat c ON c.chat_id = t.topic_id
+	WHERE t.chat_id = $1
+	ORDER BY c.created_at, t.topic_id`
+
+	rows, err := db.Query(ctx, q, chatID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	res := make([]*group.Topic, 0)
+	for rows.Next() {
+		t, _, err := scanTopic(rows)
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, t)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+func (r *TopicRepository) Create(
+	ctx context.Context, db storage.ExecQuerier, t *group.Topic,
+) (*group.Topic, error) {
+	{
+		q := `
+		INSERT INTO messaging.chat
+		(chat_id, chat_type, created_at)
+		VALUES ($1, 'group_topic', $2)`
+
+		now := time.Now()
+		_, err := db.Exec(ctx, q, t.ID, now)
+		if err != nil {
+			return nil, err
+		}
+		t.CreatedAt = domain.Timestamp(now.Unix())
+	}
+	{
+		q := `
+		INSERT INTO messaging.group_topic
+		(topic_id, chat_id, title, icon, creator_id, closed)
+		VALUES ($1, $2, $3, $4, $5, $6)`
+
+		_, err := db.Exec(ctx, q, t.ID, t.Group.ID, t.Title, t.Icon, uuid.UUID(t.CreatorID), t.Closed)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return t, nil
+}
+
+func (r *TopicRepository) Update(
+	ctx context.Context, db storage.ExecQuerier, t *group.Topic,
+) (*group.Topic, error) {
+	q := `
+	UPDATE messaging.group_topic
+	SET title = $2,
+		icon = $3,
+		closed = $4
+	WHERE topic_id = $1`
+
+	_, err := db.Exec(ctx, q, t.ID, t.Title, t.Icon, t.Closed)
+	if err != nil {
+		return nil, fmt.Errorf("updating topic failed: %s", err)
+	}
+
+	return t, nil
+}
+
+func (r *TopicRepository) Delete(
+	ctx context.Context, db storage.ExecQuerier, topicID domain.ChatID,
+) error {
+	q := `DELETE FROM messaging.chat WHERE chat_id = $1`
+	_, err := db.Exec(ctx, q, topicID)
+	return err
+}
+
+func (r *TopicRepository) GetStats(
+	ctx context.Context, db storage.ExecQuerier, user domain.UserID, topics []domain.ChatID,
+) (map[domain.ChatID]repository.TopicStats, error) {
+	q := `
+	SELECT t.topic_id,
+		COALESCE(s.last_update_id, 0),
+		(SELECT COUNT(*)
+		FROM messaging.update u
+		WHERE u.chat_id = t.topic_id
+			AND u.update_type IN ('text_message', 'file_message', 'poll')
+			AND u.sender_id <> $1
+			AND u.update_id > COALESCE(r.read_update_id, 0)
+			AND NOT EXISTS (
+				SELECT 1
+				FROM messaging.update_deleted_update ud
+					JOIN messaging.update du ON du.chat_id = ud.chat_id AND du.update_id = ud.update_id
+				WHERE ud.chat_id = u.chat_id
+					AND ud.deleted_update_id = u.update_id
+					AND (ud.mode = 'for_all' OR du.sender_id = $1)
+			))
+	FROM messaging.group_topic t
+		LEFT JOIN messaging.chat_sequence s ON s.chat_id = t.topic_id
+		LEFT JOIN messaging.topic_read r ON r.topic_id = t.topic_id AND r.user_id = $1
+	WHERE t.topic_id = ANY($2)`
+
+	ids := make([]uuid.UUID, len(topics))
+	for i, id := range topics {
+		ids[i] = uuid.UUID(id)
+	}
+
+	rows, err := db.Query(ctx, q, uuid.UUID(user), ids)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	res := make(map[domain.ChatID]repository.TopicStats, len(topics))
+	for rows.Next() {
+		var (
+			topicID      uuid.UUID
+			lastUpdateID int64
+			unreadCount  int
+		)
+		if err := rows.Scan(&topicID, &lastUpdateID, &unreadCount); err != nil {
+			return nil, err
+		}
+		res[domain.ChatID(topicID)] = repository.TopicStats{
+			LastUpdateID: domain.UpdateID(lastUpdateID),
+			UnreadCount:  unreadCount,
+		}
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+func (r *TopicRepository) MarkRead(
+	ctx context.Context, db storage.ExecQuerier, topicID domain.ChatID, user domain.UserID, until domain.UpdateID,
+) error {
+	q := `
+	INSERT INTO messaging.topic_read (topic_id, user_id, read_update_id)
+	VALUES ($1, $2, $3)
+	ON CONFLICT (topic_id, user_id) DO UPDATE
+	SET read_update_id = GREATEST(messaging.topic_read.read_update_id, EXCLUDED.read_update_id)`
+
+	_, err := db.Exec(ctx, q, topicID, uuid.UUID(user), int64(until))
+	return err
+}
+
+// scanTopic returns the topic without its group and the group ID
+func scanTopic(row pgx.Row) (*group.Topic, domain.ChatID, error) {
+	var (
+		t         group.Topic
+		topicID   uuid.UUID
+		chatID    uuid.UUID
+		createdAt time.Time
+		creatorID uuid.UUID
+	)
+
+	err := row.Scan(&topicID, &chatID, &createdAt, &t.Title, &t.Icon, &creatorID, &t.Closed)
+	if err != nil {
+		return nil, domain.ChatID{}, err
+	}
+
+	t.ID = domain.ChatID(topicID)
+	t.CreatedAt = domain.Timestamp(createdAt.Unix())
+	t.CreatorID = domain.UserID(creatorID)
+
+	return &t, domain.ChatID(chatID), nil
+}
@@ -21,6 +21,14 @@ func uuids(s []domain.UserID) []uuid.UUID {
 	return res
 }
 
+func updateIDs(ids []domain.UpdateID) []int64 {
+	res := make([]int64, len(ids))
+	for i, id := range ids {
+		res[i] = int64(id)
+	}
+	return res
+}
+
 func sliceMisses[T comparable](orig, comp []T) []T {
 	compMap := make(map[T]bool, len(comp))
 	for _, t := range comp {
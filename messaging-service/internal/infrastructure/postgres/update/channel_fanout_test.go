@@ -0,0 +1,64 @@
+package update
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/chakchat/chakchat-backend/messaging-service/internal/application/publish"
+	"github.com/chakchat/chakchat-backend/messaging-service/internal/domain"
+	"github.com/chakchat/chakchat-backend/messaging-service/internal/domain/channel"
+	"github.com/chakchat/chakchat-backend/messaging-service/internal/infrastructure/postgres/chat"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+func TestChannelFanoutRetries(t *testing.T) {
+	ctx := context.Background()
+	db := newTestDB(t)
+
+	var (
+		channelRepo = chat.NewChannelRepository()
+		fanoutRepo  = chat.NewChannelFanoutRepository()
+	)
+
+	owner := domain.UserID(uuid.New())
+	c, err := channel.NewChannel(owner, "Channel", "")
+	require.NoError(t, err)
+	_, err = channelRepo.Create(ctx, db, c)
+	require.NoError(t, err)
+
+	f, err := publish.NewFanout(uuid.UUID(c.ID), uuid.UUID(owner), "update", map[string]string{})
+	require.NoError(t, err)
+	f, err = fanoutRepo.Create(ctx, db, f)
+	require.NoError(t, err)
+
+	pendingIDs := func() []int64 {
+		pending, err := fanoutRepo.FetchPending(ctx, db, 10)
+		require.NoError(t, err)
+		ids := make([]int64, 0, len(pending))
+		for _, p := range pending {
+			ids = append(ids, p.ID)
+		}
+		return ids
+	}
+	require.Equal(t, []int64{f.ID}, pendingIDs())
+
+	retryAt := time.Now().Add(time.Hour)
+	f.Attempts = 1
+	f.RetryAt = &retryAt
+	_, err = fanoutRepo.Update(ctx, db, f)
+	require.NoError(t, err)
+	require.Empty(t, pendingIDs())
+
+	retryAt = time.Now().Add(-time.Second)
+	_, err = fanoutRepo.Update(ctx, db, f)
+	require.NoError(t, err)
+	require.Equal(t, []int64{f.ID}, pendingIDs())
+
+	parkedAt := time.Now()
+	f.ParkedAt = &parkedAt
+	_, err = fanoutRepo.Update(ctx, db, f)
+	require.NoError(t, err)
+	require.Empty(t, pendingIDs())
+}
@@ -5,6 +5,7 @@ import (
 	"encoding/base64"
 	"errors"
 	"fmt"
+	"slices"
 	"strconv"
 	"strings"
 	"time"
@@ -42,12 +43,36 @@ func (r *GenericUpdateRepository) GetLastUpdateID(
 	return domain.UpdateID(lastUpdateID), nil
 }
 
+// GetRange returns updates of the chat excluding thread updates
 func (r *GenericUpdateRepository) GetRange(
 	ctx context.Context,
 	db storage.ExecQuerier,
 	visibleTo domain.UserID,
 	chatID domain.ChatID,
 	from, to domain.UpdateID,
+) ([]generic.Update, error) {
+	return r.getRange(ctx, db, visibleTo, chatID, nil, from, to)
+}
+
+func (r *GenericUpdateRepository) GetThreadRange(
+	ctx context.Context,
+	db storage.ExecQuerier,
+	visibleTo domain.UserID,
+	chatID domain.ChatID,
+	rootID domain.UpdateID,
+	from, to domain.UpdateID,
+) ([]generic.Update, error) {
+	return r.getRange(ctx, db, visibleTo, chatID, &rootID, from, to)
+}
+
+// Updates of the main stream are fetched if threadRootID is nil
+func (r *GenericUpdateRepository) getRange(
+	ctx context.Context,
+	db storage.ExecQuerier,
+	visibleTo domain.UserID,
+	chatID domain.ChatID,
+	threadRootID *domain.UpdateID,
+	from, to domain.UpdateID,
 ) ([]generic.Update, error) {
 	// TODO: This query should be optimized.
 	// Especially the check of being deleted.
@@ -56,11 +81,14 @@ func (r *GenericUpdateRepository) GetRange(
 		u.update_id,
 		u.update_type,
 		u.created_at,
-		u.sender_id
+		u.sender_id,
+		u.thread_root_id
 	FROM messaging.update u
 		LEFT JOIN messaging.update_deleted_update ud ON ud.deleted_update_id = u.update_id AND ud.chat_id = u.chat_id
 	WHERE u.chat_id = $1 
 		AND u.update_id BETWEEN $3 AND $4
+		AND u.update_id >= messaging.get_visible_from_update_id($1, $2)
+		AND u.thread_root_id IS NOT DISTINCT FROM $5
 		AND ud.mode IS DISTINCT FROM 'for_all'
 		AND (
 			ud.mode IS DISTINCT FROM 'for_deletion_sender' 
@@ -71,7 +99,7 @@ func (r *GenericUpdateRepository) GetRange(
 					AND update_id = ud.update_id)
 		)`
 
-	rows, err := db.Query(ctx, q, chatID, visibleTo, from, to)
+	rows, err := db.Query(ctx, q, chatID, visibleTo, from, to, threadRootID)
 	if err != nil {
 		return nil, err
 	}
@@ -81,21 +109,23 @@ func (r *GenericUpdateRepository) GetRange(
 
 	for rows.Next() {
 		var (
-			updateID   int64
-			updateType string
-			createdAt  time.Time
-			senderID   uuid.UUID
+			updateID     int64
+			updateType   string
+			createdAt    time.Time
+			senderID     uuid.UUID
+			threadRootID *int64
 		)
-		if err := rows.Scan(&updateID, &updateType, &createdAt, &senderID); err != nil {
+		if err := rows.Scan(&updateID, &updateType, &createdAt, &senderID, &threadRootID); err != nil {
 			return nil, err
 		}
 		updates = append(updates, generic.Update{
-			UpdateID:   updateID,
-			ChatID:     uuid.UUID(chatID),
-			SenderID:   senderID,
-			UpdateType: updateType,
-			CreatedAt:  createdAt.Unix(),
-			Content:    generic.UpdateContent{},
+			UpdateID:     updateID,
+			ChatID:       uuid.UUID(chatID),
+			SenderID:     senderID,
+			UpdateType:   updateType,
+			ThreadRootID: threadRootID,
+			CreatedAt:    createdAt.Unix(),
+			Content:      generic.UpdateContent{},
 		})
 	}
 
@@ -112,10 +142,22 @@ func (r *GenericUpdateRepository) GetRange(
 		return nil, err
 	}
 
+	if err := r.fillThreads(ctx, db, chatID, updates); err != nil {
+		return nil, err
+	}
+
+	if err := r.fillPolls(ctx, db, chatID, updates); err != nil {
+		return nil, err
+	}
+
 	if err := r.fillReactions(ctx, db, chatID, updates); err != nil {
 		return nil, err
 	}
 
+	if err := r.fillPins(ctx, db, chatID, updates); err != nil {
+		return nil, err
+	}
+
 	if err := r.fillUpdateDeleted(ctx, db, chatID, updates); err != nil {
 		return nil, err
 	}
@@ -124,6 +166,10 @@ func (r *GenericUpdateRepository) GetRange(
 		return nil, err
 	}
 
+	if err := r.fillServiceMessages(ctx, db, chatID, updates); err != nil {
+		return nil, err
+	}
+
 	return updates, nil
 }
 
@@ -139,11 +185,13 @@ func (r *GenericUpdateRepository) Get(
 		u.update_id,
 		u.update_type,
 		u.created_at,
-		u.sender_id
+		u.sender_id,
+		u.thread_root_id
 	FROM messaging.update u
 		LEFT JOIN messaging.update_deleted_update ud ON ud.deleted_update_id = u.update_id AND ud.chat_id = u.chat_id
 	WHERE u.chat_id = $1 
 		AND u.update_id = $2
+		AND u.update_id >= messaging.get_visible_from_update_id($1, $3)
 		AND ud.mode IS DISTINCT FROM 'for_all'
 		AND (
 			ud.mode IS DISTINCT FROM 'for_deletion_sender' 
@@ -155,10 +203,11 @@ func (r *GenericUpdateRepository) Get(
 		)`
 
 	var (
-		updateIDVal int64
-		updateType  string
-		createdAt   time.Time
-		senderID    uuid.UUID
+		updateIDVal  int64
+		updateType   string
+		createdAt    time.Time
+		senderID     uuid.UUID
+		threadRootID *int64
 	)
 
 	err := db.QueryRow(ctx, q, chatID, updateID, visibleTo).Scan(
@@ -166,18 +215,20 @@ func (r *GenericUpdateRepository) Get(
 		&updateType,
 		&createdAt,
 		&senderID,
+		&threadRootID,
 	)
 	if err != nil {
 		return nil, err
 	}
 
 	update := generic.Update{
-		UpdateID:   updateIDVal,
-		ChatID:     uuid.UUID(chatID),
-		SenderID:   senderID,
-		UpdateType: updateType,
-		CreatedAt:  createdAt.Unix(),
-		Content:    generic.UpdateContent{},
+		UpdateID:     updateIDVal,
+		ChatID:       uuid.UUID(chatID),
+		SenderID:     senderID,
+		UpdateType:   updateType,
+		ThreadRootID: threadRootID,
+		CreatedAt:    createdAt.Unix(),
+		Content:      generic.UpdateContent{},
 	}
 
 	// Fill in details based on update type
@@ -188,6 +239,9 @@ func (r *GenericUpdateRepository) Get(
 		if err := r.fillTextMessages(ctx, db, chatID, updates); err != nil {
 			return nil, err
 		}
+		if err := r.fillThreads(ctx, db, chatID, updates); err != nil {
+			return nil, err
+		}
 	case domain.UpdateTypeTextMessageEdited:
 		if err := r.fillTextMessageEdited(ctx, db, chatID, updates); err != nil {
 			return nil, err
@@ -196,10 +250,21 @@ func (r *GenericUpdateRepository) Get(
 		if err := r.fillFileMessages(ctx, db, chatID, updates); err != nil {
 			return nil, err
 		}
+		if err := r.fillThreads(ctx, db, chatID, updates); err != nil {
+			return nil, err
+		}
+	case domain.UpdateTypePoll:
+		if err := r.fillPolls(ctx, db, chatID, updates); err != nil {
+			return nil, err
+		}
 	case domain.UpdateTypeReaction:
 		if err := r.fillReactions(ctx, db, chatID, updates); err != nil {
 			return nil, err
 		}
+	case domain.UpdateTypeMessagePinned, domain.UpdateTypeMessageUnpinned:
+		if err := r.fillPins(ctx, db, chatID, updates); err != nil {
+			return nil, err
+		}
 	case domain.UpdateTypeDeleted:
 		if err := r.fillUpdateDeleted(ctx, db, chatID, updates); err != nil {
 			return nil, err
@@ -208,6 +273,11 @@ func (r *GenericUpdateRepository) Get(
 		if err := r.fillSecretUpdates(ctx, db, chatID, updates); err != nil {
 			return nil, err
 		}
+	case domain.UpdateTypeMemberAdded, domain.UpdateTypeMemberRemoved, domain.UpdateTypeInfoChanged,
+		domain.UpdateTypePhotoChanged, domain.UpdateTypeExpirationChanged:
+		if err := r.fillServiceMessages(ctx, db, chatID, updates); err != nil {
+			return nil, err
+		}
 	}
 
 	return &updates[0], nil
@@ -224,7 +294,7 @@ func (r *GenericUpdateRepository) FetchLast( // TODO: rename to latest
 
 	var updateTypes []string
 	if opt.Mode & repository.FetchLastModeMessages != 0 {
-		updateTypes = append(updateTypes, "text_message", "file_message")
+		updateTypes = append(updateTypes, "text_message", "file_message", "poll")
 	}
 	if opt.Mode & repository.FetchLastModeReactions != 0 {
 		updateTypes = append(updateTypes, "reaction")
@@ -272,6 +342,8 @@ func (r *GenericUpdateRepository) getUpdateIDFromLast(
 		LEFT JOIN messaging.update_deleted_update ud 
 			ON ud.deleted_update_id = u.update_id AND ud.chat_id = u.chat_id
 	WHERE u.chat_id = $1
+		AND u.thread_root_id IS NULL
+		AND u.update_id >= messaging.get_visible_from_update_id($1, $2)
 		%s -- Here is check for update type.
 		AND ud.mode IS DISTINCT FROM 'for_all'
 		AND (
@@ -369,6 +441,16 @@ func (r *GenericUpdateRepository) fillTextMessages(
 		return err
 	}
 
+	entities, err := r.getTextEntities(ctx, db, chatID, ids)
+	if err != nil {
+		return err
+	}
+
+	mentions, err := r.getTextMentions(ctx, db, chatID, ids)
+	if err != nil {
+		return err
+	}
+
 	// Update the updates with the text message info
 	for i, update := range updates {
 		if update.UpdateType == domain.UpdateTypeTextMessage {
@@ -377,6 +459,8 @@ func (r *GenericUpdateRepository) fillTextMessages(
 				if edit, exists := editedInfo[update.UpdateID]; exists {
 					info.Edited = edit
 				}
+				info.Entities = entities[update.UpdateID]
+				info.Mentions = mentions[update.UpdateID]
 
 				reactions, err := r.getMessageReactions(ctx, db, chatID, domain.UpdateID(update.UpdateID))
 				if err != nil {
@@ -396,6 +480,96 @@ func (r *GenericUpdateRepository) fillTextMessages(
 	return nil
 }
 
+// getTextEntities returns entities of text messages or their edits by update id
+func (r *GenericUpdateRepository) getTextEntities(
+	ctx context.Context,
+	db storage.ExecQuerier,
+	chatID domain.ChatID,
+	updateIDs []int64,
+) (map[int64][]generic.TextEntityContent, error) {
+	if len(updateIDs) == 0 {
+		return nil, nil
+	}
+
+	q := fmt.Sprintf(`
+	SELECT
+		e.update_id,
+		e.entity_offset,
+		e.entity_length,
+		e.entity_type,
+		e.url
+	FROM messaging.text_entity e
+	WHERE e.chat_id = $1 
+		AND e.update_id IN %s
+	ORDER BY e.update_id, e.entity_offset
+	`, sqlArgsArr(2, len(updateIDs)))
+
+	rows, err := db.Query(ctx, q, append([]any{chatID}, idsToAny(updateIDs)...)...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	entities := make(map[int64][]generic.TextEntityContent)
+	for rows.Next() {
+		var (
+			updateID int64
+			entity   generic.TextEntityContent
+			url      *string
+		)
+		if err := rows.Scan(&updateID, &entity.Offset, &entity.Length, &entity.Type, &url); err != nil {
+			return nil, err
+		}
+		if url != nil {
+			entity.URL = *url
+		}
+		entities[updateID] = append(entities[updateID], entity)
+	}
+
+	return entities, rows.Err()
+}
+
+func (r *GenericUpdateRepository) getTextMentions(
+	ctx context.Context,
+	db storage.ExecQuerier,
+	chatID domain.ChatID,
+	messageIDs []int64,
+) (map[int64][]generic.MentionContent, error) {
+	q := fmt.Sprintf(`
+	SELECT
+		m.update_id,
+		m.mention_offset,
+		m.mention_length,
+		m.user_id
+	FROM messaging.mention m
+	WHERE m.chat_id = $1 
+		AND m.update_id IN %s
+	ORDER BY m.update_id, m.mention_offset
+	`, sqlArgsArr(2, len(messageIDs)))
+
+	rows, err := db.Query(ctx, q, append([]any{chatID}, idsToAny(messageIDs)...)...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	mentions := make(map[int64][]generic.MentionContent)
+	for rows.Next() {
+		var (
+			updateID int64
+			offset   int
+			length   int
+			userID   *uuid.UUID
+		)
+		if err := rows.Scan(&updateID, &offset, &length, &userID); err != nil {
+			return nil, err
+		}
+		mentions[updateID] = append(mentions[updateID], generic.NewMentionContent(offset, length, userID))
+	}
+
+	return mentions, rows.Err()
+}
+
 // Helper function to get edits for text messages
 func (r *GenericUpdateRepository) getTextEdits(
 	ctx context.Context,
@@ -467,6 +641,18 @@ func (r *GenericUpdateRepository) getTextEdits(
 		return nil, err
 	}
 
+	editIDs := make([]int64, 0, len(result))
+	for _, edit := range result {
+		editIDs = append(editIDs, edit.UpdateID)
+	}
+	entities, err := r.getTextEntities(ctx, db, chatID, editIDs)
+	if err != nil {
+		return nil, err
+	}
+	for _, edit := range result {
+		edit.Content.TextMessageEdited.NewEntities = entities[edit.UpdateID]
+	}
+
 	return result, nil
 }
 
@@ -520,10 +706,16 @@ func (r *GenericUpdateRepository) fillTextMessageEdited(
 		return err
 	}
 
+	entities, err := r.getTextEntities(ctx, db, chatID, ids)
+	if err != nil {
+		return err
+	}
+
 	// Update the updates with the edit info
 	for i, update := range updates {
 		if update.UpdateType == domain.UpdateTypeTextMessageEdited {
 			if info, ok := edits[update.UpdateID]; ok {
+				info.NewEntities = entities[update.UpdateID]
 				updates[i].Content.TextMessageEdited = &info
 			} else {
 				panic("database is inconsistent!!!")
@@ -632,6 +824,220 @@ func (r *GenericUpdateRepository) fillFileMessages(
 	return nil
 }
 
+// fillPolls fills polls with their current results.
+// Voters of anonymous polls are not selected at all.
+func (r *GenericUpdateRepository) fillPolls(
+	ctx context.Context,
+	db storage.ExecQuerier,
+	chatID domain.ChatID,
+	updates []generic.Update,
+) error {
+	ids := updateTypesIDs(updates, domain.UpdateTypePoll)
+	if len(ids) == 0 {
+		return nil
+	}
+
+	q1 := fmt.Sprintf(`
+	SELECT
+		p.update_id,
+		p.question,
+		p.multiple_choice,
+		p.anonymous,
+		p.closes_at,
+		p.closed OR p.closes_at <= NOW(),
+		p.reply_to_id,
+		(SELECT COUNT(DISTINCT v.user_id) 
+		 FROM messaging.poll_vote v 
+		 WHERE v.chat_id = p.chat_id AND v.poll_id = p.update_id)
+	FROM messaging.poll_update p
+	WHERE p.chat_id = $1 
+		AND p.update_id IN %s
+	`, sqlArgsArr(2, len(ids)))
+
+	rows, err := db.Query(ctx, q1, append([]any{chatID}, idsToAny(ids)...)...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	polls := make(map[int64]*generic.PollContent)
+
+	for rows.Next() {
+		var (
+			updateID int64
+			poll     generic.PollContent
+			closesAt *time.Time
+		)
+
+		if err := rows.Scan(
+			&updateID,
+			&poll.Question,
+			&poll.MultipleChoice,
+			&poll.Anonymous,
+			&closesAt,
+			&poll.Closed,
+			&poll.ReplyTo,
+			&poll.TotalVoters,
+		); err != nil {
+			return err
+		}
+
+		if closesAt != nil {
+			unix := closesAt.Unix()
+			poll.ClosesAt = &unix
+		}
+		polls[updateID] = &poll
+	}
+
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	q2 := fmt.Sprintf(`
+	SELECT
+		o.poll_id,
+		o.text,
+		COUNT(v.user_id),
+		ARRAY_REMOVE(ARRAY_AGG(v.user_id ORDER BY v.voted_at, v.user_id) FILTER (WHERE NOT p.anonymous), NULL)
+	FROM messaging.poll_option o
+		JOIN messaging.poll_update p ON p.chat_id = o.chat_id AND p.update_id = o.poll_id
+		LEFT JOIN messaging.poll_vote v 
+			ON v.chat_id = o.chat_id AND v.poll_id = o.poll_id AND v.option_index = o.option_index
+	WHERE o.chat_id = $1 
+		AND o.poll_id IN %s
+	GROUP BY o.poll_id, o.option_index, o.text
+	ORDER BY o.poll_id, o.option_index
+	`, sqlArgsArr(2, len(ids)))
+
+	optionRows, err := db.Query(ctx, q2, append([]any{chatID}, idsToAny(ids)...)...)
+	if err != nil {
+		return err
+	}
+	defer optionRows.Close()
+
+	for optionRows.Next() {
+		var (
+			pollID int64
+			option generic.PollOptionContent
+		)
+
+		if err := optionRows.Scan(&pollID, &option.Text, &option.Votes, &option.Voters); err != nil {
+			return err
+		}
+
+		poll, ok := polls[pollID]
+		if !ok {
+			panic("database is inconsistent!!!")
+		}
+		if poll.Anonymous {
+			// Just in case
+			option.Voters = nil
+		}
+		poll.Options = append(poll.Options, option)
+	}
+
+	if err := optionRows.Err(); err != nil {
+		return err
+	}
+
+	for i, update := range updates {
+		if update.UpdateType == domain.UpdateTypePoll {
+			if info, ok := polls[update.UpdateID]; ok {
+				reactions, err := r.getMessageReactions(ctx, db, chatID, domain.UpdateID(update.UpdateID))
+				if err != nil {
+					return err
+				}
+				if reactions != nil {
+					info.Reactions = reactions
+				}
+
+				updates[i].Content.Poll = info
+			} else {
+				panic("database is inconsistent!!!")
+			}
+		}
+	}
+
+	return nil
+}
+
+// fillThreads fills thread info of messages that are thread roots
+func (r *GenericUpdateRepository) fillThreads(
+	ctx context.Context,
+	db storage.ExecQuerier,
+	chatID domain.ChatID,
+	updates []generic.Update,
+) error {
+	ids := append(
+		updateTypesIDs(updates, domain.UpdateTypeTextMessage),
+		updateTypesIDs(updates, domain.UpdateTypeFileMessage)...,
+	)
+	if len(ids) == 0 {
+		return nil
+	}
+
+	// Window function is computed before DISTINCT ON, so the count includes all replies
+	q := fmt.Sprintf(`
+	SELECT DISTINCT ON (u.thread_root_id)
+		u.thread_root_id,
+		COUNT(*) OVER (PARTITION BY u.thread_root_id),
+		u.update_id,
+		u.sender_id
+	FROM messaging.update u
+	WHERE u.chat_id = $1
+		AND u.thread_root_id IN %s
+		AND u.update_type IN ('text_message', 'file_message')
+		AND NOT EXISTS (
+			SELECT 1
+			FROM messaging.update_deleted_update ud
+			WHERE ud.chat_id = u.chat_id
+				AND ud.deleted_update_id = u.update_id
+				AND ud.mode = 'for_all'
+		)
+	ORDER BY u.thread_root_id, u.update_id DESC
+	`, sqlArgsArr(2, len(ids)))
+
+	rows, err := db.Query(ctx, q, append([]any{chatID}, idsToAny(ids)...)...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	threads := make(map[int64]*generic.ThreadInfo)
+
+	for rows.Next() {
+		var (
+			rootID int64
+			info   generic.ThreadInfo
+		)
+
+		if err := rows.Scan(&rootID, &info.ReplyCount, &info.LastReplyID, &info.LastReplierID); err != nil {
+			return err
+		}
+
+		threads[rootID] = &info
+	}
+
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	for i, update := range updates {
+		info, ok := threads[update.UpdateID]
+		if !ok {
+			continue
+		}
+		switch {
+		case update.Content.TextMessage != nil:
+			updates[i].Content.TextMessage.Thread = info
+		case update.Content.FileMessage != nil:
+			updates[i].Content.FileMessage.Thread = info
+		}
+	}
+
+	return nil
+}
+
 func (r *GenericUpdateRepository) getMessageReactions(
 	ctx context.Context,
 	db storage.ExecQuerier,
@@ -752,6 +1158,69 @@ func (r *GenericUpdateRepository) fillReactions(
 	return nil
 }
 
+func (r *GenericUpdateRepository) fillPins(
+	ctx context.Context,
+	db storage.ExecQuerier,
+	chatID domain.ChatID,
+	updates []generic.Update,
+) error {
+	ids := append(
+		updateTypesIDs(updates, domain.UpdateTypeMessagePinned),
+		updateTypesIDs(updates, domain.UpdateTypeMessageUnpinned)...,
+	)
+	if len(ids) == 0 {
+		return nil
+	}
+
+	q := fmt.Sprintf(`
+	SELECT
+		p.update_id,
+		p.message_id
+	FROM messaging.pin_update p
+	WHERE p.chat_id = $1 
+		AND p.update_id IN %s
+	`, sqlArgsArr(2, len(ids)))
+
+	rows, err := db.Query(ctx, q, append([]any{chatID}, idsToAny(ids)...)...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	pins := make(map[int64]generic.PinContent)
+
+	for rows.Next() {
+		var (
+			updateID  int64
+			messageID int64
+		)
+
+		if err := rows.Scan(&updateID, &messageID); err != nil {
+			return err
+		}
+
+		pins[updateID] = generic.PinContent{
+			MessageID: messageID,
+		}
+	}
+
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	for i, update := range updates {
+		if update.UpdateType == domain.UpdateTypeMessagePinned || update.UpdateType == domain.UpdateTypeMessageUnpinned {
+			if info, ok := pins[update.UpdateID]; ok {
+				updates[i].Content.Pin = &info
+			} else {
+				panic("database is inconsistent!!!")
+			}
+		}
+	}
+
+	return nil
+}
+
 func (r *GenericUpdateRepository) fillUpdateDeleted(
 	ctx context.Context,
 	db storage.ExecQuerier,
@@ -883,6 +1352,90 @@ func (r *GenericUpdateRepository) fillSecretUpdates(
 	return nil
 }
 
+func (r *GenericUpdateRepository) fillServiceMessages(
+	ctx context.Context,
+	db storage.ExecQuerier,
+	chatID domain.ChatID,
+	updates []generic.Update,
+) error {
+	ids := make([]int64, 0)
+	for _, typ := range serviceMessageTypes {
+		ids = append(ids, updateTypesIDs(updates, typ)...)
+	}
+	if len(ids) == 0 {
+		return nil
+	}
+
+	q := fmt.Sprintf(`
+	SELECT
+		sm.update_id,
+		sm.members,
+		COALESCE(sm.name, ''),
+		COALESCE(sm.description, ''),
+		COALESCE(sm.photo, ''),
+		sm.expiration_seconds
+	FROM messaging.service_message_update sm
+	WHERE sm.chat_id = $1
+		AND sm.update_id IN %s
+	`, sqlArgsArr(2, len(ids)))
+
+	rows, err := db.Query(ctx, q, append([]any{chatID}, idsToAny(ids)...)...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	type serviceMessageRow struct {
+		members     []uuid.UUID
+		name        string
+		description string
+		photo       string
+		expiration  *int64
+	}
+	serviceMessages := make(map[int64]serviceMessageRow)
+
+	for rows.Next() {
+		var (
+			updateID int64
+			row      serviceMessageRow
+		)
+
+		if err := rows.Scan(&updateID, &row.members, &row.name, &row.description, &row.photo, &row.expiration); err != nil {
+			return err
+		}
+
+		serviceMessages[updateID] = row
+	}
+
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	for i, update := range updates {
+		if !slices.Contains(serviceMessageTypes, update.UpdateType) {
+			continue
+		}
+		row, ok := serviceMessages[update.UpdateID]
+		if !ok {
+			panic("database is inconsistent!!!")
+		}
+		content := generic.NewServiceMessageContent(
+			update.UpdateType, row.members, row.name, row.description, row.photo, row.expiration,
+		)
+		updates[i].Content.Service = &content
+	}
+
+	return nil
+}
+
+var serviceMessageTypes = []string{
+	domain.UpdateTypeMemberAdded,
+	domain.UpdateTypeMemberRemoved,
+	domain.UpdateTypeInfoChanged,
+	domain.UpdateTypePhotoChanged,
+	domain.UpdateTypeExpirationChanged,
+}
+
 // Helper function to convert []int64 to []any for SQL parameters
 func idsToAny(ids []int64) []any {
 	result := make([]any, len(ids))
@@ -0,0 +1,167 @@
+package update
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/chakchat/chakchat-backend/messaging-service/internal/application/generic"
+	"github.com/chakchat/chakchat-backend/messaging-service/internal/application/storage/repository"
+	"github.com/chakchat/chakchat-backend/messaging-service/internal/domain"
+	"github.com/chakchat/chakchat-backend/messaging-service/internal/domain/group"
+	"github.com/chakchat/chakchat-backend/messaging-service/internal/infrastructure/postgres/chat"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/stretchr/testify/require"
+)
+
+// The database is recreated from the migrations, so it must be a disposable one
+const EnvTestPostgresDSN = "MESSAGING_TEST_POSTGRES_DSN"
+
+func TestHistoryHiddenFromNewMember(t *testing.T) {
+	ctx := context.Background()
+	db := newTestDB(t)
+
+	var (
+		groupRepo   = chat.NewGroupChatRepository()
+		topicRepo   = chat.NewTopicRepository()
+		updateRepo  = NewUpdateRepository()
+		genericRepo = NewGenericUpdateRepository()
+		searchRepo  = NewSearchRepository()
+	)
+
+	owner := domain.UserID(uuid.New())
+	member := domain.UserID(uuid.New())
+
+	g, err := group.NewGroupChat(owner, []domain.UserID{owner}, "Group")
+	require.NoError(t, err)
+	require.NoError(t, g.SetHistoryVisibility(owner, true))
+	_, err = groupRepo.Create(ctx, db, g)
+	require.NoError(t, err)
+
+	topic, err := g.NewTopic(owner, "Topic", "")
+	require.NoError(t, err)
+	_, err = topicRepo.Create(ctx, db, topic)
+	require.NoError(t, err)
+
+	send := func(c domain.Chatter, text string) domain.UpdateID {
+		msg, err := domain.NewTextMessage(c, owner, text, nil, nil)
+		require.NoError(t, err)
+		msg, err = updateRepo.CreateTextMessage(ctx, db, msg)
+		require.NoError(t, err)
+		return msg.UpdateID
+	}
+
+	oldGroupMsg := send(g, "old group plan")
+	oldTopicMsg := send(topic, "old topic plan")
+
+	require.NoError(t, g.AddMember(owner, member))
+	_, err = groupRepo.Update(ctx, db, g)
+	require.NoError(t, err)
+
+	newGroupMsg := send(g, "new group plan")
+	newTopicMsg := send(topic, "new topic plan")
+
+	streams := []struct {
+		name   string
+		id     domain.ChatID
+		oldMsg domain.UpdateID
+		newMsg domain.UpdateID
+	}{
+		{name: "Group", id: g.ID, oldMsg: oldGroupMsg, newMsg: newGroupMsg},
+		{name: "Topic", id: topic.ID, oldMsg: oldTopicMsg, newMsg: newTopicMsg},
+	}
+	for _, s := range streams {
+		t.Run(s.name, func(t *testing.T) {
+			updates, err := genericRepo.GetRange(ctx, db, member, s.id, 0, s.newMsg)
+			require.NoError(t, err)
+			require.Equal(t, []int64{int64(s.newMsg)}, updateIDs(updates))
+
+			updates, err = genericRepo.FetchLast(ctx, db, member, s.id)
+			require.NoError(t, err)
+			require.Equal(t, []int64{int64(s.newMsg)}, updateIDs(updates))
+
+			_, err = genericRepo.Get(ctx, db, member, s.id, s.oldMsg)
+			require.Error(t, err)
+
+			// The ones who were in the group see the whole history
+			_, err = genericRepo.Get(ctx, db, owner, s.id, s.oldMsg)
+			require.NoError(t, err)
+		})
+
+		// Hidden messages can't be forwarded, replied to, pinned or reacted to
+		t.Run(s.name+"Find", func(t *testing.T) {
+			_, err := updateRepo.FindTextMessage(ctx, db, s.id, s.oldMsg, member)
+			require.ErrorIs(t, err, repository.ErrNotFound)
+
+			_, err = updateRepo.FindGenericMessage(ctx, db, s.id, s.oldMsg, member)
+			require.ErrorIs(t, err, repository.ErrNotFound)
+
+			_, err = updateRepo.FindTextMessage(ctx, db, s.id, s.newMsg, member)
+			require.NoError(t, err)
+
+			_, err = updateRepo.FindTextMessage(ctx, db, s.id, s.oldMsg, owner)
+			require.NoError(t, err)
+		})
+	}
+
+	t.Run("Search", func(t *testing.T) {
+		found, err := searchRepo.SearchMessages(ctx, db, member, domain.SearchQuery("plan"),
+			repository.SearchFilter{Limit: 10})
+		require.NoError(t, err)
+		require.Len(t, found, 2)
+
+		texts := make([]string, 0, len(found))
+		for _, m := range found {
+			require.Equal(t, uuid.UUID(g.ID), m.ChatID)
+			texts = append(texts, m.Content.TextMessage.Text)
+		}
+		require.ElementsMatch(t, []string{"new group plan", "new topic plan"}, texts)
+
+		found, err = searchRepo.SearchMessages(ctx, db, owner, domain.SearchQuery("plan"),
+			repository.SearchFilter{Limit: 10})
+		require.NoError(t, err)
+		require.Len(t, found, 4)
+	})
+}
+
+// newTestDB applies the migrations to the database from EnvTestPostgresDSN.
+// The test is skipped if it is not set.
+func newTestDB(t *testing.T) *pgxpool.Pool {
+	t.Helper()
+
+	dsn := os.Getenv(EnvTestPostgresDSN)
+	if dsn == "" {
+		t.Skipf("%s is not set", EnvTestPostgresDSN)
+	}
+
+	ctx := context.Background()
+	db, err := pgxpool.New(ctx, dsn)
+	require.NoError(t, err)
+	t.Cleanup(db.Close)
+
+	_, err = db.Exec(ctx, `DROP SCHEMA IF EXISTS messaging CASCADE`)
+	require.NoError(t, err)
+
+	// Versions are zero-padded, so the files are sorted in the order of versions
+	migrations, err := filepath.Glob("../../../../migrations/V*.sql")
+	require.NoError(t, err)
+	require.NotEmpty(t, migrations)
+	for _, path := range migrations {
+		sql, err := os.ReadFile(path)
+		require.NoError(t, err)
+		_, err = db.Exec(ctx, string(sql))
+		require.NoError(t, err, path)
+	}
+
+	return db
+}
+
+func updateIDs(updates []generic.Update) []int64 {
+	ids := make([]int64, 0, len(updates))
+	for _, u := range updates {
+		ids = append(ids, u.UpdateID)
+	}
+	return ids
+}
@@ -0,0 +1,57 @@
+package update
+
+import (
+	"context"
+
+	"github.com/chakchat/chakchat-backend/messaging-service/internal/application/storage"
+	"github.com/chakchat/chakchat-backend/messaging-service/internal/domain"
+	"github.com/google/uuid"
+)
+
+type MentionRepository struct{}
+
+func NewMentionRepository() *MentionRepository {
+	return &MentionRepository{}
+}
+
+func (r *MentionRepository) CountUnread(
+	ctx context.Context, db storage.ExecQuerier, chatID domain.ChatID, userID domain.UserID,
+) (int, error) {
+	q := `
+	SELECT COUNT(DISTINCT m.update_id)
+	FROM messaging.mention m
+		JOIN messaging.update u ON u.chat_id = m.chat_id AND u.update_id = m.update_id
+	WHERE m.chat_id = $1
+		AND (m.user_id = $2 OR m.user_id IS NULL)
+		AND u.sender_id <> $2
+		AND m.update_id > COALESCE((
+			SELECT r.read_update_id 
+			FROM messaging.mention_read r
+			WHERE r.chat_id = $1 AND r.user_id = $2
+		), 0)
+		AND NOT EXISTS (
+			SELECT 1 
+			FROM messaging.update_deleted_update ud
+				JOIN messaging.update du ON du.chat_id = ud.chat_id AND du.update_id = ud.update_id
+			WHERE ud.chat_id = m.chat_id 
+				AND ud.deleted_update_id = m.update_id
+				AND (ud.mode = 'for_all' OR du.sender_id = $2)
+		)`
+
+	var count int
+	err := db.QueryRow(ctx, q, chatID, uuid.UUID(userID)).Scan(&count)
+	return count, err
+}
+
+func (r *MentionRepository) MarkRead(
+	ctx context.Context, db storage.ExecQuerier, chatID domain.ChatID, userID domain.UserID, until domain.UpdateID,
+) error {
+	q := `
+	INSERT INTO messaging.mention_read (chat_id, user_id, read_update_id)
+	VALUES ($1, $2, $3)
+	ON CONFLICT (chat_id, user_id) DO UPDATE
+	SET read_update_id = GREATEST(messaging.mention_read.read_update_id, EXCLUDED.read_update_id)`
+
+	_, err := db.Exec(ctx, q, chatID, uuid.UUID(userID), int64(until))
+	return err
+}
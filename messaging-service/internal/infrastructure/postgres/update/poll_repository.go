@@ -0,0 +1,291 @@
+package update
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/chakchat/chakchat-backend/messaging-service/internal/application/storage"
+	"github.com/chakchat/chakchat-backend/messaging-service/internal/application/storage/repository"
+	"github.com/chakchat/chakchat-backend/messaging-service/internal/domain"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+type PollRepository struct {
+	// Used to get deletions of polls
+	updateRepo *UpdateRepository
+}
+
+func NewPollRepository() *PollRepository {
+	return &PollRepository{
+		updateRepo: NewUpdateRepository(),
+	}
+}
+
+func (r *PollRepository) CreatePoll(
+	ctx context.Context, db storage.ExecQuerier, poll *domain.Poll,
+) (*domain.Poll, error) {
+	// Insert base update
+	q1 := `
+	INSERT INTO messaging.update (chat_id, update_id, update_type, created_at, sender_id, thread_root_id)
+	VALUES ($1, $2, 'poll', $3, $4, $5)
+	RETURNING update_id`
+
+	now := time.Now()
+	var updateID int64
+	err := db.QueryRow(ctx, q1,
+		poll.ChatID,
+		poll.UpdateID,
+		now,
+		uuid.UUID(poll.SenderID),
+		poll.ThreadRootID,
+	).Scan(&updateID)
+	if err != nil {
+		return nil, err
+	}
+	poll.CreatedAt = domain.Timestamp(now.Unix())
+
+	// Insert poll specific data
+	q2 := `
+	INSERT INTO messaging.poll_update (
+		chat_id, update_id, question, multiple_choice, anonymous, closes_at, closed, reply_to_id
+	)
+	VALUES ($1, $2, $3, $4, $5, $6, $7, $8)`
+
+	_, err = db.Exec(ctx, q2,
+		poll.ChatID,
+		updateID,
+		poll.Question,
+		poll.MultipleChoice,
+		poll.Anonymous,
+		timestampPtrToTime(poll.ClosesAt),
+		poll.Closed,
+		poll.ReplyTo,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	q3 := `
+	INSERT INTO messaging.poll_option (chat_id, poll_id, option_index, text)
+	VALUES ($1, $2, $3, $4)`
+
+	for i, option := range poll.Options {
+		if _, err := db.Exec(ctx, q3, poll.ChatID, updateID, i, option.Text); err != nil {
+			return nil, err
+		}
+	}
+
+	poll.UpdateID = domain.UpdateID(updateID)
+
+	if err := r.storeVotes(ctx, db, poll); err != nil {
+		return nil, err
+	}
+
+	return poll, nil
+}
+
+func (r *PollRepository) FindPoll(
+	ctx context.Context, db storage.ExecQuerier, chatID domain.ChatID, updateID domain.UpdateID,
+) (*domain.Poll, error) {
+	q := `
+	SELECT 
+		u.created_at, 
+		u.sender_id,
+		u.thread_root_id,
+		p.question,
+		p.multiple_choice,
+		p.anonymous,
+		p.closes_at,
+		p.closed,
+		p.reply_to_id
+	FROM messaging.update u
+	JOIN messaging.poll_update p ON u.chat_id = p.chat_id AND u.update_id = p.update_id
+	WHERE u.chat_id = $1 AND u.update_id = $2
+	FOR UPDATE OF p`
+
+	var (
+		createdAt      time.Time
+		senderID       uuid.UUID
+		threadRootID   *domain.UpdateID
+		question       string
+		multipleChoice bool
+		anonymous      bool
+		closesAt       *time.Time
+		closed         bool
+		replyToID      *domain.UpdateID
+	)
+
+	err := db.QueryRow(ctx, q, chatID, updateID).Scan(
+		&createdAt,
+		&senderID,
+		&threadRootID,
+		&question,
+		&multipleChoice,
+		&anonymous,
+		&closesAt,
+		&closed,
+		&replyToID,
+	)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, repository.ErrNotFound
+		}
+		return nil, err
+	}
+
+	deletions, err := r.updateRepo.getDeletions(ctx, db, chatID, updateID)
+	if err != nil {
+		return nil, err
+	}
+
+	options, err := r.getOptions(ctx, db, chatID, updateID)
+	if err != nil {
+		return nil, err
+	}
+
+	var closesAtTimestamp *domain.Timestamp
+	if closesAt != nil {
+		ts := domain.Timestamp(closesAt.Unix())
+		closesAtTimestamp = &ts
+	}
+
+	poll := &domain.Poll{
+		Message: domain.Message{
+			Update: domain.Update{
+				UpdateID:     updateID,
+				ChatID:       chatID,
+				SenderID:     domain.UserID(senderID),
+				ThreadRootID: threadRootID,
+				CreatedAt:    domain.Timestamp(createdAt.Unix()),
+				Deleted:      deletions,
+			},
+			ReplyTo: replyToID,
+		},
+		PollSettings: domain.PollSettings{
+			MultipleChoice: multipleChoice,
+			Anonymous:      anonymous,
+			ClosesAt:       closesAtTimestamp,
+		},
+		Question: question,
+		Options:  options,
+		Closed:   closed,
+	}
+
+	return poll, nil
+}
+
+func (r *PollRepository) UpdatePoll(
+	ctx context.Context, db storage.ExecQuerier, poll *domain.Poll,
+) (*domain.Poll, error) {
+	q := `
+	UPDATE messaging.poll_update
+	SET closed = $3
+	WHERE chat_id = $1 AND update_id = $2`
+
+	_, err := db.Exec(ctx, q, poll.ChatID, poll.UpdateID, poll.Closed)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := r.storeVotes(ctx, db, poll); err != nil {
+		return nil, err
+	}
+
+	return poll, nil
+}
+
+func (r *PollRepository) getOptions(
+	ctx context.Context, db storage.ExecQuerier, chatID domain.ChatID, pollID domain.UpdateID,
+) ([]domain.PollOption, error) {
+	q := `
+	SELECT 
+		o.text,
+		ARRAY_REMOVE(ARRAY_AGG(v.user_id ORDER BY v.voted_at, v.user_id), NULL)
+	FROM messaging.poll_option o
+		LEFT JOIN messaging.poll_vote v 
+			ON v.chat_id = o.chat_id AND v.poll_id = o.poll_id AND v.option_index = o.option_index
+	WHERE o.chat_id = $1 AND o.poll_id = $2
+	GROUP BY o.option_index, o.text
+	ORDER BY o.option_index`
+
+	rows, err := db.Query(ctx, q, chatID, pollID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var options []domain.PollOption
+	for rows.Next() {
+		var (
+			text   string
+			voters []uuid.UUID
+		)
+		if err := rows.Scan(&text, &voters); err != nil {
+			return nil, err
+		}
+
+		option := domain.PollOption{
+			Text: text,
+		}
+		for _, voter := range voters {
+			option.Voters = append(option.Voters, domain.UserID(voter))
+		}
+		options = append(options, option)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return options, nil
+}
+
+// storeVotes makes stored votes match the poll.
+// Votes that haven't changed keep their time.
+func (r *PollRepository) storeVotes(
+	ctx context.Context, db storage.ExecQuerier, poll *domain.Poll,
+) error {
+	var (
+		optionIndexes []int16
+		voters        []uuid.UUID
+	)
+	for i, option := range poll.Options {
+		for _, voter := range option.Voters {
+			optionIndexes = append(optionIndexes, int16(i))
+			voters = append(voters, uuid.UUID(voter))
+		}
+	}
+
+	q1 := `
+	DELETE FROM messaging.poll_vote v
+	WHERE v.chat_id = $1 
+		AND v.poll_id = $2
+		AND NOT EXISTS (
+			SELECT 1 
+			FROM UNNEST($3::SMALLINT[], $4::UUID[]) AS n(option_index, user_id)
+			WHERE n.option_index = v.option_index AND n.user_id = v.user_id
+		)`
+
+	if _, err := db.Exec(ctx, q1, poll.ChatID, poll.UpdateID, optionIndexes, voters); err != nil {
+		return err
+	}
+
+	q2 := `
+	INSERT INTO messaging.poll_vote (chat_id, poll_id, option_index, user_id)
+	SELECT $1, $2, n.option_index, n.user_id
+	FROM UNNEST($3::SMALLINT[], $4::UUID[]) AS n(option_index, user_id)
+	ON CONFLICT DO NOTHING`
+
+	_, err := db.Exec(ctx, q2, poll.ChatID, poll.UpdateID, optionIndexes, voters)
+	return err
+}
+
+func timestampPtrToTime(ts *domain.Timestamp) *time.Time {
+	if ts == nil {
+		return nil
+	}
+	t := ts.Time()
+	return &t
+}
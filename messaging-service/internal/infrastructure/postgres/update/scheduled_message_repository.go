@@ -0,0 +1,331 @@
+package update
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/chakchat/chakchat-backend/messaging-service/internal/application/storage"
+	"github.com/chakchat/chakchat-backend/messaging-service/internal/application/storage/repository"
+	"github.com/chakchat/chakchat-backend/messaging-service/internal/domain"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+const scheduledMessageColumns = `
+		scheduled_id,
+		chat_id,
+		sender_id,
+		text,
+		file_id,
+		reply_to_id,
+		send_at,
+		created_at`
+
+type ScheduledMessageRepository struct{}
+
+func NewScheduledMessageRepository() *ScheduledMessageRepository {
+	return &ScheduledMessageRepository{}
+}
+
+func (r *ScheduledMessageRepository) Create(
+	ctx context.Context, db storage.ExecQuerier, msg *domain.ScheduledMessage,
+) (*domain.ScheduledMessage, error) {
+	q := `
+	INSERT INTO messaging.scheduled_message (chat_id, sender_id, text, file_id, reply_to_id, send_at, created_at)
+	VALUES ($1, $2, $3, $4, $5, $6, $7)
+	RETURNING scheduled_id`
+
+	now := time.Now()
+	var id int64
+	err := db.QueryRow(ctx, q,
+		msg.ChatID,
+		uuid.UUID(msg.SenderID),
+		textOrNil(msg),
+		msg.FileID,
+		msg.ReplyTo,
+		msg.SendAt.Time(),
+		now,
+	).Scan(&id)
+	if err != nil {
+		return nil, err
+	}
+
+	msg.ID = domain.ScheduledMessageID(id)
+	msg.CreatedAt = domain.Timestamp(now.Unix())
+
+	if err := r.storeEntities(ctx, db, msg); err != nil {
+		return nil, err
+	}
+	return msg, nil
+}
+
+func (r *ScheduledMessageRepository) FindByID(
+	ctx context.Context, db storage.ExecQuerier, id domain.ScheduledMessageID,
+) (*domain.ScheduledMessage, error) {
+	q := `SELECT ` + scheduledMessageColumns + `
+	FROM messaging.scheduled_message
+	WHERE scheduled_id = $1`
+
+	msg, err := scanScheduledMessage(db.QueryRow(ctx, q, id))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, repository.ErrNotFound
+		}
+		return nil, err
+	}
+
+	if err := r.fillEntities(ctx, db, []*domain.ScheduledMessage{msg}); err != nil {
+		return nil, err
+	}
+	return msg, nil
+}
+
+func (r *ScheduledMessageRepository) Update(
+	ctx context.Context, db storage.ExecQuerier, msg *domain.ScheduledMessage,
+) (*domain.ScheduledMessage, error) {
+	q := `
+	UPDATE messaging.scheduled_message
+	SET text = $2, send_at = $3
+	WHERE scheduled_id = $1
+		AND (claimed_until IS NULL OR claimed_until <= now())`
+
+	tag, err := db.Exec(ctx, q, msg.ID, textOrNil(msg), msg.SendAt.Time())
+	if err != nil {
+		return nil, err
+	}
+	if tag.RowsAffected() == 0 {
+		return nil, repository.ErrScheduledClaimed
+	}
+
+	if err := r.storeEntities(ctx, db, msg); err != nil {
+		return nil, err
+	}
+	return msg, nil
+}
+
+func (r *ScheduledMessageRepository) Delete(
+	ctx context.Context, db storage.ExecQuerier, id domain.ScheduledMessageID,
+) error {
+	q := `
+	DELETE FROM messaging.scheduled_message
+	WHERE scheduled_id = $1
+		AND (claimed_until IS NULL OR claimed_until <= now())`
+
+	tag, err := db.Exec(ctx, q, id)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return repository.ErrScheduledClaimed
+	}
+	return nil
+}
+
+func (r *ScheduledMessageRepository) DeleteSent(
+	ctx context.Context, db storage.ExecQuerier, id domain.ScheduledMessageID,
+) error {
+	q := `DELETE FROM messaging.scheduled_message WHERE scheduled_id = $1`
+	_, err := db.Exec(ctx, q, id)
+	return err
+}
+
+func (r *ScheduledMessageRepository) GetByChatAndSender(
+	ctx context.Context, db storage.ExecQuerier, chatID domain.ChatID, senderID domain.UserID,
+) ([]*domain.ScheduledMessage, error) {
+	q := `SELECT ` + scheduledMessageColumns + `
+	FROM messaging.scheduled_message
+	WHERE chat_id = $1 AND sender_id = $2
+	ORDER BY send_at, scheduled_id`
+
+	rows, err := db.Query(ctx, q, chatID, uuid.UUID(senderID))
+	if err != nil {
+		return nil, err
+	}
+	msgs, err := collectScheduledMessages(rows)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := r.fillEntities(ctx, db, msgs); err != nil {
+		return nil, err
+	}
+	return msgs, nil
+}
+
+func (r *ScheduledMessageRepository) ClaimDue(
+	ctx context.Context, db storage.ExecQuerier, now time.Time, claimFor time.Duration, limit int,
+) ([]*domain.ScheduledMessage, error) {
+	q := `
+	UPDATE messaging.scheduled_message
+	SET claimed_until = $2
+	WHERE scheduled_id IN (
+		SELECT scheduled_id
+		FROM messaging.scheduled_message
+		WHERE send_at <= $1
+			AND (claimed_until IS NULL OR claimed_until <= $1)
+		ORDER BY send_at, scheduled_id
+		LIMIT $3
+		FOR UPDATE SKIP LOCKED
+	)
+	RETURNING ` + scheduledMessageColumns
+
+	rows, err := db.Query(ctx, q, now, now.Add(claimFor), limit)
+	if err != nil {
+		return nil, err
+	}
+	msgs, err := collectScheduledMessages(rows)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := r.fillEntities(ctx, db, msgs); err != nil {
+		return nil, err
+	}
+	return msgs, nil
+}
+
+// storeEntities replaces stored entities of the scheduled message
+func (r *ScheduledMessageRepository) storeEntities(
+	ctx context.Context, db storage.ExecQuerier, msg *domain.ScheduledMessage,
+) error {
+	q1 := `DELETE FROM messaging.scheduled_text_entity WHERE scheduled_id = $1`
+	if _, err := db.Exec(ctx, q1, msg.ID); err != nil {
+		return err
+	}
+	if len(msg.Entities) == 0 {
+		return nil
+	}
+
+	var (
+		offsets = make([]int, len(msg.Entities))
+		lengths = make([]int, len(msg.Entities))
+		types   = make([]string, len(msg.Entities))
+		urls    = make([]*string, len(msg.Entities))
+	)
+	for i, entity := range msg.Entities {
+		offsets[i] = entity.Offset
+		lengths[i] = entity.Length
+		types[i] = string(entity.Type)
+		if entity.URL != "" {
+			url := entity.URL
+			urls[i] = &url
+		}
+	}
+
+	q2 := `
+	INSERT INTO messaging.scheduled_text_entity (scheduled_id, entity_offset, entity_length, entity_type, url)
+	SELECT $1, e.entity_offset, e.entity_length, e.entity_type::messaging.text_entity_type, e.url
+	FROM UNNEST($2::INT[], $3::INT[], $4::TEXT[], $5::TEXT[]) AS e(entity_offset, entity_length, entity_type, url)`
+
+	_, err := db.Exec(ctx, q2, msg.ID, offsets, lengths, types, urls)
+	return err
+}
+
+// fillEntities loads entities of all the messages in one query
+func (r *ScheduledMessageRepository) fillEntities(
+	ctx context.Context, db storage.ExecQuerier, msgs []*domain.ScheduledMessage,
+) error {
+	if len(msgs) == 0 {
+		return nil
+	}
+
+	byID := make(map[domain.ScheduledMessageID]*domain.ScheduledMessage, len(msgs))
+	ids := make([]int64, len(msgs))
+	for i, msg := range msgs {
+		byID[msg.ID] = msg
+		ids[i] = int64(msg.ID)
+	}
+
+	q := `
+	SELECT scheduled_id, entity_offset, entity_length, entity_type, url
+	FROM messaging.scheduled_text_entity
+	WHERE scheduled_id = ANY($1)
+	ORDER BY scheduled_id, entity_offset`
+
+	rows, err := db.Query(ctx, q, ids)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			id         int64
+			entity     domain.TextEntity
+			entityType string
+			url        *string
+		)
+		if err := rows.Scan(&id, &entity.Offset, &entity.Length, &entityType, &url); err != nil {
+			return err
+		}
+		entity.Type = domain.TextEntityType(entityType)
+		if url != nil {
+			entity.URL = *url
+		}
+		msg := byID[domain.ScheduledMessageID(id)]
+		msg.Entities = append(msg.Entities, entity)
+	}
+
+	return rows.Err()
+}
+
+func collectScheduledMessages(rows pgx.Rows) ([]*domain.ScheduledMessage, error) {
+	defer rows.Close()
+
+	res := make([]*domain.ScheduledMessage, 0)
+	for rows.Next() {
+		msg, err := scanScheduledMessage(rows)
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, msg)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+func scanScheduledMessage(row pgx.Row) (*domain.ScheduledMessage, error) {
+	var (
+		id        int64
+		chatID    uuid.UUID
+		senderID  uuid.UUID
+		text      *string
+		fileID    *uuid.UUID
+		replyToID *domain.UpdateID
+		sendAt    time.Time
+		createdAt time.Time
+	)
+
+	err := row.Scan(&id, &chatID, &senderID, &text, &fileID, &replyToID, &sendAt, &createdAt)
+	if err != nil {
+		return nil, err
+	}
+
+	msg := &domain.ScheduledMessage{
+		ID:        domain.ScheduledMessageID(id),
+		ChatID:    domain.ChatID(chatID),
+		SenderID:  domain.UserID(senderID),
+		FileID:    fileID,
+		ReplyTo:   replyToID,
+		SendAt:    domain.Timestamp(sendAt.Unix()),
+		CreatedAt: domain.Timestamp(createdAt.Unix()),
+	}
+	if text != nil {
+		msg.Text = *text
+	}
+
+	return msg, nil
+}
+
+// Text is NULL for file messages
+func textOrNil(msg *domain.ScheduledMessage) *string {
+	if msg.IsFile() {
+		return nil
+	}
+	return &msg.Text
+}
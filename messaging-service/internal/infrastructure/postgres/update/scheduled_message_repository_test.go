@@ -0,0 +1,53 @@
+package update
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/chakchat/chakchat-backend/messaging-service/internal/application/storage/repository"
+	"github.com/chakchat/chakchat-backend/messaging-service/internal/domain"
+	"github.com/chakchat/chakchat-backend/messaging-service/internal/domain/group"
+	"github.com/chakchat/chakchat-backend/messaging-service/internal/infrastructure/postgres/chat"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+func TestScheduledMessageClaimedNotChanged(t *testing.T) {
+	ctx := context.Background()
+	db := newTestDB(t)
+
+	var (
+		groupRepo     = chat.NewGroupChatRepository()
+		scheduledRepo = NewScheduledMessageRepository()
+	)
+
+	owner := domain.UserID(uuid.New())
+	g, err := group.NewGroupChat(owner, []domain.UserID{owner}, "Group")
+	require.NoError(t, err)
+	_, err = groupRepo.Create(ctx, db, g)
+	require.NoError(t, err)
+
+	sendAt := time.Now().Add(time.Hour)
+	msg, err := domain.NewScheduledTextMessage(g, owner, "text", nil, nil, domain.Timestamp(sendAt.Unix()))
+	require.NoError(t, err)
+	msg, err = scheduledRepo.Create(ctx, db, msg)
+	require.NoError(t, err)
+
+	_, err = scheduledRepo.Update(ctx, db, msg)
+	require.NoError(t, err)
+
+	claimed, err := scheduledRepo.ClaimDue(ctx, db, sendAt.Add(time.Second), time.Minute, 10)
+	require.NoError(t, err)
+	require.Len(t, claimed, 1)
+
+	_, err = scheduledRepo.Update(ctx, db, msg)
+	require.ErrorIs(t, err, repository.ErrScheduledClaimed)
+
+	err = scheduledRepo.Delete(ctx, db, msg.ID)
+	require.ErrorIs(t, err, repository.ErrScheduledClaimed)
+
+	require.NoError(t, scheduledRepo.DeleteSent(ctx, db, msg.ID))
+	_, err = scheduledRepo.FindByID(ctx, db, msg.ID)
+	require.ErrorIs(t, err, repository.ErrNotFound)
+}
@@ -0,0 +1,183 @@
+package update
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/chakchat/chakchat-backend/messaging-service/internal/application/generic"
+	"github.com/chakchat/chakchat-backend/messaging-service/internal/application/storage"
+	"github.com/chakchat/chakchat-backend/messaging-service/internal/application/storage/repository"
+	"github.com/chakchat/chakchat-backend/messaging-service/internal/domain"
+	"github.com/google/uuid"
+)
+
+// Private use characters can't be typed by users in practice,
+// so they are safe to mark highlighted words in ts_headline() output.
+const (
+	highlightStart = '\uE000'
+	highlightStop  = '\uE001'
+)
+
+type SearchRepository struct {
+	// Fills messages content the same way as GetRange() does
+	updateRepo *GenericUpdateRepository
+}
+
+func NewSearchRepository() *SearchRepository {
+	return &SearchRepository{
+		updateRepo: NewGenericUpdateRepository(),
+	}
+}
+
+func (r *SearchRepository) SearchMessages(
+	ctx context.Context,
+	db storage.ExecQuerier,
+	visibleTo domain.UserID,
+	query domain.SearchQuery,
+	filter repository.SearchFilter,
+) ([]generic.FoundMessage, error) {
+	// Full-text search finds word forms while ILIKE finds substrings (e.g. parts of words).
+	// The secret chats check is redundant since they have no text messages but it is explicit.
+	// Topic messages are stored in the topic stream, so membership is checked in the parent group.
+	// The history hidden from the member is checked in the stream of the message.
+	q := `
+	SELECT
+		COALESCE(t.chat_id, u.chat_id),
+		t.topic_id,
+		u.update_id,
+		u.created_at,
+		u.sender_id,
+		u.thread_root_id,
+		ts_headline('messaging.message_search', tm.text, tsq.query, $3)
+	FROM messaging.text_message_update tm
+		CROSS JOIN websearch_to_tsquery('messaging.message_search', $2) AS tsq(query)
+		JOIN messaging.update u ON u.chat_id = tm.chat_id AND u.update_id = tm.update_id
+		LEFT JOIN messaging.group_topic t ON t.topic_id = tm.chat_id
+		JOIN messaging.membership m ON m.chat_id = COALESCE(t.chat_id, tm.chat_id) AND m.user_id = $1
+		JOIN messaging.chat c ON c.chat_id = COALESCE(t.chat_id, tm.chat_id)
+	WHERE c.chat_type NOT IN ('secret_personal', 'secret_group')
+		AND (tm.text_search @@ tsq.query OR tm.text ILIKE $4)
+		AND u.update_id >= messaging.get_visible_from_update_id(tm.chat_id, $1)
+		AND NOT EXISTS (
+			SELECT 1
+			FROM messaging.update_deleted_update ud
+				JOIN messaging.update du ON du.chat_id = ud.chat_id AND du.update_id = ud.update_id
+			WHERE ud.chat_id = tm.chat_id
+				AND ud.deleted_update_id = tm.update_id
+				AND (ud.mode = 'for_all' OR du.sender_id = $1)
+		)
+		AND ($5::UUID IS NULL OR COALESCE(t.chat_id, tm.chat_id) = $5)
+		AND ($6::UUID IS NULL OR u.sender_id = $6)
+		AND ($7::TIMESTAMPTZ IS NULL OR u.created_at >= $7)
+		AND ($8::TIMESTAMPTZ IS NULL OR u.created_at < $8)
+	ORDER BY u.created_at DESC, u.chat_id, u.update_id DESC
+	LIMIT $9 OFFSET $10`
+
+	headlineOpts := "HighlightAll=true, StartSel=" + string(highlightStart) + ", StopSel=" + string(highlightStop)
+
+	var chatID, senderID *uuid.UUID
+	if filter.ChatID != nil {
+		id := uuid.UUID(*filter.ChatID)
+		chatID = &id
+	}
+	if filter.SenderID != nil {
+		id := uuid.UUID(*filter.SenderID)
+		senderID = &id
+	}
+
+	rows, err := db.Query(ctx, q,
+		visibleTo, string(query), headlineOpts, "%"+escapeLike(string(query))+"%",
+		chatID, senderID, filter.Since, filter.Until,
+		filter.Limit, filter.Offset,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	found := make([]generic.FoundMessage, 0, filter.Limit)
+	headlines := make([]string, 0, filter.Limit)
+	for rows.Next() {
+		var (
+			chatID       uuid.UUID
+			topicID      *uuid.UUID
+			updateID     int64
+			createdAt    time.Time
+			senderID     uuid.UUID
+			threadRootID *int64
+			headline     string
+		)
+		if err := rows.Scan(&chatID, &topicID, &updateID, &createdAt, &senderID, &threadRootID, &headline); err != nil {
+			return nil, err
+		}
+		found = append(found, generic.FoundMessage{
+			Update: generic.Update{
+				UpdateID:     updateID,
+				ChatID:       chatID,
+				SenderID:     senderID,
+				UpdateType:   domain.UpdateTypeTextMessage,
+				ThreadRootID: threadRootID,
+				CreatedAt:    createdAt.Unix(),
+			},
+			TopicID: topicID,
+		})
+		headlines = append(headlines, headline)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	if err := r.fillContent(ctx, db, found); err != nil {
+		return nil, err
+	}
+
+	for i := range found {
+		text := found[i].Content.TextMessage.Text
+		headlineText, wordHighlights := generic.ParseHighlights(headlines[i], highlightStart, highlightStop)
+		// ts_headline() rebuilds the text from tokens, so offsets are valid only if nothing is lost
+		if headlineText != text {
+			wordHighlights = nil
+		}
+		found[i].Highlights = generic.MergeHighlights(
+			wordHighlights,
+			generic.SubstringHighlights(text, string(query)),
+		)
+	}
+
+	return found, nil
+}
+
+// fillContent fills text messages stream by stream because content queries are bound to a stream.
+// Topic messages are stored in the stream of the topic rather than of the group.
+func (r *SearchRepository) fillContent(ctx context.Context, db storage.ExecQuerier, found []generic.FoundMessage) error {
+	byChat := make(map[uuid.UUID][]int)
+	for i := range found {
+		streamID := found[i].ChatID
+		if found[i].TopicID != nil {
+			streamID = *found[i].TopicID
+		}
+		byChat[streamID] = append(byChat[streamID], i)
+	}
+
+	for chatID, indexes := range byChat {
+		updates := make([]generic.Update, 0, len(indexes))
+		for _, i := range indexes {
+			updates = append(updates, found[i].Update)
+		}
+		if err := r.updateRepo.fillTextMessages(ctx, db, domain.ChatID(chatID), updates); err != nil {
+			return err
+		}
+		if err := r.updateRepo.fillThreads(ctx, db, domain.ChatID(chatID), updates); err != nil {
+			return err
+		}
+		for j, i := range indexes {
+			found[i].Update = updates[j]
+		}
+	}
+	return nil
+}
+
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(s)
+}
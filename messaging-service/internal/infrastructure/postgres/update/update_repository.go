@@ -19,30 +19,35 @@ func NewUpdateRepository() *UpdateRepository {
 }
 
 func (r *UpdateRepository) FindGenericMessage(
-	ctx context.Context, db storage.ExecQuerier, chatID domain.ChatID, updateID domain.UpdateID,
+	ctx context.Context, db storage.ExecQuerier, chatID domain.ChatID, updateID domain.UpdateID, visibleTo domain.UserID,
 ) (*domain.Message, error) {
 	q := `
 	SELECT 
 		u.update_type,
 		u.created_at,
 		u.sender_id,
-		COALESCE(tm.reply_to_id, fm.reply_to_id)
+		u.thread_root_id,
+		COALESCE(tm.reply_to_id, fm.reply_to_id, pu.reply_to_id)
 	FROM messaging.update u
 		LEFT JOIN messaging.text_message_update tm ON tm.chat_id = u.chat_id AND tm.update_id = u.update_id
 		LEFT JOIN messaging.file_message_update fm ON fm.chat_id = u.chat_id AND fm.update_id = u.update_id
-	WHERE u.chat_id = $1 AND u.update_id = $2`
+		LEFT JOIN messaging.poll_update pu ON pu.chat_id = u.chat_id AND pu.update_id = u.update_id
+	WHERE u.chat_id = $1 AND u.update_id = $2
+		AND u.update_id >= messaging.get_visible_from_update_id($1, $3)`
 
 	var (
-		updateType string
-		createdAt  time.Time
-		senderID   uuid.UUID
-		replyToID  *int64
+		updateType   string
+		createdAt    time.Time
+		senderID     uuid.UUID
+		threadRootID *domain.UpdateID
+		replyToID    *int64
 	)
 
-	err := db.QueryRow(ctx, q, chatID, updateID).Scan(
+	err := db.QueryRow(ctx, q, chatID, updateID, visibleTo).Scan(
 		&updateType,
 		&createdAt,
 		&senderID,
+		&threadRootID,
 		&replyToID,
 	)
 	if err != nil {
@@ -65,11 +70,12 @@ func (r *UpdateRepository) FindGenericMessage(
 
 	message := &domain.Message{
 		Update: domain.Update{
-			UpdateID:  updateID,
-			ChatID:    chatID,
-			SenderID:  domain.UserID(senderID),
-			CreatedAt: domain.Timestamp(createdAt.Unix()),
-			Deleted:   deletions,
+			UpdateID:     updateID,
+			ChatID:       chatID,
+			SenderID:     domain.UserID(senderID),
+			ThreadRootID: threadRootID,
+			CreatedAt:    domain.Timestamp(createdAt.Unix()),
+			Deleted:      deletions,
 		},
 		ReplyTo:   replyToUpdateID,
 		Forwarded: false, // This isn't in the schema, would need additional data
@@ -96,8 +102,8 @@ func (r *UpdateRepository) CreateUpdateDeleted(
 	ctx context.Context, db storage.ExecQuerier, deleted *domain.UpdateDeleted,
 ) (*domain.UpdateDeleted, error) {
 	q1 := `
-	INSERT INTO messaging.update (chat_id, update_id, update_type, created_at, sender_id)
-	VALUES ($1, $2, 'update_deleted', $3, $4)
+	INSERT INTO messaging.update (chat_id, update_id, update_type, created_at, sender_id, thread_root_id)
+	VALUES ($1, $2, 'update_deleted', $3, $4, $5)
 	RETURNING update_id`
 
 	now := time.Now()
@@ -107,6 +113,7 @@ func (r *UpdateRepository) CreateUpdateDeleted(
 		deleted.UpdateID,
 		now,
 		uuid.UUID(deleted.SenderID),
+		deleted.ThreadRootID,
 	).Scan(&updateID)
 	if err != nil {
 		return nil, err
@@ -135,8 +142,8 @@ func (r *UpdateRepository) CreateTextMessage(
 	ctx context.Context, db storage.ExecQuerier, msg *domain.TextMessage,
 ) (*domain.TextMessage, error) {
 	q1 := `
-	INSERT INTO messaging.update (chat_id, update_id, update_type, created_at, sender_id)
-	VALUES ($1, $2, 'text_message', $3, $4)
+	INSERT INTO messaging.update (chat_id, update_id, update_type, created_at, sender_id, thread_root_id)
+	VALUES ($1, $2, 'text_message', $3, $4, $5)
 	RETURNING update_id`
 
 	now := time.Now()
@@ -146,6 +153,7 @@ func (r *UpdateRepository) CreateTextMessage(
 		msg.UpdateID,
 		now,
 		uuid.UUID(msg.SenderID),
+		msg.ThreadRootID,
 	).Scan(&updateID)
 	if err != nil {
 		return nil, err
@@ -174,6 +182,13 @@ func (r *UpdateRepository) CreateTextMessage(
 	}
 
 	msg.UpdateID = domain.UpdateID(updateID)
+
+	if err := r.storeEntities(ctx, db, msg.ChatID, msg.UpdateID, msg.Entities); err != nil {
+		return nil, err
+	}
+	if err := r.storeMentions(ctx, db, msg); err != nil {
+		return nil, err
+	}
 	return msg, nil
 }
 
@@ -182,8 +197,8 @@ func (r *UpdateRepository) CreateTextMessageEdited(
 ) (*domain.TextMessageEdited, error) {
 	// Insert base update
 	q1 := `
-	INSERT INTO messaging.update (chat_id, update_id, update_type, created_at, sender_id)
-	VALUES ($1, $2, 'text_message_edited', $3, $4)
+	INSERT INTO messaging.update (chat_id, update_id, update_type, created_at, sender_id, thread_root_id)
+	VALUES ($1, $2, 'text_message_edited', $3, $4, $5)
 	RETURNING update_id`
 
 	now := time.Now()
@@ -193,6 +208,7 @@ func (r *UpdateRepository) CreateTextMessageEdited(
 		edited.UpdateID,
 		now,
 		uuid.UUID(edited.SenderID),
+		edited.ThreadRootID,
 	).Scan(&updateID)
 	if err != nil {
 		return nil, err
@@ -215,14 +231,18 @@ func (r *UpdateRepository) CreateTextMessageEdited(
 	}
 
 	edited.UpdateID = domain.UpdateID(updateID)
+
+	if err := r.storeEntities(ctx, db, edited.ChatID, edited.UpdateID, edited.NewEntities); err != nil {
+		return nil, err
+	}
 	return edited, nil
 }
 
 func (r *UpdateRepository) FindTextMessage(
-	ctx context.Context, db storage.ExecQuerier, chatID domain.ChatID, updateID domain.UpdateID,
+	ctx context.Context, db storage.ExecQuerier, chatID domain.ChatID, updateID domain.UpdateID, visibleTo domain.UserID,
 ) (*domain.TextMessage, error) {
 	// Get base message first
-	message, err := r.FindGenericMessage(ctx, db, chatID, updateID)
+	message, err := r.FindGenericMessage(ctx, db, chatID, updateID, visibleTo)
 	if err != nil {
 		return nil, err
 	}
@@ -243,6 +263,16 @@ func (r *UpdateRepository) FindTextMessage(
 		return nil, err
 	}
 
+	entities, err := r.getEntities(ctx, db, chatID, updateID)
+	if err != nil {
+		return nil, err
+	}
+
+	mentions, err := r.getMentions(ctx, db, chatID, updateID)
+	if err != nil {
+		return nil, err
+	}
+
 	// Check if there's an edit for this message
 	edits, err := r.getTextMessageEdits(ctx, db, chatID, updateID)
 	if err != nil {
@@ -256,9 +286,11 @@ func (r *UpdateRepository) FindTextMessage(
 	}
 
 	textMsg := &domain.TextMessage{
-		Message: *message,
-		Text:    text,
-		Edited:  edited,
+		Message:  *message,
+		Text:     text,
+		Entities: entities,
+		Mentions: mentions,
+		Edited:   edited,
 	}
 
 	return textMsg, nil
@@ -288,16 +320,164 @@ func (r *UpdateRepository) UpdateTextMessage(
 		return nil, err
 	}
 
+	if err := r.storeEntities(ctx, db, msg.ChatID, msg.UpdateID, msg.Entities); err != nil {
+		return nil, err
+	}
+	if err := r.storeMentions(ctx, db, msg); err != nil {
+		return nil, err
+	}
 	return msg, nil
 }
 
+// storeEntities replaces stored entities of the text message or its edit
+func (r *UpdateRepository) storeEntities(
+	ctx context.Context, db storage.ExecQuerier,
+	chatID domain.ChatID, updateID domain.UpdateID, entities []domain.TextEntity,
+) error {
+	q1 := `DELETE FROM messaging.text_entity WHERE chat_id = $1 AND update_id = $2`
+	if _, err := db.Exec(ctx, q1, chatID, updateID); err != nil {
+		return err
+	}
+	if len(entities) == 0 {
+		return nil
+	}
+
+	var (
+		offsets = make([]int, len(entities))
+		lengths = make([]int, len(entities))
+		types   = make([]string, len(entities))
+		urls    = make([]*string, len(entities))
+	)
+	for i, entity := range entities {
+		offsets[i] = entity.Offset
+		lengths[i] = entity.Length
+		types[i] = string(entity.Type)
+		if entity.URL != "" {
+			url := entity.URL
+			urls[i] = &url
+		}
+	}
+
+	q2 := `
+	INSERT INTO messaging.text_entity (chat_id, update_id, entity_offset, entity_length, entity_type, url)
+	SELECT $1, $2, e.entity_offset, e.entity_length, e.entity_type::messaging.text_entity_type, e.url
+	FROM UNNEST($3::INT[], $4::INT[], $5::TEXT[], $6::TEXT[]) AS e(entity_offset, entity_length, entity_type, url)`
+
+	_, err := db.Exec(ctx, q2, chatID, updateID, offsets, lengths, types, urls)
+	return err
+}
+
+func (r *UpdateRepository) getEntities(
+	ctx context.Context, db storage.ExecQuerier, chatID domain.ChatID, updateID domain.UpdateID,
+) ([]domain.TextEntity, error) {
+	q := `
+	SELECT entity_offset, entity_length, entity_type, url
+	FROM messaging.text_entity
+	WHERE chat_id = $1 AND update_id = $2
+	ORDER BY entity_offset`
+
+	rows, err := db.Query(ctx, q, chatID, updateID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var entities []domain.TextEntity
+	for rows.Next() {
+		var (
+			entity     domain.TextEntity
+			entityType string
+			url        *string
+		)
+		if err := rows.Scan(&entity.Offset, &entity.Length, &entityType, &url); err != nil {
+			return nil, err
+		}
+		entity.Type = domain.TextEntityType(entityType)
+		if url != nil {
+			entity.URL = *url
+		}
+		entities = append(entities, entity)
+	}
+
+	return entities, rows.Err()
+}
+
+// storeMentions replaces stored mentions of the message
+func (r *UpdateRepository) storeMentions(
+	ctx context.Context, db storage.ExecQuerier, msg *domain.TextMessage,
+) error {
+	q1 := `DELETE FROM messaging.mention WHERE chat_id = $1 AND update_id = $2`
+	if _, err := db.Exec(ctx, q1, msg.ChatID, msg.UpdateID); err != nil {
+		return err
+	}
+	if len(msg.Mentions) == 0 {
+		return nil
+	}
+
+	var (
+		offsets = make([]int, len(msg.Mentions))
+		lengths = make([]int, len(msg.Mentions))
+		userIDs = make([]*uuid.UUID, len(msg.Mentions))
+	)
+	for i, mention := range msg.Mentions {
+		offsets[i] = mention.Offset
+		lengths[i] = mention.Length
+		if !mention.IsEveryone() {
+			id := uuid.UUID(*mention.UserID)
+			userIDs[i] = &id
+		}
+	}
+
+	q2 := `
+	INSERT INTO messaging.mention (chat_id, update_id, mention_offset, mention_length, user_id)
+	SELECT $1, $2, m.mention_offset, m.mention_length, m.user_id
+	FROM UNNEST($3::INT[], $4::INT[], $5::UUID[]) AS m(mention_offset, mention_length, user_id)`
+
+	_, err := db.Exec(ctx, q2, msg.ChatID, msg.UpdateID, offsets, lengths, userIDs)
+	return err
+}
+
+func (r *UpdateRepository) getMentions(
+	ctx context.Context, db storage.ExecQuerier, chatID domain.ChatID, messageID domain.UpdateID,
+) ([]domain.Mention, error) {
+	q := `
+	SELECT mention_offset, mention_length, user_id
+	FROM messaging.mention
+	WHERE chat_id = $1 AND update_id = $2
+	ORDER BY mention_offset`
+
+	rows, err := db.Query(ctx, q, chatID, messageID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var mentions []domain.Mention
+	for rows.Next() {
+		var (
+			mention domain.Mention
+			userID  *uuid.UUID
+		)
+		if err := rows.Scan(&mention.Offset, &mention.Length, &userID); err != nil {
+			return nil, err
+		}
+		if userID != nil {
+			id := domain.UserID(*userID)
+			mention.UserID = &id
+		}
+		mentions = append(mentions, mention)
+	}
+
+	return mentions, rows.Err()
+}
+
 func (r *UpdateRepository) CreateReaction(
 	ctx context.Context, db storage.ExecQuerier, reaction *domain.Reaction,
 ) (*domain.Reaction, error) {
 	// Insert base update
 	q1 := `
-	INSERT INTO messaging.update (chat_id, update_id, update_type, created_at, sender_id)
-	VALUES ($1, $2, 'reaction', $3, $4)
+	INSERT INTO messaging.update (chat_id, update_id, update_type, created_at, sender_id, thread_root_id)
+	VALUES ($1, $2, 'reaction', $3, $4, $5)
 	RETURNING update_id`
 
 	now := time.Now()
@@ -307,6 +487,7 @@ func (r *UpdateRepository) CreateReaction(
 		reaction.UpdateID,
 		now,
 		uuid.UUID(reaction.SenderID),
+		reaction.ThreadRootID,
 	).Scan(&updateID)
 	if err != nil {
 		return nil, err
@@ -338,6 +519,7 @@ func (r *UpdateRepository) FindReaction(
 	SELECT 
 		u.created_at, 
 		u.sender_id,
+		u.thread_root_id,
 		r.reaction,
 		r.message_id
 	FROM messaging.update u
@@ -347,6 +529,7 @@ func (r *UpdateRepository) FindReaction(
 	var (
 		createdAt    time.Time
 		senderID     uuid.UUID
+		threadRootID *domain.UpdateID
 		reactionType string
 		messageID    int64
 	)
@@ -354,6 +537,7 @@ func (r *UpdateRepository) FindReaction(
 	err := db.QueryRow(ctx, q, chatID, updateID).Scan(
 		&createdAt,
 		&senderID,
+		&threadRootID,
 		&reactionType,
 		&messageID,
 	)
@@ -371,11 +555,12 @@ func (r *UpdateRepository) FindReaction(
 
 	reaction := &domain.Reaction{
 		Update: domain.Update{
-			UpdateID:  updateID,
-			ChatID:    chatID,
-			SenderID:  domain.UserID(senderID),
-			CreatedAt: domain.Timestamp(createdAt.Unix()),
-			Deleted:   deletions,
+			UpdateID:     updateID,
+			ChatID:       chatID,
+			SenderID:     domain.UserID(senderID),
+			ThreadRootID: threadRootID,
+			CreatedAt:    domain.Timestamp(createdAt.Unix()),
+			Deleted:      deletions,
 		},
 		Type:      domain.ReactionType(reactionType),
 		MessageID: domain.UpdateID(messageID),
@@ -384,11 +569,144 @@ func (r *UpdateRepository) FindReaction(
 	return reaction, nil
 }
 
+func (r *UpdateRepository) CreatePin(
+	ctx context.Context, db storage.ExecQuerier, pin *domain.Pin,
+) (*domain.Pin, error) {
+	// Insert base update
+	q1 := `
+	INSERT INTO messaging.update (chat_id, update_id, update_type, created_at, sender_id)
+	VALUES ($1, $2, $3, $4, $5)
+	RETURNING update_id`
+
+	now := time.Now()
+	var updateID int64
+	err := db.QueryRow(ctx, q1,
+		pin.ChatID,
+		pin.UpdateID,
+		pin.UpdateType(),
+		now,
+		uuid.UUID(pin.SenderID),
+	).Scan(&updateID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Insert pin specific data
+	q2 := `
+	INSERT INTO messaging.pin_update (chat_id, update_id, message_id)
+	VALUES ($1, $2, $3)`
+
+	_, err = db.Exec(ctx, q2,
+		pin.ChatID,
+		updateID,
+		pin.MessageID,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	pin.UpdateID = domain.UpdateID(updateID)
+	pin.CreatedAt = domain.Timestamp(now.Unix())
+	return pin, nil
+}
+
+func (r *UpdateRepository) CreateServiceMessage(
+	ctx context.Context, db storage.ExecQuerier, msg *domain.ServiceMessage,
+) (*domain.ServiceMessage, error) {
+	// Insert base update
+	q1 := `
+	INSERT INTO messaging.update (chat_id, update_id, update_type, created_at, sender_id)
+	VALUES ($1, $2, $3, $4, $5)
+	RETURNING update_id`
+
+	now := time.Now()
+	var updateID int64
+	err := db.QueryRow(ctx, q1,
+		msg.ChatID,
+		msg.UpdateID,
+		msg.UpdateType(),
+		now,
+		uuid.UUID(msg.SenderID),
+	).Scan(&updateID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Insert only the columns of the update type
+	var (
+		members           []uuid.UUID
+		name, description *string
+		photo             *string
+		expirationSeconds *int64
+	)
+	switch msg.Type {
+	case domain.UpdateTypeMemberAdded, domain.UpdateTypeMemberRemoved:
+		members = make([]uuid.UUID, len(msg.Members))
+		for i, m := range msg.Members {
+			members[i] = uuid.UUID(m)
+		}
+	case domain.UpdateTypeInfoChanged:
+		name, description = &msg.Name, &msg.Description
+	case domain.UpdateTypePhotoChanged:
+		cp := string(msg.Photo)
+		photo = &cp
+	case domain.UpdateTypeExpirationChanged:
+		if msg.Expiration != nil {
+			cp := int64(msg.Expiration.Seconds())
+			expirationSeconds = &cp
+		}
+	}
+
+	q2 := `
+	INSERT INTO messaging.service_message_update
+	(chat_id, update_id, members, name, description, photo, expiration_seconds)
+	VALUES ($1, $2, $3, $4, $5, $6, $7)`
+
+	_, err = db.Exec(ctx, q2,
+		msg.ChatID,
+		updateID,
+		members,
+		name,
+		description,
+		photo,
+		expirationSeconds,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	msg.UpdateID = domain.UpdateID(updateID)
+	msg.CreatedAt = domain.Timestamp(now.Unix())
+	return msg, nil
+}
+
+func (r *UpdateRepository) IsPinned(
+	ctx context.Context, db storage.ExecQuerier, chatID domain.ChatID, messageID domain.UpdateID,
+) (bool, error) {
+	q := `
+	SELECT u.update_type = 'message_pinned'
+	FROM messaging.pin_update p
+	JOIN messaging.update u ON u.chat_id = p.chat_id AND u.update_id = p.update_id
+	WHERE p.chat_id = $1 AND p.message_id = $2
+	ORDER BY p.update_id DESC
+	LIMIT 1`
+
+	var pinned bool
+	err := db.QueryRow(ctx, q, chatID, messageID).Scan(&pinned)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return false, nil
+		}
+		return false, err
+	}
+	return pinned, nil
+}
+
 func (r *UpdateRepository) FindFileMessage(
-	ctx context.Context, db storage.ExecQuerier, chatID domain.ChatID, updateID domain.UpdateID,
+	ctx context.Context, db storage.ExecQuerier, chatID domain.ChatID, updateID domain.UpdateID, visibleTo domain.UserID,
 ) (*domain.FileMessage, error) {
 	// Get base message first
-	message, err := r.FindGenericMessage(ctx, db, chatID, updateID)
+	message, err := r.FindGenericMessage(ctx, db, chatID, updateID, visibleTo)
 	if err != nil {
 		return nil, err
 	}
@@ -444,8 +762,8 @@ func (r *UpdateRepository) CreateFileMessage(
 ) (*domain.FileMessage, error) {
 	// Insert base update
 	q1 := `
-	INSERT INTO messaging.update (chat_id, update_id, update_type, created_at, sender_id)
-	VALUES ($1, $2, 'file_message', $3, $4)
+	INSERT INTO messaging.update (chat_id, update_id, update_type, created_at, sender_id, thread_root_id)
+	VALUES ($1, $2, 'file_message', $3, $4, $5)
 	RETURNING update_id`
 
 	now := time.Now()
@@ -455,6 +773,7 @@ func (r *UpdateRepository) CreateFileMessage(
 		msg.UpdateID,
 		now,
 		uuid.UUID(msg.SenderID),
+		msg.ThreadRootID,
 	).Scan(&updateID)
 	if err != nil {
 		return nil, err
@@ -494,6 +813,42 @@ func (r *UpdateRepository) CreateFileMessage(
 	return msg, nil
 }
 
+func (r *UpdateRepository) GetThreadParticipants(
+	ctx context.Context, db storage.ExecQuerier, chatID domain.ChatID, rootID domain.UpdateID,
+) ([]domain.UserID, error) {
+	q := `
+	SELECT sender_id
+	FROM messaging.update
+	WHERE chat_id = $1 AND update_id = $2
+	UNION
+	SELECT sender_id
+	FROM messaging.update
+	WHERE chat_id = $1 
+		AND thread_root_id = $2
+		AND update_type IN ('text_message', 'file_message')`
+
+	rows, err := db.Query(ctx, q, chatID, rootID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var participants []domain.UserID
+	for rows.Next() {
+		var senderID uuid.UUID
+		if err := rows.Scan(&senderID); err != nil {
+			return nil, err
+		}
+		participants = append(participants, domain.UserID(senderID))
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return participants, nil
+}
+
 // Helper functions
 
 func (r *UpdateRepository) getDeletions(
@@ -584,6 +939,11 @@ func (r *UpdateRepository) getTextMessageEdits(
 			return nil, err
 		}
 
+		entities, err := r.getEntities(ctx, db, chatID, domain.UpdateID(id))
+		if err != nil {
+			return nil, err
+		}
+
 		edit := &domain.TextMessageEdited{
 			Update: domain.Update{
 				UpdateID:  domain.UpdateID(id),
@@ -592,8 +952,9 @@ func (r *UpdateRepository) getTextMessageEdits(
 				CreatedAt: domain.Timestamp(createdAt.Unix()),
 				Deleted:   deletions,
 			},
-			MessageID: messageID,
-			NewText:   newText,
+			MessageID:   messageID,
+			NewText:     newText,
+			NewEntities: entities,
 		}
 
 		edits = append(edits, edit)
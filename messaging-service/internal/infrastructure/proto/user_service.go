@@ -0,0 +1,43 @@
+package proto
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/chakchat/chakchat-backend/messaging-service/internal/infrastructure/proto/userservice"
+	"github.com/google/uuid"
+	"google.golang.org/grpc"
+)
+
+type UserService struct {
+	client userservice.UserServiceClient
+}
+
+func NewUserService(conn *grpc.ClientConn) *UserService {
+	return &UserService{
+		client: userservice.NewUserServiceClient(conn),
+	}
+}
+
+func (s *UserService) GetUserIDsByUsernames(ctx context.Context, usernames []string) (map[string]uuid.UUID, error) {
+	resp, err := s.client.GetUserIDsByUsernames(ctx, &userservice.GetUserIDsByUsernamesRequest{
+		Usernames: usernames,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if resp.Status != userservice.UserResponseStatus_SUCCESS {
+		return nil, errors.New("user service failed to get user ids by usernames")
+	}
+
+	ids := make(map[string]uuid.UUID, len(resp.Users))
+	for _, user := range resp.Users {
+		id, err := uuid.Parse(user.GetUserId().GetValue())
+		if err != nil {
+			return nil, fmt.Errorf("cannot parse userId from user service: %s", err)
+		}
+		ids[user.Username] = id
+	}
+	return ids, nil
+}
@@ -3,11 +3,15 @@ package errmap
 import (
 	"errors"
 	"fmt"
+	"math"
 	"net/http"
+	"strconv"
+	"time"
 
 	"github.com/chakchat/chakchat-backend/messaging-service/internal/application/services"
 	"github.com/chakchat/chakchat-backend/messaging-service/internal/domain"
 	"github.com/chakchat/chakchat-backend/messaging-service/internal/rest/restapi"
+	"github.com/chakchat/chakchat-backend/shared/go/ratelimit"
 	"github.com/gin-gonic/gin"
 	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/trace"
@@ -26,6 +30,13 @@ func Respond(c *gin.Context, err error) {
 	if resp.Code >= 500 {
 		c.Error(err)
 	}
+
+	var retryErr domain.RetryError
+	if errors.As(err, &retryErr) {
+		retryAfter := max(int(math.Ceil(time.Until(retryErr.RetryAt.Time()).Seconds())), 1)
+		c.Header(ratelimit.HeaderRetryAfter, strconv.Itoa(retryAfter))
+	}
+
 	c.JSON(resp.Code, resp.Body)
 }
 
@@ -118,6 +129,62 @@ var servicesErrMap = map[services.Error]Response{
 			ErrorMessage: "Secret update is not found",
 		},
 	},
+	services.ErrPollNotFound: {
+		Code: http.StatusNotFound,
+		Body: restapi.ErrorResponse{
+			ErrorType:    "poll_not_found",
+			ErrorMessage: "Poll is not found",
+		},
+	},
+	services.ErrScheduledNotFound: {
+		Code: http.StatusNotFound,
+		Body: restapi.ErrorResponse{
+			ErrorType:    "scheduled_not_found",
+			ErrorMessage: "Scheduled message is not found",
+		},
+	},
+	services.ErrMentionedUserNotFound: {
+		Code: http.StatusNotFound,
+		Body: restapi.ErrorResponse{
+			ErrorType:    "mentioned_user_not_found",
+			ErrorMessage: "Mentioned user is not found",
+		},
+	},
+	services.ErrInviteLinkNotFound: {
+		Code: http.StatusNotFound,
+		Body: restapi.ErrorResponse{
+			ErrorType:    "invite_link_not_found",
+			ErrorMessage: "Invite link is not found",
+		},
+	},
+	services.ErrJoinRequestNotFound: {
+		Code: http.StatusNotFound,
+		Body: restapi.ErrorResponse{
+			ErrorType:    "join_request_not_found",
+			ErrorMessage: "Join request is not found",
+		},
+	},
+	services.ErrJoinRequestExists: {
+		Code: http.StatusBadRequest,
+		Body: restapi.ErrorResponse{
+			ErrorType:    "join_request_exists",
+			ErrorMessage: "Join request is already submitted",
+		},
+	},
+	services.ErrBanNotFound: {
+		Code: http.StatusNotFound,
+		Body: restapi.ErrorResponse{
+			ErrorType:    "ban_not_found",
+			ErrorMessage: "Ban is not found",
+		},
+	},
+	services.ErrTopicNotFound: {
+		Code: http.StatusNotFound,
+		Body: restapi.ErrorResponse{
+			ErrorType:    "topic_not_found",
+			ErrorMessage: "Topic is not found",
+		},
+	},
 }
 
 var domainErrMap = map[domain.Error]Response{
@@ -275,4 +342,431 @@ var domainErrMap = map[domain.Error]Response{
 			ErrorMessage: "Invalid reaction type",
 		},
 	},
+	domain.ErrSearchQueryEmpty: {
+		Code: http.StatusBadRequest,
+		Body: restapi.ErrorResponse{
+			ErrorType:    "search_query_empty",
+			ErrorMessage: "Search query is empty",
+		},
+	},
+	domain.ErrSearchQueryTooLong: {
+		Code: http.StatusBadRequest,
+		Body: restapi.ErrorResponse{
+			ErrorType:    "search_query_too_long",
+			ErrorMessage: "Search query is too long",
+		},
+	},
+	domain.ErrNestedThread: {
+		Code: http.StatusBadRequest,
+		Body: restapi.ErrorResponse{
+			ErrorType:    "nested_thread",
+			ErrorMessage: "Thread message can't be a thread root",
+		},
+	},
+	domain.ErrUpdateNotFromThread: {
+		Code: http.StatusBadRequest,
+		Body: restapi.ErrorResponse{
+			ErrorType:    "update_not_from_thread",
+			ErrorMessage: "Update is not from thread",
+		},
+	},
+	domain.ErrReplyOutsideThread: {
+		Code: http.StatusBadRequest,
+		Body: restapi.ErrorResponse{
+			ErrorType:    "reply_outside_thread",
+			ErrorMessage: "Thread message can be replied only in its thread",
+		},
+	},
+	domain.ErrMessageAlreadyPinned: {
+		Code: http.StatusBadRequest,
+		Body: restapi.ErrorResponse{
+			ErrorType:    "message_already_pinned",
+			ErrorMessage: "Message is already pinned",
+		},
+	},
+	domain.ErrMessageNotPinned: {
+		Code: http.StatusBadRequest,
+		Body: restapi.ErrorResponse{
+			ErrorType:    "message_not_pinned",
+			ErrorMessage: "Message is not pinned",
+		},
+	},
+	domain.ErrPollQuestionEmpty: {
+		Code: http.StatusBadRequest,
+		Body: restapi.ErrorResponse{
+			ErrorType:    "poll_question_empty",
+			ErrorMessage: "Poll question is empty",
+		},
+	},
+	domain.ErrPollQuestionTooLong: {
+		Code: http.StatusBadRequest,
+		Body: restapi.ErrorResponse{
+			ErrorType:    "poll_question_too_long",
+			ErrorMessage: "Poll question is too long",
+		},
+	},
+	domain.ErrPollOptionsCount: {
+		Code: http.StatusBadRequest,
+		Body: restapi.ErrorResponse{
+			ErrorType:    "poll_options_count",
+			ErrorMessage: "Poll must have from 2 to 10 options",
+		},
+	},
+	domain.ErrPollInvalidOption: {
+		Code: http.StatusBadRequest,
+		Body: restapi.ErrorResponse{
+			ErrorType:    "poll_invalid_option",
+			ErrorMessage: "Invalid poll option",
+		},
+	},
+	domain.ErrPollCloseTimePassed: {
+		Code: http.StatusBadRequest,
+		Body: restapi.ErrorResponse{
+			ErrorType:    "poll_close_time_passed",
+			ErrorMessage: "Poll close time has already passed",
+		},
+	},
+	domain.ErrPollClosed: {
+		Code: http.StatusBadRequest,
+		Body: restapi.ErrorResponse{
+			ErrorType:    "poll_closed",
+			ErrorMessage: "Poll is closed",
+		},
+	},
+	domain.ErrPollNoOptionChosen: {
+		Code: http.StatusBadRequest,
+		Body: restapi.ErrorResponse{
+			ErrorType:    "poll_no_option_chosen",
+			ErrorMessage: "No poll option is chosen",
+		},
+	},
+	domain.ErrPollSingleChoice: {
+		Code: http.StatusBadRequest,
+		Body: restapi.ErrorResponse{
+			ErrorType:    "poll_single_choice",
+			ErrorMessage: "Poll allows only one option",
+		},
+	},
+	domain.ErrPollNotVoted: {
+		Code: http.StatusBadRequest,
+		Body: restapi.ErrorResponse{
+			ErrorType:    "poll_not_voted",
+			ErrorMessage: "You have not voted in the poll",
+		},
+	},
+	domain.ErrPollCloseForbidden: {
+		Code: http.StatusBadRequest,
+		Body: restapi.ErrorResponse{
+			ErrorType:    "poll_close_forbidden",
+			ErrorMessage: "Only poll author or admin can close the poll",
+		},
+	},
+	domain.ErrScheduleTimePassed: {
+		Code: http.StatusBadRequest,
+		Body: restapi.ErrorResponse{
+			ErrorType:    "schedule_time_passed",
+			ErrorMessage: "Scheduled send time has already passed",
+		},
+	},
+	domain.ErrScheduleTooFar: {
+		Code: http.StatusBadRequest,
+		Body: restapi.ErrorResponse{
+			ErrorType:    "schedule_too_far",
+			ErrorMessage: "Scheduled send time is too far in the future",
+		},
+	},
+	domain.ErrScheduledFileText: {
+		Code: http.StatusBadRequest,
+		Body: restapi.ErrorResponse{
+			ErrorType:    "scheduled_file_text",
+			ErrorMessage: "Text of scheduled file message can't be set",
+		},
+	},
+	domain.ErrScheduledSending: {
+		Code: http.StatusBadRequest,
+		Body: restapi.ErrorResponse{
+			ErrorType:    "scheduled_sending",
+			ErrorMessage: "Scheduled message is already being sent",
+		},
+	},
+	domain.ErrMentionInvalid: {
+		Code: http.StatusBadRequest,
+		Body: restapi.ErrorResponse{
+			ErrorType:    "mention_invalid",
+			ErrorMessage: "Mention doesn't match the text",
+		},
+	},
+	domain.ErrMentionNotMember: {
+		Code: http.StatusBadRequest,
+		Body: restapi.ErrorResponse{
+			ErrorType:    "mention_not_member",
+			ErrorMessage: "Mentioned user is not a member of the chat",
+		},
+	},
+	domain.ErrTooManyMentions: {
+		Code: http.StatusBadRequest,
+		Body: restapi.ErrorResponse{
+			ErrorType:    "too_many_mentions",
+			ErrorMessage: "Too many mentions",
+		},
+	},
+	domain.ErrTextEntityInvalid: {
+		Code: http.StatusBadRequest,
+		Body: restapi.ErrorResponse{
+			ErrorType:    "text_entity_invalid",
+			ErrorMessage: "Text entity is invalid",
+		},
+	},
+	domain.ErrTextEntityOutOfRange: {
+		Code: http.StatusBadRequest,
+		Body: restapi.ErrorResponse{
+			ErrorType:    "text_entity_out_of_range",
+			ErrorMessage: "Text entity is out of text range",
+		},
+	},
+	domain.ErrTextEntitiesOverlap: {
+		Code: http.StatusBadRequest,
+		Body: restapi.ErrorResponse{
+			ErrorType:    "text_entities_overlap",
+			ErrorMessage: "Text entities overlap",
+		},
+	},
+	domain.ErrTextEntityURLInvalid: {
+		Code: http.StatusBadRequest,
+		Body: restapi.ErrorResponse{
+			ErrorType:    "text_entity_url_invalid",
+			ErrorMessage: "Text entity URL is invalid or its scheme is not allowed",
+		},
+	},
+	domain.ErrTooManyTextEntities: {
+		Code: http.StatusBadRequest,
+		Body: restapi.ErrorResponse{
+			ErrorType:    "too_many_text_entities",
+			ErrorMessage: "Too many text entities",
+		},
+	},
+	domain.ErrInvalidPermissions: {
+		Code: http.StatusBadRequest,
+		Body: restapi.ErrorResponse{
+			ErrorType:    "invalid_permissions",
+			ErrorMessage: "Invalid permissions",
+		},
+	},
+	domain.ErrPermissionDenied: {
+		Code: http.StatusBadRequest,
+		Body: restapi.ErrorResponse{
+			ErrorType:    "permission_denied",
+			ErrorMessage: "Sender doesn't have such permission",
+		},
+	},
+	domain.ErrMemberRestricted: {
+		Code: http.StatusBadRequest,
+		Body: restapi.ErrorResponse{
+			ErrorType:    "member_restricted",
+			ErrorMessage: "Group members are restricted from sending it",
+		},
+	},
+	domain.ErrMemberIsOwner: {
+		Code: http.StatusBadRequest,
+		Body: restapi.ErrorResponse{
+			ErrorType:    "member_is_owner",
+			ErrorMessage: "Member is group owner",
+		},
+	},
+	domain.ErrMemberNotAdmin: {
+		Code: http.StatusBadRequest,
+		Body: restapi.ErrorResponse{
+			ErrorType:    "member_not_admin",
+			ErrorMessage: "Member is not admin",
+		},
+	},
+	domain.ErrSenderNotOwner: {
+		Code: http.StatusBadRequest,
+		Body: restapi.ErrorResponse{
+			ErrorType:    "sender_not_owner",
+			ErrorMessage: "Sender is not group owner",
+		},
+	},
+	domain.ErrOwnerLastMember: {
+		Code: http.StatusBadRequest,
+		Body: restapi.ErrorResponse{
+			ErrorType:    "owner_last_member",
+			ErrorMessage: "Group owner is the last member, delete the group instead",
+		},
+	},
+	domain.ErrInviteLinkInvalid: {
+		Code: http.StatusBadRequest,
+		Body: restapi.ErrorResponse{
+			ErrorType:    "invite_link_invalid",
+			ErrorMessage: "Invite link expiration or usage limit is invalid",
+		},
+	},
+	domain.ErrInviteLinkNotFromChat: {
+		Code: http.StatusBadRequest,
+		Body: restapi.ErrorResponse{
+			ErrorType:    "invite_link_not_from_chat",
+			ErrorMessage: "Invite link is not from this chat",
+		},
+	},
+	domain.ErrInviteLinkRevoked: {
+		Code: http.StatusBadRequest,
+		Body: restapi.ErrorResponse{
+			ErrorType:    "invite_link_revoked",
+			ErrorMessage: "Invite link is revoked",
+		},
+	},
+	domain.ErrInviteLinkExpired: {
+		Code: http.StatusBadRequest,
+		Body: restapi.ErrorResponse{
+			ErrorType:    "invite_link_expired",
+			ErrorMessage: "Invite link is expired",
+		},
+	},
+	domain.ErrInviteLinkUsageLimitReached: {
+		Code: http.StatusBadRequest,
+		Body: restapi.ErrorResponse{
+			ErrorType:    "invite_link_usage_limit_reached",
+			ErrorMessage: "Invite link usage limit is reached",
+		},
+	},
+	domain.ErrJoinRequiresApproval: {
+		Code: http.StatusBadRequest,
+		Body: restapi.ErrorResponse{
+			ErrorType:    "join_requires_approval",
+			ErrorMessage: "Joining requires admin approval, submit a join request instead",
+		},
+	},
+	domain.ErrJoinApprovalNotRequired: {
+		Code: http.StatusBadRequest,
+		Body: restapi.ErrorResponse{
+			ErrorType:    "join_approval_not_required",
+			ErrorMessage: "Joining doesn't require approval, join directly instead",
+		},
+	},
+	domain.ErrJoinRequestNotFromChat: {
+		Code: http.StatusBadRequest,
+		Body: restapi.ErrorResponse{
+			ErrorType:    "join_request_not_from_chat",
+			ErrorMessage: "Join request is not from this chat",
+		},
+	},
+	domain.ErrBanSelf: {
+		Code: http.StatusBadRequest,
+		Body: restapi.ErrorResponse{
+			ErrorType:    "ban_self",
+			ErrorMessage: "Sender can't ban itself",
+		},
+	},
+	domain.ErrBanReasonTooLong: {
+		Code: http.StatusBadRequest,
+		Body: restapi.ErrorResponse{
+			ErrorType:    "ban_reason_too_long",
+			ErrorMessage: "Ban reason is too long",
+		},
+	},
+	domain.ErrBanExpirationInvalid: {
+		Code: http.StatusBadRequest,
+		Body: restapi.ErrorResponse{
+			ErrorType:    "ban_expiration_invalid",
+			ErrorMessage: "Ban expiration must be in the future",
+		},
+	},
+	domain.ErrBanNotFromChat: {
+		Code: http.StatusBadRequest,
+		Body: restapi.ErrorResponse{
+			ErrorType:    "ban_not_from_chat",
+			ErrorMessage: "Ban is not from this chat",
+		},
+	},
+	domain.ErrUserBanned: {
+		Code: http.StatusBadRequest,
+		Body: restapi.ErrorResponse{
+			ErrorType:    "user_banned",
+			ErrorMessage: "User is banned in the group",
+		},
+	},
+	domain.ErrChannelReadOnly: {
+		Code: http.StatusBadRequest,
+		Body: restapi.ErrorResponse{
+			ErrorType:    "channel_read_only",
+			ErrorMessage: "Only channel admins can post",
+		},
+	},
+	domain.ErrAlreadySubscribed: {
+		Code: http.StatusBadRequest,
+		Body: restapi.ErrorResponse{
+			ErrorType:    "already_subscribed",
+			ErrorMessage: "User is already subscribed to the channel",
+		},
+	},
+	domain.ErrNotSubscribed: {
+		Code: http.StatusBadRequest,
+		Body: restapi.ErrorResponse{
+			ErrorType:    "not_subscribed",
+			ErrorMessage: "User is not subscribed to the channel",
+		},
+	},
+	domain.ErrTooManyViewedPosts: {
+		Code: http.StatusBadRequest,
+		Body: restapi.ErrorResponse{
+			ErrorType:    "too_many_viewed_posts",
+			ErrorMessage: "Too many viewed posts",
+		},
+	},
+	domain.ErrTopicTitleEmpty: {
+		Code: http.StatusBadRequest,
+		Body: restapi.ErrorResponse{
+			ErrorType:    "topic_title_empty",
+			ErrorMessage: "Topic title is empty",
+		},
+	},
+	domain.ErrTopicTitleTooLong: {
+		Code: http.StatusBadRequest,
+		Body: restapi.ErrorResponse{
+			ErrorType:    "topic_title_too_long",
+			ErrorMessage: "Topic title is too long",
+		},
+	},
+	domain.ErrTopicIconTooLong: {
+		Code: http.StatusBadRequest,
+		Body: restapi.ErrorResponse{
+			ErrorType:    "topic_icon_too_long",
+			ErrorMessage: "Topic icon is too long",
+		},
+	},
+	domain.ErrTopicClosed: {
+		Code: http.StatusBadRequest,
+		Body: restapi.ErrorResponse{
+			ErrorType:    "topic_closed",
+			ErrorMessage: "Topic is closed",
+		},
+	},
+	domain.ErrTopicNotClosed: {
+		Code: http.StatusBadRequest,
+		Body: restapi.ErrorResponse{
+			ErrorType:    "topic_not_closed",
+			ErrorMessage: "Topic is not closed",
+		},
+	},
+	domain.ErrAdminsOnly: {
+		Code: http.StatusBadRequest,
+		Body: restapi.ErrorResponse{
+			ErrorType:    "admins_only",
+			ErrorMessage: "Only group admins can post",
+		},
+	},
+	domain.ErrSlowMode: {
+		Code: http.StatusTooManyRequests,
+		Body: restapi.ErrorResponse{
+			ErrorType:    "slow_mode",
+			ErrorMessage: "Slow mode is on. Retry after the delay in Retry-After header",
+		},
+	},
+	domain.ErrSlowModeInvalid: {
+		Code: http.StatusBadRequest,
+		Body: restapi.ErrorResponse{
+			ErrorType:    "slow_mode_invalid",
+			ErrorMessage: "Slow mode delay is invalid",
+		},
+	},
 }
@@ -0,0 +1,212 @@
+package chat
+
+import (
+	"context"
+
+	"github.com/chakchat/chakchat-backend/messaging-service/internal/application/dto"
+	"github.com/chakchat/chakchat-backend/messaging-service/internal/application/generic"
+	"github.com/chakchat/chakchat-backend/messaging-service/internal/application/request"
+	"github.com/chakchat/chakchat-backend/messaging-service/internal/rest/errmap"
+	"github.com/chakchat/chakchat-backend/messaging-service/internal/rest/restapi"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type ChannelService interface {
+	CreateChannel(ctx context.Context, req request.CreateChannel) (*dto.ChannelDTO, error)
+	UpdateChannelInfo(ctx context.Context, req request.UpdateChannelInfo) (*dto.ChannelDTO, error)
+	DeleteChannel(ctx context.Context, req request.DeleteChat) error
+	Subscribe(ctx context.Context, req request.SubscribeChannel) (*dto.ChannelDTO, error)
+	Unsubscribe(ctx context.Context, req request.SubscribeChannel) error
+	SetAdmin(ctx context.Context, req request.SetGroupAdmin) (*dto.ChannelDTO, error)
+	RemoveAdmin(ctx context.Context, req request.RemoveGroupAdmin) (*dto.ChannelDTO, error)
+}
+
+type ChannelHandler struct {
+	service ChannelService
+}
+
+func NewChannelHandler(service ChannelService) *ChannelHandler {
+	return &ChannelHandler{
+		service: service,
+	}
+}
+
+func (h *ChannelHandler) CreateChannel(c *gin.Context) {
+	userId := getUserID(c.Request.Context())
+
+	req := struct {
+		Name        string `json:"name"`
+		Description string `json:"description"`
+	}{}
+	if err := c.ShouldBindBodyWithJSON(&req); err != nil {
+		restapi.SendUnprocessableJSON(c)
+		return
+	}
+
+	channel, err := h.service.CreateChannel(c.Request.Context(), request.CreateChannel{
+		SenderID:    userId,
+		Name:        req.Name,
+		Description: req.Description,
+	})
+	if err != nil {
+		errmap.Respond(c, err)
+		return
+	}
+
+	restapi.SendSuccess(c, generic.FromChannelDTO(channel))
+}
+
+func (h *ChannelHandler) UpdateChannel(c *gin.Context) {
+	chatId, err := uuid.Parse(c.Param(paramChatID))
+	if err != nil {
+		restapi.SendInvalidChatID(c)
+		return
+	}
+	userId := getUserID(c.Request.Context())
+
+	req := struct {
+		Name        string `json:"name"`
+		Description string `json:"description"`
+	}{}
+	if err := c.ShouldBindBodyWithJSON(&req); err != nil {
+		restapi.SendUnprocessableJSON(c)
+		return
+	}
+
+	channel, err := h.service.UpdateChannelInfo(c.Request.Context(), request.UpdateChannelInfo{
+		ChatID:      chatId,
+		SenderID:    userId,
+		Name:        req.Name,
+		Description: req.Description,
+	})
+	if err != nil {
+		errmap.Respond(c, err)
+		return
+	}
+
+	restapi.SendSuccess(c, generic.FromChannelDTO(channel))
+}
+
+func (h *ChannelHandler) DeleteChannel(c *gin.Context) {
+	chatId, err := uuid.Parse(c.Param(paramChatID))
+	if err != nil {
+		restapi.SendInvalidChatID(c)
+		return
+	}
+	userId := getUserID(c.Request.Context())
+
+	err = h.service.DeleteChannel(c.Request.Context(), request.DeleteChat{
+		ChatID:   chatId,
+		SenderID: userId,
+	})
+	if err != nil {
+		errmap.Respond(c, err)
+		return
+	}
+
+	restapi.SendSuccess(c, struct{}{})
+}
+
+func (h *ChannelHandler) Subscribe(c *gin.Context) {
+	chatId, err := uuid.Parse(c.Param(paramChatID))
+	if err != nil {
+		restapi.SendInvalidChatID(c)
+		return
+	}
+	userId := getUserID(c.Request.Context())
+
+	channel, err := h.service.Subscribe(c.Request.Context(), request.SubscribeChannel{
+		ChatID:   chatId,
+		SenderID: userId,
+	})
+	if err != nil {
+		errmap.Respond(c, err)
+		return
+	}
+
+	restapi.SendSuccess(c, generic.FromChannelDTO(channel))
+}
+
+func (h *ChannelHandler) Unsubscribe(c *gin.Context) {
+	chatId, err := uuid.Parse(c.Param(paramChatID))
+	if err != nil {
+		restapi.SendInvalidChatID(c)
+		return
+	}
+	userId := getUserID(c.Request.Context())
+
+	err = h.service.Unsubscribe(c.Request.Context(), request.SubscribeChannel{
+		ChatID:   chatId,
+		SenderID: userId,
+	})
+	if err != nil {
+		errmap.Respond(c, err)
+		return
+	}
+
+	restapi.SendSuccess(c, struct{}{})
+}
+
+func (h *ChannelHandler) SetAdmin(c *gin.Context) {
+	chatId, err := uuid.Parse(c.Param(paramChatID))
+	if err != nil {
+		restapi.SendInvalidChatID(c)
+		return
+	}
+	userId := getUserID(c.Request.Context())
+
+	memberId, err := uuid.Parse(c.Param(paramMemberID))
+	if err != nil {
+		restapi.SendInvalidMemberID(c)
+		return
+	}
+
+	req := struct {
+		Permissions []string `json:"permissions"`
+	}{}
+	if err := c.ShouldBindBodyWithJSON(&req); err != nil {
+		restapi.SendUnprocessableJSON(c)
+		return
+	}
+
+	channel, err := h.service.SetAdmin(c.Request.Context(), request.SetGroupAdmin{
+		ChatID:      chatId,
+		SenderID:    userId,
+		MemberID:    memberId,
+		Permissions: req.Permissions,
+	})
+	if err != nil {
+		errmap.Respond(c, err)
+		return
+	}
+
+	restapi.SendSuccess(c, generic.FromChannelDTO(channel))
+}
+
+func (h *ChannelHandler) RemoveAdmin(c *gin.Context) {
+	chatId, err := uuid.Parse(c.Param(paramChatID))
+	if err != nil {
+		restapi.SendInvalidChatID(c)
+		return
+	}
+	userId := getUserID(c.Request.Context())
+
+	memberId, err := uuid.Parse(c.Param(paramMemberID))
+	if err != nil {
+		restapi.SendInvalidMemberID(c)
+		return
+	}
+
+	channel, err := h.service.RemoveAdmin(c.Request.Context(), request.RemoveGroupAdmin{
+		ChatID:   chatId,
+		SenderID: userId,
+		MemberID: memberId,
+	})
+	if err != nil {
+		errmap.Respond(c, err)
+		return
+	}
+
+	restapi.SendSuccess(c, generic.FromChannelDTO(channel))
+}
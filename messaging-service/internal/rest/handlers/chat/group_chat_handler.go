@@ -2,6 +2,7 @@ package chat
 
 import (
 	"context"
+	"strconv"
 
 	"github.com/chakchat/chakchat-backend/messaging-service/internal/application/dto"
 	"github.com/chakchat/chakchat-backend/messaging-service/internal/application/generic"
@@ -12,7 +13,15 @@ import (
 	"github.com/google/uuid"
 )
 
-const paramMemberID = "memberId"
+const (
+	paramMemberID = "memberId"
+
+	queryParamOffset = "offset"
+	queryParamLimit  = "limit"
+
+	defaultBansLimit = 20
+	maxBansLimit     = 100
+)
 
 type GroupChatService interface {
 	CreateGroup(ctx context.Context, req request.CreateGroup) (*dto.GroupChatDTO, error)
@@ -20,6 +29,16 @@ type GroupChatService interface {
 	DeleteGroup(ctx context.Context, req request.DeleteChat) error
 	AddMember(ctx context.Context, req request.AddMember) (*dto.GroupChatDTO, error)
 	DeleteMember(ctx context.Context, req request.DeleteMember) (*dto.GroupChatDTO, error)
+	SetAdmin(ctx context.Context, req request.SetGroupAdmin) (*dto.GroupChatDTO, error)
+	RemoveAdmin(ctx context.Context, req request.RemoveGroupAdmin) (*dto.GroupChatDTO, error)
+	TransferOwnership(ctx context.Context, req request.TransferGroupOwnership) (*dto.GroupChatDTO, error)
+	LeaveGroup(ctx context.Context, req request.LeaveGroup) (*dto.GroupChatDTO, error)
+	SetMemberPermissions(ctx context.Context, req request.SetMemberPermissions) (*dto.GroupChatDTO, error)
+	SetPostingMode(ctx context.Context, req request.SetPostingMode) (*dto.GroupChatDTO, error)
+	SetHistoryVisibility(ctx context.Context, req request.SetHistoryVisibility) (*dto.GroupChatDTO, error)
+	BanMember(ctx context.Context, req request.BanMember) (*dto.GroupBanDTO, error)
+	UnbanMember(ctx context.Context, req request.UnbanMember) error
+	GetBans(ctx context.Context, req request.GetGroupBans) (*dto.GroupBanPageDTO, error)
 }
 
 type GroupChatHandler struct {
@@ -161,3 +180,315 @@ func (h *GroupChatHandler) DeleteMember(c *gin.Context) {
 
 	restapi.SendSuccess(c, generic.FromGroupChatDTO(group))
 }
+
+func (h *GroupChatHandler) SetAdmin(c *gin.Context) {
+	chatId, err := uuid.Parse(c.Param(paramChatID))
+	if err != nil {
+		restapi.SendInvalidChatID(c)
+		return
+	}
+	userId := getUserID(c.Request.Context())
+
+	memberId, err := uuid.Parse(c.Param(paramMemberID))
+	if err != nil {
+		restapi.SendInvalidMemberID(c)
+		return
+	}
+
+	req := struct {
+		Permissions []string `json:"permissions"`
+	}{}
+	if err := c.ShouldBindBodyWithJSON(&req); err != nil {
+		restapi.SendUnprocessableJSON(c)
+		return
+	}
+
+	group, err := h.service.SetAdmin(c.Request.Context(), request.SetGroupAdmin{
+		ChatID:      chatId,
+		SenderID:    userId,
+		MemberID:    memberId,
+		Permissions: req.Permissions,
+	})
+	if err != nil {
+		errmap.Respond(c, err)
+		return
+	}
+
+	restapi.SendSuccess(c, generic.FromGroupChatDTO(group))
+}
+
+func (h *GroupChatHandler) RemoveAdmin(c *gin.Context) {
+	chatId, err := uuid.Parse(c.Param(paramChatID))
+	if err != nil {
+		restapi.SendInvalidChatID(c)
+		return
+	}
+	userId := getUserID(c.Request.Context())
+
+	memberId, err := uuid.Parse(c.Param(paramMemberID))
+	if err != nil {
+		restapi.SendInvalidMemberID(c)
+		return
+	}
+
+	group, err := h.service.RemoveAdmin(c.Request.Context(), request.RemoveGroupAdmin{
+		ChatID:   chatId,
+		SenderID: userId,
+		MemberID: memberId,
+	})
+	if err != nil {
+		errmap.Respond(c, err)
+		return
+	}
+
+	restapi.SendSuccess(c, generic.FromGroupChatDTO(group))
+}
+
+func (h *GroupChatHandler) TransferOwnership(c *gin.Context) {
+	chatId, err := uuid.Parse(c.Param(paramChatID))
+	if err != nil {
+		restapi.SendInvalidChatID(c)
+		return
+	}
+	userId := getUserID(c.Request.Context())
+
+	memberId, err := uuid.Parse(c.Param(paramMemberID))
+	if err != nil {
+		restapi.SendInvalidMemberID(c)
+		return
+	}
+
+	group, err := h.service.TransferOwnership(c.Request.Context(), request.TransferGroupOwnership{
+		ChatID:   chatId,
+		SenderID: userId,
+		MemberID: memberId,
+	})
+	if err != nil {
+		errmap.Respond(c, err)
+		return
+	}
+
+	restapi.SendSuccess(c, generic.FromGroupChatDTO(group))
+}
+
+func (h *GroupChatHandler) LeaveGroup(c *gin.Context) {
+	chatId, err := uuid.Parse(c.Param(paramChatID))
+	if err != nil {
+		restapi.SendInvalidChatID(c)
+		return
+	}
+	userId := getUserID(c.Request.Context())
+
+	group, err := h.service.LeaveGroup(c.Request.Context(), request.LeaveGroup{
+		ChatID:   chatId,
+		SenderID: userId,
+	})
+	if err != nil {
+		errmap.Respond(c, err)
+		return
+	}
+
+	restapi.SendSuccess(c, generic.FromGroupChatDTO(group))
+}
+
+func (h *GroupChatHandler) SetMemberPermissions(c *gin.Context) {
+	chatId, err := uuid.Parse(c.Param(paramChatID))
+	if err != nil {
+		restapi.SendInvalidChatID(c)
+		return
+	}
+	userId := getUserID(c.Request.Context())
+
+	req := struct {
+		Permissions []string `json:"permissions"`
+	}{}
+	if err := c.ShouldBindBodyWithJSON(&req); err != nil {
+		restapi.SendUnprocessableJSON(c)
+		return
+	}
+
+	group, err := h.service.SetMemberPermissions(c.Request.Context(), request.SetMemberPermissions{
+		ChatID:      chatId,
+		SenderID:    userId,
+		Permissions: req.Permissions,
+	})
+	if err != nil {
+		errmap.Respond(c, err)
+		return
+	}
+
+	restapi.SendSuccess(c, generic.FromGroupChatDTO(group))
+}
+
+func (h *GroupChatHandler) SetPostingMode(c *gin.Context) {
+	chatId, err := uuid.Parse(c.Param(paramChatID))
+	if err != nil {
+		restapi.SendInvalidChatID(c)
+		return
+	}
+	userId := getUserID(c.Request.Context())
+
+	req := struct {
+		SlowModeSeconds int  `json:"slow_mode_seconds"`
+		AdminsOnly      bool `json:"admins_only"`
+	}{}
+	if err := c.ShouldBindBodyWithJSON(&req); err != nil {
+		restapi.SendUnprocessableJSON(c)
+		return
+	}
+
+	group, err := h.service.SetPostingMode(c.Request.Context(), request.SetPostingMode{
+		ChatID:          chatId,
+		SenderID:        userId,
+		SlowModeSeconds: req.SlowModeSeconds,
+		AdminsOnly:      req.AdminsOnly,
+	})
+	if err != nil {
+		errmap.Respond(c, err)
+		return
+	}
+
+	restapi.SendSuccess(c, generic.FromGroupChatDTO(group))
+}
+
+func (h *GroupChatHandler) SetHistoryVisibility(c *gin.Context) {
+	chatId, err := uuid.Parse(c.Param(paramChatID))
+	if err != nil {
+		restapi.SendInvalidChatID(c)
+		return
+	}
+	userId := getUserID(c.Request.Context())
+
+	req := struct {
+		HistoryHidden bool `json:"history_hidden"`
+	}{}
+	if err := c.ShouldBindBodyWithJSON(&req); err != nil {
+		restapi.SendUnprocessableJSON(c)
+		return
+	}
+
+	group, err := h.service.SetHistoryVisibility(c.Request.Context(), request.SetHistoryVisibility{
+		ChatID:        chatId,
+		SenderID:      userId,
+		HistoryHidden: req.HistoryHidden,
+	})
+	if err != nil {
+		errmap.Respond(c, err)
+		return
+	}
+
+	restapi.SendSuccess(c, generic.FromGroupChatDTO(group))
+}
+
+func (h *GroupChatHandler) BanMember(c *gin.Context) {
+	chatId, err := uuid.Parse(c.Param(paramChatID))
+	if err != nil {
+		restapi.SendInvalidChatID(c)
+		return
+	}
+	userId := getUserID(c.Request.Context())
+
+	memberId, err := uuid.Parse(c.Param(paramMemberID))
+	if err != nil {
+		restapi.SendInvalidMemberID(c)
+		return
+	}
+
+	req := struct {
+		Reason    string `json:"reason"`
+		ExpiresAt *int64 `json:"expires_at"`
+	}{}
+	if err := c.ShouldBindBodyWithJSON(&req); err != nil {
+		restapi.SendUnprocessableJSON(c)
+		return
+	}
+
+	ban, err := h.service.BanMember(c.Request.Context(), request.BanMember{
+		ChatID:    chatId,
+		SenderID:  userId,
+		MemberID:  memberId,
+		Reason:    req.Reason,
+		ExpiresAt: req.ExpiresAt,
+	})
+	if err != nil {
+		errmap.Respond(c, err)
+		return
+	}
+
+	restapi.SendSuccess(c, generic.FromGroupBanDTO(ban))
+}
+
+func (h *GroupChatHandler) UnbanMember(c *gin.Context) {
+	chatId, err := uuid.Parse(c.Param(paramChatID))
+	if err != nil {
+		restapi.SendInvalidChatID(c)
+		return
+	}
+	userId := getUserID(c.Request.Context())
+
+	memberId, err := uuid.Parse(c.Param(paramMemberID))
+	if err != nil {
+		restapi.SendInvalidMemberID(c)
+		return
+	}
+
+	err = h.service.UnbanMember(c.Request.Context(), request.UnbanMember{
+		ChatID:   chatId,
+		SenderID: userId,
+		MemberID: memberId,
+	})
+	if err != nil {
+		errmap.Respond(c, err)
+		return
+	}
+
+	restapi.SendSuccess(c, struct{}{})
+}
+
+func (h *GroupChatHandler) GetBans(c *gin.Context) {
+	chatId, err := uuid.Parse(c.Param(paramChatID))
+	if err != nil {
+		restapi.SendInvalidChatID(c)
+		return
+	}
+
+	req := request.GetGroupBans{
+		ChatID:   chatId,
+		SenderID: getUserID(c.Request.Context()),
+		Limit:    defaultBansLimit,
+	}
+
+	var errors []restapi.ErrorDetail
+	if offset, ok := c.GetQuery(queryParamOffset); ok {
+		if n, err := strconv.Atoi(offset); err == nil && n >= 0 {
+			req.Offset = n
+		} else {
+			errors = append(errors, restapi.ErrorDetail{
+				Field:   queryParamOffset,
+				Message: "Must be non-negative integer",
+			})
+		}
+	}
+	if limit, ok := c.GetQuery(queryParamLimit); ok {
+		if n, err := strconv.Atoi(limit); err == nil && n > 0 && n <= maxBansLimit {
+			req.Limit = n
+		} else {
+			errors = append(errors, restapi.ErrorDetail{
+				Field:   queryParamLimit,
+				Message: "Must be integer from 1 to " + strconv.Itoa(maxBansLimit),
+			})
+		}
+	}
+	if len(errors) != 0 {
+		restapi.SendValidationError(c, errors)
+		return
+	}
+
+	page, err := h.service.GetBans(c.Request.Context(), req)
+	if err != nil {
+		errmap.Respond(c, err)
+		return
+	}
+
+	restapi.SendSuccess(c, generic.FromGroupBanPageDTO(page))
+}
@@ -0,0 +1,218 @@
+package chat
+
+import (
+	"context"
+
+	"github.com/chakchat/chakchat-backend/messaging-service/internal/application/dto"
+	"github.com/chakchat/chakchat-backend/messaging-service/internal/application/generic"
+	"github.com/chakchat/chakchat-backend/messaging-service/internal/application/request"
+	"github.com/chakchat/chakchat-backend/messaging-service/internal/rest/errmap"
+	"github.com/chakchat/chakchat-backend/messaging-service/internal/rest/restapi"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+const paramToken = "token"
+
+type InviteLinkService interface {
+	CreateInviteLink(ctx context.Context, req request.CreateInviteLink) (*dto.InviteLinkDTO, error)
+	GetInviteLinks(ctx context.Context, req request.GetInviteLinks) ([]dto.InviteLinkDTO, error)
+	RevokeInviteLink(ctx context.Context, req request.RevokeInviteLink) (*dto.InviteLinkDTO, error)
+	JoinByInviteLink(ctx context.Context, req request.JoinByInviteLink) (*dto.GroupChatDTO, error)
+	RequestToJoin(ctx context.Context, req request.JoinByInviteLink) (*dto.JoinRequestDTO, error)
+	GetJoinRequests(ctx context.Context, req request.GetJoinRequests) ([]dto.JoinRequestDTO, error)
+	ApproveJoinRequest(ctx context.Context, req request.ResolveJoinRequest) (*dto.GroupChatDTO, error)
+	DeclineJoinRequest(ctx context.Context, req request.ResolveJoinRequest) error
+}
+
+type InviteLinkHandler struct {
+	service InviteLinkService
+}
+
+func NewInviteLinkHandler(service InviteLinkService) *InviteLinkHandler {
+	return &InviteLinkHandler{
+		service: service,
+	}
+}
+
+func (h *InviteLinkHandler) CreateInviteLink(c *gin.Context) {
+	chatId, err := uuid.Parse(c.Param(paramChatID))
+	if err != nil {
+		restapi.SendInvalidChatID(c)
+		return
+	}
+	userId := getUserID(c.Request.Context())
+
+	req := struct {
+		ExpiresAt        *int64 `json:"expires_at"`
+		UsageLimit       int    `json:"usage_limit"`
+		RequiresApproval bool   `json:"requires_approval"`
+	}{}
+	if err := c.ShouldBindBodyWithJSON(&req); err != nil {
+		restapi.SendUnprocessableJSON(c)
+		return
+	}
+
+	link, err := h.service.CreateInviteLink(c.Request.Context(), request.CreateInviteLink{
+		ChatID:           chatId,
+		SenderID:         userId,
+		ExpiresAt:        req.ExpiresAt,
+		UsageLimit:       req.UsageLimit,
+		RequiresApproval: req.RequiresApproval,
+	})
+	if err != nil {
+		errmap.Respond(c, err)
+		return
+	}
+
+	restapi.SendSuccess(c, generic.FromInviteLinkDTO(link))
+}
+
+func (h *InviteLinkHandler) GetInviteLinks(c *gin.Context) {
+	chatId, err := uuid.Parse(c.Param(paramChatID))
+	if err != nil {
+		restapi.SendInvalidChatID(c)
+		return
+	}
+	userId := getUserID(c.Request.Context())
+
+	links, err := h.service.GetInviteLinks(c.Request.Context(), request.GetInviteLinks{
+		ChatID:   chatId,
+		SenderID: userId,
+	})
+	if err != nil {
+		errmap.Respond(c, err)
+		return
+	}
+
+	restapi.SendSuccess(c, gin.H{
+		"invite_links": generic.FromInviteLinkDTOs(links),
+	})
+}
+
+func (h *InviteLinkHandler) RevokeInviteLink(c *gin.Context) {
+	chatId, err := uuid.Parse(c.Param(paramChatID))
+	if err != nil {
+		restapi.SendInvalidChatID(c)
+		return
+	}
+	userId := getUserID(c.Request.Context())
+
+	link, err := h.service.RevokeInviteLink(c.Request.Context(), request.RevokeInviteLink{
+		ChatID:   chatId,
+		SenderID: userId,
+		Token:    c.Param(paramToken),
+	})
+	if err != nil {
+		errmap.Respond(c, err)
+		return
+	}
+
+	restapi.SendSuccess(c, generic.FromInviteLinkDTO(link))
+}
+
+func (h *InviteLinkHandler) JoinByInviteLink(c *gin.Context) {
+	userId := getUserID(c.Request.Context())
+
+	group, err := h.service.JoinByInviteLink(c.Request.Context(), request.JoinByInviteLink{
+		SenderID: userId,
+		Token:    c.Param(paramToken),
+	})
+	if err != nil {
+		errmap.Respond(c, err)
+		return
+	}
+
+	restapi.SendSuccess(c, generic.FromGroupChatDTO(group))
+}
+
+func (h *InviteLinkHandler) RequestToJoin(c *gin.Context) {
+	userId := getUserID(c.Request.Context())
+
+	joinReq, err := h.service.RequestToJoin(c.Request.Context(), request.JoinByInviteLink{
+		SenderID: userId,
+		Token:    c.Param(paramToken),
+	})
+	if err != nil {
+		errmap.Respond(c, err)
+		return
+	}
+
+	restapi.SendSuccess(c, generic.FromJoinRequestDTO(joinReq))
+}
+
+func (h *InviteLinkHandler) GetJoinRequests(c *gin.Context) {
+	chatId, err := uuid.Parse(c.Param(paramChatID))
+	if err != nil {
+		restapi.SendInvalidChatID(c)
+		return
+	}
+	userId := getUserID(c.Request.Context())
+
+	reqs, err := h.service.GetJoinRequests(c.Request.Context(), request.GetJoinRequests{
+		ChatID:   chatId,
+		SenderID: userId,
+	})
+	if err != nil {
+		errmap.Respond(c, err)
+		return
+	}
+
+	restapi.SendSuccess(c, gin.H{
+		"join_requests": generic.FromJoinRequestDTOs(reqs),
+	})
+}
+
+func (h *InviteLinkHandler) ApproveJoinRequest(c *gin.Context) {
+	chatId, err := uuid.Parse(c.Param(paramChatID))
+	if err != nil {
+		restapi.SendInvalidChatID(c)
+		return
+	}
+	userId := getUserID(c.Request.Context())
+
+	memberId, err := uuid.Parse(c.Param(paramMemberID))
+	if err != nil {
+		restapi.SendInvalidMemberID(c)
+		return
+	}
+
+	group, err := h.service.ApproveJoinRequest(c.Request.Context(), request.ResolveJoinRequest{
+		ChatID:   chatId,
+		SenderID: userId,
+		UserID:   memberId,
+	})
+	if err != nil {
+		errmap.Respond(c, err)
+		return
+	}
+
+	restapi.SendSuccess(c, generic.FromGroupChatDTO(group))
+}
+
+func (h *InviteLinkHandler) DeclineJoinRequest(c *gin.Context) {
+	chatId, err := uuid.Parse(c.Param(paramChatID))
+	if err != nil {
+		restapi.SendInvalidChatID(c)
+		return
+	}
+	userId := getUserID(c.Request.Context())
+
+	memberId, err := uuid.Parse(c.Param(paramMemberID))
+	if err != nil {
+		restapi.SendInvalidMemberID(c)
+		return
+	}
+
+	err = h.service.DeclineJoinRequest(c.Request.Context(), request.ResolveJoinRequest{
+		ChatID:   chatId,
+		SenderID: userId,
+		UserID:   memberId,
+	})
+	if err != nil {
+		errmap.Respond(c, err)
+		return
+	}
+
+	restapi.SendSuccess(c, struct{}{})
+}
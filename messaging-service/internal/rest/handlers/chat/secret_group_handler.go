@@ -20,6 +20,11 @@ type SecretGroupService interface {
 	AddMember(ctx context.Context, req request.AddMember) (*dto.SecretGroupChatDTO, error)
 	DeleteMember(ctx context.Context, req request.DeleteMember) (*dto.SecretGroupChatDTO, error)
 	SetExpiration(ctx context.Context, req request.SetExpiration) (*dto.SecretGroupChatDTO, error)
+	SetAdmin(ctx context.Context, req request.SetGroupAdmin) (*dto.SecretGroupChatDTO, error)
+	RemoveAdmin(ctx context.Context, req request.RemoveGroupAdmin) (*dto.SecretGroupChatDTO, error)
+	TransferOwnership(ctx context.Context, req request.TransferGroupOwnership) (*dto.SecretGroupChatDTO, error)
+	LeaveGroup(ctx context.Context, req request.LeaveGroup) (*dto.SecretGroupChatDTO, error)
+	SetMemberPermissions(ctx context.Context, req request.SetMemberPermissions) (*dto.SecretGroupChatDTO, error)
 }
 
 type SecretGroupHandler struct {
@@ -190,3 +195,142 @@ func (h *SecretGroupHandler) SetExpiration(c *gin.Context) {
 
 	restapi.SendSuccess(c, generic.FromSecretGroupChatDTO(group))
 }
+
+func (h *SecretGroupHandler) SetAdmin(c *gin.Context) {
+	chatId, err := uuid.Parse(c.Param(paramChatID))
+	if err != nil {
+		restapi.SendInvalidChatID(c)
+		return
+	}
+	userId := getUserID(c.Request.Context())
+
+	memberId, err := uuid.Parse(c.Param(paramMemberID))
+	if err != nil {
+		restapi.SendInvalidMemberID(c)
+		return
+	}
+
+	req := struct {
+		Permissions []string `json:"permissions"`
+	}{}
+	if err := c.ShouldBindBodyWithJSON(&req); err != nil {
+		restapi.SendUnprocessableJSON(c)
+		return
+	}
+
+	group, err := h.service.SetAdmin(c.Request.Context(), request.SetGroupAdmin{
+		ChatID:      chatId,
+		SenderID:    userId,
+		MemberID:    memberId,
+		Permissions: req.Permissions,
+	})
+	if err != nil {
+		errmap.Respond(c, err)
+		return
+	}
+
+	restapi.SendSuccess(c, generic.FromSecretGroupChatDTO(group))
+}
+
+func (h *SecretGroupHandler) RemoveAdmin(c *gin.Context) {
+	chatId, err := uuid.Parse(c.Param(paramChatID))
+	if err != nil {
+		restapi.SendInvalidChatID(c)
+		return
+	}
+	userId := getUserID(c.Request.Context())
+
+	memberId, err := uuid.Parse(c.Param(paramMemberID))
+	if err != nil {
+		restapi.SendInvalidMemberID(c)
+		return
+	}
+
+	group, err := h.service.RemoveAdmin(c.Request.Context(), request.RemoveGroupAdmin{
+		ChatID:   chatId,
+		SenderID: userId,
+		MemberID: memberId,
+	})
+	if err != nil {
+		errmap.Respond(c, err)
+		return
+	}
+
+	restapi.SendSuccess(c, generic.FromSecretGroupChatDTO(group))
+}
+
+func (h *SecretGroupHandler) TransferOwnership(c *gin.Context) {
+	chatId, err := uuid.Parse(c.Param(paramChatID))
+	if err != nil {
+		restapi.SendInvalidChatID(c)
+		return
+	}
+	userId := getUserID(c.Request.Context())
+
+	memberId, err := uuid.Parse(c.Param(paramMemberID))
+	if err != nil {
+		restapi.SendInvalidMemberID(c)
+		return
+	}
+
+	group, err := h.service.TransferOwnership(c.Request.Context(), request.TransferGroupOwnership{
+		ChatID:   chatId,
+		SenderID: userId,
+		MemberID: memberId,
+	})
+	if err != nil {
+		errmap.Respond(c, err)
+		return
+	}
+
+	restapi.SendSuccess(c, generic.FromSecretGroupChatDTO(group))
+}
+
+func (h *SecretGroupHandler) LeaveGroup(c *gin.Context) {
+	chatId, err := uuid.Parse(c.Param(paramChatID))
+	if err != nil {
+		restapi.SendInvalidChatID(c)
+		return
+	}
+	userId := getUserID(c.Request.Context())
+
+	group, err := h.service.LeaveGroup(c.Request.Context(), request.LeaveGroup{
+		ChatID:   chatId,
+		SenderID: userId,
+	})
+	if err != nil {
+		errmap.Respond(c, err)
+		return
+	}
+
+	restapi.SendSuccess(c, generic.FromSecretGroupChatDTO(group))
+}
+
+func (h *SecretGroupHandler) SetMemberPermissions(c *gin.Context) {
+	chatId, err := uuid.Parse(c.Param(paramChatID))
+	if err != nil {
+		restapi.SendInvalidChatID(c)
+		return
+	}
+	userId := getUserID(c.Request.Context())
+
+	req := struct {
+		Permissions []string `json:"permissions"`
+	}{}
+	if err := c.ShouldBindBodyWithJSON(&req); err != nil {
+		restapi.SendUnprocessableJSON(c)
+		return
+	}
+
+	group, err := h.service.SetMemberPermissions(c.Request.Context(), request.SetMemberPermissions{
+		ChatID:      chatId,
+		SenderID:    userId,
+		Permissions: req.Permissions,
+	})
+	if err != nil {
+		errmap.Respond(c, err)
+		return
+	}
+
+	restapi.SendSuccess(c, generic.FromSecretGroupChatDTO(group))
+}
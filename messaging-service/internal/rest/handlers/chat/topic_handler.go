@@ -0,0 +1,232 @@
+package chat
+
+import (
+	"context"
+
+	"github.com/chakchat/chakchat-backend/messaging-service/internal/application/dto"
+	"github.com/chakchat/chakchat-backend/messaging-service/internal/application/generic"
+	"github.com/chakchat/chakchat-backend/messaging-service/internal/application/request"
+	"github.com/chakchat/chakchat-backend/messaging-service/internal/rest/errmap"
+	"github.com/chakchat/chakchat-backend/messaging-service/internal/rest/restapi"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+const paramTopicID = "topicId"
+
+type TopicService interface {
+	CreateTopic(ctx context.Context, req request.CreateTopic) (*dto.TopicDTO, error)
+	UpdateTopic(ctx context.Context, req request.UpdateTopic) (*dto.TopicDTO, error)
+	CloseTopic(ctx context.Context, req request.TopicAction) (*dto.TopicDTO, error)
+	ReopenTopic(ctx context.Context, req request.TopicAction) (*dto.TopicDTO, error)
+	DeleteTopic(ctx context.Context, req request.TopicAction) error
+	GetTopics(ctx context.Context, req request.GetTopics) ([]dto.TopicDTO, error)
+	ReadTopic(ctx context.Context, req request.ReadTopic) (int, error)
+}
+
+type TopicHandler struct {
+	service TopicService
+}
+
+func NewTopicHandler(service TopicService) *TopicHandler {
+	return &TopicHandler{
+		service: service,
+	}
+}
+
+func (h *TopicHandler) CreateTopic(c *gin.Context) {
+	chatId, err := uuid.Parse(c.Param(paramChatID))
+	if err != nil {
+		restapi.SendInvalidChatID(c)
+		return
+	}
+	userId := getUserID(c.Request.Context())
+
+	req := struct {
+		Title string `json:"title"`
+		Icon  string `json:"icon"`
+	}{}
+	if err := c.ShouldBindBodyWithJSON(&req); err != nil {
+		restapi.SendUnprocessableJSON(c)
+		return
+	}
+
+	topic, err := h.service.CreateTopic(c.Request.Context(), request.CreateTopic{
+		ChatID:   chatId,
+		SenderID: userId,
+		Title:    req.Title,
+		Icon:     req.Icon,
+	})
+	if err != nil {
+		errmap.Respond(c, err)
+		return
+	}
+
+	restapi.SendSuccess(c, generic.FromTopicDTO(topic))
+}
+
+func (h *TopicHandler) UpdateTopic(c *gin.Context) {
+	chatId, err := uuid.Parse(c.Param(paramChatID))
+	if err != nil {
+		restapi.SendInvalidChatID(c)
+		return
+	}
+	topicId, ok := parseTopicID(c)
+	if !ok {
+		return
+	}
+	userId := getUserID(c.Request.Context())
+
+	req := struct {
+		Title string `json:"title"`
+		Icon  string `json:"icon"`
+	}{}
+	if err := c.ShouldBindBodyWithJSON(&req); err != nil {
+		restapi.SendUnprocessableJSON(c)
+		return
+	}
+
+	topic, err := h.service.UpdateTopic(c.Request.Context(), request.UpdateTopic{
+		ChatID:   chatId,
+		TopicID:  topicId,
+		SenderID: userId,
+		Title:    req.Title,
+		Icon:     req.Icon,
+	})
+	if err != nil {
+		errmap.Respond(c, err)
+		return
+	}
+
+	restapi.SendSuccess(c, generic.FromTopicDTO(topic))
+}
+
+func (h *TopicHandler) CloseTopic(c *gin.Context) {
+	h.handleTopicAction(c, h.service.CloseTopic)
+}
+
+func (h *TopicHandler) ReopenTopic(c *gin.Context) {
+	h.handleTopicAction(c, h.service.ReopenTopic)
+}
+
+func (h *TopicHandler) DeleteTopic(c *gin.Context) {
+	chatId, err := uuid.Parse(c.Param(paramChatID))
+	if err != nil {
+		restapi.SendInvalidChatID(c)
+		return
+	}
+	topicId, ok := parseTopicID(c)
+	if !ok {
+		return
+	}
+	userId := getUserID(c.Request.Context())
+
+	err = h.service.DeleteTopic(c.Request.Context(), request.TopicAction{
+		ChatID:   chatId,
+		TopicID:  topicId,
+		SenderID: userId,
+	})
+	if err != nil {
+		errmap.Respond(c, err)
+		return
+	}
+
+	restapi.SendSuccess(c, struct{}{})
+}
+
+func (h *TopicHandler) GetTopics(c *gin.Context) {
+	chatId, err := uuid.Parse(c.Param(paramChatID))
+	if err != nil {
+		restapi.SendInvalidChatID(c)
+		return
+	}
+	userId := getUserID(c.Request.Context())
+
+	topics, err := h.service.GetTopics(c.Request.Context(), request.GetTopics{
+		ChatID:   chatId,
+		SenderID: userId,
+	})
+	if err != nil {
+		errmap.Respond(c, err)
+		return
+	}
+
+	restapi.SendSuccess(c, gin.H{
+		"topics": generic.FromTopicDTOs(topics),
+	})
+}
+
+func (h *TopicHandler) ReadTopic(c *gin.Context) {
+	chatId, err := uuid.Parse(c.Param(paramChatID))
+	if err != nil {
+		restapi.SendInvalidChatID(c)
+		return
+	}
+	topicId, ok := parseTopicID(c)
+	if !ok {
+		return
+	}
+	userId := getUserID(c.Request.Context())
+
+	req := struct {
+		UpdateID int64 `json:"update_id"`
+	}{}
+	if err := c.ShouldBindBodyWithJSON(&req); err != nil {
+		restapi.SendUnprocessableJSON(c)
+		return
+	}
+
+	unread, err := h.service.ReadTopic(c.Request.Context(), request.ReadTopic{
+		ChatID:   chatId,
+		TopicID:  topicId,
+		SenderID: userId,
+		UpdateID: req.UpdateID,
+	})
+	if err != nil {
+		errmap.Respond(c, err)
+		return
+	}
+
+	restapi.SendSuccess(c, gin.H{
+		"unread_count": unread,
+	})
+}
+
+func (h *TopicHandler) handleTopicAction(
+	c *gin.Context, action func(context.Context, request.TopicAction) (*dto.TopicDTO, error),
+) {
+	chatId, err := uuid.Parse(c.Param(paramChatID))
+	if err != nil {
+		restapi.SendInvalidChatID(c)
+		return
+	}
+	topicId, ok := parseTopicID(c)
+	if !ok {
+		return
+	}
+	userId := getUserID(c.Request.Context())
+
+	topic, err := action(c.Request.Context(), request.TopicAction{
+		ChatID:   chatId,
+		TopicID:  topicId,
+		SenderID: userId,
+	})
+	if err != nil {
+		errmap.Respond(c, err)
+		return
+	}
+
+	restapi.SendSuccess(c, generic.FromTopicDTO(topic))
+}
+
+func parseTopicID(c *gin.Context) (uuid.UUID, bool) {
+	topicId, err := uuid.Parse(c.Param(paramTopicID))
+	if err != nil {
+		restapi.SendValidationError(c, []restapi.ErrorDetail{{
+			Field:   paramTopicID,
+			Message: "Invalid topic ID",
+		}})
+		return uuid.Nil, false
+	}
+	return topicId, true
+}
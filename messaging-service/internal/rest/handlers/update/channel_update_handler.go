@@ -0,0 +1,159 @@
+package update
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/chakchat/chakchat-backend/messaging-service/internal/application/dto"
+	"github.com/chakchat/chakchat-backend/messaging-service/internal/application/generic"
+	"github.com/chakchat/chakchat-backend/messaging-service/internal/application/request"
+	"github.com/chakchat/chakchat-backend/messaging-service/internal/rest/errmap"
+	"github.com/chakchat/chakchat-backend/messaging-service/internal/rest/restapi"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type ChannelUpdateService interface {
+	SendPost(ctx context.Context, req request.SendTextMessage) (*dto.TextMessageDTO, error)
+	EditPost(ctx context.Context, req request.EditTextMessage) (*dto.TextMessageDTO, error)
+	DeletePost(ctx context.Context, req request.DeleteMessage) (*dto.UpdateDeletedDTO, error)
+	ViewPosts(ctx context.Context, req request.ViewPosts) ([]dto.PostViewsDTO, error)
+}
+
+type ChannelUpdateHandler struct {
+	service ChannelUpdateService
+}
+
+func NewChannelUpdateHandler(service ChannelUpdateService) *ChannelUpdateHandler {
+	return &ChannelUpdateHandler{
+		service: service,
+	}
+}
+
+func (h *ChannelUpdateHandler) SendPost(c *gin.Context) {
+	chatID, err := uuid.Parse(c.Param(paramChatID))
+	if err != nil {
+		restapi.SendInvalidChatID(c)
+		return
+	}
+	userID := getUserID(c.Request.Context())
+
+	req := struct {
+		Text     string              `json:"text"`
+		Entities []textEntityRequest `json:"entities"`
+		ReplyTo  *int64              `json:"reply_to"`
+	}{}
+	if err := c.ShouldBindBodyWithJSON(&req); err != nil {
+		restapi.SendUnprocessableJSON(c)
+		return
+	}
+
+	msg, err := h.service.SendPost(c.Request.Context(), request.SendTextMessage{
+		ChatID:         chatID,
+		SenderID:       userID,
+		Text:           req.Text,
+		Entities:       parseTextEntities(req.Entities),
+		ReplyToMessage: req.ReplyTo,
+	})
+	if err != nil {
+		errmap.Respond(c, err)
+		return
+	}
+
+	restapi.SendSuccess(c, generic.FromTextMessageDTO(msg))
+}
+
+func (h *ChannelUpdateHandler) EditPost(c *gin.Context) {
+	chatID, err := uuid.Parse(c.Param(paramChatID))
+	if err != nil {
+		restapi.SendInvalidChatID(c)
+		return
+	}
+	updateID, err := strconv.ParseInt(c.Param(paramUpdateID), 10, 64)
+	if err != nil {
+		restapi.SendInvalidUpdateID(c)
+		return
+	}
+	userID := getUserID(c.Request.Context())
+
+	req := struct {
+		Text     string              `json:"text"`
+		Entities []textEntityRequest `json:"entities"`
+	}{}
+	if err := c.ShouldBindBodyWithJSON(&req); err != nil {
+		restapi.SendUnprocessableJSON(c)
+		return
+	}
+
+	msg, err := h.service.EditPost(c.Request.Context(), request.EditTextMessage{
+		ChatID:      chatID,
+		SenderID:    userID,
+		MessageID:   updateID,
+		NewText:     req.Text,
+		NewEntities: parseTextEntities(req.Entities),
+	})
+	if err != nil {
+		errmap.Respond(c, err)
+		return
+	}
+
+	restapi.SendSuccess(c, generic.FromTextMessageDTO(msg))
+}
+
+func (h *ChannelUpdateHandler) DeletePost(c *gin.Context) {
+	chatID, err := uuid.Parse(c.Param(paramChatID))
+	if err != nil {
+		restapi.SendInvalidChatID(c)
+		return
+	}
+	updateID, err := strconv.ParseInt(c.Param(paramUpdateID), 10, 64)
+	if err != nil {
+		restapi.SendInvalidUpdateID(c)
+		return
+	}
+	userID := getUserID(c.Request.Context())
+
+	deleted, err := h.service.DeletePost(c.Request.Context(), request.DeleteMessage{
+		ChatID:     chatID,
+		SenderID:   userID,
+		MessageID:  updateID,
+		DeleteMode: c.Param(paramDeleteMode),
+	})
+	if err != nil {
+		errmap.Respond(c, err)
+		return
+	}
+
+	restapi.SendSuccess(c, generic.FromUpdateDeletedDTO(deleted))
+}
+
+func (h *ChannelUpdateHandler) ViewPosts(c *gin.Context) {
+	chatID, err := uuid.Parse(c.Param(paramChatID))
+	if err != nil {
+		restapi.SendInvalidChatID(c)
+		return
+	}
+	userID := getUserID(c.Request.Context())
+
+	req := struct {
+		PostIDs []int64 `json:"post_ids"`
+	}{}
+	if err := c.ShouldBindBodyWithJSON(&req); err != nil {
+		restapi.SendUnprocessableJSON(c)
+		return
+	}
+
+	views, err := h.service.ViewPosts(c.Request.Context(), request.ViewPosts{
+		ChatID:   chatID,
+		SenderID: userID,
+		PostIDs:  req.PostIDs,
+	})
+	if err != nil {
+		errmap.Respond(c, err)
+		return
+	}
+
+	restapi.SendSuccess(c, gin.H{
+		"views": generic.FromPostViewsDTOs(views),
+	})
+}
@@ -13,12 +13,16 @@ import (
 )
 
 const (
+	paramThreadRootID = "rootId"
+	paramTopicID      = "topicId"
+
 	queryParamFrom = "from"
 	queryParamTo   = "to"
 )
 
 type GenericUpdateService interface {
 	GetUpdatesRange(context.Context, request.GetUpdatesRange) ([]generic.Update, error)
+	GetThreadUpdatesRange(context.Context, request.GetThreadUpdatesRange) ([]generic.Update, error)
 	GetUpdate(context.Context, request.GetUpdate) (*generic.Update, error)
 }
 
@@ -52,10 +56,20 @@ func (h *GenericUpdateHandler) GetUpdatesRange(c *gin.Context) {
 			Message: "'to' query parameter is required integer",
 		}})
 	}
+	// Topic updates are requested on the topic route only
+	var topicID *uuid.UUID
+	if c.Param(paramTopicID) != "" {
+		id, ok := parseTopicID(c)
+		if !ok {
+			return
+		}
+		topicID = &id
+	}
 	userID := getUserID(c.Request.Context())
 
 	updates, err := h.service.GetUpdatesRange(c.Request.Context(), request.GetUpdatesRange{
 		ChatID:   chatID,
+		TopicID:  topicID,
 		SenderID: userID,
 		From:     from,
 		To:       to,
@@ -69,3 +83,49 @@ func (h *GenericUpdateHandler) GetUpdatesRange(c *gin.Context) {
 		"updates": updates,
 	})
 }
+
+func (h *GenericUpdateHandler) GetThreadUpdatesRange(c *gin.Context) {
+	chatID, err := uuid.Parse(c.Param(paramChatID))
+	if err != nil {
+		restapi.SendInvalidChatID(c)
+		return
+	}
+	rootID, err := strconv.ParseInt(c.Param(paramThreadRootID), 10, 64)
+	if err != nil {
+		restapi.SendInvalidUpdateID(c)
+		return
+	}
+	from, err := strconv.ParseInt(c.Query(queryParamFrom), 10, 64)
+	if err != nil {
+		restapi.SendValidationError(c, []restapi.ErrorDetail{{
+			Field:   queryParamFrom,
+			Message: "'from' query parameter is required integer",
+		}})
+		return
+	}
+	to, err := strconv.ParseInt(c.Query(queryParamTo), 10, 64)
+	if err != nil {
+		restapi.SendValidationError(c, []restapi.ErrorDetail{{
+			Field:   queryParamTo,
+			Message: "'to' query parameter is required integer",
+		}})
+		return
+	}
+	userID := getUserID(c.Request.Context())
+
+	updates, err := h.service.GetThreadUpdatesRange(c.Request.Context(), request.GetThreadUpdatesRange{
+		ChatID:       chatID,
+		SenderID:     userID,
+		ThreadRootID: rootID,
+		From:         from,
+		To:           to,
+	})
+	if err != nil {
+		errmap.Respond(c, err)
+		return
+	}
+
+	restapi.SendSuccess(c, gin.H{
+		"updates": updates,
+	})
+}
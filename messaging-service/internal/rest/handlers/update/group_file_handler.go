@@ -35,8 +35,9 @@ func (h *GroupFileHandler) SendFileMessage(c *gin.Context) {
 	userID := getUserID(c.Request.Context())
 
 	req := struct {
-		FileID  uuid.UUID `json:"file_id"`
-		ReplyTo *int64    `json:"reply_to"`
+		FileID       uuid.UUID `json:"file_id"`
+		ReplyTo      *int64    `json:"reply_to"`
+		ThreadRootID *int64    `json:"thread_root_id"`
 	}{}
 	if err := c.ShouldBindBodyWithJSON(&req); err != nil {
 		restapi.SendUnprocessableJSON(c)
@@ -47,6 +48,7 @@ func (h *GroupFileHandler) SendFileMessage(c *gin.Context) {
 		SenderID:       userID,
 		FileID:         req.FileID,
 		ReplyToMessage: req.ReplyTo,
+		ThreadRootID:   req.ThreadRootID,
 	})
 	if err != nil {
 		errmap.Respond(c, err)
@@ -21,6 +21,8 @@ type GroupUpdateService interface {
 	DeleteReaction(ctx context.Context, req request.DeleteReaction) (*dto.UpdateDeletedDTO, error)
 	ForwardTextMessage(ctx context.Context, req request.ForwardMessage) (*dto.TextMessageDTO, error)
 	ForwardFileMessage(ctx context.Context, req request.ForwardMessage) (*dto.FileMessageDTO, error)
+	PinMessage(ctx context.Context, req request.PinMessage) (*dto.PinDTO, error)
+	UnpinMessage(ctx context.Context, req request.PinMessage) (*dto.PinDTO, error)
 }
 
 type GroupUpdateHandler struct {
@@ -42,18 +44,30 @@ func (h *GroupUpdateHandler) SendTextMessage(c *gin.Context) {
 	userID := getUserID(c.Request.Context())
 
 	req := struct {
-		Text    string `json:"text"`
-		ReplyTo *int64 `json:"reply_to"`
+		Text         string              `json:"text"`
+		Entities     []textEntityRequest `json:"entities"`
+		ReplyTo      *int64              `json:"reply_to"`
+		ThreadRootID *int64              `json:"thread_root_id"`
+		Mentions     []mentionRequest    `json:"mentions"`
 	}{}
 	if err := c.ShouldBindBodyWithJSON(&req); err != nil {
 		restapi.SendUnprocessableJSON(c)
 	}
 
+	mentions, ok := parseMentions(req.Mentions)
+	if !ok {
+		sendInvalidMentionType(c)
+		return
+	}
+
 	msg, err := h.service.SendTextMessage(c.Request.Context(), request.SendTextMessage{
 		ChatID:         chatID,
 		SenderID:       userID,
 		Text:           req.Text,
+		Entities:       parseTextEntities(req.Entities),
 		ReplyToMessage: req.ReplyTo,
+		ThreadRootID:   req.ThreadRootID,
+		Mentions:       mentions,
 	})
 	if err != nil {
 		errmap.Respond(c, err)
@@ -77,17 +91,19 @@ func (h *GroupUpdateHandler) EditTextMessage(c *gin.Context) {
 	userID := getUserID(c.Request.Context())
 
 	req := struct {
-		Text string `json:"text"`
+		Text     string              `json:"text"`
+		Entities []textEntityRequest `json:"entities"`
 	}{}
 	if err := c.ShouldBindBodyWithJSON(&req); err != nil {
 		restapi.SendUnprocessableJSON(c)
 	}
 
 	msg, err := h.service.EditTextMessage(c.Request.Context(), request.EditTextMessage{
-		ChatID:    chatID,
-		SenderID:  userID,
-		MessageID: updateID,
-		NewText:   req.Text,
+		ChatID:      chatID,
+		SenderID:    userID,
+		MessageID:   updateID,
+		NewText:     req.Text,
+		NewEntities: parseTextEntities(req.Entities),
 	})
 	if err != nil {
 		errmap.Respond(c, err)
@@ -240,3 +256,42 @@ func (h *GroupUpdateHandler) ForwardFileMessage(c *gin.Context) {
 
 	restapi.SendSuccess(c, generic.FromFileMessageDTO(msg))
 }
+
+func (h *GroupUpdateHandler) PinMessage(c *gin.Context) {
+	h.setPinned(c, h.service.PinMessage)
+}
+
+func (h *GroupUpdateHandler) UnpinMessage(c *gin.Context) {
+	h.setPinned(c, h.service.UnpinMessage)
+}
+
+func (h *GroupUpdateHandler) setPinned(
+	c *gin.Context, handle func(context.Context, request.PinMessage) (*dto.PinDTO, error),
+) {
+	chatID, err := uuid.Parse(c.Param(paramChatID))
+	if err != nil {
+		restapi.SendInvalidChatID(c)
+		return
+	}
+	userID := getUserID(c.Request.Context())
+
+	req := struct {
+		MessageID int64 `json:"message_id"`
+	}{}
+	if err := c.ShouldBindBodyWithJSON(&req); err != nil {
+		restapi.SendUnprocessableJSON(c)
+		return
+	}
+
+	pin, err := handle(c.Request.Context(), request.PinMessage{
+		ChatID:    chatID,
+		SenderID:  userID,
+		MessageID: req.MessageID,
+	})
+	if err != nil {
+		errmap.Respond(c, err)
+		return
+	}
+
+	restapi.SendSuccess(c, generic.FromPinDTO(pin))
+}
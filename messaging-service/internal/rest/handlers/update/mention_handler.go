@@ -0,0 +1,87 @@
+package update
+
+import (
+	"context"
+
+	"github.com/chakchat/chakchat-backend/messaging-service/internal/application/generic"
+	"github.com/chakchat/chakchat-backend/messaging-service/internal/application/request"
+	"github.com/chakchat/chakchat-backend/messaging-service/internal/rest/errmap"
+	"github.com/chakchat/chakchat-backend/messaging-service/internal/rest/restapi"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type MentionService interface {
+	ReadMentions(ctx context.Context, req request.ReadMentions) (int, error)
+}
+
+type MentionHandler struct {
+	service MentionService
+}
+
+func NewMentionHandler(service MentionService) *MentionHandler {
+	return &MentionHandler{
+		service: service,
+	}
+}
+
+func (h *MentionHandler) ReadMentions(c *gin.Context) {
+	chatID, err := uuid.Parse(c.Param(paramChatID))
+	if err != nil {
+		restapi.SendInvalidChatID(c)
+		return
+	}
+	userID := getUserID(c.Request.Context())
+
+	req := struct {
+		UpdateID int64 `json:"update_id"`
+	}{}
+	if err := c.ShouldBindBodyWithJSON(&req); err != nil {
+		restapi.SendUnprocessableJSON(c)
+		return
+	}
+
+	unread, err := h.service.ReadMentions(c.Request.Context(), request.ReadMentions{
+		ChatID:   chatID,
+		SenderID: userID,
+		UpdateID: req.UpdateID,
+	})
+	if err != nil {
+		errmap.Respond(c, err)
+		return
+	}
+
+	restapi.SendSuccess(c, gin.H{
+		"unread_mentions": unread,
+	})
+}
+
+type mentionRequest struct {
+	Offset int    `json:"offset"`
+	Length int    `json:"length"`
+	Type   string `json:"type"`
+}
+
+func parseMentions(mentions []mentionRequest) ([]request.Mention, bool) {
+	res := make([]request.Mention, len(mentions))
+	for i, m := range mentions {
+		if m.Type != generic.MentionTypeUser && m.Type != generic.MentionTypeEveryone {
+			return nil, false
+		}
+		res[i] = request.Mention{
+			Offset:   m.Offset,
+			Length:   m.Length,
+			Everyone: m.Type == generic.MentionTypeEveryone,
+		}
+	}
+	return res, true
+}
+
+func sendInvalidMentionType(c *gin.Context) {
+	restapi.SendValidationError(c, []restapi.ErrorDetail{
+		{
+			Field:   "mentions",
+			Message: "Mention type must be \"user\" or \"everyone\"",
+		},
+	})
+}
@@ -21,6 +21,8 @@ type PersonalUpdateService interface {
 	DeleteReaction(ctx context.Context, req request.DeleteReaction) (*dto.UpdateDeletedDTO, error)
 	ForwardTextMessage(ctx context.Context, req request.ForwardMessage) (*dto.TextMessageDTO, error)
 	ForwardFileMessage(ctx context.Context, req request.ForwardMessage) (*dto.FileMessageDTO, error)
+	PinMessage(ctx context.Context, req request.PinMessage) (*dto.PinDTO, error)
+	UnpinMessage(ctx context.Context, req request.PinMessage) (*dto.PinDTO, error)
 }
 
 const (
@@ -48,8 +50,9 @@ func (h *PersonalUpdateHandler) SendTextMessage(c *gin.Context) {
 	userID := getUserID(c.Request.Context())
 
 	req := struct {
-		Text    string `json:"text"`
-		ReplyTo *int64 `json:"reply_to"`
+		Text     string              `json:"text"`
+		Entities []textEntityRequest `json:"entities"`
+		ReplyTo  *int64              `json:"reply_to"`
 	}{}
 	if err := c.ShouldBindBodyWithJSON(&req); err != nil {
 		restapi.SendUnprocessableJSON(c)
@@ -59,6 +62,7 @@ func (h *PersonalUpdateHandler) SendTextMessage(c *gin.Context) {
 		ChatID:         chatID,
 		SenderID:       userID,
 		Text:           req.Text,
+		Entities:       parseTextEntities(req.Entities),
 		ReplyToMessage: req.ReplyTo,
 	})
 	if err != nil {
@@ -83,17 +87,19 @@ func (h *PersonalUpdateHandler) EditTextMessage(c *gin.Context) {
 	userID := getUserID(c.Request.Context())
 
 	req := struct {
-		Text string `json:"text"`
+		Text     string              `json:"text"`
+		Entities []textEntityRequest `json:"entities"`
 	}{}
 	if err := c.ShouldBindBodyWithJSON(&req); err != nil {
 		restapi.SendUnprocessableJSON(c)
 	}
 
 	msg, err := h.service.EditTextMessage(c.Request.Context(), request.EditTextMessage{
-		ChatID:    chatID,
-		SenderID:  userID,
-		MessageID: updateID,
-		NewText:   req.Text,
+		ChatID:      chatID,
+		SenderID:    userID,
+		MessageID:   updateID,
+		NewText:     req.Text,
+		NewEntities: parseTextEntities(req.Entities),
 	})
 	if err != nil {
 		errmap.Respond(c, err)
@@ -246,3 +252,42 @@ func (h *PersonalUpdateHandler) ForwardFileMessage(c *gin.Context) {
 
 	restapi.SendSuccess(c, generic.FromFileMessageDTO(msg))
 }
+
+func (h *PersonalUpdateHandler) PinMessage(c *gin.Context) {
+	h.setPinned(c, h.service.PinMessage)
+}
+
+func (h *PersonalUpdateHandler) UnpinMessage(c *gin.Context) {
+	h.setPinned(c, h.service.UnpinMessage)
+}
+
+func (h *PersonalUpdateHandler) setPinned(
+	c *gin.Context, handle func(context.Context, request.PinMessage) (*dto.PinDTO, error),
+) {
+	chatID, err := uuid.Parse(c.Param(paramChatID))
+	if err != nil {
+		restapi.SendInvalidChatID(c)
+		return
+	}
+	userID := getUserID(c.Request.Context())
+
+	req := struct {
+		MessageID int64 `json:"message_id"`
+	}{}
+	if err := c.ShouldBindBodyWithJSON(&req); err != nil {
+		restapi.SendUnprocessableJSON(c)
+		return
+	}
+
+	pin, err := handle(c.Request.Context(), request.PinMessage{
+		ChatID:    chatID,
+		SenderID:  userID,
+		MessageID: req.MessageID,
+	})
+	if err != nil {
+		errmap.Respond(c, err)
+		return
+	}
+
+	restapi.SendSuccess(c, generic.FromPinDTO(pin))
+}
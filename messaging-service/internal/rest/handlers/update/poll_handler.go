@@ -0,0 +1,157 @@
+package update
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/chakchat/chakchat-backend/messaging-service/internal/application/dto"
+	"github.com/chakchat/chakchat-backend/messaging-service/internal/application/generic"
+	"github.com/chakchat/chakchat-backend/messaging-service/internal/application/request"
+	"github.com/chakchat/chakchat-backend/messaging-service/internal/rest/errmap"
+	"github.com/chakchat/chakchat-backend/messaging-service/internal/rest/restapi"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type PollService interface {
+	SendPoll(ctx context.Context, req request.SendPoll) (*dto.PollDTO, error)
+	Vote(ctx context.Context, req request.VotePoll) (*dto.PollDTO, error)
+	RetractVote(ctx context.Context, req request.RetractPollVote) (*dto.PollDTO, error)
+	ClosePoll(ctx context.Context, req request.ClosePoll) (*dto.PollDTO, error)
+}
+
+type PollHandler struct {
+	service PollService
+}
+
+func NewPollHandler(service PollService) *PollHandler {
+	return &PollHandler{
+		service: service,
+	}
+}
+
+func (h *PollHandler) SendPoll(c *gin.Context) {
+	chatID, err := uuid.Parse(c.Param(paramChatID))
+	if err != nil {
+		restapi.SendInvalidChatID(c)
+		return
+	}
+	userID := getUserID(c.Request.Context())
+
+	req := struct {
+		Question       string   `json:"question"`
+		Options        []string `json:"options"`
+		MultipleChoice bool     `json:"multiple_choice"`
+		Anonymous      bool     `json:"anonymous"`
+		ClosesAt       *int64   `json:"closes_at"`
+		ReplyTo        *int64   `json:"reply_to"`
+	}{}
+	if err := c.ShouldBindBodyWithJSON(&req); err != nil {
+		restapi.SendUnprocessableJSON(c)
+		return
+	}
+
+	poll, err := h.service.SendPoll(c.Request.Context(), request.SendPoll{
+		ChatID:         chatID,
+		SenderID:       userID,
+		Question:       req.Question,
+		Options:        req.Options,
+		MultipleChoice: req.MultipleChoice,
+		Anonymous:      req.Anonymous,
+		ClosesAt:       req.ClosesAt,
+		ReplyToMessage: req.ReplyTo,
+	})
+	if err != nil {
+		errmap.Respond(c, err)
+		return
+	}
+
+	restapi.SendSuccess(c, generic.FromPollDTO(poll))
+}
+
+func (h *PollHandler) Vote(c *gin.Context) {
+	chatID, err := uuid.Parse(c.Param(paramChatID))
+	if err != nil {
+		restapi.SendInvalidChatID(c)
+		return
+	}
+	pollID, err := strconv.ParseInt(c.Param(paramUpdateID), 10, 64)
+	if err != nil {
+		restapi.SendInvalidUpdateID(c)
+		return
+	}
+	userID := getUserID(c.Request.Context())
+
+	req := struct {
+		Options []int `json:"options"`
+	}{}
+	if err := c.ShouldBindBodyWithJSON(&req); err != nil {
+		restapi.SendUnprocessableJSON(c)
+		return
+	}
+
+	poll, err := h.service.Vote(c.Request.Context(), request.VotePoll{
+		ChatID:   chatID,
+		SenderID: userID,
+		PollID:   pollID,
+		Options:  req.Options,
+	})
+	if err != nil {
+		errmap.Respond(c, err)
+		return
+	}
+
+	restapi.SendSuccess(c, generic.FromPollDTO(poll))
+}
+
+func (h *PollHandler) RetractVote(c *gin.Context) {
+	chatID, err := uuid.Parse(c.Param(paramChatID))
+	if err != nil {
+		restapi.SendInvalidChatID(c)
+		return
+	}
+	pollID, err := strconv.ParseInt(c.Param(paramUpdateID), 10, 64)
+	if err != nil {
+		restapi.SendInvalidUpdateID(c)
+		return
+	}
+	userID := getUserID(c.Request.Context())
+
+	poll, err := h.service.RetractVote(c.Request.Context(), request.RetractPollVote{
+		ChatID:   chatID,
+		SenderID: userID,
+		PollID:   pollID,
+	})
+	if err != nil {
+		errmap.Respond(c, err)
+		return
+	}
+
+	restapi.SendSuccess(c, generic.FromPollDTO(poll))
+}
+
+func (h *PollHandler) ClosePoll(c *gin.Context) {
+	chatID, err := uuid.Parse(c.Param(paramChatID))
+	if err != nil {
+		restapi.SendInvalidChatID(c)
+		return
+	}
+	pollID, err := strconv.ParseInt(c.Param(paramUpdateID), 10, 64)
+	if err != nil {
+		restapi.SendInvalidUpdateID(c)
+		return
+	}
+	userID := getUserID(c.Request.Context())
+
+	poll, err := h.service.ClosePoll(c.Request.Context(), request.ClosePoll{
+		ChatID:   chatID,
+		SenderID: userID,
+		PollID:   pollID,
+	})
+	if err != nil {
+		errmap.Respond(c, err)
+		return
+	}
+
+	restapi.SendSuccess(c, generic.FromPollDTO(poll))
+}
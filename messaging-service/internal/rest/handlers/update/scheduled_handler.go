@@ -0,0 +1,198 @@
+package update
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/chakchat/chakchat-backend/messaging-service/internal/application/dto"
+	"github.com/chakchat/chakchat-backend/messaging-service/internal/application/generic"
+	"github.com/chakchat/chakchat-backend/messaging-service/internal/application/request"
+	"github.com/chakchat/chakchat-backend/messaging-service/internal/rest/errmap"
+	"github.com/chakchat/chakchat-backend/messaging-service/internal/rest/restapi"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+const paramScheduledID = "scheduledId"
+
+type ScheduledMessageService interface {
+	ScheduleTextMessage(ctx context.Context, req request.ScheduleTextMessage) (*dto.ScheduledMessageDTO, error)
+	ScheduleFileMessage(ctx context.Context, req request.ScheduleFileMessage) (*dto.ScheduledMessageDTO, error)
+	GetScheduledMessages(ctx context.Context, req request.GetScheduledMessages) ([]dto.ScheduledMessageDTO, error)
+	EditScheduledMessage(ctx context.Context, req request.EditScheduledMessage) (*dto.ScheduledMessageDTO, error)
+	CancelScheduledMessage(ctx context.Context, req request.CancelScheduledMessage) error
+}
+
+type ScheduledMessageHandler struct {
+	service ScheduledMessageService
+}
+
+func NewScheduledMessageHandler(service ScheduledMessageService) *ScheduledMessageHandler {
+	return &ScheduledMessageHandler{
+		service: service,
+	}
+}
+
+func (h *ScheduledMessageHandler) ScheduleTextMessage(c *gin.Context) {
+	chatID, err := uuid.Parse(c.Param(paramChatID))
+	if err != nil {
+		restapi.SendInvalidChatID(c)
+		return
+	}
+	userID := getUserID(c.Request.Context())
+
+	req := struct {
+		Text     string              `json:"text"`
+		Entities []textEntityRequest `json:"entities"`
+		ReplyTo  *int64              `json:"reply_to"`
+		SendAt   int64               `json:"send_at"`
+	}{}
+	if err := c.ShouldBindBodyWithJSON(&req); err != nil {
+		restapi.SendUnprocessableJSON(c)
+		return
+	}
+
+	msg, err := h.service.ScheduleTextMessage(c.Request.Context(), request.ScheduleTextMessage{
+		ChatID:         chatID,
+		SenderID:       userID,
+		Text:           req.Text,
+		Entities:       parseTextEntities(req.Entities),
+		ReplyToMessage: req.ReplyTo,
+		SendAt:         req.SendAt,
+	})
+	if err != nil {
+		errmap.Respond(c, err)
+		return
+	}
+
+	restapi.SendSuccess(c, generic.FromScheduledMessageDTO(msg))
+}
+
+func (h *ScheduledMessageHandler) ScheduleFileMessage(c *gin.Context) {
+	chatID, err := uuid.Parse(c.Param(paramChatID))
+	if err != nil {
+		restapi.SendInvalidChatID(c)
+		return
+	}
+	userID := getUserID(c.Request.Context())
+
+	req := struct {
+		FileID  uuid.UUID `json:"file_id"`
+		ReplyTo *int64    `json:"reply_to"`
+		SendAt  int64     `json:"send_at"`
+	}{}
+	if err := c.ShouldBindBodyWithJSON(&req); err != nil {
+		restapi.SendUnprocessableJSON(c)
+		return
+	}
+
+	msg, err := h.service.ScheduleFileMessage(c.Request.Context(), request.ScheduleFileMessage{
+		ChatID:         chatID,
+		SenderID:       userID,
+		FileID:         req.FileID,
+		ReplyToMessage: req.ReplyTo,
+		SendAt:         req.SendAt,
+	})
+	if err != nil {
+		errmap.Respond(c, err)
+		return
+	}
+
+	restapi.SendSuccess(c, generic.FromScheduledMessageDTO(msg))
+}
+
+func (h *ScheduledMessageHandler) GetScheduledMessages(c *gin.Context) {
+	chatID, err := uuid.Parse(c.Param(paramChatID))
+	if err != nil {
+		restapi.SendInvalidChatID(c)
+		return
+	}
+	userID := getUserID(c.Request.Context())
+
+	msgs, err := h.service.GetScheduledMessages(c.Request.Context(), request.GetScheduledMessages{
+		ChatID:   chatID,
+		SenderID: userID,
+	})
+	if err != nil {
+		errmap.Respond(c, err)
+		return
+	}
+
+	restapi.SendSuccess(c, gin.H{
+		"scheduled": generic.FromScheduledMessageDTOs(msgs),
+	})
+}
+
+func (h *ScheduledMessageHandler) EditScheduledMessage(c *gin.Context) {
+	chatID, err := uuid.Parse(c.Param(paramChatID))
+	if err != nil {
+		restapi.SendInvalidChatID(c)
+		return
+	}
+	scheduledID, err := strconv.ParseInt(c.Param(paramScheduledID), 10, 64)
+	if err != nil {
+		sendInvalidScheduledID(c)
+		return
+	}
+	userID := getUserID(c.Request.Context())
+
+	req := struct {
+		NewText     string              `json:"new_text"`
+		NewEntities []textEntityRequest `json:"new_entities"`
+		SendAt      int64               `json:"send_at"`
+	}{}
+	if err := c.ShouldBindBodyWithJSON(&req); err != nil {
+		restapi.SendUnprocessableJSON(c)
+		return
+	}
+
+	msg, err := h.service.EditScheduledMessage(c.Request.Context(), request.EditScheduledMessage{
+		ChatID:      chatID,
+		SenderID:    userID,
+		ScheduledID: scheduledID,
+		NewText:     req.NewText,
+		NewEntities: parseTextEntities(req.NewEntities),
+		SendAt:      req.SendAt,
+	})
+	if err != nil {
+		errmap.Respond(c, err)
+		return
+	}
+
+	restapi.SendSuccess(c, generic.FromScheduledMessageDTO(msg))
+}
+
+func (h *ScheduledMessageHandler) CancelScheduledMessage(c *gin.Context) {
+	chatID, err := uuid.Parse(c.Param(paramChatID))
+	if err != nil {
+		restapi.SendInvalidChatID(c)
+		return
+	}
+	scheduledID, err := strconv.ParseInt(c.Param(paramScheduledID), 10, 64)
+	if err != nil {
+		sendInvalidScheduledID(c)
+		return
+	}
+	userID := getUserID(c.Request.Context())
+
+	err = h.service.CancelScheduledMessage(c.Request.Context(), request.CancelScheduledMessage{
+		ChatID:      chatID,
+		SenderID:    userID,
+		ScheduledID: scheduledID,
+	})
+	if err != nil {
+		errmap.Respond(c, err)
+		return
+	}
+
+	restapi.SendSuccess(c, struct{}{})
+}
+
+func sendInvalidScheduledID(c *gin.Context) {
+	restapi.SendValidationError(c, []restapi.ErrorDetail{
+		{
+			Field:   paramScheduledID,
+			Message: "Invalid scheduledId route parameter",
+		},
+	})
+}
@@ -0,0 +1,136 @@
+package update
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/chakchat/chakchat-backend/messaging-service/internal/application/generic"
+	"github.com/chakchat/chakchat-backend/messaging-service/internal/application/request"
+	"github.com/chakchat/chakchat-backend/messaging-service/internal/domain"
+	"github.com/chakchat/chakchat-backend/messaging-service/internal/rest/errmap"
+	"github.com/chakchat/chakchat-backend/messaging-service/internal/rest/restapi"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+const (
+	queryParamPattern  = "pattern"
+	queryParamSenderID = "sender_id"
+	queryParamSince    = "since"
+	queryParamUntil    = "until"
+	queryParamOffset   = "offset"
+	queryParamLimit    = "limit"
+
+	defaultSearchLimit = 20
+	maxSearchLimit     = 100
+)
+
+type SearchService interface {
+	SearchMessages(context.Context, request.SearchMessages) (*generic.SearchResult, error)
+}
+
+type SearchHandler struct {
+	service SearchService
+}
+
+func NewSearchHandler(service SearchService) *SearchHandler {
+	return &SearchHandler{
+		service: service,
+	}
+}
+
+// SearchMessages searches across all chats of the user.
+func (h *SearchHandler) SearchMessages(c *gin.Context) {
+	h.search(c, nil, "")
+}
+
+func (h *SearchHandler) SearchPersonalMessages(c *gin.Context) {
+	h.searchInChat(c, domain.ChatTypePersonal)
+}
+
+func (h *SearchHandler) SearchGroupMessages(c *gin.Context) {
+	h.searchInChat(c, domain.ChatTypeGroup)
+}
+
+func (h *SearchHandler) searchInChat(c *gin.Context, chatType string) {
+	chatID, err := uuid.Parse(c.Param(paramChatID))
+	if err != nil {
+		restapi.SendInvalidChatID(c)
+		return
+	}
+	h.search(c, &chatID, chatType)
+}
+
+func (h *SearchHandler) search(c *gin.Context, chatID *uuid.UUID, chatType string) {
+	req := request.SearchMessages{
+		SenderID: getUserID(c.Request.Context()),
+		Query:    c.Query(queryParamPattern),
+		ChatID:   chatID,
+		ChatType: chatType,
+		Limit:    defaultSearchLimit,
+	}
+
+	var errors []restapi.ErrorDetail
+	if senderID, ok := c.GetQuery(queryParamSenderID); ok {
+		if id, err := uuid.Parse(senderID); err == nil {
+			req.FromSenderID = &id
+		} else {
+			errors = append(errors, restapi.ErrorDetail{
+				Field:   queryParamSenderID,
+				Message: "Must be UUID",
+			})
+		}
+	}
+	if since, ok := c.GetQuery(queryParamSince); ok {
+		if ts, err := strconv.ParseInt(since, 10, 64); err == nil {
+			req.Since = &ts
+		} else {
+			errors = append(errors, restapi.ErrorDetail{
+				Field:   queryParamSince,
+				Message: "Must be unix timestamp",
+			})
+		}
+	}
+	if until, ok := c.GetQuery(queryParamUntil); ok {
+		if ts, err := strconv.ParseInt(until, 10, 64); err == nil {
+			req.Until = &ts
+		} else {
+			errors = append(errors, restapi.ErrorDetail{
+				Field:   queryParamUntil,
+				Message: "Must be unix timestamp",
+			})
+		}
+	}
+	if offset, ok := c.GetQuery(queryParamOffset); ok {
+		if n, err := strconv.Atoi(offset); err == nil && n >= 0 {
+			req.Offset = n
+		} else {
+			errors = append(errors, restapi.ErrorDetail{
+				Field:   queryParamOffset,
+				Message: "Must be non-negative integer",
+			})
+		}
+	}
+	if limit, ok := c.GetQuery(queryParamLimit); ok {
+		if n, err := strconv.Atoi(limit); err == nil && n > 0 && n <= maxSearchLimit {
+			req.Limit = n
+		} else {
+			errors = append(errors, restapi.ErrorDetail{
+				Field:   queryParamLimit,
+				Message: "Must be integer from 1 to " + strconv.Itoa(maxSearchLimit),
+			})
+		}
+	}
+	if len(errors) != 0 {
+		restapi.SendValidationError(c, errors)
+		return
+	}
+
+	res, err := h.service.SearchMessages(c.Request.Context(), req)
+	if err != nil {
+		errmap.Respond(c, err)
+		return
+	}
+
+	restapi.SendSuccess(c, res)
+}
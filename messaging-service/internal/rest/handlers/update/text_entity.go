@@ -0,0 +1,27 @@
+package update
+
+import "github.com/chakchat/chakchat-backend/messaging-service/internal/application/request"
+
+type textEntityRequest struct {
+	Type   string `json:"type"`
+	Offset int    `json:"offset"`
+	Length int    `json:"length"`
+	URL    string `json:"url"`
+}
+
+func parseTextEntities(entities []textEntityRequest) []request.TextEntity {
+	if len(entities) == 0 {
+		return nil
+	}
+
+	res := make([]request.TextEntity, len(entities))
+	for i, e := range entities {
+		res[i] = request.TextEntity{
+			Type:   e.Type,
+			Offset: e.Offset,
+			Length: e.Length,
+			URL:    e.URL,
+		}
+	}
+	return res
+}
@@ -0,0 +1,154 @@
+package update
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/chakchat/chakchat-backend/messaging-service/internal/application/dto"
+	"github.com/chakchat/chakchat-backend/messaging-service/internal/application/generic"
+	"github.com/chakchat/chakchat-backend/messaging-service/internal/application/request"
+	"github.com/chakchat/chakchat-backend/messaging-service/internal/rest/errmap"
+	"github.com/chakchat/chakchat-backend/messaging-service/internal/rest/restapi"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type TopicUpdateService interface {
+	SendTextMessage(ctx context.Context, req request.SendTopicTextMessage) (*dto.TextMessageDTO, error)
+	EditTextMessage(ctx context.Context, req request.EditTopicTextMessage) (*dto.TextMessageDTO, error)
+	DeleteMessage(ctx context.Context, req request.DeleteTopicMessage) (*dto.UpdateDeletedDTO, error)
+}
+
+type TopicUpdateHandler struct {
+	service TopicUpdateService
+}
+
+func NewTopicUpdateHandler(service TopicUpdateService) *TopicUpdateHandler {
+	return &TopicUpdateHandler{
+		service: service,
+	}
+}
+
+func (h *TopicUpdateHandler) SendTextMessage(c *gin.Context) {
+	chatID, err := uuid.Parse(c.Param(paramChatID))
+	if err != nil {
+		restapi.SendInvalidChatID(c)
+		return
+	}
+	topicID, ok := parseTopicID(c)
+	if !ok {
+		return
+	}
+	userID := getUserID(c.Request.Context())
+
+	req := struct {
+		Text     string              `json:"text"`
+		Entities []textEntityRequest `json:"entities"`
+		ReplyTo  *int64              `json:"reply_to"`
+	}{}
+	if err := c.ShouldBindBodyWithJSON(&req); err != nil {
+		restapi.SendUnprocessableJSON(c)
+		return
+	}
+
+	msg, err := h.service.SendTextMessage(c.Request.Context(), request.SendTopicTextMessage{
+		ChatID:         chatID,
+		TopicID:        topicID,
+		SenderID:       userID,
+		Text:           req.Text,
+		Entities:       parseTextEntities(req.Entities),
+		ReplyToMessage: req.ReplyTo,
+	})
+	if err != nil {
+		errmap.Respond(c, err)
+		return
+	}
+
+	restapi.SendSuccess(c, generic.FromTextMessageDTO(msg))
+}
+
+func (h *TopicUpdateHandler) EditTextMessage(c *gin.Context) {
+	chatID, err := uuid.Parse(c.Param(paramChatID))
+	if err != nil {
+		restapi.SendInvalidChatID(c)
+		return
+	}
+	topicID, ok := parseTopicID(c)
+	if !ok {
+		return
+	}
+	updateID, err := strconv.ParseInt(c.Param(paramUpdateID), 10, 64)
+	if err != nil {
+		restapi.SendInvalidUpdateID(c)
+		return
+	}
+	userID := getUserID(c.Request.Context())
+
+	req := struct {
+		Text     string              `json:"text"`
+		Entities []textEntityRequest `json:"entities"`
+	}{}
+	if err := c.ShouldBindBodyWithJSON(&req); err != nil {
+		restapi.SendUnprocessableJSON(c)
+		return
+	}
+
+	msg, err := h.service.EditTextMessage(c.Request.Context(), request.EditTopicTextMessage{
+		ChatID:      chatID,
+		TopicID:     topicID,
+		SenderID:    userID,
+		MessageID:   updateID,
+		NewText:     req.Text,
+		NewEntities: parseTextEntities(req.Entities),
+	})
+	if err != nil {
+		errmap.Respond(c, err)
+		return
+	}
+
+	restapi.SendSuccess(c, generic.FromTextMessageDTO(msg))
+}
+
+func (h *TopicUpdateHandler) DeleteMessage(c *gin.Context) {
+	chatID, err := uuid.Parse(c.Param(paramChatID))
+	if err != nil {
+		restapi.SendInvalidChatID(c)
+		return
+	}
+	topicID, ok := parseTopicID(c)
+	if !ok {
+		return
+	}
+	updateID, err := strconv.ParseInt(c.Param(paramUpdateID), 10, 64)
+	if err != nil {
+		restapi.SendInvalidUpdateID(c)
+		return
+	}
+	userID := getUserID(c.Request.Context())
+
+	deleted, err := h.service.DeleteMessage(c.Request.Context(), request.DeleteTopicMessage{
+		ChatID:     chatID,
+		TopicID:    topicID,
+		SenderID:   userID,
+		MessageID:  updateID,
+		DeleteMode: c.Param(paramDeleteMode),
+	})
+	if err != nil {
+		errmap.Respond(c, err)
+		return
+	}
+
+	restapi.SendSuccess(c, generic.FromUpdateDeletedDTO(deleted))
+}
+
+func parseTopicID(c *gin.Context) (uuid.UUID, bool) {
+	topicID, err := uuid.Parse(c.Param(paramTopicID))
+	if err != nil {
+		restapi.SendValidationError(c, []restapi.ErrorDetail{{
+			Field:   paramTopicID,
+			Message: "Invalid topic ID",
+		}})
+		return uuid.Nil, false
+	}
+	return topicID, true
+}
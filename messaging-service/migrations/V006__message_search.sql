@@ -0,0 +1,21 @@
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+-- Russian configuration stems Latin words with the English stemmer,
+-- so both languages are searched by word forms.
+-- It is copied to be tuned without touching the code.
+CREATE TEXT SEARCH CONFIGURATION messaging.message_search (COPY = pg_catalog.russian);
+
+-- Edits overwrite the text, so the vector always matches the latest version of the message
+ALTER TABLE messaging.text_message_update
+    ADD COLUMN text_search TSVECTOR
+        GENERATED ALWAYS AS (to_tsvector('messaging.message_search', text)) STORED;
+
+CREATE INDEX text_message_update_text_search_idx
+    ON messaging.text_message_update USING GIN (text_search);
+
+-- Used for substring search by ILIKE
+CREATE INDEX text_message_update_text_trgm_idx
+    ON messaging.text_message_update USING GIN (text gin_trgm_ops);
+
+CREATE INDEX update_deleted_update_deleted_update_id_idx
+    ON messaging.update_deleted_update (chat_id, deleted_update_id);
@@ -0,0 +1,11 @@
+-- Thread messages and all updates related to them (edits, reactions, deletions)
+-- form a separate stream of the chat.
+ALTER TABLE messaging.update
+    ADD COLUMN thread_root_id BIGINT,
+    ADD FOREIGN KEY (chat_id, thread_root_id)
+        REFERENCES messaging.update (chat_id, update_id)
+        ON DELETE CASCADE;
+
+CREATE INDEX update_thread_idx
+    ON messaging.update (chat_id, thread_root_id, update_id)
+    WHERE thread_root_id IS NOT NULL;
@@ -0,0 +1,20 @@
+ALTER TYPE messaging.update_type ADD VALUE 'message_pinned';
+ALTER TYPE messaging.update_type ADD VALUE 'message_unpinned';
+
+-- A message is pinned if its latest pin update has 'message_pinned' type
+CREATE TABLE messaging.pin_update (
+    chat_id UUID NOT NULL,
+    update_id BIGINT NOT NULL,
+    message_id BIGINT NOT NULL,
+
+    PRIMARY KEY (chat_id, update_id),
+    FOREIGN KEY (chat_id, update_id) 
+        REFERENCES messaging.update (chat_id, update_id) 
+        ON DELETE CASCADE,
+    FOREIGN KEY (chat_id, message_id)
+        REFERENCES messaging.update (chat_id, update_id) 
+        ON DELETE CASCADE
+);
+
+CREATE INDEX pin_update_message_idx
+    ON messaging.pin_update (chat_id, message_id, update_id);
@@ -0,0 +1,47 @@
+ALTER TYPE messaging.update_type ADD VALUE 'poll';
+
+CREATE TABLE messaging.poll_update (
+    chat_id UUID NOT NULL,
+    update_id BIGINT NOT NULL,
+    question TEXT NOT NULL,
+    multiple_choice BOOLEAN NOT NULL,
+    anonymous BOOLEAN NOT NULL,
+    closes_at TIMESTAMPTZ,
+    closed BOOLEAN NOT NULL DEFAULT FALSE,
+    reply_to_id BIGINT,
+
+    PRIMARY KEY (chat_id, update_id),
+    FOREIGN KEY (chat_id, update_id) 
+        REFERENCES messaging.update (chat_id, update_id) 
+        ON DELETE CASCADE,
+    FOREIGN KEY (chat_id, reply_to_id)
+        REFERENCES messaging.update (chat_id, update_id) 
+        ON DELETE CASCADE
+);
+
+CREATE TABLE messaging.poll_option (
+    chat_id UUID NOT NULL,
+    poll_id BIGINT NOT NULL,
+    option_index SMALLINT NOT NULL,
+    text VARCHAR(255) NOT NULL,
+
+    PRIMARY KEY (chat_id, poll_id, option_index),
+    FOREIGN KEY (chat_id, poll_id) 
+        REFERENCES messaging.poll_update (chat_id, update_id) 
+        ON DELETE CASCADE
+);
+
+-- Voters of anonymous polls are stored as well to prevent voting twice,
+-- but they must never be returned to clients.
+CREATE TABLE messaging.poll_vote (
+    chat_id UUID NOT NULL,
+    poll_id BIGINT NOT NULL,
+    option_index SMALLINT NOT NULL,
+    user_id UUID NOT NULL,
+    voted_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+
+    PRIMARY KEY (chat_id, poll_id, option_index, user_id),
+    FOREIGN KEY (chat_id, poll_id, option_index) 
+        REFERENCES messaging.poll_option (chat_id, poll_id, option_index) 
+        ON DELETE CASCADE
+);
@@ -0,0 +1,19 @@
+-- Scheduled messages aren't updates until they are sent
+CREATE TABLE messaging.scheduled_message (
+    scheduled_id BIGINT GENERATED ALWAYS AS IDENTITY PRIMARY KEY,
+    chat_id UUID NOT NULL REFERENCES messaging.chat (chat_id) ON DELETE CASCADE,
+    sender_id UUID NOT NULL,
+    text TEXT,
+    file_id UUID,
+    reply_to_id BIGINT,
+    send_at TIMESTAMPTZ NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+
+    CHECK ((text IS NULL) <> (file_id IS NULL))
+);
+
+CREATE INDEX scheduled_message_send_at_idx
+    ON messaging.scheduled_message (send_at);
+
+CREATE INDEX scheduled_message_chat_sender_idx
+    ON messaging.scheduled_message (chat_id, sender_id, send_at);
@@ -0,0 +1,25 @@
+CREATE TABLE messaging.mention (
+    chat_id UUID NOT NULL,
+    update_id BIGINT NOT NULL,
+    mention_offset INT NOT NULL,
+    mention_length INT NOT NULL,
+    -- NULL if everyone is mentioned
+    user_id UUID,
+
+    PRIMARY KEY (chat_id, update_id, mention_offset),
+    FOREIGN KEY (chat_id, update_id) 
+        REFERENCES messaging.text_message_update (chat_id, update_id) 
+        ON DELETE CASCADE
+);
+
+CREATE INDEX mention_user_idx
+    ON messaging.mention (chat_id, user_id, update_id);
+
+-- Mentions up to read_update_id are read by the user
+CREATE TABLE messaging.mention_read (
+    chat_id UUID NOT NULL REFERENCES messaging.chat (chat_id) ON DELETE CASCADE,
+    user_id UUID NOT NULL,
+    read_update_id BIGINT NOT NULL,
+
+    PRIMARY KEY (chat_id, user_id)
+);
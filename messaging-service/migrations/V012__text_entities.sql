@@ -0,0 +1,25 @@
+CREATE TYPE messaging.text_entity_type AS ENUM (
+    'bold',
+    'italic',
+    'code',
+    'pre',
+    'spoiler',
+    'link',
+    'mention'
+);
+
+-- Entities of text messages and their edits
+CREATE TABLE messaging.text_entity (
+    chat_id UUID NOT NULL,
+    update_id BIGINT NOT NULL,
+    entity_offset INT NOT NULL,
+    entity_length INT NOT NULL,
+    entity_type messaging.text_entity_type NOT NULL,
+    -- Set only for links
+    url TEXT,
+
+    PRIMARY KEY (chat_id, update_id, entity_offset),
+    FOREIGN KEY (chat_id, update_id) 
+        REFERENCES messaging.update (chat_id, update_id) 
+        ON DELETE CASCADE
+);
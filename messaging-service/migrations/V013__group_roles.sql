@@ -0,0 +1,20 @@
+-- admin_id of group chats is the group owner.
+-- Owner has all admin permissions, so it is never stored here.
+CREATE TABLE messaging.group_admin (
+    chat_id UUID NOT NULL,
+    user_id UUID NOT NULL,
+    -- Bit set of domain.AdminPermissions
+    permissions INT NOT NULL,
+
+    PRIMARY KEY (chat_id, user_id),
+    FOREIGN KEY (user_id, chat_id)
+        REFERENCES messaging.membership (user_id, chat_id)
+        ON DELETE CASCADE
+);
+
+-- Bit set of domain.MemberPermissions. 7 allows everything
+ALTER TABLE messaging.group_chat
+    ADD COLUMN member_permissions INT NOT NULL DEFAULT 7;
+
+ALTER TABLE messaging.secret_group_chat
+    ADD COLUMN member_permissions INT NOT NULL DEFAULT 7;
@@ -0,0 +1,4 @@
+-- Join time decides who becomes the group owner when the owner leaves.
+-- clock_timestamp() keeps the order of members inserted by a single statement.
+ALTER TABLE messaging.membership
+    ADD COLUMN joined_at TIMESTAMPTZ NOT NULL DEFAULT clock_timestamp();
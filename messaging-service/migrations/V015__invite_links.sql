@@ -0,0 +1,25 @@
+-- Revoked links are kept, so their pending join requests stay valid
+CREATE TABLE messaging.invite_link (
+    token TEXT PRIMARY KEY,
+    chat_id UUID NOT NULL REFERENCES messaging.group_chat (chat_id) ON DELETE CASCADE,
+    creator_id UUID NOT NULL,
+    expires_at TIMESTAMPTZ,
+    -- 0 means unlimited
+    usage_limit INT NOT NULL DEFAULT 0,
+    usage_count INT NOT NULL DEFAULT 0,
+    requires_approval BOOLEAN NOT NULL DEFAULT FALSE,
+    revoked BOOLEAN NOT NULL DEFAULT FALSE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX invite_link_chat_id_idx
+    ON messaging.invite_link (chat_id, created_at);
+
+CREATE TABLE messaging.join_request (
+    chat_id UUID NOT NULL REFERENCES messaging.group_chat (chat_id) ON DELETE CASCADE,
+    user_id UUID NOT NULL,
+    token TEXT NOT NULL REFERENCES messaging.invite_link (token) ON DELETE CASCADE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+
+    PRIMARY KEY (chat_id, user_id)
+);
@@ -0,0 +1,16 @@
+-- Banned users can't be added to the group or join it by an invite link.
+-- Expired bans are ignored and replaced when the user is banned again.
+CREATE TABLE messaging.group_ban (
+    chat_id UUID NOT NULL REFERENCES messaging.group_chat (chat_id) ON DELETE CASCADE,
+    user_id UUID NOT NULL,
+    banned_by UUID NOT NULL,
+    reason TEXT NOT NULL DEFAULT '',
+    -- NULL means the ban never expires
+    expires_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+
+    PRIMARY KEY (chat_id, user_id)
+);
+
+CREATE INDEX group_ban_chat_created_idx
+    ON messaging.group_ban (chat_id, created_at DESC);
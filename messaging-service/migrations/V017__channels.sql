@@ -0,0 +1,95 @@
+ALTER TYPE messaging.chat_type ADD VALUE 'channel';
+
+-- admin_id is the channel owner, other admins are stored in group_admin.
+-- Subscribers are stored in membership like members of other chats.
+CREATE TABLE messaging.channel (
+    chat_id UUID NOT NULL PRIMARY KEY REFERENCES messaging.chat (chat_id) ON DELETE CASCADE,
+    admin_id UUID NOT NULL,
+    channel_name VARCHAR(255) NOT NULL,
+    channel_description TEXT NOT NULL DEFAULT '',
+    -- Kept here because counting millions of subscribers on every read is too slow
+    subscribers_count BIGINT NOT NULL DEFAULT 0
+);
+
+-- Subscribers are paged by user_id when an event is fanned out
+CREATE INDEX membership_chat_user_idx
+    ON messaging.membership (chat_id, user_id);
+
+CREATE OR REPLACE FUNCTION messaging.check_chat_type() RETURNS TRIGGER AS $$
+DECLARE
+    must_chat_type messaging.chat_type;
+BEGIN
+    IF TG_TABLE_NAME = 'personal_chat' THEN
+        must_chat_type := 'personal';
+    ELSIF TG_TABLE_NAME = 'group_chat' THEN
+        must_chat_type := 'group';
+    ELSIF TG_TABLE_NAME = 'secret_personal_chat' THEN
+        must_chat_type := 'secret_personal';
+    ELSIF TG_TABLE_NAME = 'secret_group_chat' THEN
+        must_chat_type := 'secret_group';
+    ELSIF TG_TABLE_NAME = 'channel' THEN
+        must_chat_type := 'channel';
+    ELSE
+        RAISE EXCEPTION 'Unknown chat relation %', TG_TABLE_NAME;
+    END IF;
+
+    IF (SELECT chat_type != must_chat_type FROM messaging.chat WHERE chat_id = NEW.chat_id) THEN
+        RAISE EXCEPTION 'The created chat must be of type %', must_chat_type;
+    END IF;
+
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER ensure_channel_chat_type
+    BEFORE INSERT
+    ON messaging.channel
+    FOR EACH ROW
+EXECUTE PROCEDURE messaging.check_chat_type();
+
+CREATE TRIGGER ensure_cannot_delete_from_channel_t
+    BEFORE DELETE
+    ON messaging.channel
+    FOR EACH ROW
+EXECUTE PROCEDURE messaging.check_cannot_delete_subchat();
+
+--------------------------------------------------------------------------------
+
+-- Each user views a post at most once, views_count is the number of such rows
+CREATE TABLE messaging.channel_post_view (
+    chat_id UUID NOT NULL,
+    update_id BIGINT NOT NULL,
+    user_id UUID NOT NULL,
+
+    PRIMARY KEY (chat_id, update_id, user_id),
+    FOREIGN KEY (chat_id, update_id)
+        REFERENCES messaging.update (chat_id, update_id)
+        ON DELETE CASCADE
+);
+
+CREATE TABLE messaging.channel_post_stats (
+    chat_id UUID NOT NULL,
+    update_id BIGINT NOT NULL,
+    views_count BIGINT NOT NULL DEFAULT 0,
+
+    PRIMARY KEY (chat_id, update_id),
+    FOREIGN KEY (chat_id, update_id)
+        REFERENCES messaging.update (chat_id, update_id)
+        ON DELETE CASCADE
+);
+
+--------------------------------------------------------------------------------
+
+-- Events for channel subscribers are not published in a single message.
+-- They are stored here and delivered in batches of subscribers by a background dispatcher.
+CREATE TABLE messaging.channel_fanout (
+    fanout_id BIGSERIAL PRIMARY KEY,
+    chat_id UUID NOT NULL REFERENCES messaging.channel (chat_id) ON DELETE CASCADE,
+    -- The sender doesn't receive its own event
+    sender_id UUID NOT NULL,
+    event_type VARCHAR(64) NOT NULL,
+    event_data JSONB NOT NULL,
+    -- The last subscriber the event is delivered to. NULL means delivery hasn't started
+    last_user_id UUID,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
@@ -0,0 +1,57 @@
+ALTER TYPE messaging.chat_type ADD VALUE 'group_topic';
+
+-- Each topic is a chat of group_topic type, so its updates are stored with topic_id as chat_id
+-- and have an independent update_id sequence in chat_sequence.
+-- Topics have no membership, the members are the ones of the group.
+CREATE TABLE messaging.group_topic (
+    topic_id UUID NOT NULL PRIMARY KEY REFERENCES messaging.chat (chat_id) ON DELETE CASCADE,
+    chat_id UUID NOT NULL REFERENCES messaging.group_chat (chat_id) ON DELETE CASCADE,
+    title VARCHAR(255) NOT NULL,
+    icon VARCHAR(64) NOT NULL DEFAULT '',
+    creator_id UUID NOT NULL,
+    closed BOOLEAN NOT NULL DEFAULT FALSE
+);
+
+CREATE INDEX group_topic_chat_idx
+    ON messaging.group_topic (chat_id);
+
+CREATE FUNCTION messaging.check_topic_chat_type() RETURNS TRIGGER AS $$
+BEGIN
+    IF (SELECT chat_type != 'group_topic' FROM messaging.chat WHERE chat_id = NEW.topic_id) THEN
+        RAISE EXCEPTION 'The created chat must be of type group_topic';
+    END IF;
+
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER ensure_group_topic_chat_type
+    BEFORE INSERT
+    ON messaging.group_topic
+    FOR EACH ROW
+EXECUTE PROCEDURE messaging.check_topic_chat_type();
+
+-- Topics are deleted with the group, so their chats (and updates) are deleted too
+CREATE FUNCTION messaging.delete_topic_chat() RETURNS TRIGGER AS $$
+BEGIN
+    DELETE FROM messaging.chat WHERE chat_id = OLD.topic_id;
+    RETURN OLD;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER delete_topic_chat_t
+    AFTER DELETE
+    ON messaging.group_topic
+    FOR EACH ROW
+EXECUTE PROCEDURE messaging.delete_topic_chat();
+
+--------------------------------------------------------------------------------
+
+-- Messages of a topic up to read_update_id are read by the user
+CREATE TABLE messaging.topic_read (
+    topic_id UUID NOT NULL REFERENCES messaging.group_topic (topic_id) ON DELETE CASCADE,
+    user_id UUID NOT NULL,
+    read_update_id BIGINT NOT NULL,
+
+    PRIMARY KEY (topic_id, user_id)
+);
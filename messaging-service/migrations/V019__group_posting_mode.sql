@@ -0,0 +1,8 @@
+-- Zero slow_mode_seconds means slow mode is off
+ALTER TABLE messaging.group_chat
+    ADD COLUMN slow_mode_seconds INT NOT NULL DEFAULT 0,
+    ADD COLUMN admins_only BOOLEAN NOT NULL DEFAULT FALSE;
+
+-- Slow mode looks for the last messages of members sent recently
+CREATE INDEX update_created_at_idx
+    ON messaging.update (chat_id, created_at);
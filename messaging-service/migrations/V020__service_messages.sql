@@ -0,0 +1,25 @@
+ALTER TYPE messaging.update_type ADD VALUE 'member_added';
+ALTER TYPE messaging.update_type ADD VALUE 'member_removed';
+ALTER TYPE messaging.update_type ADD VALUE 'info_changed';
+ALTER TYPE messaging.update_type ADD VALUE 'photo_changed';
+ALTER TYPE messaging.update_type ADD VALUE 'expiration_changed';
+
+-- Service messages record changes of the chat itself.
+-- Only the columns of the update type are set, others are NULL.
+CREATE TABLE messaging.service_message_update (
+    chat_id UUID NOT NULL,
+    update_id BIGINT NOT NULL,
+    -- Added or removed members
+    members UUID[],
+    name VARCHAR(255),
+    description TEXT,
+    -- Empty if the photo is deleted
+    photo TEXT,
+    -- NULL on expiration_changed means expiration is turned off
+    expiration_seconds BIGINT,
+
+    PRIMARY KEY (chat_id, update_id),
+    FOREIGN KEY (chat_id, update_id)
+        REFERENCES messaging.update (chat_id, update_id)
+        ON DELETE CASCADE
+);
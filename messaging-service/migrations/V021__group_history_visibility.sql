@@ -0,0 +1,21 @@
+ALTER TABLE messaging.group_chat
+    ADD COLUMN history_hidden BOOLEAN NOT NULL DEFAULT FALSE;
+
+-- The first update the member can see if the group history is hidden.
+-- It is set when the member joins, so it is the update recording the join.
+-- Members who were in the chat from the beginning see the whole history.
+ALTER TABLE messaging.membership
+    ADD COLUMN visible_from_update_id BIGINT NOT NULL DEFAULT 0;
+
+-- Returns 0 if the whole history of the chat is visible to the member
+CREATE FUNCTION messaging.get_visible_from_update_id(chat UUID, member UUID)
+RETURNS BIGINT AS $$
+    SELECT COALESCE((
+        SELECT m.visible_from_update_id
+        FROM messaging.membership m
+            JOIN messaging.group_chat g ON g.chat_id = m.chat_id
+        WHERE m.chat_id = chat
+            AND m.user_id = member
+            AND g.history_hidden
+    ), 0)
+$$ LANGUAGE sql STABLE;
@@ -0,0 +1,31 @@
+-- Topics have their own update_id sequences, so the boundary of the group is useless for them.
+-- The first update of each topic the member can see is set when the member joins the group.
+-- Topics created after the member joined have no boundary and are visible entirely.
+CREATE TABLE messaging.topic_history_boundary (
+    topic_id UUID NOT NULL REFERENCES messaging.group_topic (topic_id) ON DELETE CASCADE,
+    user_id UUID NOT NULL,
+    visible_from_update_id BIGINT NOT NULL,
+
+    PRIMARY KEY (topic_id, user_id)
+);
+
+-- Returns 0 if the whole history of the chat or the topic is visible to the member
+CREATE OR REPLACE FUNCTION messaging.get_visible_from_update_id(chat UUID, member UUID)
+RETURNS BIGINT AS $$
+    SELECT COALESCE((
+        SELECT m.visible_from_update_id
+        FROM messaging.membership m
+            JOIN messaging.group_chat g ON g.chat_id = m.chat_id
+        WHERE m.chat_id = chat
+            AND m.user_id = member
+            AND g.history_hidden
+    ), (
+        SELECT b.visible_from_update_id
+        FROM messaging.topic_history_boundary b
+            JOIN messaging.group_topic t ON t.topic_id = b.topic_id
+            JOIN messaging.group_chat g ON g.chat_id = t.chat_id
+        WHERE b.topic_id = chat
+            AND b.user_id = member
+            AND g.history_hidden
+    ), 0)
+$$ LANGUAGE sql STABLE;
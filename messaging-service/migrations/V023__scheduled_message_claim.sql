@@ -0,0 +1,5 @@
+-- A due message is claimed by a dispatcher before it is sent,
+-- so other dispatchers skip it while it is being sent.
+-- The claim expires if the dispatcher fails, then the message is sent again.
+ALTER TABLE messaging.scheduled_message
+    ADD COLUMN claimed_until TIMESTAMPTZ;
@@ -0,0 +1,11 @@
+-- Entities of scheduled text messages. They are copied to the message when it is sent.
+CREATE TABLE messaging.scheduled_text_entity (
+    scheduled_id BIGINT NOT NULL REFERENCES messaging.scheduled_message (scheduled_id) ON DELETE CASCADE,
+    entity_offset INT NOT NULL,
+    entity_length INT NOT NULL,
+    entity_type messaging.text_entity_type NOT NULL,
+    -- Set only for links
+    url TEXT,
+
+    PRIMARY KEY (scheduled_id, entity_offset)
+);
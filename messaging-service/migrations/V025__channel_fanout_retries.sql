@@ -0,0 +1,6 @@
+-- A fanout that fails to be dispatched is retried later, so it doesn't hold the other fanouts.
+-- It is parked after too many attempts and isn't dispatched anymore.
+ALTER TABLE messaging.channel_fanout
+    ADD COLUMN attempts INT NOT NULL DEFAULT 0,
+    ADD COLUMN retry_at TIMESTAMPTZ,
+    ADD COLUMN parked_at TIMESTAMPTZ;
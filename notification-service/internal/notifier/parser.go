@@ -42,6 +42,17 @@ type DeleteMessageContent struct {
 	DeletedMode string `json:"deleted_mode"`
 }
 
+type LoginCodeMessage struct {
+	Message string `json:"message"`
+}
+
+type NewLoginMessage struct {
+	Time       time.Time `json:"time"`
+	IP         string    `json:"ip"`
+	UserAgent  string    `json:"user_agent"`
+	DeviceType string    `json:"device_type"`
+}
+
 type CreateChatMessage struct {
 	SenderID uuid.UUID `json:"sender_id"`
 	Chat     *struct {
@@ -87,18 +98,46 @@ func (p *Parser) ParseNotification(ctx context.Context, raw []byte) (string, err
 	}
 
 	switch notific.Type {
-	case "update":
+	// Thread updates are sent only to thread participants, so they are notified as regular updates
+	case "update", "thread_update":
 		return p.ParseUpdateNotification(ctx, notific.Data)
+	case "mention":
+		return p.ParseMention(ctx, notific.Data)
 	case "chat_created":
 		return p.ParseChatCreated(ctx, notific.Data)
 	case "group_info_updated":
 		return p.ParseGroupInfoUpdated(ctx, notific.Data)
 	case "group_members_added", "group_members_removed":
 		return p.ParseGroupMembersChanged(ctx, notific.Type, notific.Data)
+	case "login_code":
+		return p.ParseLoginCode(notific.Data)
+	case "new_login":
+		return p.ParseNewLogin(notific.Data)
 	}
 	return "", nil
 }
 
+func (p *Parser) ParseMention(ctx context.Context, data json.RawMessage) (string, error) {
+	var update UpdateMessage
+	if err := json.Unmarshal(data, &update); err != nil {
+		return "", err
+	}
+	var content TextMessageContent
+	if err := json.Unmarshal(update.Content, &content); err != nil {
+		return "", err
+	}
+
+	sender, err := p.grpcHandler.GetName(ctx, update.SenderID)
+	if err != nil {
+		return "", err
+	}
+	groupName, err := p.grpcHandler.GetGroupName()
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%s mentioned you in %s: %s", *sender, groupName, Truncate(content.Text, 30)), nil
+}
+
 func (p *Parser) ParseUpdateNotification(ctx context.Context, data json.RawMessage) (string, error) {
 	var update UpdateMessage
 	if err := json.Unmarshal(data, &update); err != nil {
@@ -158,6 +197,17 @@ func (p *Parser) ParseUpdateNotification(ctx context.Context, data json.RawMessa
 			return "", err
 		}
 		return fmt.Sprintf("%s put new reaction: %s", *sender, content.Reaction), nil
+	case "message_pinned":
+		sender, err := p.grpcHandler.GetName(ctx, update.SenderID)
+		if err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("%s pinned a message", *sender), nil
+	case "message_unpinned":
+		return "", nil
+	case "poll":
+		// Poll is published again on every vote, so it can't be told apart from a new poll
+		return "", nil
 	case "delete":
 		return "", nil
 	}
@@ -172,6 +222,29 @@ func (p *Parser) ParseChatCreated(ctx context.Context, data json.RawMessage) (st
 	return fmt.Sprintf("New %s chat: %s", chat.Chat.Type, chat.Chat.Name), nil
 }
 
+func (p *Parser) ParseLoginCode(data json.RawMessage) (string, error) {
+	var code LoginCodeMessage
+	if err := json.Unmarshal(data, &code); err != nil {
+		return "", err
+	}
+	return code.Message, nil
+}
+
+func (p *Parser) ParseNewLogin(data json.RawMessage) (string, error) {
+	var login NewLoginMessage
+	if err := json.Unmarshal(data, &login); err != nil {
+		return "", err
+	}
+	device := login.DeviceType
+	if device == "" {
+		device = "unknown device"
+	}
+	if login.IP == "" {
+		return fmt.Sprintf("New login from %s", device), nil
+	}
+	return fmt.Sprintf("New login from %s (%s)", device, login.IP), nil
+}
+
 func (p *Parser) ParseGroupInfoUpdated(ctx context.Context, data json.RawMessage) (string, error) {
 	var groupInfo GroupInfoUpdated
 	if err := json.Unmarshal(data, &groupInfo); err != nil {
@@ -3,10 +3,14 @@ package idempotency
 import (
 	"bytes"
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
 	"log"
 	"net/http"
 	"strings"
 
+	"github.com/chakchat/chakchat-backend/shared/go/auth"
 	"github.com/chakchat/chakchat-backend/shared/go/internal/restapi"
 	"github.com/gin-gonic/gin"
 )
@@ -17,6 +21,9 @@ type CapturedResponse struct {
 	StatusCode int
 	Headers    http.Header
 	Body       []byte
+	// Hash of the request the response was captured for.
+	// Empty fingerprint matches any request.
+	Fingerprint string
 }
 
 type IdempotencyStorage interface {
@@ -24,17 +31,25 @@ type IdempotencyStorage interface {
 	Store(ctx context.Context, key string, resp *CapturedResponse) error
 }
 
-func New(storage IdempotencyStorage) gin.HandlerFunc {
+// Locker marks the key as in progress so the request is executed only once across all replicas.
+type Locker interface {
+	// Should return ok=false if the key is already locked.
+	// Returned token must be passed to Unlock.
+	TryLock(ctx context.Context, key string) (token string, ok bool, err error)
+	Unlock(ctx context.Context, key string, token string) error
+}
+
+func New(storage IdempotencyStorage, locker Locker) gin.HandlerFunc {
 	m := &idempotencyMiddleware{
 		storage: storage,
-		lock:    NewLocker(),
+		locker:  locker,
 	}
 	return m.Handle
 }
 
 type idempotencyMiddleware struct {
 	storage IdempotencyStorage
-	lock    *Locker
+	locker  Locker
 }
 
 func (m *idempotencyMiddleware) Handle(c *gin.Context) {
@@ -50,17 +65,39 @@ func (m *idempotencyMiddleware) Handle(c *gin.Context) {
 		return
 	}
 
-	m.lock.Lock(key)
-	defer m.lock.Unlock(key)
+	fingerprint, err := requestFingerprint(c)
+	if err != nil {
+		log.Printf("idempotency middleware: reading request body failed: %s", err)
+		restapi.SendInternalError(c)
+		return
+	}
 
-	cached, ok, err := m.storage.Get(c.Request.Context(), key)
+	if m.respondCached(c, key, fingerprint) {
+		return
+	}
+
+	token, locked, err := m.locker.TryLock(c.Request.Context(), key)
 	if err != nil {
-		log.Printf("idempotency middleware: gettings cached response failed: %s", err)
+		log.Printf("idempotency middleware: locking key failed: %s", err)
 		restapi.SendInternalError(c)
 		return
 	}
-	if ok {
-		writeCached(c, cached)
+	if !locked {
+		c.JSON(http.StatusConflict, restapi.ErrorResponse{
+			ErrorType:    restapi.ErrTypeIdempotencyKeyInProgress,
+			ErrorMessage: "Request with the same \"" + HeaderIdempotencyKey + "\" is in progress",
+		})
+		return
+	}
+	defer func() {
+		// Request context may be already cancelled but the key must be unlocked anyway
+		if err := m.locker.Unlock(context.WithoutCancel(c.Request.Context()), key, token); err != nil {
+			log.Printf("idempotency middleware: unlocking key failed: %s", err)
+		}
+	}()
+
+	// The response could be stored by another replica between the first check and locking
+	if m.respondCached(c, key, fingerprint) {
 		return
 	}
 	// Check if storage (and this func too) was cancelled
@@ -74,6 +111,7 @@ func (m *idempotencyMiddleware) Handle(c *gin.Context) {
 	c.Next()
 
 	if resp := capturer.ExtractResponse(); captureCondition(resp) {
+		resp.Fingerprint = fingerprint
 		// I think I must guarantee that idempotent endpoint will NOT be re-executed
 		// So, some retries are performed if storing response fails
 		// Store() looks idempotent so everything is okay
@@ -92,8 +130,60 @@ func (m *idempotencyMiddleware) Handle(c *gin.Context) {
 	}
 }
 
+// Returns true if the response is written
+func (m *idempotencyMiddleware) respondCached(c *gin.Context, key, fingerprint string) bool {
+	cached, ok, err := m.storage.Get(c.Request.Context(), key)
+	if err != nil {
+		log.Printf("idempotency middleware: gettings cached response failed: %s", err)
+		restapi.SendInternalError(c)
+		return true
+	}
+	if !ok {
+		return false
+	}
+
+	if cached.Fingerprint != "" && cached.Fingerprint != fingerprint {
+		c.JSON(http.StatusUnprocessableEntity, restapi.ErrorResponse{
+			ErrorType:    restapi.ErrTypeIdempotencyKeyReused,
+			ErrorMessage: "\"" + HeaderIdempotencyKey + "\" was already used for another request",
+		})
+		return true
+	}
+
+	writeCached(c, cached)
+	return true
+}
+
+// Fingerprint is a hash of method, path, user and body.
+// The body is read entirely and then restored for the handler.
+func requestFingerprint(c *gin.Context) (string, error) {
+	var body []byte
+	if c.Request.Body != nil {
+		var err error
+		body, err = io.ReadAll(c.Request.Body)
+		if err != nil {
+			return "", err
+		}
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+	}
+
+	var user string
+	if claims := auth.GetClaims(c.Request.Context()); claims != nil {
+		user, _ = claims[auth.ClaimId].(string)
+	}
+
+	h := sha256.New()
+	for _, part := range []string{c.Request.Method, c.Request.URL.Path, user} {
+		h.Write([]byte(part))
+		h.Write([]byte{0})
+	}
+	h.Write(body)
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
 func captureCondition(resp *CapturedResponse) bool {
-	return resp.StatusCode < 500
+	// Rate limited request wasn't executed so it should be retried with the same key
+	return resp.StatusCode < 500 && resp.StatusCode != http.StatusTooManyRequests
 }
 
 func copyHeaders(src http.Header, dst http.Header) {
@@ -33,9 +33,10 @@ func NewStorage(client *redis.Client, conf *IdempotencyConfig) IdempotencyStorag
 }
 
 type cachedRespMeta struct {
-	StatusCode int         `json:"status_code"`
-	Headers    http.Header `json:"headers"`
-	BodyKey    string      `json:"body_key"`
+	StatusCode  int         `json:"status_code"`
+	Headers     http.Header `json:"headers"`
+	BodyKey     string      `json:"body_key"`
+	Fingerprint string      `json:"fingerprint"`
 }
 
 func (s *idempotencyStorage) Get(ctx context.Context, key string) (*CapturedResponse, bool, error) {
@@ -60,9 +61,10 @@ func (s *idempotencyStorage) Get(ctx context.Context, key string) (*CapturedResp
 	}
 
 	resp := &CapturedResponse{
-		StatusCode: meta.StatusCode,
-		Headers:    meta.Headers,
-		Body:       body,
+		StatusCode:  meta.StatusCode,
+		Headers:     meta.Headers,
+		Body:        body,
+		Fingerprint: meta.Fingerprint,
 	}
 	return resp, true, nil
 }
@@ -82,9 +84,10 @@ func (s *idempotencyStorage) getBody(ctx context.Context, bodyKey string) ([]byt
 
 func (s *idempotencyStorage) Store(ctx context.Context, key string, resp *CapturedResponse) error {
 	meta := cachedRespMeta{
-		StatusCode: resp.StatusCode,
-		Headers:    resp.Headers,
-		BodyKey:    prefixIdempotencyData + uuid.NewString(),
+		StatusCode:  resp.StatusCode,
+		Headers:     resp.Headers,
+		BodyKey:     prefixIdempotencyData + uuid.NewString(),
+		Fingerprint: resp.Fingerprint,
 	}
 
 	metaJson, err := json.Marshal(meta)
@@ -76,30 +76,55 @@ func TestSlowExecutionFastRetry(t *testing.T) {
 
 	// Act
 	wg := sync.WaitGroup{}
-	var resp1, resp2, resp3 *httptest.ResponseRecorder
-	wg.Add(3)
-	go func() {
-		resp1 = execute(r, "/200-slow", idempotencyKey)
-		wg.Done()
-	}()
-	go func() {
-		resp2 = execute(r, "/200-slow", idempotencyKey)
-		wg.Done()
-	}()
-	go func() {
-		resp3 = execute(r, "/200-slow", idempotencyKey)
-		wg.Done()
-	}()
+	resps := make([]*httptest.ResponseRecorder, 3)
+	for i := range resps {
+		wg.Add(1)
+		go func() {
+			resps[i] = execute(r, "/200-slow", idempotencyKey)
+			wg.Done()
+		}()
+	}
 	wg.Wait()
+	retry := execute(r, "/200-slow", idempotencyKey)
+
+	// Assert
+	var succeeded *httptest.ResponseRecorder
+	for _, resp := range resps {
+		if resp.Code == http.StatusConflict {
+			continue
+		}
+		assert.Nil(t, succeeded, "Only one request must be executed")
+		succeeded = resp
+	}
+	assert.NotNil(t, succeeded)
+	assert.Equal(t, succeeded.Code, retry.Code)
+	assert.Equal(t, succeeded.Body.String(), retry.Body.String())
+}
+
+func TestKeyReusedForAnotherRequest(t *testing.T) {
+	// Arrange
+	r, _ := setUp()
+	r.POST("/200", func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+	const idempotencyKey = "5f0d1e34-6a1c-4a57-bf3d-0d2f0f8b6a11"
+
+	// Act
+	first := executeWithBody(r, "/200", idempotencyKey, `{"text":"first"}`)
+	second := executeWithBody(r, "/200", idempotencyKey, `{"text":"second"}`)
 
 	// Assert
-	assert.Equal(t, resp1, resp2)
-	assert.Equal(t, resp1, resp3)
+	assert.Equal(t, http.StatusOK, first.Code)
+	assert.Equal(t, http.StatusUnprocessableEntity, second.Code)
 }
 
 func execute(r *gin.Engine, path, idempotencyKey string) *httptest.ResponseRecorder {
+	return executeWithBody(r, path, idempotencyKey, "")
+}
+
+func executeWithBody(r *gin.Engine, path, idempotencyKey, body string) *httptest.ResponseRecorder {
 	respRecorder := httptest.NewRecorder()
-	req := httptest.NewRequest(http.MethodPost, path, strings.NewReader(""))
+	req := httptest.NewRequest(http.MethodPost, path, strings.NewReader(body))
 	req.Header[HeaderIdempotencyKey] = []string{idempotencyKey}
 
 	r.ServeHTTP(respRecorder, req)
@@ -108,21 +133,52 @@ func execute(r *gin.Engine, path, idempotencyKey string) *httptest.ResponseRecor
 
 func setUp() (*gin.Engine, *mockIdempotencyStorage) {
 	r := gin.New()
-	mockStorage := &mockIdempotencyStorage{map[string]*CapturedResponse{}}
-	r.Use(New(mockStorage))
+	mockStorage := &mockIdempotencyStorage{m: map[string]*CapturedResponse{}}
+	mockLocker := &mockLocker{m: map[string]string{}}
+	r.Use(New(mockStorage, mockLocker))
 	return r, mockStorage
 }
 
 type mockIdempotencyStorage struct {
-	m map[string]*CapturedResponse
+	mu sync.Mutex
+	m  map[string]*CapturedResponse
 }
 
 func (s *mockIdempotencyStorage) Get(_ context.Context, key string) (*CapturedResponse, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
 	resp, ok := s.m[key]
 	return resp, ok, nil
 }
 
 func (s *mockIdempotencyStorage) Store(_ context.Context, key string, resp *CapturedResponse) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
 	s.m[key] = resp
 	return nil
 }
+
+type mockLocker struct {
+	mu sync.Mutex
+	m  map[string]string
+}
+
+func (l *mockLocker) TryLock(_ context.Context, key string) (string, bool, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if _, ok := l.m[key]; ok {
+		return "", false, nil
+	}
+	token := key + ":token"
+	l.m[key] = token
+	return token, true, nil
+}
+
+func (l *mockLocker) Unlock(_ context.Context, key string, token string) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.m[key] == token {
+		delete(l.m, key)
+	}
+	return nil
+}
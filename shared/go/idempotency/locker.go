@@ -1,53 +1,59 @@
 package idempotency
 
-import "sync"
-
-type counterMu struct {
-	mu     sync.Mutex
-	locked int
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+)
+
+const prefixIdempotencyLock = "IdempotencyData:Lock:"
+
+// Deletes the lock only if it is still owned by the token.
+// Otherwise the lock has expired and may be already acquired by another request.
+var unlockScript = redis.NewScript(`
+if redis.call('GET', KEYS[1]) == ARGV[1] then
+	return redis.call('DEL', KEYS[1])
+end
+return 0
+`)
+
+type LockerConfig struct {
+	// Lock is released automatically after LockExp if the replica holding it crashed.
+	// It should be longer than any request execution.
+	LockExp time.Duration
 }
 
-type Locker struct {
-	keyMu map[string]*counterMu
-	mu    sync.Mutex
+type redisLocker struct {
+	conf   *LockerConfig
+	client *redis.Client
 }
 
-func NewLocker() *Locker {
-	return &Locker{
-		keyMu: make(map[string]*counterMu),
-		mu:    sync.Mutex{},
+func NewLocker(client *redis.Client, conf *LockerConfig) Locker {
+	return &redisLocker{
+		conf:   conf,
+		client: client,
 	}
 }
 
-func (l *Locker) Lock(key string) {
-	l.mu.Lock()
-	keyed, ok := l.keyMu[key]
-	if !ok {
-		keyed = new(counterMu)
-		l.keyMu[key] = keyed
-	}
-	keyed.locked++
-	l.mu.Unlock()
-
-	keyed.mu.Lock()
-}
+func (l *redisLocker) TryLock(ctx context.Context, key string) (string, bool, error) {
+	token := uuid.NewString()
 
-func (l *Locker) Unlock(key string) {
-	l.mu.Lock()
-	keyed, ok := l.keyMu[key]
+	ok, err := l.client.SetNX(ctx, prefixIdempotencyLock+key, token, l.conf.LockExp).Result()
+	if err != nil {
+		return "", false, fmt.Errorf("redis idempotency key locking failed: %s", err)
+	}
 	if !ok {
-		// Unknown key
-		l.mu.Unlock()
-		return
+		return "", false, nil
 	}
-	l.mu.Unlock()
-
-	keyed.mu.Unlock()
+	return token, true, nil
+}
 
-	l.mu.Lock()
-	keyed.locked--
-	if keyed.locked <= 0 {
-		delete(l.keyMu, key)
+func (l *redisLocker) Unlock(ctx context.Context, key string, token string) error {
+	if err := unlockScript.Run(ctx, l.client, []string{prefixIdempotencyLock + key}, token).Err(); err != nil {
+		return fmt.Errorf("redis idempotency key unlocking failed: %s", err)
 	}
-	l.mu.Unlock()
+	return nil
 }
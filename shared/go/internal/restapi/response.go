@@ -3,12 +3,15 @@ package restapi
 // Specified by contract.md in /api folder
 
 const (
-	ErrTypeInternal              = "internal"
-	ErrTypeInvalidJson           = "invalid_json"
-	ErrTypeValidationFailed      = "validation_failed"
-	ErrTypeNotFound              = "not_found"
-	ErrTypeIdempotencyKeyMissing = "idempotency_key_missing"
-	ErrTypeUnautorized           = "unauthorized"
+	ErrTypeInternal                 = "internal"
+	ErrTypeInvalidJson              = "invalid_json"
+	ErrTypeValidationFailed         = "validation_failed"
+	ErrTypeNotFound                 = "not_found"
+	ErrTypeIdempotencyKeyMissing    = "idempotency_key_missing"
+	ErrTypeIdempotencyKeyInProgress = "idempotency_key_in_progress"
+	ErrTypeIdempotencyKeyReused     = "idempotency_key_reused"
+	ErrTypeUnautorized              = "unauthorized"
+	ErrTypeRateLimitExceeded        = "rate_limit_exceeded"
 )
 
 type ErrorDetail struct {
@@ -0,0 +1,134 @@
+package ratelimit
+
+import (
+	"context"
+	"log"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/chakchat/chakchat-backend/shared/go/auth"
+	"github.com/chakchat/chakchat-backend/shared/go/internal/restapi"
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	HeaderRetryAfter         = "Retry-After"
+	HeaderRateLimitLimit     = "X-RateLimit-Limit"
+	HeaderRateLimitRemaining = "X-RateLimit-Remaining"
+)
+
+// Limit allows at most Requests requests during any Window.
+// Zero Requests disables limiting.
+type Limit struct {
+	Requests int
+	Window   time.Duration
+}
+
+type Result struct {
+	Allowed   bool
+	Remaining int
+	// Zero if request is allowed
+	RetryAfter time.Duration
+}
+
+type Limiter interface {
+	// Allow registers the request if it fits the limit
+	Allow(ctx context.Context, key string, limit Limit) (*Result, error)
+}
+
+// KeyFunc extracts a key requests are counted by.
+// If ok is false the request is not limited.
+type KeyFunc func(c *gin.Context) (key string, ok bool)
+
+type Config struct {
+	// Name separates counters of different limits.
+	// Routes that use the same Name share the same counters.
+	Name  string
+	Limit Limit
+	Key   KeyFunc
+}
+
+func New(limiter Limiter, conf *Config) gin.HandlerFunc {
+	if conf.Limit.Requests <= 0 {
+		return func(c *gin.Context) {
+			c.Next()
+		}
+	}
+
+	return func(c *gin.Context) {
+		key, ok := conf.Key(c)
+		if !ok {
+			c.Next()
+			return
+		}
+
+		res, err := limiter.Allow(c.Request.Context(), conf.Name+":"+key, conf.Limit)
+		if err != nil {
+			// Limiter outage shouldn't make the whole endpoint unavailable
+			log.Printf("rate limit middleware: limiter failed: %s", err)
+			c.Next()
+			return
+		}
+
+		c.Header(HeaderRateLimitLimit, strconv.Itoa(conf.Limit.Requests))
+		c.Header(HeaderRateLimitRemaining, strconv.Itoa(res.Remaining))
+
+		if !res.Allowed {
+			retryAfter := int(math.Ceil(res.RetryAfter.Seconds()))
+			c.Header(HeaderRetryAfter, strconv.Itoa(retryAfter))
+			c.JSON(http.StatusTooManyRequests, restapi.ErrorResponse{
+				ErrorType:    restapi.ErrTypeRateLimitExceeded,
+				ErrorMessage: "Too many requests. Retry after " + strconv.Itoa(retryAfter) + " seconds",
+			})
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// ByUser limits authenticated users by "sub" claim.
+// It must be used after auth middleware.
+func ByUser() KeyFunc {
+	return func(c *gin.Context) (string, bool) {
+		claims := auth.GetClaims(c.Request.Context())
+		if claims == nil {
+			return "", false
+		}
+		sub, ok := claims[auth.ClaimId].(string)
+		if !ok || sub == "" {
+			return "", false
+		}
+		return "user:" + sub, true
+	}
+}
+
+func ByIP() KeyFunc {
+	return func(c *gin.Context) (string, bool) {
+		ip := c.ClientIP()
+		if ip == "" {
+			return "", false
+		}
+		return "ip:" + ip, true
+	}
+}
+
+// ByJSONField limits by string field of JSON body (e.g. "phone").
+// Body is cached so handlers are still able to read it with c.ShouldBindBodyWithJSON()
+func ByJSONField(field string) KeyFunc {
+	return func(c *gin.Context) (string, bool) {
+		var body map[string]any
+		if err := c.ShouldBindBodyWithJSON(&body); err != nil {
+			// Handler will respond with the proper error
+			return "", false
+		}
+		val, ok := body[field].(string)
+		if !ok || val == "" {
+			return "", false
+		}
+		return field + ":" + val, true
+	}
+}
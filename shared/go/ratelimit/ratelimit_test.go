@@ -0,0 +1,89 @@
+package ratelimit
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_RateLimitMiddleware(t *testing.T) {
+	limit := Limit{Requests: 2, Window: time.Minute}
+
+	t.Run("ExceedsLimit", func(t *testing.T) {
+		r := gin.New()
+		r.Use(New(newFakeLimiter(), &Config{Name: "test", Limit: limit, Key: ByIP()}))
+		r.POST("/", func(c *gin.Context) {
+			c.Status(http.StatusOK)
+		})
+
+		require.Equal(t, http.StatusOK, execute(r, `{}`).Code)
+		require.Equal(t, http.StatusOK, execute(r, `{}`).Code)
+
+		resp := execute(r, `{}`)
+		require.Equal(t, http.StatusTooManyRequests, resp.Code)
+		require.Equal(t, "60", resp.Header().Get(HeaderRetryAfter))
+		require.Equal(t, "0", resp.Header().Get(HeaderRateLimitRemaining))
+	})
+
+	t.Run("SeparateKeys", func(t *testing.T) {
+		r := gin.New()
+		r.Use(New(newFakeLimiter(), &Config{Name: "test", Limit: limit, Key: ByJSONField("phone")}))
+		r.POST("/", func(c *gin.Context) {
+			var req struct {
+				Phone string `json:"phone"`
+			}
+			// Body must be still readable by the handler
+			require.NoError(t, c.ShouldBindBodyWithJSON(&req))
+			require.NotEmpty(t, req.Phone)
+			c.Status(http.StatusOK)
+		})
+
+		for range 2 {
+			require.Equal(t, http.StatusOK, execute(r, `{"phone": "79999999999"}`).Code)
+		}
+		require.Equal(t, http.StatusTooManyRequests, execute(r, `{"phone": "79999999999"}`).Code)
+		require.Equal(t, http.StatusOK, execute(r, `{"phone": "79111111111"}`).Code)
+	})
+
+	t.Run("NoKey", func(t *testing.T) {
+		r := gin.New()
+		r.Use(New(newFakeLimiter(), &Config{Name: "test", Limit: limit, Key: ByUser()}))
+		r.POST("/", func(c *gin.Context) {
+			c.Status(http.StatusOK)
+		})
+
+		// Unauthenticated requests are not counted by ByUser()
+		for range 5 {
+			require.Equal(t, http.StatusOK, execute(r, `{}`).Code)
+		}
+	})
+}
+
+func execute(r *gin.Engine, body string) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(body))
+	r.ServeHTTP(w, req)
+	return w
+}
+
+type fakeLimiter struct {
+	counts map[string]int
+}
+
+func newFakeLimiter() *fakeLimiter {
+	return &fakeLimiter{counts: map[string]int{}}
+}
+
+func (l *fakeLimiter) Allow(_ context.Context, key string, limit Limit) (*Result, error) {
+	if l.counts[key] >= limit.Requests {
+		return &Result{Allowed: false, RetryAfter: limit.Window}, nil
+	}
+	l.counts[key]++
+	return &Result{Allowed: true, Remaining: limit.Requests - l.counts[key]}, nil
+}
@@ -0,0 +1,70 @@
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+)
+
+const prefixRateLimit = "RateLimit:"
+
+// Sliding window log. Every allowed request is stored in a sorted set with its time as a score.
+// Script is executed atomically so concurrent requests of different replicas can't exceed the limit.
+var slidingWindowScript = redis.NewScript(`
+local key = KEYS[1]
+local now = tonumber(ARGV[1])
+local window = tonumber(ARGV[2])
+local limit = tonumber(ARGV[3])
+local member = ARGV[4]
+
+redis.call('ZREMRANGEBYSCORE', key, '-inf', now - window)
+
+local count = redis.call('ZCARD', key)
+if count < limit then
+	redis.call('ZADD', key, now, member)
+	redis.call('PEXPIRE', key, window)
+	return {1, limit - count - 1, 0}
+end
+
+local oldest = redis.call('ZRANGE', key, 0, 0, 'WITHSCORES')
+local retryAfter = tonumber(oldest[2]) + window - now
+return {0, 0, retryAfter}
+`)
+
+type redisLimiter struct {
+	client *redis.Client
+}
+
+func NewRedisLimiter(client *redis.Client) Limiter {
+	return &redisLimiter{
+		client: client,
+	}
+}
+
+func (l *redisLimiter) Allow(ctx context.Context, key string, limit Limit) (*Result, error) {
+	now := nowFunc().UnixMilli()
+
+	res, err := slidingWindowScript.Run(ctx, l.client,
+		[]string{prefixRateLimit + key},
+		now, limit.Window.Milliseconds(), limit.Requests, uuid.NewString(),
+	).Int64Slice()
+	if err != nil {
+		return nil, fmt.Errorf("redis rate limit script failed: %s", err)
+	}
+	if len(res) != 3 {
+		return nil, fmt.Errorf("unexpected rate limit script result: %v", res)
+	}
+
+	return &Result{
+		Allowed:    res[0] == 1,
+		Remaining:  int(res[1]),
+		RetryAfter: time.Duration(res[2]) * time.Millisecond,
+	}, nil
+}
+
+var nowFunc = func() time.Time {
+	return time.Now()
+}
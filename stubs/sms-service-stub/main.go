@@ -3,6 +3,7 @@ package main
 import (
 	"net/http"
 	"sync"
+	"sync/atomic"
 
 	"github.com/gin-gonic/gin"
 )
@@ -11,10 +12,17 @@ func main() {
 	r := gin.New()
 
 	m := sync.Map{}
+	// Simulates provider outage so identity-service failover can be tested
+	var failing atomic.Bool
 
 	r.POST("/", func(c *gin.Context) {
+		if failing.Load() {
+			c.String(http.StatusServiceUnavailable, "provider is unavailable")
+			return
+		}
 		type Req struct {
 			Phone   string `json:"phone"`
+			Email   string `json:"email"`
 			Message string `json:"message"`
 		}
 		req := new(Req)
@@ -22,7 +30,25 @@ func main() {
 			c.String(http.StatusBadRequest, "it is not valid json")
 			return
 		}
-		m.Store(req.Phone, req.Message)
+		// Emails are stored the same way so GET /:phone also works for them
+		if req.Email != "" {
+			m.Store(req.Email, req.Message)
+		} else {
+			m.Store(req.Phone, req.Message)
+		}
+		c.Status(http.StatusOK)
+	})
+
+	r.PUT("/failure", func(c *gin.Context) {
+		type Req struct {
+			Fail bool `json:"fail"`
+		}
+		req := new(Req)
+		if err := c.ShouldBindBodyWithJSON(req); err != nil {
+			c.String(http.StatusBadRequest, "it is not valid json")
+			return
+		}
+		failing.Store(req.Fail)
 		c.Status(http.StatusOK)
 	})
 
@@ -34,6 +34,19 @@ func NewServerStub() *ServerStub {
 }
 
 func (ServerStub) GetUser(ctx context.Context, req *userservice.UserRequest) (*userservice.UserResponse, error) {
+	// Every user id has an existing owner
+	if req.UserId != nil {
+		id := req.UserId.GetValue()
+		username := "user_with_id_" + id
+		name := "User with id " + id
+		return &userservice.UserResponse{
+			Status:   userservice.UserResponseStatus_SUCCESS,
+			Name:     &name,
+			UserName: &username,
+			UserId:   &userservice.UUID{Value: id},
+		}, nil
+	}
+
 	phone := req.GetPhoneNumber()
 	// 79********1 phone numbers have existing owners
 	if phone[len(phone)-1] == '1' {
@@ -79,6 +92,45 @@ func (ServerStub) CreateUser(ctx context.Context, req *userservice.CreateUserReq
 	}, nil
 }
 
+func (ServerStub) UpdatePhone(ctx context.Context, req *userservice.UpdatePhoneRequest) (*userservice.UpdatePhoneResponse, error) {
+	phone := req.GetPhoneNumber()
+	// The same phone rules as in GetUser
+	if phone[len(phone)-1] == '1' {
+		return &userservice.UpdatePhoneResponse{
+			Status: userservice.UpdatePhoneStatus_PHONE_TAKEN,
+		}, nil
+	}
+	if phone[len(phone)-1] == '2' {
+		return &userservice.UpdatePhoneResponse{
+			Status: userservice.UpdatePhoneStatus_UPDATE_FAILED,
+		}, nil
+	}
+
+	return &userservice.UpdatePhoneResponse{
+		Status: userservice.UpdatePhoneStatus_UPDATED,
+	}, nil
+}
+
+// Users of the stub are never suspended
+func (ServerStub) GetSuspension(ctx context.Context, req *userservice.GetSuspensionRequest) (*userservice.GetSuspensionResponse, error) {
+	return &userservice.GetSuspensionResponse{
+		Status:    userservice.UserResponseStatus_SUCCESS,
+		Suspended: false,
+	}, nil
+}
+
+func (ServerStub) SuspendUser(ctx context.Context, req *userservice.SuspendUserRequest) (*userservice.SuspendUserResponse, error) {
+	return &userservice.SuspendUserResponse{
+		Status: userservice.UserResponseStatus_SUCCESS,
+	}, nil
+}
+
+func (ServerStub) UnsuspendUser(ctx context.Context, req *userservice.UnsuspendUserRequest) (*userservice.UnsuspendUserResponse, error) {
+	return &userservice.UnsuspendUserResponse{
+		Status: userservice.UserResponseStatus_SUCCESS,
+	}, nil
+}
+
 var _ userservice.UserServiceServer = ServerStub{}
 
 type User struct {
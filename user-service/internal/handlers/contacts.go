@@ -0,0 +1,133 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/chakchat/chakchat-backend/shared/go/auth"
+	"github.com/chakchat/chakchat-backend/user-service/internal/restapi"
+	"github.com/chakchat/chakchat-backend/user-service/internal/services"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type ContactsServer interface {
+	GetContacts(ctx context.Context, id uuid.UUID) ([]uuid.UUID, error)
+	AddContact(ctx context.Context, id, contactID uuid.UUID) error
+	RemoveContact(ctx context.Context, id, contactID uuid.UUID) error
+}
+
+type Contacts struct {
+	Contacts []uuid.UUID `json:"contacts"`
+}
+
+func GetContacts(service ContactsServer) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		meId, ok := getMeID(c)
+		if !ok {
+			restapi.SendUnauthorizedError(c, nil)
+			return
+		}
+
+		contacts, err := service.GetContacts(c.Request.Context(), meId)
+		if err != nil {
+			c.Error(err)
+			restapi.SendInternalError(c)
+			return
+		}
+
+		if contacts == nil {
+			contacts = []uuid.UUID{}
+		}
+		restapi.SendSuccess(c, Contacts{Contacts: contacts})
+	}
+}
+
+func AddContact(service ContactsServer) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		meId, ok := getMeID(c)
+		if !ok {
+			restapi.SendUnauthorizedError(c, nil)
+			return
+		}
+
+		contactId, ok := getContactID(c)
+		if !ok {
+			return
+		}
+
+		err := service.AddContact(c.Request.Context(), meId, contactId)
+		if err != nil {
+			switch {
+			case errors.Is(err, services.ErrNotFound):
+				c.JSON(http.StatusNotFound, restapi.ErrorResponse{
+					ErrorType:    restapi.ErrTypeNotFound,
+					ErrorMessage: "Not found user with the id",
+				})
+			case errors.Is(err, services.ErrValidationError):
+				c.JSON(http.StatusBadRequest, restapi.ErrorResponse{
+					ErrorType:    restapi.ErrTypeBadRequest,
+					ErrorMessage: "User can't add themselves to contacts",
+				})
+			default:
+				c.Error(err)
+				restapi.SendInternalError(c)
+			}
+			return
+		}
+
+		restapi.SendSuccess(c, struct{}{})
+	}
+}
+
+func RemoveContact(service ContactsServer) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		meId, ok := getMeID(c)
+		if !ok {
+			restapi.SendUnauthorizedError(c, nil)
+			return
+		}
+
+		contactId, ok := getContactID(c)
+		if !ok {
+			return
+		}
+
+		if err := service.RemoveContact(c.Request.Context(), meId, contactId); err != nil {
+			c.Error(err)
+			restapi.SendInternalError(c)
+			return
+		}
+
+		restapi.SendSuccess(c, struct{}{})
+	}
+}
+
+func getMeID(c *gin.Context) (uuid.UUID, bool) {
+	claimId, ok := auth.GetClaims(c.Request.Context())[auth.ClaimId]
+	if !ok {
+		return uuid.Nil, false
+	}
+
+	meId, err := uuid.Parse(claimId.(string))
+	if err != nil {
+		return uuid.Nil, false
+	}
+	return meId, true
+}
+
+// getContactID sends validation error if the parameter is invalid
+func getContactID(c *gin.Context) (uuid.UUID, bool) {
+	contactId, err := uuid.Parse(c.Param("userId"))
+	if err != nil {
+		restapi.SendValidationError(c, []restapi.ErrorDetail{
+			{
+				Field:   "UserId",
+				Message: "Invalid UserId parameter",
+			},
+		})
+		return uuid.Nil, false
+	}
+	return contactId, true
+}
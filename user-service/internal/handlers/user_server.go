@@ -5,6 +5,7 @@ import (
 	"errors"
 	"log"
 	"regexp"
+	"time"
 
 	pb "github.com/chakchat/chakchat-backend/user-service/internal/grpcservice"
 	"github.com/chakchat/chakchat-backend/user-service/internal/models"
@@ -22,8 +23,19 @@ func NewUserServer(userService services.UserService) *UserServer {
 }
 
 func (s *UserServer) GetUser(ctx context.Context, req *pb.UserRequest) (*pb.UserResponse, error) {
-
-	user, err := s.userService.GetUser(ctx, req.PhoneNumber)
+	var user *models.User
+	var err error
+	if req.UserId != nil {
+		id, parseErr := uuid.Parse(req.UserId.Value)
+		if parseErr != nil {
+			return &pb.UserResponse{
+				Status: pb.UserResponseStatus_FAILED,
+			}, nil
+		}
+		user, err = s.userService.GetUserByID(ctx, id)
+	} else {
+		user, err = s.userService.GetUser(ctx, req.PhoneNumber)
+	}
 	if err != nil {
 		if errors.Is(err, services.ErrNotFound) {
 			log.Printf("Failed find user and returns not found, %s", err)
@@ -104,3 +116,160 @@ func (s *UserServer) CreateUser(ctx context.Context, req *pb.CreateUserRequest)
 		UserName: &user.Username,
 	}, nil
 }
+
+func (s *UserServer) UpdatePhone(ctx context.Context, req *pb.UpdatePhoneRequest) (*pb.UpdatePhoneResponse, error) {
+	matchedPhone, _ := regexp.MatchString(`^[79]9\d{9}$`, req.PhoneNumber)
+	if !matchedPhone {
+		return &pb.UpdatePhoneResponse{
+			Status: pb.UpdatePhoneStatus_PHONE_VALIDATION_FAILED,
+		}, nil
+	}
+	id, err := uuid.Parse(req.GetUserId().GetValue())
+	if err != nil {
+		return &pb.UpdatePhoneResponse{
+			Status: pb.UpdatePhoneStatus_UPDATE_FAILED,
+		}, nil
+	}
+
+	notify, err := s.userService.UpdatePhone(ctx, id, req.PhoneNumber)
+	if err != nil {
+		if errors.Is(err, services.ErrNotFound) {
+			return &pb.UpdatePhoneResponse{
+				Status: pb.UpdatePhoneStatus_USER_NOT_FOUND,
+			}, nil
+		}
+		if errors.Is(err, services.ErrAlreadyExists) {
+			return &pb.UpdatePhoneResponse{
+				Status: pb.UpdatePhoneStatus_PHONE_TAKEN,
+			}, nil
+		}
+		log.Printf("Updating phone failed: %s", err)
+		return &pb.UpdatePhoneResponse{
+			Status: pb.UpdatePhoneStatus_UPDATE_FAILED,
+		}, nil
+	}
+
+	notifyIds := make([]*pb.UUID, 0, len(notify))
+	for _, userId := range notify {
+		notifyIds = append(notifyIds, &pb.UUID{Value: userId.String()})
+	}
+	return &pb.UpdatePhoneResponse{
+		Status:        pb.UpdatePhoneStatus_UPDATED,
+		NotifyUserIds: notifyIds,
+	}, nil
+}
+
+func (s *UserServer) GetSuspension(ctx context.Context, req *pb.GetSuspensionRequest) (*pb.GetSuspensionResponse, error) {
+	id, err := uuid.Parse(req.GetUserId().GetValue())
+	if err != nil {
+		return &pb.GetSuspensionResponse{
+			Status: pb.UserResponseStatus_FAILED,
+		}, nil
+	}
+
+	suspension, err := s.userService.GetSuspension(ctx, id)
+	if err != nil {
+		if errors.Is(err, services.ErrNotFound) {
+			return &pb.GetSuspensionResponse{
+				Status: pb.UserResponseStatus_NOT_FOUND,
+			}, nil
+		}
+		log.Printf("Getting suspension failed: %s", err)
+		return &pb.GetSuspensionResponse{
+			Status: pb.UserResponseStatus_FAILED,
+		}, nil
+	}
+
+	if suspension == nil {
+		return &pb.GetSuspensionResponse{
+			Status:    pb.UserResponseStatus_SUCCESS,
+			Suspended: false,
+		}, nil
+	}
+	resp := &pb.GetSuspensionResponse{
+		Status:    pb.UserResponseStatus_SUCCESS,
+		Suspended: true,
+		Reason:    &suspension.Reason,
+	}
+	if suspension.Until != nil {
+		until := suspension.Until.Unix()
+		resp.SuspendedUntil = &until
+	}
+	return resp, nil
+}
+
+func (s *UserServer) SuspendUser(ctx context.Context, req *pb.SuspendUserRequest) (*pb.SuspendUserResponse, error) {
+	id, err := uuid.Parse(req.GetUserId().GetValue())
+	if err != nil {
+		return &pb.SuspendUserResponse{
+			Status: pb.UserResponseStatus_FAILED,
+		}, nil
+	}
+
+	var until *time.Time
+	if req.SuspendedUntil != nil {
+		t := time.Unix(req.GetSuspendedUntil(), 0).UTC()
+		until = &t
+	}
+
+	if err := s.userService.Suspend(ctx, id, req.Reason, until); err != nil {
+		if errors.Is(err, services.ErrNotFound) {
+			return &pb.SuspendUserResponse{
+				Status: pb.UserResponseStatus_NOT_FOUND,
+			}, nil
+		}
+		log.Printf("Suspending user failed: %s", err)
+		return &pb.SuspendUserResponse{
+			Status: pb.UserResponseStatus_FAILED,
+		}, nil
+	}
+	return &pb.SuspendUserResponse{
+		Status: pb.UserResponseStatus_SUCCESS,
+	}, nil
+}
+
+func (s *UserServer) UnsuspendUser(ctx context.Context, req *pb.UnsuspendUserRequest) (*pb.UnsuspendUserResponse, error) {
+	id, err := uuid.Parse(req.GetUserId().GetValue())
+	if err != nil {
+		return &pb.UnsuspendUserResponse{
+			Status: pb.UserResponseStatus_FAILED,
+		}, nil
+	}
+
+	if err := s.userService.Unsuspend(ctx, id); err != nil {
+		if errors.Is(err, services.ErrNotFound) {
+			return &pb.UnsuspendUserResponse{
+				Status: pb.UserResponseStatus_NOT_FOUND,
+			}, nil
+		}
+		log.Printf("Unsuspending user failed: %s", err)
+		return &pb.UnsuspendUserResponse{
+			Status: pb.UserResponseStatus_FAILED,
+		}, nil
+	}
+	return &pb.UnsuspendUserResponse{
+		Status: pb.UserResponseStatus_SUCCESS,
+	}, nil
+}
+
+func (s *UserServer) GetUserIDsByUsernames(ctx context.Context, req *pb.GetUserIDsByUsernamesRequest) (*pb.GetUserIDsByUsernamesResponse, error) {
+	ids, err := s.userService.GetUserIDsByUsernames(ctx, req.GetUsernames())
+	if err != nil {
+		log.Printf("Getting user ids by usernames failed: %s", err)
+		return &pb.GetUserIDsByUsernamesResponse{
+			Status: pb.UserResponseStatus_FAILED,
+		}, nil
+	}
+
+	users := make([]*pb.UserIDByUsername, 0, len(ids))
+	for username, id := range ids {
+		users = append(users, &pb.UserIDByUsername{
+			Username: username,
+			UserId:   &pb.UUID{Value: id.String()},
+		})
+	}
+	return &pb.GetUserIDsByUsernamesResponse{
+		Status: pb.UserResponseStatus_SUCCESS,
+		Users:  users,
+	}, nil
+}
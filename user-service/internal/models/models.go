@@ -14,6 +14,13 @@ const (
 	RestrictionNone      = "only_me"
 )
 
+type UserStatus string
+
+const (
+	UserStatusActive    = "active"
+	UserStatusSuspended = "suspended"
+)
+
 type User struct {
 	ID          uuid.UUID
 	Name        string
@@ -27,6 +34,21 @@ type User struct {
 	PhoneVisibility       Restriction `gorm:"default:everyone"`
 }
 
+type Suspension struct {
+	Status UserStatus
+	Reason string
+	// Nil if the suspension doesn't expire
+	Until *time.Time
+}
+
+// Active reports whether the user is suspended at the given moment.
+func (s *Suspension) Active(now time.Time) bool {
+	if s.Status != UserStatusSuspended {
+		return false
+	}
+	return s.Until == nil || s.Until.After(now)
+}
+
 type FieldRestriction struct {
 	OwnerID        uuid.UUID `gorm:"primaryKey"`
 	FieldName      string
@@ -0,0 +1,47 @@
+package services
+
+import (
+	"context"
+	"errors"
+
+	"github.com/chakchat/chakchat-backend/user-service/internal/storage"
+	"github.com/google/uuid"
+)
+
+type ContactRepository interface {
+	GetContactIDs(ctx context.Context, id uuid.UUID) ([]uuid.UUID, error)
+	// Returns NotFound error if the contact user doesn't exist.
+	AddContact(ctx context.Context, id, contactID uuid.UUID) error
+	RemoveContact(ctx context.Context, id, contactID uuid.UUID) error
+}
+
+type ContactService struct {
+	contactRepo ContactRepository
+}
+
+func NewContactService(contactRepo ContactRepository) *ContactService {
+	return &ContactService{
+		contactRepo: contactRepo,
+	}
+}
+
+func (s *ContactService) GetContacts(ctx context.Context, id uuid.UUID) ([]uuid.UUID, error) {
+	return s.contactRepo.GetContactIDs(ctx, id)
+}
+
+func (s *ContactService) AddContact(ctx context.Context, id, contactID uuid.UUID) error {
+	if id == contactID {
+		return ErrValidationError
+	}
+	if err := s.contactRepo.AddContact(ctx, id, contactID); err != nil {
+		if errors.Is(err, storage.ErrNotFound) {
+			return ErrNotFound
+		}
+		return err
+	}
+	return nil
+}
+
+func (s *ContactService) RemoveContact(ctx context.Context, id, contactID uuid.UUID) error {
+	return s.contactRepo.RemoveContact(ctx, id, contactID)
+}
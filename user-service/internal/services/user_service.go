@@ -3,6 +3,7 @@ package services
 import (
 	"context"
 	"errors"
+	"time"
 
 	"github.com/chakchat/chakchat-backend/user-service/internal/models"
 	"github.com/chakchat/chakchat-backend/user-service/internal/storage"
@@ -17,15 +18,32 @@ type UserRepository interface {
 	GetUserByPhone(ctx context.Context, phone string) (*models.User, error)
 	CreateUser(ctx context.Context, user *models.User) (*models.User, error)
 	GetUserById(ctx context.Context, id uuid.UUID) (*models.User, error)
+	GetUserIDsByUsernames(ctx context.Context, usernames []string) (map[string]uuid.UUID, error)
+	// Returns AlreadyExists error if phone belongs to another user.
+	UpdatePhone(ctx context.Context, id uuid.UUID, phone string) error
+	GetSuspension(ctx context.Context, id uuid.UUID) (*models.Suspension, error)
+	// until is nil for the suspension that doesn't expire.
+	Suspend(ctx context.Context, id uuid.UUID, reason string, until *time.Time) error
+	Unsuspend(ctx context.Context, id uuid.UUID) error
+}
+
+type ContactOwnerRepository interface {
+	// Returns users who have the user in their contacts
+	GetContactOwnerIDs(ctx context.Context, id uuid.UUID) ([]uuid.UUID, error)
 }
 
 type UserService struct {
-	userRepo UserRepository
+	userRepo        UserRepository
+	restrictionRepo GetRestrictionRepository
+	contactRepo     ContactOwnerRepository
 }
 
-func NewGetUserService(userHandler UserRepository) *UserService {
+func NewGetUserService(userHandler UserRepository, restrictions GetRestrictionRepository,
+	contacts ContactOwnerRepository) *UserService {
 	return &UserService{
-		userRepo: userHandler,
+		userRepo:        userHandler,
+		restrictionRepo: restrictions,
+		contactRepo:     contacts,
 	}
 }
 
@@ -41,6 +59,23 @@ func (s *UserService) GetUser(ctx context.Context, phone string) (*models.User,
 	return user, nil
 }
 
+func (s *UserService) GetUserByID(ctx context.Context, id uuid.UUID) (*models.User, error) {
+	user, err := s.userRepo.GetUserById(ctx, id)
+	if err != nil {
+		if errors.Is(err, storage.ErrNotFound) {
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+
+	return user, nil
+}
+
+// GetUserIDsByUsernames omits unknown usernames
+func (s *UserService) GetUserIDsByUsernames(ctx context.Context, usernames []string) (map[string]uuid.UUID, error) {
+	return s.userRepo.GetUserIDsByUsernames(ctx, usernames)
+}
+
 func (s *UserService) CreateUser(ctx context.Context, user *models.User) (*models.User, error) {
 	newUser, err := s.userRepo.CreateUser(ctx, user)
 	if errors.Is(err, storage.ErrAlreadyExists) {
@@ -59,3 +94,66 @@ func (s *UserService) GetName(ctx context.Context, id uuid.UUID) (*string, error
 	}
 	return &user.Name, nil
 }
+
+// UpdatePhone changes user's phone and returns users that should be notified about it.
+// They are users who have the user in contacts if the phone is visible to everyone
+// and users from the phone restriction list if it is visible to specified users.
+// Nobody is notified if the phone is visible only to its owner.
+func (s *UserService) UpdatePhone(ctx context.Context, id uuid.UUID, phone string) ([]uuid.UUID, error) {
+	if err := s.userRepo.UpdatePhone(ctx, id, phone); err != nil {
+		if errors.Is(err, storage.ErrNotFound) {
+			return nil, ErrNotFound
+		}
+		if errors.Is(err, storage.ErrAlreadyExists) {
+			return nil, ErrAlreadyExists
+		}
+		return nil, err
+	}
+
+	user, err := s.userRepo.GetUserById(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	switch user.PhoneVisibility {
+	case models.RestrictionAll:
+		return s.contactRepo.GetContactOwnerIDs(ctx, id)
+	case models.RestrictionSpecified:
+		return s.restrictionRepo.GetAllowedUserIDs(ctx, id, "phone")
+	}
+	return nil, nil
+}
+
+// GetSuspension returns nil if the user is not suspended or the suspension has expired.
+func (s *UserService) GetSuspension(ctx context.Context, id uuid.UUID) (*models.Suspension, error) {
+	suspension, err := s.userRepo.GetSuspension(ctx, id)
+	if err != nil {
+		if errors.Is(err, storage.ErrNotFound) {
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+	if !suspension.Active(time.Now()) {
+		return nil, nil
+	}
+	return suspension, nil
+}
+
+func (s *UserService) Suspend(ctx context.Context, id uuid.UUID, reason string, until *time.Time) error {
+	if err := s.userRepo.Suspend(ctx, id, reason, until); err != nil {
+		if errors.Is(err, storage.ErrNotFound) {
+			return ErrNotFound
+		}
+		return err
+	}
+	return nil
+}
+
+func (s *UserService) Unsuspend(ctx context.Context, id uuid.UUID) error {
+	if err := s.userRepo.Unsuspend(ctx, id); err != nil {
+		if errors.Is(err, storage.ErrNotFound) {
+			return ErrNotFound
+		}
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,93 @@
+package storage
+
+import (
+	"context"
+	"errors"
+
+	"github.com/chakchat/chakchat-backend/shared/go/postgres"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+type ContactStorage struct {
+	db postgres.SQLer
+}
+
+func NewContactStorage(db postgres.SQLer) *ContactStorage {
+	return &ContactStorage{
+		db: db,
+	}
+}
+
+func (s *ContactStorage) GetContactIDs(ctx context.Context, id uuid.UUID) ([]uuid.UUID, error) {
+	q := `SELECT contact_user_id FROM users.contact WHERE owner_user_id = $1`
+
+	rows, err := s.db.Query(ctx, q, id)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var contacts []uuid.UUID
+	for rows.Next() {
+		var contactID uuid.UUID
+		if err := rows.Scan(&contactID); err != nil {
+			return nil, err
+		}
+		contacts = append(contacts, contactID)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return contacts, nil
+}
+
+// GetContactOwnerIDs returns users who have the user in their contacts.
+func (s *ContactStorage) GetContactOwnerIDs(ctx context.Context, id uuid.UUID) ([]uuid.UUID, error) {
+	q := `SELECT owner_user_id FROM users.contact WHERE contact_user_id = $1`
+
+	rows, err := s.db.Query(ctx, q, id)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var owners []uuid.UUID
+	for rows.Next() {
+		var ownerID uuid.UUID
+		if err := rows.Scan(&ownerID); err != nil {
+			return nil, err
+		}
+		owners = append(owners, ownerID)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return owners, nil
+}
+
+// AddContact returns NotFound error if the contact user doesn't exist.
+// Adding the same contact again is not an error.
+func (s *ContactStorage) AddContact(ctx context.Context, id, contactID uuid.UUID) error {
+	q := `INSERT INTO users.contact (owner_user_id, contact_user_id) VALUES ($1, $2)
+	ON CONFLICT DO NOTHING`
+
+	_, err := s.db.Exec(ctx, q, id, contactID)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23503" {
+			return ErrNotFound
+		}
+		return err
+	}
+	return nil
+}
+
+func (s *ContactStorage) RemoveContact(ctx context.Context, id, contactID uuid.UUID) error {
+	q := `DELETE FROM users.contact WHERE owner_user_id = $1 AND contact_user_id = $2`
+
+	_, err := s.db.Exec(ctx, q, id, contactID)
+	return err
+}
@@ -11,6 +11,7 @@ import (
 	"github.com/chakchat/chakchat-backend/user-service/internal/models"
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
 )
 
 var ErrNotFound = errors.New("not found")
@@ -126,6 +127,30 @@ func (s *UserStorage) GetUserByUsername(ctx context.Context, username string) (*
 	return &user, nil
 }
 
+// GetUserIDsByUsernames returns ids of existing users by their usernames
+func (s *UserStorage) GetUserIDsByUsernames(ctx context.Context, usernames []string) (map[string]uuid.UUID, error) {
+	q := `SELECT username, id FROM users.user WHERE username = ANY($1)`
+
+	rows, err := s.db.Query(ctx, q, usernames)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	ids := make(map[string]uuid.UUID, len(usernames))
+	for rows.Next() {
+		var (
+			username string
+			id       uuid.UUID
+		)
+		if err := rows.Scan(&username, &id); err != nil {
+			return nil, err
+		}
+		ids[username] = id
+	}
+	return ids, rows.Err()
+}
+
 func (s *UserStorage) GetUsersByCriteria(ctx context.Context, req SearchUsersRequest) (*SearchUsersResponse, error) {
 	var users []models.User
 	q := `SELECT
@@ -301,6 +326,47 @@ func (s *UserStorage) UpdatePhoto(ctx context.Context, id uuid.UUID, photoURL st
 	return user, nil
 }
 
+func (s *UserStorage) UpdatePhone(ctx context.Context, id uuid.UUID, phone string) (err error) {
+	tx, err := s.db.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		return err
+	}
+	defer finishTx(ctx, tx, &err)
+
+	// Row lock prevents concurrent changes of the same user's phone
+	q := `SELECT id FROM users.user WHERE id = $1 FOR UPDATE`
+	var existingId uuid.UUID
+	if err = tx.QueryRow(ctx, q, id).Scan(&existingId); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			err = ErrNotFound
+		}
+		return err
+	}
+
+	q = `SELECT id FROM users.user WHERE phone = $1`
+	var ownerId uuid.UUID
+	err = tx.QueryRow(ctx, q, phone).Scan(&ownerId)
+	if err == nil {
+		err = ErrAlreadyExists
+		return err
+	}
+	if !errors.Is(err, pgx.ErrNoRows) {
+		return err
+	}
+
+	updateQuery := `UPDATE users.user SET phone = $1 WHERE id = $2`
+	if _, err = tx.Exec(ctx, updateQuery, phone, id); err != nil {
+		// The same phone may be taken by a concurrent transaction
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+			err = ErrAlreadyExists
+		}
+		return err
+	}
+
+	return nil
+}
+
 func (s *UserStorage) DeletePhoto(ctx context.Context, id uuid.UUID) (*models.User, error) {
 	updateQuery := `UPDATE users.user SET photo_url = '' WHERE id = $1`
 	_, err := s.db.Exec(ctx, updateQuery, id)
@@ -331,3 +397,46 @@ func (s *UserStorage) DeleteUser(ctx context.Context, id uuid.UUID) error {
 
 	return nil
 }
+
+func (s *UserStorage) GetSuspension(ctx context.Context, id uuid.UUID) (*models.Suspension, error) {
+	q := `SELECT status, suspension_reason, suspended_until FROM users.user WHERE id = $1`
+
+	var (
+		suspension models.Suspension
+		reason     *string
+	)
+	if err := s.db.QueryRow(ctx, q, id).Scan(&suspension.Status, &reason, &suspension.Until); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+	if reason != nil {
+		suspension.Reason = *reason
+	}
+	return &suspension, nil
+}
+
+func (s *UserStorage) Suspend(ctx context.Context, id uuid.UUID, reason string, until *time.Time) error {
+	q := `UPDATE users.user SET status = 'suspended', suspension_reason = $1, suspended_until = $2 WHERE id = $3`
+	tag, err := s.db.Exec(ctx, q, reason, until, id)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func (s *UserStorage) Unsuspend(ctx context.Context, id uuid.UUID) error {
+	q := `UPDATE users.user SET status = 'active', suspension_reason = NULL, suspended_until = NULL WHERE id = $1`
+	tag, err := s.db.Exec(ctx, q, id)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
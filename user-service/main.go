@@ -117,14 +117,16 @@ func main() {
 	}()
 
 	userStorage := storage.NewUserStorage(db)
-	userService := services.NewGetUserService(userStorage)
-	userServer := handlers.NewUserServer(*userService)
 	restrictionStorage := storage.NewRestrictionStorage(db)
+	contactStorage := storage.NewContactStorage(db)
+	userService := services.NewGetUserService(userStorage, restrictionStorage, contactStorage)
+	userServer := handlers.NewUserServer(*userService)
 	getUserService := services.NewGetService(userStorage, restrictionStorage)
 	getRestrictionService := services.NewGetRestrictionService(restrictionStorage)
 	updateUserService := services.NewUpdateUserService(userStorage)
 	updateRestrictions := services.NewUpdateRestrService(restrictionStorage)
 	processPhotoService := services.NewProcessPhotoService(userStorage, fileClient)
+	contactService := services.NewContactService(contactStorage)
 	getUserServer := handlers.NewGetUserHandler(getUserService)
 
 	grpcPort := viper.GetString("server.grpc-port")
@@ -167,6 +169,9 @@ func main() {
 		GET("/v1.0/users/:users", getUserServer.GetUsers()).
 		GET("/v1.0/me", getUserServer.GetMe()).
 		GET("/v1.0/me/restrictions", handlers.GetAllowedUserIDs(getRestrictionService, getUserService)).
+		GET("/v1.0/me/contacts", handlers.GetContacts(contactService)).
+		PUT("/v1.0/me/contacts/:userId", handlers.AddContact(contactService)).
+		DELETE("/v1.0/me/contacts/:userId", handlers.RemoveContact(contactService)).
 		PUT("v1.0/me", handlers.UpdateUser(updateUserService, getUserService)).
 		PUT("v1.0/me/restrictions", handlers.UpdateRestrictions(updateRestrictions)).
 		PUT("v1.0/me/profile-photo", handlers.UpdatePhoto(processPhotoService)).
@@ -0,0 +1,2 @@
+-- Phone can be changed now, so uniqueness must be guaranteed by DB
+CREATE UNIQUE INDEX IF NOT EXISTS user_phone_unique_idx ON users.user (phone);
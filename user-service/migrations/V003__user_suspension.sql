@@ -0,0 +1,7 @@
+CREATE TYPE users.user_status AS ENUM ('active', 'suspended');
+
+ALTER TABLE users.user
+    ADD COLUMN IF NOT EXISTS status users.user_status NOT NULL DEFAULT 'active',
+    ADD COLUMN IF NOT EXISTS suspension_reason TEXT,
+    -- NULL means the suspension doesn't expire
+    ADD COLUMN IF NOT EXISTS suspended_until TIMESTAMP WITH TIME ZONE;
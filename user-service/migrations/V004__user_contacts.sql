@@ -0,0 +1,9 @@
+-- Users the owner keeps in the contact list.
+-- The owners are notified when a contact changes a phone visible to everyone.
+CREATE TABLE IF NOT EXISTS users.contact (
+    owner_user_id UUID NOT NULL REFERENCES users.user (id) ON DELETE CASCADE,
+    contact_user_id UUID NOT NULL REFERENCES users.user (id) ON DELETE CASCADE,
+    PRIMARY KEY (owner_user_id, contact_user_id)
+);
+
+CREATE INDEX IF NOT EXISTS contact_contact_user_id_idx ON users.contact (contact_user_id);